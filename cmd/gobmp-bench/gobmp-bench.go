@@ -0,0 +1,101 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/sbezverk/gobmp/pkg/bench"
+)
+
+var (
+	captures   string
+	ipv4       int
+	ipv6       int
+	write      string
+	iterations int
+	parseOnly  bool
+	jsonOutput bool
+)
+
+func init() {
+	flag.StringVar(&captures, "captures", "", "Comma separated list of capture files with raw BMP messages, when empty a synthetic full table is generated")
+	flag.IntVar(&ipv4, "ipv4-routes", bench.FullTableIPv4, "Number of IPv4 routes of the synthetic full table")
+	flag.IntVar(&ipv6, "ipv6-routes", bench.FullTableIPv6, "Number of IPv6 routes of the synthetic full table")
+	flag.StringVar(&write, "write", "", "Write the synthetic full table to the file and exit, the file can be used with --captures")
+	flag.IntVar(&iterations, "iterations", 3, "Number of runs over the messages")
+	flag.BoolVar(&parseOnly, "parse-only", false, "Measure only parsing, by default parsed messages are also produced")
+	flag.BoolVar(&jsonOutput, "json", false, "Print results of runs as JSON, to be stored and compared between releases")
+}
+
+// result defines JSON output of a run
+type result struct {
+	Iteration       int     `json:"iteration"`
+	Messages        int     `json:"messages"`
+	Bytes           int     `json:"bytes"`
+	Published       int64   `json:"published"`
+	Seconds         float64 `json:"seconds"`
+	MessagesPerSec  float64 `json:"messages_per_sec"`
+	PublishedPerSec float64 `json:"published_per_sec"`
+	AllocsPerMsg    float64 `json:"allocs_per_message"`
+	BytesPerMsg     float64 `json:"alloc_bytes_per_message"`
+}
+
+func main() {
+	flag.Parse()
+	if iterations < 1 || ipv4 < 0 || ipv6 < 0 {
+		fmt.Fprintf(os.Stderr, "invalid number of iterations %d or routes %d/%d\n", iterations, ipv4, ipv6)
+		os.Exit(1)
+	}
+	var msgs [][]byte
+	var err error
+	if captures != "" {
+		msgs, err = bench.LoadCaptures(strings.Split(captures, ",")...)
+	} else {
+		var b bytes.Buffer
+		if _, err = bench.Generate(&b, ipv4, ipv6); err == nil {
+			if write != "" {
+				if err := ioutil.WriteFile(write, b.Bytes(), 0644); err != nil {
+					fmt.Fprintf(os.Stderr, "failed to write full table with error: %+v\n", err)
+					os.Exit(1)
+				}
+				return
+			}
+			msgs, err = bench.Split(b.Bytes())
+		}
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to load BMP messages with error: %+v\n", err)
+		os.Exit(1)
+	}
+	if len(msgs) == 0 {
+		fmt.Fprintf(os.Stderr, "no BMP messages to benchmark\n")
+		os.Exit(1)
+	}
+	enc := json.NewEncoder(os.Stdout)
+	for i := 1; i <= iterations; i++ {
+		r := bench.Run(msgs, !parseOnly)
+		if !jsonOutput {
+			fmt.Printf("run %d: %s\n", i, r)
+			continue
+		}
+		if err := enc.Encode(&result{
+			Iteration:       i,
+			Messages:        r.Messages,
+			Bytes:           r.Bytes,
+			Published:       r.Published,
+			Seconds:         r.Duration.Seconds(),
+			MessagesPerSec:  r.MessagesPerSec(),
+			PublishedPerSec: r.PublishedPerSec(),
+			AllocsPerMsg:    r.AllocsPerMessage(),
+			BytesPerMsg:     r.AllocBytesPerMessage(),
+		}); err != nil {
+			fmt.Fprintf(os.Stderr, "failed to encode result with error: %+v\n", err)
+			os.Exit(1)
+		}
+	}
+}
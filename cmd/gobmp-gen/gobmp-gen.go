@@ -0,0 +1,323 @@
+package main
+
+import (
+	"encoding/binary"
+	"flag"
+	"fmt"
+	"math/rand"
+	"net"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/sbezverk/gobmp/pkg/bgp"
+	"github.com/sbezverk/gobmp/pkg/bmp"
+)
+
+const (
+	// maxPrefixesPerUpdate defines how many /24 prefixes are packed into a single BGP Update,
+	// 4 bytes per prefix keeps the update well below BGP maximum message length.
+	maxPrefixesPerUpdate = 800
+)
+
+var (
+	bmpSrv      string
+	peers       int
+	prefixes    int
+	startPrefix string
+	localAS     uint
+	peerAS      uint
+	churnRate   int
+	duration    int
+)
+
+func init() {
+	flag.StringVar(&bmpSrv, "bmp-server", "127.0.0.1:5000", "Address and port of BMP listener to send generated messages to")
+	flag.IntVar(&peers, "peers", 1, "Number of BGP peers to simulate")
+	flag.IntVar(&prefixes, "prefixes", 1000, "Number of /24 IPv4 prefixes advertised by each peer in the initial table dump")
+	flag.StringVar(&startPrefix, "start-prefix", "10.0.0.0", "First IPv4 prefix of the generated table")
+	flag.UintVar(&localAS, "local-as", 65000, "AS number of the simulated monitored router")
+	flag.UintVar(&peerAS, "peer-as", 65001, "AS number of the first simulated peer, following peers get consecutive AS numbers")
+	flag.IntVar(&churnRate, "churn-rate", 0, "Number of withdraw or re-advertise updates per second sent after the table dump, up to 1000000000, 0 disables churn")
+	flag.IntVar(&duration, "duration", 0, "Duration in seconds of churn phase, 0 runs until interrupted")
+}
+
+// peer defines a simulated BGP peer of the monitored router
+type peer struct {
+	addr     net.IP
+	as       uint32
+	bgpID    net.IP
+	prefixes []uint32
+	// withdrawn tracks prefixes currently withdrawn by churn
+	withdrawn map[int]bool
+}
+
+func newPeers(n int, first uint32, count int) []*peer {
+	ps := make([]*peer, n)
+	for i := 0; i < n; i++ {
+		addr := net.IPv4(172, 16, byte((i+1)>>8), byte(i+1))
+		p := &peer{
+			addr:      addr,
+			as:        uint32(peerAS) + uint32(i),
+			bgpID:     addr,
+			prefixes:  make([]uint32, count),
+			withdrawn: make(map[int]bool),
+		}
+		for j := 0; j < count; j++ {
+			p.prefixes[j] = first + uint32(j)<<8
+		}
+		ps[i] = p
+	}
+
+	return ps
+}
+
+func (p *peer) perPeerHeader() *bmp.PerPeerHeader {
+	return bmp.NewPerPeerHeader(p.addr, p.as, p.bgpID, time.Now(), false)
+}
+
+func openMessage(as uint32, bgpID net.IP) *bgp.OpenMessage {
+	myAS := uint16(as)
+	if as > 0xffff {
+		// AS_TRANS
+		myAS = 23456
+	}
+	as4 := make([]byte, 4)
+	binary.BigEndian.PutUint32(as4, as)
+	return &bgp.OpenMessage{
+		MyAS:     myAS,
+		HoldTime: 180,
+		BGPID:    bgpID.To4(),
+		Capabilities: bgp.Capability{
+			// Multiprotocol Extensions IPv4 Unicast
+			1: []*bgp.CapabilityData{{Value: []byte{0, 1, 0, 1}}},
+			// Route Refresh
+			2: []*bgp.CapabilityData{{Value: []byte{}}},
+			// 4-octet AS number
+			65: []*bgp.CapabilityData{{Value: as4}},
+		},
+	}
+}
+
+func initiationMessage() ([]byte, error) {
+	im := &bmp.InitiationMessage{
+		TLV: []bmp.InformationalTLV{
+			{InformationType: 1, Information: []byte("gobmp-gen synthetic BMP stream")},
+			{InformationType: 2, Information: []byte("gobmp-gen")},
+		},
+	}
+	b, err := im.Serialize()
+	if err != nil {
+		return nil, err
+	}
+
+	return bmp.SerializeMessage(bmp.InitiationMsg, nil, b)
+}
+
+func terminationMessage() ([]byte, error) {
+	// Reason TLV, Session administratively closed
+	return bmp.SerializeMessage(bmp.TerminationMsg, nil, bmp.SerializeTLV([]bmp.InformationalTLV{
+		{InformationType: 1, Information: []byte{0, 0}},
+	}))
+}
+
+func peerUpMessage(p *peer, routerID net.IP) ([]byte, error) {
+	pu := &bmp.PeerUpMessage{
+		LocalAddress: make([]byte, 16),
+		LocalPort:    179,
+		RemotePort:   uint16(30000 + rand.Intn(30000)),
+		SentOpen:     openMessage(uint32(localAS), routerID),
+		ReceivedOpen: openMessage(p.as, p.bgpID),
+	}
+	copy(pu.LocalAddress[12:], routerID.To4())
+	b, err := pu.Serialize()
+	if err != nil {
+		return nil, err
+	}
+
+	return bmp.SerializeMessage(bmp.PeerUpMsg, p.perPeerHeader(), b)
+}
+
+func encodePrefixes(pfxs []uint32) []byte {
+	b := make([]byte, 0, len(pfxs)*4)
+	for _, pfx := range pfxs {
+		v := make([]byte, 4)
+		binary.BigEndian.PutUint32(v, pfx)
+		b = append(b, 24)
+		b = append(b, v[:3]...)
+	}
+
+	return b
+}
+
+// routeMonitorMessage builds BMP Route Monitoring message carrying BGP Update with advertised and withdrawn
+// prefixes, when both are empty, the update is End-of-RIB marker.
+func routeMonitorMessage(p *peer, advertise, withdraw []uint32) ([]byte, error) {
+	up := &bgp.Update{
+		WithdrawnRoutes: encodePrefixes(withdraw),
+		NLRI:            encodePrefixes(advertise),
+	}
+	if len(advertise) != 0 {
+		asPath := make([]byte, 6)
+		// AS_SEQUENCE of one 4 bytes AS
+		asPath[0] = 2
+		asPath[1] = 1
+		binary.BigEndian.PutUint32(asPath[2:], p.as)
+		up.PathAttributes = []bgp.PathAttribute{
+			{AttributeTypeFlags: 0x40, AttributeType: 1, Attribute: []byte{0}},
+			{AttributeTypeFlags: 0x40, AttributeType: 2, Attribute: asPath},
+			{AttributeTypeFlags: 0x40, AttributeType: 3, Attribute: []byte(p.addr.To4())},
+		}
+	}
+	rm := &bmp.RouteMonitor{Update: up}
+	b, err := rm.Serialize()
+	if err != nil {
+		return nil, err
+	}
+
+	return bmp.SerializeMessage(bmp.RouteMonitorMsg, p.perPeerHeader(), b)
+}
+
+// sender writes generated BMP messages to BMP listener connection
+type sender struct {
+	conn net.Conn
+}
+
+func (s *sender) send(b []byte, err error) error {
+	if err != nil {
+		return err
+	}
+	_, err = s.conn.Write(b)
+
+	return err
+}
+
+func tableDump(s *sender, ps []*peer, routerID net.IP) (int, error) {
+	msgs := 0
+	for _, p := range ps {
+		if err := s.send(peerUpMessage(p, routerID)); err != nil {
+			return msgs, err
+		}
+		msgs++
+		for i := 0; i < len(p.prefixes); i += maxPrefixesPerUpdate {
+			e := i + maxPrefixesPerUpdate
+			if e > len(p.prefixes) {
+				e = len(p.prefixes)
+			}
+			if err := s.send(routeMonitorMessage(p, p.prefixes[i:e], nil)); err != nil {
+				return msgs, err
+			}
+			msgs++
+		}
+		// End-of-RIB
+		if err := s.send(routeMonitorMessage(p, nil, nil)); err != nil {
+			return msgs, err
+		}
+		msgs++
+	}
+
+	return msgs, nil
+}
+
+func churn(s *sender, ps []*peer, stop <-chan struct{}) (int, error) {
+	msgs := 0
+	ticker := time.NewTicker(time.Second / time.Duration(churnRate))
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return msgs, nil
+		case <-ticker.C:
+		}
+		p := ps[rand.Intn(len(ps))]
+		if len(p.prefixes) == 0 {
+			continue
+		}
+		i := rand.Intn(len(p.prefixes))
+		var err error
+		if p.withdrawn[i] {
+			err = s.send(routeMonitorMessage(p, p.prefixes[i:i+1], nil))
+			delete(p.withdrawn, i)
+		} else {
+			err = s.send(routeMonitorMessage(p, nil, p.prefixes[i:i+1]))
+			p.withdrawn[i] = true
+		}
+		if err != nil {
+			return msgs, err
+		}
+		msgs++
+	}
+}
+
+// run sends generated BMP stream to BMP listener, the connection is closed on return
+func run() error {
+	first := net.ParseIP(startPrefix).To4()
+	if first == nil {
+		return fmt.Errorf("invalid start prefix %s, must be IPv4 address", startPrefix)
+	}
+	// Churn interval is a second divided by churn rate, it must not be shorter than a nanosecond
+	if peers < 1 || peers > 0xffff || prefixes < 0 || churnRate < 0 || churnRate > int(time.Second) {
+		return fmt.Errorf("invalid number of peers %d, prefixes %d or churn rate %d", peers, prefixes, churnRate)
+	}
+	ps := newPeers(peers, binary.BigEndian.Uint32(first), prefixes)
+	routerID := net.IPv4(192, 0, 2, 1)
+
+	conn, err := net.Dial("tcp", bmpSrv)
+	if err != nil {
+		return fmt.Errorf("failed to connect to BMP listener %s with error: %+v", bmpSrv, err)
+	}
+	defer conn.Close()
+	s := &sender{conn: conn}
+
+	start := time.Now()
+	if err := s.send(initiationMessage()); err != nil {
+		return fmt.Errorf("failed to send initiation message with error: %+v", err)
+	}
+	msgs, err := tableDump(s, ps, routerID)
+	if err != nil {
+		return fmt.Errorf("failed to send table dump with error: %+v", err)
+	}
+	glog.Infof("%3f seconds took to send table dump of %d prefixes from %d peers in %d messages", time.Since(start).Seconds(), prefixes*peers, peers, msgs)
+
+	if churnRate > 0 {
+		stop := make(chan struct{})
+		sig := make(chan os.Signal, 1)
+		signal.Notify(sig, syscall.SIGINT, syscall.SIGTERM)
+		go func() {
+			if duration > 0 {
+				select {
+				case <-sig:
+				case <-time.After(time.Second * time.Duration(duration)):
+				}
+			} else {
+				<-sig
+			}
+			close(stop)
+		}()
+		start = time.Now()
+		msgs, err = churn(s, ps, stop)
+		if err != nil {
+			return fmt.Errorf("failed to send churn updates with error: %+v", err)
+		}
+		glog.Infof("%3f seconds of churn, sent %d updates", time.Since(start).Seconds(), msgs)
+	}
+	if err := s.send(terminationMessage()); err != nil {
+		return fmt.Errorf("failed to send termination message with error: %+v", err)
+	}
+
+	return nil
+}
+
+func main() {
+	flag.Parse()
+	_ = flag.Set("logtostderr", "true")
+	rand.Seed(time.Now().UnixNano())
+
+	if err := run(); err != nil {
+		glog.Errorf("%+v", err)
+		os.Exit(1)
+	}
+	glog.Infof("gobmp-gen completed sending BMP stream to %s", bmpSrv)
+}
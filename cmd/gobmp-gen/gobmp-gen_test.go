@@ -0,0 +1,67 @@
+package main
+
+import (
+	"net"
+	"reflect"
+	"testing"
+
+	"github.com/sbezverk/gobmp/pkg/bmp"
+)
+
+func TestGeneratedMessages(t *testing.T) {
+	ps := newPeers(2, 0x0a000000, 3)
+	routerID := net.IPv4(192, 0, 2, 1)
+
+	b, err := peerUpMessage(ps[1], routerID)
+	if err != nil {
+		t.Fatalf("failed to build peer up message with error: %+v", err)
+	}
+	ch, err := bmp.UnmarshalCommonHeader(b[:bmp.CommonHeaderLength])
+	if err != nil {
+		t.Fatalf("failed to unmarshal common header with error: %+v", err)
+	}
+	if ch.MessageType != bmp.PeerUpMsg || int(ch.MessageLength) != len(b) {
+		t.Fatalf("unexpected common header %+v for message of length %d", ch, len(b))
+	}
+	pph, err := bmp.UnmarshalPerPeerHeader(b[bmp.CommonHeaderLength : bmp.CommonHeaderLength+bmp.PerPeerHeaderLength])
+	if err != nil {
+		t.Fatalf("failed to unmarshal per peer header with error: %+v", err)
+	}
+	if pph.GetPeerAddrString() != "172.16.0.2" || pph.PeerAS != 65002 {
+		t.Fatalf("unexpected peer address %s or peer as %d", pph.GetPeerAddrString(), pph.PeerAS)
+	}
+	pu, err := bmp.UnmarshalPeerUpMessage(b[bmp.CommonHeaderLength+bmp.PerPeerHeaderLength:], false)
+	if err != nil {
+		t.Fatalf("failed to unmarshal peer up message with error: %+v", err)
+	}
+	if as, ok := pu.ReceivedOpen.Is4BytesASCapable(); !ok || as != 65002 {
+		t.Fatalf("expected received open to carry 4 bytes as 65002, got %d", as)
+	}
+
+	b, err = routeMonitorMessage(ps[0], ps[0].prefixes, ps[0].prefixes[:1])
+	if err != nil {
+		t.Fatalf("failed to build route monitor message with error: %+v", err)
+	}
+	rm, err := bmp.UnmarshalBMPRouteMonitorMessage(b[bmp.CommonHeaderLength+bmp.PerPeerHeaderLength:])
+	if err != nil {
+		t.Fatalf("failed to unmarshal route monitor message with error: %+v", err)
+	}
+	if !reflect.DeepEqual(rm.Update.NLRI, []byte{24, 10, 0, 0, 24, 10, 0, 1, 24, 10, 0, 2}) {
+		t.Fatalf("unexpected nlri %+v", rm.Update.NLRI)
+	}
+	if !reflect.DeepEqual(rm.Update.WithdrawnRoutes, []byte{24, 10, 0, 0}) {
+		t.Fatalf("unexpected withdrawn routes %+v", rm.Update.WithdrawnRoutes)
+	}
+	if rm.Update.BaseAttributes.Nexthop != "172.16.0.1" || !reflect.DeepEqual(rm.Update.BaseAttributes.ASPath, []uint32{65001}) {
+		t.Fatalf("unexpected base attributes %+v", rm.Update.BaseAttributes)
+	}
+}
+
+func TestRunInvalidFlags(t *testing.T) {
+	defer func(r int) { churnRate = r }(churnRate)
+	// Churn interval of a second divided by the rate would be 0
+	churnRate = 2000000000
+	if err := run(); err == nil {
+		t.Fatalf("expected churn rate %d to be rejected", churnRate)
+	}
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/golang/glog"
+	"github.com/sbezverk/gobmp/pkg/alert"
+	"github.com/sbezverk/gobmp/pkg/bmp"
+	"github.com/sbezverk/gobmp/pkg/flap"
+	"github.com/sbezverk/gobmp/pkg/macmove"
+	"github.com/sbezverk/gobmp/pkg/message"
+	"github.com/sbezverk/gobmp/pkg/pub"
+	"github.com/sbezverk/gobmp/pkg/schema"
+	"github.com/sbezverk/gobmp/pkg/topology"
+)
+
+var (
+	outDir string
+)
+
+func init() {
+	flag.StringVar(&outDir, "output-dir", "./schemas", "Directory to write JSON Schemas of published messages to")
+}
+
+func main() {
+	flag.Parse()
+	_ = flag.Set("logtostderr", "true")
+	schemas := map[string]*schema.Schema{
+		"envelope": schema.Generate("envelope", &pub.Envelope{}, nil),
+	}
+	for t, s := range message.Schemas() {
+		schemas[bmp.MsgTypeName(t)] = s
+	}
+	for t, v := range map[int]interface{}{
+		bmp.TopologyEventMsg: &topology.Event{},
+		bmp.PrefixFlapMsg:    &flap.Event{},
+		bmp.AlertMsg:         &alert.Alert{},
+		bmp.MACEventMsg:      &macmove.Event{},
+	} {
+		schemas[bmp.MsgTypeName(t)] = schema.Generate(bmp.MsgTypeName(t), v, nil)
+	}
+	if err := os.MkdirAll(outDir, 0755); err != nil {
+		glog.Errorf("failed to create output directory %s with error: %+v", outDir, err)
+		os.Exit(1)
+	}
+	for n, s := range schemas {
+		b, err := json.MarshalIndent(s, "", "  ")
+		if err != nil {
+			glog.Errorf("failed to marshal schema of %s with error: %+v", n, err)
+			os.Exit(1)
+		}
+		f := filepath.Join(outDir, n+".schema.json")
+		if err := ioutil.WriteFile(f, append(b, '\n'), 0644); err != nil {
+			glog.Errorf("failed to write schema %s with error: %+v", f, err)
+			os.Exit(1)
+		}
+	}
+	glog.Infof("%d schemas have been written to %s", len(schemas), outDir)
+}
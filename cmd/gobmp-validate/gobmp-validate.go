@@ -0,0 +1,489 @@
+package main
+
+import (
+	"bytes"
+	"encoding/binary"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+
+	"github.com/sbezverk/gobmp/pkg/bmp"
+)
+
+var (
+	file    string
+	verbose bool
+)
+
+func init() {
+	flag.StringVar(&file, "file", "-", "File with raw BMP messages as received from a router, \"-\" reads the messages from the standard input")
+	flag.BoolVar(&verbose, "verbose", false, "Print all messages, by default only messages with issues are printed")
+}
+
+var msgTypeNames = map[byte]string{
+	bmp.RouteMonitorMsg: "route_monitoring",
+	bmp.StatsReportMsg:  "statistics_report",
+	bmp.PeerDownMsg:     "peer_down",
+	bmp.PeerUpMsg:       "peer_up",
+	bmp.InitiationMsg:   "initiation",
+	bmp.TerminationMsg:  "termination",
+	bmp.RouteMirrorMsg:  "route_mirroring",
+}
+
+// statsLength defines the length of known Statistics Report counters and gauges, RFC 7854 and RFC 8671
+var statsLength = map[uint16]int{
+	0: 4, 1: 4, 2: 4, 3: 4, 4: 4, 5: 4, 6: 4,
+	7: 8, 8: 8,
+	9: 11, 10: 11,
+	11: 4, 12: 4, 13: 4,
+	14: 8, 15: 8,
+	16: 11, 17: 11,
+}
+
+var bgpMarker = bytes.Repeat([]byte{0xff}, 16)
+
+// issue defines a conformance issue found in a BMP message
+type issue struct {
+	err  bool
+	text string
+}
+
+func (i issue) String() string {
+	if i.err {
+		return "error: " + i.text
+	}
+
+	return "warning: " + i.text
+}
+
+// report defines the result of validation of a single BMP message
+type report struct {
+	index   int
+	offset  int
+	version byte
+	msgType byte
+	length  int
+	issues  []issue
+}
+
+func (r *report) errorf(format string, a ...interface{}) {
+	r.issues = append(r.issues, issue{err: true, text: fmt.Sprintf(format, a...)})
+}
+
+func (r *report) warnf(format string, a ...interface{}) {
+	r.issues = append(r.issues, issue{text: fmt.Sprintf(format, a...)})
+}
+
+func (r *report) errors() int {
+	n := 0
+	for _, i := range r.issues {
+		if i.err {
+			n++
+		}
+	}
+
+	return n
+}
+
+func (r *report) String() string {
+	name, ok := msgTypeNames[r.msgType]
+	if !ok {
+		name = fmt.Sprintf("type %d", r.msgType)
+	}
+
+	return fmt.Sprintf("message %d at offset %d: %s, version %d, length %d", r.index, r.offset, name, r.version, r.length)
+}
+
+// tlv defines a generic BMP Information TLV
+type tlv struct {
+	t uint16
+	v []byte
+}
+
+// parseTLVs parses Information TLVs reporting truncated TLVs and lengths exceeding the message
+func (r *report) parseTLVs(b []byte, what string) []tlv {
+	tlvs := make([]tlv, 0)
+	for p := 0; p < len(b); {
+		if len(b)-p < 4 {
+			r.errorf("%s tlv at offset %d is truncated, %d bytes left for 4 bytes tlv header", what, p, len(b)-p)
+			return tlvs
+		}
+		t := binary.BigEndian.Uint16(b[p : p+2])
+		l := int(binary.BigEndian.Uint16(b[p+2 : p+4]))
+		if l > len(b)-p-4 {
+			r.errorf("%s tlv type %d at offset %d has length %d exceeding %d remaining bytes", what, t, p, l, len(b)-p-4)
+			return tlvs
+		}
+		tlvs = append(tlvs, tlv{t: t, v: b[p+4 : p+4+l]})
+		p += 4 + l
+	}
+
+	return tlvs
+}
+
+// validate runs parser of gobmp and reports its error or panic
+func (r *report) validate(what string, f func() error) {
+	defer func() {
+		if e := recover(); e != nil {
+			r.errorf("gobmp %s parser panicked: %v", what, e)
+		}
+	}()
+	if err := f(); err != nil {
+		r.errorf("gobmp %s parser failed: %+v", what, err)
+	}
+}
+
+// validateStream validates all BMP messages found in b, validation stops when the length of a message
+// cannot be trusted.
+func validateStream(b []byte) []*report {
+	reports := make([]*report, 0)
+	for p := 0; p < len(b); {
+		r := &report{index: len(reports) + 1, offset: p}
+		reports = append(reports, r)
+		if len(b)-p < bmp.CommonHeaderLength {
+			r.errorf("common header is truncated, %d bytes left", len(b)-p)
+			break
+		}
+		r.version = b[p]
+		r.length = int(binary.BigEndian.Uint32(b[p+1 : p+5]))
+		r.msgType = b[p+5]
+		if r.version != 3 && r.version != 4 {
+			r.errorf("invalid version %d, expected 3 or 4 (draft-ietf-grow-bmp-tlv)", r.version)
+		}
+		if r.length < bmp.CommonHeaderLength || r.length > len(b)-p {
+			r.errorf("message length %d is invalid, %d bytes left, validation stopped", r.length, len(b)-p)
+			break
+		}
+		body := b[p+bmp.CommonHeaderLength : p+r.length]
+		p += r.length
+		if _, ok := msgTypeNames[r.msgType]; !ok {
+			r.errorf("invalid message type %d, expected between 0 and 6", r.msgType)
+			continue
+		}
+		validateMessage(r, body)
+	}
+
+	return reports
+}
+
+func validateMessage(r *report, body []byte) {
+	switch r.msgType {
+	case bmp.InitiationMsg:
+		validateInitiation(r, body)
+		return
+	case bmp.TerminationMsg:
+		validateTermination(r, body)
+		return
+	}
+	if len(body) < bmp.PerPeerHeaderLength {
+		r.errorf("message of %d bytes is too short for per-peer header of %d bytes", len(body), bmp.PerPeerHeaderLength)
+		return
+	}
+	var ph *bmp.PerPeerHeader
+	r.validate("per-peer header", func() error {
+		var err error
+		ph, err = bmp.UnmarshalPerPeerHeader(body[:bmp.PerPeerHeaderLength])
+		return err
+	})
+	validatePerPeerHeader(r, body[:bmp.PerPeerHeaderLength])
+	body = body[bmp.PerPeerHeaderLength:]
+	switch r.msgType {
+	case bmp.RouteMonitorMsg:
+		validateRouteMonitor(r, body)
+	case bmp.StatsReportMsg:
+		validateStats(r, body)
+	case bmp.PeerDownMsg:
+		validatePeerDown(r, body)
+	case bmp.PeerUpMsg:
+		if ph != nil {
+			validatePeerUp(r, body, ph.IsRemotePeerIPv6())
+		}
+	case bmp.RouteMirrorMsg:
+		validateRouteMirror(r, body)
+	}
+}
+
+func validatePerPeerHeader(r *report, b []byte) {
+	peerType := b[0]
+	flags := b[1]
+	pd := b[2:10]
+	switch peerType {
+	case 0, 1, 2:
+		if flags&0x0f != 0 {
+			r.warnf("reserved per-peer header flags 0x%02x are set", flags&0x0f)
+		}
+	case 3:
+		if flags&0x7f != 0 {
+			r.warnf("reserved per-peer header flags 0x%02x of loc-rib instance peer are set", flags&0x7f)
+		}
+	default:
+		r.errorf("invalid peer type %d, expected between 0 and 3", peerType)
+	}
+	switch peerType {
+	case 0:
+		if !bytes.Equal(pd, make([]byte, 8)) {
+			r.warnf("peer distinguisher of global instance peer is not zero")
+		}
+	case 1:
+		if t := binary.BigEndian.Uint16(pd[:2]); t > 2 {
+			r.errorf("peer distinguisher of rd instance peer has invalid route distinguisher type %d", t)
+		}
+	}
+}
+
+// validateBGPMessage validates BGP message header and returns the message type
+func validateBGPMessage(r *report, b []byte, what string) (byte, bool) {
+	if len(b) < 19 {
+		r.errorf("%s of %d bytes is too short for bgp message header", what, len(b))
+		return 0, false
+	}
+	if !bytes.Equal(b[:16], bgpMarker) {
+		r.errorf("%s marker is not all ones", what)
+	}
+	if l := int(binary.BigEndian.Uint16(b[16:18])); l != len(b) {
+		r.errorf("%s length %d does not match %d bytes carried by bmp message", what, l, len(b))
+	}
+
+	return b[18], true
+}
+
+func validateRouteMonitor(r *report, b []byte) {
+	var pdu []byte
+	if r.version == 4 {
+		tlvs, err := bmp.UnmarshalTLVs(b)
+		if err != nil {
+			r.errorf("route monitoring tlvs are malformed: %+v", err)
+			return
+		}
+		for _, t := range tlvs {
+			switch {
+			case t.Type == bmp.BGPPDUTLV:
+				pdu = t.Value
+			case t.Type == bmp.StatelessParsingTLV, t.Type == bmp.GroupTLV, t.Type == bmp.VRFTableNameTLV:
+			case t.IsEnterprise():
+				r.warnf("enterprise specific route monitoring tlv type %d", t.Type)
+			default:
+				r.warnf("unknown route monitoring tlv type %d", t.Type)
+			}
+		}
+	} else {
+		pdu = b
+	}
+	if pdu == nil {
+		r.errorf("route monitoring message does not carry bgp pdu")
+		return
+	}
+	if t, ok := validateBGPMessage(r, pdu, "bgp pdu"); ok && t != 2 {
+		r.errorf("bgp pdu type %d is not update", t)
+		return
+	}
+	var rm *bmp.RouteMonitor
+	r.validate("route monitoring", func() error {
+		var err error
+		if r.version == 4 {
+			rm, err = bmp.UnmarshalBMPRouteMonitorV4Message(b)
+		} else {
+			rm, err = bmp.UnmarshalBMPRouteMonitorMessage(b)
+		}
+		return err
+	})
+	if rm == nil || rm.Update == nil || rm.Update.BaseAttributes == nil {
+		return
+	}
+	for _, a := range rm.Update.BaseAttributes.UnknownAttrs {
+		r.warnf("path attribute type %d with flags 0x%02x is not decoded by gobmp", a.Type, a.Flags)
+	}
+}
+
+func validateStats(r *report, b []byte) {
+	if len(b) < 4 {
+		r.errorf("statistics report of %d bytes is too short for stats count", len(b))
+		return
+	}
+	count := int(binary.BigEndian.Uint32(b[:4]))
+	tlvs := r.parseTLVs(b[4:], "statistics")
+	if count != len(tlvs) {
+		r.errorf("stats count %d does not match %d statistics tlvs", count, len(tlvs))
+	}
+	for _, t := range tlvs {
+		l, ok := statsLength[t.t]
+		if !ok {
+			r.warnf("unknown statistics type %d", t.t)
+			continue
+		}
+		if len(t.v) != l {
+			r.errorf("statistics type %d has length %d, expected %d", t.t, len(t.v), l)
+		}
+	}
+	r.validate("statistics report", func() error {
+		_, err := bmp.UnmarshalBMPStatsReportMessage(b)
+		return err
+	})
+}
+
+func validatePeerDown(r *report, b []byte) {
+	if len(b) < 1 {
+		r.errorf("peer down message does not carry reason")
+		return
+	}
+	reason, data := b[0], b[1:]
+	switch reason {
+	case 1, 3:
+		// Notification PDU follows
+		if t, ok := validateBGPMessage(r, data, "peer down notification"); ok && t != 3 {
+			r.errorf("peer down reason %d carries bgp message type %d instead of notification", reason, t)
+		}
+	case 2:
+		// 2 bytes FSM event code follows
+		if len(data) != 2 {
+			r.errorf("peer down reason 2 carries %d bytes, expected 2 bytes fsm event code", len(data))
+		}
+	case 4, 5, 6:
+		if len(data) != 0 {
+			r.warnf("peer down reason %d carries %d bytes of unexpected data", reason, len(data))
+		}
+	default:
+		r.errorf("invalid peer down reason %d, expected between 1 and 6", reason)
+	}
+	r.validate("peer down", func() error {
+		_, err := bmp.UnmarshalPeerDownMessage(b)
+		return err
+	})
+}
+
+func validatePeerUp(r *report, b []byte, ipv6 bool) {
+	// Local Address 16 bytes, Local Port 2 bytes and Remote Port 2 bytes
+	if len(b) < 20 {
+		r.errorf("peer up message of %d bytes is too short", len(b))
+		return
+	}
+	p := 20
+	for _, what := range []string{"sent open", "received open"} {
+		if len(b)-p < 19 {
+			r.errorf("%s message is truncated, %d bytes left", what, len(b)-p)
+			return
+		}
+		l := int(binary.BigEndian.Uint16(b[p+16 : p+18]))
+		if l < 29 || l > len(b)-p {
+			r.errorf("%s message length %d is invalid, %d bytes left", what, l, len(b)-p)
+			return
+		}
+		if t, ok := validateBGPMessage(r, b[p:p+l], what); ok && t != 1 {
+			r.errorf("%s message type is %d instead of open", what, t)
+		}
+		p += l
+	}
+	for _, t := range r.parseTLVs(b[p:], "peer up information") {
+		switch t.t {
+		case 0:
+		case bmp.PeerUpVRFTableNameTLV:
+			if len(t.v) > 255 {
+				r.errorf("vrf/table name of %d bytes exceeds 255 bytes", len(t.v))
+			}
+		default:
+			r.warnf("unknown peer up information tlv type %d", t.t)
+		}
+	}
+	r.validate("peer up", func() error {
+		_, err := bmp.UnmarshalPeerUpMessage(b, ipv6)
+		return err
+	})
+}
+
+func validateInitiation(r *report, b []byte) {
+	found := map[uint16]bool{}
+	for _, t := range r.parseTLVs(b, "initiation") {
+		switch t.t {
+		case 0, 1, 2:
+			found[t.t] = true
+		default:
+			r.warnf("unknown initiation tlv type %d", t.t)
+		}
+	}
+	if !found[1] {
+		r.errorf("initiation message does not carry mandatory sysDescr tlv")
+	}
+	if !found[2] {
+		r.errorf("initiation message does not carry mandatory sysName tlv")
+	}
+	r.validate("initiation", func() error {
+		_, err := bmp.UnmarshalInitiationMessage(b)
+		return err
+	})
+}
+
+func validateTermination(r *report, b []byte) {
+	for _, t := range r.parseTLVs(b, "termination") {
+		switch t.t {
+		case 0:
+		case 1:
+			if len(t.v) != 2 {
+				r.errorf("termination reason tlv has length %d, expected 2", len(t.v))
+				continue
+			}
+			if reason := binary.BigEndian.Uint16(t.v); reason > 4 {
+				r.warnf("unknown termination reason %d", reason)
+			}
+		default:
+			r.warnf("unknown termination tlv type %d", t.t)
+		}
+	}
+}
+
+func validateRouteMirror(r *report, b []byte) {
+	for _, t := range r.parseTLVs(b, "route mirroring") {
+		switch t.t {
+		case 0:
+			validateBGPMessage(r, t.v, "mirrored bgp message")
+		case 1:
+			if len(t.v) != 2 {
+				r.errorf("route mirroring information tlv has length %d, expected 2", len(t.v))
+				continue
+			}
+			if code := binary.BigEndian.Uint16(t.v); code > 1 {
+				r.warnf("unknown route mirroring information code %d", code)
+			}
+		default:
+			r.warnf("unknown route mirroring tlv type %d", t.t)
+		}
+	}
+}
+
+func printReports(w io.Writer, reports []*report, all bool) (int, int) {
+	errs, warns := 0, 0
+	for _, r := range reports {
+		e := r.errors()
+		errs += e
+		warns += len(r.issues) - e
+		if len(r.issues) == 0 && !all {
+			continue
+		}
+		fmt.Fprintln(w, r)
+		for _, i := range r.issues {
+			fmt.Fprintf(w, "  %s\n", i)
+		}
+	}
+	fmt.Fprintf(w, "%d messages, %d errors, %d warnings\n", len(reports), errs, warns)
+
+	return errs, warns
+}
+
+func main() {
+	flag.Parse()
+	var b []byte
+	var err error
+	if file == "-" {
+		b, err = ioutil.ReadAll(os.Stdin)
+	} else {
+		b, err = ioutil.ReadFile(file)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to read BMP messages with error: %+v\n", err)
+		os.Exit(2)
+	}
+	if errs, _ := printReports(os.Stdout, validateStream(b), verbose); errs != 0 {
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"bytes"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/sbezverk/gobmp/pkg/bmp"
+)
+
+func message(t *testing.T, msgType byte, pph *bmp.PerPeerHeader, payload []byte) []byte {
+	b, err := bmp.SerializeMessage(msgType, pph, payload)
+	if err != nil {
+		t.Fatalf("failed to serialize bmp message with error: %+v", err)
+	}
+
+	return b
+}
+
+func bgpMessage(msgType byte, body []byte) []byte {
+	b := append([]byte{}, bgpMarker...)
+	l := 19 + len(body)
+	b = append(b, byte(l>>8), byte(l), msgType)
+
+	return append(b, body...)
+}
+
+func TestValidateStream(t *testing.T) {
+	pph := bmp.NewPerPeerHeader(net.IPv4(192, 0, 2, 2), 65001, net.IPv4(192, 0, 2, 2), time.Unix(1600000000, 0), false)
+	initiation := []byte{0, 1, 0, 4, 'd', 'e', 's', 'c', 0, 2, 0, 2, 'r', '1'}
+	// ORIGIN igp, empty AS_PATH, NEXT_HOP 192.0.2.2, AIGP metric 100 and NLRI 10.0.0.0/8
+	attrs := []byte{0x40, 0x01, 0x01, 0x00, 0x40, 0x02, 0x00, 0x40, 0x03, 0x04, 192, 0, 2, 2,
+		0x80, 0x1a, 0x0b, 0x01, 0x00, 0x0b, 0, 0, 0, 0, 0, 0, 0, 0x64}
+	update := bgpMessage(2, append(append([]byte{0, 0, 0, byte(len(attrs))}, attrs...), 8, 10))
+	badMarker := append([]byte{}, update...)
+	badMarker[0] = 0
+	tests := []struct {
+		name     string
+		input    []byte
+		reports  int
+		errors   int
+		warnings int
+	}{
+		{
+			name:    "valid initiation",
+			input:   message(t, bmp.InitiationMsg, nil, initiation),
+			reports: 1,
+		},
+		{
+			name:    "initiation without sysName",
+			input:   message(t, bmp.InitiationMsg, nil, initiation[:8]),
+			reports: 1,
+			errors:  1,
+		},
+		{
+			name:     "route monitoring with unknown path attribute",
+			input:    message(t, bmp.RouteMonitorMsg, pph, update),
+			reports:  1,
+			warnings: 1,
+		},
+		{
+			name:     "route monitoring with invalid marker",
+			input:    message(t, bmp.RouteMonitorMsg, pph, badMarker),
+			reports:  1,
+			errors:   1,
+			warnings: 1,
+		},
+		{
+			name: "statistics report with invalid count and unknown type",
+			// Count 3, type 0 with 4 bytes counter and unknown type 100
+			input:    message(t, bmp.StatsReportMsg, pph, []byte{0, 0, 0, 3, 0, 0, 0, 4, 0, 0, 0, 1, 0, 100, 0, 1, 0}),
+			reports:  1,
+			errors:   1,
+			warnings: 1,
+		},
+		{
+			name: "peer down with short fsm event code",
+			// Reason 2 with 1 byte
+			input:   message(t, bmp.PeerDownMsg, pph, []byte{2, 1}),
+			reports: 1,
+			errors:  1,
+		},
+		{
+			name:    "second message is truncated",
+			input:   append(message(t, bmp.InitiationMsg, nil, initiation), message(t, bmp.InitiationMsg, nil, initiation)[:10]...),
+			reports: 2,
+			errors:  1,
+		},
+		{
+			name:    "invalid message type",
+			input:   []byte{3, 0, 0, 0, 6, 7},
+			reports: 1,
+			errors:  1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reports := validateStream(tt.input)
+			var w bytes.Buffer
+			errs, warns := printReports(&w, reports, true)
+			if len(reports) != tt.reports || errs != tt.errors || warns != tt.warnings {
+				t.Logf("%s", w.String())
+				t.Fatalf("expected %d messages, %d errors and %d warnings, got %d messages, %d errors and %d warnings",
+					tt.reports, tt.errors, tt.warnings, len(reports), errs, warns)
+			}
+		})
+	}
+}
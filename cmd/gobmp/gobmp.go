@@ -3,20 +3,50 @@ package main
 import (
 	"flag"
 	"fmt"
+	"net"
 	"os"
+	"os/signal"
 	"runtime"
 	"strconv"
 	"strings"
+	"syscall"
+	"time"
 
 	"net/http"
 	_ "net/http/pprof"
 
 	"github.com/golang/glog"
+	"github.com/sbezverk/gobmp/pkg/addr"
+	"github.com/sbezverk/gobmp/pkg/admin"
+	"github.com/sbezverk/gobmp/pkg/alert"
+	"github.com/sbezverk/gobmp/pkg/bmp"
+	"github.com/sbezverk/gobmp/pkg/capture"
+	"github.com/sbezverk/gobmp/pkg/cluster"
+	"github.com/sbezverk/gobmp/pkg/codec"
 	"github.com/sbezverk/gobmp/pkg/dumper"
+	"github.com/sbezverk/gobmp/pkg/enrich"
+	"github.com/sbezverk/gobmp/pkg/eventhubs"
 	"github.com/sbezverk/gobmp/pkg/filer"
+	"github.com/sbezverk/gobmp/pkg/flap"
+	"github.com/sbezverk/gobmp/pkg/gnmi"
 	"github.com/sbezverk/gobmp/pkg/gobmpsrv"
 	"github.com/sbezverk/gobmp/pkg/kafka"
+	"github.com/sbezverk/gobmp/pkg/latency"
+	"github.com/sbezverk/gobmp/pkg/logging"
+	"github.com/sbezverk/gobmp/pkg/lookingglass"
+	"github.com/sbezverk/gobmp/pkg/macmove"
 	"github.com/sbezverk/gobmp/pkg/pub"
+	"github.com/sbezverk/gobmp/pkg/rpki"
+	"github.com/sbezverk/gobmp/pkg/sqs"
+	"github.com/sbezverk/gobmp/pkg/state"
+	"github.com/sbezverk/gobmp/pkg/systemd"
+	"github.com/sbezverk/gobmp/pkg/telemetry"
+	"github.com/sbezverk/gobmp/pkg/topology"
+	"github.com/sbezverk/gobmp/pkg/tracing"
+	"github.com/sbezverk/gobmp/pkg/transform"
+	"github.com/sbezverk/gobmp/pkg/webhook"
+	"github.com/sbezverk/gobmp/pkg/websocket"
+	"github.com/sbezverk/gobmp/pkg/webui"
 	"github.com/sbezverk/tools"
 )
 
@@ -26,9 +56,89 @@ var (
 	perfPort  int
 	kafkaSrv  string
 	intercept string
+	teeDsts   string
+	teeQueue  int
 	splitAF   string
 	dump      string
 	file      string
+	fileComp  string
+	fileBatch int
+	hookURL   string
+	hookKey   string
+	hookBatch int
+	hookFlush int
+	hookRetry int
+	hookDLF   string
+	hookComp  string
+	sqsURL    string
+	sqsRegion string
+	ehConn    string
+	ehName    string
+	vrpSource string
+	vrpReload int
+	geoLite   string
+	statePort int
+	gnmiPort  int
+	wsPort    int
+	webUI     string
+	topoPort  int
+	lgPort    int
+	topoEvent string
+	flapWin   int
+	flapLimit int
+	macEvents string
+	dupMACWin int
+	dupMACLim int
+	alertConf string
+	listeners string
+	rtrGroups string
+	allowSrc  string
+	unixSock  string
+	stdin     bool
+	keepAlive int
+	rcvBuf    int
+	acceptors int
+	sessRate  int
+	totalRate int
+	idleTime  int
+	parseLat  bool
+	legacyIP  bool
+	otlpURL   string
+	otlpHdrs  string
+	traceRate float64
+	clMembers string
+	clSelf    string
+	clAPIPort int
+	stateFile string
+	stateIntv int
+	stateSync int
+	topicPart int
+	topicRepl int
+	topicRet  int
+	lingerMs  int
+	batchSize int
+	kafkaComp string
+	kafkaIdem string
+	topicFile string
+	envelope  string
+	collector string
+	msgFormat string
+	naming    string
+	logLevels string
+	capDir    string
+	chkUpdate string
+	transConf string
+	combine   string
+	rawUpdate bool
+	adminID   string
+	bmpTopic  string
+	bmpOffset string
+	bmpKafka  string
+	outTypes  string
+	ssTypes   string
+	wsTypes   string
+	adminPort int
+	adminTok  string
 )
 
 func init() {
@@ -36,46 +146,494 @@ func init() {
 	flag.IntVar(&srcPort, "source-port", 5000, "port exposed to outside")
 	flag.IntVar(&dstPort, "destination-port", 5050, "port openBMP is listening")
 	flag.StringVar(&kafkaSrv, "kafka-server", "", "URL to access Kafka server")
+	flag.IntVar(&topicPart, "kafka-topic-partitions", 1, "Number of partitions of Kafka topics created by gobmp")
+	flag.IntVar(&topicRepl, "kafka-topic-replication", 1, "Replication factor of Kafka topics created by gobmp")
+	flag.IntVar(&topicRet, "kafka-topic-retention", 900, "Retention in seconds of Kafka topics created by gobmp")
+	flag.IntVar(&lingerMs, "kafka-linger", 0, "Time in milliseconds Kafka producer collects messages into a batch, 0 sends messages as soon as possible")
+	flag.IntVar(&batchSize, "kafka-batch-size", 0, "Size in bytes of a batch which Kafka producer sends without waiting for \"kafka-linger\", 0 does not limit the batch by size")
+	flag.StringVar(&kafkaComp, "kafka-compression", "none", "Compression of messages produced to Kafka, one of \"none\", \"gzip\", \"snappy\", \"lz4\" or \"zstd\"")
+	flag.StringVar(&kafkaIdem, "kafka-idempotent", "false", "When set \"true\", Kafka producer is idempotent, messages are acknowledged by all in sync replicas and retried without duplicates")
+	flag.StringVar(&topicFile, "kafka-topics", "", "JSON file with templates of Kafka topic names per message type, when not set, topics are named gobmp.parsed.{type}")
+	flag.StringVar(&envelope, "message-envelope", "true", "When set \"true\" (default), published messages are wrapped in the envelope with schema version and collector id, if set \"false\", legacy bare messages are published")
+	flag.StringVar(&collector, "collector-id", "", "Identity of gobmp instance in the message envelope, when not set, the host name is used")
+	flag.StringVar(&msgFormat, "message-format", "json", "Format of messages published to Kafka or stored in the message file, one of \"json\", \"flat\", \"cbor\" or \"msgpack\"")
+	flag.StringVar(&naming, "message-field-naming", "snake_case", "Naming of fields of messages published to Kafka or stored in the message file, \"snake_case\" or \"camelCase\"")
 	flag.StringVar(&intercept, "intercept", "false", "When intercept set \"true\", all incomming BMP messges will be copied to TCP port specified by destination-port, otherwise received BMP messages will be published to Kafka.")
+	flag.StringVar(&teeDsts, "intercept-destinations", "", "Comma separated list of host:port of downstream collectors receiving copies of BMP messages in intercept mode, empty forwards to destination-port")
+	flag.IntVar(&teeQueue, "intercept-queue", 1000, "Number of BMP messages buffered per BMP session and intercept destination, messages not fitting the queue of a slow destination are dropped")
 	flag.StringVar(&splitAF, "split-af", "true", "When set \"true\" (default) ipv4 and ipv6 will be published in separate topics. if set \"false\" the same topic will be used for both address families.")
 	flag.IntVar(&perfPort, "performance-port", 56767, "port used for performance debugging")
-	flag.StringVar(&dump, "dump", "", "Dump resulting messages to file when \"dump=file\" or to the standard output when \"dump=console\"")
+	flag.StringVar(&logLevels, "log-levels", "", "Comma separated list of module=level setting verbosity of modules \"bmp\", \"bgp\", \"bgpls\", \"sr\" and \"kafka\" independently of --v, levels can be changed at runtime at /debug/log-levels on performance-port")
+	flag.StringVar(&capDir, "capture-dir", "", "Directory to write captures of raw BMP messages requested at /debug/capture on performance-port, empty disables captures")
+	flag.StringVar(&chkUpdate, "validate-updates", "false", "When set \"true\", BGP Updates are checked for missing mandatory attributes, malformed attributes and RFC 7606 treat-as-withdraw conditions, published messages are annotated with found issues")
+	flag.StringVar(&transConf, "transform-config", "", "JSON file with per message type rules renaming, removing and adding fields, dropping or redacting prefixes, anonymizing addresses, or Go plugins transforming messages before they are published")
+	flag.StringVar(&combine, "combine-updates", "", "Comma separated list of message types, e.g. unicast_prefix_v4,ls_link, messages of these types produced from a single BGP Update are published as a single message carrying json array of the messages, messages of other types are published one message per prefix")
+	flag.StringVar(&outTypes, "output-message-types", "", "Comma separated list of message types published to Kafka, file or console, names may carry \"*\" wildcards, e.g. ls_*, and names prefixed with \"!\" disable matching types, empty publishes all types")
+	flag.StringVar(&ssTypes, "state-stream-message-types", "", "Comma separated list of message types streamed by state-stream-port in the format of \"output-message-types\", empty streams all types")
+	flag.StringVar(&wsTypes, "websocket-message-types", "", "Comma separated list of message types streamed by websocket-port in the format of \"output-message-types\", empty streams all types")
+	flag.IntVar(&adminPort, "admin-port", 0, "Port of admin http API listing BMP sessions, changing log levels and enabling message types per destination at runtime, 0 disables the API")
+	flag.StringVar(&adminTok, "admin-token", "", "Bearer token required by requests of admin API, when not set, GOBMP_ADMIN_TOKEN environment variable is used")
+	flag.BoolVar(&rawUpdate, "attach-raw-update", false, "When set, messages produced from BGP Updates carry base64 encoded BGP UPDATE and BMP headers as received from the router in \"raw\" object")
+	flag.StringVar(&adminID, "openbmp-admin-id", "", "When set, router_hash, peer_hash, base_attr_hash and hash of peer and unicast prefix messages are calculated as by OpenBMP collector with the admin id, by default gobmp hashes are used")
+	flag.StringVar(&dump, "dump", "", "Dump resulting messages to file when \"dump=file\", to the standard output when \"dump=console\", post them to webhook-url when \"dump=webhook\", send them to SQS queue when \"dump=sqs\" or to Azure Event Hubs when \"dump=eventhubs\"")
 	flag.StringVar(&file, "msg-file", "/tmp/messages.json", "Full path anf file name to store messages when \"dump=file\"")
+	flag.StringVar(&fileComp, "msg-file-compression", "none", "Compression of messages stored in the message file, \"none\", \"gzip\" or \"zstd\"")
+	flag.IntVar(&fileBatch, "msg-file-batch", 0, "Number of messages compressed together in the message file, 0 compresses every message separately")
+	flag.StringVar(&hookURL, "webhook-url", "", "URL receiving batches of messages in POST requests when \"dump=webhook\"")
+	flag.StringVar(&hookKey, "webhook-secret", "", "Secret signing webhook requests with HMAC-SHA256 in X-Gobmp-Signature header, when not set, GOBMP_WEBHOOK_SECRET environment variable is used, empty does not sign requests")
+	flag.IntVar(&hookBatch, "webhook-batch", 100, "Maximum number of messages posted to the webhook in a single request")
+	flag.IntVar(&hookFlush, "webhook-flush-interval", 1000, "Time in milliseconds after which a partial batch of messages is posted to the webhook")
+	flag.IntVar(&hookRetry, "webhook-retries", 5, "Number of retries with exponential backoff of a failed webhook request")
+	flag.StringVar(&hookComp, "webhook-compression", "none", "Compression of bodies of webhook requests, \"none\", \"gzip\" or \"zstd\"")
+	flag.StringVar(&hookDLF, "webhook-dead-letter", "", "File storing messages which could not be posted to the webhook in the format of the message file, empty drops the messages")
+	flag.StringVar(&sqsURL, "sqs-queue-url", "", "URL of AWS SQS queue receiving messages when \"dump=sqs\", credentials are taken from AWS_ACCESS_KEY_ID, AWS_SECRET_ACCESS_KEY and AWS_SESSION_TOKEN environment variables")
+	flag.StringVar(&sqsRegion, "sqs-region", "", "AWS region of \"sqs-queue-url\", when not set, the region is taken from the queue url")
+	flag.StringVar(&ehConn, "eventhubs-connection-string", "", "Connection string of Azure Event Hubs receiving messages when \"dump=eventhubs\", when not set, GOBMP_EVENTHUBS_CONNECTION_STRING environment variable is used")
+	flag.StringVar(&ehName, "eventhubs-name", "", "Name of the event hub, when not set, EntityPath of \"eventhubs-connection-string\" is used")
+	flag.StringVar(&vrpSource, "rpki-vrp", "", "File or http(s) URL with VRPs in JSON or CSV format, when set, unicast prefixes are annotated with rpki_status")
+	flag.IntVar(&vrpReload, "rpki-vrp-reload", 0, "Interval in seconds to reload VRPs from \"rpki-vrp\", 0 disables reload")
+	flag.IntVar(&statePort, "state-stream-port", 0, "Port of http endpoint streaming the latest state of published objects as newline delimited json, 0 disables the endpoint")
+	flag.IntVar(&gnmiPort, "gnmi-port", 0, "Port of gNMI server exposing the latest state of published objects to Get and Subscribe RPCs with ON_CHANGE and SAMPLE subscriptions, 0 disables the server")
+	flag.IntVar(&wsPort, "websocket-port", 0, "Port of websocket endpoint streaming published messages, 0 disables the endpoint")
+	flag.StringVar(&geoLite, "geolite-dir", "", "Directory with MaxMind GeoLite2 Country CSV database, when set, prefixes are enriched with the country")
+	flag.StringVar(&webUI, "web-ui", "false", "When set \"true\", web UI with routers, peers and message rates is served at /ui/ on performance-port")
+	flag.IntVar(&topoPort, "topology-port", 0, "Port of http endpoint exposing BGP-LS topology graph and path computation, 0 disables the endpoint")
+	flag.IntVar(&lgPort, "looking-glass-port", 0, "Port of read-only looking glass http endpoint answering /routes and /peers queries from the latest state of unicast and L3VPN prefixes, 0 disables the endpoint")
+	flag.StringVar(&topoEvent, "topology-events", "false", "When set \"true\", events derived from BGP-LS topology changes are published to topology_event topic")
+	flag.IntVar(&flapWin, "flap-window", 60, "Window in seconds in which changes of a prefix are counted by flap detection")
+	flag.IntVar(&flapLimit, "flap-threshold", 0, "Number of changes of a prefix within \"flap-window\" to publish prefix_flap event, 0 disables flap detection")
+	flag.StringVar(&macEvents, "mac-events", "false", "When set \"true\", MACs of EVPN MAC/IP Advertisement routes moving between locations are published to mac_event topic")
+	flag.IntVar(&dupMACWin, "duplicate-mac-window", 180, "Window in seconds in which moves of a MAC are counted by duplicate MAC detection")
+	flag.IntVar(&dupMACLim, "duplicate-mac-threshold", 5, "Number of moves of a MAC within \"duplicate-mac-window\" to publish duplicate_mac_suspected event, 0 disables duplicate MAC detection")
+	flag.StringVar(&alertConf, "alert-config", "", "JSON file with per peer thresholds of prefixes, update rate and withdrawals, when set, alerts are published to alert topic")
+	flag.StringVar(&listeners, "bmp-listeners", "", "JSON file with BMP listeners, their allowed sources and tags, when set, source-port is not used")
+	flag.StringVar(&rtrGroups, "router-groups", "", "JSON file with named groups of routers matched by source prefixes, messages of the group's routers carry the group in enrichment and optionally are published to topics with the group's prefix")
+	flag.IntVar(&sessRate, "session-rate", 0, "Maximum number of BMP messages per second processed from a single BMP session, 0 disables the limit")
+	flag.IntVar(&totalRate, "total-rate", 0, "Maximum number of BMP messages per second processed from all BMP sessions, shared equally by active sessions, 0 disables the limit")
+	flag.IntVar(&idleTime, "session-idle-timeout", 0, "Time in seconds after which a BMP session without received messages is closed and peer down messages of its peers are published, BMP has no keepalives, the timeout must exceed statistics report interval of routers, 0 disables the timeout")
+	flag.BoolVar(&parseLat, "parse-latency", false, "When set, parse latency of BMP messages is recorded per BMP message type and per router, p50 and p99 latencies are returned at /debug/parse-latency on performance-port")
+	flag.BoolVar(&legacyIP, "legacy-address-format", false, "When set, IP addresses of published messages are rendered as before canonical forms were introduced, IPv4-mapped IPv6 addresses as IPv4 addresses and addresses of invalid length as returned by Go net.IP")
+	flag.StringVar(&otlpURL, "otlp-endpoint", "", "URL of OpenTelemetry collector receiving OTLP/HTTP traces, for example http://localhost:4318, when set, BMP messages are traced from receive through parse to publish, empty disables tracing")
+	flag.StringVar(&otlpHdrs, "otlp-headers", "", "Comma separated list of key=value headers added to OTLP export requests, for example authentication of tracing backend")
+	flag.Float64Var(&traceRate, "trace-ratio", 1, "Share of BMP messages traced when \"otlp-endpoint\" is set, greater than 0 and not greater than 1")
+	flag.StringVar(&clMembers, "cluster-members", "", "Comma separated list of BMP listening addresses host:port of all gobmp instances sharing BMP sessions, empty disables clustering")
+	flag.StringVar(&clSelf, "cluster-self", "", "BMP listening address host:port of this instance as listed in \"cluster-members\"")
+	flag.IntVar(&clAPIPort, "cluster-api-port", 0, "Port of cluster API sharing BMP sessions between cluster members and locating owners of routers, must be the same on all members, 0 disables it")
+	flag.StringVar(&stateFile, "state-file", "", "File to save BMP sessions state and published prefixes, when set, the state is restored on start and known routers resume their BMP sessions, cached prefixes are exported at /debug/rib on performance-port")
+	flag.IntVar(&stateIntv, "state-interval", 60, "Interval in seconds to save the state to \"state-file\"")
+	flag.IntVar(&stateSync, "state-resync", 300, "Time in seconds in which a known router re-sends its tables, unchanged prefixes are not published again and prefixes not re-sent are withdrawn")
+	flag.StringVar(&allowSrc, "bmp-allowed-sources", "", "Comma separated list of prefixes or addresses allowed to establish BMP session to source-port, empty allows any source")
+	flag.IntVar(&keepAlive, "bmp-keepalive", 0, "TCP keepalive period in seconds of BMP sessions to source-port, 0 uses the default period, negative value disables keepalives")
+	flag.IntVar(&rcvBuf, "bmp-receive-buffer", 0, "Size in bytes of socket receive buffer of BMP sessions to source-port, 0 uses the system default")
+	flag.IntVar(&acceptors, "bmp-acceptors", 1, "Number of sockets accepting BMP sessions on source-port with SO_REUSEPORT, linux only")
+	flag.StringVar(&unixSock, "bmp-unix-socket", "", "Path of unix socket accepting BMP sessions of co-located exporters in addition to TCP listeners, empty disables the socket")
+	flag.BoolVar(&stdin, "stdin", false, "Read a single BMP session from stdin, for example a capture piped to gobmp, and exit at the end of the stream, no listening sockets are opened")
+	flag.StringVar(&bmpTopic, "kafka-bmp-topic", "", "Kafka topic with raw BMP messages published by forwarders keyed by router, when set, BMP messages are consumed from the topic instead of listening for BMP sessions, empty disables the consumer")
+	flag.StringVar(&bmpOffset, "kafka-bmp-offset", "newest", "Offset to start consuming \"kafka-bmp-topic\" from, \"newest\" consumes BMP messages published after the start, \"oldest\" consumes all retained BMP messages")
+	flag.StringVar(&bmpKafka, "kafka-bmp-server", "", "URL to access Kafka server with \"kafka-bmp-topic\", when not set, kafka-server is used")
 }
 
 func main() {
 	flag.Parse()
 	_ = flag.Set("logtostderr", "true")
-	// Starting performance collecting http server
+	if err := logging.SetLevels(logLevels); err != nil {
+		glog.Errorf("failed to set log levels with error: %+v", err)
+		os.Exit(1)
+	}
+	addr.SetLegacy(legacyIP)
+	if bmpTopic != "" && stdin {
+		glog.Errorf("kafka-bmp-topic and stdin are mutually exclusive")
+		os.Exit(1)
+	}
+	if adminTok == "" {
+		adminTok = os.Getenv("GOBMP_ADMIN_TOKEN")
+	}
+	if hookKey == "" {
+		hookKey = os.Getenv("GOBMP_WEBHOOK_SECRET")
+	}
+	if ehConn == "" {
+		ehConn = os.Getenv("GOBMP_EVENTHUBS_CONNECTION_STRING")
+	}
+	if adminPort != 0 && adminTok == "" {
+		glog.Errorf("admin-port requires admin-token or GOBMP_ADMIN_TOKEN environment variable")
+		os.Exit(1)
+	}
+	if bmpOffset != "newest" && bmpOffset != "oldest" {
+		glog.Errorf("invalid kafka-bmp-offset %q, supported offsets are \"newest\" and \"oldest\"", bmpOffset)
+		os.Exit(1)
+	}
+	// Starting performance collecting http server, it also serves verbosity of modules
+	http.Handle("/debug/log-levels", logging.NewHandler())
 	go func() {
 		glog.Info(http.ListenAndServe(fmt.Sprintf(":%d", perfPort), nil))
 	}()
 	// Initializing publisher
 	var publisher pub.Publisher
+	var reopener filer.Reopener
 	var err error
+	// Initializing optional cluster membership
+	var members cluster.Cluster
+	if clMembers != "" {
+		members, err = cluster.NewCluster(clSelf, strings.Split(clMembers, ","), 5*time.Second)
+		if err != nil {
+			glog.Errorf("failed to initialize cluster membership with error: %+v", err)
+			os.Exit(1)
+		}
+		glog.V(5).Infof("cluster membership has been successfully initialized.")
+	}
+	if clAPIPort != 0 && members == nil {
+		glog.Errorf("cluster-api-port requires cluster-members")
+		os.Exit(1)
+	}
+	// Loading optional router groups, they are needed by Kafka topic names and BMP server
+	var groups []*gobmpsrv.RouterGroup
+	if rtrGroups != "" {
+		if groups, err = gobmpsrv.LoadRouterGroups(rtrGroups); err != nil {
+			glog.Errorf("failed to load router groups with error: %+v", err)
+			os.Exit(1)
+		}
+	}
+	// Loading optional message types published combined per BGP Update
+	combined := make(map[int]bool)
+	if combine != "" {
+		for _, name := range strings.Split(combine, ",") {
+			t, ok := bmp.MsgTypeByName(strings.TrimSpace(name))
+			if !ok {
+				glog.Errorf("unknown message type %q in combine-updates", name)
+				os.Exit(1)
+			}
+			combined[t] = true
+		}
+	}
+	// Loading optional message types enabled per destination, all types are published by default
+	outputTypes, err := pub.ParseTypes(outTypes)
+	if err != nil {
+		glog.Errorf("invalid output-message-types with error: %+v", err)
+		os.Exit(1)
+	}
+	stateStreamTypes, err := pub.ParseTypes(ssTypes)
+	if err != nil {
+		glog.Errorf("invalid state-stream-message-types with error: %+v", err)
+		os.Exit(1)
+	}
+	websocketTypes, err := pub.ParseTypes(wsTypes)
+	if err != nil {
+		glog.Errorf("invalid websocket-message-types with error: %+v", err)
+		os.Exit(1)
+	}
+	jsonFormat := msgFormat == "" || strings.EqualFold(msgFormat, codec.JSON)
+	binaryFormat := strings.EqualFold(msgFormat, codec.CBOR) || strings.EqualFold(msgFormat, codec.MessagePack)
 	switch strings.ToLower(dump) {
 	case "file":
-		publisher, err = filer.NewFiler(file)
+		publisher, err = filer.NewFilerWithConfig(file, &filer.Config{
+			Compression: fileComp,
+			BatchSize:   fileBatch,
+		})
 		if err != nil {
 			glog.Errorf("failed to initialize file publisher with error: %+v", err)
 			os.Exit(1)
 		}
+		reopener, _ = publisher.(filer.Reopener)
 		glog.V(5).Infof("file publisher has been successfully initialized.")
 	case "console":
+		if binaryFormat {
+			glog.Errorf("message-format %s can not be printed to the standard output", msgFormat)
+			os.Exit(1)
+		}
 		publisher, err = dumper.NewDumper()
 		if err != nil {
 			glog.Errorf("failed to initialize console publisher with error: %+v", err)
 			os.Exit(1)
 		}
 		glog.V(5).Infof("console publisher has been successfully initialized.")
+	case "webhook":
+		if binaryFormat {
+			glog.Errorf("message-format %s can not be posted to the webhook", msgFormat)
+			os.Exit(1)
+		}
+		publisher, err = webhook.NewPublisher(&webhook.Config{
+			URL:            hookURL,
+			Secret:         hookKey,
+			BatchSize:      hookBatch,
+			FlushInterval:  time.Duration(hookFlush) * time.Millisecond,
+			Retries:        hookRetry,
+			DeadLetterFile: hookDLF,
+			Compression:    hookComp,
+		})
+		if err != nil {
+			glog.Errorf("failed to initialize webhook publisher with error: %+v", err)
+			os.Exit(1)
+		}
+		glog.V(5).Infof("webhook publisher has been successfully initialized.")
+	case "sqs":
+		if binaryFormat {
+			glog.Errorf("message-format %s can not be sent to SQS", msgFormat)
+			os.Exit(1)
+		}
+		publisher, err = sqs.NewPublisher(&sqs.Config{
+			QueueURL: sqsURL,
+			Region:   sqsRegion,
+		})
+		if err != nil {
+			glog.Errorf("failed to initialize SQS publisher with error: %+v", err)
+			os.Exit(1)
+		}
+		glog.V(5).Infof("SQS publisher has been successfully initialized.")
+	case "eventhubs":
+		if binaryFormat {
+			glog.Errorf("message-format %s can not be sent to Event Hubs", msgFormat)
+			os.Exit(1)
+		}
+		publisher, err = eventhubs.NewPublisher(&eventhubs.Config{
+			ConnectionString: ehConn,
+			EventHub:         ehName,
+		})
+		if err != nil {
+			glog.Errorf("failed to initialize Event Hubs publisher with error: %+v", err)
+			os.Exit(1)
+		}
+		glog.V(5).Infof("Event Hubs publisher has been successfully initialized.")
 	default:
-		publisher, err = kafka.NewKafkaPublisher(kafkaSrv)
+		idempotent, perr := strconv.ParseBool(kafkaIdem)
+		if perr != nil {
+			glog.Errorf("failed to parse to bool the value of the kafka-idempotent flag with error: %+v", perr)
+			os.Exit(1)
+		}
+		names := kafka.DefaultTopicNames()
+		if topicFile != "" {
+			if names, perr = kafka.LoadTopicNames(topicFile); perr != nil {
+				glog.Errorf("failed to load Kafka topic names with error: %+v", perr)
+				os.Exit(1)
+			}
+		}
+		for g, prefix := range gobmpsrv.TopicPrefixes(groups) {
+			if names.GroupPrefixes == nil {
+				names.GroupPrefixes = make(map[string]string)
+			}
+			names.GroupPrefixes[g] = prefix
+		}
+		if !jsonFormat && !names.Static() {
+			glog.Errorf("Kafka topic names depending on message fields require message-format json")
+			os.Exit(1)
+		}
+		if naming == codec.CamelCase && !names.Static() {
+			glog.Errorf("Kafka topic names depending on message fields require message-field-naming snake_case")
+			os.Exit(1)
+		}
+		if len(combined) != 0 && !names.Static() {
+			glog.Errorf("Kafka topic names depending on message fields can not be used with combine-updates")
+			os.Exit(1)
+		}
+		publisher, err = kafka.NewKafkaPublisherWithConfig(kafkaSrv, &kafka.TopicConfig{
+			Partitions:  int32(topicPart),
+			Replication: int16(topicRepl),
+			Retention:   time.Duration(topicRet) * time.Second,
+			Types:       outputTypes,
+			// Only the cluster leader creates topics at start
+			Deferred: members != nil && !members.IsLeader(),
+		}, &kafka.ProducerConfig{
+			Linger:      time.Duration(lingerMs) * time.Millisecond,
+			BatchSize:   batchSize,
+			Compression: kafkaComp,
+			Idempotent:  idempotent,
+		}, names)
 		if err != nil {
 			glog.Errorf("failed to initialize Kafka publisher with error: %+v", err)
 			os.Exit(1)
 		}
 		glog.V(5).Infof("Kafka publisher has been successfully initialized.")
 	}
+	// Encoding messages published by the output publisher to the message format
+	if publisher, err = codec.NewPublisher(msgFormat, publisher); err != nil {
+		glog.Errorf("failed to initialize message format with error: %+v", err)
+		os.Exit(1)
+	}
+	// Renaming fields of messages published by the output publisher, transforms refer to fields as produced
+	if publisher, err = codec.NewNamingPublisher(naming, publisher); err != nil {
+		glog.Errorf("failed to initialize message field naming with error: %+v", err)
+		os.Exit(1)
+	}
+	// Wrapping messages published by the output publisher in the envelope, other publishers receive bare messages
+	envelopeFlag, err := strconv.ParseBool(envelope)
+	if err != nil {
+		glog.Errorf("failed to parse to bool the value of the message-envelope flag with error: %+v", err)
+		os.Exit(1)
+	}
+	if envelopeFlag {
+		if collector == "" {
+			if collector, err = os.Hostname(); err != nil {
+				glog.Errorf("failed to get host name for collector id with error: %+v", err)
+				os.Exit(1)
+			}
+		}
+		publisher = pub.NewEnvelope(collector, publisher)
+	}
+	// Combining messages of a single BGP Update published by the output publisher, other publishers receive
+	// messages one by one
+	if len(combined) != 0 {
+		publisher = pub.NewCombiner(combined, publisher)
+	}
+	// Transforming messages published by the output publisher before they are combined and wrapped in the envelope,
+	// other publishers receive messages as produced
+	if transConf != "" {
+		config, err := transform.LoadConfig(transConf)
+		if err != nil {
+			glog.Errorf("failed to load transform configuration with error: %+v", err)
+			os.Exit(1)
+		}
+		transformers, err := config.Transformers()
+		if err != nil {
+			glog.Errorf("failed to initialize transforms with error: %+v", err)
+			os.Exit(1)
+		}
+		publisher = transform.NewPublisher(transformers, publisher)
+		glog.V(5).Infof("%d transforms have been successfully initialized.", len(transformers))
+	}
+	// Publishing only enabled message types by the output publisher, admin API changes enabled types at runtime
+	filters := make(map[string]pub.TypeFilter)
+	if outputTypes != nil || adminPort != 0 {
+		f := pub.NewFilter(outputTypes, publisher)
+		filters["output"] = f
+		publisher = f
+	}
+	// Initializing optional state store, it suppresses unchanged prefixes re-sent by known routers
+	var store state.Store
+	if stateFile != "" {
+		store, err = state.NewStore(stateFile, time.Duration(stateIntv)*time.Second, time.Duration(stateSync)*time.Second, publisher)
+		if err != nil {
+			glog.Errorf("failed to initialize state store with error: %+v", err)
+			os.Exit(1)
+		}
+		publisher = store
+		// Cached prefixes are exported at /debug/rib on performance-port
+		http.Handle("/debug/rib", state.NewHandler(store))
+		glog.V(5).Infof("state store has been successfully initialized.")
+	}
+
+	// Initializing optional state stream server, it receives a copy of all published messages
+	var srv telemetry.Server
+	if statePort != 0 || gnmiPort != 0 {
+		srv = telemetry.NewServer()
+		var p pub.Publisher = srv
+		if stateStreamTypes != nil || adminPort != 0 {
+			f := pub.NewFilter(stateStreamTypes, srv)
+			filters["state-stream"] = f
+			p = f
+		}
+		publisher = pub.NewMulti(publisher, p)
+	}
+	if statePort != 0 {
+		go func() {
+			glog.Info(http.ListenAndServe(fmt.Sprintf(":%d", statePort), telemetry.NewHandler(srv)))
+		}()
+		glog.V(5).Infof("state stream server has been successfully initialized on port %d.", statePort)
+	}
+	// Initializing optional gNMI server, it exposes the state kept by the state stream server
+	if gnmiPort != 0 {
+		go func() {
+			glog.Info(gnmi.ListenAndServe(fmt.Sprintf(":%d", gnmiPort), srv))
+		}()
+		glog.V(5).Infof("gNMI server has been successfully initialized on port %d.", gnmiPort)
+	}
+
+	// Initializing optional websocket streamer, it receives a copy of all published messages
+	if wsPort != 0 {
+		ws := websocket.NewStreamer()
+		var p pub.Publisher = ws
+		if websocketTypes != nil || adminPort != 0 {
+			f := pub.NewFilter(websocketTypes, ws)
+			filters["websocket"] = f
+			p = f
+		}
+		publisher = pub.NewMulti(publisher, p)
+		mux := http.NewServeMux()
+		mux.Handle("/stream", ws)
+		go func() {
+			glog.Info(http.ListenAndServe(fmt.Sprintf(":%d", wsPort), mux))
+		}()
+		glog.V(5).Infof("websocket streamer has been successfully initialized on port %d.", wsPort)
+	}
+
+	// Initializing optional looking glass, it receives a copy of all published messages
+	if lgPort != 0 {
+		lg := lookingglass.NewLookingGlass()
+		publisher = pub.NewMulti(publisher, lg)
+		go func() {
+			glog.Info(http.ListenAndServe(fmt.Sprintf(":%d", lgPort), lg))
+		}()
+		glog.V(5).Infof("looking glass has been successfully initialized on port %d.", lgPort)
+	}
+
+	// Initializing optional topology graph, it receives a copy of all published messages
+	topoEventsFlag, err := strconv.ParseBool(topoEvent)
+	if err != nil {
+		glog.Errorf("failed to parse to bool the value of the topology-events flag with error: %+v", err)
+		os.Exit(1)
+	}
+	if topoPort != 0 || topoEventsFlag {
+		var events pub.Publisher
+		if topoEventsFlag {
+			events = publisher
+		}
+		g := topology.NewGraph(events)
+		publisher = pub.NewMulti(publisher, g)
+		if topoPort != 0 {
+			go func() {
+				glog.Info(http.ListenAndServe(fmt.Sprintf(":%d", topoPort), topology.NewHandler(g)))
+			}()
+		}
+		glog.V(5).Infof("topology graph has been successfully initialized.")
+	}
+
+	// Initializing optional prefix flap detector, it receives a copy of all published messages
+	if flapLimit != 0 {
+		if flapWin <= 0 {
+			glog.Errorf("invalid flap-window %d, must be greater than 0", flapWin)
+			os.Exit(1)
+		}
+		publisher = pub.NewMulti(publisher, flap.NewDetector(time.Duration(flapWin)*time.Second, flapLimit, publisher))
+		glog.V(5).Infof("prefix flap detector has been successfully initialized.")
+	}
+
+	// Initializing optional MAC move detector, it receives a copy of all published messages
+	macEventsFlag, err := strconv.ParseBool(macEvents)
+	if err != nil {
+		glog.Errorf("failed to parse to bool the value of the mac-events flag with error: %+v", err)
+		os.Exit(1)
+	}
+	if macEventsFlag {
+		if dupMACWin <= 0 {
+			glog.Errorf("invalid duplicate-mac-window %d, must be greater than 0", dupMACWin)
+			os.Exit(1)
+		}
+		publisher = pub.NewMulti(publisher, macmove.NewDetector(time.Duration(dupMACWin)*time.Second, dupMACLim, publisher))
+		glog.V(5).Infof("MAC move detector has been successfully initialized.")
+	}
+
+	// Initializing optional alert monitor, it receives a copy of all published messages
+	if alertConf != "" {
+		config, err := alert.LoadConfig(alertConf)
+		if err != nil {
+			glog.Errorf("failed to load alert configuration with error: %+v", err)
+			os.Exit(1)
+		}
+		publisher = pub.NewMulti(publisher, alert.NewMonitor(config, publisher))
+		glog.V(5).Infof("alert monitor has been successfully initialized.")
+	}
+
+	// Initializing optional web UI, it is served by performance collecting http server, when telemetry server
+	// is enabled, its cache is used to search prefixes and topology objects.
+	webUIFlag, err := strconv.ParseBool(webUI)
+	if err != nil {
+		glog.Errorf("failed to parse to bool the value of the web-ui flag with error: %+v", err)
+		os.Exit(1)
+	}
+	if webUIFlag {
+		ui := webui.NewDashboard(srv)
+		publisher = pub.NewMulti(publisher, ui)
+		http.Handle("/ui/", ui)
+		glog.V(5).Infof("web ui has been successfully initialized on port %d.", perfPort)
+	}
 
 	// Initializing bmp server
 	interceptFlag, err := strconv.ParseBool(intercept)
@@ -83,22 +641,253 @@ func main() {
 		glog.Errorf("failed to parse to bool the value of the intercept flag with error: %+v", err)
 		os.Exit(1)
 	}
+	// Initializing tee forwarding BMP messages to downstream collectors in intercept mode
+	var tee gobmpsrv.Tee
+	if interceptFlag {
+		dst := []string{fmt.Sprintf(":%d", dstPort)}
+		if teeDsts != "" {
+			dst = strings.Split(teeDsts, ",")
+		}
+		if tee, err = gobmpsrv.NewTee(dst, teeQueue); err != nil {
+			glog.Errorf("failed to initialize intercept with error: %+v", err)
+			os.Exit(1)
+		}
+		// Forwarding statistics are returned at /debug/intercept on performance-port
+		http.Handle("/debug/intercept", gobmpsrv.NewTeeHandler(tee))
+	}
+	var recorder latency.Recorder
+	if parseLat {
+		recorder = latency.NewRecorder()
+		http.Handle("/debug/parse-latency", latency.NewHandler(recorder))
+	}
+	var tracer tracing.Tracer
+	if otlpURL != "" {
+		headers, err := tracing.ParseHeaders(otlpHdrs)
+		if err != nil {
+			glog.Errorf("failed to parse otlp-headers with error: %+v", err)
+			os.Exit(1)
+		}
+		if tracer, err = tracing.NewTracer(&tracing.ExporterConfig{Endpoint: otlpURL, ServiceName: "gobmp", Headers: headers}, traceRate); err != nil {
+			glog.Errorf("failed to initialize tracing with error: %+v", err)
+			os.Exit(1)
+		}
+		glog.V(5).Infof("tracing has been successfully initialized, spans are exported to %s.", otlpURL)
+	}
 	splitAFFlag, err := strconv.ParseBool(splitAF)
 	if err != nil {
 		glog.Errorf("failed to parse to bool the value of the intercept flag with error: %+v", err)
 		os.Exit(1)
 	}
-	bmpSrv, err := gobmpsrv.NewBMPServer(srcPort, dstPort, interceptFlag, publisher, splitAFFlag)
+	chkUpdateFlag, err := strconv.ParseBool(chkUpdate)
+	if err != nil {
+		glog.Errorf("failed to parse to bool the value of the validate-updates flag with error: %+v", err)
+		os.Exit(1)
+	}
+	// Initializing optional RPKI validator
+	var validator rpki.Validator
+	if vrpSource != "" {
+		vrps, err := rpki.LoadVRPs(vrpSource)
+		if err != nil {
+			glog.Errorf("failed to load VRPs from %s with error: %+v", vrpSource, err)
+			os.Exit(1)
+		}
+		validator = rpki.NewValidator(vrps)
+		glog.V(5).Infof("RPKI validator has been successfully initialized with %d VRPs.", len(vrps))
+	}
+	// Initializing optional enrichment plugins
+	enrichers := make([]enrich.Enricher, 0)
+	if geoLite != "" {
+		g, err := enrich.NewGeoLite(geoLite)
+		if err != nil {
+			glog.Errorf("failed to load GeoLite database from %s with error: %+v", geoLite, err)
+			os.Exit(1)
+		}
+		enrichers = append(enrichers, g)
+		glog.V(5).Infof("GeoLite enrichment plugin has been successfully initialized.")
+	}
+	// Initializing optional rate limiter of BMP sessions
+	var limiter gobmpsrv.RateLimiter
+	if sessRate < 0 || totalRate < 0 {
+		glog.Errorf("invalid session-rate %d or total-rate %d, must not be negative", sessRate, totalRate)
+		os.Exit(1)
+	}
+	if sessRate != 0 || totalRate != 0 {
+		limiter = gobmpsrv.NewRateLimiter(sessRate, totalRate)
+	}
+	if idleTime < 0 {
+		glog.Errorf("invalid session-idle-timeout %d, must not be negative", idleTime)
+		os.Exit(1)
+	}
+	// Initializing optional capture of raw BMP messages, captures are requested at /debug/capture
+	// on performance-port
+	var capturer capture.Capturer
+	if capDir != "" {
+		capturer, err = capture.NewCapturer(capDir)
+		if err != nil {
+			glog.Errorf("failed to initialize capture of BMP messages with error: %+v", err)
+			os.Exit(1)
+		}
+		http.Handle("/debug/capture", capture.NewHandler(capturer))
+		glog.V(5).Infof("capture of BMP messages has been successfully initialized.")
+	}
+	var lcs []*gobmpsrv.ListenerConfig
+	switch {
+	case stdin:
+		// BMP session is read from stdin, no listening sockets are opened
+	case bmpTopic != "":
+		// BMP messages are consumed from Kafka, no listening sockets are opened
+	case listeners != "":
+		lcs, err = gobmpsrv.LoadListeners(listeners)
+		if err != nil {
+			glog.Errorf("failed to load BMP listeners with error: %+v", err)
+			os.Exit(1)
+		}
+	default:
+		// Default listener on source-port
+		lc := &gobmpsrv.ListenerConfig{
+			Name:          "default",
+			Address:       fmt.Sprintf(":%d", srcPort),
+			KeepAlive:     keepAlive,
+			ReceiveBuffer: rcvBuf,
+			Acceptors:     acceptors,
+		}
+		if allowSrc != "" {
+			lc.AllowedSources = strings.Split(allowSrc, ",")
+		}
+		lcs = append(lcs, lc)
+	}
+	if unixSock != "" && !stdin && bmpTopic == "" {
+		lcs = append(lcs, &gobmpsrv.ListenerConfig{Name: "unix", Network: "unix", Address: unixSock, ReceiveBuffer: rcvBuf})
+	}
+	bmpSrv, err := gobmpsrv.NewBMPServerWithConfig(lcs, publisher, &gobmpsrv.Config{
+		Tee:            tee,
+		SplitAF:        splitAFFlag,
+		Validator:      validator,
+		Enrichers:      enrichers,
+		RouterGroups:   groups,
+		RateLimiter:    limiter,
+		Cluster:        members,
+		Store:          store,
+		Capturer:       capturer,
+		CheckUpdates:   chkUpdateFlag,
+		AttachRaw:      rawUpdate,
+		IdleTimeout:    time.Duration(idleTime) * time.Second,
+		Latency:        recorder,
+		Tracer:         tracer,
+		OpenBMPAdminID: adminID,
+	})
 	if err != nil {
 		glog.Errorf("failed to setup new gobmp server with error: %+v", err)
 		os.Exit(1)
 	}
+	if stdin {
+		// Publishing messages of BMP session piped to stdin and exiting at the end of the stream
+		if err := bmpSrv.Serve("stdin", os.Stdin); err != nil {
+			glog.Errorf("failed to process BMP messages from stdin with error: %+v", err)
+			bmpSrv.Stop()
+			os.Exit(1)
+		}
+		bmpSrv.Stop()
+		os.Exit(0)
+	}
+	// Starting optional cluster API, members pull BMP sessions of each other from it
+	var registry cluster.Registry
+	if clAPIPort != 0 {
+		registry = cluster.NewRegistry(members, clAPIPort, func() []*cluster.Session {
+			return clusterSessions(bmpSrv)
+		}, 5*time.Second)
+		go func() {
+			glog.Info(http.ListenAndServe(fmt.Sprintf(":%d", clAPIPort), cluster.NewHandler(members, registry)))
+		}()
+		glog.V(5).Infof("cluster API has been successfully initialized on port %d.", clAPIPort)
+	}
+	// Starting optional admin API, it is served on its own port as it requires authentication
+	if adminPort != 0 {
+		go func() {
+			glog.Info(http.ListenAndServe(fmt.Sprintf(":%d", adminPort), admin.NewHandler(adminTok, bmpSrv, filters)))
+		}()
+		glog.V(5).Infof("admin API has been successfully initialized on port %d.", adminPort)
+	}
+	// Starting optional consumer of BMP messages published to Kafka by forwarders, each router's records
+	// are served as a BMP session
+	var consumer kafka.BMPConsumer
+	if bmpTopic != "" {
+		srv := bmpKafka
+		if srv == "" {
+			srv = kafkaSrv
+		}
+		consumer, err = kafka.NewBMPConsumer(srv, bmpTopic, bmpOffset == "oldest", bmpSrv.Serve)
+		if err != nil {
+			glog.Errorf("failed to consume BMP messages from Kafka topic %s with error: %+v", bmpTopic, err)
+			bmpSrv.Stop()
+			os.Exit(1)
+		}
+	}
 	// Starting Interceptor server
 	bmpSrv.Start()
 
 	stopCh := tools.SetupSignalHandler()
+	if validator != nil && vrpReload > 0 {
+		go rpki.Refresh(validator, vrpSource, time.Second*time.Duration(vrpReload), stopCh)
+	}
+	// SIGUSR1 dumps BMP sessions to the log, SIGUSR2 reopens the message file after its rotation
+	usrCh := make(chan os.Signal, 1)
+	signal.Notify(usrCh, syscall.SIGUSR1, syscall.SIGUSR2)
+	go func() {
+		for sig := range usrCh {
+			switch sig {
+			case syscall.SIGUSR1:
+				sessions := bmpSrv.Sessions()
+				glog.Infof("%d BMP sessions, %d goroutines", len(sessions), runtime.NumGoroutine())
+				for _, s := range sessions {
+					glog.Infof("BMP session %s listener %q remote %s started %s messages %d", s.Name, s.Listener, s.Remote, s.Started.Format(time.RFC3339), s.Messages)
+				}
+			case syscall.SIGUSR2:
+				if reopener == nil {
+					continue
+				}
+				if err := reopener.Reopen(); err != nil {
+					glog.Errorf("failed to reopen message file %s with error: %+v", file, err)
+					continue
+				}
+				glog.Infof("message file %s has been reopened", file)
+			}
+		}
+	}()
+	// Notifying systemd that the startup is complete, the watchdog is pinged only when it is enabled
+	if err := systemd.Notify(systemd.Ready); err != nil {
+		glog.Warningf("failed to notify systemd with error: %+v", err)
+	}
+	go systemd.Watchdog(stopCh)
 	<-stopCh
+	if err := systemd.Notify(systemd.Stopping); err != nil {
+		glog.Warningf("failed to notify systemd with error: %+v", err)
+	}
 
+	if consumer != nil {
+		consumer.Stop()
+	}
 	bmpSrv.Stop()
+	if registry != nil {
+		registry.Stop()
+	}
+	if members != nil {
+		members.Stop()
+	}
 	os.Exit(0)
 }
+
+// clusterSessions returns BMP sessions of routers served by the local cluster member, sessions without
+// the router's address, for example read from stdin, are not shared.
+func clusterSessions(srv gobmpsrv.BMPServer) []*cluster.Session {
+	sessions := make([]*cluster.Session, 0)
+	for _, s := range srv.Sessions() {
+		host, _, err := net.SplitHostPort(s.Remote)
+		if err != nil || net.ParseIP(host) == nil {
+			continue
+		}
+		sessions = append(sessions, &cluster.Session{Router: host, Started: s.Started})
+	}
+
+	return sessions
+}
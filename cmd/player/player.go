@@ -52,7 +52,12 @@ func main() {
 	glog.V(5).Infof("Kafka publisher has been successfully initialized.")
 	defer publisher.Stop()
 
-	msgs, err := loadMessages(f)
+	r, err := filer.NewReader(f)
+	if err != nil {
+		glog.Errorf("fail to read messages file %s with error: %+v", file, err)
+		os.Exit(1)
+	}
+	msgs, err := loadMessages(r)
 	if err != nil {
 		glog.Errorf("Failed to load messages with error: %+v", err)
 		os.Exit(1)
@@ -81,9 +86,9 @@ func main() {
 	os.Exit(0)
 }
 
-func loadMessages(f *os.File) ([]*filer.MsgOut, error) {
+func loadMessages(r io.Reader) ([]*filer.MsgOut, error) {
 	msgs := make([]*filer.MsgOut, 0)
-	m := bufio.NewReader(f)
+	m := bufio.NewReader(r)
 	done := false
 	for !done {
 		b, err := m.ReadBytes('\n')
@@ -98,6 +103,11 @@ func loadMessages(f *os.File) ([]*filer.MsgOut, error) {
 		if err := json.Unmarshal(b, msg); err != nil {
 			return nil, fmt.Errorf("fail to unmarshal message with error: %+v", err)
 		}
+		// Messages are replayed as they were published
+		if msg.Value, err = msg.Payload(); err != nil {
+			return nil, fmt.Errorf("fail to decompress message with error: %+v", err)
+		}
+		msg.Encoding = ""
 		msgs = append(msgs, msg)
 	}
 
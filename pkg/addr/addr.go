@@ -0,0 +1,69 @@
+package addr
+
+import (
+	"net"
+	"sync/atomic"
+)
+
+// legacy is set to 1 when addresses are rendered the way they were before canonical forms were introduced,
+// it is accessed atomically
+var legacy int32
+
+// SetLegacy sets rendering of addresses to the legacy format, IPv4-mapped IPv6 addresses are rendered
+// as IPv4 addresses and addresses of invalid length as returned by net.IP String.
+func SetLegacy(l bool) {
+	v := int32(0)
+	if l {
+		v = 1
+	}
+	atomic.StoreInt32(&legacy, v)
+}
+
+// IsLegacy returns true when addresses are rendered in the legacy format
+func IsLegacy() bool {
+	return atomic.LoadInt32(&legacy) == 1
+}
+
+// IPv4 returns dotted decimal IPv4 address without leading zeros of 4 bytes or of IPv4-mapped IPv6 address
+// of 16 bytes, empty string is returned for other values.
+func IPv4(b []byte) string {
+	if IsLegacy() {
+		return net.IP(b).To4().String()
+	}
+	ip := net.IP(b).To4()
+	if ip == nil {
+		return ""
+	}
+
+	return ip.String()
+}
+
+// IPv6 returns lowercase IPv6 address of 16 bytes in RFC 5952 form, IPv4-mapped IPv6 address is returned
+// as ::ffff: followed by dotted decimal IPv4 address, 4 bytes are returned as IPv4 address, empty string
+// is returned for other values.
+func IPv6(b []byte) string {
+	if IsLegacy() {
+		return net.IP(b).To16().String()
+	}
+	switch len(b) {
+	case net.IPv4len:
+		return IPv4(b)
+	case net.IPv6len:
+		if ip := net.IP(b).To4(); ip != nil {
+			return "::ffff:" + ip.String()
+		}
+		return net.IP(b).String()
+	}
+
+	return ""
+}
+
+// String returns IPv4 address of 4 bytes and IPv6 address of 16 bytes in their canonical forms,
+// empty string is returned for other values.
+func String(b []byte) string {
+	if IsLegacy() {
+		return net.IP(b).String()
+	}
+
+	return IPv6(b)
+}
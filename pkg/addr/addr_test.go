@@ -0,0 +1,41 @@
+package addr
+
+import (
+	"net"
+	"testing"
+)
+
+func TestAddr(t *testing.T) {
+	mapped := []byte(net.ParseIP("192.0.2.1"))
+	tests := []struct {
+		name   string
+		f      func([]byte) string
+		b      []byte
+		expect string
+		legacy string
+	}{
+		{name: "ipv4", f: IPv4, b: []byte{10, 0, 0, 1}, expect: "10.0.0.1", legacy: "10.0.0.1"},
+		{name: "ipv4 of mapped ipv6", f: IPv4, b: mapped, expect: "192.0.2.1", legacy: "192.0.2.1"},
+		{name: "ipv4 of invalid length", f: IPv4, b: []byte{10, 0, 1}, expect: "", legacy: "<nil>"},
+		{name: "ipv6", f: IPv6, b: []byte(net.ParseIP("2001:DB8:0:0:1:0:0:1")), expect: "2001:db8::1:0:0:1", legacy: "2001:db8::1:0:0:1"},
+		{name: "ipv6 of mapped ipv4", f: IPv6, b: mapped, expect: "::ffff:192.0.2.1", legacy: "192.0.2.1"},
+		{name: "ipv6 of ipv4", f: IPv6, b: []byte{10, 0, 0, 1}, expect: "10.0.0.1", legacy: "10.0.0.1"},
+		{name: "ipv6 of invalid length", f: IPv6, b: []byte{0x20, 0x01}, expect: "", legacy: "<nil>"},
+		{name: "string of ipv4", f: String, b: []byte{192, 168, 0, 10}, expect: "192.168.0.10", legacy: "192.168.0.10"},
+		{name: "string of mapped ipv4", f: String, b: mapped, expect: "::ffff:192.0.2.1", legacy: "192.0.2.1"},
+		{name: "string of invalid length", f: String, b: []byte{0x20, 0x01}, expect: "", legacy: "?2001"},
+	}
+	defer SetLegacy(false)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			SetLegacy(false)
+			if s := tt.f(tt.b); s != tt.expect {
+				t.Errorf("expected %q, got %q", tt.expect, s)
+			}
+			SetLegacy(true)
+			if s := tt.f(tt.b); s != tt.legacy {
+				t.Errorf("expected legacy %q, got %q", tt.legacy, s)
+			}
+		})
+	}
+}
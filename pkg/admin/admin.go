@@ -0,0 +1,184 @@
+package admin
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/golang/glog"
+	"github.com/sbezverk/gobmp/pkg/bmp"
+	"github.com/sbezverk/gobmp/pkg/gobmpsrv"
+	"github.com/sbezverk/gobmp/pkg/logging"
+	"github.com/sbezverk/gobmp/pkg/pub"
+)
+
+// SessionLister defines the source of BMP sessions listed by the admin API
+type SessionLister interface {
+	Sessions() []*gobmpsrv.SessionInfo
+}
+
+type admin struct {
+	token    string
+	sessions SessionLister
+	filters  map[string]pub.TypeFilter
+}
+
+// NewHandler returns http handler of the admin API reconfiguring the collector at runtime, every request must carry
+// the token in "Authorization: Bearer {token}" header:
+//
+//	GET /admin/sessions
+//	GET /admin/log-levels
+//	POST /admin/log-levels?module={module}&level={level}
+//	GET /admin/filters
+//	POST /admin/filters?destination={destination}&types={types}
+//	POST /admin/filters?destination={destination}&enable={type}
+//	POST /admin/filters?destination={destination}&disable={type}
+//
+// sessions returns BMP sessions served by the collector, log-levels returns and sets verbosity of modules.
+// filters returns message types enabled per destination, POST replaces enabled types of the destination
+// with types in the format of ParseTypes of pkg/pub, empty types enable all types, or enables or disables
+// matching types. filters are keyed by destination name, for example "output".
+func NewHandler(token string, sessions SessionLister, filters map[string]pub.TypeFilter) http.Handler {
+	a := &admin{
+		token:    token,
+		sessions: sessions,
+		filters:  filters,
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/admin/sessions", a.handleSessions)
+	mux.Handle("/admin/log-levels", logging.NewHandler())
+	mux.HandleFunc("/admin/filters", a.handleFilters)
+
+	return a.authenticate(mux)
+}
+
+func (a *admin) authenticate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+		// Admin API without a token is never open
+		if a.token == "" || subtle.ConstantTimeCompare([]byte(token), []byte(a.token)) != 1 {
+			glog.Warningf("rejected unauthenticated admin request %s %s from %s", r.Method, r.URL.Path, r.RemoteAddr)
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (a *admin) handleSessions(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	sessions := make([]*gobmpsrv.SessionInfo, 0)
+	if a.sessions != nil {
+		sessions = a.sessions.Sessions()
+	}
+	writeJSON(w, sessions)
+}
+
+func (a *admin) handleFilters(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+	case http.MethodPost, http.MethodPut:
+		q := r.URL.Query()
+		dst := q.Get("destination")
+		f, ok := a.filters[dst]
+		if !ok {
+			http.Error(w, fmt.Sprintf("unknown destination %q, supported destinations: %s", dst, strings.Join(a.destinations(), ",")), http.StatusBadRequest)
+			return
+		}
+		types, err := update(f.Types(), q.Get("types"), q.Get("enable"), q.Get("disable"))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		f.SetTypes(types)
+		glog.Infof("message types of destination %s are set to %s", dst, strings.Join(typeNames(types), ","))
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	filters := make(map[string][]string, len(a.filters))
+	for dst, f := range a.filters {
+		filters[dst] = typeNames(f.Types())
+	}
+	writeJSON(w, filters)
+}
+
+// update returns enabled types after replacing current types with types, or after enabling or disabling
+// matching types, nil current and nil returned types enable all types.
+func update(current map[int]bool, types, enable, disable string) (map[int]bool, error) {
+	if enable == "" && disable == "" {
+		return pub.ParseTypes(types)
+	}
+	if types != "" {
+		return nil, fmt.Errorf("types can not be combined with enable or disable")
+	}
+	if current == nil {
+		current = allTypes()
+	}
+	if enable != "" {
+		matched, err := pub.ParseTypes(enable)
+		if err != nil {
+			return nil, err
+		}
+		for t := range matched {
+			current[t] = true
+		}
+	}
+	if disable != "" {
+		matched, err := pub.ParseTypes(disable)
+		if err != nil {
+			return nil, err
+		}
+		for t := range matched {
+			delete(current, t)
+		}
+	}
+
+	return current, nil
+}
+
+func allTypes() map[int]bool {
+	types := make(map[int]bool)
+	for _, t := range bmp.MsgTypes() {
+		types[t] = true
+	}
+
+	return types
+}
+
+// typeNames returns sorted names of enabled types, nil types are all types
+func typeNames(types map[int]bool) []string {
+	if types == nil {
+		types = allTypes()
+	}
+	names := make([]string, 0, len(types))
+	for t := range types {
+		names = append(names, bmp.MsgTypeName(t))
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+func (a *admin) destinations() []string {
+	dsts := make([]string, 0, len(a.filters))
+	for dst := range a.filters {
+		dsts = append(dsts, dst)
+	}
+	sort.Strings(dsts)
+
+	return dsts
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		glog.Errorf("failed to send admin response with error: %+v", err)
+	}
+}
@@ -0,0 +1,123 @@
+package admin
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/sbezverk/gobmp/pkg/bmp"
+	"github.com/sbezverk/gobmp/pkg/gobmpsrv"
+	"github.com/sbezverk/gobmp/pkg/pub"
+)
+
+type fakeSessions []*gobmpsrv.SessionInfo
+
+func (f fakeSessions) Sessions() []*gobmpsrv.SessionInfo {
+	return f
+}
+
+type discard struct{}
+
+func (discard) PublishMessage(msgType int, msgHash []byte, msg []byte) error { return nil }
+
+func (discard) Stop() {}
+
+func TestAdmin(t *testing.T) {
+	started := time.Date(2026, 10, 15, 0, 0, 0, 0, time.UTC)
+	output := pub.NewFilter(nil, discard{})
+	h := NewHandler("secret", fakeSessions{{Name: "192.0.2.1", Listener: "default", Remote: "192.0.2.1:34567", Started: started, Messages: 10}},
+		map[string]pub.TypeFilter{"output": output})
+	tests := []struct {
+		name   string
+		method string
+		url    string
+		token  string
+		status int
+		expect interface{}
+		types  map[int]bool
+	}{
+		{
+			name:   "unauthenticated",
+			method: http.MethodGet,
+			url:    "/admin/sessions",
+			token:  "guess",
+			status: http.StatusUnauthorized,
+		},
+		{
+			name:   "sessions",
+			method: http.MethodGet,
+			url:    "/admin/sessions",
+			token:  "secret",
+			status: http.StatusOK,
+			expect: []interface{}{map[string]interface{}{"name": "192.0.2.1", "listener": "default", "remote": "192.0.2.1:34567", "started": "2026-10-15T00:00:00Z", "messages": float64(10)}},
+		},
+		{
+			name:   "set types",
+			method: http.MethodPost,
+			url:    "/admin/filters?destination=output&types=ls_node,ls_link",
+			token:  "secret",
+			status: http.StatusOK,
+			expect: map[string]interface{}{"output": []interface{}{"ls_link", "ls_node"}},
+			types:  map[int]bool{bmp.LSNodeMsg: true, bmp.LSLinkMsg: true},
+		},
+		{
+			name:   "enable type",
+			method: http.MethodPost,
+			url:    "/admin/filters?destination=output&enable=peer",
+			token:  "secret",
+			status: http.StatusOK,
+			expect: map[string]interface{}{"output": []interface{}{"ls_link", "ls_node", "peer"}},
+			types:  map[int]bool{bmp.LSNodeMsg: true, bmp.LSLinkMsg: true, bmp.PeerStateChangeMsg: true},
+		},
+		{
+			name:   "disable type",
+			method: http.MethodPost,
+			url:    "/admin/filters?destination=output&disable=ls_*",
+			token:  "secret",
+			status: http.StatusOK,
+			expect: map[string]interface{}{"output": []interface{}{"peer"}},
+			types:  map[int]bool{bmp.PeerStateChangeMsg: true},
+		},
+		{
+			name:   "unknown destination",
+			method: http.MethodPost,
+			url:    "/admin/filters?destination=kafka&types=peer",
+			token:  "secret",
+			status: http.StatusBadRequest,
+			types:  map[int]bool{bmp.PeerStateChangeMsg: true},
+		},
+		{
+			name:   "log level",
+			method: http.MethodPost,
+			url:    "/admin/log-levels?module=bgp&level=0",
+			token:  "secret",
+			status: http.StatusOK,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.url, nil)
+			req.Header.Set("Authorization", "Bearer "+tt.token)
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, req)
+			if w.Code != tt.status {
+				t.Fatalf("expected status %d, got %d: %s", tt.status, w.Code, w.Body.String())
+			}
+			if tt.expect != nil {
+				var v interface{}
+				if err := json.Unmarshal(w.Body.Bytes(), &v); err != nil {
+					t.Fatalf("failed to unmarshal response with error: %+v", err)
+				}
+				if !reflect.DeepEqual(v, tt.expect) {
+					t.Errorf("expected response %+v, got %+v", tt.expect, v)
+				}
+			}
+			if tt.types != nil && !reflect.DeepEqual(output.Types(), tt.types) {
+				t.Errorf("expected enabled types %v, got %v", tt.types, output.Types())
+			}
+		})
+	}
+}
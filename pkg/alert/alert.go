@@ -0,0 +1,250 @@
+package alert
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/sbezverk/gobmp/pkg/bmp"
+	"github.com/sbezverk/gobmp/pkg/pub"
+)
+
+// Type defines the type of alert
+type Type string
+
+const (
+	// MaxPrefixes is raised when the number of prefixes advertised by a peer exceeds the limit
+	MaxPrefixes Type = "max_prefixes"
+	// UpdateRate is raised when the rate of updates from a peer exceeds the limit
+	UpdateRate Type = "update_rate"
+	// MassWithdrawal is raised when the number of withdrawals from a peer within the interval exceeds the limit
+	MassWithdrawal Type = "mass_withdrawal"
+)
+
+// Alert defines alert message
+type Alert struct {
+	Alert     Type   `json:"alert"`
+	Timestamp string `json:"timestamp"`
+	RouterIP  string `json:"router_ip"`
+	PeerIP    string `json:"peer_ip"`
+	PeerASN   uint32 `json:"peer_asn,omitempty"`
+	Value     int    `json:"value"`
+	Threshold int    `json:"threshold"`
+}
+
+// Monitor defines a Publisher watching published messages of all peers and raising alerts when
+// thresholds are exceeded. An alert is raised once and it is raised again only after the value
+// has dropped below the threshold.
+type Monitor interface {
+	pub.Publisher
+}
+
+type peer struct {
+	routerIP    string
+	peerIP      string
+	peerASN     uint32
+	prefixes    map[string]struct{}
+	updates     int
+	withdrawals int
+	// active keeps alerts raised and not yet cleared
+	active map[Type]bool
+}
+
+type monitor struct {
+	sync.Mutex
+	config *Config
+	alerts pub.Publisher
+	client *http.Client
+	peers  map[string]*peer
+	stop   chan struct{}
+}
+
+var _ Monitor = &monitor{}
+
+// message defines fields of published messages used by the monitor
+type message struct {
+	Action    string `json:"action"`
+	Hash      string `json:"hash"`
+	RouterIP  string `json:"router_ip"`
+	PeerIP    string `json:"peer_ip"`
+	PeerASN   uint32 `json:"peer_asn"`
+	RemoteIP  string `json:"remote_ip"`
+	RemoteASN uint32 `json:"remote_asn"`
+}
+
+func (m *monitor) PublishMessage(msgType int, msgHash []byte, msg []byte) error {
+	switch msgType {
+	case bmp.StatsReportMsg, bmp.TopologyEventMsg, bmp.PrefixFlapMsg, bmp.AlertMsg:
+		return nil
+	}
+	k := &message{}
+	if err := json.Unmarshal(msg, k); err != nil {
+		return fmt.Errorf("failed to unmarshal message with error: %+v", err)
+	}
+	if msgType == bmp.PeerStateChangeMsg {
+		k.PeerIP, k.PeerASN = k.RemoteIP, k.RemoteASN
+	}
+	if k.PeerIP == "" {
+		return nil
+	}
+	m.Lock()
+	alerts := m.process(msgType, k)
+	m.Unlock()
+
+	return m.raise(alerts)
+}
+
+func (m *monitor) Stop() {
+	close(m.stop)
+}
+
+func (m *monitor) process(msgType int, k *message) []*Alert {
+	id := k.RouterIP + "_" + k.PeerIP
+	p, ok := m.peers[id]
+	if !ok {
+		p = &peer{
+			routerIP: k.RouterIP,
+			peerIP:   k.PeerIP,
+			prefixes: make(map[string]struct{}),
+			active:   make(map[Type]bool),
+		}
+		m.peers[id] = p
+	}
+	if k.PeerASN != 0 {
+		p.peerASN = k.PeerASN
+	}
+	if msgType == bmp.PeerStateChangeMsg {
+		// Peer down withdraws all prefixes of the peer
+		if k.Action == "down" {
+			p.prefixes = make(map[string]struct{})
+			delete(p.active, MaxPrefixes)
+		}
+		return nil
+	}
+	p.updates++
+	if k.Action == "del" {
+		p.withdrawals++
+	}
+	switch msgType {
+	case bmp.UnicastPrefixMsg, bmp.UnicastPrefixV4Msg, bmp.UnicastPrefixV6Msg:
+	case bmp.L3VPNMsg, bmp.L3VPNV4Msg, bmp.L3VPNV6Msg:
+	default:
+		return nil
+	}
+	if k.Action == "del" {
+		delete(p.prefixes, k.Hash)
+	} else {
+		p.prefixes[k.Hash] = struct{}{}
+	}
+	t := m.config.thresholds(p.peerIP)
+
+	return p.check(MaxPrefixes, len(p.prefixes), t.MaxPrefixes)
+}
+
+// check returns the alert when the value exceeds the threshold and the alert is not active,
+// the alert is cleared when the value drops to or below the threshold.
+func (p *peer) check(t Type, value, threshold int) []*Alert {
+	if threshold == 0 {
+		return nil
+	}
+	if value <= threshold {
+		delete(p.active, t)
+		return nil
+	}
+	if p.active[t] {
+		return nil
+	}
+	p.active[t] = true
+
+	return []*Alert{{
+		Alert:     t,
+		Timestamp: time.Now().UTC().Format(time.RFC3339Nano),
+		RouterIP:  p.routerIP,
+		PeerIP:    p.peerIP,
+		PeerASN:   p.peerASN,
+		Value:     value,
+		Threshold: threshold,
+	}}
+}
+
+// evaluate checks update rate and withdrawals of all peers accumulated since the previous evaluation
+func (m *monitor) evaluate() []*Alert {
+	m.Lock()
+	defer m.Unlock()
+	alerts := make([]*Alert, 0)
+	for _, p := range m.peers {
+		t := m.config.thresholds(p.peerIP)
+		alerts = append(alerts, p.check(UpdateRate, p.updates/m.config.Interval, t.MaxUpdateRate)...)
+		alerts = append(alerts, p.check(MassWithdrawal, p.withdrawals, t.MaxWithdrawals)...)
+		p.updates, p.withdrawals = 0, 0
+	}
+
+	return alerts
+}
+
+func (m *monitor) run() {
+	ticker := time.NewTicker(time.Duration(m.config.Interval) * time.Second)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			if err := m.raise(m.evaluate()); err != nil {
+				glog.Errorf("failed to publish alert with error: %+v", err)
+			}
+		case <-m.stop:
+			return
+		}
+	}
+}
+
+// raise publishes alerts and posts them to webhooks
+func (m *monitor) raise(alerts []*Alert) error {
+	for _, a := range alerts {
+		b, err := json.Marshal(a)
+		if err != nil {
+			return fmt.Errorf("failed to marshal alert with error: %+v", err)
+		}
+		glog.Warningf("%s alert for router %s peer %s, value %d exceeds threshold %d", a.Alert, a.RouterIP, a.PeerIP, a.Value, a.Threshold)
+		for _, url := range m.config.Webhooks {
+			go m.post(url, b)
+		}
+		if err := m.alerts.PublishMessage(bmp.AlertMsg, []byte(a.RouterIP+"_"+a.PeerIP), b); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (m *monitor) post(url string, b []byte) {
+	resp, err := m.client.Post(url, "application/json", bytes.NewReader(b))
+	if err != nil {
+		glog.Errorf("failed to post alert to webhook %s with error: %+v", url, err)
+		return
+	}
+	resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		glog.Errorf("webhook %s rejected alert with status %s", url, resp.Status)
+	}
+}
+
+// NewMonitor instantiates a new alerting Monitor, alerts are published to alerts publisher
+func NewMonitor(config *Config, alerts pub.Publisher) Monitor {
+	if config.Interval <= 0 {
+		config.Interval = defaultInterval
+	}
+	m := &monitor{
+		config: config,
+		alerts: alerts,
+		client: &http.Client{Timeout: 5 * time.Second},
+		peers:  make(map[string]*peer),
+		stop:   make(chan struct{}),
+	}
+	go m.run()
+
+	return m
+}
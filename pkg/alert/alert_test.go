@@ -0,0 +1,124 @@
+package alert
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/go-test/deep"
+	"github.com/sbezverk/gobmp/pkg/bmp"
+)
+
+// alertCollector is a Publisher collecting alerts
+type alertCollector struct {
+	alerts []*Alert
+}
+
+func (c *alertCollector) PublishMessage(msgType int, msgHash []byte, msg []byte) error {
+	a := &Alert{}
+	if err := json.Unmarshal(msg, a); err != nil {
+		return err
+	}
+	// Timestamp is not predictable
+	a.Timestamp = ""
+	c.alerts = append(c.alerts, a)
+
+	return nil
+}
+
+func (c *alertCollector) Stop() {}
+
+func prefixMsg(action, peer string, i int) []byte {
+	return []byte(fmt.Sprintf(`{"action":"%s","hash":"%d","router_ip":"192.0.2.1","peer_ip":"%s","peer_asn":65001,"prefix":"10.0.%d.0","prefix_len":24}`, action, i, peer, i))
+}
+
+func TestMonitor(t *testing.T) {
+	config := &Config{
+		Interval: 2,
+		Default:  &Thresholds{MaxPrefixes: 3, MaxUpdateRate: 1, MaxWithdrawals: 2},
+		Peers: map[string]*Thresholds{
+			"192.0.2.3": {MaxPrefixes: 100},
+		},
+	}
+	c := &alertCollector{}
+	m := NewMonitor(config, c).(*monitor)
+	defer m.Stop()
+	publish := func(msgType int, msg []byte) {
+		if err := m.PublishMessage(msgType, nil, msg); err != nil {
+			t.Fatalf("failed to publish message with error: %+v", err)
+		}
+	}
+	for i := 0; i < 5; i++ {
+		publish(bmp.UnicastPrefixV4Msg, prefixMsg("add", "192.0.2.2", i))
+		publish(bmp.UnicastPrefixV4Msg, prefixMsg("add", "192.0.2.3", i))
+	}
+	// Re-advertisement does not change the number of prefixes
+	publish(bmp.UnicastPrefixV4Msg, prefixMsg("add", "192.0.2.2", 0))
+	for i := 0; i < 3; i++ {
+		publish(bmp.UnicastPrefixV4Msg, prefixMsg("del", "192.0.2.2", i))
+	}
+	// Alert is raised again after the number of prefixes has dropped below the limit
+	publish(bmp.UnicastPrefixV4Msg, prefixMsg("add", "192.0.2.2", 10))
+	publish(bmp.UnicastPrefixV4Msg, prefixMsg("add", "192.0.2.2", 11))
+	if err := m.raise(m.evaluate()); err != nil {
+		t.Fatalf("failed to raise alerts with error: %+v", err)
+	}
+	// Counters are reset by evaluation
+	if err := m.raise(m.evaluate()); err != nil {
+		t.Fatalf("failed to raise alerts with error: %+v", err)
+	}
+	expect := []*Alert{
+		{Alert: MaxPrefixes, RouterIP: "192.0.2.1", PeerIP: "192.0.2.2", PeerASN: 65001, Value: 4, Threshold: 3},
+		{Alert: MaxPrefixes, RouterIP: "192.0.2.1", PeerIP: "192.0.2.2", PeerASN: 65001, Value: 4, Threshold: 3},
+		{Alert: UpdateRate, RouterIP: "192.0.2.1", PeerIP: "192.0.2.2", PeerASN: 65001, Value: 5, Threshold: 1},
+		{Alert: MassWithdrawal, RouterIP: "192.0.2.1", PeerIP: "192.0.2.2", PeerASN: 65001, Value: 3, Threshold: 2},
+	}
+	if !reflect.DeepEqual(c.alerts, expect) {
+		t.Errorf("alerts do not match expected")
+		t.Logf("Differences: %+v", deep.Equal(c.alerts, expect))
+	}
+}
+
+func TestWebhook(t *testing.T) {
+	received := make(chan *Alert, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		a := &Alert{}
+		if err := json.NewDecoder(r.Body).Decode(a); err != nil {
+			t.Errorf("failed to decode alert with error: %+v", err)
+		}
+		received <- a
+	}))
+	defer srv.Close()
+	file := filepath.Join(t.TempDir(), "alert.json")
+	if err := ioutil.WriteFile(file, []byte(`{"default":{"max_prefixes":1},"webhooks":["`+srv.URL+`"]}`), 0644); err != nil {
+		t.Fatalf("failed to write configuration with error: %+v", err)
+	}
+	config, err := LoadConfig(file)
+	if err != nil {
+		t.Fatalf("failed to load configuration with error: %+v", err)
+	}
+	if config.Interval != defaultInterval {
+		t.Errorf("expected default interval %d but got %d", defaultInterval, config.Interval)
+	}
+	m := NewMonitor(config, &alertCollector{})
+	defer m.Stop()
+	for i := 0; i < 2; i++ {
+		if err := m.PublishMessage(bmp.UnicastPrefixV6Msg, nil, prefixMsg("add", "2001:db8::1", i)); err != nil {
+			t.Fatalf("failed to publish message with error: %+v", err)
+		}
+	}
+	select {
+	case a := <-received:
+		if a.Alert != MaxPrefixes || a.PeerIP != "2001:db8::1" || a.Value != 2 {
+			t.Errorf("unexpected alert %+v", a)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("webhook has not received alert")
+	}
+}
@@ -0,0 +1,60 @@
+package alert
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+)
+
+// Thresholds defines alerting thresholds of a peer, 0 disables the corresponding alert.
+// MaxUpdateRate is the number of updates per second and MaxWithdrawals is the number of withdrawals
+// within the evaluation interval.
+type Thresholds struct {
+	MaxPrefixes    int `json:"max_prefixes,omitempty"`
+	MaxUpdateRate  int `json:"max_update_rate,omitempty"`
+	MaxWithdrawals int `json:"max_withdrawals,omitempty"`
+}
+
+// Config defines alerting configuration, Peers carries thresholds of specific peers by peer address
+// overriding Default thresholds, alerts are posted to all Webhooks.
+type Config struct {
+	Interval int                    `json:"interval_sec,omitempty"`
+	Default  *Thresholds            `json:"default,omitempty"`
+	Peers    map[string]*Thresholds `json:"peers,omitempty"`
+	Webhooks []string               `json:"webhooks,omitempty"`
+}
+
+// defaultInterval defines the default evaluation interval of update rate and withdrawals in seconds
+const defaultInterval = 10
+
+// thresholds returns thresholds of the peer
+func (c *Config) thresholds(peerIP string) *Thresholds {
+	if t, ok := c.Peers[peerIP]; ok {
+		return t
+	}
+	if c.Default != nil {
+		return c.Default
+	}
+
+	return &Thresholds{}
+}
+
+// LoadConfig reads alerting configuration from JSON file
+func LoadConfig(file string) (*Config, error) {
+	b, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	c := &Config{}
+	if err := json.Unmarshal(b, c); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal alert configuration %s with error: %+v", file, err)
+	}
+	if c.Interval < 0 {
+		return nil, fmt.Errorf("invalid evaluation interval %d", c.Interval)
+	}
+	if c.Interval == 0 {
+		c.Interval = defaultInterval
+	}
+
+	return c, nil
+}
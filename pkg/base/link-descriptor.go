@@ -62,14 +62,7 @@ func (l *LinkDescriptor) GetLinkIPv6NeighborAddr() net.IP {
 // GetLinkMTID returns Link Multi-Topology identifiers
 func (l *LinkDescriptor) GetLinkMTID() *MultiTopologyIdentifier {
 	if tlv, ok := l.LinkTLV[263]; ok {
-		m, err := UnmarshalMultiTopologyIdentifierTLV(tlv.Value)
-		if err != nil {
-			return nil
-		}
-		if m == nil {
-			return nil
-		}
-		return m[0]
+		return descriptorMTID(tlv.Value)
 	}
 
 	return nil
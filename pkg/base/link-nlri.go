@@ -106,12 +106,22 @@ func (l *LinkNLRI) GetRemoteASN() uint32 {
 
 // GetLocalIGPRouterID returns value of Local node IGP router id
 func (l *LinkNLRI) GetLocalIGPRouterID() string {
-	return l.LocalNode.GetIGPRouterID()
+	return l.LocalNode.GetProtocolIGPRouterID(l.ProtocolID)
 }
 
 // GetRemoteIGPRouterID returns value of Remote node IGP router id
 func (l *LinkNLRI) GetRemoteIGPRouterID() string {
-	return l.RemoteNode.GetIGPRouterID()
+	return l.RemoteNode.GetProtocolIGPRouterID(l.ProtocolID)
+}
+
+// IsLocalPseudonode returns true when Local node of the link is a pseudonode
+func (l *LinkNLRI) IsLocalPseudonode() bool {
+	return l.LocalNode.IsPseudonode()
+}
+
+// IsRemotePseudonode returns true when Remote node of the link is a pseudonode
+func (l *LinkNLRI) IsRemotePseudonode() bool {
+	return l.RemoteNode.IsPseudonode()
 }
 
 // UnmarshalLinkNLRI builds Link NLRI object
@@ -1,14 +1,42 @@
 package base
 
 import (
+	"fmt"
+	"strconv"
+
 	"github.com/golang/glog"
 	"github.com/sbezverk/tools"
 )
 
+// MSDTypes lists registered IGP MSD-Types as defined in
+// https://www.iana.org/assignments/igp-parameters/igp-parameters.xhtml#igp-msd-types
+var MSDTypes = map[uint8]string{
+	0:  "Reserved",
+	1:  "Base MPLS Imposition MSD",
+	2:  "ERLD-MSD",
+	41: "SRH Max SL",
+	42: "SRH Max End Pop",
+	44: "SRH Max H.Encaps",
+	45: "SRH Max End D",
+}
+
 // MSDTV defines MSD Type Value tuple
+// https://tools.ietf.org/html/rfc8814#section-3 (Node MSD TLV 266)
+// https://tools.ietf.org/html/rfc8814#section-4 (Link MSD TLV 267)
 type MSDTV struct {
-	Type  uint8 `json:"msd_type"`
-	Value uint8 `json:"msd_value"`
+	Type  uint8  `json:"msd_type"`
+	Value uint8  `json:"msd_value"`
+	Name  string `json:"msd_name,omitempty"`
+}
+
+// GetMSDTypeName returns a name of MSD type registered with IANA, for unregistered types
+// a generic name carrying the numeric value of the type is returned.
+func GetMSDTypeName(t uint8) string {
+	if n, ok := MSDTypes[t]; ok {
+		return n
+	}
+
+	return "Unknown MSD type " + strconv.Itoa(int(t))
 }
 
 // UnmarshalMSDTV builds slice of MSD Type Value tuples
@@ -16,6 +44,9 @@ func UnmarshalMSDTV(b []byte) ([]*MSDTV, error) {
 	if glog.V(6) {
 		glog.Infof("UnmarshalMSDTV Raw: %s", tools.MessageHex(b))
 	}
+	if len(b)%2 != 0 {
+		return nil, fmt.Errorf("invalid length %d of MSD TLV, must be a multiple of 2", len(b))
+	}
 	tvs := make([]*MSDTV, 0)
 	for p := 0; p < len(b); {
 		tv := &MSDTV{}
@@ -23,8 +54,19 @@ func UnmarshalMSDTV(b []byte) ([]*MSDTV, error) {
 		p++
 		tv.Value = b[p]
 		p++
+		tv.Name = GetMSDTypeName(tv.Type)
 		tvs = append(tvs, tv)
 	}
 
 	return tvs, nil
 }
+
+// MarshalMSDTV returns a wire format representation of a slice of MSD Type Value tuples
+func MarshalMSDTV(tvs []*MSDTV) []byte {
+	b := make([]byte, 0, len(tvs)*2)
+	for _, tv := range tvs {
+		b = append(b, tv.Type, tv.Value)
+	}
+
+	return b
+}
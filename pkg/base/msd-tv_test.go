@@ -0,0 +1,61 @@
+package base
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestUnmarshalMSDTV(t *testing.T) {
+	tests := []struct {
+		name   string
+		input  []byte
+		expect []*MSDTV
+		fail   bool
+	}{
+		{
+			name:  "base mpls imposition",
+			input: []byte{0x01, 0x0a},
+			expect: []*MSDTV{
+				{Type: 1, Value: 10, Name: "Base MPLS Imposition MSD"},
+			},
+		},
+		{
+			name:  "mpls and srh",
+			input: []byte{0x01, 0x0c, 0x29, 0x05, 0x2c, 0x02},
+			expect: []*MSDTV{
+				{Type: 1, Value: 12, Name: "Base MPLS Imposition MSD"},
+				{Type: 41, Value: 5, Name: "SRH Max SL"},
+				{Type: 44, Value: 2, Name: "SRH Max H.Encaps"},
+			},
+		},
+		{
+			name:  "unknown type",
+			input: []byte{0xc8, 0x01},
+			expect: []*MSDTV{
+				{Type: 200, Value: 1, Name: "Unknown MSD type 200"},
+			},
+		},
+		{
+			name:  "odd length",
+			input: []byte{0x01, 0x0a, 0x02},
+			fail:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := UnmarshalMSDTV(tt.input)
+			if err != nil && !tt.fail {
+				t.Fatalf("supposed to succeed but failed with error: %+v", err)
+			}
+			if err == nil && tt.fail {
+				t.Fatalf("supposed to fail but succeeded")
+			}
+			if err != nil {
+				return
+			}
+			if !reflect.DeepEqual(got, tt.expect) {
+				t.Errorf("expected %+v does not match computed %+v", tt.expect, got)
+			}
+		})
+	}
+}
@@ -2,6 +2,7 @@ package base
 
 import (
 	"encoding/binary"
+	"fmt"
 
 	"github.com/golang/glog"
 	"github.com/sbezverk/tools"
@@ -14,11 +15,16 @@ type MultiTopologyIdentifier struct {
 	MTID  uint16 `json:"mt_id"`
 }
 
-// UnmarshalMultiTopologyIdentifierTLV builds Multi Topology Identifier TLV object
+// UnmarshalMultiTopologyIdentifierTLV builds Multi Topology Identifier TLV object, MT-ID is 12 bits, O and A flags
+// are defined only in Node Attribute TLV of IS-IS, in link and prefix descriptors these bits are reserved.
+// https://tools.ietf.org/html/rfc7752#section-3.2.1.5
 func UnmarshalMultiTopologyIdentifierTLV(b []byte) ([]*MultiTopologyIdentifier, error) {
 	if glog.V(6) {
 		glog.Infof("MultiTopologyIdentifierTLV Raw: %s", tools.MessageHex(b))
 	}
+	if len(b) == 0 || len(b)%2 != 0 {
+		return nil, fmt.Errorf("invalid length %d of multi topology identifier tlv", len(b))
+	}
 	p := 0
 	// number of mt_id entries length / 2
 	mti := make([]*MultiTopologyIdentifier, len(b)/2)
@@ -34,3 +40,14 @@ func UnmarshalMultiTopologyIdentifierTLV(b []byte) ([]*MultiTopologyIdentifier,
 
 	return mti, nil
 }
+
+// descriptorMTID returns MT-ID of Multi Topology Identifier TLV of link or prefix descriptor, the TLV of a descriptor
+// carries exactly one MT-ID, the reserved bits are ignored.
+func descriptorMTID(b []byte) *MultiTopologyIdentifier {
+	m, err := UnmarshalMultiTopologyIdentifierTLV(b)
+	if err != nil || len(m) != 1 {
+		return nil
+	}
+
+	return &MultiTopologyIdentifier{MTID: m[0].MTID}
+}
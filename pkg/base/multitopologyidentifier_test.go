@@ -0,0 +1,49 @@
+package base
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestGetLinkMTID(t *testing.T) {
+	tests := []struct {
+		name   string
+		input  []byte
+		expect *MultiTopologyIdentifier
+	}{
+		{
+			name:   "ipv6 unicast topology",
+			input:  []byte{0x00, 0x02},
+			expect: &MultiTopologyIdentifier{MTID: 2},
+		},
+		{
+			name:   "reserved bits set",
+			input:  []byte{0xc0, 0x03},
+			expect: &MultiTopologyIdentifier{MTID: 3},
+		},
+		{
+			name:  "empty tlv",
+			input: []byte{},
+		},
+		{
+			name:  "odd length",
+			input: []byte{0x00, 0x02, 0x00},
+		},
+		{
+			name:  "more than one mt-id",
+			input: []byte{0x00, 0x02, 0x00, 0x03},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := &LinkDescriptor{
+				LinkTLV: map[uint16]TLV{
+					263: {Type: 263, Length: uint16(len(tt.input)), Value: tt.input},
+				},
+			}
+			if got := l.GetLinkMTID(); !reflect.DeepEqual(got, tt.expect) {
+				t.Errorf("expected %+v does not match computed %+v", tt.expect, got)
+			}
+		})
+	}
+}
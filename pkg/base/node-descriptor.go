@@ -3,10 +3,10 @@ package base
 import (
 	"encoding/binary"
 	"fmt"
-	"net"
 	"strconv"
 
 	"github.com/golang/glog"
+	"github.com/sbezverk/gobmp/pkg/addr"
 	"github.com/sbezverk/tools"
 )
 
@@ -47,13 +47,28 @@ func (nd *NodeDescriptor) GetOSPFAreaID() string {
 	return "err"
 }
 
-// GetIGPRouterID returns a value of Node Descriptor sub TLV IGP Router ID
+// GetIGPRouterID returns a value of Node Descriptor sub TLV IGP Router ID, IS-IS System ID and LAN ID are
+// formatted as dot separated groups of 2 bytes, OSPF pseudonode is formatted as DR's Router ID and DR's
+// interface address separated by "-".
 func (nd *NodeDescriptor) GetIGPRouterID() string {
+	return nd.GetProtocolIGPRouterID(0)
+}
+
+// GetProtocolIGPRouterID returns a value of Node Descriptor sub TLV IGP Router ID of the node of protocol id,
+// OSPFv3 pseudonode is formatted as DR's Router ID and DR's interface id separated by "-".
+// https://tools.ietf.org/html/rfc7752#section-3.2.1.4
+func (nd *NodeDescriptor) GetProtocolIGPRouterID(id ProtoID) string {
 	var s string
 	i := 0
 	if tlv, ok := nd.SubTLV[515]; ok {
 		if tlv.Length == 4 {
-			return net.IP(tlv.Value).To4().String()
+			return addr.IPv4(tlv.Value)
+		}
+		if tlv.Length == 8 {
+			if id == OSPFv3 {
+				return fmt.Sprintf("%s-%d", addr.IPv4(tlv.Value[:4]), binary.BigEndian.Uint32(tlv.Value[4:]))
+			}
+			return addr.IPv4(tlv.Value[:4]) + "-" + addr.IPv4(tlv.Value[4:])
 		}
 		for p := 0; p < len(tlv.Value); p++ {
 			s += fmt.Sprintf("%02x", tlv.Value[p])
@@ -69,6 +84,15 @@ func (nd *NodeDescriptor) GetIGPRouterID() string {
 	return s
 }
 
+// IsPseudonode returns true when IGP Router ID identifies a pseudonode, IS-IS LAN ID of 7 bytes or OSPF
+// Designated Router's Router ID and interface of 8 bytes.
+func (nd *NodeDescriptor) IsPseudonode() bool {
+	if tlv, ok := nd.SubTLV[515]; ok {
+		return tlv.Length == 7 || tlv.Length == 8
+	}
+	return false
+}
+
 //GetBGPRouterID returns BGP Router ID found in Node Descriptor sub tlv
 func (nd *NodeDescriptor) GetBGPRouterID() []byte {
 	if tlv, ok := nd.SubTLV[516]; ok {
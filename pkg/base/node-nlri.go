@@ -43,7 +43,12 @@ func (n *NodeNLRI) GetNodeLSID() uint32 {
 
 // GetNodeIGPRouterID returns a value of Node Descriptor TLV IGP Router ID
 func (n *NodeNLRI) GetNodeIGPRouterID() string {
-	return n.LocalNode.GetIGPRouterID()
+	return n.LocalNode.GetProtocolIGPRouterID(n.ProtocolID)
+}
+
+// IsPseudonode returns true when the node is a pseudonode
+func (n *NodeNLRI) IsPseudonode() bool {
+	return n.LocalNode.IsPseudonode()
 }
 
 // GetNodeASN returns Autonomous System Number used to uniqely identify BGP-LS domain
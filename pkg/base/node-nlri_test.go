@@ -8,9 +8,10 @@ import (
 
 func TestGetIdentifier(t *testing.T) {
 	tests := []struct {
-		name     string
-		node     *NodeNLRI
-		expected string
+		name       string
+		node       *NodeNLRI
+		expected   string
+		pseudonode bool
 	}{
 		{
 			name: "8 bytes all zeros",
@@ -87,6 +88,57 @@ func TestGetIdentifier(t *testing.T) {
 			},
 			expected: "0.1.255.1",
 		},
+		{
+			name: "isis pseudonode",
+			node: &NodeNLRI{
+				ProtocolID: ISISL2,
+				LocalNode: &NodeDescriptor{
+					SubTLV: map[uint16]TLV{
+						515: {
+							Type:   515,
+							Length: 7,
+							Value:  []byte{0x15, 0x14, 0x13, 0x12, 0x11, 0x10, 0x02},
+						},
+					},
+				},
+			},
+			expected:   "1514.1312.1110.02",
+			pseudonode: true,
+		},
+		{
+			name: "ospfv2 pseudonode",
+			node: &NodeNLRI{
+				ProtocolID: OSPFv2,
+				LocalNode: &NodeDescriptor{
+					SubTLV: map[uint16]TLV{
+						515: {
+							Type:   515,
+							Length: 8,
+							Value:  []byte{0xc0, 0x00, 0x02, 0x01, 0x0a, 0x01, 0x01, 0x01},
+						},
+					},
+				},
+			},
+			expected:   "192.0.2.1-10.1.1.1",
+			pseudonode: true,
+		},
+		{
+			name: "ospfv3 pseudonode",
+			node: &NodeNLRI{
+				ProtocolID: OSPFv3,
+				LocalNode: &NodeDescriptor{
+					SubTLV: map[uint16]TLV{
+						515: {
+							Type:   515,
+							Length: 8,
+							Value:  []byte{0xc0, 0x00, 0x02, 0x01, 0x00, 0x00, 0x00, 0x05},
+						},
+					},
+				},
+			},
+			expected:   "192.0.2.1-5",
+			pseudonode: true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -94,6 +146,9 @@ func TestGetIdentifier(t *testing.T) {
 			if strings.Compare(got, tt.expected) != 0 {
 				t.Errorf("failed, expected %s got %s", tt.expected, got)
 			}
+			if p := tt.node.IsPseudonode(); p != tt.pseudonode {
+				t.Errorf("failed, expected pseudonode %t got %t", tt.pseudonode, p)
+			}
 		})
 	}
 }
@@ -14,14 +14,7 @@ type PrefixDescriptor struct {
 // GetPrefixMTID returns Multi-Topology identifiers
 func (pd *PrefixDescriptor) GetPrefixMTID() *MultiTopologyIdentifier {
 	if tlv, ok := pd.PrefixTLV[263]; ok {
-		m, err := UnmarshalMultiTopologyIdentifierTLV(tlv.Value)
-		if err != nil {
-			return nil
-		}
-		if m == nil {
-			return nil
-		}
-		return m[0]
+		return descriptorMTID(tlv.Value)
 	}
 
 	return nil
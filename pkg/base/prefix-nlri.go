@@ -86,7 +86,7 @@ func (p *PrefixNLRI) GetPrefixLSID() uint32 {
 
 // GetLocalIGPRouterID returns value of Local node IGP router id
 func (p *PrefixNLRI) GetLocalIGPRouterID() string {
-	return p.LocalNode.GetIGPRouterID()
+	return p.LocalNode.GetProtocolIGPRouterID(p.ProtocolID)
 }
 
 // GetLocalASN returns value of Local Node's ASN
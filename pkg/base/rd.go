@@ -3,9 +3,9 @@ package base
 import (
 	"encoding/binary"
 	"fmt"
-	"net"
 
 	"github.com/golang/glog"
+	"github.com/sbezverk/gobmp/pkg/addr"
 	"github.com/sbezverk/tools"
 )
 
@@ -40,7 +40,7 @@ func (rd *RD) String() string {
 	case 0:
 		s += fmt.Sprintf("%d:%d", binary.BigEndian.Uint16(rd.Value[0:2]), binary.BigEndian.Uint32(rd.Value[2:]))
 	case 1:
-		s += fmt.Sprintf("%s:%d", net.IP(rd.Value[0:4]).To4().String(), binary.BigEndian.Uint16(rd.Value[4:]))
+		s += fmt.Sprintf("%s:%d", addr.IPv4(rd.Value[0:4]), binary.BigEndian.Uint16(rd.Value[4:]))
 	case 2:
 		s += fmt.Sprintf("%d:%d", binary.BigEndian.Uint32(rd.Value[0:4]), binary.BigEndian.Uint16(rd.Value[4:]))
 	}
@@ -2,6 +2,7 @@ package base
 
 import (
 	"encoding/binary"
+	"encoding/json"
 )
 
 // TLV defines generic Typle Length Value element
@@ -18,6 +19,38 @@ type SubTLV struct {
 	Value  []byte `json:"sub_tlv_value,omitempty"`
 }
 
+// UnmarshalJSON restores TLV from JSON, Length is not carried in JSON and is derived from the Value
+func (t *TLV) UnmarshalJSON(b []byte) error {
+	result := struct {
+		Type  uint16 `json:"tlv_type,omitempty"`
+		Value []byte `json:"tlv_value,omitempty"`
+	}{}
+	if err := json.Unmarshal(b, &result); err != nil {
+		return err
+	}
+	t.Type = result.Type
+	t.Length = uint16(len(result.Value))
+	t.Value = result.Value
+
+	return nil
+}
+
+// UnmarshalJSON restores SubTLV from JSON, Length is not carried in JSON and is derived from the Value
+func (stlv *SubTLV) UnmarshalJSON(b []byte) error {
+	result := struct {
+		Type  uint16 `json:"sub_tlv_type"`
+		Value []byte `json:"sub_tlv_value,omitempty"`
+	}{}
+	if err := json.Unmarshal(b, &result); err != nil {
+		return err
+	}
+	stlv.Type = result.Type
+	stlv.Length = uint16(len(result.Value))
+	stlv.Value = result.Value
+
+	return nil
+}
+
 // UnmarshalTLV builds a map of TLVs elements
 func UnmarshalTLV(b []byte) (map[uint16]TLV, error) {
 	stlvs := make(map[uint16]TLV)
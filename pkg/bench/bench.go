@@ -0,0 +1,131 @@
+package bench
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io/ioutil"
+	"runtime"
+	"sync/atomic"
+	"time"
+
+	"github.com/sbezverk/gobmp/pkg/bmp"
+	"github.com/sbezverk/gobmp/pkg/message"
+	"github.com/sbezverk/gobmp/pkg/parser"
+)
+
+// Result defines the outcome of a benchmark run over a set of BMP messages
+type Result struct {
+	// Messages is the number of processed BMP messages
+	Messages int
+	// Bytes is the total length of processed BMP messages
+	Bytes int
+	// Published is the number of messages published by the producer, 0 when only parsing is measured
+	Published int64
+	Duration  time.Duration
+	// Allocs is the number of heap allocations done during the run
+	Allocs uint64
+	// AllocBytes is the number of bytes allocated on the heap during the run
+	AllocBytes uint64
+}
+
+// MessagesPerSec returns the rate of processed BMP messages
+func (r *Result) MessagesPerSec() float64 {
+	return float64(r.Messages) / r.Duration.Seconds()
+}
+
+// PublishedPerSec returns the rate of published messages
+func (r *Result) PublishedPerSec() float64 {
+	return float64(r.Published) / r.Duration.Seconds()
+}
+
+// AllocsPerMessage returns the number of heap allocations per processed BMP message
+func (r *Result) AllocsPerMessage() float64 {
+	return float64(r.Allocs) / float64(r.Messages)
+}
+
+// AllocBytesPerMessage returns the number of bytes allocated on the heap per processed BMP message
+func (r *Result) AllocBytesPerMessage() float64 {
+	return float64(r.AllocBytes) / float64(r.Messages)
+}
+
+func (r *Result) String() string {
+	return fmt.Sprintf("%d messages (%d bytes) in %v: %.0f msgs/sec, %d published (%.0f/sec), %.1f allocs/msg, %.0f B/msg",
+		r.Messages, r.Bytes, r.Duration, r.MessagesPerSec(), r.Published, r.PublishedPerSec(), r.AllocsPerMessage(), r.AllocBytesPerMessage())
+}
+
+// Split returns BMP messages of the stream, as they are read from the router's connection
+func Split(b []byte) ([][]byte, error) {
+	msgs := make([][]byte, 0)
+	for p := 0; p < len(b); {
+		if p+bmp.CommonHeaderLength > len(b) {
+			return nil, fmt.Errorf("truncated common header at offset %d", p)
+		}
+		l := int(binary.BigEndian.Uint32(b[p+1 : p+5]))
+		if l < bmp.CommonHeaderLength || p+l > len(b) {
+			return nil, fmt.Errorf("invalid length %d of message at offset %d", l, p)
+		}
+		msgs = append(msgs, b[p:p+l])
+		p += l
+	}
+
+	return msgs, nil
+}
+
+// LoadCaptures returns BMP messages of capture files with raw BMP messages, as written by --capture-dir
+func LoadCaptures(files ...string) ([][]byte, error) {
+	msgs := make([][]byte, 0)
+	for _, f := range files {
+		b, err := ioutil.ReadFile(f)
+		if err != nil {
+			return nil, err
+		}
+		m, err := Split(b)
+		if err != nil {
+			return nil, fmt.Errorf("failed to split capture %s into BMP messages with error: %+v", f, err)
+		}
+		msgs = append(msgs, m...)
+	}
+
+	return msgs, nil
+}
+
+// counter is a Publisher counting published messages
+type counter struct {
+	published int64
+}
+
+func (c *counter) PublishMessage(msgType int, msgHash []byte, msg []byte) error {
+	atomic.AddInt64(&c.published, 1)
+	return nil
+}
+
+func (c *counter) Stop() {}
+
+// Run parses BMP messages one by one, as gobmp does for messages read from router's connection, when produce
+// is true, parsed messages are also passed to the producer and published to a publisher discarding them.
+func Run(msgs [][]byte, produce bool) *Result {
+	c := &counter{}
+	p := message.NewProducer(c, false)
+	r := &Result{Messages: len(msgs)}
+	var before, after runtime.MemStats
+	runtime.GC()
+	runtime.ReadMemStats(&before)
+	start := time.Now()
+	for _, b := range msgs {
+		for _, msg := range parser.Parse(b) {
+			if produce {
+				p.Produce(msg)
+			}
+		}
+	}
+	r.Duration = time.Since(start)
+	runtime.ReadMemStats(&after)
+	for _, b := range msgs {
+		r.Bytes += len(b)
+	}
+	r.Published = atomic.LoadInt64(&c.published)
+	r.Allocs = after.Mallocs - before.Mallocs
+	r.AllocBytes = after.TotalAlloc - before.TotalAlloc
+
+	return r
+}
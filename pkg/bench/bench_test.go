@@ -0,0 +1,132 @@
+package bench
+
+import (
+	"bytes"
+	"flag"
+	"strings"
+	"testing"
+
+	"github.com/sbezverk/gobmp/pkg/parser"
+)
+
+var captures = flag.String("captures", "", "comma separated list of capture files to benchmark instead of the synthetic full table")
+
+func TestGenerate(t *testing.T) {
+	var b1, b2 bytes.Buffer
+	n, err := Generate(&b1, 1000, 200)
+	if err != nil {
+		t.Fatalf("failed to generate table with error: %+v", err)
+	}
+	if _, err := Generate(&b2, 1000, 200); err != nil {
+		t.Fatalf("failed to generate table with error: %+v", err)
+	}
+	if !bytes.Equal(b1.Bytes(), b2.Bytes()) {
+		t.Fatalf("generated tables are not identical")
+	}
+	msgs, err := Split(b1.Bytes())
+	if err != nil {
+		t.Fatalf("failed to split table with error: %+v", err)
+	}
+	if len(msgs) != n {
+		t.Fatalf("expected %d messages, got %d", n, len(msgs))
+	}
+	// Initiation and Termination are not returned by the parser
+	parsed := 0
+	for _, m := range msgs {
+		parsed += len(parser.Parse(m))
+	}
+	if parsed != n-2 {
+		t.Fatalf("expected %d parsed messages, got %d", n-2, parsed)
+	}
+	r := Run(msgs, true)
+	// A message per route and per peer
+	if r.Published != 1202 {
+		t.Fatalf("expected 1202 published messages, got %d", r.Published)
+	}
+}
+
+func TestSplit(t *testing.T) {
+	tests := []struct {
+		name  string
+		input []byte
+		msgs  int
+		fail  bool
+	}{
+		{
+			name:  "two messages",
+			input: []byte{3, 0, 0, 0, 6, 4, 3, 0, 0, 0, 6, 5},
+			msgs:  2,
+		},
+		{
+			name:  "truncated message",
+			input: []byte{3, 0, 0, 0, 6, 4, 3, 0, 0, 0, 8, 5},
+			fail:  true,
+		},
+		{
+			name:  "truncated header",
+			input: []byte{3, 0, 0, 0, 6, 4, 3, 0},
+			fail:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			msgs, err := Split(tt.input)
+			if err != nil && !tt.fail {
+				t.Fatalf("expected to succeed but failed with error: %+v", err)
+			}
+			if err == nil && tt.fail {
+				t.Fatalf("expected to fail but succeeded")
+			}
+			if len(msgs) != tt.msgs {
+				t.Fatalf("expected %d messages, got %d", tt.msgs, len(msgs))
+			}
+		})
+	}
+}
+
+// fullTable returns BMP messages of capture files passed with -captures, or of the synthetic full table
+func fullTable(b *testing.B) [][]byte {
+	if *captures != "" {
+		msgs, err := LoadCaptures(strings.Split(*captures, ",")...)
+		if err != nil {
+			b.Fatalf("failed to load captures with error: %+v", err)
+		}
+		return msgs
+	}
+	var buf bytes.Buffer
+	if _, err := Generate(&buf, FullTableIPv4, FullTableIPv6); err != nil {
+		b.Fatalf("failed to generate full table with error: %+v", err)
+	}
+	msgs, err := Split(buf.Bytes())
+	if err != nil {
+		b.Fatalf("failed to split full table with error: %+v", err)
+	}
+
+	return msgs
+}
+
+func benchmarkFullTable(b *testing.B, produce bool) {
+	msgs := fullTable(b)
+	b.ReportAllocs()
+	b.ResetTimer()
+	var r *Result
+	for i := 0; i < b.N; i++ {
+		r = Run(msgs, produce)
+	}
+	b.StopTimer()
+	b.ReportMetric(r.MessagesPerSec(), "msgs/s")
+	b.ReportMetric(r.AllocsPerMessage(), "allocs/msg")
+	if produce {
+		b.ReportMetric(r.PublishedPerSec(), "published/s")
+	}
+}
+
+// BenchmarkParseFullTable measures parsing of the full table, run with "go test -bench . -benchtime 3x ./pkg/bench"
+func BenchmarkParseFullTable(b *testing.B) {
+	benchmarkFullTable(b, false)
+}
+
+// BenchmarkProduceFullTable measures parsing and producing of the full table
+func BenchmarkProduceFullTable(b *testing.B) {
+	benchmarkFullTable(b, true)
+}
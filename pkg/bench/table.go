@@ -0,0 +1,268 @@
+package bench
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math/rand"
+	"net"
+	"time"
+
+	"github.com/sbezverk/gobmp/pkg/bgp"
+	"github.com/sbezverk/gobmp/pkg/bmp"
+)
+
+const (
+	// FullTableIPv4 is the number of IPv4 routes of the synthetic full table
+	FullTableIPv4 = 900000
+	// FullTableIPv6 is the number of IPv6 routes of the synthetic full table
+	FullTableIPv6 = 150000
+	// seed makes generated tables identical between runs and releases
+	seed = 20261015
+	// maxGroup is the maximum number of prefixes sharing path attributes in a single BGP Update
+	maxGroup = 5
+	localAS  = 64500
+	peerAS   = 64510
+)
+
+var (
+	// timestamp is used in all per-peer headers, so generated tables are byte for byte reproducible
+	timestamp = time.Unix(1760486400, 0)
+	routerID  = net.IPv4(192, 0, 2, 1).To4()
+	peerIPv4  = net.IPv4(192, 0, 2, 2).To4()
+	routerV6  = net.ParseIP("2001:db8::1")
+	peerIPv6  = net.ParseIP("2001:db8::2")
+	// transitASes are the ASes used to build AS_PATH of generated routes
+	transitASes = []uint32{174, 701, 1299, 2914, 3257, 3356, 3491, 6453, 6461, 6762, 6830, 7018, 9002, 12956, 15169, 16509, 20940, 32934, 64496, 131072, 262144, 396982}
+)
+
+// Generate writes to w a synthetic full table as BMP messages: Initiation, Peer Up of IPv4 peer, Route Monitoring
+// messages with ipv4 routes and End-of-RIB, Peer Up of IPv6 peer, Route Monitoring messages with ipv6 routes
+// and End-of-RIB, and Termination. Prefix lengths, AS_PATHs, MED and communities vary as in the Internet table,
+// for the same arguments the output is always the same. It returns the number of written BMP messages.
+func Generate(w io.Writer, ipv4, ipv6 int) (int, error) {
+	g := &generator{
+		w:    w,
+		rand: rand.New(rand.NewSource(seed)),
+	}
+	g.write(initiationMessage())
+	g.write(peerUpMessage(peerIPv4, routerID, 1))
+	g.table(peerIPv4, ipv4, g.ipv4Prefixes())
+	g.write(peerUpMessage(peerIPv6, routerV6, 2))
+	g.table(peerIPv6, ipv6, g.ipv6Prefixes())
+	g.write(bmp.SerializeMessage(bmp.TerminationMsg, nil, bmp.SerializeTLV([]bmp.InformationalTLV{
+		{InformationType: 1, Information: []byte{0, 0}},
+	})))
+
+	return g.msgs, g.err
+}
+
+// generator writes BMP messages of the table, after the first failure all writes are skipped
+type generator struct {
+	w    io.Writer
+	rand *rand.Rand
+	msgs int
+	err  error
+}
+
+func (g *generator) write(b []byte, err error) {
+	if g.err != nil {
+		return
+	}
+	if err != nil {
+		g.err = err
+		return
+	}
+	if _, g.err = g.w.Write(b); g.err == nil {
+		g.msgs++
+	}
+}
+
+// ipv4Prefixes returns a generator of non overlapping IPv4 prefixes, most of them are /24
+func (g *generator) ipv4Prefixes() func() []byte {
+	next := uint64(1 << 24)
+	return func() []byte {
+		l := 24
+		switch r := g.rand.Intn(100); {
+		case r < 8:
+			l = 23
+		case r < 18:
+			l = 22
+		case r < 22:
+			l = 21
+		case r < 26:
+			l = 20
+		case r < 30:
+			l = 16 + g.rand.Intn(4)
+		}
+		size := uint64(1) << uint(32-l)
+		next = (next + size - 1) &^ (size - 1)
+		if next+size > 224<<24 {
+			// Wrap around unicast space, prefixes overlap only for tables much bigger than the Internet's
+			next = 1 << 24
+		}
+		b := make([]byte, 4)
+		binary.BigEndian.PutUint32(b, uint32(next))
+		next += size
+		return append([]byte{byte(l)}, b[:(l+7)/8]...)
+	}
+}
+
+// ipv6Prefixes returns a generator of non overlapping IPv6 prefixes, most of them are /48
+func (g *generator) ipv6Prefixes() func() []byte {
+	next := uint64(0x24) << 56
+	return func() []byte {
+		l := 48
+		switch r := g.rand.Intn(100); {
+		case r < 15:
+			l = 32
+		case r < 25:
+			l = 44
+		case r < 35:
+			l = 40
+		case r < 40:
+			l = 29
+		}
+		size := uint64(1) << uint(64-l)
+		next = (next + size - 1) &^ (size - 1)
+		b := make([]byte, 8)
+		binary.BigEndian.PutUint64(b, next)
+		next += size
+		return append([]byte{byte(l)}, b[:(l+7)/8]...)
+	}
+}
+
+// table writes Route Monitoring messages with n routes of the peer followed by End-of-RIB
+func (g *generator) table(peer net.IP, n int, prefix func() []byte) {
+	ipv6 := peer.To4() == nil
+	for n > 0 {
+		group := 1 + g.rand.Intn(maxGroup)
+		if group > n {
+			group = n
+		}
+		nlri := make([]byte, 0, group*9)
+		for i := 0; i < group; i++ {
+			nlri = append(nlri, prefix()...)
+		}
+		n -= group
+		up := &bgp.Update{PathAttributes: g.pathAttributes()}
+		if ipv6 {
+			// AFI 2, SAFI 1, Next Hop of the peer and Reserved byte
+			mp := append([]byte{0, 2, 1, 16}, peer.To16()...)
+			mp = append(mp, 0)
+			up.PathAttributes = append(up.PathAttributes, bgp.PathAttribute{AttributeTypeFlags: 0x80, AttributeType: bgp.MP_REACH_NLRI, Attribute: append(mp, nlri...)})
+		} else {
+			up.PathAttributes = append(up.PathAttributes, bgp.PathAttribute{AttributeTypeFlags: 0x40, AttributeType: 3, Attribute: []byte(peer)})
+			up.NLRI = nlri
+		}
+		g.write(routeMonitorMessage(peer, up))
+	}
+	eor := &bgp.Update{}
+	if ipv6 {
+		eor.PathAttributes = []bgp.PathAttribute{{AttributeTypeFlags: 0x80, AttributeType: bgp.MP_UNREACH_NLRI, Attribute: []byte{0, 2, 1}}}
+	}
+	g.write(routeMonitorMessage(peer, eor))
+}
+
+// pathAttributes returns ORIGIN, AS_PATH of 1 to 7 ASes, optional MED and COMMUNITY attributes of a group of routes
+func (g *generator) pathAttributes() []bgp.PathAttribute {
+	origin := byte(0)
+	if g.rand.Intn(10) == 0 {
+		origin = 2
+	}
+	hops := g.rand.Intn(7)
+	asPath := make([]byte, 2+4*(hops+1))
+	// AS_SEQUENCE of 4 bytes ASes starting with the peer's AS
+	asPath[0] = 2
+	asPath[1] = byte(hops + 1)
+	binary.BigEndian.PutUint32(asPath[2:], peerAS)
+	for i := 1; i <= hops; i++ {
+		binary.BigEndian.PutUint32(asPath[2+4*i:], transitASes[g.rand.Intn(len(transitASes))])
+	}
+	attrs := []bgp.PathAttribute{
+		{AttributeTypeFlags: 0x40, AttributeType: 1, Attribute: []byte{origin}},
+		{AttributeTypeFlags: 0x40, AttributeType: 2, Attribute: asPath},
+	}
+	if g.rand.Intn(3) == 0 {
+		med := make([]byte, 4)
+		binary.BigEndian.PutUint32(med, uint32(g.rand.Intn(1000)))
+		attrs = append(attrs, bgp.PathAttribute{AttributeTypeFlags: 0x80, AttributeType: 4, Attribute: med})
+	}
+	if c := g.rand.Intn(5); c != 0 {
+		comm := make([]byte, 4*c)
+		for i := 0; i < c; i++ {
+			binary.BigEndian.PutUint16(comm[4*i:], peerAS)
+			binary.BigEndian.PutUint16(comm[4*i+2:], uint16(g.rand.Intn(3000)))
+		}
+		attrs = append(attrs, bgp.PathAttribute{AttributeTypeFlags: 0xc0, AttributeType: 8, Attribute: comm})
+	}
+
+	return attrs
+}
+
+func openMessage(as uint32, bgpID net.IP, afi uint16) *bgp.OpenMessage {
+	as4 := make([]byte, 4)
+	binary.BigEndian.PutUint32(as4, as)
+	mp := make([]byte, 4)
+	binary.BigEndian.PutUint16(mp, afi)
+	mp[3] = 1
+	return &bgp.OpenMessage{
+		MyAS:     uint16(as),
+		HoldTime: 180,
+		BGPID:    bgpID,
+		Capabilities: bgp.Capability{
+			// Multiprotocol Extensions Unicast of the session's address family
+			1: []*bgp.CapabilityData{{Value: mp}},
+			// Route Refresh
+			2: []*bgp.CapabilityData{{Value: []byte{}}},
+			// 4-octet AS number
+			65: []*bgp.CapabilityData{{Value: as4}},
+		},
+	}
+}
+
+func initiationMessage() ([]byte, error) {
+	im := &bmp.InitiationMessage{
+		TLV: []bmp.InformationalTLV{
+			{InformationType: 1, Information: []byte("gobmp synthetic full table")},
+			{InformationType: 2, Information: []byte("gobmp-bench")},
+		},
+	}
+	b, err := im.Serialize()
+	if err != nil {
+		return nil, err
+	}
+
+	return bmp.SerializeMessage(bmp.InitiationMsg, nil, b)
+}
+
+func peerUpMessage(peer net.IP, local net.IP, afi uint16) ([]byte, error) {
+	pu := &bmp.PeerUpMessage{
+		LocalAddress: make([]byte, 16),
+		LocalPort:    179,
+		RemotePort:   40179,
+		SentOpen:     openMessage(localAS, routerID, afi),
+		ReceivedOpen: openMessage(peerAS, peerIPv4, afi),
+	}
+	if l := local.To4(); l != nil {
+		copy(pu.LocalAddress[12:], l)
+	} else {
+		copy(pu.LocalAddress, local.To16())
+	}
+	b, err := pu.Serialize()
+	if err != nil {
+		return nil, fmt.Errorf("failed to serialize Peer Up message with error: %+v", err)
+	}
+
+	return bmp.SerializeMessage(bmp.PeerUpMsg, bmp.NewPerPeerHeader(peer, peerAS, peerIPv4, timestamp, false), b)
+}
+
+func routeMonitorMessage(peer net.IP, up *bgp.Update) ([]byte, error) {
+	rm := &bmp.RouteMonitor{Update: up}
+	b, err := rm.Serialize()
+	if err != nil {
+		return nil, fmt.Errorf("failed to serialize Route Monitoring message with error: %+v", err)
+	}
+
+	return bmp.SerializeMessage(bmp.RouteMonitorMsg, bmp.NewPerPeerHeader(peer, peerAS, peerIPv4, timestamp, false), b)
+}
@@ -0,0 +1,47 @@
+package bgp
+
+import (
+	"encoding/binary"
+
+	"github.com/sbezverk/gobmp/pkg/addr"
+)
+
+// asTrans defines AS_TRANS, 2 bytes AS speakers use it in place of 4 bytes ASes, RFC 6793
+const asTrans = 23456
+
+// decodeAggregator returns AS and IP address of the aggregating speaker carried in AGGREGATOR or AS4_AGGREGATOR
+// attribute, AS is 2 bytes long when the attribute is 6 bytes long and 4 bytes long when it is 8 bytes long.
+// The last return value is false when the length of the attribute is invalid.
+func decodeAggregator(b []byte) (uint32, string, bool) {
+	switch len(b) {
+	case 6:
+		return uint32(binary.BigEndian.Uint16(b[:2])), addr.IPv4(b[2:]), true
+	case 8:
+		return binary.BigEndian.Uint32(b[:4]), addr.IPv4(b[4:]), true
+	}
+
+	return 0, "", false
+}
+
+// aggregator sets AS and IP address of the aggregating speaker, AGGREGATOR received from 2 bytes AS speaker
+// carrying AS_TRANS is replaced by AS4_AGGREGATOR as per RFC 6793 Section 4.2.3. The return value is false
+// when AS4_AGGREGATOR and AS4_PATH must be ignored, because AGGREGATOR of 2 bytes AS speaker carries an AS
+// other than AS_TRANS.
+func (ba *BaseAttributes) aggregator() bool {
+	as, addr, ok := decodeAggregator(ba.Aggregator)
+	if !ok {
+		return true
+	}
+	ba.AggregatorAS, ba.AggregatorAddress = as, addr
+	if len(ba.Aggregator) != 6 || len(ba.AS4Aggregator) == 0 {
+		return true
+	}
+	if as != asTrans {
+		return false
+	}
+	if len(ba.AS4Aggregator) == 8 {
+		ba.AggregatorAS, ba.AggregatorAddress, _ = decodeAggregator(ba.AS4Aggregator)
+	}
+
+	return true
+}
@@ -0,0 +1,66 @@
+package bgp
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestAggregator(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   []byte
+		as      uint32
+		address string
+		asPath  []uint32
+	}{
+		{
+			name: "4 bytes AS aggregator",
+			// ATOMIC_AGGREGATE, AGGREGATOR 65001 192.0.2.1
+			input:   []byte{0x40, 0x06, 0x00, 0xc0, 0x07, 0x08, 0x00, 0x00, 0xfd, 0xe9, 0xc0, 0x00, 0x02, 0x01},
+			as:      65001,
+			address: "192.0.2.1",
+		},
+		{
+			name: "AS_TRANS aggregator with AS4_AGGREGATOR",
+			// AS_PATH: AS_SEQUENCE 65001 23456, AGGREGATOR 23456 192.0.2.2
+			// AS4_PATH: AS_SEQUENCE 4200000001, AS4_AGGREGATOR 4200000001 192.0.2.2
+			input: []byte{0x40, 0x02, 0x06, 0x02, 0x02, 0xfd, 0xe9, 0x5b, 0xa0,
+				0xc0, 0x07, 0x06, 0x5b, 0xa0, 0xc0, 0x00, 0x02, 0x02,
+				0xc0, 0x11, 0x06, 0x02, 0x01, 0xfa, 0x56, 0xea, 0x01,
+				0xc0, 0x12, 0x08, 0xfa, 0x56, 0xea, 0x01, 0xc0, 0x00, 0x02, 0x02},
+			as:      4200000001,
+			address: "192.0.2.2",
+			asPath:  []uint32{65001, 4200000001},
+		},
+		{
+			name: "2 bytes AS aggregator ignores AS4 attributes",
+			// AS_PATH: AS_SEQUENCE 65001 23456, AGGREGATOR 65010 192.0.2.2
+			// AS4_PATH: AS_SEQUENCE 4200000001, AS4_AGGREGATOR 4200000001 192.0.2.2
+			input: []byte{0x40, 0x02, 0x06, 0x02, 0x02, 0xfd, 0xe9, 0x5b, 0xa0,
+				0xc0, 0x07, 0x06, 0xfd, 0xf2, 0xc0, 0x00, 0x02, 0x02,
+				0xc0, 0x11, 0x06, 0x02, 0x01, 0xfa, 0x56, 0xea, 0x01,
+				0xc0, 0x12, 0x08, 0xfa, 0x56, 0xea, 0x01, 0xc0, 0x00, 0x02, 0x02},
+			as:      65010,
+			address: "192.0.2.2",
+			asPath:  []uint32{65001, 23456},
+		},
+		{
+			name:  "invalid aggregator length",
+			input: []byte{0xc0, 0x07, 0x05, 0xfd, 0xf2, 0xc0, 0x00, 0x02},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := UnmarshalBGPBaseAttributes(tt.input)
+			if err != nil {
+				t.Fatalf("expected to succeed but failed with error: %+v", err)
+			}
+			if got.AggregatorAS != tt.as || got.AggregatorAddress != tt.address {
+				t.Errorf("expected aggregator %d %s does not match actual aggregator %d %s", tt.as, tt.address, got.AggregatorAS, got.AggregatorAddress)
+			}
+			if len(tt.asPath) != 0 && !reflect.DeepEqual(got.ASPath, tt.asPath) {
+				t.Errorf("expected as path %+v does not match actual as path %+v", tt.asPath, got.ASPath)
+			}
+		})
+	}
+}
@@ -0,0 +1,162 @@
+package bgp
+
+import (
+	"encoding/binary"
+	"fmt"
+)
+
+// AS_PATH segment types, RFC 4271 and RFC 5065
+const (
+	ASSet            = 1
+	ASSequence       = 2
+	ASConfedSequence = 3
+	ASConfedSet      = 4
+)
+
+// ASPathSegment defines a single segment of AS_PATH or AS4_PATH attribute
+type ASPathSegment struct {
+	Type string   `json:"type"`
+	ASN  []uint32 `json:"asn"`
+}
+
+var asPathSegmentTypes = map[uint8]string{
+	ASSet:            "as_set",
+	ASSequence:       "as_sequence",
+	ASConfedSequence: "as_confed_sequence",
+	ASConfedSet:      "as_confed_set",
+}
+
+func (s *ASPathSegment) isConfed() bool {
+	return s.Type == asPathSegmentTypes[ASConfedSequence] || s.Type == asPathSegmentTypes[ASConfedSet]
+}
+
+// length returns the number of ASes the segment contributes to the path length,
+// AS_SET counts as one, confederation segments are not counted, RFC 4271 and RFC 5065
+func (s *ASPathSegment) length() int {
+	switch {
+	case s.isConfed():
+		return 0
+	case s.Type == asPathSegmentTypes[ASSet]:
+		return 1
+	default:
+		return len(s.ASN)
+	}
+}
+
+// unmarshalASPathSegments returns a slice of AS_PATH segments, as4 defines if 2 or 4 bytes AS is used
+func unmarshalASPathSegments(b []byte, as4 bool) ([]ASPathSegment, error) {
+	asLen := 2
+	if as4 {
+		asLen = 4
+	}
+	segments := make([]ASPathSegment, 0)
+	for p := 0; p < len(b); {
+		if p+2 > len(b) {
+			return nil, fmt.Errorf("not enough bytes to unmarshal AS_PATH segment header")
+		}
+		t, ok := asPathSegmentTypes[b[p]]
+		if !ok {
+			return nil, fmt.Errorf("invalid AS_PATH segment type %d", b[p])
+		}
+		p++
+		l := int(b[p])
+		p++
+		if p+l*asLen > len(b) {
+			return nil, fmt.Errorf("not enough bytes to unmarshal AS_PATH segment of %d ASes", l)
+		}
+		seg := ASPathSegment{
+			Type: t,
+			ASN:  make([]uint32, l),
+		}
+		for n := 0; n < l; n++ {
+			if as4 {
+				seg.ASN[n] = binary.BigEndian.Uint32(b[p : p+4])
+			} else {
+				seg.ASN[n] = uint32(binary.BigEndian.Uint16(b[p : p+2]))
+			}
+			p += asLen
+		}
+		segments = append(segments, seg)
+	}
+
+	return segments, nil
+}
+
+// flattenASPath returns a list of all ASes found in AS_PATH segments
+func flattenASPath(segments []ASPathSegment) []uint32 {
+	path := make([]uint32, 0)
+	for _, seg := range segments {
+		path = append(path, seg.ASN...)
+	}
+
+	return path
+}
+
+// confedASPath returns a list of ASes found in confederation segments of AS_PATH
+func confedASPath(segments []ASPathSegment) []uint32 {
+	var path []uint32
+	for _, seg := range segments {
+		if seg.isConfed() {
+			path = append(path, seg.ASN...)
+		}
+	}
+
+	return path
+}
+
+// mergeAS4Path reconstructs AS_PATH received from 2 bytes AS speaker by using AS4_PATH as per RFC 6793 Section 4.2.3.
+func mergeAS4Path(asPath, as4Path []ASPathSegment) []ASPathSegment {
+	// Confederation segments must not be carried in AS4_PATH, if received, they are discarded
+	as4 := make([]ASPathSegment, 0, len(as4Path))
+	as4Len := 0
+	for _, seg := range as4Path {
+		if seg.isConfed() {
+			continue
+		}
+		as4 = append(as4, seg)
+		as4Len += seg.length()
+	}
+	asLen := 0
+	for _, seg := range asPath {
+		asLen += seg.length()
+	}
+	if len(as4) == 0 || asLen < as4Len {
+		// AS4_PATH is longer than AS_PATH, AS4_PATH is ignored
+		return asPath
+	}
+	// Keeping leading ASes of AS_PATH which are not present in AS4_PATH
+	n := asLen - as4Len
+	merged := make([]ASPathSegment, 0, len(asPath)+len(as4))
+	for _, seg := range asPath {
+		if seg.isConfed() {
+			merged = append(merged, seg)
+			continue
+		}
+		if n == 0 {
+			break
+		}
+		if seg.Type == asPathSegmentTypes[ASSequence] && len(seg.ASN) > n {
+			merged = append(merged, ASPathSegment{Type: seg.Type, ASN: seg.ASN[:n]})
+			n = 0
+			continue
+		}
+		merged = append(merged, seg)
+		n -= seg.length()
+	}
+
+	return append(merged, as4...)
+}
+
+// originAS returns the AS of the route's originator, when AS_PATH is empty or the last segment is not
+// AS_SEQUENCE, the origin cannot be determined and 0 is returned, RFC 6811 Section 2.
+func originAS(segments []ASPathSegment) uint32 {
+	if len(segments) == 0 {
+		return 0
+	}
+	last := segments[len(segments)-1]
+	if last.Type != asPathSegmentTypes[ASSequence] || len(last.ASN) == 0 {
+		return 0
+	}
+
+	return last.ASN[len(last.ASN)-1]
+}
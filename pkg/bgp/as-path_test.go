@@ -0,0 +1,112 @@
+package bgp
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/go-test/deep"
+)
+
+func TestUnmarshalASPathAttributes(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    []byte
+		segments []ASPathSegment
+		asPath   []uint32
+		confed   []uint32
+		originAS uint32
+	}{
+		{
+			name: "AS4 sequence and set",
+			// AS_PATH: AS_SEQUENCE 65001 4200000001, AS_SET 65010 65011
+			input: []byte{0x40, 0x02, 0x14, 0x02, 0x02, 0x00, 0x00, 0xfd, 0xe9, 0xfa, 0x56, 0xea, 0x01, 0x01, 0x02, 0x00, 0x00, 0xfd, 0xf2, 0x00, 0x00, 0xfd, 0xf3},
+			segments: []ASPathSegment{
+				{Type: "as_sequence", ASN: []uint32{65001, 4200000001}},
+				{Type: "as_set", ASN: []uint32{65010, 65011}},
+			},
+			asPath:   []uint32{65001, 4200000001, 65010, 65011},
+			originAS: 0,
+		},
+		{
+			name: "AS4 confederation sequence",
+			// AS_PATH: AS_CONFED_SEQUENCE 64512, AS_SEQUENCE 65001 65002
+			input: []byte{0x40, 0x02, 0x10, 0x03, 0x01, 0x00, 0x00, 0xfc, 0x00, 0x02, 0x02, 0x00, 0x00, 0xfd, 0xe9, 0x00, 0x00, 0xfd, 0xea},
+			segments: []ASPathSegment{
+				{Type: "as_confed_sequence", ASN: []uint32{64512}},
+				{Type: "as_sequence", ASN: []uint32{65001, 65002}},
+			},
+			asPath:   []uint32{64512, 65001, 65002},
+			confed:   []uint32{64512},
+			originAS: 65002,
+		},
+		{
+			name: "AS2 path merged with AS4_PATH",
+			// AS_PATH: AS_SEQUENCE 65001 23456 23456
+			// AS4_PATH: AS_SEQUENCE 4200000001 4200000002
+			input: []byte{0x40, 0x02, 0x08, 0x02, 0x03, 0xfd, 0xe9, 0x5b, 0xa0, 0x5b, 0xa0,
+				0xc0, 0x11, 0x0a, 0x02, 0x02, 0xfa, 0x56, 0xea, 0x01, 0xfa, 0x56, 0xea, 0x02},
+			segments: []ASPathSegment{
+				{Type: "as_sequence", ASN: []uint32{65001}},
+				{Type: "as_sequence", ASN: []uint32{4200000001, 4200000002}},
+			},
+			asPath:   []uint32{65001, 4200000001, 4200000002},
+			originAS: 4200000002,
+		},
+		{
+			name: "AS2 path with longer AS4_PATH",
+			// AS_PATH: AS_SEQUENCE 23456
+			// AS4_PATH: AS_SEQUENCE 4200000001 4200000002, AS4_PATH is ignored
+			input: []byte{0x40, 0x02, 0x04, 0x02, 0x01, 0x5b, 0xa0,
+				0xc0, 0x11, 0x0a, 0x02, 0x02, 0xfa, 0x56, 0xea, 0x01, 0xfa, 0x56, 0xea, 0x02},
+			segments: []ASPathSegment{
+				{Type: "as_sequence", ASN: []uint32{23456}},
+			},
+			asPath:   []uint32{23456},
+			originAS: 23456,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := UnmarshalBGPBaseAttributes(tt.input)
+			if err != nil {
+				t.Fatalf("expected to succeed but failed with error: %+v", err)
+			}
+			if !reflect.DeepEqual(got.ASPathSegments, tt.segments) {
+				t.Logf("differences: %+v", deep.Equal(got.ASPathSegments, tt.segments))
+				t.Errorf("expected segments %+v do not match actual segments %+v", tt.segments, got.ASPathSegments)
+			}
+			if !reflect.DeepEqual(got.ASPath, tt.asPath) {
+				t.Errorf("expected as path %+v does not match actual as path %+v", tt.asPath, got.ASPath)
+			}
+			if !reflect.DeepEqual(got.ConfedASPath, tt.confed) {
+				t.Errorf("expected confederation as path %+v does not match actual path %+v", tt.confed, got.ConfedASPath)
+			}
+			if got.OriginAS != tt.originAS {
+				t.Errorf("expected origin as %d does not match actual origin as %d", tt.originAS, got.OriginAS)
+			}
+		})
+	}
+}
+
+func TestUnmarshalASPathSegmentsInvalid(t *testing.T) {
+	tests := []struct {
+		name  string
+		input []byte
+	}{
+		{
+			name:  "invalid segment type",
+			input: []byte{0x05, 0x01, 0x00, 0x00, 0xfd, 0xe9},
+		},
+		{
+			name:  "truncated segment",
+			input: []byte{0x02, 0x02, 0x00, 0x00, 0xfd, 0xe9},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := unmarshalASPathSegments(tt.input, true); err == nil {
+				t.Fatalf("expected to fail but succeeded")
+			}
+		})
+	}
+}
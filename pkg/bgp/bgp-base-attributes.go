@@ -5,13 +5,21 @@ import (
 	"encoding/binary"
 	"encoding/hex"
 	"encoding/json"
-	"net"
 	"strconv"
+	"strings"
 
 	"github.com/golang/glog"
+	"github.com/sbezverk/gobmp/pkg/addr"
+	"github.com/sbezverk/gobmp/pkg/logging"
 	"github.com/sbezverk/tools"
 )
 
+// Well-known communities of Long-Lived Graceful Restart https://tools.ietf.org/html/rfc9494#section-4.3
+const (
+	CommunityLLGRStale uint32 = 0xffff0006
+	CommunityNoLLGR    uint32 = 0xffff0007
+)
+
 // BaseAttributes defines a structure holding BGP's basic, non nlri based attributes,
 // codes for each can be found:
 // https://www.iana.org/assignments/bgp-parameters/bgp-parameters.xhtml#bgp-parameters-2
@@ -20,6 +28,7 @@ type BaseAttributes struct {
 	Origin           string   `json:"origin,omitempty"`
 	ASPath           []uint32 `json:"as_path,omitempty"`
 	ASPathCount      int32    `json:"as_path_count,omitempty"`
+	OriginAS         uint32   `json:"origin_as,omitempty"`
 	Nexthop          string   `json:"nexthop,omitempty"`
 	MED              uint32   `json:"med,omitempty"`
 	LocalPref        uint32   `json:"local_pref,omitempty"`
@@ -28,6 +37,7 @@ type BaseAttributes struct {
 	CommunityList    []string `json:"community_list,omitempty"`
 	OriginatorID     string   `json:"originator_id,omitempty"`
 	ClusterList      string   `json:"cluster_list,omitempty"`
+	ClusterIDs       []string `json:"cluster_ids,omitempty"`
 	ExtCommunityList []string `json:"ext_community_list,omitempty"`
 	AS4Path          []uint32 `json:"as4_path,omitempty"`
 	AS4PathCount     int32    `json:"as4_path_count,omitempty"`
@@ -39,17 +49,39 @@ type BaseAttributes struct {
 	// AIGP
 	// PEDistinguisherLable
 	LgCommunityList []string `json:"large_community_list,omitempty"`
-	// SecPath
+	// BGPsecPath carries BGPsec_Path attribute, BGPsec speakers send it instead of AS_PATH
+	BGPsecPath *BGPsecPath `json:"bgpsec_path,omitempty"`
+	// OTC carries Only to Customer attribute https://tools.ietf.org/html/rfc9234#section-5,
+	// the value is AS of the speaker which marked the route.
+	OTC uint32 `json:"otc,omitempty"`
+	// LLGRStale and NoLLGR are set when the route carries well-known LLGR_STALE and NO_LLGR communities
+	// https://tools.ietf.org/html/rfc9494#section-4.3, LLGR_STALE marks a route retained as stale by a speaker
+	// whose peer restarted, such routes are least preferred until the peer re-advertises them.
+	LLGRStale bool `json:"llgr_stale,omitempty"`
+	NoLLGR    bool `json:"no_llgr,omitempty"`
 	// AttrSet
+	// ASPathSegments carries AS_PATH with preserved segments structure, when AS_PATH was received
+	// from 2 bytes AS speaker, AS4_PATH is merged in as per RFC 6793.
+	ASPathSegments []ASPathSegment `json:"as_path_segments,omitempty"`
+	// ConfedASPath carries member ASes of AS_CONFED_SEQUENCE and AS_CONFED_SET segments of AS_PATH in the order
+	// the route traversed them within the confederation, RFC 5065.
+	ConfedASPath []uint32 `json:"confed_as_path,omitempty"`
+	// AggregatorAS and AggregatorAddress carry AS and IP address of the speaker which aggregated the route,
+	// AS4_AGGREGATOR takes precedence over AGGREGATOR carrying AS_TRANS.
+	AggregatorAS      uint32 `json:"aggregator_as,omitempty"`
+	AggregatorAddress string `json:"aggregator_address,omitempty"`
+	// UnknownAttrs carries path attributes which are not decoded, including ATTR_SET, in the order received.
+	UnknownAttrs []UnknownAttribute `json:"unknown_attrs,omitempty"`
 }
 
 // UnmarshalBGPBaseAttributes discovers all present Base Attributes in BGP Update
 // and instantiates BaseAttributes object
 func UnmarshalBGPBaseAttributes(b []byte) (*BaseAttributes, error) {
-	if glog.V(6) {
+	if logging.V(logging.BGP, 6) {
 		glog.Infof("UnmarshalBGPBaseAttributes RAW: %+v", tools.MessageHex(b))
 	}
 	baseAttr := BaseAttributes{}
+	var asPath, as4Path []byte
 	for p := 0; p < len(b); {
 		flag := b[p]
 		p++
@@ -68,8 +100,7 @@ func UnmarshalBGPBaseAttributes(b []byte) (*BaseAttributes, error) {
 		case 1:
 			baseAttr.Origin = unmarshalAttrOrigin(b[p : p+int(l)])
 		case 2:
-			baseAttr.ASPath = unmarshalAttrASPath(b[p : p+int(l)])
-			baseAttr.ASPathCount = int32(len(baseAttr.ASPath))
+			asPath = b[p : p+int(l)]
 		case 3:
 			baseAttr.Nexthop = unmarshalAttrNextHop(b[p : p+int(l)])
 		case 4:
@@ -82,14 +113,17 @@ func UnmarshalBGPBaseAttributes(b []byte) (*BaseAttributes, error) {
 			baseAttr.Aggregator = unmarshalAttrAggregator(b[p : p+int(l)])
 		case 8:
 			baseAttr.CommunityList = unmarshalAttrCommunity(b[p : p+int(l)])
+			baseAttr.LLGRStale, baseAttr.NoLLGR = llgrCommunities(b[p : p+int(l)])
 		case 9:
 			baseAttr.OriginatorID = unmarshalAttrOriginatorID(b[p : p+int(l)])
 		case 10:
-			baseAttr.ClusterList = unmarshalAttrClusterList(b[p : p+int(l)])
+			baseAttr.ClusterIDs = unmarshalAttrClusterIDs(b[p : p+int(l)])
+			baseAttr.ClusterList = strings.Join(baseAttr.ClusterIDs, ", ")
 		case 16:
 			baseAttr.ExtCommunityList = unmarshalAttrExtCommunity(b[p : p+int(l)])
 		case 17:
-			baseAttr.AS4Path = unmarshalAttrAS4Path(b[p : p+int(l)])
+			as4Path = b[p : p+int(l)]
+			baseAttr.AS4Path = unmarshalAttrAS4Path(as4Path)
 			baseAttr.AS4PathCount = int32(len(baseAttr.AS4Path))
 		case 18:
 			baseAttr.AS4Aggregator = unmarshalAttrAS4Aggregator(b[p : p+int(l)])
@@ -106,21 +140,111 @@ func UnmarshalBGPBaseAttributes(b []byte) (*BaseAttributes, error) {
 		case 32:
 			baseAttr.LgCommunityList = unmarshalAttrLgCommunity(b[p : p+int(l)])
 		case 33:
+			bp, err := UnmarshalBGPsecPath(b[p : p+int(l)])
+			if err != nil {
+				glog.Errorf("failed to unmarshal bgpsec_path attribute with error: %+v", err)
+				break
+			}
+			baseAttr.BGPsecPath = bp
+		case 35:
+			baseAttr.OTC = unmarshalAttrOTC(b[p : p+int(l)])
 		case 128:
 		}
+		if !decodedAttributes[t] {
+			// ATTR_SET and attributes not decoded by gobmp are preserved as received
+			baseAttr.UnknownAttrs = append(baseAttr.UnknownAttrs, newUnknownAttribute(t, flag, b[p:p+int(l)]))
+		}
 		p += int(l)
 	}
+	useAS4 := baseAttr.aggregator()
+	if len(asPath) != 0 {
+		as4 := isASPath4(asPath)
+		segments, err := unmarshalASPathSegments(asPath, as4)
+		if err != nil {
+			return nil, err
+		}
+		if !as4 && len(as4Path) != 0 && useAS4 {
+			// AS_PATH from 2 bytes AS speaker, AS4_PATH carries the actual 4 bytes ASes
+			as4Segments, err := unmarshalASPathSegments(as4Path, true)
+			if err != nil {
+				return nil, err
+			}
+			segments = mergeAS4Path(segments, as4Segments)
+		}
+		baseAttr.ASPathSegments = segments
+		baseAttr.ConfedASPath = confedASPath(segments)
+		baseAttr.ASPath = flattenASPath(segments)
+		baseAttr.ASPathCount = int32(len(baseAttr.ASPath))
+		baseAttr.OriginAS = originAS(segments)
+	}
+	if len(asPath) == 0 && baseAttr.BGPsecPath != nil {
+		// Update from BGPsec speaker carries no AS_PATH, the path is recovered from Secure_Path
+		baseAttr.ASPath = baseAttr.BGPsecPath.ASPath()
+		baseAttr.ASPathCount = int32(len(baseAttr.ASPath))
+		if len(baseAttr.ASPath) != 0 {
+			baseAttr.OriginAS = baseAttr.ASPath[len(baseAttr.ASPath)-1]
+		}
+	}
 	// Calculating hash of all recovered base attributes
-	ba, err := json.Marshal(baseAttr)
+	h, err := baseAttr.hash()
 	if err != nil {
 		return nil, err
 	}
-	s := md5.Sum(ba)
-	baseAttr.BaseAttrHash = hex.EncodeToString(s[:])
+	baseAttr.BaseAttrHash = h
 
 	return &baseAttr, nil
 }
 
+// legacyAttributes carries base attributes decoded before base_attrs was extended, base_attr_hash is
+// calculated from them so it stays the same as published by previous releases when new fields are added
+// to BaseAttributes.
+type legacyAttributes struct {
+	Origin           string   `json:"origin,omitempty"`
+	ASPath           []uint32 `json:"as_path,omitempty"`
+	ASPathCount      int32    `json:"as_path_count,omitempty"`
+	Nexthop          string   `json:"nexthop,omitempty"`
+	MED              uint32   `json:"med,omitempty"`
+	LocalPref        uint32   `json:"local_pref,omitempty"`
+	IsAtomicAgg      bool     `json:"is_atomic_agg"`
+	Aggregator       []byte   `json:"aggregator,omitempty"`
+	CommunityList    []string `json:"community_list,omitempty"`
+	OriginatorID     string   `json:"originator_id,omitempty"`
+	ClusterList      string   `json:"cluster_list,omitempty"`
+	ExtCommunityList []string `json:"ext_community_list,omitempty"`
+	AS4Path          []uint32 `json:"as4_path,omitempty"`
+	AS4PathCount     int32    `json:"as4_path_count,omitempty"`
+	AS4Aggregator    []byte   `json:"as4_aggregator,omitempty"`
+	LgCommunityList  []string `json:"large_community_list,omitempty"`
+}
+
+// hash returns md5 hash of json representation of legacy base attributes.
+func (ba *BaseAttributes) hash() (string, error) {
+	b, err := json.Marshal(&legacyAttributes{
+		Origin:           ba.Origin,
+		ASPath:           ba.ASPath,
+		ASPathCount:      ba.ASPathCount,
+		Nexthop:          ba.Nexthop,
+		MED:              ba.MED,
+		LocalPref:        ba.LocalPref,
+		IsAtomicAgg:      ba.IsAtomicAgg,
+		Aggregator:       ba.Aggregator,
+		CommunityList:    ba.CommunityList,
+		OriginatorID:     ba.OriginatorID,
+		ClusterList:      ba.ClusterList,
+		ExtCommunityList: ba.ExtCommunityList,
+		AS4Path:          ba.AS4Path,
+		AS4PathCount:     ba.AS4PathCount,
+		AS4Aggregator:    ba.AS4Aggregator,
+		LgCommunityList:  ba.LgCommunityList,
+	})
+	if err != nil {
+		return "", err
+	}
+	s := md5.Sum(b)
+
+	return hex.EncodeToString(s[:]), nil
+}
+
 // unmarshalAttrOrigin returns the value of Origin attribute
 func unmarshalAttrOrigin(b []byte) string {
 	switch b[0] {
@@ -140,32 +264,18 @@ func unmarshalAttrASPath(b []byte) []uint32 {
 	if len(b) == 0 {
 		return nil
 	}
-	path := make([]uint32, 0)
-	as4 := isASPath4(b)
-	for p := 0; p < len(b); {
-		// Skipping type
-		p++
-		// Length of path segment of type
-		l := b[p]
-		p++
-		// Attempting to detect if 2 or 4 bytes AS is used
-		for n := 0; n < int(l); n++ {
-			if as4 {
-				as := binary.BigEndian.Uint32(b[p : p+4])
-				p += 4
-				path = append(path, as)
-			} else {
-				as := binary.BigEndian.Uint16(b[p : p+2])
-				p += 2
-				path = append(path, uint32(as))
-			}
-		}
+	segments, err := unmarshalASPathSegments(b, isASPath4(b))
+	if err != nil {
+		return nil
 	}
 
-	return path
+	return flattenASPath(segments)
 }
 
 func isASPath4(b []byte) bool {
+	if len(b) < 2 {
+		return false
+	}
 	p := 0
 	// Skipping type
 	p++
@@ -184,14 +294,14 @@ func isASPath4(b []byte) bool {
 	}
 	// Check if next segment can be found with AS4
 	if p+l*4 < len(b) {
-		if b[p+l*4] == 0x1 || b[p+l*4] == 0x2 {
+		if _, ok := asPathSegmentTypes[b[p+l*4]]; ok {
 			// Found next AS4 segment, confirmed AS4
 			return true
 		}
 	}
 	// Check if next segment can be found with AS2
 	if p+l*2 < len(b) {
-		if b[p+l*2] == 0x1 || b[p+l*2] == 0x2 {
+		if _, ok := asPathSegmentTypes[b[p+l*2]]; ok {
 			// Found next AS2 segment, confirmed AS2
 			return false
 		}
@@ -203,9 +313,9 @@ func isASPath4(b []byte) bool {
 // unmarshalAttrNextHop returns the value of Next Hop attribute
 func unmarshalAttrNextHop(b []byte) string {
 	if len(b) == 4 {
-		return net.IP(b).To4().String()
+		return addr.IPv4(b)
 	}
-	return net.IP(b).To16().String()
+	return addr.IPv6(b)
 }
 
 // unmarshalAttrMED returns the value of MED attribute
@@ -255,42 +365,53 @@ func unmarshalAttrCommunity(b []byte) []string {
 	return s
 }
 
+// llgrCommunities returns whether LLGR_STALE and NO_LLGR well-known communities are present
+func llgrCommunities(b []byte) (bool, bool) {
+	var stale, noLLGR bool
+	for _, c := range getCommunity(b) {
+		switch c {
+		case CommunityLLGRStale:
+			stale = true
+		case CommunityNoLLGR:
+			noLLGR = true
+		}
+	}
+
+	return stale, noLLGR
+}
+
+// unmarshalAttrOTC returns AS carried in Only to Customer attribute, malformed attribute is
+// treated as absent per RFC 9234
+func unmarshalAttrOTC(b []byte) uint32 {
+	if len(b) != 4 {
+		return 0
+	}
+
+	return binary.BigEndian.Uint32(b)
+}
+
 // unmarshalAttrOriginatorID returns the value of ORIGINATOR_ID attribute
 func unmarshalAttrOriginatorID(b []byte) string {
 	if len(b) == 4 {
-		return net.IP(b).To4().String()
+		return addr.IPv4(b)
 	}
 
 	return "invalid length"
 }
 
-// getClusterID returns a slice of Cluster IDs from Cluster List attribute
-func getClusterID(b []byte) [][]byte {
-	cl := make([][]byte, 0)
-	i := 0
-	for p := 0; p < len(b); {
-		c := make([]byte, 4)
-		copy(c, b[p:p+4])
-		p += 4
-		i++
-		cl = append(cl, c)
+// unmarshalAttrClusterIDs returns a slice of Cluster IDs of CLUSTER_LIST attribute, the first Cluster ID
+// is the cluster of the last route reflector the route passed through, RFC 4456. CLUSTER_LIST of invalid
+// length is ignored.
+func unmarshalAttrClusterIDs(b []byte) []string {
+	if len(b) == 0 || len(b)%4 != 0 {
+		return nil
 	}
-
-	return cl
-}
-
-// unmarshalAttrClusterList returns the string with comma separated communities.
-func unmarshalAttrClusterList(b []byte) string {
-	var clist string
-	cl := getClusterID(b)
-	for i, c := range cl {
-		clist += net.IP(c).To4().String()
-		if i < len(cl)-1 {
-			clist += ", "
-		}
+	cl := make([]string, 0, len(b)/4)
+	for p := 0; p < len(b); p += 4 {
+		cl = append(cl, addr.IPv4(b[p:p+4]))
 	}
 
-	return clist
+	return cl
 }
 
 //  unmarshalAttrExtCommunity returns a slice with all extended communities found in bgp update
@@ -17,14 +17,69 @@ func TestUnmarshaBaseAttributes(t *testing.T) {
 			name:  "panic 1",
 			input: []byte{0x40, 0x01, 0x01, 0x00, 0x40, 0x02, 0x20, 0x02, 0x06, 0x00, 0x00, 0x88, 0x38, 0x00, 0x00, 0x9a, 0x6d, 0x00, 0x00, 0x19, 0x35, 0x00, 0x00, 0x0a, 0x7f, 0x00, 0x00, 0x65, 0x20, 0x00, 0x00, 0x53, 0x4e, 0x01, 0x01, 0x00, 0x00, 0x12, 0xc9, 0x40, 0x03, 0x04, 0xc2, 0x1c, 0x62, 0x25, 0x80, 0x04, 0x04, 0x00, 0x00, 0x00, 0x00, 0xc0, 0x07, 0x08, 0x00, 0x00, 0x65, 0x20, 0xc0, 0x78, 0x51, 0x88, 0xc0, 0x08, 0x18, 0x00, 0x00, 0x9a, 0x6d, 0x19, 0x35, 0x00, 0x56, 0x19, 0x35, 0x0b, 0xb8, 0x19, 0x35, 0x0c, 0x1c, 0x19, 0x35, 0x0c, 0x1e, 0x9a, 0x6d, 0xc2, 0x02, 0xc0, 0x20, 0x30, 0x00, 0x00, 0x88, 0x38, 0x00, 0x00, 0x00, 0x0a, 0x00, 0x00, 0x00, 0xd3, 0x00, 0x00, 0x88, 0x38, 0x00, 0x00, 0x00, 0x0b, 0x00, 0x00, 0x00, 0x01, 0x00, 0x00, 0x88, 0x38, 0x00, 0x00, 0x00, 0x64, 0x00, 0x00, 0x00, 0x31, 0x00, 0x00, 0x88, 0x38, 0x00, 0x00, 0x00, 0x7a, 0x00, 0x00, 0x00, 0x01},
 			expect: &BaseAttributes{
-				BaseAttrHash:    "adfbe0fd919abd7a940eb75ed99af1e5",
-				Origin:          "igp",
-				ASPath:          []uint32{34872, 39533, 6453, 2687, 25888, 21326, 4809},
-				ASPathCount:     7,
-				Nexthop:         "194.28.98.37",
-				Aggregator:      []byte{0, 0, 101, 32, 192, 120, 81, 136},
-				CommunityList:   []string{"0:39533", "6453:86", "6453:3000", "6453:3100", "6453:3102", "39533:49666"},
-				LgCommunityList: []string{"34872:10:211", "34872:11:1", "34872:100:49", "34872:122:1"},
+				BaseAttrHash: "adfbe0fd919abd7a940eb75ed99af1e5",
+				Origin:       "igp",
+				ASPath:       []uint32{34872, 39533, 6453, 2687, 25888, 21326, 4809},
+				ASPathCount:  7,
+				ASPathSegments: []ASPathSegment{
+					{Type: "as_sequence", ASN: []uint32{34872, 39533, 6453, 2687, 25888, 21326}},
+					{Type: "as_set", ASN: []uint32{4809}},
+				},
+				Nexthop:           "194.28.98.37",
+				Aggregator:        []byte{0, 0, 101, 32, 192, 120, 81, 136},
+				AggregatorAS:      25888,
+				AggregatorAddress: "192.120.81.136",
+				CommunityList:     []string{"0:39533", "6453:86", "6453:3000", "6453:3100", "6453:3102", "39533:49666"},
+				LgCommunityList:   []string{"34872:10:211", "34872:11:1", "34872:100:49", "34872:122:1"},
+			},
+		},
+		{
+			name: "only to customer",
+			// ORIGIN igp, OTC 65001
+			input: []byte{0x40, 0x01, 0x01, 0x00, 0xc0, 0x23, 0x04, 0x00, 0x00, 0xfd, 0xe9},
+			expect: &BaseAttributes{
+				BaseAttrHash: "279ae1ce0259c0c3694d66cd3854612a",
+				Origin:       "igp",
+				OTC:          65001,
+			},
+		},
+		{
+			name: "llgr communities",
+			// ORIGIN igp, COMMUNITIES LLGR_STALE NO_LLGR
+			input: []byte{0x40, 0x01, 0x01, 0x00, 0xc0, 0x08, 0x08, 0xff, 0xff, 0x00, 0x06, 0xff, 0xff, 0x00, 0x07},
+			expect: &BaseAttributes{
+				BaseAttrHash:  "a99f32401025843cccdb5e378aae210d",
+				Origin:        "igp",
+				CommunityList: []string{"65535:6", "65535:7"},
+				LLGRStale:     true,
+				NoLLGR:        true,
+			},
+		},
+		{
+			name: "route reflector attributes",
+			// ORIGIN igp, ORIGINATOR_ID 192.0.2.1, CLUSTER_LIST 10.0.0.2 10.0.0.1
+			input: []byte{0x40, 0x01, 0x01, 0x00, 0x80, 0x09, 0x04, 0xc0, 0x00, 0x02, 0x01,
+				0x80, 0x0a, 0x08, 0x0a, 0x00, 0x00, 0x02, 0x0a, 0x00, 0x00, 0x01},
+			expect: &BaseAttributes{
+				BaseAttrHash: "6f36b9ee3e88d3af0ab515d6e548cb31",
+				Origin:       "igp",
+				OriginatorID: "192.0.2.1",
+				ClusterList:  "10.0.0.2, 10.0.0.1",
+				ClusterIDs:   []string{"10.0.0.2", "10.0.0.1"},
+			},
+		},
+		{
+			name: "unknown attributes",
+			// ORIGIN igp, AIGP metric 100, ATTR_SET origin AS 65001 with ORIGIN igp
+			input: []byte{0x40, 0x01, 0x01, 0x00, 0x80, 0x1a, 0x0b, 0x01, 0x00, 0x0b, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x64,
+				0xc0, 0x80, 0x08, 0x00, 0x00, 0xfd, 0xe9, 0x40, 0x01, 0x01, 0x00},
+			expect: &BaseAttributes{
+				BaseAttrHash: "279ae1ce0259c0c3694d66cd3854612a",
+				Origin:       "igp",
+				UnknownAttrs: []UnknownAttribute{
+					{Type: 26, Flags: 0x80, RawHex: "01000b0000000000000064"},
+					{Type: 128, Flags: 0xc0, RawHex: "0000fde940010100"},
+				},
 			},
 		},
 	}
@@ -0,0 +1,245 @@
+package bgp
+
+import (
+	"encoding/binary"
+	"fmt"
+	"strconv"
+)
+
+// CapabilityParameters defines decoded parameters of a BGP Capability, only fields of the capability's
+// code are set, capabilities without parameters, for example Route Refresh, carry no parameters.
+type CapabilityParameters struct {
+	// AFI and SAFI of Multiprotocol Extensions https://tools.ietf.org/html/rfc4760#section-8,
+	// AFI is also set for BGPsec capability
+	AFI  uint16 `json:"afi,omitempty"`
+	SAFI uint8  `json:"safi,omitempty"`
+	// ASN of Support for 4-octet AS number capability https://tools.ietf.org/html/rfc6793#section-3
+	ASN uint32 `json:"asn,omitempty"`
+	// Graceful Restart capability https://tools.ietf.org/html/rfc4724#section-3
+	RestartState         bool                    `json:"restart_state,omitempty"`
+	GracefulNotification bool                    `json:"graceful_notification,omitempty"`
+	RestartTime          uint16                  `json:"restart_time,omitempty"`
+	GracefulRestart      []*GracefulRestartTuple `json:"graceful_restart,omitempty"`
+	// Long-Lived Graceful Restart capability https://tools.ietf.org/html/rfc9494#section-3
+	LongLivedGracefulRestart []*LLGRTuple `json:"llgr,omitempty"`
+	// ADD-PATH capability https://tools.ietf.org/html/rfc7911#section-4
+	AddPath []*AddPathTuple `json:"add_path,omitempty"`
+	// Extended Next Hop Encoding capability https://tools.ietf.org/html/rfc8950#section-4
+	ExtendedNextHop []*ExtendedNextHopTuple `json:"extended_nexthop,omitempty"`
+	// Multiple Labels capability https://tools.ietf.org/html/rfc8277#section-2.1
+	MultipleLabels []*MultipleLabelsTuple `json:"multiple_labels,omitempty"`
+	// FQDN capability https://datatracker.ietf.org/doc/html/draft-walton-bgp-hostname-capability
+	Hostname   string `json:"hostname,omitempty"`
+	DomainName string `json:"domain_name,omitempty"`
+	// BGP Role capability https://tools.ietf.org/html/rfc9234#section-4.1
+	Role string `json:"role,omitempty"`
+	// BGPsec capability https://tools.ietf.org/html/rfc8205#section-2.1
+	BGPsecVersion   uint8  `json:"bgpsec_version,omitempty"`
+	BGPsecDirection string `json:"bgpsec_direction,omitempty"`
+}
+
+// GracefulRestartTuple defines AFI/SAFI of Graceful Restart capability and whether the forwarding state
+// was preserved for it
+type GracefulRestartTuple struct {
+	AFI                 uint16 `json:"afi"`
+	SAFI                uint8  `json:"safi"`
+	ForwardingPreserved bool   `json:"forwarding_preserved"`
+}
+
+// LLGRTuple defines AFI/SAFI of Long-Lived Graceful Restart capability with its long-lived stale time
+// in seconds
+type LLGRTuple struct {
+	AFI                 uint16 `json:"afi"`
+	SAFI                uint8  `json:"safi"`
+	ForwardingPreserved bool   `json:"forwarding_preserved"`
+	StaleTime           uint32 `json:"stale_time"`
+}
+
+// AddPathTuple defines AFI/SAFI of ADD-PATH capability, Mode is "receive", "send" or "send_receive"
+type AddPathTuple struct {
+	AFI  uint16 `json:"afi"`
+	SAFI uint8  `json:"safi"`
+	Mode string `json:"mode"`
+}
+
+// ExtendedNextHopTuple defines NLRI AFI/SAFI which next hop can be of Next Hop AFI
+type ExtendedNextHopTuple struct {
+	AFI        uint16 `json:"afi"`
+	SAFI       uint16 `json:"safi"`
+	NextHopAFI uint16 `json:"nexthop_afi"`
+}
+
+// MultipleLabelsTuple defines AFI/SAFI of Multiple Labels capability with the number of labels
+type MultipleLabelsTuple struct {
+	AFI   uint16 `json:"afi"`
+	SAFI  uint8  `json:"safi"`
+	Count uint8  `json:"count"`
+}
+
+var addPathModes = map[uint8]string{
+	1: "receive",
+	2: "send",
+	3: "send_receive",
+}
+
+var bgpRoles = map[uint8]string{
+	0: "provider",
+	1: "rs",
+	2: "rs_client",
+	3: "customer",
+	4: "peer",
+}
+
+// UnmarshalCapabilityParameters returns decoded parameters of the capability's value, nil is returned
+// for capabilities without parameters and for capabilities not known to gobmp.
+func UnmarshalCapabilityParameters(code uint8, b []byte) (*CapabilityParameters, error) {
+	switch code {
+	case 1:
+		if len(b) != 4 {
+			return nil, fmt.Errorf("invalid length %d of Multiprotocol Extensions capability", len(b))
+		}
+		return &CapabilityParameters{AFI: binary.BigEndian.Uint16(b[0:2]), SAFI: b[3]}, nil
+	case 5:
+		return unmarshalExtendedNextHop(b)
+	case 7:
+		if len(b) != 3 {
+			return nil, fmt.Errorf("invalid length %d of BGPsec capability", len(b))
+		}
+		p := &CapabilityParameters{
+			BGPsecVersion:   b[0] >> 4,
+			BGPsecDirection: "receive",
+			AFI:             binary.BigEndian.Uint16(b[1:3]),
+		}
+		if b[0]&0x08 != 0 {
+			p.BGPsecDirection = "send"
+		}
+		return p, nil
+	case 8:
+		return unmarshalMultipleLabels(b)
+	case 9:
+		if len(b) != 1 {
+			return nil, fmt.Errorf("invalid length %d of BGP Role capability", len(b))
+		}
+		role, ok := bgpRoles[b[0]]
+		if !ok {
+			role = "unknown " + strconv.Itoa(int(b[0]))
+		}
+		return &CapabilityParameters{Role: role}, nil
+	case 64:
+		return unmarshalGracefulRestart(b)
+	case 65:
+		if len(b) != 4 {
+			return nil, fmt.Errorf("invalid length %d of 4-octet AS number capability", len(b))
+		}
+		return &CapabilityParameters{ASN: binary.BigEndian.Uint32(b)}, nil
+	case 69:
+		return unmarshalAddPath(b)
+	case 71:
+		return unmarshalLLGR(b)
+	case 73:
+		return unmarshalFQDN(b)
+	}
+
+	return nil, nil
+}
+
+func unmarshalExtendedNextHop(b []byte) (*CapabilityParameters, error) {
+	if len(b)%6 != 0 {
+		return nil, fmt.Errorf("invalid length %d of Extended Next Hop Encoding capability", len(b))
+	}
+	p := &CapabilityParameters{}
+	for i := 0; i < len(b); i += 6 {
+		p.ExtendedNextHop = append(p.ExtendedNextHop, &ExtendedNextHopTuple{
+			AFI:        binary.BigEndian.Uint16(b[i : i+2]),
+			SAFI:       binary.BigEndian.Uint16(b[i+2 : i+4]),
+			NextHopAFI: binary.BigEndian.Uint16(b[i+4 : i+6]),
+		})
+	}
+
+	return p, nil
+}
+
+func unmarshalMultipleLabels(b []byte) (*CapabilityParameters, error) {
+	if len(b)%4 != 0 {
+		return nil, fmt.Errorf("invalid length %d of Multiple Labels capability", len(b))
+	}
+	p := &CapabilityParameters{}
+	for i := 0; i < len(b); i += 4 {
+		p.MultipleLabels = append(p.MultipleLabels, &MultipleLabelsTuple{
+			AFI:   binary.BigEndian.Uint16(b[i : i+2]),
+			SAFI:  b[i+2],
+			Count: b[i+3],
+		})
+	}
+
+	return p, nil
+}
+
+func unmarshalGracefulRestart(b []byte) (*CapabilityParameters, error) {
+	if len(b) < 2 || (len(b)-2)%4 != 0 {
+		return nil, fmt.Errorf("invalid length %d of Graceful Restart capability", len(b))
+	}
+	p := &CapabilityParameters{
+		RestartState:         b[0]&0x80 != 0,
+		GracefulNotification: b[0]&0x40 != 0,
+		RestartTime:          binary.BigEndian.Uint16(b[0:2]) & 0x0fff,
+	}
+	for i := 2; i < len(b); i += 4 {
+		p.GracefulRestart = append(p.GracefulRestart, &GracefulRestartTuple{
+			AFI:                 binary.BigEndian.Uint16(b[i : i+2]),
+			SAFI:                b[i+2],
+			ForwardingPreserved: b[i+3]&0x80 != 0,
+		})
+	}
+
+	return p, nil
+}
+
+func unmarshalAddPath(b []byte) (*CapabilityParameters, error) {
+	if len(b)%4 != 0 {
+		return nil, fmt.Errorf("invalid length %d of ADD-PATH capability", len(b))
+	}
+	p := &CapabilityParameters{}
+	for i := 0; i < len(b); i += 4 {
+		mode, ok := addPathModes[b[i+3]]
+		if !ok {
+			mode = "unknown " + strconv.Itoa(int(b[i+3]))
+		}
+		p.AddPath = append(p.AddPath, &AddPathTuple{
+			AFI:  binary.BigEndian.Uint16(b[i : i+2]),
+			SAFI: b[i+2],
+			Mode: mode,
+		})
+	}
+
+	return p, nil
+}
+
+func unmarshalLLGR(b []byte) (*CapabilityParameters, error) {
+	if len(b)%7 != 0 {
+		return nil, fmt.Errorf("invalid length %d of Long-Lived Graceful Restart capability", len(b))
+	}
+	p := &CapabilityParameters{}
+	for i := 0; i < len(b); i += 7 {
+		p.LongLivedGracefulRestart = append(p.LongLivedGracefulRestart, &LLGRTuple{
+			AFI:                 binary.BigEndian.Uint16(b[i : i+2]),
+			SAFI:                b[i+2],
+			ForwardingPreserved: b[i+3]&0x80 != 0,
+			StaleTime:           uint32(b[i+4])<<16 | uint32(b[i+5])<<8 | uint32(b[i+6]),
+		})
+	}
+
+	return p, nil
+}
+
+func unmarshalFQDN(b []byte) (*CapabilityParameters, error) {
+	if len(b) < 1 || len(b) < 1+int(b[0])+1 || len(b) != 1+int(b[0])+1+int(b[1+int(b[0])]) {
+		return nil, fmt.Errorf("invalid length %d of FQDN capability", len(b))
+	}
+	hl := int(b[0])
+
+	return &CapabilityParameters{
+		Hostname:   string(b[1 : 1+hl]),
+		DomainName: string(b[2+hl:]),
+	}, nil
+}
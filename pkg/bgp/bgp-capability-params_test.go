@@ -0,0 +1,126 @@
+package bgp
+
+import (
+	"testing"
+
+	"github.com/go-test/deep"
+)
+
+func TestUnmarshalCapabilityParameters(t *testing.T) {
+	tests := []struct {
+		name   string
+		code   uint8
+		input  []byte
+		expect *CapabilityParameters
+		fail   bool
+	}{
+		{
+			name:  "graceful restart",
+			code:  64,
+			input: []byte{0xc0, 0x78, 0, 1, 1, 0x80, 0, 2, 1, 0},
+			expect: &CapabilityParameters{
+				RestartState:         true,
+				GracefulNotification: true,
+				RestartTime:          120,
+				GracefulRestart: []*GracefulRestartTuple{
+					{AFI: 1, SAFI: 1, ForwardingPreserved: true},
+					{AFI: 2, SAFI: 1},
+				},
+			},
+		},
+		{
+			name:  "graceful restart without tuples",
+			code:  64,
+			input: []byte{0, 0x5a},
+			expect: &CapabilityParameters{
+				RestartTime: 90,
+			},
+		},
+		{
+			name:  "long-lived graceful restart",
+			code:  71,
+			input: []byte{0, 1, 128, 0x80, 0, 0x0e, 0x10},
+			expect: &CapabilityParameters{
+				LongLivedGracefulRestart: []*LLGRTuple{{AFI: 1, SAFI: 128, ForwardingPreserved: true, StaleTime: 3600}},
+			},
+		},
+		{
+			name:  "add-path",
+			code:  69,
+			input: []byte{0, 1, 1, 1, 0, 2, 1, 3},
+			expect: &CapabilityParameters{
+				AddPath: []*AddPathTuple{{AFI: 1, SAFI: 1, Mode: "receive"}, {AFI: 2, SAFI: 1, Mode: "send_receive"}},
+			},
+		},
+		{
+			name:   "fqdn",
+			code:   73,
+			input:  []byte{2, 'r', '1', 11, 'e', 'x', 'a', 'm', 'p', 'l', 'e', '.', 'c', 'o', 'm'},
+			expect: &CapabilityParameters{Hostname: "r1", DomainName: "example.com"},
+		},
+		{
+			name:   "fqdn without domain",
+			code:   73,
+			input:  []byte{2, 'r', '1', 0},
+			expect: &CapabilityParameters{Hostname: "r1"},
+		},
+		{
+			name:   "role",
+			code:   9,
+			input:  []byte{3},
+			expect: &CapabilityParameters{Role: "customer"},
+		},
+		{
+			name:   "bgpsec",
+			code:   7,
+			input:  []byte{0x08, 0, 2},
+			expect: &CapabilityParameters{BGPsecDirection: "send", AFI: 2},
+		},
+		{
+			name:   "multiple labels",
+			code:   8,
+			input:  []byte{0, 1, 4, 2},
+			expect: &CapabilityParameters{MultipleLabels: []*MultipleLabelsTuple{{AFI: 1, SAFI: 4, Count: 2}}},
+		},
+		{
+			name:  "route refresh",
+			code:  2,
+			input: []byte{},
+		},
+		{
+			name:  "truncated fqdn",
+			code:  73,
+			input: []byte{2, 'r', '1', 11, 'e'},
+			fail:  true,
+		},
+		{
+			name:  "invalid add-path",
+			code:  69,
+			input: []byte{0, 1, 1},
+			fail:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			params, err := UnmarshalCapabilityParameters(tt.code, tt.input)
+			if err != nil && !tt.fail {
+				t.Fatalf("supposed to succeed but failed with error: %+v", err)
+			}
+			if err == nil && tt.fail {
+				t.Fatalf("supposed to fail but succeeded")
+			}
+			if diff := deep.Equal(params, tt.expect); len(diff) != 0 {
+				t.Errorf("expected and actual parameters do not match, differences: %+v", diff)
+			}
+		})
+	}
+}
+
+func TestUnmarshalBGPCapabilityTruncated(t *testing.T) {
+	if _, err := UnmarshalBGPCapability([]byte{64, 6, 0, 120}); err == nil {
+		t.Errorf("capability longer than the parameter is supposed to fail")
+	}
+	if _, err := UnmarshalBGPCapability([]byte{2, 0, 64}); err == nil {
+		t.Errorf("capability without length is supposed to fail")
+	}
+}
@@ -1,10 +1,12 @@
 package bgp
 
 import (
-	"encoding/binary"
+	"fmt"
+	"sort"
 	"strconv"
 
 	"github.com/golang/glog"
+	"github.com/sbezverk/gobmp/pkg/logging"
 	"github.com/sbezverk/tools"
 )
 
@@ -37,9 +39,11 @@ var BGPCapabilities = map[uint8]string{
 	185: "Prestandard OPERATIONAL message (deprecated)",
 }
 
+// CapabilityData defines a single BGP Capability, Parameters carry decoded Value of capabilities known to gobmp
 type CapabilityData struct {
-	Value       []byte `json:"capability_value,omitempty"`
-	Description string `json:"capability_descr,omitempty"`
+	Value       []byte                `json:"capability_value,omitempty"`
+	Description string                `json:"capability_descr,omitempty"`
+	Parameters  *CapabilityParameters `json:"capability_params,omitempty"`
 }
 
 // Capability Defines a structure for BGP Capability TLV which is sent as a part
@@ -71,6 +75,8 @@ func getAFISAFIString(afi uint16, safi uint8) string {
 		safiStr = "BGP-LS"
 	case 72:
 		safiStr = "BGP-LS-VPN"
+	case 85:
+		safiStr = "BGP-MUP"
 	case 128:
 		safiStr = "MPLS-labeled VPN"
 	}
@@ -80,15 +86,21 @@ func getAFISAFIString(afi uint16, safi uint8) string {
 
 // UnmarshalBGPCapability builds BGP Capability Information TLV object
 func UnmarshalBGPCapability(b []byte) (Capability, error) {
-	if glog.V(6) {
+	if logging.V(logging.BGP, 6) {
 		glog.Infof("UnmarshalBGPCapability Raw: %s", tools.MessageHex(b))
 	}
 	caps := make(Capability)
 	for p := 0; p < len(b); {
 		code := b[p]
 		p++
+		if p >= len(b) {
+			return nil, fmt.Errorf("capability %d is truncated", code)
+		}
 		length := b[p]
 		p++
+		if p+int(length) > len(b) {
+			return nil, fmt.Errorf("invalid length %d of capability %d", length, code)
+		}
 		capData := &CapabilityData{}
 		capData.Value = make([]byte, length)
 		copy(capData.Value, b[p:p+int(length)])
@@ -97,12 +109,14 @@ func UnmarshalBGPCapability(b []byte) (Capability, error) {
 		if !ok {
 			capData.Description = "Unknown capability " + strconv.Itoa(int(code))
 		}
-		switch code {
-		case 1:
-			// According RFC https://tools.ietf.org/html/rfc2858#section-7 Length will always be 4 bytes.
-			afi := binary.BigEndian.Uint16(capData.Value[:2])
-			safi := capData.Value[3]
-			capData.Description += getAFISAFIString(afi, safi)
+		params, err := UnmarshalCapabilityParameters(code, capData.Value)
+		if err != nil {
+			// Malformed capability is published with its raw value only
+			glog.Errorf("failed to decode parameters of capability %d with error: %+v", code, err)
+		}
+		capData.Parameters = params
+		if code == 1 && params != nil {
+			capData.Description += getAFISAFIString(params.AFI, params.SAFI)
 		}
 		c, ok := caps[code]
 		if !ok {
@@ -115,3 +129,21 @@ func UnmarshalBGPCapability(b []byte) (Capability, error) {
 
 	return caps, nil
 }
+
+// Serialize generates a slice of bytes from Capability map, capabilities are sorted by code
+func (c Capability) Serialize() []byte {
+	codes := make([]int, 0, len(c))
+	for code := range c {
+		codes = append(codes, int(code))
+	}
+	sort.Ints(codes)
+	b := make([]byte, 0)
+	for _, code := range codes {
+		for _, d := range c[uint8(code)] {
+			b = append(b, uint8(code), byte(len(d.Value)))
+			b = append(b, d.Value...)
+		}
+	}
+
+	return b
+}
@@ -2,6 +2,7 @@ package bgp
 
 import (
 	"github.com/golang/glog"
+	"github.com/sbezverk/gobmp/pkg/logging"
 	"github.com/sbezverk/tools"
 )
 
@@ -14,7 +15,7 @@ type InformationalTLV struct {
 
 // UnmarshalBGPTLV builds a slice of Informational TLVs
 func UnmarshalBGPTLV(b []byte) ([]InformationalTLV, Capability, error) {
-	if glog.V(6) {
+	if logging.V(logging.BGP, 6) {
 		glog.Infof("BGPTLV Raw: %s", tools.MessageHex(b))
 	}
 	tlvs := make([]InformationalTLV, 0)
@@ -5,6 +5,7 @@ import (
 	"fmt"
 
 	"github.com/golang/glog"
+	"github.com/sbezverk/gobmp/pkg/logging"
 	"github.com/sbezverk/tools"
 )
 
@@ -54,7 +55,7 @@ func (o *OpenMessage) AddPathCapability() map[int]bool {
 		glog.Errorf("invalid length %d of AddPath capability", len(v))
 		return m
 	}
-	if glog.V(6) {
+	if logging.V(logging.BGP, 6) {
 		glog.Infof("AddPath Capability Raw: %s", tools.MessageHex(v[0].Value))
 	}
 	// Check for Capability data consistency
@@ -71,7 +72,7 @@ func (o *OpenMessage) AddPathCapability() map[int]bool {
 			flag = true
 		}
 		m[NLRIMessageType(afi, safi)] = flag
-		if glog.V(6) {
+		if logging.V(logging.BGP, 6) {
 			glog.Infof("AddPath Capability for AFI/SAFI: %d/%d is %t", afi, safi, flag)
 		}
 	}
@@ -79,6 +80,37 @@ func (o *OpenMessage) AddPathCapability() map[int]bool {
 	return m
 }
 
+// ExtendedNextHopCapability returns a map of NLRI types for which the speaker supports IPv6 next hop
+// advertised with Extended Next Hop Encoding capability https://tools.ietf.org/html/rfc8950#section-4
+func (o *OpenMessage) ExtendedNextHopCapability() map[int]bool {
+	m := make(map[int]bool)
+	v, ok := o.Capabilities[5]
+	if !ok {
+		return m
+	}
+	for _, c := range v {
+		// Each entry is NLRI AFI (2 bytes), NLRI SAFI (2 bytes) and Next Hop AFI (2 bytes)
+		if len(c.Value)%6 != 0 {
+			glog.Errorf("invalid length of Extended Next Hop Encoding capability %d", len(c.Value))
+			continue
+		}
+		for p := 0; p < len(c.Value); p += 6 {
+			afi := binary.BigEndian.Uint16(c.Value[p : p+2])
+			safi := binary.BigEndian.Uint16(c.Value[p+2 : p+4])
+			nhAFI := binary.BigEndian.Uint16(c.Value[p+4 : p+6])
+			if nhAFI != 2 || safi > 0xff {
+				continue
+			}
+			// Skipping AFI/SAFI not known to gobmp
+			if t := NLRIMessageType(afi, uint8(safi)); t != 0 {
+				m[t] = true
+			}
+		}
+	}
+
+	return m
+}
+
 // IsMultiLabelCapable returns true or false if Open message originated by a bgp speaker
 // supporting Multiple Label Capability
 func (o *OpenMessage) IsMultiLabelCapable() bool {
@@ -91,7 +123,7 @@ func (o *OpenMessage) IsMultiLabelCapable() bool {
 
 // UnmarshalBGPOpenMessage validate information passed in byte slice and returns BGPOpenMessage object
 func UnmarshalBGPOpenMessage(b []byte) (*OpenMessage, error) {
-	if glog.V(6) {
+	if logging.V(logging.BGP, 6) {
 		glog.Infof("BGPOpenMessage Raw: %s", tools.MessageHex(b))
 	}
 	if len(b) < BGPMinOpenMessageLength-16 {
@@ -130,3 +162,37 @@ func UnmarshalBGPOpenMessage(b []byte) (*OpenMessage, error) {
 
 	return &m, nil
 }
+
+// Serialize generates a slice of bytes from OpenMessage structure including BGP message header,
+// Capabilities are carried in a single Capabilities Optional Parameter.
+func (o *OpenMessage) Serialize() ([]byte, error) {
+	if len(o.BGPID) != 4 {
+		return nil, fmt.Errorf("invalid length %d of BGP ID", len(o.BGPID))
+	}
+	params := make([]byte, 0)
+	for _, tlv := range o.OptionalParameters {
+		params = append(params, tlv.Type, byte(len(tlv.Value)))
+		params = append(params, tlv.Value...)
+	}
+	if len(o.Capabilities) != 0 {
+		caps := o.Capabilities.Serialize()
+		if len(caps) > 255 {
+			return nil, fmt.Errorf("capabilities length %d exceeds 255 bytes", len(caps))
+		}
+		params = append(params, 2, byte(len(caps)))
+		params = append(params, caps...)
+	}
+	if len(params) > 255 {
+		return nil, fmt.Errorf("optional parameters length %d exceeds 255 bytes", len(params))
+	}
+	b := serializeHeader(1, 10+len(params))
+	b = append(b, 4)
+	v := make([]byte, 4)
+	binary.BigEndian.PutUint16(v[0:2], o.MyAS)
+	binary.BigEndian.PutUint16(v[2:4], uint16(o.HoldTime))
+	b = append(b, v...)
+	b = append(b, o.BGPID...)
+	b = append(b, byte(len(params)))
+
+	return append(b, params...), nil
+}
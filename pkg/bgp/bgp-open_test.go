@@ -30,14 +30,17 @@ func TestUnmarshalBGPOpenMessage(t *testing.T) {
 						{
 							Description: "Multiprotocol Extensions for BGP-4 : afi=1 safi=1 Unicast IPv4",
 							Value:       []byte{0, 1, 0, 1},
+							Parameters:  &CapabilityParameters{AFI: 1, SAFI: 1},
 						},
 						{
 							Description: "Multiprotocol Extensions for BGP-4 : afi=1 safi=4 MPLS Labels IPv4",
 							Value:       []byte{0, 1, 0, 4},
+							Parameters:  &CapabilityParameters{AFI: 1, SAFI: 4},
 						},
 						{
 							Description: "Multiprotocol Extensions for BGP-4 : afi=1 safi=128 MPLS-labeled VPN IPv4",
 							Value:       []byte{0, 1, 0, 128},
+							Parameters:  &CapabilityParameters{AFI: 1, SAFI: 128},
 						},
 					},
 					2: []*CapabilityData{
@@ -50,18 +53,21 @@ func TestUnmarshalBGPOpenMessage(t *testing.T) {
 						{
 							Description: "Extended Next Hop Encoding",
 							Value:       []byte{0, 1, 0, 1, 0, 2, 0, 1, 0, 2, 0, 2, 0, 1, 0, 128, 0, 2},
+							Parameters:  &CapabilityParameters{ExtendedNextHop: []*ExtendedNextHopTuple{{AFI: 1, SAFI: 1, NextHopAFI: 2}, {AFI: 1, SAFI: 2, NextHopAFI: 2}, {AFI: 1, SAFI: 128, NextHopAFI: 2}}},
 						},
 					},
 					65: []*CapabilityData{
 						{
 							Description: "Support for 4-octet AS number capability",
 							Value:       []byte{0, 0, 19, 206},
+							Parameters:  &CapabilityParameters{ASN: 5070},
 						},
 					},
 					69: []*CapabilityData{
 						{
 							Description: "ADD-PATH Capability",
 							Value:       []byte{1, 0, 134, 3},
+							Parameters:  &CapabilityParameters{AddPath: []*AddPathTuple{{AFI: 256, SAFI: 134, Mode: "send_receive"}}},
 						},
 					},
 					128: []*CapabilityData{
@@ -153,3 +159,40 @@ func TestAddPathCapability(t *testing.T) {
 		})
 	}
 }
+
+func TestExtendedNextHopCapability(t *testing.T) {
+	tests := []struct {
+		name       string
+		openMsgRaw []byte
+		expect     map[int]bool
+	}{
+		{
+			name: "ipv4 unicast, multicast and vpnv4 with ipv6 next hop",
+			// Extended Next Hop Encoding capability 1/1/2, 1/2/2 and 1/128/2
+			openMsgRaw: []byte{0x00, 0x7B, 0x01, 0x04, 0x5B, 0xA0, 0x00, 0xB4, 0x0A, 0x00, 0x00, 0x0A, 0x5E, 0x02, 0x06, 0x01, 0x04, 0x00, 0x01, 0x00, 0x01, 0x02, 0x06, 0x01, 0x04, 0x00, 0x01, 0x00, 0x04, 0x02, 0x06, 0x01, 0x04, 0x00, 0x01, 0x00, 0x80, 0x02, 0x06, 0x01, 0x04, 0x00, 0x02, 0x00, 0x80, 0x02, 0x06, 0x01, 0x04, 0x00, 0x01, 0x00, 0x49, 0x02, 0x02, 0x80, 0x00, 0x02, 0x02, 0x02, 0x00, 0x02, 0x06, 0x41, 0x04, 0x00, 0x01, 0x86, 0xA0, 0x02, 0x0E, 0x45, 0x0C, 0x00, 0x01, 0x01, 0x01, 0x00, 0x01, 0x04, 0x01, 0x00, 0x01, 0x80, 0x03, 0x02, 0x14, 0x05, 0x12, 0x00, 0x01, 0x00, 0x01, 0x00, 0x02, 0x00, 0x01, 0x00, 0x02, 0x00, 0x02, 0x00, 0x01, 0x00, 0x80, 0x00, 0x02},
+			expect: map[int]bool{
+				NLRIMessageType(1, 1):   true,
+				NLRIMessageType(1, 2):   true,
+				NLRIMessageType(1, 128): true,
+			},
+		},
+		{
+			name:       "no capability",
+			openMsgRaw: []byte{0x00, 0x1D, 0x01, 0x04, 0x5B, 0xA0, 0x00, 0xB4, 0x0A, 0x00, 0x00, 0x0A, 0x00},
+			expect:     map[int]bool{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			om, err := UnmarshalBGPOpenMessage(tt.openMsgRaw)
+			if err != nil {
+				t.Fatalf("expected to succeed but failed with error: %+v", err)
+			}
+			enh := om.ExtendedNextHopCapability()
+			if !reflect.DeepEqual(enh, tt.expect) {
+				t.Logf("Diffs: %+v", deep.Equal(enh, tt.expect))
+				t.Fatal("extended next hop capability does not match expected")
+			}
+		})
+	}
+}
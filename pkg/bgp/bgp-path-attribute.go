@@ -4,6 +4,7 @@ import (
 	"encoding/binary"
 
 	"github.com/golang/glog"
+	"github.com/sbezverk/gobmp/pkg/logging"
 	"github.com/sbezverk/tools"
 )
 
@@ -17,7 +18,7 @@ type PathAttribute struct {
 
 // UnmarshalBGPPathAttributes builds BGP Path attributes slice
 func UnmarshalBGPPathAttributes(b []byte) ([]PathAttribute, error) {
-	if glog.V(6) {
+	if logging.V(logging.BGP, 6) {
 		glog.Infof("BGPPathAttributes Raw: %s", tools.MessageHex(b))
 	}
 	attrs := make([]PathAttribute, 0)
@@ -49,3 +50,22 @@ func UnmarshalBGPPathAttributes(b []byte) ([]PathAttribute, error) {
 
 	return attrs, nil
 }
+
+// Serialize generates a slice of bytes from PathAttribute structure, Extended Length flag
+// is set when the attribute does not fit into 255 bytes.
+func (pa *PathAttribute) Serialize() []byte {
+	f := pa.AttributeTypeFlags &^ 0x10
+	if len(pa.Attribute) > 255 {
+		f |= 0x10
+	}
+	b := []byte{f, pa.AttributeType}
+	if f&0x10 == 0x10 {
+		l := make([]byte, 2)
+		binary.BigEndian.PutUint16(l, uint16(len(pa.Attribute)))
+		b = append(b, l...)
+	} else {
+		b = append(b, byte(len(pa.Attribute)))
+	}
+
+	return append(b, pa.Attribute...)
+}
@@ -8,6 +8,7 @@ import (
 
 	"github.com/golang/glog"
 	"github.com/sbezverk/gobmp/pkg/bgpls"
+	"github.com/sbezverk/gobmp/pkg/logging"
 	"github.com/sbezverk/gobmp/pkg/prefixsid"
 	"github.com/sbezverk/tools"
 )
@@ -18,6 +19,25 @@ const (
 	BGP4_NLRI       = 0
 )
 
+const (
+	// BGPHeaderLength defines the length of BGP message header, 16 bytes Marker, 2 bytes Length and 1 byte Type
+	BGPHeaderLength = 19
+	// BGPMaxMessageLength defines the maximum length of BGP message
+	BGPMaxMessageLength = 4096
+)
+
+// serializeHeader generates BGP message header of a specified type for the payload of length l
+func serializeHeader(t byte, l int) []byte {
+	b := make([]byte, BGPHeaderLength)
+	for i := 0; i < 16; i++ {
+		b[i] = 0xff
+	}
+	binary.BigEndian.PutUint16(b[16:18], uint16(BGPHeaderLength+l))
+	b[18] = t
+
+	return b
+}
+
 // Update defines a structure of BGP Update message
 type Update struct {
 	WithdrawnRoutesLength    uint16
@@ -26,6 +46,8 @@ type Update struct {
 	PathAttributes           []PathAttribute
 	NLRI                     []byte
 	BaseAttributes           *BaseAttributes
+	// Validation carries the result of semantic validation of the update when it was requested
+	Validation *UpdateValidation
 }
 
 // GetAllAttributeID return a slixe of int with all attributes found in BGP Update
@@ -109,18 +131,27 @@ func (up *Update) GetNLRIType() (uint8, int) {
 
 // UnmarshalBGPUpdate build BGP Update object from the byte slice provided
 func UnmarshalBGPUpdate(b []byte) (*Update, error) {
-	if glog.V(6) {
+	if logging.V(logging.BGP, 6) {
 		glog.Infof("BGPUpdate Raw: %s", tools.MessageHex(b))
 	}
 	p := 0
 	u := Update{}
+	if len(b) < 4 {
+		return nil, fmt.Errorf("invalid length %d of BGP Update", len(b))
+	}
 	u.WithdrawnRoutesLength = binary.BigEndian.Uint16(b[p : p+2])
 	p += 2
+	if p+int(u.WithdrawnRoutesLength)+2 > len(b) {
+		return nil, fmt.Errorf("withdrawn routes length %d exceeds BGP Update length %d", u.WithdrawnRoutesLength, len(b))
+	}
 	u.WithdrawnRoutes = make([]byte, u.WithdrawnRoutesLength)
 	copy(u.WithdrawnRoutes, b[p:p+int(u.WithdrawnRoutesLength)])
 	p += int(u.WithdrawnRoutesLength)
 	u.TotalPathAttributeLength = binary.BigEndian.Uint16(b[p : p+2])
 	p += 2
+	if p+int(u.TotalPathAttributeLength) > len(b) {
+		return nil, fmt.Errorf("total path attribute length %d exceeds BGP Update length %d", u.TotalPathAttributeLength, len(b))
+	}
 	attrs, err := UnmarshalBGPPathAttributes(b[p : p+int(u.TotalPathAttributeLength)])
 	if err != nil {
 		return nil, err
@@ -138,3 +169,26 @@ func UnmarshalBGPUpdate(b []byte) (*Update, error) {
 
 	return &u, nil
 }
+
+// Serialize generates a slice of bytes from Update structure including BGP message header
+func (up *Update) Serialize() ([]byte, error) {
+	attrs := make([]byte, 0)
+	for _, attr := range up.PathAttributes {
+		attrs = append(attrs, attr.Serialize()...)
+	}
+	l := 2 + len(up.WithdrawnRoutes) + 2 + len(attrs) + len(up.NLRI)
+	if BGPHeaderLength+l > BGPMaxMessageLength {
+		return nil, fmt.Errorf("BGP Update length %d exceeds maximum message length", BGPHeaderLength+l)
+	}
+	b := serializeHeader(2, l)
+	wl := make([]byte, 2)
+	binary.BigEndian.PutUint16(wl, uint16(len(up.WithdrawnRoutes)))
+	b = append(b, wl...)
+	b = append(b, up.WithdrawnRoutes...)
+	al := make([]byte, 2)
+	binary.BigEndian.PutUint16(al, uint16(len(attrs)))
+	b = append(b, al...)
+	b = append(b, attrs...)
+
+	return append(b, up.NLRI...), nil
+}
@@ -24,7 +24,11 @@ func TestUnmarshalBGPUpdate(t *testing.T) {
 					BaseAttrHash: "3b87061fdf773278959113c6f010f24c",
 					ASPath:       []uint32{65001, 65003},
 					ASPathCount:  2,
-					Origin:       "incomplete",
+					OriginAS:     65003,
+					ASPathSegments: []ASPathSegment{
+						{Type: "as_sequence", ASN: []uint32{65001, 65003}},
+					},
+					Origin: "incomplete",
 				},
 				PathAttributes: []PathAttribute{
 					{
@@ -70,3 +74,39 @@ func TestUnmarshalBGPUpdate(t *testing.T) {
 		})
 	}
 }
+
+func TestSerializeBGPUpdate(t *testing.T) {
+	input := []byte{0x00, 0x00, 0x00, 0x2C, 0x40, 0x01, 0x01, 0x02, 0x40, 0x02, 0x0A, 0x02, 0x02, 0x00, 0x00, 0xFD, 0xE9, 0x00, 0x00, 0xFD, 0xEB, 0x80, 0x0E, 0x18, 0x00, 0x02, 0x01, 0x10, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0xFF, 0xFF, 0x0A, 0x98, 0xB7, 0x0B, 0x00, 0x10, 0x20, 0x01}
+	u, err := UnmarshalBGPUpdate(input)
+	if err != nil {
+		t.Fatalf("failed to unmarshal BGP Update with error: %+v", err)
+	}
+	b, err := u.Serialize()
+	if err != nil {
+		t.Fatalf("failed to serialize BGP Update with error: %+v", err)
+	}
+	if len(b) != BGPHeaderLength+len(input) || b[18] != 2 {
+		t.Fatalf("invalid BGP Update header %+v", b[:BGPHeaderLength])
+	}
+	if !reflect.DeepEqual(input, b[BGPHeaderLength:]) {
+		t.Fatalf("expected %+v does not match serialized %+v", input, b[BGPHeaderLength:])
+	}
+}
+
+func TestUnmarshalMalformedBGPUpdate(t *testing.T) {
+	tests := []struct {
+		name  string
+		input []byte
+	}{
+		{name: "short", input: []byte{0x00, 0x00, 0x00}},
+		{name: "withdrawn routes length", input: []byte{0x00, 0x05, 0x18, 0x0a, 0x00, 0x00, 0x00}},
+		{name: "total path attribute length", input: []byte{0x00, 0x00, 0x00, 0xff}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := UnmarshalBGPUpdate(tt.input); err == nil {
+				t.Fatalf("expected to fail but succeeded")
+			}
+		})
+	}
+}
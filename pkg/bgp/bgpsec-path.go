@@ -0,0 +1,123 @@
+package bgp
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+)
+
+const (
+	bgpsecSegmentLength = 6
+	bgpsecSKILength     = 20
+	// bgpsecConfedSegmentFlag marks Secure_Path Segment added by a member of AS Confederation
+	bgpsecConfedSegmentFlag = 0x80
+)
+
+// BGPsecPath defines BGPsec_Path attribute https://tools.ietf.org/html/rfc8205#section-3
+type BGPsecPath struct {
+	SecurePath      []BGPsecSegment        `json:"secure_path,omitempty"`
+	SignatureBlocks []BGPsecSignatureBlock `json:"signature_blocks,omitempty"`
+}
+
+// BGPsecSegment defines Secure_Path Segment
+type BGPsecSegment struct {
+	PCount        uint8  `json:"pcount"`
+	ConfedSegment bool   `json:"confed_segment,omitempty"`
+	AS            uint32 `json:"as"`
+}
+
+// BGPsecSignatureBlock defines Signature_Block, a block carries signatures produced with
+// a single Algorithm Suite.
+type BGPsecSignatureBlock struct {
+	AlgorithmSuiteID uint8             `json:"algorithm_suite_id"`
+	Signatures       []BGPsecSignature `json:"signatures,omitempty"`
+}
+
+// BGPsecSignature defines Signature Segment, Subject Key Identifier and Signature are hex encoded
+type BGPsecSignature struct {
+	SKI       string `json:"ski"`
+	Signature string `json:"signature"`
+}
+
+// UnmarshalBGPsecPath builds BGPsecPath object from BGPsec_Path attribute value
+func UnmarshalBGPsecPath(b []byte) (*BGPsecPath, error) {
+	if len(b) < 2 {
+		return nil, fmt.Errorf("invalid length %d of bgpsec_path attribute", len(b))
+	}
+	bp := &BGPsecPath{
+		SecurePath:      make([]BGPsecSegment, 0),
+		SignatureBlocks: make([]BGPsecSignatureBlock, 0),
+	}
+	// Secure_Path Length includes the length field itself
+	l := int(binary.BigEndian.Uint16(b[0:2]))
+	if l < 2 || l > len(b) || (l-2)%bgpsecSegmentLength != 0 {
+		return nil, fmt.Errorf("invalid secure_path length %d", l)
+	}
+	for p := 2; p < l; p += bgpsecSegmentLength {
+		bp.SecurePath = append(bp.SecurePath, BGPsecSegment{
+			PCount:        b[p],
+			ConfedSegment: b[p+1]&bgpsecConfedSegmentFlag == bgpsecConfedSegmentFlag,
+			AS:            binary.BigEndian.Uint32(b[p+2 : p+6]),
+		})
+	}
+	for p := l; p < len(b); {
+		if p+3 > len(b) {
+			return nil, fmt.Errorf("not enough bytes to unmarshal signature_block")
+		}
+		// Signature_Block Length includes the length field itself
+		bl := int(binary.BigEndian.Uint16(b[p : p+2]))
+		if bl < 3 || p+bl > len(b) {
+			return nil, fmt.Errorf("invalid signature_block length %d", bl)
+		}
+		sb, err := unmarshalBGPsecSignatureBlock(b[p+2 : p+bl])
+		if err != nil {
+			return nil, err
+		}
+		bp.SignatureBlocks = append(bp.SignatureBlocks, *sb)
+		p += bl
+	}
+
+	return bp, nil
+}
+
+func unmarshalBGPsecSignatureBlock(b []byte) (*BGPsecSignatureBlock, error) {
+	sb := &BGPsecSignatureBlock{
+		AlgorithmSuiteID: b[0],
+		Signatures:       make([]BGPsecSignature, 0),
+	}
+	for p := 1; p < len(b); {
+		if p+bgpsecSKILength+2 > len(b) {
+			return nil, fmt.Errorf("not enough bytes to unmarshal signature segment")
+		}
+		ski := b[p : p+bgpsecSKILength]
+		p += bgpsecSKILength
+		sl := int(binary.BigEndian.Uint16(b[p : p+2]))
+		p += 2
+		if p+sl > len(b) {
+			return nil, fmt.Errorf("invalid signature length %d", sl)
+		}
+		sb.Signatures = append(sb.Signatures, BGPsecSignature{
+			SKI:       hex.EncodeToString(ski),
+			Signature: hex.EncodeToString(b[p : p+sl]),
+		})
+		p += sl
+	}
+
+	return sb, nil
+}
+
+// ASPath returns the list of ASes of Secure_Path, each AS is repeated pCount times as it would
+// appear in AS_PATH, confederation segments are skipped.
+func (bp *BGPsecPath) ASPath() []uint32 {
+	path := make([]uint32, 0, len(bp.SecurePath))
+	for _, s := range bp.SecurePath {
+		if s.ConfedSegment {
+			continue
+		}
+		for i := 0; i < int(s.PCount); i++ {
+			path = append(path, s.AS)
+		}
+	}
+
+	return path
+}
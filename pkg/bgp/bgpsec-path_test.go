@@ -0,0 +1,87 @@
+package bgp
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/go-test/deep"
+)
+
+func TestUnmarshalBGPsecPath(t *testing.T) {
+	ski := []byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 18, 19, 20}
+	tests := []struct {
+		name   string
+		input  []byte
+		expect *BGPsecPath
+		asPath []uint32
+		fail   bool
+	}{
+		{
+			name: "two segments one signature block",
+			input: append(append([]byte{
+				// Secure_Path length 14, pCount 1 AS 65001, pCount 2 AS 65002
+				0x00, 0x0e, 0x01, 0x00, 0x00, 0x00, 0xfd, 0xe9, 0x02, 0x00, 0x00, 0x00, 0xfd, 0xea,
+				// Signature_Block length 28, Algorithm Suite 1
+				0x00, 0x1c, 0x01,
+			}, ski...), 0x00, 0x03, 0xaa, 0xbb, 0xcc),
+			expect: &BGPsecPath{
+				SecurePath: []BGPsecSegment{
+					{PCount: 1, AS: 65001},
+					{PCount: 2, AS: 65002},
+				},
+				SignatureBlocks: []BGPsecSignatureBlock{
+					{
+						AlgorithmSuiteID: 1,
+						Signatures: []BGPsecSignature{
+							{SKI: "0102030405060708090a0b0c0d0e0f1011121314", Signature: "aabbcc"},
+						},
+					},
+				},
+			},
+			asPath: []uint32{65001, 65002, 65002},
+		},
+		{
+			name: "confederation segment",
+			// Secure_Path length 8, pCount 1 confed flag AS 64512
+			input: []byte{0x00, 0x08, 0x01, 0x80, 0x00, 0x00, 0xfc, 0x00},
+			expect: &BGPsecPath{
+				SecurePath: []BGPsecSegment{
+					{PCount: 1, ConfedSegment: true, AS: 64512},
+				},
+				SignatureBlocks: []BGPsecSignatureBlock{},
+			},
+			asPath: []uint32{},
+		},
+		{
+			name:  "invalid secure_path length",
+			input: []byte{0x00, 0x07, 0x01, 0x00, 0x00, 0x00, 0xfd},
+			fail:  true,
+		},
+		{
+			name:  "truncated signature",
+			input: append(append([]byte{0x00, 0x02, 0x00, 0x1c, 0x01}, ski...), 0x00, 0x03, 0xaa),
+			fail:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bp, err := UnmarshalBGPsecPath(tt.input)
+			if err != nil && !tt.fail {
+				t.Fatalf("expected to succeed but failed with error: %+v", err)
+			}
+			if err == nil && tt.fail {
+				t.Fatalf("expected to fail but succeeded")
+			}
+			if err != nil {
+				return
+			}
+			if !reflect.DeepEqual(tt.expect, bp) {
+				t.Logf("Differences: %+v", deep.Equal(tt.expect, bp))
+				t.Fatalf("expected bgpsec_path does not match computed")
+			}
+			if !reflect.DeepEqual(tt.asPath, bp.ASPath()) {
+				t.Fatalf("expected as path %v, got %v", tt.asPath, bp.ASPath())
+			}
+		})
+	}
+}
@@ -100,6 +100,11 @@ const (
 	// ECPServiceCarvingTimestamp extended community prefix for Service Carving Timestamp [draft-ietf-bess-evpn-fast-df-recovery-01]
 	ECPServiceCarvingTimestamp = "sct="
 
+	// BGP-MUP Extended Community Sub-Types
+
+	// ECPMUPDirectSegmentID extended community prefix for Direct-Type Segment Identifier	[draft-mpmz-bess-mup-safi]
+	ECPMUPDirectSegmentID = "mup="
+
 	// Non-Transitive Two-Octet AS-Specific Extended Community Sub-Types
 
 	//ECPLinkBandwidth extended community prefix for Link Bandwidth Extended Community	[draft-ietf-idr-link-bandwidth-00]
@@ -4,9 +4,10 @@ import (
 	"encoding/binary"
 	"fmt"
 	"math"
-	"net"
 
 	"github.com/golang/glog"
+	"github.com/sbezverk/gobmp/pkg/addr"
+	"github.com/sbezverk/gobmp/pkg/logging"
 	"github.com/sbezverk/tools"
 )
 
@@ -49,6 +50,8 @@ func makeExtCommunity(b []byte) (*ExtCommunity, error) {
 	case 2:
 		fallthrough
 	case 6:
+		fallthrough
+	case 0xc:
 		st := uint8(b[p])
 		ext.SubType = &st
 		l = 6
@@ -69,7 +72,7 @@ func makeExtCommunity(b []byte) (*ExtCommunity, error) {
 func UnmarshalBGPExtCommunity(b []byte) ([]ExtCommunity, error) {
 	exts := make([]ExtCommunity, 0)
 	for p := 0; p < len(b); {
-		if glog.V(6) {
+		if logging.V(logging.BGP, 6) {
 			glog.Infof("Extended community: %s", tools.MessageHex(b[p:p+8]))
 		}
 		ext, err := makeExtCommunity(b[p : p+8])
@@ -219,6 +222,12 @@ var evpnSubTypes = map[uint8]string{
 	0xf: ECPServiceCarvingTimestamp,
 }
 
+// BGP-MUP Extended Community Sub-Types
+// 0x00	Direct-Type Segment Identifier	[draft-mpmz-bess-mup-safi]
+var mupSubTypes = map[uint8]string{
+	0x0: ECPMUPDirectSegmentID,
+}
+
 // Non-Transitive Two-Octet AS-Specific Extended Community Sub-Types
 // 0x04	Link Bandwidth Extended Community	[draft-ietf-idr-link-bandwidth-00]
 // 0x80	Virtual-Network Identifier Extended Community	[draft-drao-bgp-l3vpn-virtual-network-overlays]
@@ -255,7 +264,7 @@ func type0(subType uint8, value []byte) string {
 
 // Transitive IPv4 Specific Extended Community
 func type1(subType uint8, value []byte) string {
-	return getSubType(transIPv4SubTypes, subType) + fmt.Sprintf("%s:%d", net.IP(value[0:4]).To4().String(), binary.BigEndian.Uint16(value[4:]))
+	return getSubType(transIPv4SubTypes, subType) + fmt.Sprintf("%s:%d", addr.IPv4(value[0:4]), binary.BigEndian.Uint16(value[4:]))
 }
 
 // Transitive Four-Octet AS-Specific Extended Community
@@ -305,6 +314,19 @@ func type6(subType uint8, value []byte) string {
 	return getSubType(evpnSubTypes, subType) + s
 }
 
+// BGP-MUP Extended Community
+func typeC(subType uint8, value []byte) string {
+	var s string
+	switch subType {
+	case 0x00:
+		s = fmt.Sprintf("%d:%d", binary.BigEndian.Uint16(value[0:2]), binary.BigEndian.Uint32(value[2:]))
+	default:
+		s = tools.MessageHex(value)
+	}
+
+	return getSubType(mupSubTypes, subType) + s
+}
+
 // 0x08 Flow spec redirect/mirror to IP next-hop [draft-simpson-idr-flowspec-redirect] 2012-09-28
 func type8(subType uint8, value []byte) string {
 	return ECPFlowspec + "redirect_to_ip_next_hop"
@@ -355,7 +377,7 @@ func type81(subType uint8, value []byte) string {
 	if len(value) == 6 {
 		switch subType {
 		case 0x08:
-			s = fmt.Sprintf("%s:%d", net.IP(value[0:4]).To4().String(), binary.BigEndian.Uint16(value[4:]))
+			s = fmt.Sprintf("%s:%d", addr.IPv4(value[0:4]), binary.BigEndian.Uint16(value[4:]))
 		default:
 			s = tools.MessageHex(value)
 		}
@@ -389,6 +411,7 @@ var extComm = map[uint8]func(uint8, []byte) string{
 	0x3:  type3,
 	0x6:  type6,
 	0x8:  type8,
+	0xc:  typeC,
 	0x40: type40,
 	0x80: type80,
 	0x81: type81,
@@ -31,6 +31,11 @@ func TestExtendedCommunity(t *testing.T) {
 			input:  []byte{0x06, 0x03, 0x0c, 0x03, 0x00, 0x00, 0x1b, 0x08},
 			expect: "rmac=0C:03:00:00:1B:08",
 		},
+		{
+			name:   "type c mup direct segment id",
+			input:  []byte{0x0c, 0x00, 0x00, 0x0a, 0x00, 0x00, 0x00, 0x0a},
+			expect: "mup=10:10",
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
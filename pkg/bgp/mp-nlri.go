@@ -5,6 +5,8 @@ import (
 	"github.com/sbezverk/gobmp/pkg/evpn"
 	"github.com/sbezverk/gobmp/pkg/flowspec"
 	"github.com/sbezverk/gobmp/pkg/ls"
+	"github.com/sbezverk/gobmp/pkg/mup"
+	"github.com/sbezverk/gobmp/pkg/rtc"
 	"github.com/sbezverk/gobmp/pkg/srpolicy"
 )
 
@@ -18,7 +20,12 @@ type MPNLRI interface {
 	GetNLRI71() (*ls.NLRI71, error)
 	GetNLRI73() (*srpolicy.NLRI73, error)
 	GetFlowspecNLRI() (*flowspec.NLRI, error)
+	GetNLRIRTC() (*rtc.NLRI, error)
+	GetNLRIMUP() (*mup.NLRI, error)
 	GetNextHop() string
+	GetNextHopOriginal() string
+	GetNextHopLinkLocal() string
+	GetNextHopAFI() uint16
 	IsIPv6NLRI() bool
 	IsNextHopIPv6() bool
 }
@@ -30,12 +37,21 @@ func NLRIMessageType(afi uint16, safi uint8) int {
 	// 16388 BGP-LS	[RFC7752] : 71	BGP-LS	[RFC7752]
 	case afi == 16388 && safi == 71:
 		return 71
+	// 16388 BGP-LS	[RFC7752] : 72	BGP-LS-VPN	[RFC7752]
+	case afi == 16388 && safi == 72:
+		return 72
 	// 1 IP (IP version 4) : 1 unicast forwarding
 	case afi == 1 && safi == 1:
 		return 1
 	// 2 IP6 (IP version 6) : 1 unicast forwarding
 	case afi == 2 && safi == 1:
 		return 2
+	// 1 IP (IP version 4) : 2 multicast forwarding
+	case afi == 1 && safi == 2:
+		return 3
+	// 2 IP6 (IP version 6) : 2 multicast forwarding
+	case afi == 2 && safi == 2:
+		return 4
 	// 1 IP (IP version 4) : 4 MPLS Labels
 	case afi == 1 && safi == 4:
 		return 16
@@ -72,6 +88,15 @@ func NLRIMessageType(afi uint16, safi uint8) int {
 		// AFI 2 and SAFI 134 FlowSpec VPNv6
 	case afi == 2 && safi == 134:
 		return 27
+		// AFI 1 and SAFI 132 Route Target Constraint
+	case afi == 1 && safi == 132:
+		return 28
+		// AFI 1 and SAFI 85 BGP-MUP IPv4
+	case afi == 1 && safi == 85:
+		return 29
+		// AFI 2 and SAFI 85 BGP-MUP IPv6
+	case afi == 2 && safi == 85:
+		return 30
 	}
 
 	return 0
@@ -6,11 +6,15 @@ import (
 	"net"
 
 	"github.com/golang/glog"
+	"github.com/sbezverk/gobmp/pkg/addr"
 	"github.com/sbezverk/gobmp/pkg/base"
 	"github.com/sbezverk/gobmp/pkg/evpn"
 	"github.com/sbezverk/gobmp/pkg/flowspec"
 	"github.com/sbezverk/gobmp/pkg/l3vpn"
+	"github.com/sbezverk/gobmp/pkg/logging"
 	"github.com/sbezverk/gobmp/pkg/ls"
+	"github.com/sbezverk/gobmp/pkg/mup"
+	"github.com/sbezverk/gobmp/pkg/rtc"
 	"github.com/sbezverk/gobmp/pkg/srpolicy"
 	"github.com/sbezverk/gobmp/pkg/unicast"
 	"github.com/sbezverk/tools"
@@ -39,58 +43,109 @@ func (mp *MPReachNLRI) IsIPv6NLRI() bool {
 	return mp.AddressFamilyID == 2
 }
 
-// IsNextHopIPv6 return true if the next hop is IPv6 address, otherwise it returns flase
+// IsNextHopIPv6 return true if the next hop is IPv6 address, otherwise it returns flase, IPv4-mapped IPv6
+// next hop is treated as IPv4 next hop.
 func (mp *MPReachNLRI) IsNextHopIPv6() bool {
 	// https://tools.ietf.org/id/draft-mishra-bess-ipv4nlri-ipv6nh-use-cases-00.html#rfc.section.3
+	return mp.ipv6NextHop() != nil && !mp.IsNextHopIPv4Mapped()
+}
+
+// ipv6NextHop returns global IPv6 next hop address without RD, or nil when the next hop is not IPv6.
+func (mp *MPReachNLRI) ipv6NextHop() net.IP {
 	switch mp.NextHopAddressLength {
-	case 16:
-		fallthrough
-	case 32:
-		fallthrough
-	case 24:
-		fallthrough
-	case 48:
-		return true
-	default:
-		return false
+	case 16, 32:
+		// IPv6 or IPv6 + Link Local IPv6
+		// https://tools.ietf.org/html/rfc2545#section-3
+		return net.IP(mp.NextHopAddress[:16])
+	case 24, 48:
+		// RD (8 bytes) + IPv6 or RD (8 bytes) + IPv6 + RD (8 bytes) + Link Local IPv6
+		// https://tools.ietf.org/html/rfc8950#section-3
+		return net.IP(mp.NextHopAddress[8:24])
 	}
+
+	return nil
+}
+
+// IsNextHopIPv4Mapped returns true if the next hop is IPv4-mapped IPv6 address, 6PE (RFC 4798) and 6VPE (RFC 4659)
+// use it to carry IPv4 next hop of labeled IPv6 and VPN-IPv6 routes over IPv4 core.
+func (mp *MPReachNLRI) IsNextHopIPv4Mapped() bool {
+	nh := mp.ipv6NextHop()
+
+	return nh != nil && nh.To4() != nil
 }
 
-// GetNextHop return a string representation of the next hop ip address.
+// GetNextHop return a string representation of the next hop ip address, when both global and link local
+// IPv6 next hops are present, the global one is returned. IPv4-mapped IPv6 next hop is returned as IPv4 address.
 func (mp *MPReachNLRI) GetNextHop() string {
 	switch mp.NextHopAddressLength {
 	case 4:
 		// IPv4
-		return net.IP(mp.NextHopAddress).To4().String()
+		return addr.IPv4(mp.NextHopAddress)
 	case 8:
 		// Peer 3 (Local-RIB) Next hop is 8 bytes RD 4 bytes and IPv4 address 4 bytes
-		return net.IP(mp.NextHopAddress[4:]).To4().String()
+		return addr.IPv4(mp.NextHopAddress[4:])
 	case 12:
 		// RD (8 bytes) + IPv4
-		return net.IP(mp.NextHopAddress[8:]).To4().String()
-	case 16:
-		// IPv6
-		return net.IP(mp.NextHopAddress).To16().String()
-	case 24:
-		// RD (8 bytes) + IPv6
-		return net.IP(mp.NextHopAddress[8:]).To16().String()
-	case 32:
-		// IPv6 + Link Local IPv6
-		// https://tools.ietf.org/html/rfc2545#section-3
-		return net.IP(mp.NextHopAddress[:16]).To16().String() + "," + net.IP(mp.NextHopAddress[16:]).To16().String()
+		return addr.IPv4(mp.NextHopAddress[8:])
+	}
+	if nh := mp.ipv6NextHop(); nh != nil {
+		if nh4 := nh.To4(); nh4 != nil {
+			return addr.IPv4(nh4)
+		}
+		return addr.IPv6(nh)
 	}
 
 	return "invalid"
 }
 
-// GetNLRI71 check for presense of NLRI 71 in the NLRI 14 NLRI data and if exists, instantiate NLRI71 object
-func (mp *MPReachNLRI) GetNLRI71() (*ls.NLRI71, error) {
-	if mp.SubAddressFamilyID == 71 {
-		nlri71, err := ls.UnmarshalLSNLRI71(mp.NLRI)
-		if err != nil {
-			return nil, err
+// GetNextHopOriginal returns a string representation of the next hop as it is encoded when it differs from
+// the one returned by GetNextHop, for IPv4-mapped IPv6 next hop ::ffff:a.b.c.d is returned, otherwise
+// empty string is returned.
+func (mp *MPReachNLRI) GetNextHopOriginal() string {
+	if !mp.IsNextHopIPv4Mapped() {
+		return ""
+	}
+
+	return "::ffff:" + mp.ipv6NextHop().To4().String()
+}
+
+// GetNextHopLinkLocal returns a string representation of link local IPv6 next hop, if next hop does not carry
+// link local address, empty string is returned.
+func (mp *MPReachNLRI) GetNextHopLinkLocal() string {
+	switch mp.NextHopAddressLength {
+	case 32:
+		return addr.IPv6(mp.NextHopAddress[16:])
+	case 48:
+		return addr.IPv6(mp.NextHopAddress[32:])
+	}
+
+	return ""
+}
+
+// GetNextHopAFI returns AFI of the next hop address, 1 for IPv4 and 2 for IPv6, IPv4 and VPN-IPv4 NLRIs
+// may carry IPv6 next hop when Extended Next Hop Encoding is negotiated https://tools.ietf.org/html/rfc8950
+// IPv4-mapped IPv6 next hop is IPv4 next hop.
+func (mp *MPReachNLRI) GetNextHopAFI() uint16 {
+	switch mp.NextHopAddressLength {
+	case 4, 8, 12:
+		return 1
+	case 16, 24, 32, 48:
+		if mp.IsNextHopIPv4Mapped() {
+			return 1
 		}
-		return nlri71, nil
+		return 2
+	}
+
+	return 0
+}
+
+// GetNLRI71 check for presense of NLRI 71 or BGP-LS-VPN NLRI 72 in the NLRI 14 NLRI data and if exists, instantiate NLRI71 object
+func (mp *MPReachNLRI) GetNLRI71() (*ls.NLRI71, error) {
+	switch mp.SubAddressFamilyID {
+	case 71:
+		return ls.UnmarshalLSNLRI71(mp.NLRI)
+	case 72:
+		return ls.UnmarshalLSVPNNLRI72(mp.NLRI)
 	}
 
 	// TODO return new type of errors to be able to check for the code
@@ -111,7 +166,7 @@ func (mp *MPReachNLRI) GetNLRI73() (*srpolicy.NLRI73, error) {
 	return nil, fmt.Errorf("not found")
 }
 
-// GetNLRIL3VPN check for presense of NLRI L3VPN AFI 1 and SAFI 128 in the NLRI 14 NLRI data and if exists, instantiate L3VPN object
+// GetNLRIL3VPN check for presense of NLRI L3VPN AFI 1 or 2 and SAFI 128 in the NLRI 14 NLRI data and if exists, instantiate L3VPN object
 func (mp *MPReachNLRI) GetNLRIL3VPN() (*base.MPNLRI, error) {
 	if (mp.AddressFamilyID == 1 || mp.AddressFamilyID == 2) && mp.SubAddressFamilyID == 128 {
 		pathID := mp.addPath[NLRIMessageType(mp.AddressFamilyID, mp.SubAddressFamilyID)]
@@ -140,9 +195,9 @@ func (mp *MPReachNLRI) GetNLRIEVPN() (*evpn.Route, error) {
 	return nil, fmt.Errorf("not found")
 }
 
-// GetNLRIUnicast check for presense of NLRI EVPN AFI 1 or 2  and SAFI 1 in the NLRI 14 NLRI data and if exists, instantiate Unicast object
+// GetNLRIUnicast check for presense of NLRI AFI 1 or 2 and SAFI 1 (unicast) or 2 (multicast) in the NLRI data and if exists, instantiate Unicast object
 func (mp *MPReachNLRI) GetNLRIUnicast() (*base.MPNLRI, error) {
-	if (mp.AddressFamilyID == 1 || mp.AddressFamilyID == 2) && mp.SubAddressFamilyID == 1 {
+	if (mp.AddressFamilyID == 1 || mp.AddressFamilyID == 2) && (mp.SubAddressFamilyID == 1 || mp.SubAddressFamilyID == 2) {
 		pathID := mp.addPath[NLRIMessageType(mp.AddressFamilyID, mp.SubAddressFamilyID)]
 		nlri, err := unicast.UnmarshalUnicastNLRI(mp.NLRI, pathID)
 		if err != nil {
@@ -182,7 +237,7 @@ func (mp *MPReachNLRI) GetFlowspecNLRI() (*flowspec.NLRI, error) {
 
 // UnmarshalMPReachNLRI builds MP Reach NLRI attributes
 func UnmarshalMPReachNLRI(b []byte, srv6 bool, addPath map[int]bool) (MPNLRI, error) {
-	if glog.V(6) {
+	if logging.V(logging.BGP, 6) {
 		glog.Infof("MPReachNLRI Raw: %s SRv6 flag: %t add path: %+v", tools.MessageHex(b), srv6, addPath)
 	}
 	if len(b) == 0 {
@@ -209,3 +264,33 @@ func UnmarshalMPReachNLRI(b []byte, srv6 bool, addPath map[int]bool) (MPNLRI, er
 
 	return &mp, nil
 }
+
+// GetNLRIRTC check for presense of NLRI Route Target Constraint AFI 1 and SAFI 132 in the NLRI 14 NLRI data and if exists, instantiate RTC object
+func (mp *MPReachNLRI) GetNLRIRTC() (*rtc.NLRI, error) {
+	if mp.AddressFamilyID == 1 && mp.SubAddressFamilyID == 132 {
+		pathID := mp.addPath[NLRIMessageType(mp.AddressFamilyID, mp.SubAddressFamilyID)]
+		nlri, err := rtc.UnmarshalRTCNLRI(mp.NLRI, pathID)
+		if err != nil {
+			return nil, err
+		}
+		return nlri, nil
+	}
+
+	// TODO return new type of errors to be able to check for the code
+	return nil, fmt.Errorf("not found")
+}
+
+// GetNLRIMUP check for presense of NLRI BGP-MUP AFI 1 or 2 and SAFI 85 in the NLRI 14 NLRI data and if exists, instantiate MUP object
+func (mp *MPReachNLRI) GetNLRIMUP() (*mup.NLRI, error) {
+	if (mp.AddressFamilyID == 1 || mp.AddressFamilyID == 2) && mp.SubAddressFamilyID == 85 {
+		pathID := mp.addPath[NLRIMessageType(mp.AddressFamilyID, mp.SubAddressFamilyID)]
+		nlri, err := mup.UnmarshalMUPNLRI(mp.NLRI, mp.AddressFamilyID == 2, pathID)
+		if err != nil {
+			return nil, err
+		}
+		return nlri, nil
+	}
+
+	// TODO return new type of errors to be able to check for the code
+	return nil, fmt.Errorf("not found")
+}
@@ -57,3 +57,93 @@ func TestUnmarshalMPReachNLRI(t *testing.T) {
 		})
 	}
 }
+
+func TestMPReachNLRINextHop(t *testing.T) {
+	global := []byte{0x20, 0x01, 0x0d, 0xb8, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0x01}
+	linkLocal := []byte{0xfe, 0x80, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0x01}
+	mapped := []byte{0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0xff, 0xff, 192, 0, 2, 1}
+	rd := make([]byte, 8)
+	tests := []struct {
+		name      string
+		mp        *MPReachNLRI
+		nexthop   string
+		linkLocal string
+		original  string
+		afi       uint16
+	}{
+		{
+			name:    "ipv4 unicast with ipv4 next hop",
+			mp:      &MPReachNLRI{AddressFamilyID: 1, SubAddressFamilyID: 1, NextHopAddress: []byte{192, 0, 2, 1}},
+			nexthop: "192.0.2.1",
+			afi:     1,
+		},
+		{
+			name:    "ipv4 unicast with ipv6 next hop",
+			mp:      &MPReachNLRI{AddressFamilyID: 1, SubAddressFamilyID: 1, NextHopAddress: global},
+			nexthop: "2001:db8::1",
+			afi:     2,
+		},
+		{
+			name:    "vpnv4 with ipv6 next hop",
+			mp:      &MPReachNLRI{AddressFamilyID: 1, SubAddressFamilyID: 128, NextHopAddress: append(append([]byte{}, rd...), global...)},
+			nexthop: "2001:db8::1",
+			afi:     2,
+		},
+		{
+			name:      "vpnv4 with ipv6 and link local next hops",
+			mp:        &MPReachNLRI{AddressFamilyID: 1, SubAddressFamilyID: 128, NextHopAddress: append(append(append(append([]byte{}, rd...), global...), rd...), linkLocal...)},
+			nexthop:   "2001:db8::1",
+			linkLocal: "fe80::1",
+			afi:       2,
+		},
+		{
+			name:      "ipv6 unicast with ipv6 and link local next hops",
+			mp:        &MPReachNLRI{AddressFamilyID: 2, SubAddressFamilyID: 1, NextHopAddress: append(append([]byte{}, global...), linkLocal...)},
+			nexthop:   "2001:db8::1",
+			linkLocal: "fe80::1",
+			afi:       2,
+		},
+		{
+			name:     "6pe labeled ipv6 with ipv4-mapped next hop",
+			mp:       &MPReachNLRI{AddressFamilyID: 2, SubAddressFamilyID: 4, NextHopAddress: mapped},
+			nexthop:  "192.0.2.1",
+			original: "::ffff:192.0.2.1",
+			afi:      1,
+		},
+		{
+			name:      "6pe labeled ipv6 with ipv4-mapped and link local next hops",
+			mp:        &MPReachNLRI{AddressFamilyID: 2, SubAddressFamilyID: 4, NextHopAddress: append(append([]byte{}, mapped...), linkLocal...)},
+			nexthop:   "192.0.2.1",
+			linkLocal: "fe80::1",
+			original:  "::ffff:192.0.2.1",
+			afi:       1,
+		},
+		{
+			name:     "6vpe vpnv6 with ipv4-mapped next hop",
+			mp:       &MPReachNLRI{AddressFamilyID: 2, SubAddressFamilyID: 128, NextHopAddress: append(append([]byte{}, rd...), mapped...)},
+			nexthop:  "192.0.2.1",
+			original: "::ffff:192.0.2.1",
+			afi:      1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mp.NextHopAddressLength = uint8(len(tt.mp.NextHopAddress))
+			if nh := tt.mp.GetNextHop(); nh != tt.nexthop {
+				t.Errorf("expected next hop %s, got %s", tt.nexthop, nh)
+			}
+			if ll := tt.mp.GetNextHopLinkLocal(); ll != tt.linkLocal {
+				t.Errorf("expected link local next hop %q, got %q", tt.linkLocal, ll)
+			}
+			if afi := tt.mp.GetNextHopAFI(); afi != tt.afi {
+				t.Errorf("expected next hop afi %d, got %d", tt.afi, afi)
+			}
+			if o := tt.mp.GetNextHopOriginal(); o != tt.original {
+				t.Errorf("expected original next hop %q, got %q", tt.original, o)
+			}
+			if v6 := tt.mp.IsNextHopIPv6(); v6 != (tt.afi == 2) {
+				t.Errorf("expected next hop ipv6 %t, got %t", tt.afi == 2, v6)
+			}
+		})
+	}
+}
@@ -9,7 +9,10 @@ import (
 	"github.com/sbezverk/gobmp/pkg/evpn"
 	"github.com/sbezverk/gobmp/pkg/flowspec"
 	"github.com/sbezverk/gobmp/pkg/l3vpn"
+	"github.com/sbezverk/gobmp/pkg/logging"
 	"github.com/sbezverk/gobmp/pkg/ls"
+	"github.com/sbezverk/gobmp/pkg/mup"
+	"github.com/sbezverk/gobmp/pkg/rtc"
 	"github.com/sbezverk/gobmp/pkg/srpolicy"
 	"github.com/sbezverk/gobmp/pkg/unicast"
 	"github.com/sbezverk/tools"
@@ -38,20 +41,36 @@ func (mp *MPUnReachNLRI) GetNextHop() string {
 	return ""
 }
 
+// GetNextHopOriginal returns a string representation of the next hop as it is encoded, MP_UNREACH_NLRI does not
+// carry Next Hop and empty string is returned.
+func (mp *MPUnReachNLRI) GetNextHopOriginal() string {
+	return ""
+}
+
+// GetNextHopLinkLocal returns a string representation of link local IPv6 next hop, MP_UNREACH_NLRI does not
+// carry Next Hop and empty string is returned.
+func (mp *MPUnReachNLRI) GetNextHopLinkLocal() string {
+	return ""
+}
+
+// GetNextHopAFI returns AFI of the next hop address, MP_UNREACH_NLRI does not carry Next Hop and 0 is returned.
+func (mp *MPUnReachNLRI) GetNextHopAFI() uint16 {
+	return 0
+}
+
 // IsNextHopIPv6 return true if the next hop is IPv6 address, otherwise it returns flase.
 // in case of MP_UNREACH_NLRI there is no Next Hope field and this func should not be used.
 func (mp *MPUnReachNLRI) IsNextHopIPv6() bool {
 	return false
 }
 
-// GetNLRI71 check for presense of NLRI 71 in the NLRI 14 NLRI data and if exists, instantiate NLRI71 object
+// GetNLRI71 check for presense of NLRI 71 or BGP-LS-VPN NLRI 72 in the NLRI 14 NLRI data and if exists, instantiate NLRI71 object
 func (mp *MPUnReachNLRI) GetNLRI71() (*ls.NLRI71, error) {
-	if mp.SubAddressFamilyID == 71 {
-		nlri71, err := ls.UnmarshalLSNLRI71(mp.WithdrawnRoutes)
-		if err != nil {
-			return nil, err
-		}
-		return nlri71, nil
+	switch mp.SubAddressFamilyID {
+	case 71:
+		return ls.UnmarshalLSNLRI71(mp.WithdrawnRoutes)
+	case 72:
+		return ls.UnmarshalLSVPNNLRI72(mp.WithdrawnRoutes)
 	}
 
 	// TODO return new type of errors to be able to check for the code
@@ -72,11 +91,11 @@ func (mp *MPUnReachNLRI) GetNLRI73() (*srpolicy.NLRI73, error) {
 	return nil, fmt.Errorf("not found")
 }
 
-// GetNLRIL3VPN check for presense of NLRI L3VPN AFI 1 and SAFI 128 in the NLRI 14 NLRI data and if exists, instantiate L3VPN object
+// GetNLRIL3VPN check for presense of NLRI L3VPN AFI 1 or 2 and SAFI 128 in the NLRI 15 NLRI data and if exists, instantiate L3VPN object
 func (mp *MPUnReachNLRI) GetNLRIL3VPN() (*base.MPNLRI, error) {
-	if mp.AddressFamilyID == 1 && mp.SubAddressFamilyID == 128 {
+	if (mp.AddressFamilyID == 1 || mp.AddressFamilyID == 2) && mp.SubAddressFamilyID == 128 {
 		pathID := mp.addPath[NLRIMessageType(mp.AddressFamilyID, mp.SubAddressFamilyID)]
-		nlri, err := l3vpn.UnmarshalL3VPNNLRI(mp.WithdrawnRoutes, pathID)
+		nlri, err := l3vpn.UnmarshalL3VPNWithdrawnNLRI(mp.WithdrawnRoutes, pathID)
 		if err != nil {
 			return nil, err
 		}
@@ -101,9 +120,9 @@ func (mp *MPUnReachNLRI) GetNLRIEVPN() (*evpn.Route, error) {
 	return nil, fmt.Errorf("not found")
 }
 
-// GetNLRIUnicast check for presense of NLRI EVPN AFI 1 or 2  and SAFI 1 in the NLRI 14 NLRI data and if exists, instantiate Unicast object
+// GetNLRIUnicast check for presense of NLRI AFI 1 or 2 and SAFI 1 (unicast) or 2 (multicast) in the NLRI data and if exists, instantiate Unicast object
 func (mp *MPUnReachNLRI) GetNLRIUnicast() (*base.MPNLRI, error) {
-	if (mp.AddressFamilyID == 1 || mp.AddressFamilyID == 2) && mp.SubAddressFamilyID == 1 {
+	if (mp.AddressFamilyID == 1 || mp.AddressFamilyID == 2) && (mp.SubAddressFamilyID == 1 || mp.SubAddressFamilyID == 2) {
 		pathID := mp.addPath[NLRIMessageType(mp.AddressFamilyID, mp.SubAddressFamilyID)]
 		nlri, err := unicast.UnmarshalUnicastNLRI(mp.WithdrawnRoutes, pathID)
 		if err != nil {
@@ -143,7 +162,7 @@ func (mp *MPUnReachNLRI) GetFlowspecNLRI() (*flowspec.NLRI, error) {
 
 // UnmarshalMPUnReachNLRI builds MP Reach NLRI attributes
 func UnmarshalMPUnReachNLRI(b []byte, addPath map[int]bool) (MPNLRI, error) {
-	if glog.V(6) {
+	if logging.V(logging.BGP, 6) {
 		glog.Infof("MPUnReachNLRI Raw: %s", tools.MessageHex(b))
 	}
 	if len(b) == 0 {
@@ -162,3 +181,33 @@ func UnmarshalMPUnReachNLRI(b []byte, addPath map[int]bool) (MPNLRI, error) {
 
 	return &mp, nil
 }
+
+// GetNLRIRTC check for presense of NLRI Route Target Constraint AFI 1 and SAFI 132 in the NLRI 15 NLRI data and if exists, instantiate RTC object
+func (mp *MPUnReachNLRI) GetNLRIRTC() (*rtc.NLRI, error) {
+	if mp.AddressFamilyID == 1 && mp.SubAddressFamilyID == 132 {
+		pathID := mp.addPath[NLRIMessageType(mp.AddressFamilyID, mp.SubAddressFamilyID)]
+		nlri, err := rtc.UnmarshalRTCNLRI(mp.WithdrawnRoutes, pathID)
+		if err != nil {
+			return nil, err
+		}
+		return nlri, nil
+	}
+
+	// TODO return new type of errors to be able to check for the code
+	return nil, fmt.Errorf("not found")
+}
+
+// GetNLRIMUP check for presense of NLRI BGP-MUP AFI 1 or 2 and SAFI 85 in the NLRI 15 NLRI data and if exists, instantiate MUP object
+func (mp *MPUnReachNLRI) GetNLRIMUP() (*mup.NLRI, error) {
+	if (mp.AddressFamilyID == 1 || mp.AddressFamilyID == 2) && mp.SubAddressFamilyID == 85 {
+		pathID := mp.addPath[NLRIMessageType(mp.AddressFamilyID, mp.SubAddressFamilyID)]
+		nlri, err := mup.UnmarshalMUPNLRI(mp.WithdrawnRoutes, mp.AddressFamilyID == 2, pathID)
+		if err != nil {
+			return nil, err
+		}
+		return nlri, nil
+	}
+
+	// TODO return new type of errors to be able to check for the code
+	return nil, fmt.Errorf("not found")
+}
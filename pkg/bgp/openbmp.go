@@ -0,0 +1,54 @@
+package bgp
+
+import (
+	"crypto/md5"
+	"encoding/binary"
+	"encoding/hex"
+	"strconv"
+	"strings"
+)
+
+// OpenBMPHash returns base_attr_hash of base attributes as calculated by OpenBMP collector, md5 of
+// as_path, next_hop, aggregator, origin, med, local_pref, community_list and ext_community_list in
+// OpenBMP text representation, followed by binary peer hash. peerHash is hex string of OpenBMP peer_hash.
+func (ba *BaseAttributes) OpenBMPHash(peerHash string) string {
+	h := md5.New()
+	h.Write([]byte(ba.openBMPASPath()))
+	h.Write([]byte(ba.Nexthop))
+	if ba.AggregatorAddress != "" {
+		h.Write([]byte(strconv.FormatUint(uint64(ba.AggregatorAS), 10) + " " + ba.AggregatorAddress))
+	}
+	h.Write([]byte(ba.Origin))
+	// MED and Local Preference are hashed as 32 bits integers in host, little endian, byte order
+	b := make([]byte, 4)
+	binary.LittleEndian.PutUint32(b, ba.MED)
+	h.Write(b)
+	binary.LittleEndian.PutUint32(b, ba.LocalPref)
+	h.Write(b)
+	h.Write([]byte(strings.Join(ba.CommunityList, " ")))
+	h.Write([]byte(strings.Join(ba.ExtCommunityList, " ")))
+	ph, _ := hex.DecodeString(peerHash)
+	h.Write(ph)
+
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// openBMPASPath returns AS_PATH in OpenBMP text representation, each AS is prefixed by a space,
+// ASes of AS_SET and AS_CONFED_SET segments are enclosed in braces.
+func (ba *BaseAttributes) openBMPASPath() string {
+	var s strings.Builder
+	for _, seg := range ba.ASPathSegments {
+		set := seg.Type == asPathSegmentTypes[ASSet] || seg.Type == asPathSegmentTypes[ASConfedSet]
+		if set {
+			s.WriteString(" {")
+		}
+		for _, as := range seg.ASN {
+			s.WriteString(" " + strconv.FormatUint(uint64(as), 10))
+		}
+		if set {
+			s.WriteString(" }")
+		}
+	}
+
+	return s.String()
+}
@@ -0,0 +1,50 @@
+package bgp
+
+import (
+	"testing"
+)
+
+// Expected values are calculated independently of gobmp by md5 of attributes concatenated in the order and
+// encoding OpenBMP collector uses.
+func TestOpenBMPHash(t *testing.T) {
+	peerHash := "cd024a93c02f19508b0627b524aa770e"
+	tests := []struct {
+		name   string
+		attrs  *BaseAttributes
+		expect string
+	}{
+		{
+			name: "all hashed attributes",
+			attrs: &BaseAttributes{
+				Origin: "igp",
+				ASPathSegments: []ASPathSegment{
+					{Type: "as_sequence", ASN: []uint32{65001, 65002}},
+					{Type: "as_set", ASN: []uint32{65003}},
+				},
+				Nexthop:           "192.0.2.1",
+				MED:               100,
+				LocalPref:         200,
+				AggregatorAS:      65003,
+				AggregatorAddress: "192.0.2.3",
+				CommunityList:     []string{"65001:1", "65001:2"},
+				ExtCommunityList:  []string{"rt=65001:100"},
+				// Attributes not hashed by OpenBMP
+				LgCommunityList: []string{"65001:1:1"},
+				OriginatorID:    "192.0.2.10",
+			},
+			expect: "3d3a00e9abd5fef39e916a32a5b34f2a",
+		},
+		{
+			name:   "origin only",
+			attrs:  &BaseAttributes{Origin: "igp"},
+			expect: "ad00f53330e96aa8db3cc969934bdbc0",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if h := tt.attrs.OpenBMPHash(peerHash); h != tt.expect {
+				t.Fatalf("expected base_attr_hash %s but got %s", tt.expect, h)
+			}
+		})
+	}
+}
@@ -0,0 +1,45 @@
+package bgp
+
+import "encoding/hex"
+
+// UnknownAttribute carries a path attribute which gobmp does not decode, the attribute is preserved
+// as received so no information is dropped before the native support of the attribute.
+type UnknownAttribute struct {
+	Type   uint8  `json:"type"`
+	Flags  uint8  `json:"flags"`
+	RawHex string `json:"raw_hex"`
+}
+
+// decodedAttributes lists types of path attributes decoded by gobmp, either into BaseAttributes or
+// into messages of the NLRI carried by the update.
+var decodedAttributes = map[uint8]bool{
+	1:  true, // ORIGIN
+	2:  true, // AS_PATH
+	3:  true, // NEXT_HOP
+	4:  true, // MULTI_EXIT_DISC
+	5:  true, // LOCAL_PREF
+	6:  true, // ATOMIC_AGGREGATE
+	7:  true, // AGGREGATOR
+	8:  true, // COMMUNITY
+	9:  true, // ORIGINATOR_ID
+	10: true, // CLUSTER_LIST
+	14: true, // MP_REACH_NLRI
+	15: true, // MP_UNREACH_NLRI
+	16: true, // EXTENDED COMMUNITIES
+	17: true, // AS4_PATH
+	18: true, // AS4_AGGREGATOR
+	23: true, // Tunnel Encapsulation, decoded by SR Policy
+	29: true, // BGP-LS Attribute
+	32: true, // LARGE_COMMUNITY
+	33: true, // BGPsec_Path
+	35: true, // Only to Customer
+	40: true, // Prefix-SID
+}
+
+func newUnknownAttribute(t, flags uint8, b []byte) UnknownAttribute {
+	return UnknownAttribute{
+		Type:   t,
+		Flags:  flags,
+		RawHex: hex.EncodeToString(b),
+	}
+}
@@ -0,0 +1,176 @@
+package bgp
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Actions of error handling of malformed BGP Update, RFC 7606 Section 2, actions are ordered by severity
+const (
+	ActionAttributeDiscard = "attribute-discard"
+	ActionTreatAsWithdraw  = "treat-as-withdraw"
+	ActionSessionReset     = "session-reset"
+)
+
+var actionSeverity = map[string]int{
+	ActionAttributeDiscard: 1,
+	ActionTreatAsWithdraw:  2,
+	ActionSessionReset:     3,
+}
+
+// ValidationIssue defines a semantic error found in BGP Update and the action RFC 7606 requires from
+// the receiving speaker, Attribute is 0 when the error is not specific to a path attribute.
+type ValidationIssue struct {
+	Attribute uint8  `json:"attribute,omitempty"`
+	Action    string `json:"action"`
+	Reason    string `json:"reason"`
+}
+
+// UpdateValidation defines the result of semantic validation of BGP Update, Action is the most severe
+// action of all found issues.
+type UpdateValidation struct {
+	Action string            `json:"action"`
+	Issues []ValidationIssue `json:"issues"`
+}
+
+func (v *UpdateValidation) add(attr uint8, action string, format string, a ...interface{}) {
+	v.Issues = append(v.Issues, ValidationIssue{
+		Attribute: attr,
+		Action:    action,
+		Reason:    fmt.Sprintf(format, a...),
+	})
+	if actionSeverity[action] > actionSeverity[v.Action] {
+		v.Action = action
+	}
+}
+
+// wellKnownAttributes lists well-known attributes which must have Optional bit cleared and Transitive bit set
+var wellKnownAttributes = map[uint8]string{
+	1: "ORIGIN",
+	2: "AS_PATH",
+	3: "NEXT_HOP",
+	5: "LOCAL_PREF",
+	6: "ATOMIC_AGGREGATE",
+}
+
+// Validate checks BGP Update for semantic errors, missing mandatory attributes, malformed attributes and
+// NEXT_HOP of updates carrying only Multiprotocol routes, it returns nil when no issues are found.
+// Actions of found issues are as defined in RFC 7606, the update is not modified.
+func (up *Update) Validate() *UpdateValidation {
+	v := &UpdateValidation{}
+	seen := make(map[uint8]bool)
+	for _, attr := range up.PathAttributes {
+		t := attr.AttributeType
+		if seen[t] {
+			if t == MP_REACH_NLRI || t == MP_UNREACH_NLRI {
+				v.add(t, ActionSessionReset, "attribute %d is present more than once", t)
+			} else {
+				v.add(t, ActionAttributeDiscard, "attribute %d is present more than once, all but the first are discarded", t)
+			}
+			continue
+		}
+		seen[t] = true
+		if name, ok := wellKnownAttributes[t]; ok && attr.AttributeTypeFlags&0xc0 != 0x40 {
+			v.add(t, ActionTreatAsWithdraw, "well-known attribute %s has invalid flags 0x%02x", name, attr.AttributeTypeFlags)
+		}
+		validateAttribute(v, t, attr.Attribute)
+	}
+	// NLRI of the update is either carried in NLRI field or in MP_REACH_NLRI attribute
+	if len(up.NLRI) != 0 || seen[MP_REACH_NLRI] {
+		for _, t := range []uint8{1, 2} {
+			if !seen[t] {
+				v.add(t, ActionTreatAsWithdraw, "mandatory attribute %s is missing", wellKnownAttributes[t])
+			}
+		}
+	}
+	switch {
+	case len(up.NLRI) != 0 && !seen[3]:
+		v.add(3, ActionTreatAsWithdraw, "mandatory attribute NEXT_HOP is missing")
+	case len(up.NLRI) == 0 && seen[3] && seen[MP_REACH_NLRI]:
+		// RFC 4760 Section 3, NEXT_HOP of the update without NLRI field is ignored
+		v.add(3, ActionAttributeDiscard, "NEXT_HOP attribute is present in the update carrying only Multiprotocol routes")
+	}
+	if len(v.Issues) == 0 {
+		return nil
+	}
+	sort.SliceStable(v.Issues, func(i, j int) bool {
+		return actionSeverity[v.Issues[i].Action] > actionSeverity[v.Issues[j].Action]
+	})
+
+	return v
+}
+
+// validateAttribute checks the value of the attribute as defined in RFC 7606 Section 7
+func validateAttribute(v *UpdateValidation, t uint8, b []byte) {
+	switch t {
+	case 1:
+		if len(b) != 1 || b[0] > 2 {
+			v.add(t, ActionTreatAsWithdraw, "ORIGIN has invalid length %d or value", len(b))
+		}
+	case 2:
+		if err := validateASPath(b, isASPath4(b)); err != nil {
+			v.add(t, ActionTreatAsWithdraw, "AS_PATH is malformed: %+v", err)
+		}
+	case 3:
+		if len(b) != 4 {
+			v.add(t, ActionTreatAsWithdraw, "NEXT_HOP has invalid length %d", len(b))
+		}
+	case 4, 5, 9:
+		if len(b) != 4 {
+			v.add(t, ActionTreatAsWithdraw, "attribute %d has invalid length %d", t, len(b))
+		}
+	case 6:
+		if len(b) != 0 {
+			v.add(t, ActionAttributeDiscard, "ATOMIC_AGGREGATE has invalid length %d", len(b))
+		}
+	case 7:
+		if len(b) != 6 && len(b) != 8 {
+			v.add(t, ActionAttributeDiscard, "AGGREGATOR has invalid length %d", len(b))
+		}
+	case 8, 10:
+		if len(b) == 0 || len(b)%4 != 0 {
+			v.add(t, ActionTreatAsWithdraw, "attribute %d has invalid length %d", t, len(b))
+		}
+	case 14:
+		// AFI, SAFI, Next Hop length, Next Hop and Reserved byte
+		if len(b) < 5 || len(b) < 5+int(b[3]) {
+			v.add(t, ActionSessionReset, "MP_REACH_NLRI has invalid length %d", len(b))
+		}
+	case 15:
+		if len(b) < 3 {
+			v.add(t, ActionSessionReset, "MP_UNREACH_NLRI has invalid length %d", len(b))
+		}
+	case 16:
+		if len(b) == 0 || len(b)%8 != 0 {
+			v.add(t, ActionTreatAsWithdraw, "EXTENDED COMMUNITIES has invalid length %d", len(b))
+		}
+	case 17:
+		// RFC 6793 Section 6, malformed AS4_PATH is discarded
+		if err := validateASPath(b, true); err != nil {
+			v.add(t, ActionAttributeDiscard, "AS4_PATH is malformed: %+v", err)
+		}
+	case 18:
+		if len(b) != 8 {
+			v.add(t, ActionAttributeDiscard, "AS4_AGGREGATOR has invalid length %d", len(b))
+		}
+	case 32:
+		if len(b) == 0 || len(b)%12 != 0 {
+			v.add(t, ActionTreatAsWithdraw, "LARGE_COMMUNITY has invalid length %d", len(b))
+		}
+	}
+}
+
+// validateASPath returns error if AS_PATH segments cannot be decoded or a segment has no ASes, RFC 7606 Section 7.2
+func validateASPath(b []byte, as4 bool) error {
+	segments, err := unmarshalASPathSegments(b, as4)
+	if err != nil {
+		return err
+	}
+	for _, seg := range segments {
+		if len(seg.ASN) == 0 {
+			return fmt.Errorf("%s segment has no ASes", seg.Type)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,129 @@
+package bgp
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/go-test/deep"
+)
+
+func TestUpdateValidate(t *testing.T) {
+	origin := []byte{0x40, 0x01, 0x01, 0x00}
+	asPath := []byte{0x40, 0x02, 0x06, 0x02, 0x01, 0x00, 0x00, 0xfd, 0xe9}
+	nextHop := []byte{0x40, 0x03, 0x04, 192, 0, 2, 1}
+	// IPv6 unicast 2001:db8::/32 with next hop 2001:db8::1
+	mpReach := []byte{0x80, 0x0e, 0x1a, 0x00, 0x02, 0x01, 0x10, 0x20, 0x01, 0x0d, 0xb8, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0x01, 0x00, 0x20, 0x20, 0x01, 0x0d, 0xb8}
+	nlri := []byte{8, 10}
+	update := func(attrs []byte, nlri []byte) []byte {
+		b := []byte{0, 0, byte(len(attrs) >> 8), byte(len(attrs))}
+		b = append(b, attrs...)
+		return append(b, nlri...)
+	}
+	join := func(attrs ...[]byte) []byte {
+		b := make([]byte, 0)
+		for _, a := range attrs {
+			b = append(b, a...)
+		}
+		return b
+	}
+	tests := []struct {
+		name   string
+		input  []byte
+		expect *UpdateValidation
+	}{
+		{
+			name:   "valid ipv4 update",
+			input:  update(join(origin, asPath, nextHop), nlri),
+			expect: nil,
+		},
+		{
+			name:   "valid mp update",
+			input:  update(join(origin, asPath, mpReach), nil),
+			expect: nil,
+		},
+		{
+			name:   "end of rib",
+			input:  update(nil, nil),
+			expect: nil,
+		},
+		{
+			name:  "missing mandatory attributes",
+			input: update(nextHop, nlri),
+			expect: &UpdateValidation{
+				Action: ActionTreatAsWithdraw,
+				Issues: []ValidationIssue{
+					{Attribute: 1, Action: ActionTreatAsWithdraw, Reason: "mandatory attribute ORIGIN is missing"},
+					{Attribute: 2, Action: ActionTreatAsWithdraw, Reason: "mandatory attribute AS_PATH is missing"},
+				},
+			},
+		},
+		{
+			name:  "missing next hop",
+			input: update(join(origin, asPath), nlri),
+			expect: &UpdateValidation{
+				Action: ActionTreatAsWithdraw,
+				Issues: []ValidationIssue{
+					{Attribute: 3, Action: ActionTreatAsWithdraw, Reason: "mandatory attribute NEXT_HOP is missing"},
+				},
+			},
+		},
+		{
+			name:  "next hop on mp update",
+			input: update(join(origin, asPath, nextHop, mpReach), nil),
+			expect: &UpdateValidation{
+				Action: ActionAttributeDiscard,
+				Issues: []ValidationIssue{
+					{Attribute: 3, Action: ActionAttributeDiscard, Reason: "NEXT_HOP attribute is present in the update carrying only Multiprotocol routes"},
+				},
+			},
+		},
+		{
+			name: "malformed as path and invalid origin",
+			// AS_SEQUENCE without ASes and ORIGIN value 3
+			input: update(join([]byte{0x40, 0x01, 0x01, 0x03}, []byte{0x40, 0x02, 0x02, 0x02, 0x00}, nextHop), nlri),
+			expect: &UpdateValidation{
+				Action: ActionTreatAsWithdraw,
+				Issues: []ValidationIssue{
+					{Attribute: 1, Action: ActionTreatAsWithdraw, Reason: "ORIGIN has invalid length 1 or value"},
+					{Attribute: 2, Action: ActionTreatAsWithdraw, Reason: "AS_PATH is malformed: as_sequence segment has no ASes"},
+				},
+			},
+		},
+		{
+			name: "invalid flags and attribute lengths",
+			// ORIGIN with Optional flag, ATOMIC_AGGREGATE with a value and COMMUNITY of 3 bytes
+			input: update(join([]byte{0xc0, 0x01, 0x01, 0x00}, asPath, nextHop, []byte{0x40, 0x06, 0x01, 0x00}, []byte{0xc0, 0x08, 0x03, 0, 0, 1}), nlri),
+			expect: &UpdateValidation{
+				Action: ActionTreatAsWithdraw,
+				Issues: []ValidationIssue{
+					{Attribute: 1, Action: ActionTreatAsWithdraw, Reason: "well-known attribute ORIGIN has invalid flags 0xc0"},
+					{Attribute: 8, Action: ActionTreatAsWithdraw, Reason: "attribute 8 has invalid length 3"},
+					{Attribute: 6, Action: ActionAttributeDiscard, Reason: "ATOMIC_AGGREGATE has invalid length 1"},
+				},
+			},
+		},
+		{
+			name:  "duplicate mp reach",
+			input: update(join(origin, asPath, mpReach, mpReach), nil),
+			expect: &UpdateValidation{
+				Action: ActionSessionReset,
+				Issues: []ValidationIssue{
+					{Attribute: 14, Action: ActionSessionReset, Reason: "attribute 14 is present more than once"},
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			up, err := UnmarshalBGPUpdate(tt.input)
+			if err != nil {
+				t.Fatalf("failed to unmarshal BGP Update with error: %+v", err)
+			}
+			got := up.Validate()
+			if !reflect.DeepEqual(tt.expect, got) {
+				t.Logf("Differences: %+v", deep.Equal(tt.expect, got))
+				t.Fatalf("expected validation %+v does not match actual %+v", tt.expect, got)
+			}
+		})
+	}
+}
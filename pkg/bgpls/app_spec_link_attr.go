@@ -5,6 +5,7 @@ import (
 
 	"github.com/golang/glog"
 	"github.com/sbezverk/gobmp/pkg/base"
+	"github.com/sbezverk/gobmp/pkg/logging"
 	"github.com/sbezverk/tools"
 )
 
@@ -21,7 +22,7 @@ type AppSpecLinkAttr struct {
 
 // UnmarshalAppSpecLinkAttr builds Application Specific Link Attributes object
 func UnmarshalAppSpecLinkAttr(b []byte) (*AppSpecLinkAttr, error) {
-	if glog.V(6) {
+	if logging.V(logging.BGPLS, 6) {
 		glog.Infof("App SpecLink Attr Raw: %s", tools.MessageHex(b))
 	}
 	if len(b) < 4 {
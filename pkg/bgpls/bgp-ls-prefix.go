@@ -3,7 +3,8 @@ package bgpls
 import (
 	"encoding/binary"
 	"fmt"
-	"net"
+
+	"github.com/sbezverk/gobmp/pkg/addr"
 )
 
 // GetPrefixIGPFlags returns  IGP Flags
@@ -61,9 +62,9 @@ func (ls *NLRI) GetPrefixOSPFForwardAddr() string {
 			continue
 		}
 		if tlv.Length == 4 {
-			return net.IP(tlv.Value).To4().String()
+			return addr.IPv4(tlv.Value)
 		}
-		return net.IP(tlv.Value).To16().String()
+		return addr.IPv6(tlv.Value)
 	}
 
 	return ""
@@ -4,6 +4,7 @@ import (
 	"fmt"
 
 	"github.com/golang/glog"
+	"github.com/sbezverk/gobmp/pkg/logging"
 	"github.com/sbezverk/tools"
 )
 
@@ -18,7 +19,7 @@ type IGPFlags struct {
 
 // UnmarshalIGPFlag builds IGPFlag Object
 func UnmarshalIGPFlags(b []byte) (*IGPFlags, error) {
-	if glog.V(6) {
+	if logging.V(logging.BGPLS, 6) {
 		glog.Infof("IGP Flags TLV Raw: %s", tools.MessageHex(b))
 	}
 	if len(b) < 1 {
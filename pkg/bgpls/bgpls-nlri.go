@@ -4,10 +4,11 @@ import (
 	"encoding/binary"
 	"fmt"
 	"math"
-	"net"
 
 	"github.com/golang/glog"
+	"github.com/sbezverk/gobmp/pkg/addr"
 	"github.com/sbezverk/gobmp/pkg/base"
+	"github.com/sbezverk/gobmp/pkg/logging"
 	"github.com/sbezverk/gobmp/pkg/sr"
 	"github.com/sbezverk/gobmp/pkg/srv6"
 	"github.com/sbezverk/tools"
@@ -19,6 +20,11 @@ type NLRI struct {
 	LS []TLV
 }
 
+// Marshal returns a wire format representation of BGP-LS Attribute
+func (ls *NLRI) Marshal() []byte {
+	return MarshalBGPLSTLV(ls.LS)
+}
+
 // GetLinkID returns Local and Remote Link ID as a slice of uint32
 func (ls *NLRI) GetLinkID() ([]uint32, error) {
 	for _, tlv := range ls.LS {
@@ -72,7 +78,19 @@ func (ls *NLRI) GetNodeFlags() (*NodeAttrFlags, error) {
 		}
 		return UnmarshalNodeAttrFlags(tlv.Value)
 	}
-	return nil, fmt.Errorf("node found")
+	return nil, fmt.Errorf("node flags not found")
+}
+
+// GetNodeOpaqueAttribute returns hex string of Opaque Node Attribute TLV, empty string is returned
+// when the node does not carry the TLV.
+func (ls *NLRI) GetNodeOpaqueAttribute() string {
+	for _, tlv := range ls.LS {
+		if tlv.Type != 1025 {
+			continue
+		}
+		return fmt.Sprintf("%x", tlv.Value)
+	}
+	return ""
 }
 
 // GetNodeName returns Value field identifies the symbolic name of the router node
@@ -119,13 +137,35 @@ func (ls *NLRI) GetISISAreaID() string {
 	return s
 }
 
+// GetISISAreaIDs returns IS-IS Area Identifiers of all IS-IS Area Identifier TLVs, an area is formatted
+// as its first octet followed by dot separated pairs of octets, for example 49.0001
+func (ls *NLRI) GetISISAreaIDs() []string {
+	areas := make([]string, 0)
+	for _, tlv := range ls.LS {
+		if tlv.Type != 1027 || len(tlv.Value) == 0 {
+			continue
+		}
+		s := fmt.Sprintf("%02x", tlv.Value[0])
+		for p := 1; p < len(tlv.Value); p += 2 {
+			e := p + 2
+			if e > len(tlv.Value) {
+				e = len(tlv.Value)
+			}
+			s += "." + fmt.Sprintf("%x", tlv.Value[p:e])
+		}
+		areas = append(areas, s)
+	}
+
+	return areas
+}
+
 // GetLocalIPv4RouterID returns string with local Node IPv4 router ID
 func (ls *NLRI) GetLocalIPv4RouterID() string {
 	for _, tlv := range ls.LS {
 		if tlv.Type != 1028 {
 			continue
 		}
-		return net.IP(tlv.Value).To4().String()
+		return addr.IPv4(tlv.Value)
 	}
 
 	return ""
@@ -137,7 +177,7 @@ func (ls *NLRI) GetLocalIPv6RouterID() string {
 		if tlv.Type != 1029 {
 			continue
 		}
-		return net.IP(tlv.Value).To16().String()
+		return addr.IPv6(tlv.Value)
 	}
 
 	return ""
@@ -149,7 +189,7 @@ func (ls *NLRI) GetRemoteIPv4RouterID() string {
 		if tlv.Type != 1030 {
 			continue
 		}
-		return net.IP(tlv.Value).To4().String()
+		return addr.IPv4(tlv.Value)
 	}
 
 	return ""
@@ -161,7 +201,7 @@ func (ls *NLRI) GetRemoteIPv6RouterID() string {
 		if tlv.Type != 1031 {
 			continue
 		}
-		return net.IP(tlv.Value).To16().String()
+		return addr.IPv6(tlv.Value)
 	}
 
 	return ""
@@ -318,9 +358,9 @@ func (ls *NLRI) GetLSSourceRouterID() (string, error) {
 		}
 		switch len(tlv.Value) {
 		case 4:
-			return net.IP(tlv.Value).To4().String(), nil
+			return addr.IPv4(tlv.Value), nil
 		case 16:
-			return net.IP(tlv.Value).To16().String(), nil
+			return addr.IPv6(tlv.Value), nil
 		default:
 			return "", fmt.Errorf("invalid length %d of Source Router ID TLV", len(tlv.Value))
 		}
@@ -477,7 +517,7 @@ func (ls *NLRI) GetUnreservedLinkBandwidthKbps() []uint64 {
 // GetLinkProtectionType returns value of Link Protection Type
 func (ls *NLRI) GetLinkProtectionType() uint16 {
 	for _, tlv := range ls.LS {
-		if tlv.Type != 1093 {
+		if tlv.Type != 1093 || len(tlv.Value) < 2 {
 			continue
 		}
 		return binary.BigEndian.Uint16(tlv.Value)
@@ -486,6 +526,18 @@ func (ls *NLRI) GetLinkProtectionType() uint16 {
 	return 0
 }
 
+// GetLinkProtectionTypeFlags returns decoded Link Protection Type
+func (ls *NLRI) GetLinkProtectionTypeFlags() (*LinkProtectionType, error) {
+	for _, tlv := range ls.LS {
+		if tlv.Type != 1093 {
+			continue
+		}
+		return UnmarshalLinkProtectionType(tlv.Value)
+	}
+
+	return nil, fmt.Errorf("not found")
+}
+
 // GetLinkMPLSProtocolMask returns value of MPLS Protocol Mask
 func (ls *NLRI) GetLinkMPLSProtocolMask() uint8 {
 	for _, tlv := range ls.LS {
@@ -498,6 +550,18 @@ func (ls *NLRI) GetLinkMPLSProtocolMask() uint8 {
 	return 0
 }
 
+// GetLinkMPLSProtocolMaskFlags returns decoded MPLS Protocol Mask
+func (ls *NLRI) GetLinkMPLSProtocolMaskFlags() (*MPLSProtocolMask, error) {
+	for _, tlv := range ls.LS {
+		if tlv.Type != 1094 {
+			continue
+		}
+		return UnmarshalMPLSProtocolMask(tlv.Value)
+	}
+
+	return nil, fmt.Errorf("not found")
+}
+
 // GetSRLG returns slice of uint32 carrying data structure
 // consisting of a (variable) list of SRLG values
 func (ls *NLRI) GetSRLG() []uint32 {
@@ -528,6 +592,19 @@ func (ls *NLRI) GetLinkName() string {
 	return ""
 }
 
+// GetLinkOpaqueAttribute returns hex string of Opaque Link Attribute TLV, empty string is returned
+// when the link does not carry the TLV.
+func (ls *NLRI) GetLinkOpaqueAttribute() string {
+	for _, tlv := range ls.LS {
+		if tlv.Type != 1097 {
+			continue
+		}
+		return fmt.Sprintf("%x", tlv.Value)
+	}
+
+	return ""
+}
+
 // GetPeerNodeSID returns PeerNode SID TLV includes a SID associated with the BGP peer node
 // that is described by a BGP-LS Link NLRI
 func (ls *NLRI) GetPeerNodeSID() (*sr.PeerSID, error) {
@@ -567,6 +644,24 @@ func (ls *NLRI) GetPeerSetSID() (*sr.PeerSID, error) {
 	return nil, fmt.Errorf("not found")
 }
 
+// GetPeerSetSIDs returns all PeerSet SID TLVs of BGP-LS Link NLRI, the peer described by the NLRI can be
+// a member of several sets of peers.
+func (ls *NLRI) GetPeerSetSIDs() ([]*sr.PeerSID, error) {
+	sids := make([]*sr.PeerSID, 0)
+	for _, tlv := range ls.LS {
+		if tlv.Type != 1103 {
+			continue
+		}
+		sid, err := sr.UnmarshalPeerSID(tlv.Value)
+		if err != nil {
+			return nil, err
+		}
+		sids = append(sids, sid)
+	}
+
+	return sids, nil
+}
+
 // GetSRv6EndpointBehavior returns SRv6 SID NLRI Endpoint behavior object
 func (ls *NLRI) GetSRv6EndpointBehavior() *srv6.EndpointBehavior {
 	for _, tlv := range ls.LS {
@@ -741,9 +836,26 @@ func (ls *NLRI) GetSRAdjacencySID(proto base.ProtoID) ([]*sr.AdjacencySIDTLV, er
 	return adjs, nil
 }
 
+// GetSRLANAdjacencySID returns SR LAN Adjacency SID objects
+func (ls *NLRI) GetSRLANAdjacencySID(proto base.ProtoID) ([]*sr.LANAdjacencySIDTLV, error) {
+	adjs := make([]*sr.LANAdjacencySIDTLV, 0)
+	for _, tlv := range ls.LS {
+		if tlv.Type != 1100 {
+			continue
+		}
+		adj, err := sr.UnmarshalLANAdjacencySIDTLV(tlv.Value, proto)
+		if err != nil {
+			return nil, err
+		}
+		adjs = append(adjs, adj)
+	}
+
+	return adjs, nil
+}
+
 // UnmarshalBGPLSNLRI builds Prefix NLRI object
 func UnmarshalBGPLSNLRI(b []byte) (*NLRI, error) {
-	if glog.V(6) {
+	if logging.V(logging.BGPLS, 6) {
 		glog.Infof("BGPLSNLRI Raw: %s", tools.MessageHex(b))
 	}
 	if len(b) == 0 {
@@ -0,0 +1,230 @@
+package bgpls
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/go-test/deep"
+	"github.com/sbezverk/gobmp/pkg/base"
+	"github.com/sbezverk/gobmp/pkg/sr"
+)
+
+func TestGetSRLANAdjacencySID(t *testing.T) {
+	label, index := uint32(24002), uint32(5)
+	tests := []struct {
+		name   string
+		ls     *NLRI
+		proto  base.ProtoID
+		expect []*sr.LANAdjacencySIDTLV
+		fail   bool
+	}{
+		{
+			name: "isis two neighbors",
+			ls: &NLRI{
+				LS: []TLV{
+					{
+						Type:   1099,
+						Length: 7,
+						Value:  []byte{0x30, 0x00, 0x00, 0x00, 0x00, 0x5d, 0xc1},
+					},
+					{
+						Type:   1100,
+						Length: 13,
+						Value:  []byte{0x30, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0x00, 0x5d, 0xc2},
+					},
+					{
+						Type:   1100,
+						Length: 14,
+						Value:  []byte{0x00, 0x01, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x03, 0x00, 0x00, 0x00, 0x05},
+					},
+				},
+			},
+			proto: base.ISISL2,
+			expect: []*sr.LANAdjacencySIDTLV{
+				{
+					Flags:      &sr.AdjISISFlags{VFlag: true, LFlag: true},
+					NeighborID: "0000.0000.0002",
+					SID:        24002,
+					SIDValue:   &sr.SIDValue{Type: sr.SIDTypeLabel, Label: &label},
+				},
+				{
+					Flags:      &sr.AdjISISFlags{},
+					Weight:     1,
+					NeighborID: "0000.0000.0003",
+					SID:        5,
+					SIDValue:   &sr.SIDValue{Type: sr.SIDTypeIndex, Index: &index},
+				},
+			},
+		},
+		{
+			name: "ospf neighbor",
+			ls: &NLRI{
+				LS: []TLV{
+					{
+						Type:   1100,
+						Length: 11,
+						Value:  []byte{0x60, 0x00, 0x00, 0x00, 0xc0, 0x00, 0x02, 0x01, 0x00, 0x5d, 0xc2},
+					},
+				},
+			},
+			proto: base.OSPFv2,
+			expect: []*sr.LANAdjacencySIDTLV{
+				{
+					Flags:      &sr.AdjOSPFFlags{VFlag: true, LFlag: true},
+					NeighborID: "192.0.2.1",
+					SID:        24002,
+					SIDValue:   &sr.SIDValue{Type: sr.SIDTypeLabel, Label: &label},
+				},
+			},
+		},
+		{
+			name: "no lan adjacency sid",
+			ls: &NLRI{
+				LS: []TLV{
+					{
+						Type:   1099,
+						Length: 7,
+						Value:  []byte{0x30, 0x00, 0x00, 0x00, 0x00, 0x5d, 0xc1},
+					},
+				},
+			},
+			proto:  base.ISISL2,
+			expect: []*sr.LANAdjacencySIDTLV{},
+		},
+		{
+			name: "truncated neighbor id",
+			ls: &NLRI{
+				LS: []TLV{
+					{
+						Type:   1100,
+						Length: 9,
+						Value:  []byte{0x30, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x5d, 0xc2},
+					},
+				},
+			},
+			proto: base.ISISL2,
+			fail:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.ls.GetSRLANAdjacencySID(tt.proto)
+			if err != nil && !tt.fail {
+				t.Fatalf("supposed to succeed but failed with error: %+v", err)
+			}
+			if err == nil && tt.fail {
+				t.Fatalf("supposed to fail but succeeded")
+			}
+			if err != nil {
+				return
+			}
+			if diff := deep.Equal(got, tt.expect); len(diff) != 0 {
+				t.Errorf("expected and actual LAN Adjacency SIDs do not match, differences: %+v", diff)
+			}
+		})
+	}
+}
+
+func TestFlagsJSON(t *testing.T) {
+	tests := []struct {
+		name   string
+		flags  interface{}
+		json   string
+		input  string
+		result interface{}
+	}{
+		{
+			name:   "node attribute flags",
+			flags:  &NodeAttrFlags{OFlag: true, BFlag: true},
+			json:   `{"o_flag":true,"t_flag":false,"e_flag":false,"b_flag":true,"r_flag":false,"v_flag":false,"raw":144}`,
+			input:  `{"raw":144}`,
+			result: &NodeAttrFlags{},
+		},
+		{
+			name:   "mpls protocol mask",
+			flags:  &MPLSProtocolMask{LFlag: true},
+			json:   `{"l_flag":true,"r_flag":false,"raw":128}`,
+			input:  `{"l_flag":true}`,
+			result: &MPLSProtocolMask{},
+		},
+		{
+			name:   "link protection type",
+			flags:  &LinkProtectionType{Shared: true, Enhanced: true},
+			json:   `{"extra_traffic":false,"unprotected":false,"shared":true,"dedicated_1_1":false,"dedicated_1_plus_1":false,"enhanced":true,"raw":36}`,
+			input:  `{"raw":36}`,
+			result: &LinkProtectionType{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := json.Marshal(tt.flags)
+			if err != nil {
+				t.Fatalf("failed to marshal flags with error: %+v", err)
+			}
+			if string(b) != tt.json {
+				t.Errorf("expected json %s does not match marshaled %s", tt.json, string(b))
+			}
+			if err := json.Unmarshal([]byte(tt.input), tt.result); err != nil {
+				t.Fatalf("failed to unmarshal flags with error: %+v", err)
+			}
+			if diff := deep.Equal(tt.result, tt.flags); len(diff) != 0 {
+				t.Errorf("expected and unmarshaled flags do not match, differences: %+v", diff)
+			}
+		})
+	}
+}
+
+func TestGetNodeAttributes(t *testing.T) {
+	ls := &NLRI{
+		LS: []TLV{
+			{Type: 1025, Length: 3, Value: []byte{0x01, 0x02, 0xab}},
+			{Type: 1027, Length: 3, Value: []byte{0x49, 0x00, 0x01}},
+			{Type: 1027, Length: 6, Value: []byte{0x39, 0x84, 0x0f, 0x80, 0x00, 0x01}},
+			{Type: 1027, Length: 0, Value: []byte{}},
+			{Type: 1029, Length: 16, Value: []byte{0x20, 0x01, 0x0d, 0xb8, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0x01}},
+		},
+	}
+	if opaque := ls.GetNodeOpaqueAttribute(); opaque != "0102ab" {
+		t.Errorf("expected opaque node attribute 0102ab, got %s", opaque)
+	}
+	if diff := deep.Equal(ls.GetISISAreaIDs(), []string{"49.0001", "39.840f.8000.01"}); len(diff) != 0 {
+		t.Errorf("expected and actual IS-IS areas do not match, differences: %+v", diff)
+	}
+	if id := ls.GetLocalIPv6RouterID(); id != "2001:db8::1" {
+		t.Errorf("expected IPv6 router ID 2001:db8::1, got %s", id)
+	}
+	if id := ls.GetLocalIPv4RouterID(); id != "" {
+		t.Errorf("expected no IPv4 router ID, got %s", id)
+	}
+}
+
+func TestGetLinkAttributes(t *testing.T) {
+	ls := &NLRI{
+		LS: []TLV{
+			{Type: 1093, Length: 2, Value: []byte{0x10, 0x00}},
+			{Type: 1097, Length: 2, Value: []byte{0xde, 0xad}},
+			{Type: 1098, Length: 4, Value: []byte("core")},
+		},
+	}
+	if p := ls.GetLinkProtectionType(); p != 0x1000 {
+		t.Errorf("expected link protection type 0x1000, got 0x%04x", p)
+	}
+	p, err := ls.GetLinkProtectionTypeFlags()
+	if err != nil {
+		t.Fatalf("failed to get link protection type with error: %+v", err)
+	}
+	if diff := deep.Equal(p, &LinkProtectionType{Dedicated1plus1: true}); len(diff) != 0 {
+		t.Errorf("expected and actual link protection types do not match, differences: %+v", diff)
+	}
+	if opaque := ls.GetLinkOpaqueAttribute(); opaque != "dead" {
+		t.Errorf("expected opaque link attribute dead, got %s", opaque)
+	}
+	if name := ls.GetLinkName(); name != "core" {
+		t.Errorf("expected link name core, got %s", name)
+	}
+	// Link Protection Type shorter than 2 octets
+	ls.LS[0].Value = []byte{0x10}
+	if p := ls.GetLinkProtectionType(); p != 0 {
+		t.Errorf("expected no link protection type of truncated TLV, got 0x%04x", p)
+	}
+}
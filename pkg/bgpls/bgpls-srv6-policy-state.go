@@ -6,6 +6,7 @@ import (
 	"fmt"
 
 	"github.com/golang/glog"
+	"github.com/sbezverk/gobmp/pkg/logging"
 	"github.com/sbezverk/tools"
 )
 
@@ -47,7 +48,7 @@ type SRBindingSID struct {
 
 // UnmarshalSRBindingSID instantiates SR Binding SID object from a slice of bytes
 func UnmarshalSRBindingSID(b []byte) (*SRBindingSID, error) {
-	if glog.V(6) {
+	if logging.V(logging.BGPLS, 6) {
 		glog.Infof("SR Binding SID TLV Raw: %s", tools.MessageHex(b))
 	}
 	if len(b) != 12 && len(b) != 36 {
@@ -124,7 +125,7 @@ type SRCandidatePathState struct {
 
 //UnmarshalSRCandidatePathState instantiates SR Candidate Path State object from a slice of bytes
 func UnmarshalSRCandidatePathState(b []byte) (*SRCandidatePathState, error) {
-	if glog.V(6) {
+	if logging.V(logging.BGPLS, 6) {
 		glog.Infof("SR Candidate Path State TLV Raw: %s", tools.MessageHex(b))
 	}
 	if len(b) != 8 {
@@ -160,7 +161,7 @@ type SRCandidatePathName struct {
 
 // UnmarshalSRCandidatePathName instantiates SR Candidate Path Name object from a slice of bytes
 func UnmarshalSRCandidatePathName(b []byte) (*SRCandidatePathName, error) {
-	if glog.V(6) {
+	if logging.V(logging.BGPLS, 6) {
 		glog.Infof("SR Candidate Path Name TLV Raw: %s", tools.MessageHex(b))
 	}
 	s := &SRCandidatePathName{
@@ -190,7 +191,7 @@ type SRCandidatePathConstraints struct {
 
 // UnmarshalSRCandidatePathConstraints instantiates SR Candidate Path Constraints object from a slice of bytes
 func UnmarshalSRCandidatePathConstraints(b []byte) (*SRCandidatePathConstraints, error) {
-	if glog.V(6) {
+	if logging.V(logging.BGPLS, 6) {
 		glog.Infof("SR Candidate Path Constraints TLV Raw: %s", tools.MessageHex(b))
 	}
 	if len(b) < 8 {
@@ -223,7 +224,7 @@ func UnmarshalSRCandidatePathConstraints(b []byte) (*SRCandidatePathConstraints,
 
 // UnmarshalSRCandidatePathConstraintsSubTLV unmarshals a map of SR Candidate Path Constraints Sub TLV from a slice of bytes
 func UnmarshalSRCandidatePathConstraintsSubTLV(b []byte) (map[uint16]SRCandidatePathConstraintsSubTLV, error) {
-	if glog.V(6) {
+	if logging.V(logging.BGPLS, 6) {
 		glog.Infof("SR Candidate Path Constraints Sub TLV Raw: %s", tools.MessageHex(b))
 	}
 	if len(b) < 4 {
@@ -286,7 +287,7 @@ type SRAffinityConstraint struct {
 
 // UnmarshalSRAffinityConstraint instantiates SR Affinity Constraint object from a slice of bytes
 func UnmarshalSRAffinityConstraint(b []byte) (*SRAffinityConstraint, error) {
-	if glog.V(6) {
+	if logging.V(logging.BGPLS, 6) {
 		glog.Infof("SR Affinity Constraint Sub TLV Raw: %s", tools.MessageHex(b))
 	}
 	if len(b) < 4 {
@@ -367,7 +368,7 @@ type SRSRLGConstraint struct {
 
 // UnmarshalSRSRLGConstraint instantiates SR SRLG Constraint object from a slice of bytes
 func UnmarshalSRSRLGConstraint(b []byte) (*SRSRLGConstraint, error) {
-	if glog.V(6) {
+	if logging.V(logging.BGPLS, 6) {
 		glog.Infof("SR SRLG Constraint Sub TLV Raw: %s", tools.MessageHex(b))
 	}
 	if len(b) < 4 {
@@ -417,7 +418,7 @@ type SRBandwidthConstraint struct {
 
 // UnmarshalSRBandwidthConstraint instantiates SR Bandwidth Constraint object from a slice of bytes
 func UnmarshalSRBandwidthConstraint(b []byte) (*SRBandwidthConstraint, error) {
-	if glog.V(6) {
+	if logging.V(logging.BGPLS, 6) {
 		glog.Infof("SR Bandwidth Constraint Sub TLV Raw: %s", tools.MessageHex(b))
 	}
 	if len(b) != 4 {
@@ -469,7 +470,7 @@ type SRDisjointGroupConstraint struct {
 
 // UnmarshalSRDisjointGroupConstraint instantiates SR DisjointGroup Constraint object from a slice of bytes
 func UnmarshalSRDisjointGroupConstraint(b []byte) (*SRDisjointGroupConstraint, error) {
-	if glog.V(6) {
+	if logging.V(logging.BGPLS, 6) {
 		glog.Infof("SR DisjointGroup Constraint Sub TLV Raw: %s", tools.MessageHex(b))
 	}
 	if len(b) != 8 {
@@ -564,7 +565,7 @@ type SRSegmentList struct {
 
 // UnmarshalSRSegmentList instantiates SRSegmentList from a slice of bytes
 func UnmarshalSRSegmentList(b []byte) (*SRSegmentList, error) {
-	if glog.V(6) {
+	if logging.V(logging.BGPLS, 6) {
 		glog.Infof("SR Segment List TLV Raw: %s", tools.MessageHex(b))
 	}
 	if len(b) < 12 {
@@ -607,7 +608,7 @@ func UnmarshalSRSegmentList(b []byte) (*SRSegmentList, error) {
 
 // UnmarshalSRSegmentListSubTLV instantiates a map of SR Segment List Sub TLVs from a slice of bytes
 func UnmarshalSRSegmentListSubTLV(b []byte) (map[uint16]SRSegmentListSubTLV, error) {
-	if glog.V(6) {
+	if logging.V(logging.BGPLS, 6) {
 		glog.Infof("SR Segment List Sub TLV Raw: %s", tools.MessageHex(b))
 	}
 	if len(b) < 4 {
@@ -688,7 +689,7 @@ type MPLSLabelSID struct {
 
 // UnmarshalMPLSLabelSID instantiates MPLSLabelSID object from a slice of bytes
 func UnmarshalMPLSLabelSID(b []byte) (SID, error) {
-	if glog.V(6) {
+	if logging.V(logging.BGPLS, 6) {
 		glog.Infof("MPLS Label SID Raw: %s", tools.MessageHex(b))
 	}
 	if len(b) != 4 {
@@ -744,7 +745,7 @@ type SRv6SID struct {
 
 // UnmarshalSRv6SID instantiates SRv6 SID object from a slice of bytes
 func UnmarshalSRv6SID(b []byte) (SID, error) {
-	if glog.V(6) {
+	if logging.V(logging.BGPLS, 6) {
 		glog.Infof("SRv6 SID Raw: %s", tools.MessageHex(b))
 	}
 	if len(b) != 16 {
@@ -799,7 +800,7 @@ type SRType1Descriptor struct {
 
 // UnmarshalSRType1Descriptor instantiates SR DisjointGroup Constraint object from a slice of bytes
 func UnmarshalSRType1Descriptor(b []byte) (SegmentDescriptor, error) {
-	if glog.V(6) {
+	if logging.V(logging.BGPLS, 6) {
 		glog.Infof("SR Type1 Descriptor Raw: %s", tools.MessageHex(b))
 	}
 	if len(b) != 1 {
@@ -845,7 +846,7 @@ type SRSegmentSubTLV interface {
 
 // UnmarshalSRSegmentSubTLV instantiates a map of SR Segment Sub TLVs from a slice of bytes
 func UnmarshalSRSegmentSubTLV(b []byte) (map[uint16]SRSegmentSubTLV, error) {
-	if glog.V(6) {
+	if logging.V(logging.BGPLS, 6) {
 		glog.Infof("SR Segment Sub TLV Raw: %s", tools.MessageHex(b))
 	}
 	if len(b) < 4 {
@@ -886,7 +887,7 @@ var _ SRSegmentListSubTLV = &SRSegment{}
 
 // UnmarshalSRSegment instantiates SR Segment Sub TLV object from a slice of bytes
 func UnmarshalSRSegment(b []byte) (SRSegmentListSubTLV, error) {
-	if glog.V(6) {
+	if logging.V(logging.BGPLS, 6) {
 		glog.Infof("SR Segment Sub TLV Raw: %s", tools.MessageHex(b))
 	}
 	if len(b) < 4 {
@@ -1030,7 +1031,7 @@ type SRSegmentListMetric struct {
 
 // UnmarshalSRSegmentListMetric instantiates SR DisjointGroup Constraint object from a slice of bytes
 func UnmarshalSRSegmentListMetric(b []byte) (SRSegmentListSubTLV, error) {
-	if glog.V(6) {
+	if logging.V(logging.BGPLS, 6) {
 		glog.Infof("SR Segment List Metric Raw: %s", tools.MessageHex(b))
 	}
 	if len(b) != 16 {
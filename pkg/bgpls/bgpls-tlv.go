@@ -4,6 +4,7 @@ import (
 	"encoding/binary"
 
 	"github.com/golang/glog"
+	"github.com/sbezverk/gobmp/pkg/logging"
 	"github.com/sbezverk/tools"
 )
 
@@ -17,7 +18,7 @@ type TLV struct {
 
 // UnmarshalBGPLSTLV builds Collection of BGP-LS TLVs
 func UnmarshalBGPLSTLV(b []byte) ([]TLV, error) {
-	if glog.V(6) {
+	if logging.V(logging.BGPLS, 6) {
 		glog.Infof("BGPLSTLV Raw: %s", tools.MessageHex(b))
 	}
 	lstlvs := make([]TLV, 0)
@@ -35,3 +36,23 @@ func UnmarshalBGPLSTLV(b []byte) ([]TLV, error) {
 
 	return lstlvs, nil
 }
+
+// Marshal returns a wire format representation of BGP-LS TLV including type and length
+func (tlv *TLV) Marshal() []byte {
+	b := make([]byte, 4+len(tlv.Value))
+	binary.BigEndian.PutUint16(b[0:2], tlv.Type)
+	binary.BigEndian.PutUint16(b[2:4], uint16(len(tlv.Value)))
+	copy(b[4:], tlv.Value)
+
+	return b
+}
+
+// MarshalBGPLSTLV returns a wire format representation of a collection of BGP-LS TLVs
+func MarshalBGPLSTLV(tlvs []TLV) []byte {
+	b := make([]byte, 0)
+	for _, tlv := range tlvs {
+		b = append(b, tlv.Marshal()...)
+	}
+
+	return b
+}
@@ -6,6 +6,7 @@ import (
 
 	"github.com/golang/glog"
 	"github.com/sbezverk/gobmp/pkg/base"
+	"github.com/sbezverk/gobmp/pkg/logging"
 	"github.com/sbezverk/tools"
 )
 
@@ -47,7 +48,7 @@ func getFADSubTLVValue(tlv *base.SubTLV) ([]uint32, error) {
 
 // UnmarshalFlexAlgoDefinition builds Flexible Algorithm Definition (FAD) TLV object
 func UnmarshalFlexAlgoDefinition(b []byte) (*FlexAlgoDefinition, error) {
-	if glog.V(6) {
+	if logging.V(logging.BGPLS, 6) {
 		glog.Infof("FlexAlgo Definition Raw: %s", tools.MessageHex(b))
 	}
 	if len(b) < 4 {
@@ -109,7 +110,7 @@ type FlexAlgoPrefixMetric struct {
 
 // UnmarshalFlexAlgoPrefixMetric builds Flexible Algorithm Prefix Metric TLV object
 func UnmarshalFlexAlgoPrefixMetric(b []byte) (*FlexAlgoPrefixMetric, error) {
-	if glog.V(6) {
+	if logging.V(logging.BGPLS, 6) {
 		glog.Infof("FlexAlgo Prefix Metric Raw: %s", tools.MessageHex(b))
 	}
 	if len(b) < 8 {
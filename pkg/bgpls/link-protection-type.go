@@ -0,0 +1,101 @@
+package bgpls
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// LinkProtectionType defines Link Protection Type TLV, the first octet carries protection capabilities,
+// the second octet is reserved.
+// https://tools.ietf.org/html/rfc7752#section-3.3.2.2
+// https://tools.ietf.org/html/rfc5307#section-1.2
+// +-------+-----------------+
+// |  Bit  | Description     |
+// +-------+-----------------+
+// | 0x01  | Extra Traffic   |
+// | 0x02  | Unprotected     |
+// | 0x04  | Shared          |
+// | 0x08  | Dedicated 1:1   |
+// | 0x10  | Dedicated 1+1   |
+// | 0x20  | Enhanced        |
+// +-------+-----------------+
+type LinkProtectionType struct {
+	ExtraTraffic    bool `json:"extra_traffic"`
+	Unprotected     bool `json:"unprotected"`
+	Shared          bool `json:"shared"`
+	Dedicated1to1   bool `json:"dedicated_1_1"`
+	Dedicated1plus1 bool `json:"dedicated_1_plus_1"`
+	Enhanced        bool `json:"enhanced"`
+}
+
+// UnmarshalLinkProtectionType builds Link Protection Type object
+func UnmarshalLinkProtectionType(b []byte) (*LinkProtectionType, error) {
+	if len(b) < 1 {
+		return nil, fmt.Errorf("not enough bytes to unmarshal Link Protection Type")
+	}
+
+	return &LinkProtectionType{
+		ExtraTraffic:    b[0]&0x01 == 0x01,
+		Unprotected:     b[0]&0x02 == 0x02,
+		Shared:          b[0]&0x04 == 0x04,
+		Dedicated1to1:   b[0]&0x08 == 0x08,
+		Dedicated1plus1: b[0]&0x10 == 0x10,
+		Enhanced:        b[0]&0x20 == 0x20,
+	}, nil
+}
+
+// GetLinkProtectionTypeByte returns a byte represenation of Link Protection Type capabilities
+func (p *LinkProtectionType) GetLinkProtectionTypeByte() byte {
+	b := byte(0)
+	if p.ExtraTraffic {
+		b += 0x01
+	}
+	if p.Unprotected {
+		b += 0x02
+	}
+	if p.Shared {
+		b += 0x04
+	}
+	if p.Dedicated1to1 {
+		b += 0x08
+	}
+	if p.Dedicated1plus1 {
+		b += 0x10
+	}
+	if p.Enhanced {
+		b += 0x20
+	}
+
+	return b
+}
+
+// MarshalJSON returns JSON of Link Protection Type carrying the raw byte of capabilities in "raw" key alongside decoded flags
+func (p *LinkProtectionType) MarshalJSON() ([]byte, error) {
+	type linkProtectionType LinkProtectionType
+	return json.Marshal(struct {
+		linkProtectionType
+		Raw uint8 `json:"raw"`
+	}{
+		linkProtectionType: linkProtectionType(*p),
+		Raw:                p.GetLinkProtectionTypeByte(),
+	})
+}
+
+// UnmarshalJSON accepts either decoded flags or the raw byte of capabilities, the raw byte takes precedence
+func (p *LinkProtectionType) UnmarshalJSON(b []byte) error {
+	type linkProtectionType LinkProtectionType
+	if err := json.Unmarshal(b, (*linkProtectionType)(p)); err != nil {
+		return err
+	}
+	raw, err := rawFlags(b)
+	if err != nil || raw == nil {
+		return err
+	}
+	np, err := UnmarshalLinkProtectionType(raw)
+	if err != nil {
+		return err
+	}
+	*p = *np
+
+	return nil
+}
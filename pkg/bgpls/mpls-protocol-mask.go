@@ -0,0 +1,76 @@
+package bgpls
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// MPLSProtocolMask defines MPLS Protocol Mask TLV
+// https://tools.ietf.org/html/rfc7752#section-3.3.2.2
+// +-----------------+-------------------------------------------+-----------+
+// |       Bit       | Description                               | Reference |
+// +-----------------+-------------------------------------------+-----------+
+// |       'L'       | Label Distribution Protocol (LDP)         | [RFC5036] |
+// |       'R'       | Extension to RSVP for LSP Tunnels         | [RFC3209] |
+// |                 | (RSVP-TE)                                 |           |
+// +-----------------+-------------------------------------------+-----------+
+type MPLSProtocolMask struct {
+	LFlag bool `json:"l_flag"`
+	RFlag bool `json:"r_flag"`
+}
+
+// UnmarshalMPLSProtocolMask builds MPLS Protocol Mask object
+func UnmarshalMPLSProtocolMask(b []byte) (*MPLSProtocolMask, error) {
+	if len(b) < 1 {
+		return nil, fmt.Errorf("not enough bytes to unmarshal MPLS Protocol Mask")
+	}
+
+	return &MPLSProtocolMask{
+		LFlag: b[0]&0x80 == 0x80,
+		RFlag: b[0]&0x40 == 0x40,
+	}, nil
+}
+
+// GetMPLSProtocolMaskByte returns a byte represenation of MPLS Protocol Mask
+func (m *MPLSProtocolMask) GetMPLSProtocolMaskByte() byte {
+	b := byte(0)
+	if m.LFlag {
+		b += 0x80
+	}
+	if m.RFlag {
+		b += 0x40
+	}
+
+	return b
+}
+
+// MarshalJSON returns JSON of MPLS Protocol Mask carrying the raw byte of the mask in "raw" key alongside decoded flags
+func (m *MPLSProtocolMask) MarshalJSON() ([]byte, error) {
+	type mplsProtocolMask MPLSProtocolMask
+	return json.Marshal(struct {
+		mplsProtocolMask
+		Raw uint8 `json:"raw"`
+	}{
+		mplsProtocolMask: mplsProtocolMask(*m),
+		Raw:              m.GetMPLSProtocolMaskByte(),
+	})
+}
+
+// UnmarshalJSON accepts either decoded flags or the raw byte of the mask, the raw byte takes precedence
+func (m *MPLSProtocolMask) UnmarshalJSON(b []byte) error {
+	type mplsProtocolMask MPLSProtocolMask
+	if err := json.Unmarshal(b, (*mplsProtocolMask)(m)); err != nil {
+		return err
+	}
+	raw, err := rawFlags(b)
+	if err != nil || raw == nil {
+		return err
+	}
+	nm, err := UnmarshalMPLSProtocolMask(raw)
+	if err != nil {
+		return err
+	}
+	*m = *nm
+
+	return nil
+}
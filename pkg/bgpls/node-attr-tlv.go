@@ -1,9 +1,11 @@
 package bgpls
 
 import (
+	"encoding/json"
 	"fmt"
 
 	"github.com/golang/glog"
+	"github.com/sbezverk/gobmp/pkg/logging"
 	"github.com/sbezverk/tools"
 )
 
@@ -32,7 +34,7 @@ func UnmarshalNodeAttrFlags(b []byte) (*NodeAttrFlags, error) {
 	if len(b) < 1 {
 		return nil, fmt.Errorf("not enough bytes to unmarshal Node Attribute Flags")
 	}
-	if glog.V(6) {
+	if logging.V(logging.BGPLS, 6) {
 		glog.Infof("Node Attr Flags Raw: %s", tools.MessageHex(b))
 	}
 	f := &NodeAttrFlags{}
@@ -45,3 +47,74 @@ func UnmarshalNodeAttrFlags(b []byte) (*NodeAttrFlags, error) {
 
 	return f, nil
 }
+
+// GetNodeAttrFlagByte returns a byte represenation of Node Attribute Flags
+func (f *NodeAttrFlags) GetNodeAttrFlagByte() byte {
+	b := byte(0)
+	if f.OFlag {
+		b += 0x80
+	}
+	if f.TFlag {
+		b += 0x40
+	}
+	if f.EFlag {
+		b += 0x20
+	}
+	if f.BFlag {
+		b += 0x10
+	}
+	if f.RFlag {
+		b += 0x08
+	}
+	if f.VFlag {
+		b += 0x04
+	}
+
+	return b
+}
+
+// MarshalJSON returns JSON of Node Attribute Flags carrying the raw byte of flags in "raw" key alongside decoded flags
+func (f *NodeAttrFlags) MarshalJSON() ([]byte, error) {
+	type nodeAttrFlags NodeAttrFlags
+	return json.Marshal(struct {
+		nodeAttrFlags
+		Raw uint8 `json:"raw"`
+	}{
+		nodeAttrFlags: nodeAttrFlags(*f),
+		Raw:           f.GetNodeAttrFlagByte(),
+	})
+}
+
+// UnmarshalJSON accepts either decoded flags or the raw byte of flags, the raw byte takes precedence
+func (f *NodeAttrFlags) UnmarshalJSON(b []byte) error {
+	type nodeAttrFlags NodeAttrFlags
+	if err := json.Unmarshal(b, (*nodeAttrFlags)(f)); err != nil {
+		return err
+	}
+	raw, err := rawFlags(b)
+	if err != nil || raw == nil {
+		return err
+	}
+	nf, err := UnmarshalNodeAttrFlags(raw)
+	if err != nil {
+		return err
+	}
+	*f = *nf
+
+	return nil
+}
+
+// rawFlags returns the raw byte of flags from JSON of flags, nil is returned when JSON does not carry the raw byte
+func rawFlags(b []byte) ([]byte, error) {
+	var v struct {
+		Raw *uint8 `json:"raw"`
+	}
+	if err := json.Unmarshal(b, &v); err != nil {
+		return nil, err
+	}
+	if v.Raw == nil {
+		return nil, nil
+	}
+
+	return []byte{*v.Raw}, nil
+}
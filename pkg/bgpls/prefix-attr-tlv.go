@@ -6,6 +6,7 @@ import (
 
 	"github.com/golang/glog"
 	"github.com/sbezverk/gobmp/pkg/base"
+	"github.com/sbezverk/gobmp/pkg/logging"
 	"github.com/sbezverk/gobmp/pkg/sr"
 	"github.com/sbezverk/tools"
 )
@@ -13,11 +14,16 @@ import (
 // PrefixAttrTLVs defines a struvture for Prefix Attributes as defined in the following RFC proposal:
 // https://datatracker.ietf.org/doc/html/draft-ietf-idr-bgp-ls-segment-routing-ext-17#section-2.3
 type PrefixAttrTLVs struct {
-	LSPrefixSID []*sr.PrefixSIDTLV `json:"ls_prefix_sid,omitempty"`
-	// TODO (sbezverk) Add "Range" TLV 1159
-	Flags          PrefixAttrFlags `json:"flags,omitempty"`
-	SourceRouterID string          `json:"source_router_id,omitempty"`
-	// TODO (sbezverk) Add "Source OSPF Router-ID" TLV 1174
+	LSPrefixSID        []*sr.PrefixSIDTLV `json:"ls_prefix_sid,omitempty"`
+	Range              []*PrefixRangeTLV  `json:"prefix_range,omitempty"`
+	Flags              PrefixAttrFlags    `json:"flags,omitempty"`
+	SourceRouterID     string             `json:"source_router_id,omitempty"`
+	SourceOSPFRouterID string             `json:"source_ospf_router_id,omitempty"`
+}
+
+// isEmpty returns true if none of Prefix Attributes is present
+func (p *PrefixAttrTLVs) isEmpty() bool {
+	return len(p.LSPrefixSID) == 0 && len(p.Range) == 0 && p.Flags == nil && p.SourceRouterID == "" && p.SourceOSPFRouterID == ""
 }
 
 // PrefixAttrFlags defines Prefix Attribute Flags interface
@@ -28,50 +34,81 @@ type PrefixAttrFlags interface {
 func (p *PrefixAttrTLVs) MarshalJSON() ([]byte, error) {
 	// Do not want to return instantiated but empty object if non of attributes present
 	// returning instantiated object if there is at least 1 initialized attribute.
-	if len(p.LSPrefixSID) == 0 && p.Flags == nil && p.SourceRouterID == "" {
+	if p.isEmpty() {
 		return nil, nil
 	}
 	switch p.Flags.(type) {
 	case *ISISFlags:
 		f := p.Flags.(*ISISFlags)
 		return json.Marshal(struct {
-			LSPrefixSID    []*sr.PrefixSIDTLV `json:"ls_prefix_sid,omitempty"`
-			Flags          *ISISFlags         `json:"flags,omitempty"`
-			SourceRouterID string             `json:"source_router_id,omitempty"`
+			LSPrefixSID        []*sr.PrefixSIDTLV `json:"ls_prefix_sid,omitempty"`
+			Range              []*PrefixRangeTLV  `json:"prefix_range,omitempty"`
+			Flags              *ISISFlags         `json:"flags,omitempty"`
+			SourceRouterID     string             `json:"source_router_id,omitempty"`
+			SourceOSPFRouterID string             `json:"source_ospf_router_id,omitempty"`
 		}{
-			Flags:          f,
-			LSPrefixSID:    p.LSPrefixSID,
-			SourceRouterID: p.SourceRouterID,
+			Flags:              f,
+			LSPrefixSID:        p.LSPrefixSID,
+			Range:              p.Range,
+			SourceRouterID:     p.SourceRouterID,
+			SourceOSPFRouterID: p.SourceOSPFRouterID,
 		})
 	case *OSPFFlags:
 		f := p.Flags.(*OSPFFlags)
 		return json.Marshal(struct {
-			LSPrefixSID    []*sr.PrefixSIDTLV `json:"ls_prefix_sid,omitempty"`
-			Flags          *OSPFFlags         `json:"flags,omitempty"`
-			SourceRouterID string             `json:"source_router_id,omitempty"`
+			LSPrefixSID        []*sr.PrefixSIDTLV `json:"ls_prefix_sid,omitempty"`
+			Range              []*PrefixRangeTLV  `json:"prefix_range,omitempty"`
+			Flags              *OSPFFlags         `json:"flags,omitempty"`
+			SourceRouterID     string             `json:"source_router_id,omitempty"`
+			SourceOSPFRouterID string             `json:"source_ospf_router_id,omitempty"`
+		}{
+			Flags:              f,
+			LSPrefixSID:        p.LSPrefixSID,
+			Range:              p.Range,
+			SourceRouterID:     p.SourceRouterID,
+			SourceOSPFRouterID: p.SourceOSPFRouterID,
+		})
+	case *OSPFv3Flags:
+		f := p.Flags.(*OSPFv3Flags)
+		return json.Marshal(struct {
+			LSPrefixSID        []*sr.PrefixSIDTLV `json:"ls_prefix_sid,omitempty"`
+			Range              []*PrefixRangeTLV  `json:"prefix_range,omitempty"`
+			Flags              *OSPFv3Flags       `json:"flags,omitempty"`
+			SourceRouterID     string             `json:"source_router_id,omitempty"`
+			SourceOSPFRouterID string             `json:"source_ospf_router_id,omitempty"`
 		}{
-			Flags:          f,
-			LSPrefixSID:    p.LSPrefixSID,
-			SourceRouterID: p.SourceRouterID,
+			Flags:              f,
+			LSPrefixSID:        p.LSPrefixSID,
+			Range:              p.Range,
+			SourceRouterID:     p.SourceRouterID,
+			SourceOSPFRouterID: p.SourceOSPFRouterID,
 		})
 	case *UnknownProtoFlags:
 		f := p.Flags.(*UnknownProtoFlags)
 		return json.Marshal(struct {
-			LSPrefixSID    []*sr.PrefixSIDTLV `json:"ls_prefix_sid,omitempty"`
-			Flags          *UnknownProtoFlags `json:"flags,omitempty"`
-			SourceRouterID string             `json:"source_router_id,omitempty"`
+			LSPrefixSID        []*sr.PrefixSIDTLV `json:"ls_prefix_sid,omitempty"`
+			Range              []*PrefixRangeTLV  `json:"prefix_range,omitempty"`
+			Flags              *UnknownProtoFlags `json:"flags,omitempty"`
+			SourceRouterID     string             `json:"source_router_id,omitempty"`
+			SourceOSPFRouterID string             `json:"source_ospf_router_id,omitempty"`
 		}{
-			Flags:          f,
-			LSPrefixSID:    p.LSPrefixSID,
-			SourceRouterID: p.SourceRouterID,
+			Flags:              f,
+			LSPrefixSID:        p.LSPrefixSID,
+			Range:              p.Range,
+			SourceRouterID:     p.SourceRouterID,
+			SourceOSPFRouterID: p.SourceOSPFRouterID,
 		})
 	default:
 		return json.Marshal(struct {
-			LSPrefixSID    []*sr.PrefixSIDTLV `json:"ls_prefix_sid,omitempty"`
-			SourceRouterID string             `json:"source_router_id,omitempty"`
+			LSPrefixSID        []*sr.PrefixSIDTLV `json:"ls_prefix_sid,omitempty"`
+			Range              []*PrefixRangeTLV  `json:"prefix_range,omitempty"`
+			SourceRouterID     string             `json:"source_router_id,omitempty"`
+			SourceOSPFRouterID string             `json:"source_ospf_router_id,omitempty"`
 		}{
-			LSPrefixSID:    p.LSPrefixSID,
-			SourceRouterID: p.SourceRouterID,
+			LSPrefixSID:        p.LSPrefixSID,
+			Range:              p.Range,
+			SourceRouterID:     p.SourceRouterID,
+			SourceOSPFRouterID: p.SourceOSPFRouterID,
 		})
 	}
 }
@@ -123,12 +160,24 @@ func (p *PrefixAttrTLVs) UnmarshalJSON(b []byte) error {
 			return err
 		}
 	}
+	// Range []*PrefixRangeTLV `json:"prefix_range,omitempty"`
+	if v, ok := objVal["prefix_range"]; ok {
+		if err := json.Unmarshal(v, &result.Range); err != nil {
+			return err
+		}
+	}
 	// SourceRouterID string             `json:"source_router_id,omitempty"`
 	if v, ok := objVal["source_router_id"]; ok {
 		if err := json.Unmarshal(v, &result.SourceRouterID); err != nil {
 			return err
 		}
 	}
+	// SourceOSPFRouterID string `json:"source_ospf_router_id,omitempty"`
+	if v, ok := objVal["source_ospf_router_id"]; ok {
+		if err := json.Unmarshal(v, &result.SourceOSPFRouterID); err != nil {
+			return err
+		}
+	}
 	*p = *result
 
 	return nil
@@ -136,7 +185,7 @@ func (p *PrefixAttrTLVs) UnmarshalJSON(b []byte) error {
 
 // UnmarshalPrefixSIDTLV builds Prefix SID TLV Object
 func UnmarshalPrefixAttrFlags(b []byte, proto base.ProtoID) (PrefixAttrFlags, error) {
-	if glog.V(6) {
+	if logging.V(logging.BGPLS, 6) {
 		glog.Infof("Prefix Attr Flags Raw: %s for proto: %+v", tools.MessageHex(b), proto)
 	}
 	p := 0
@@ -249,7 +298,7 @@ func (f *OSPFFlags) GetPrefixAttrFlagsByte() byte {
 		b += 0x80
 	}
 	if f.NFlag {
-		b += 0x20
+		b += 0x40
 	}
 
 	return b
@@ -310,12 +359,18 @@ func (ls *NLRI) GetPrefixAttrTLVs(proto base.ProtoID) (*PrefixAttrTLVs, error) {
 	if paf, err := ls.GetLSPrefixAttrFlags(proto); err == nil {
 		pr.Flags = paf
 	}
+	if r, err := ls.GetLSPrefixRange(proto); err == nil && len(r) != 0 {
+		pr.Range = r
+	}
 	if s, err := ls.GetLSSourceRouterID(); err == nil {
 		pr.SourceRouterID = s
 	}
+	if s, err := ls.GetLSSourceOSPFRouterID(); err == nil {
+		pr.SourceOSPFRouterID = s
+	}
 	// Do not want to return instantiated but empty object if non of attributes present
 	// returning instantiated object if there is at least 1 initialized attribute.
-	if len(pr.LSPrefixSID) == 0 && pr.Flags == nil && pr.SourceRouterID == "" {
+	if pr.isEmpty() {
 		return nil, fmt.Errorf("none of prefix attribute tlvs is present")
 	}
 
@@ -0,0 +1,119 @@
+package bgpls
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	"github.com/golang/glog"
+	"github.com/sbezverk/gobmp/pkg/addr"
+	"github.com/sbezverk/gobmp/pkg/base"
+	"github.com/sbezverk/gobmp/pkg/logging"
+	"github.com/sbezverk/gobmp/pkg/sr"
+	"github.com/sbezverk/tools"
+)
+
+// PrefixRangeTLV defines Range TLV object
+// https://www.rfc-editor.org/rfc/rfc9085.html#section-2.3.5
+type PrefixRangeTLV struct {
+	Flags       uint8              `json:"flags"`
+	RangeSize   uint16             `json:"range_size"`
+	LSPrefixSID []*sr.PrefixSIDTLV `json:"ls_prefix_sid,omitempty"`
+}
+
+// UnmarshalPrefixRangeTLV builds Range TLV object, Range TLV carries Prefix SID TLVs (1158)
+// as sub tlvs, the flags of the Prefix SID sub tlvs are decoded according to the protocol.
+func UnmarshalPrefixRangeTLV(b []byte, proto base.ProtoID) (*PrefixRangeTLV, error) {
+	if logging.V(logging.BGPLS, 6) {
+		glog.Infof("Prefix Range TLV Raw: %s for proto: %+v", tools.MessageHex(b), proto)
+	}
+	if len(b) < 4 {
+		return nil, fmt.Errorf("not enough bytes to unmarshal Prefix Range TLV")
+	}
+	r := &PrefixRangeTLV{}
+	p := 0
+	r.Flags = b[p]
+	p++
+	// Skip reserved byte
+	p++
+	r.RangeSize = binary.BigEndian.Uint16(b[p : p+2])
+	p += 2
+	for p < len(b) {
+		if p+4 > len(b) {
+			return nil, fmt.Errorf("not enough bytes to unmarshal Prefix Range sub tlv")
+		}
+		t := binary.BigEndian.Uint16(b[p : p+2])
+		p += 2
+		l := int(binary.BigEndian.Uint16(b[p : p+2]))
+		p += 2
+		if p+l > len(b) {
+			return nil, fmt.Errorf("invalid length %d of Prefix Range sub tlv type %d", l, t)
+		}
+		switch t {
+		case 1158:
+			psid, err := sr.UnmarshalPrefixSIDTLV(b[p:p+l], proto)
+			if err != nil {
+				return nil, err
+			}
+			r.LSPrefixSID = append(r.LSPrefixSID, psid)
+		default:
+			glog.Warningf("unknown Prefix Range sub tlv type %d", t)
+		}
+		p += l
+	}
+
+	return r, nil
+}
+
+// Marshal returns a wire format representation of Range TLV value including Prefix SID sub tlvs
+func (r *PrefixRangeTLV) Marshal() ([]byte, error) {
+	b := make([]byte, 4)
+	b[0] = r.Flags
+	// b[1] is reserved
+	binary.BigEndian.PutUint16(b[2:4], r.RangeSize)
+	for _, psid := range r.LSPrefixSID {
+		v, err := psid.Marshal()
+		if err != nil {
+			return nil, err
+		}
+		tlv := TLV{
+			Type:  1158,
+			Value: v,
+		}
+		b = append(b, tlv.Marshal()...)
+	}
+
+	return b, nil
+}
+
+// GetLSPrefixRange returns a slice of Range TLV objects
+func (ls *NLRI) GetLSPrefixRange(proto base.ProtoID) ([]*PrefixRangeTLV, error) {
+	rs := make([]*PrefixRangeTLV, 0)
+	for _, tlv := range ls.LS {
+		if tlv.Type != 1159 {
+			continue
+		}
+		r, err := UnmarshalPrefixRangeTLV(tlv.Value, proto)
+		if err != nil {
+			return nil, err
+		}
+		rs = append(rs, r)
+	}
+
+	return rs, nil
+}
+
+// GetLSSourceOSPFRouterID returns a Source OSPF Router ID of the prefix originator
+// https://www.rfc-editor.org/rfc/rfc9085.html#section-2.3.4
+func (ls *NLRI) GetLSSourceOSPFRouterID() (string, error) {
+	for _, tlv := range ls.LS {
+		if tlv.Type != 1174 {
+			continue
+		}
+		if len(tlv.Value) != 4 {
+			return "", fmt.Errorf("invalid length %d of Source OSPF Router ID TLV", len(tlv.Value))
+		}
+		return addr.IPv4(tlv.Value), nil
+	}
+
+	return "", fmt.Errorf("not found")
+}
@@ -0,0 +1,145 @@
+package bgpls
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/sbezverk/gobmp/pkg/base"
+	"github.com/sbezverk/gobmp/pkg/sr"
+)
+
+func TestUnmarshalPrefixRangeTLV(t *testing.T) {
+	index100 := uint32(100)
+	tests := []struct {
+		name   string
+		input  []byte
+		proto  base.ProtoID
+		expect *PrefixRangeTLV
+		fail   bool
+	}{
+		{
+			name:  "isis range with prefix sid index",
+			input: []byte{0x00, 0x00, 0x00, 0x10, 0x04, 0x86, 0x00, 0x08, 0x40, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x64},
+			proto: base.ISISL2,
+			expect: &PrefixRangeTLV{
+				Flags:     0,
+				RangeSize: 16,
+				LSPrefixSID: []*sr.PrefixSIDTLV{
+					{
+						Flags:    &sr.ISISFlags{NFlag: true},
+						SID:      100,
+						SIDValue: &sr.SIDValue{Type: sr.SIDTypeIndex, Index: &index100},
+					},
+				},
+			},
+		},
+		{
+			name:  "ospf range without sub tlvs",
+			input: []byte{0x80, 0x00, 0x00, 0x08},
+			proto: base.OSPFv2,
+			expect: &PrefixRangeTLV{
+				Flags:     0x80,
+				RangeSize: 8,
+			},
+		},
+		{
+			name:  "too short",
+			input: []byte{0x00, 0x00, 0x00},
+			proto: base.ISISL2,
+			fail:  true,
+		},
+		{
+			name:  "truncated sub tlv",
+			input: []byte{0x00, 0x00, 0x00, 0x10, 0x04, 0x86, 0x00, 0x08, 0x40, 0x00},
+			proto: base.ISISL2,
+			fail:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := UnmarshalPrefixRangeTLV(tt.input, tt.proto)
+			if err != nil && !tt.fail {
+				t.Fatalf("supposed to succeed but failed with error: %+v", err)
+			}
+			if err == nil && tt.fail {
+				t.Fatalf("supposed to fail but succeeded")
+			}
+			if err != nil {
+				return
+			}
+			if !reflect.DeepEqual(got, tt.expect) {
+				t.Errorf("expected %+v does not match computed %+v", tt.expect, got)
+			}
+		})
+	}
+}
+
+func TestGetPrefixAttrTLVsRangeAndSourceOSPFRouterID(t *testing.T) {
+	ls := &NLRI{
+		LS: []TLV{
+			{
+				Type:   1159,
+				Length: 4,
+				Value:  []byte{0x00, 0x00, 0x00, 0x20},
+			},
+			{
+				Type:   1174,
+				Length: 4,
+				Value:  []byte{0x0a, 0x00, 0x00, 0x01},
+			},
+		},
+	}
+	pr, err := ls.GetPrefixAttrTLVs(base.OSPFv2)
+	if err != nil {
+		t.Fatalf("failed to get Prefix Attributes with error: %+v", err)
+	}
+	if pr.SourceOSPFRouterID != "10.0.0.1" {
+		t.Errorf("expected source ospf router id 10.0.0.1 but got %s", pr.SourceOSPFRouterID)
+	}
+	if len(pr.Range) != 1 || pr.Range[0].RangeSize != 32 {
+		t.Fatalf("expected a single range of size 32 but got %+v", pr.Range)
+	}
+	b, err := json.Marshal(pr)
+	if err != nil {
+		t.Fatalf("failed to marshal Prefix Attributes with error: %+v", err)
+	}
+	result := &PrefixAttrTLVs{}
+	if err := json.Unmarshal(b, result); err != nil {
+		t.Fatalf("failed to unmarshal Prefix Attributes with error: %+v", err)
+	}
+	rb, err := json.Marshal(result)
+	if err != nil {
+		t.Fatalf("failed to marshal unmarshaled Prefix Attributes with error: %+v", err)
+	}
+	if !reflect.DeepEqual(b, rb) {
+		t.Errorf("expected %s does not match unmarshaled %s", string(b), string(rb))
+	}
+}
+
+func TestMarshalPrefixRangeTLV(t *testing.T) {
+	input := []byte{0x00, 0x00, 0x00, 0x10, 0x04, 0x86, 0x00, 0x08, 0x40, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x64}
+	r, err := UnmarshalPrefixRangeTLV(input, base.ISISL2)
+	if err != nil {
+		t.Fatalf("failed to unmarshal Prefix Range TLV with error: %+v", err)
+	}
+	result, err := r.Marshal()
+	if err != nil {
+		t.Fatalf("failed to marshal Prefix Range TLV with error: %+v", err)
+	}
+	if !reflect.DeepEqual(input, result) {
+		t.Fatalf("expected %+v does not match marshaled %+v", input, result)
+	}
+	ls := &NLRI{
+		LS: []TLV{
+			{Type: 1159, Length: uint16(len(result)), Value: result},
+		},
+	}
+	tlvs, err := UnmarshalBGPLSTLV(ls.Marshal())
+	if err != nil {
+		t.Fatalf("failed to unmarshal BGP-LS TLVs with error: %+v", err)
+	}
+	if !reflect.DeepEqual(ls.LS, tlvs) {
+		t.Fatalf("expected %+v does not match unmarshaled %+v", ls.LS, tlvs)
+	}
+}
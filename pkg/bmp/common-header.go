@@ -5,6 +5,7 @@ import (
 	"fmt"
 
 	"github.com/golang/glog"
+	"github.com/sbezverk/gobmp/pkg/logging"
 	"github.com/sbezverk/tools"
 )
 
@@ -21,12 +22,13 @@ type CommonHeader struct {
 
 // UnmarshalCommonHeader processes Common Header and returns BMPCommonHeader object
 func UnmarshalCommonHeader(b []byte) (*CommonHeader, error) {
-	if glog.V(6) {
+	if logging.V(logging.BMP, 6) {
 		glog.Infof("BMP CommonHeader Raw: %s", tools.MessageHex(b))
 	}
 	ch := &CommonHeader{}
-	if b[0] != 3 {
-		return nil, fmt.Errorf("invalid version in common header, expected 3 found %d", b[0])
+	// Version 4 is BMP with TLV extensions per draft-ietf-grow-bmp-tlv
+	if b[0] != 3 && b[0] != 4 {
+		return nil, fmt.Errorf("invalid version in common header, expected 3 or 4 found %d", b[0])
 	}
 	ch.Version = b[0]
 	ch.MessageLength = int32(binary.BigEndian.Uint32(b[1:5]))
@@ -61,3 +63,27 @@ func (c *CommonHeader) Serialize() ([]byte, error) {
 	b[5] = c.MessageType
 	return b, nil
 }
+
+// SerializeMessage generates a complete BMP message of type t, Per-Peer header is included
+// when pph is not nil.
+func SerializeMessage(t byte, pph *PerPeerHeader, payload []byte) ([]byte, error) {
+	var ph []byte
+	if pph != nil {
+		var err error
+		if ph, err = pph.Serialize(); err != nil {
+			return nil, err
+		}
+	}
+	ch := &CommonHeader{
+		Version:       3,
+		MessageLength: int32(BMP_HEADER_SIZE + len(ph) + len(payload)),
+		MessageType:   t,
+	}
+	b, err := ch.Serialize()
+	if err != nil {
+		return nil, err
+	}
+	b = append(b, ph...)
+
+	return append(b, payload...), nil
+}
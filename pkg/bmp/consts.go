@@ -1,5 +1,7 @@
 package bmp
 
+import "sort"
+
 const (
 	// CommonHeaderLength defines the length of BMP's Common header
 	CommonHeaderLength = 6
@@ -56,4 +58,75 @@ const (
 	FlowspecV4Msg = 164
 	// FlowspecV6Msg defines BMP Route Monitoring message carrying Flowspec NLRI
 	FlowspecV6Msg = 166
+	// TopologyEventMsg defines message carrying events derived from changes of BGP-LS topology
+	TopologyEventMsg = 17
+	// PrefixFlapMsg defines message carrying event of a prefix changing too often
+	PrefixFlapMsg = 18
+	// AlertMsg defines message carrying alert raised when peer's thresholds are exceeded
+	AlertMsg = 19
+	// RTConstraintMsg defines BMP Route Monitoring message carrying Route Target membership NLRI
+	RTConstraintMsg = 20
+	// MUPMsg defines BMP Route Monitoring message carrying BGP-MUP NLRI
+	MUPMsg = 21
+	// BGPEPEMsg defines message carrying BGP Egress Peer Engineering SIDs of BGP-LS Link NLRI
+	BGPEPEMsg = 22
+	// MACEventMsg defines message carrying events of MACs moving between EVPN locations
+	MACEventMsg = 23
 )
+
+var msgTypeNames = map[int]string{
+	PeerStateChangeMsg: "peer",
+	UnicastPrefixMsg:   "unicast_prefix",
+	UnicastPrefixV4Msg: "unicast_prefix_v4",
+	UnicastPrefixV6Msg: "unicast_prefix_v6",
+	LSNodeMsg:          "ls_node",
+	LSLinkMsg:          "ls_link",
+	L3VPNMsg:           "l3vpn",
+	L3VPNV4Msg:         "l3vpn_v4",
+	L3VPNV6Msg:         "l3vpn_v6",
+	LSPrefixMsg:        "ls_prefix",
+	LSSRv6SIDMsg:       "ls_srv6_sid",
+	EVPNMsg:            "evpn",
+	SRPolicyMsg:        "sr_policy",
+	SRPolicyV4Msg:      "sr_policy_v4",
+	SRPolicyV6Msg:      "sr_policy_v6",
+	FlowspecMsg:        "flowspec",
+	FlowspecV4Msg:      "flowspec_v4",
+	FlowspecV6Msg:      "flowspec_v6",
+	StatsReportMsg:     "statistics",
+	TopologyEventMsg:   "topology_event",
+	PrefixFlapMsg:      "prefix_flap",
+	AlertMsg:           "alert",
+	RTConstraintMsg:    "rt_constraint",
+	MUPMsg:             "mup",
+	BGPEPEMsg:          "bgp_epe",
+	MACEventMsg:        "mac_event",
+}
+
+// MsgTypeName returns the name of a published message type, the name matches the suffix
+// of the message's Kafka topic, empty string is returned for unknown types.
+func MsgTypeName(t int) string {
+	return msgTypeNames[t]
+}
+
+// MsgTypeByName returns the published message type by its name
+func MsgTypeByName(name string) (int, bool) {
+	for t, n := range msgTypeNames {
+		if n == name {
+			return t, true
+		}
+	}
+
+	return 0, false
+}
+
+// MsgTypes returns all published message types in ascending order
+func MsgTypes() []int {
+	types := make([]int, 0, len(msgTypeNames))
+	for t := range msgTypeNames {
+		types = append(types, t)
+	}
+	sort.Ints(types)
+
+	return types
+}
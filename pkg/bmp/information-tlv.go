@@ -5,6 +5,7 @@ import (
 	"fmt"
 
 	"github.com/golang/glog"
+	"github.com/sbezverk/gobmp/pkg/logging"
 	"github.com/sbezverk/tools"
 )
 
@@ -17,7 +18,7 @@ type InformationalTLV struct {
 
 // UnmarshalTLV builds a slice of Informational TLVs
 func UnmarshalTLV(b []byte) ([]InformationalTLV, error) {
-	if glog.V(6) {
+	if logging.V(logging.BMP, 6) {
 		glog.Infof("BMP Informational TLV Raw: %s", tools.MessageHex(b))
 	}
 	tlvs := make([]InformationalTLV, 0)
@@ -40,3 +41,17 @@ func UnmarshalTLV(b []byte) ([]InformationalTLV, error) {
 
 	return tlvs, nil
 }
+
+// SerializeTLV generates a slice of bytes from a slice of Informational TLVs
+func SerializeTLV(tlvs []InformationalTLV) []byte {
+	b := make([]byte, 0)
+	for _, tlv := range tlvs {
+		h := make([]byte, 4)
+		binary.BigEndian.PutUint16(h[0:2], uint16(tlv.InformationType))
+		binary.BigEndian.PutUint16(h[2:4], uint16(len(tlv.Information)))
+		b = append(b, h...)
+		b = append(b, tlv.Information...)
+	}
+
+	return b
+}
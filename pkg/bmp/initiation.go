@@ -5,6 +5,7 @@ import (
 	"fmt"
 
 	"github.com/golang/glog"
+	"github.com/sbezverk/gobmp/pkg/logging"
 	"github.com/sbezverk/tools"
 )
 
@@ -15,7 +16,7 @@ type InitiationMessage struct {
 
 // UnmarshalInitiationMessage processes Initiation Message and returns BMPInitiationMessage object
 func UnmarshalInitiationMessage(b []byte) (*InitiationMessage, error) {
-	if glog.V(6) {
+	if logging.V(logging.BMP, 6) {
 		glog.Infof("BMP Initiation Message Raw: %s", tools.MessageHex(b))
 	}
 	im := &InitiationMessage{
@@ -29,7 +30,9 @@ func UnmarshalInitiationMessage(b []byte) (*InitiationMessage, error) {
 		case 1:
 		case 2:
 		default:
-			return nil, fmt.Errorf("invalid tlv type, expected between 0 and 2 found %d", t)
+			if !IsVendorTLV(InitiationMsg, 0, uint16(t)) {
+				return nil, fmt.Errorf("invalid tlv type, expected between 0 and 2 found %d", t)
+			}
 		}
 		// Extracting TLV length
 		l := int16(binary.BigEndian.Uint16(b[i+2 : i+4]))
@@ -47,3 +50,8 @@ func UnmarshalInitiationMessage(b []byte) (*InitiationMessage, error) {
 
 	return im, nil
 }
+
+// Serialize generates a slice of bytes from InitiationMessage structure
+func (im *InitiationMessage) Serialize() ([]byte, error) {
+	return SerializeTLV(im.TLV), nil
+}
@@ -1,8 +1,14 @@
 package bmp
 
+import "github.com/sbezverk/gobmp/pkg/tracing"
+
 // Message defines a message used to transfer BMP messages for further processing
 // for BMP messages which do not carry PerPeerHeader, it will be set to nil.
+// Span is optional span tracing the BMP message from its receive to publishing, RawHeaders carries
+// Common Header and Per Peer Header of Route Monitoring message as received.
 type Message struct {
 	PeerHeader *PerPeerHeader
 	Payload    interface{}
+	Span       *tracing.Span
+	RawHeaders []byte
 }
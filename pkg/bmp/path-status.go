@@ -0,0 +1,88 @@
+package bmp
+
+import (
+	"encoding/binary"
+	"fmt"
+)
+
+// PathStatusTLV defines the type of Path Status TLV carried in BMP v4 Route Monitoring messages
+// per draft-ietf-grow-bmp-path-marking-tlv.
+const PathStatusTLV = 9
+
+var pathStatusNames = []struct {
+	bit  uint32
+	name string
+}{
+	{0x00000001, "invalid"},
+	{0x00000002, "best"},
+	{0x00000004, "non_selected"},
+	{0x00000008, "primary"},
+	{0x00000010, "backup"},
+	{0x00000020, "non_installed"},
+	{0x00000040, "best_external"},
+	{0x00000080, "add_path"},
+	{0x00000100, "filtered_inbound"},
+	{0x00000200, "filtered_outbound"},
+	{0x00000400, "stale"},
+	{0x00000800, "suppressed"},
+}
+
+var pathStatusReasons = map[uint16]string{
+	0x0000: "invalid_unknown",
+	0x0001: "invalid_super_network",
+	0x0002: "invalid_dampening",
+	0x0003: "invalid_damping_history",
+	0x0004: "invalid_policy_deny",
+	0x0005: "invalid_roa_not_found",
+	0x0006: "invalid_roa_invalid",
+}
+
+// PathStatus defines the content of Path Status TLV, Reason is present only when the TLV carries
+// the optional reason code.
+type PathStatus struct {
+	Status uint32
+	Reason *uint16
+}
+
+// UnmarshalPathStatus builds PathStatus object from the value of Path Status TLV
+func UnmarshalPathStatus(b []byte) (*PathStatus, error) {
+	if len(b) != 4 && len(b) != 6 {
+		return nil, fmt.Errorf("invalid length %d of path status tlv", len(b))
+	}
+	ps := &PathStatus{
+		Status: binary.BigEndian.Uint32(b[0:4]),
+	}
+	if len(b) == 6 {
+		r := binary.BigEndian.Uint16(b[4:6])
+		ps.Reason = &r
+	}
+
+	return ps, nil
+}
+
+// Names returns names of all set path status bits, status 0 is unknown
+func (ps *PathStatus) Names() []string {
+	names := make([]string, 0)
+	for _, s := range pathStatusNames {
+		if ps.Status&s.bit != 0 {
+			names = append(names, s.name)
+		}
+	}
+	if len(names) == 0 {
+		names = append(names, "unknown")
+	}
+
+	return names
+}
+
+// ReasonName returns the name of the reason code or empty string when the reason is not present
+func (ps *PathStatus) ReasonName() string {
+	if ps.Reason == nil {
+		return ""
+	}
+	if n, ok := pathStatusReasons[*ps.Reason]; ok {
+		return n
+	}
+
+	return fmt.Sprintf("reason_%d", *ps.Reason)
+}
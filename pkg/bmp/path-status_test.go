@@ -0,0 +1,58 @@
+package bmp
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestUnmarshalPathStatus(t *testing.T) {
+	tests := []struct {
+		name   string
+		input  []byte
+		names  []string
+		reason string
+		fail   bool
+	}{
+		{
+			name:  "best and primary",
+			input: []byte{0, 0, 0, 0x0a},
+			names: []string{"best", "primary"},
+		},
+		{
+			name:   "invalid with reason",
+			input:  []byte{0, 0, 0, 0x01, 0, 0x06},
+			names:  []string{"invalid"},
+			reason: "invalid_roa_invalid",
+		},
+		{
+			name:  "no status bits",
+			input: []byte{0, 0, 0, 0},
+			names: []string{"unknown"},
+		},
+		{
+			name:  "invalid length",
+			input: []byte{0, 0, 1},
+			fail:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ps, err := UnmarshalPathStatus(tt.input)
+			if err != nil && !tt.fail {
+				t.Fatalf("expected to succeed but failed with error: %+v", err)
+			}
+			if err == nil && tt.fail {
+				t.Fatalf("expected to fail but succeeded")
+			}
+			if err != nil {
+				return
+			}
+			if !reflect.DeepEqual(tt.names, ps.Names()) {
+				t.Errorf("expected names %v, got %v", tt.names, ps.Names())
+			}
+			if tt.reason != ps.ReasonName() {
+				t.Errorf("expected reason %q, got %q", tt.reason, ps.ReasonName())
+			}
+		})
+	}
+}
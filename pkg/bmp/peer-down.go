@@ -4,6 +4,7 @@ import (
 	"fmt"
 
 	"github.com/golang/glog"
+	"github.com/sbezverk/gobmp/pkg/logging"
 	"github.com/sbezverk/tools"
 )
 
@@ -15,7 +16,7 @@ type PeerDownMessage struct {
 
 // UnmarshalPeerDownMessage processes Peer Down message and returns BMPPeerDownMessage object
 func UnmarshalPeerDownMessage(b []byte) (*PeerDownMessage, error) {
-	if glog.V(6) {
+	if logging.V(logging.BMP, 6) {
 		glog.Infof("BMP Peer Down Message Raw: %s", tools.MessageHex(b))
 	}
 	pdw := &PeerDownMessage{
@@ -31,3 +32,12 @@ func UnmarshalPeerDownMessage(b []byte) (*PeerDownMessage, error) {
 
 	return pdw, nil
 }
+
+// Serialize generates a slice of bytes from PeerDownMessage structure
+func (pdw *PeerDownMessage) Serialize() ([]byte, error) {
+	if pdw.Reason < 1 || pdw.Reason > 5 {
+		return nil, fmt.Errorf("invalid reason code %d in Peer Down message", pdw.Reason)
+	}
+
+	return append([]byte{pdw.Reason}, pdw.Data...), nil
+}
@@ -2,10 +2,12 @@ package bmp
 
 import (
 	"encoding/binary"
-	"net"
+	"fmt"
 
 	"github.com/golang/glog"
+	"github.com/sbezverk/gobmp/pkg/addr"
 	"github.com/sbezverk/gobmp/pkg/bgp"
+	"github.com/sbezverk/gobmp/pkg/logging"
 	"github.com/sbezverk/tools"
 )
 
@@ -22,14 +24,46 @@ type PeerUpMessage struct {
 
 func (pum *PeerUpMessage) GetLocalAddressString() string {
 	if pum.isRemotePeerIPv6 {
-		return net.IP(pum.LocalAddress).To16().String()
+		return addr.IPv6(pum.LocalAddress)
 	}
-	return net.IP(pum.LocalAddress[12:]).To4().String()
+	return addr.IPv4(pum.LocalAddress[12:])
+}
+
+// PeerUpVRFTableNameTLV defines the type of Peer Up Information TLV carrying the name of VRF or table
+// the peer belongs to, rfc9069
+const PeerUpVRFTableNameTLV = 3
+
+// TableName returns the name of VRF or table advertised in Peer Up Information TLVs, or empty string
+// when the router does not advertise it.
+func (pum *PeerUpMessage) TableName() string {
+	for _, tlv := range pum.Information {
+		if tlv.InformationType == PeerUpVRFTableNameTLV {
+			return string(tlv.Information)
+		}
+	}
+
+	return ""
+}
+
+// peerUpAdminLabelTLV defines the type of Peer Up Information TLV carrying Admin Label, the highest type
+// assigned by IANA, Information TLVs of higher types are vendor specific.
+const peerUpAdminLabelTLV = 4
+
+// VendorInformation returns Peer Up Information TLVs of types not defined by BMP RFCs
+func (pum *PeerUpMessage) VendorInformation() []InformationalTLV {
+	var tlvs []InformationalTLV
+	for _, tlv := range pum.Information {
+		if uint16(tlv.InformationType) > peerUpAdminLabelTLV {
+			tlvs = append(tlvs, tlv)
+		}
+	}
+
+	return tlvs
 }
 
 // UnmarshalPeerUpMessage processes Peer Up message and returns BMPPeerUpMessage object
 func UnmarshalPeerUpMessage(b []byte, isIPv6 bool) (*PeerUpMessage, error) {
-	if glog.V(6) {
+	if logging.V(logging.BMP, 6) {
 		glog.Infof("BMP Peer Up Message Raw: %s", tools.MessageHex(b))
 	}
 	var err error
@@ -76,3 +110,29 @@ func UnmarshalPeerUpMessage(b []byte, isIPv6 bool) (*PeerUpMessage, error) {
 	}
 	return pu, nil
 }
+
+// Serialize generates a slice of bytes from PeerUpMessage structure
+func (pum *PeerUpMessage) Serialize() ([]byte, error) {
+	if len(pum.LocalAddress) != 16 {
+		return nil, fmt.Errorf("invalid length %d of local address", len(pum.LocalAddress))
+	}
+	if pum.SentOpen == nil || pum.ReceivedOpen == nil {
+		return nil, fmt.Errorf("both sent and received open messages must be present")
+	}
+	b := make([]byte, 20)
+	copy(b, pum.LocalAddress)
+	binary.BigEndian.PutUint16(b[16:18], pum.LocalPort)
+	binary.BigEndian.PutUint16(b[18:20], pum.RemotePort)
+	sent, err := pum.SentOpen.Serialize()
+	if err != nil {
+		return nil, err
+	}
+	b = append(b, sent...)
+	received, err := pum.ReceivedOpen.Serialize()
+	if err != nil {
+		return nil, err
+	}
+	b = append(b, received...)
+
+	return append(b, SerializeTLV(pum.Information)...), nil
+}
@@ -14,6 +14,7 @@ func TestPeerUpMsg(t *testing.T) {
 		input          []byte
 		remotePeerIPv6 bool
 		expect         *PeerUpMessage
+		tableName      string
 	}{
 		{
 			name:  "panic 1",
@@ -35,14 +36,17 @@ func TestPeerUpMsg(t *testing.T) {
 							{
 								Value:       []byte{0, 1, 0, 1},
 								Description: "Multiprotocol Extensions for BGP-4 : afi=1 safi=1 Unicast IPv4",
+								Parameters:  &bgp.CapabilityParameters{AFI: 1, SAFI: 1},
 							},
 							{
 								Value:       []byte{0, 1, 0, 4},
 								Description: "Multiprotocol Extensions for BGP-4 : afi=1 safi=4 MPLS Labels IPv4",
+								Parameters:  &bgp.CapabilityParameters{AFI: 1, SAFI: 4},
 							},
 							{
 								Value:       []byte{0, 1, 0, 128},
 								Description: "Multiprotocol Extensions for BGP-4 : afi=1 safi=128 MPLS-labeled VPN IPv4",
+								Parameters:  &bgp.CapabilityParameters{AFI: 1, SAFI: 128},
 							},
 						},
 						2: []*bgp.CapabilityData{
@@ -55,12 +59,14 @@ func TestPeerUpMsg(t *testing.T) {
 							{
 								Value:       []byte{0, 1, 0, 1, 0, 2, 0, 1, 0, 2, 0, 2, 0, 1, 0, 128, 0, 2},
 								Description: "Extended Next Hop Encoding",
+								Parameters:  &bgp.CapabilityParameters{ExtendedNextHop: []*bgp.ExtendedNextHopTuple{{AFI: 1, SAFI: 1, NextHopAFI: 2}, {AFI: 1, SAFI: 2, NextHopAFI: 2}, {AFI: 1, SAFI: 128, NextHopAFI: 2}}},
 							},
 						},
 						65: []*bgp.CapabilityData{
 							{
 								Value:       []byte{0, 0, 195, 203},
 								Description: "Support for 4-octet AS number capability",
+								Parameters:  &bgp.CapabilityParameters{ASN: 50123},
 							},
 						},
 						128: []*bgp.CapabilityData{
@@ -84,14 +90,17 @@ func TestPeerUpMsg(t *testing.T) {
 							{
 								Value:       []byte{0, 1, 0, 1},
 								Description: "Multiprotocol Extensions for BGP-4 : afi=1 safi=1 Unicast IPv4",
+								Parameters:  &bgp.CapabilityParameters{AFI: 1, SAFI: 1},
 							},
 							{
 								Value:       []byte{0, 1, 0, 4},
 								Description: "Multiprotocol Extensions for BGP-4 : afi=1 safi=4 MPLS Labels IPv4",
+								Parameters:  &bgp.CapabilityParameters{AFI: 1, SAFI: 4},
 							},
 							{
 								Value:       []byte{0, 1, 0, 128},
 								Description: "Multiprotocol Extensions for BGP-4 : afi=1 safi=128 MPLS-labeled VPN IPv4",
+								Parameters:  &bgp.CapabilityParameters{AFI: 1, SAFI: 128},
 							},
 						},
 						2: []*bgp.CapabilityData{
@@ -104,12 +113,14 @@ func TestPeerUpMsg(t *testing.T) {
 							{
 								Value:       []byte{0, 1, 0, 1, 0, 2, 0, 1, 0, 2, 0, 2, 0, 1, 0, 128, 0, 2},
 								Description: "Extended Next Hop Encoding",
+								Parameters:  &bgp.CapabilityParameters{ExtendedNextHop: []*bgp.ExtendedNextHopTuple{{AFI: 1, SAFI: 1, NextHopAFI: 2}, {AFI: 1, SAFI: 2, NextHopAFI: 2}, {AFI: 1, SAFI: 128, NextHopAFI: 2}}},
 							},
 						},
 						65: []*bgp.CapabilityData{
 							{
 								Value:       []byte{0, 0, 195, 203},
 								Description: "Support for 4-octet AS number capability",
+								Parameters:  &bgp.CapabilityParameters{ASN: 50123},
 							},
 						},
 						128: []*bgp.CapabilityData{
@@ -128,6 +139,7 @@ func TestPeerUpMsg(t *testing.T) {
 					},
 				},
 			},
+			tableName: "global",
 		},
 	}
 	for _, tt := range tests {
@@ -140,6 +152,9 @@ func TestPeerUpMsg(t *testing.T) {
 				t.Logf("differences: %+v", deep.Equal(tt.expect, peerUp))
 				t.Fatal("expected PeerUp message does not match the unmarshaled one")
 			}
+			if tn := peerUp.TableName(); tn != tt.tableName {
+				t.Fatalf("expected table name %q, got %q", tt.tableName, tn)
+			}
 		})
 	}
 }
@@ -3,6 +3,7 @@ package bmp
 import (
 	"crypto/md5"
 	"encoding/binary"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"net"
@@ -10,7 +11,9 @@ import (
 	"time"
 
 	"github.com/golang/glog"
+	"github.com/sbezverk/gobmp/pkg/addr"
 	"github.com/sbezverk/gobmp/pkg/base"
+	"github.com/sbezverk/gobmp/pkg/logging"
 	"github.com/sbezverk/tools"
 )
 
@@ -51,6 +54,69 @@ type PerPeerHeader struct {
 	PeerAS            uint32
 	PeerBGPID         []byte
 	PeerTimestamp     []byte
+	// collectorTimestamp is the time when the message carrying the header was received by the collector
+	collectorTimestamp time.Time
+}
+
+// NewPerPeerHeader returns Per-Peer header of a Global Instance Peer, V flag is set when peer address
+// is IPv6, L flag is set when adj-rib-in post policy routes are monitored.
+func NewPerPeerHeader(peerAddr net.IP, peerAS uint32, peerBGPID net.IP, ts time.Time, postPolicy bool) *PerPeerHeader {
+	pph := &PerPeerHeader{
+		PeerType:          PeerType0,
+		flagL:             postPolicy,
+		PeerDistinguisher: make([]byte, 8),
+		PeerAddress:       make([]byte, 16),
+		PeerAS:            peerAS,
+		PeerBGPID:         make([]byte, 4),
+		PeerTimestamp:     make([]byte, 8),
+	}
+	if peerAddr.To4() != nil {
+		copy(pph.PeerAddress[12:], peerAddr.To4())
+	} else {
+		pph.flagV = true
+		copy(pph.PeerAddress, peerAddr.To16())
+	}
+	copy(pph.PeerBGPID, peerBGPID.To4())
+	binary.BigEndian.PutUint32(pph.PeerTimestamp[0:4], uint32(ts.Unix()))
+	binary.BigEndian.PutUint32(pph.PeerTimestamp[4:8], uint32(ts.Nanosecond()/1000))
+
+	return pph
+}
+
+// Serialize generates a slice of bytes from PerPeerHeader structure
+func (p *PerPeerHeader) Serialize() ([]byte, error) {
+	if len(p.PeerDistinguisher) != 8 || len(p.PeerAddress) != 16 || len(p.PeerBGPID) != 4 || len(p.PeerTimestamp) != 8 {
+		return nil, fmt.Errorf("invalid Per-Peer header fields length")
+	}
+	b := make([]byte, 0, BMP_PEER_HEADER_SIZE)
+	var f byte
+	if p.PeerType == PeerType3 {
+		if p.flagF {
+			f |= 0x80
+		}
+	} else {
+		if p.flagV {
+			f |= 0x80
+		}
+		if p.flagL {
+			f |= 0x40
+		}
+		if p.flagA {
+			f |= 0x20
+		}
+		if p.flagO {
+			f |= 0x10
+		}
+	}
+	b = append(b, byte(p.PeerType), f)
+	b = append(b, p.PeerDistinguisher...)
+	b = append(b, p.PeerAddress...)
+	as := make([]byte, 4)
+	binary.BigEndian.PutUint32(as, p.PeerAS)
+	b = append(b, as...)
+	b = append(b, p.PeerBGPID...)
+
+	return append(b, p.PeerTimestamp...), nil
 }
 
 // Len returns the length of PerPeerHeader structure
@@ -78,13 +144,47 @@ func peerType(b byte) (PeerType, error) {
 	}
 }
 
+// peerTimestamp returns Peer's timestamp, the header carries seconds and microseconds since epoch
+func (p *PerPeerHeader) peerTimestamp() time.Time {
+	sec := int64(binary.BigEndian.Uint32(p.PeerTimestamp[0:4]))
+	usec := int64(binary.BigEndian.Uint32(p.PeerTimestamp[4:8]))
+
+	return time.Unix(sec, usec*int64(time.Microsecond)).UTC()
+}
+
+// GetPeerTimestamp returns Peer's timestamp in RFC3339Nano format
 func (p *PerPeerHeader) GetPeerTimestamp() string {
-	t := time.Date(1970, time.January, 1, 0, 0, 0, 0, time.UTC)
-	ts := time.Second * time.Duration(binary.BigEndian.Uint32(p.PeerTimestamp[0:4]))
-	tms := time.Duration(int(binary.BigEndian.Uint32(p.PeerTimestamp[4:8])))
-	t = t.Add(ts)
-	t = t.Add(tms)
-	return t.Format(time.RFC3339Nano)
+	return p.peerTimestamp().Format(time.RFC3339Nano)
+}
+
+// GetPeerTimestampEpoch returns Peer's timestamp as a number of microseconds since epoch
+func (p *PerPeerHeader) GetPeerTimestampEpoch() int64 {
+	return p.peerTimestamp().UnixNano() / int64(time.Microsecond)
+}
+
+// SetCollectorTimestamp stores the time when the message carrying the header was received by the collector
+func (p *PerPeerHeader) SetCollectorTimestamp(t time.Time) {
+	p.collectorTimestamp = t
+}
+
+// GetCollectorTimestamp returns collector's receive timestamp in RFC3339Nano format, if receive timestamp
+// was not set, empty string is returned.
+func (p *PerPeerHeader) GetCollectorTimestamp() string {
+	if p.collectorTimestamp.IsZero() {
+		return ""
+	}
+
+	return p.collectorTimestamp.UTC().Format(time.RFC3339Nano)
+}
+
+// GetCollectorTimestampEpoch returns collector's receive timestamp as a number of microseconds since epoch,
+// if receive timestamp was not set, 0 is returned.
+func (p *PerPeerHeader) GetCollectorTimestampEpoch() int64 {
+	if p.collectorTimestamp.IsZero() {
+		return 0
+	}
+
+	return p.collectorTimestamp.UnixNano() / int64(time.Microsecond)
 }
 
 // GetPeerHash calculates Peer Hash and returns as a hex string
@@ -100,17 +200,17 @@ func (p *PerPeerHeader) GetPeerHash() string {
 
 // GetPeerBGPIDString returns a string representation of Peer BGP ID
 func (p *PerPeerHeader) GetPeerBGPIDString() string {
-	return net.IP(p.PeerBGPID).To4().String()
+	return addr.IPv4(p.PeerBGPID)
 }
 
 // GetPeerAddrString returns a string representation of Peer address
 func (p *PerPeerHeader) GetPeerAddrString() string {
 	if p.PeerType != PeerType3 && p.flagV {
 		// IPv6 specific conversions
-		return net.IP(p.PeerAddress).To16().String()
+		return addr.IPv6(p.PeerAddress)
 	}
 	// IPv4 specific conversions
-	return net.IP(p.PeerAddress[12:]).To4().String()
+	return addr.IPv4(p.PeerAddress[12:])
 }
 
 // IsAdjRIBOutPost returns true if PeerType is 0,1 or 2 and O flag is set, otherwise it returns error
@@ -150,7 +250,9 @@ func (p *PerPeerHeader) IsRemotePeerIPv6() bool {
 }
 
 // GetPeerDistinguisherString returns string representation of Peer's distinguisher
-// depending on the peer's type.
+// depending on the peer's type. RD Instance and Loc-RIB Instance peers carry a route distinguisher
+// rendered per its type 0, 1 or 2, a distinguisher which is not a valid route distinguisher is
+// rendered as hex string.
 func (p *PerPeerHeader) GetPeerDistinguisherString() string {
 	pd := "0:0"
 	switch p.PeerType {
@@ -159,11 +261,11 @@ func (p *PerPeerHeader) GetPeerDistinguisherString() string {
 	case PeerType1:
 		fallthrough
 	case PeerType3:
-		if rd, err := base.MakeRD(p.PeerDistinguisher); err != nil {
-			break
-		} else {
-			return rd.String()
+		rd, err := base.MakeRD(p.PeerDistinguisher)
+		if err != nil {
+			return hex.EncodeToString(p.PeerDistinguisher)
 		}
+		return rd.String()
 	case PeerType2:
 		return strconv.FormatInt(int64(binary.BigEndian.Uint64(p.PeerDistinguisher)), 10)
 	}
@@ -173,7 +275,7 @@ func (p *PerPeerHeader) GetPeerDistinguisherString() string {
 
 // UnmarshalPerPeerHeader processes Per-Peer header
 func UnmarshalPerPeerHeader(b []byte) (*PerPeerHeader, error) {
-	if glog.V(6) {
+	if logging.V(logging.BMP, 6) {
 		glog.Infof("BMP Per Peer Header Raw: %s", tools.MessageHex(b))
 	}
 	pph := &PerPeerHeader{
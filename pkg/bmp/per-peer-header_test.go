@@ -0,0 +1,114 @@
+package bmp
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPerPeerHeaderTimestamps(t *testing.T) {
+	tests := []struct {
+		name                 string
+		peerTimestamp        []byte
+		collector            time.Time
+		expectPeer           string
+		expectPeerEpoch      int64
+		expectCollector      string
+		expectCollectorEpoch int64
+	}{
+		{
+			name:            "microseconds, no collector timestamp",
+			peerTimestamp:   []byte{0x5f, 0x5e, 0x10, 0x00, 0x00, 0x01, 0xe2, 0x40},
+			expectPeer:      "2020-09-13T12:26:40.123456Z",
+			expectPeerEpoch: 1600000000123456,
+		},
+		{
+			name:                 "zero microseconds with collector timestamp",
+			peerTimestamp:        []byte{0x5f, 0x5e, 0x10, 0x00, 0x00, 0x00, 0x00, 0x00},
+			collector:            time.Unix(1600000001, 654321000),
+			expectPeer:           "2020-09-13T12:26:40Z",
+			expectPeerEpoch:      1600000000000000,
+			expectCollector:      "2020-09-13T12:26:41.654321Z",
+			expectCollectorEpoch: 1600000001654321,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ph := &PerPeerHeader{PeerTimestamp: tt.peerTimestamp}
+			if !tt.collector.IsZero() {
+				ph.SetCollectorTimestamp(tt.collector)
+			}
+			if got := ph.GetPeerTimestamp(); got != tt.expectPeer {
+				t.Errorf("expected peer timestamp %s, got %s", tt.expectPeer, got)
+			}
+			if got := ph.GetPeerTimestampEpoch(); got != tt.expectPeerEpoch {
+				t.Errorf("expected peer timestamp epoch %d, got %d", tt.expectPeerEpoch, got)
+			}
+			if got := ph.GetCollectorTimestamp(); got != tt.expectCollector {
+				t.Errorf("expected collector timestamp %s, got %s", tt.expectCollector, got)
+			}
+			if got := ph.GetCollectorTimestampEpoch(); got != tt.expectCollectorEpoch {
+				t.Errorf("expected collector timestamp epoch %d, got %d", tt.expectCollectorEpoch, got)
+			}
+		})
+	}
+}
+
+func TestGetPeerDistinguisherString(t *testing.T) {
+	tests := []struct {
+		name     string
+		peerType PeerType
+		pd       []byte
+		expect   string
+	}{
+		{
+			name:     "global instance peer",
+			peerType: PeerType0,
+			pd:       []byte{0, 0, 0, 0, 0, 0, 0, 0},
+			expect:   "0:0",
+		},
+		{
+			name:     "rd instance peer, rd type 0",
+			peerType: PeerType1,
+			pd:       []byte{0, 0, 0xfd, 0xe8, 0, 0, 0, 100},
+			expect:   "65000:100",
+		},
+		{
+			name:     "rd instance peer, rd type 1",
+			peerType: PeerType1,
+			pd:       []byte{0, 1, 192, 0, 2, 1, 0, 10},
+			expect:   "192.0.2.1:10",
+		},
+		{
+			name:     "rd instance peer, rd type 2",
+			peerType: PeerType1,
+			pd:       []byte{0, 2, 0, 0x01, 0x11, 0x70, 0, 5},
+			expect:   "70000:5",
+		},
+		{
+			name:     "rd instance peer, invalid rd type",
+			peerType: PeerType1,
+			pd:       []byte{0, 5, 0, 0, 0, 0, 0, 1},
+			expect:   "0005000000000001",
+		},
+		{
+			name:     "local instance peer",
+			peerType: PeerType2,
+			pd:       []byte{0, 0, 0, 0, 0, 0, 0x01, 0x00},
+			expect:   "256",
+		},
+		{
+			name:     "loc-rib instance peer",
+			peerType: PeerType3,
+			pd:       []byte{0, 0, 0xfd, 0xe8, 0, 0, 0, 1},
+			expect:   "65000:1",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ph := &PerPeerHeader{PeerType: tt.peerType, PeerDistinguisher: tt.pd}
+			if got := ph.GetPeerDistinguisherString(); got != tt.expect {
+				t.Errorf("expected peer distinguisher %s, got %s", tt.expect, got)
+			}
+		})
+	}
+}
@@ -5,20 +5,130 @@ import (
 
 	"github.com/golang/glog"
 	"github.com/sbezverk/gobmp/pkg/bgp"
+	"github.com/sbezverk/gobmp/pkg/logging"
 	"github.com/sbezverk/tools"
 )
 
 // RouteMonitor defines a structure of BMP Route Monitoring message
 type RouteMonitor struct {
 	Update *bgp.Update
+	// TLVs carries all BMP v4 TLVs except BGP PDU TLV, including TLVs of unknown types
+	TLVs             []*TLV
+	VRFTableName     string
+	StatelessParsing *StatelessParsing
+	// Groups maps group indexes to NLRI indexes defined by Group TLVs
+	Groups map[uint16][]uint16
+	// RawUpdate is BGP UPDATE PDU as received in the message
+	RawUpdate []byte
 }
 
 // UnmarshalBMPRouteMonitorMessage builds BMP Route Monitor object
 func UnmarshalBMPRouteMonitorMessage(b []byte) (*RouteMonitor, error) {
-	if glog.V(6) {
+	if logging.V(logging.BMP, 6) {
 		glog.Infof("BMP Route Monitor Message Raw: %s length: %d", tools.MessageHex(b), len(b))
 	}
-	rm := RouteMonitor{}
+	u, err := unmarshalBGPPDU(b)
+	if err != nil {
+		return nil, err
+	}
+
+	return &RouteMonitor{Update: u, RawUpdate: b}, nil
+}
+
+// UnmarshalBMPRouteMonitorV4Message builds BMP Route Monitor object from BMP v4 Route Monitoring message
+// where BGP PDU and additional information are carried in TLVs.
+func UnmarshalBMPRouteMonitorV4Message(b []byte) (*RouteMonitor, error) {
+	if logging.V(logging.BMP, 6) {
+		glog.Infof("BMP v4 Route Monitor Message Raw: %s length: %d", tools.MessageHex(b), len(b))
+	}
+	tlvs, err := UnmarshalTLVs(b)
+	if err != nil {
+		return nil, err
+	}
+	rm := &RouteMonitor{
+		TLVs: make([]*TLV, 0, len(tlvs)),
+	}
+	found := false
+	for _, t := range tlvs {
+		switch t.Type {
+		case BGPPDUTLV:
+			if found {
+				return nil, fmt.Errorf("route monitor message carries more than one bgp pdu tlv")
+			}
+			found = true
+			if rm.Update, err = unmarshalBGPPDU(t.Value); err != nil {
+				return nil, err
+			}
+			rm.RawUpdate = t.Value
+			continue
+		case StatelessParsingTLV:
+			if rm.StatelessParsing, err = UnmarshalStatelessParsing(t.Value); err != nil {
+				return nil, err
+			}
+		case VRFTableNameTLV:
+			rm.VRFTableName = string(t.Value)
+		case GroupTLV:
+			group, indexes, err := unmarshalGroup(t.Value)
+			if err != nil {
+				return nil, err
+			}
+			if rm.Groups == nil {
+				rm.Groups = make(map[uint16][]uint16)
+			}
+			rm.Groups[group] = indexes
+		}
+		rm.TLVs = append(rm.TLVs, t)
+	}
+	if !found {
+		return nil, fmt.Errorf("route monitor message does not carry bgp pdu tlv")
+	}
+
+	return rm, nil
+}
+
+// NLRITLVs returns TLVs of type t applying to NLRI with index, NLRIs are indexed starting with 1,
+// TLVs with index 0 apply to all NLRIs.
+func (rm *RouteMonitor) NLRITLVs(t uint16, index uint16) []*TLV {
+	tlvs := make([]*TLV, 0)
+	for _, tlv := range rm.TLVs {
+		if tlv.Type == t && rm.appliesTo(tlv, index) {
+			tlvs = append(tlvs, tlv)
+		}
+	}
+
+	return tlvs
+}
+
+// EnterpriseTLVs returns enterprise specific TLVs applying to NLRI with index
+func (rm *RouteMonitor) EnterpriseTLVs(index uint16) []*TLV {
+	tlvs := make([]*TLV, 0)
+	for _, tlv := range rm.TLVs {
+		if tlv.IsEnterprise() && rm.appliesTo(tlv, index) {
+			tlvs = append(tlvs, tlv)
+		}
+	}
+
+	return tlvs
+}
+
+// appliesTo returns true if TLV applies to NLRI with index directly, as TLV of all NLRIs or TLV of a group
+func (rm *RouteMonitor) appliesTo(tlv *TLV, index uint16) bool {
+	switch {
+	case tlv.Index == 0 || tlv.Index == index:
+		return true
+	case tlv.Index&groupIndexBit != 0:
+		for _, i := range rm.Groups[tlv.Index] {
+			if i == index {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// unmarshalBGPPDU builds BGP Update object from BGP PDU, PDUs of other than Update types are ignored
+func unmarshalBGPPDU(b []byte) (*bgp.Update, error) {
 	// 16 bytes marker + 2 bytes update length + 1 byte of type
 	if len(b) < 19 {
 		return nil, fmt.Errorf("malformed route monitor message")
@@ -34,13 +144,32 @@ func UnmarshalBMPRouteMonitorMessage(b []byte) (*RouteMonitor, error) {
 	switch t {
 	case 2:
 		// Update type
-		u, err := bgp.UnmarshalBGPUpdate(b[p:])
-		if err != nil {
-			return nil, err
-		}
-		rm.Update = u
+		return bgp.UnmarshalBGPUpdate(b[p:])
 	default:
 	}
 
-	return &rm, nil
+	return nil, nil
+}
+
+// Serialize generates a slice of bytes from RouteMonitor structure
+func (rm *RouteMonitor) Serialize() ([]byte, error) {
+	if rm.Update == nil {
+		return nil, fmt.Errorf("route monitor message does not carry bgp update")
+	}
+	return rm.Update.Serialize()
+}
+
+// SerializeV4 generates a slice of bytes of BMP v4 Route Monitoring message from RouteMonitor structure,
+// BGP PDU TLV is followed by all other TLVs.
+func (rm *RouteMonitor) SerializeV4() ([]byte, error) {
+	u, err := rm.Serialize()
+	if err != nil {
+		return nil, err
+	}
+	b := (&TLV{Type: BGPPDUTLV, Value: u}).Serialize()
+	for _, t := range rm.TLVs {
+		b = append(b, t.Serialize()...)
+	}
+
+	return b, nil
 }
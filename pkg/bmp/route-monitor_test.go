@@ -0,0 +1,131 @@
+package bmp
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/go-test/deep"
+	"github.com/sbezverk/gobmp/pkg/bgp"
+)
+
+func TestRouteMonitorV4(t *testing.T) {
+	update := &bgp.Update{
+		PathAttributes: []bgp.PathAttribute{
+			{AttributeTypeFlags: 0x40, AttributeType: 1, Attribute: []byte{0}},
+		},
+		NLRI: []byte{24, 10, 0, 0, 24, 10, 0, 1},
+	}
+	tests := []struct {
+		name   string
+		tlvs   []*TLV
+		expect *RouteMonitor
+		fail   bool
+	}{
+		{
+			name: "bgp pdu only",
+			expect: &RouteMonitor{
+				TLVs: []*TLV{},
+			},
+		},
+		{
+			name: "stateless parsing, vrf name, group and unknown tlvs",
+			tlvs: []*TLV{
+				{Type: StatelessParsingTLV, Value: []byte{0, 1, 1, 0xc0}},
+				{Type: VRFTableNameTLV, Value: []byte("blue")},
+				{Type: GroupTLV, Value: []byte{0x80, 0x01, 0, 1, 0, 2}},
+				{Type: 100, Index: 0x8001, Value: []byte{1, 2, 3}},
+				{Type: EnterpriseTLVBit | 1, Index: 2, Value: []byte{0, 0, 0x7d, 0x2b, 1}},
+			},
+			expect: &RouteMonitor{
+				TLVs: []*TLV{
+					{Type: StatelessParsingTLV, Value: []byte{0, 1, 1, 0xc0}},
+					{Type: VRFTableNameTLV, Value: []byte("blue")},
+					{Type: GroupTLV, Value: []byte{0x80, 0x01, 0, 1, 0, 2}},
+					{Type: 100, Index: 0x8001, Value: []byte{1, 2, 3}},
+					{Type: EnterpriseTLVBit | 1, Index: 2, Value: []byte{0, 0, 0x7d, 0x2b, 1}},
+				},
+				VRFTableName:     "blue",
+				StatelessParsing: &StatelessParsing{AFI: 1, SAFI: 1, AddPath: true, AS4: true},
+				Groups:           map[uint16][]uint16{0x8001: {1, 2}},
+			},
+		},
+		{
+			name: "invalid group index",
+			tlvs: []*TLV{
+				{Type: GroupTLV, Value: []byte{0, 1, 0, 1}},
+			},
+			fail: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := (&RouteMonitor{Update: update, TLVs: tt.tlvs}).SerializeV4()
+			if err != nil {
+				t.Fatalf("failed to serialize route monitor with error: %+v", err)
+			}
+			rm, err := UnmarshalBMPRouteMonitorV4Message(b)
+			if err != nil {
+				if !tt.fail {
+					t.Fatalf("supposed to succeed but failed with error: %+v", err)
+				}
+				return
+			}
+			if tt.fail {
+				t.Fatalf("supposed to fail but succeeded")
+			}
+			if !reflect.DeepEqual(rm.Update.NLRI, update.NLRI) {
+				t.Errorf("bgp update nlri do not match")
+			}
+			// Raw BGP UPDATE is the value of BGP PDU TLV
+			if u, err := unmarshalBGPPDU(rm.RawUpdate); err != nil || !reflect.DeepEqual(u.NLRI, update.NLRI) {
+				t.Errorf("raw bgp update %v does not carry the update", rm.RawUpdate)
+			}
+			rm.Update = nil
+			rm.RawUpdate = nil
+			if !reflect.DeepEqual(rm, tt.expect) {
+				t.Errorf("route monitor does not match expected")
+				t.Logf("Differences: %+v", deep.Equal(rm, tt.expect))
+			}
+		})
+	}
+}
+
+func TestRouteMonitorV4NoPDU(t *testing.T) {
+	b := (&TLV{Type: VRFTableNameTLV, Value: []byte("blue")}).Serialize()
+	if _, err := UnmarshalBMPRouteMonitorV4Message(b); err == nil {
+		t.Fatalf("supposed to fail without bgp pdu tlv but succeeded")
+	}
+	if _, err := UnmarshalBMPRouteMonitorV4Message(b[:len(b)-1]); err == nil {
+		t.Fatalf("supposed to fail with truncated tlv but succeeded")
+	}
+}
+
+func TestNLRITLVs(t *testing.T) {
+	rm := &RouteMonitor{
+		TLVs: []*TLV{
+			{Type: 100, Index: 0, Value: []byte{0}},
+			{Type: 100, Index: 1, Value: []byte{1}},
+			{Type: 100, Index: 0x8001, Value: []byte{2}},
+			{Type: 101, Index: 2, Value: []byte{3}},
+		},
+		Groups: map[uint16][]uint16{0x8001: {2, 3}},
+	}
+	tests := []struct {
+		index  uint16
+		expect []byte
+	}{
+		{index: 1, expect: []byte{0, 1}},
+		{index: 2, expect: []byte{0, 2}},
+		{index: 3, expect: []byte{0, 2}},
+		{index: 4, expect: []byte{0}},
+	}
+	for _, tt := range tests {
+		values := make([]byte, 0)
+		for _, tlv := range rm.NLRITLVs(100, tt.index) {
+			values = append(values, tlv.Value...)
+		}
+		if !reflect.DeepEqual(values, tt.expect) {
+			t.Errorf("index %d expected tlvs %v but got %v", tt.index, tt.expect, values)
+		}
+	}
+}
@@ -0,0 +1,120 @@
+package bmp
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	"github.com/golang/glog"
+	"github.com/sbezverk/gobmp/pkg/logging"
+	"github.com/sbezverk/tools"
+)
+
+// BMP v4 TLV types carried in Route Monitoring messages per draft-ietf-grow-bmp-tlv,
+// the values follow the draft's codepoints and are subject to change until IANA assignment.
+const (
+	// StatelessParsingTLV carries capabilities required to parse BGP PDU without Peer Up state
+	StatelessParsingTLV = 1
+	// GroupTLV defines a group of NLRI indexes sharing TLVs
+	GroupTLV = 2
+	// VRFTableNameTLV carries the name of the VRF or table the BGP PDU belongs to
+	VRFTableNameTLV = 3
+	// BGPPDUTLV carries BGP PDU of Route Monitoring message
+	BGPPDUTLV = 4
+	// EnterpriseTLVBit is set in TLV type of enterprise specific TLVs, the first 4 bytes of the value
+	// carry the Private Enterprise Number.
+	EnterpriseTLVBit = 0x8000
+	// groupIndexBit is set in the index of a group defined by Group TLV
+	groupIndexBit = 0x8000
+)
+
+// TLV defines BMP v4 TLV, Index identifies the NLRI of BGP PDU the TLV applies to, NLRIs are indexed
+// starting with 1, 0 means that the TLV applies to all NLRIs and index with the highest bit set refers
+// to a group of NLRIs defined by Group TLV.
+type TLV struct {
+	Type  uint16
+	Index uint16
+	Value []byte
+}
+
+// IsEnterprise returns true if TLV is enterprise specific
+func (t *TLV) IsEnterprise() bool {
+	return t.Type&EnterpriseTLVBit != 0
+}
+
+// Serialize generates a slice of bytes from TLV structure
+func (t *TLV) Serialize() []byte {
+	b := make([]byte, 6+len(t.Value))
+	binary.BigEndian.PutUint16(b[0:2], t.Type)
+	binary.BigEndian.PutUint16(b[2:4], uint16(len(t.Value)))
+	binary.BigEndian.PutUint16(b[4:6], t.Index)
+	copy(b[6:], t.Value)
+
+	return b
+}
+
+// UnmarshalTLVs builds a slice of BMP v4 TLVs, TLVs of unknown types are preserved
+func UnmarshalTLVs(b []byte) ([]*TLV, error) {
+	if logging.V(logging.BMP, 6) {
+		glog.Infof("BMP v4 TLVs Raw: %s", tools.MessageHex(b))
+	}
+	tlvs := make([]*TLV, 0)
+	for p := 0; p < len(b); {
+		if p+6 > len(b) {
+			return nil, fmt.Errorf("not enough bytes to unmarshal BMP v4 TLV header")
+		}
+		t := &TLV{
+			Type:  binary.BigEndian.Uint16(b[p : p+2]),
+			Index: binary.BigEndian.Uint16(b[p+4 : p+6]),
+		}
+		l := int(binary.BigEndian.Uint16(b[p+2 : p+4]))
+		p += 6
+		if p+l > len(b) {
+			return nil, fmt.Errorf("invalid length %d of BMP v4 TLV type %d", l, t.Type)
+		}
+		t.Value = make([]byte, l)
+		copy(t.Value, b[p:p+l])
+		p += l
+		tlvs = append(tlvs, t)
+	}
+
+	return tlvs, nil
+}
+
+// StatelessParsing defines the content of Stateless Parsing TLV
+type StatelessParsing struct {
+	AFI     uint16
+	SAFI    uint8
+	AddPath bool
+	AS4     bool
+}
+
+// UnmarshalStatelessParsing builds StatelessParsing object from the value of Stateless Parsing TLV
+func UnmarshalStatelessParsing(b []byte) (*StatelessParsing, error) {
+	if len(b) != 4 {
+		return nil, fmt.Errorf("invalid length %d of stateless parsing tlv", len(b))
+	}
+
+	return &StatelessParsing{
+		AFI:     binary.BigEndian.Uint16(b[0:2]),
+		SAFI:    b[2],
+		AddPath: b[3]&0x80 != 0,
+		AS4:     b[3]&0x40 != 0,
+	}, nil
+}
+
+// unmarshalGroup returns group index and indexes of NLRIs from the value of Group TLV
+func unmarshalGroup(b []byte) (uint16, []uint16, error) {
+	if len(b) < 2 || len(b)%2 != 0 {
+		return 0, nil, fmt.Errorf("invalid length %d of group tlv", len(b))
+	}
+	group := binary.BigEndian.Uint16(b[0:2])
+	if group&groupIndexBit == 0 {
+		return 0, nil, fmt.Errorf("invalid group index %d, group bit is not set", group)
+	}
+	indexes := make([]uint16, 0, len(b)/2-1)
+	for p := 2; p < len(b); p += 2 {
+		indexes = append(indexes, binary.BigEndian.Uint16(b[p:p+2]))
+	}
+
+	return group, indexes, nil
+}
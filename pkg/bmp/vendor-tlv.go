@@ -0,0 +1,146 @@
+package bmp
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"sync"
+	"unicode/utf8"
+)
+
+// VendorTLVDecoder decodes the value of vendor specific TLV into a json friendly representation
+type VendorTLVDecoder func(b []byte) (interface{}, error)
+
+// VendorTLV defines vendor specific or otherwise not decoded TLV of BMP message, Enterprise carries
+// Private Enterprise Number of BMP v4 enterprise specific TLVs. When no decoder is registered for the TLV
+// or the decoder fails, Value carries hex encoded value of the TLV.
+type VendorTLV struct {
+	Type       uint16      `json:"type"`
+	Enterprise uint32      `json:"enterprise,omitempty"`
+	Vendor     string      `json:"vendor,omitempty"`
+	Name       string      `json:"name,omitempty"`
+	Value      interface{} `json:"value"`
+}
+
+type vendorTLVKey struct {
+	msgType    int
+	enterprise uint32
+	tlvType    uint16
+}
+
+type vendorTLVEntry struct {
+	name   string
+	decode VendorTLVDecoder
+}
+
+var vendorTLVs = struct {
+	sync.RWMutex
+	m map[vendorTLVKey]*vendorTLVEntry
+}{
+	m: make(map[vendorTLVKey]*vendorTLVEntry),
+}
+
+// enterpriseNames maps Private Enterprise Numbers of vendors known to send enterprise specific TLVs
+// https://www.iana.org/assignments/enterprise-numbers
+var enterpriseNames = map[uint32]string{
+	9:     "cisco",
+	2011:  "huawei",
+	2636:  "juniper",
+	6527:  "nokia",
+	30065: "arista",
+}
+
+// experimentalTLVTypes defines the range of TLV types reserved for experimental use in Information,
+// Statistics and Initiation TLV registries
+var experimentalTLVTypes = []uint16{65531, 65532, 65533, 65534}
+
+func init() {
+	for _, t := range experimentalTLVTypes {
+		RegisterVendorTLV(StatsReportMsg, 0, t, "experimental", CounterTLVDecoder)
+		RegisterVendorTLV(PeerUpMsg, 0, t, "experimental", StringTLVDecoder)
+		RegisterVendorTLV(InitiationMsg, 0, t, "experimental", StringTLVDecoder)
+	}
+}
+
+// RegisterVendorTLV registers the decoder of TLV type of BMP message type, enterprise is Private Enterprise
+// Number of BMP v4 enterprise specific TLV or 0 for TLVs without it. Registering a decoder of already
+// registered TLV fails.
+func RegisterVendorTLV(msgType int, enterprise uint32, tlvType uint16, name string, decode VendorTLVDecoder) error {
+	if decode == nil {
+		return fmt.Errorf("decoder of tlv type %d of message type %d is nil", tlvType, msgType)
+	}
+	k := vendorTLVKey{msgType: msgType, enterprise: enterprise, tlvType: tlvType}
+	vendorTLVs.Lock()
+	defer vendorTLVs.Unlock()
+	if _, ok := vendorTLVs.m[k]; ok {
+		return fmt.Errorf("decoder of tlv type %d enterprise %d of message type %d is already registered", tlvType, enterprise, msgType)
+	}
+	vendorTLVs.m[k] = &vendorTLVEntry{name: name, decode: decode}
+
+	return nil
+}
+
+// IsVendorTLV returns true when a decoder of TLV type of BMP message type is registered
+func IsVendorTLV(msgType int, enterprise uint32, tlvType uint16) bool {
+	vendorTLVs.RLock()
+	defer vendorTLVs.RUnlock()
+	_, ok := vendorTLVs.m[vendorTLVKey{msgType: msgType, enterprise: enterprise, tlvType: tlvType}]
+
+	return ok
+}
+
+// DecodeVendorTLV decodes TLV type of BMP message type by the registered decoder
+func DecodeVendorTLV(msgType int, enterprise uint32, tlvType uint16, b []byte) *VendorTLV {
+	t := &VendorTLV{
+		Type:       tlvType,
+		Enterprise: enterprise,
+		Vendor:     enterpriseNames[enterprise],
+		Value:      hex.EncodeToString(b),
+	}
+	vendorTLVs.RLock()
+	e, ok := vendorTLVs.m[vendorTLVKey{msgType: msgType, enterprise: enterprise, tlvType: tlvType}]
+	vendorTLVs.RUnlock()
+	if !ok {
+		return t
+	}
+	t.Name = e.name
+	if v, err := e.decode(b); err == nil {
+		t.Value = v
+	}
+
+	return t
+}
+
+// DecodeEnterpriseTLV decodes BMP v4 enterprise specific TLV of BMP message type, the first 4 bytes
+// of the value carry Private Enterprise Number.
+func DecodeEnterpriseTLV(msgType int, tlv *TLV) (*VendorTLV, error) {
+	if !tlv.IsEnterprise() {
+		return nil, fmt.Errorf("tlv type %d is not enterprise specific", tlv.Type)
+	}
+	if len(tlv.Value) < 4 {
+		return nil, fmt.Errorf("invalid length %d of enterprise specific tlv type %d", len(tlv.Value), tlv.Type)
+	}
+
+	return DecodeVendorTLV(msgType, binary.BigEndian.Uint32(tlv.Value[:4]), tlv.Type, tlv.Value[4:]), nil
+}
+
+// CounterTLVDecoder decodes the value of 32 or 64 bits counter
+func CounterTLVDecoder(b []byte) (interface{}, error) {
+	switch len(b) {
+	case 4:
+		return binary.BigEndian.Uint32(b), nil
+	case 8:
+		return binary.BigEndian.Uint64(b), nil
+	}
+
+	return nil, fmt.Errorf("invalid length %d of counter", len(b))
+}
+
+// StringTLVDecoder decodes the value of UTF-8 string
+func StringTLVDecoder(b []byte) (interface{}, error) {
+	if !utf8.Valid(b) {
+		return nil, fmt.Errorf("value is not a valid utf-8 string")
+	}
+
+	return string(b), nil
+}
@@ -0,0 +1,90 @@
+package bmp
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/go-test/deep"
+)
+
+func TestDecodeVendorTLV(t *testing.T) {
+	if err := RegisterVendorTLV(RouteMonitorMsg, 2636, 0x8001, "route_age", func(b []byte) (interface{}, error) {
+		if len(b) != 2 {
+			return nil, fmt.Errorf("invalid length %d", len(b))
+		}
+		return uint16(b[0])<<8 | uint16(b[1]), nil
+	}); err != nil {
+		t.Fatalf("failed to register decoder with error: %+v", err)
+	}
+	if err := RegisterVendorTLV(RouteMonitorMsg, 2636, 0x8001, "route_age", CounterTLVDecoder); err == nil {
+		t.Fatalf("registration of already registered decoder supposed to fail but succeeded")
+	}
+	tests := []struct {
+		name   string
+		tlv    *TLV
+		expect *VendorTLV
+		fail   bool
+	}{
+		{
+			name:   "registered enterprise tlv",
+			tlv:    &TLV{Type: 0x8001, Value: []byte{0x00, 0x00, 0x0a, 0x4c, 0x01, 0x2c}},
+			expect: &VendorTLV{Type: 0x8001, Enterprise: 2636, Vendor: "juniper", Name: "route_age", Value: uint16(300)},
+		},
+		{
+			name:   "registered enterprise tlv failing to decode",
+			tlv:    &TLV{Type: 0x8001, Value: []byte{0x00, 0x00, 0x0a, 0x4c, 0x01}},
+			expect: &VendorTLV{Type: 0x8001, Enterprise: 2636, Vendor: "juniper", Name: "route_age", Value: "01"},
+		},
+		{
+			name:   "unknown enterprise tlv",
+			tlv:    &TLV{Type: 0x8005, Value: []byte{0x00, 0x00, 0x00, 0x09, 0xca, 0xfe}},
+			expect: &VendorTLV{Type: 0x8005, Enterprise: 9, Vendor: "cisco", Value: "cafe"},
+		},
+		{
+			name: "not enterprise tlv",
+			tlv:  &TLV{Type: PathStatusTLV, Value: []byte{0x00, 0x00, 0x00, 0x02}},
+			fail: true,
+		},
+		{
+			name: "missing enterprise number",
+			tlv:  &TLV{Type: 0x8001, Value: []byte{0x00, 0x00}},
+			fail: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := DecodeEnterpriseTLV(RouteMonitorMsg, tt.tlv)
+			if err != nil && !tt.fail {
+				t.Fatalf("supposed to succeed but failed with error: %+v", err)
+			}
+			if err == nil && tt.fail {
+				t.Fatalf("supposed to fail but succeeded")
+			}
+			if err != nil {
+				return
+			}
+			if !reflect.DeepEqual(got, tt.expect) {
+				t.Logf("Differences: %+v", deep.Equal(got, tt.expect))
+				t.Errorf("expected %+v does not match computed %+v", tt.expect, got)
+			}
+		})
+	}
+}
+
+func TestExperimentalTLVs(t *testing.T) {
+	if got, expect := DecodeVendorTLV(StatsReportMsg, 0, 65531, []byte{0, 0, 0, 0, 0, 0, 0x01, 0x00}), (&VendorTLV{Type: 65531, Name: "experimental", Value: uint64(256)}); !reflect.DeepEqual(got, expect) {
+		t.Errorf("expected %+v does not match computed %+v", expect, got)
+	}
+	if got, expect := DecodeVendorTLV(StatsReportMsg, 0, 100, []byte{0x01}), (&VendorTLV{Type: 100, Value: "01"}); !reflect.DeepEqual(got, expect) {
+		t.Errorf("expected %+v does not match computed %+v", expect, got)
+	}
+	// Initiation message with sysName and experimental TLV
+	im, err := UnmarshalInitiationMessage([]byte{0x00, 0x02, 0x00, 0x02, 0x72, 0x31, 0xff, 0xfb, 0x00, 0x02, 0x6c, 0x61})
+	if err != nil {
+		t.Fatalf("supposed to succeed but failed with error: %+v", err)
+	}
+	if len(im.TLV) != 2 {
+		t.Fatalf("expected 2 tlvs but got %d", len(im.TLV))
+	}
+}
@@ -0,0 +1,104 @@
+package bmptest
+
+import (
+	"bytes"
+	"net"
+	"sync"
+	"testing"
+
+	"github.com/sbezverk/gobmp/pkg/bmp"
+	"github.com/sbezverk/gobmp/pkg/gobmpsrv"
+)
+
+// counter is a Publisher counting published messages by their types
+type counter struct {
+	sync.Mutex
+	types map[int]int
+}
+
+func (c *counter) PublishMessage(msgType int, msgHash []byte, msg []byte) error {
+	c.Lock()
+	defer c.Unlock()
+	c.types[msgType]++
+
+	return nil
+}
+
+func (c *counter) Stop() {}
+
+func serve(t *testing.T, b []byte) (map[int]int, error) {
+	c := &counter{types: make(map[int]int)}
+	srv, err := gobmpsrv.NewBMPServerWithConfig(nil, c, &gobmpsrv.Config{SplitAF: true})
+	if err != nil {
+		t.Fatalf("failed to create server with error: %+v", err)
+	}
+	err = srv.Serve("bmptest", bytes.NewReader(b))
+
+	return c.types, err
+}
+
+func TestScenario(t *testing.T) {
+	v4, err := Prefixes("10.0.0.0", 24, 300)
+	if err != nil {
+		t.Fatalf("failed to generate prefixes with error: %+v", err)
+	}
+	v6, err := Prefixes("2001:db8::", 48, 2)
+	if err != nil {
+		t.Fatalf("failed to generate prefixes with error: %+v", err)
+	}
+	if v4[299].String() != "10.1.43.0/24" || v6[1].String() != "2001:db8:1::/48" {
+		t.Fatalf("unexpected generated prefixes %s and %s", v4[299], v6[1])
+	}
+	p := &Peer{Address: net.ParseIP("192.0.2.2"), AS: 65001, BGPID: net.ParseIP("192.0.2.2")}
+	buf := &bytes.Buffer{}
+	s := NewSpeaker(buf, net.ParseIP("192.0.2.1"), 65000)
+	err = Play(s, Session("r1",
+		TableDump(p, append(v4, v6...)),
+		PeerFlap(p, v4[:1], 1, 0),
+		Malformed(p, BadUpdate),
+		func(s *Speaker) error { return s.Withdraw(p, v6) },
+	))
+	if err != nil {
+		t.Fatalf("failed to play scenario with error: %+v", err)
+	}
+	// Initiation, Peer Up with 2 IPv4 and 1 IPv6 updates and 2 End-of-RIBs, Peer Down and Peer Up with
+	// 1 update and 2 End-of-RIBs, malformed update, withdraw and Termination
+	if s.Sent() != 15 {
+		t.Errorf("expected 15 sent messages, got %d", s.Sent())
+	}
+	types, err := serve(t, buf.Bytes())
+	if err != nil {
+		t.Fatalf("failed to serve scenario with error: %+v", err)
+	}
+	// Peer Up, Peer Down and Peer Up
+	if types[bmp.PeerStateChangeMsg] != 3 {
+		t.Errorf("expected 3 peer messages, got %d", types[bmp.PeerStateChangeMsg])
+	}
+	// Table dump, flap and withdrawn IPv6 prefixes
+	if types[bmp.UnicastPrefixV4Msg] != 301 || types[bmp.UnicastPrefixV6Msg] != 4 {
+		t.Errorf("expected 301 IPv4 and 4 IPv6 prefixes, got %+v", types)
+	}
+}
+
+func TestMalformed(t *testing.T) {
+	p := &Peer{Address: net.ParseIP("2001:db8::2"), AS: 4200000000, BGPID: net.ParseIP("192.0.2.2")}
+	tests := []struct {
+		kind Malformation
+		fail bool
+	}{
+		{kind: BadVersion, fail: true},
+		{kind: BadLength, fail: true},
+		{kind: BadPeerType, fail: false},
+		{kind: BadUpdate, fail: false},
+	}
+	for _, tt := range tests {
+		buf := &bytes.Buffer{}
+		s := NewSpeaker(buf, net.ParseIP("2001:db8::1"), 65000)
+		if err := Play(s, TableDump(p, nil), Malformed(p, tt.kind), Malformed(p, tt.kind)); err != nil {
+			t.Fatalf("failed to play scenario with error: %+v", err)
+		}
+		if _, err := serve(t, buf.Bytes()); (err != nil) != tt.fail {
+			t.Errorf("malformation %d expected to fail: %t, got error: %+v", tt.kind, tt.fail, err)
+		}
+	}
+}
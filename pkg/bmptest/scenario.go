@@ -0,0 +1,175 @@
+package bmptest
+
+import (
+	"encoding/binary"
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/sbezverk/gobmp/pkg/bmp"
+)
+
+// Step defines a step of a scenario played by the speaker
+type Step func(s *Speaker) error
+
+// Play plays steps of the scenario in order, it stops at the first failed step.
+func Play(s *Speaker, steps ...Step) error {
+	for i, step := range steps {
+		if err := step(s); err != nil {
+			return fmt.Errorf("step %d of the scenario failed with error: %+v", i+1, err)
+		}
+	}
+
+	return nil
+}
+
+// Session returns the step sending Initiation message, steps of the session and Termination message
+func Session(sysName string, steps ...Step) Step {
+	return func(s *Speaker) error {
+		if err := s.Initiate(sysName); err != nil {
+			return err
+		}
+		if err := Play(s, steps...); err != nil {
+			return err
+		}
+		return s.Terminate()
+	}
+}
+
+// TableDump returns the step bringing the peer up and sending its table of prefixes followed by End-of-RIB
+// of each address family.
+func TableDump(p *Peer, prefixes []*net.IPNet) Step {
+	return func(s *Speaker) error {
+		if err := s.PeerUp(p); err != nil {
+			return err
+		}
+		if err := s.Announce(p, prefixes); err != nil {
+			return err
+		}
+		if err := s.EndOfRIB(p, false); err != nil {
+			return err
+		}
+		return s.EndOfRIB(p, true)
+	}
+}
+
+// PeerFlap returns the step bringing the peer down and up again with the table of prefixes flaps times,
+// the peer stays down for the interval.
+func PeerFlap(p *Peer, prefixes []*net.IPNet, flaps int, interval time.Duration) Step {
+	return func(s *Speaker) error {
+		for i := 0; i < flaps; i++ {
+			if err := s.PeerDown(p); err != nil {
+				return err
+			}
+			time.Sleep(interval)
+			if err := TableDump(p, prefixes)(s); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+// Pause returns the step waiting for d
+func Pause(d time.Duration) Step {
+	return func(s *Speaker) error {
+		time.Sleep(d)
+		return nil
+	}
+}
+
+// Malformation defines the kind of malformed BMP message
+type Malformation int
+
+const (
+	// BadVersion is Initiation message of BMP version 1
+	BadVersion Malformation = iota
+	// BadLength is a message which length is shorter than BMP Common Header
+	BadLength
+	// BadPeerType is Route Monitoring message with an unknown peer type
+	BadPeerType
+	// BadUpdate is Route Monitoring message which BGP Update's path attributes are longer than the update
+	BadUpdate
+)
+
+// Malformed returns the step of the peer sending a malformed message of the kind, BMP session can not continue
+// after BadVersion and BadLength messages, BadPeerType and BadUpdate messages are expected to be dropped.
+func Malformed(p *Peer, kind Malformation) Step {
+	return func(s *Speaker) error {
+		b, err := malformed(p, kind)
+		if err != nil {
+			return err
+		}
+		return s.Raw(b)
+	}
+}
+
+func malformed(p *Peer, kind Malformation) ([]byte, error) {
+	switch kind {
+	case BadVersion:
+		return []byte{1, 0, 0, 0, 6, bmp.InitiationMsg}, nil
+	case BadLength:
+		return []byte{3, 0, 0, 0, 5, bmp.RouteMonitorMsg}, nil
+	case BadPeerType:
+		b, err := bmp.SerializeMessage(bmp.RouteMonitorMsg, p.perPeerHeader(), nil)
+		if err != nil {
+			return nil, err
+		}
+		b[bmp.CommonHeaderLength] = 0xff
+		return b, nil
+	case BadUpdate:
+		// BGP Update of Marker, Length, Withdrawn Routes Length and Total Path Attribute Length of 255 bytes
+		// without path attributes
+		up := make([]byte, 23)
+		for i := 0; i < 16; i++ {
+			up[i] = 0xff
+		}
+		binary.BigEndian.PutUint16(up[16:18], uint16(len(up)))
+		up[18] = 2
+		binary.BigEndian.PutUint16(up[21:23], 255)
+		return bmp.SerializeMessage(bmp.RouteMonitorMsg, p.perPeerHeader(), up)
+	}
+
+	return nil, fmt.Errorf("unknown malformation %d", kind)
+}
+
+// Prefixes returns count consecutive prefixes of length starting with the prefix of the address
+func Prefixes(start string, length, count int) ([]*net.IPNet, error) {
+	ip := net.ParseIP(start)
+	if ip == nil {
+		return nil, fmt.Errorf("invalid address %s", start)
+	}
+	bits := 128
+	if ip.To4() != nil {
+		ip = ip.To4()
+		bits = 32
+	}
+	if length < 1 || length > bits {
+		return nil, fmt.Errorf("invalid prefix length %d", length)
+	}
+	mask := net.CIDRMask(length, bits)
+	ip = ip.Mask(mask)
+	prefixes := make([]*net.IPNet, 0, count)
+	for i := 0; i < count; i++ {
+		prefixes = append(prefixes, &net.IPNet{IP: ip, Mask: mask})
+		ip = next(ip, length)
+	}
+
+	return prefixes, nil
+}
+
+// next returns the address following the prefix of length
+func next(ip net.IP, length int) net.IP {
+	n := make(net.IP, len(ip))
+	copy(n, ip)
+	// Adding 1 at the last bit of the prefix
+	i := (length - 1) / 8
+	carry := uint(1) << uint(7-(length-1)%8)
+	for ; i >= 0 && carry != 0; i-- {
+		v := uint(n[i]) + carry
+		n[i] = byte(v)
+		carry = v >> 8
+	}
+
+	return n
+}
@@ -0,0 +1,305 @@
+package bmptest
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"time"
+
+	"github.com/sbezverk/gobmp/pkg/bgp"
+	"github.com/sbezverk/gobmp/pkg/bmp"
+)
+
+// maxPrefixesPerUpdate defines how many prefixes are packed into a single BGP Update, IPv6 prefixes take
+// up to 17 bytes, so the update stays below BGP maximum message length.
+const maxPrefixesPerUpdate = 200
+
+// Peer defines a simulated BGP peer of the monitored router
+type Peer struct {
+	Address net.IP
+	AS      uint32
+	BGPID   net.IP
+}
+
+// Speaker is a minimal BMP speaker simulating a monitored router, messages are written to the BMP session
+// in the order of calls. Speaker is not safe for concurrent use.
+type Speaker struct {
+	w        io.Writer
+	RouterID net.IP
+	AS       uint32
+	// sent counts written BMP messages
+	sent int
+}
+
+// NewSpeaker returns BMP speaker of the router writing BMP messages to w
+func NewSpeaker(w io.Writer, routerID net.IP, as uint32) *Speaker {
+	return &Speaker{
+		w:        w,
+		RouterID: routerID,
+		AS:       as,
+	}
+}
+
+// Dial connects to BMP listener of the collector and returns BMP speaker of the router, the connection is
+// closed by Close.
+func Dial(address string, routerID net.IP, as uint32) (*Speaker, error) {
+	conn, err := net.Dial("tcp", address)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to BMP listener %s with error: %+v", address, err)
+	}
+
+	return NewSpeaker(conn, routerID, as), nil
+}
+
+// Sent returns the number of BMP messages written by the speaker
+func (s *Speaker) Sent() int {
+	return s.sent
+}
+
+// Close closes the BMP session when it is a connection
+func (s *Speaker) Close() error {
+	if c, ok := s.w.(io.Closer); ok {
+		return c.Close()
+	}
+
+	return nil
+}
+
+// Raw writes b to the BMP session as is, it is used to send malformed messages
+func (s *Speaker) Raw(b []byte) error {
+	if _, err := s.w.Write(b); err != nil {
+		return err
+	}
+	s.sent++
+
+	return nil
+}
+
+func (s *Speaker) send(b []byte, err error) error {
+	if err != nil {
+		return err
+	}
+
+	return s.Raw(b)
+}
+
+// Initiate sends Initiation message with sysName and sysDescr of the router
+func (s *Speaker) Initiate(sysName string) error {
+	im := &bmp.InitiationMessage{
+		TLV: []bmp.InformationalTLV{
+			{InformationType: 1, Information: []byte("gobmp simulated router")},
+			{InformationType: 2, Information: []byte(sysName)},
+		},
+	}
+	b, err := im.Serialize()
+	if err != nil {
+		return err
+	}
+
+	return s.send(bmp.SerializeMessage(bmp.InitiationMsg, nil, b))
+}
+
+// Terminate sends Termination message with reason Session administratively closed
+func (s *Speaker) Terminate() error {
+	return s.send(bmp.SerializeMessage(bmp.TerminationMsg, nil, bmp.SerializeTLV([]bmp.InformationalTLV{
+		{InformationType: 1, Information: []byte{0, 0}},
+	})))
+}
+
+// PeerUp sends Peer Up message of the peer, both OPEN messages advertise IPv4 and IPv6 Unicast and 4-octet
+// AS number capabilities.
+func (s *Speaker) PeerUp(p *Peer) error {
+	pu := &bmp.PeerUpMessage{
+		LocalAddress: make([]byte, 16),
+		LocalPort:    179,
+		RemotePort:   40179,
+		SentOpen:     openMessage(s.AS, s.RouterID),
+		ReceivedOpen: openMessage(p.AS, p.BGPID),
+	}
+	if l := s.RouterID.To4(); l != nil {
+		copy(pu.LocalAddress[12:], l)
+	} else {
+		copy(pu.LocalAddress, s.RouterID.To16())
+	}
+	b, err := pu.Serialize()
+	if err != nil {
+		return err
+	}
+
+	return s.send(bmp.SerializeMessage(bmp.PeerUpMsg, p.perPeerHeader(), b))
+}
+
+// PeerDown sends Peer Down message of the peer with reason Remote system closed the session without
+// a notification.
+func (s *Speaker) PeerDown(p *Peer) error {
+	pd := &bmp.PeerDownMessage{Reason: 4}
+	b, err := pd.Serialize()
+	if err != nil {
+		return err
+	}
+
+	return s.send(bmp.SerializeMessage(bmp.PeerDownMsg, p.perPeerHeader(), b))
+}
+
+// Announce sends Route Monitoring messages advertising prefixes from the peer with the peer as the next hop,
+// IPv4 prefixes are carried in NLRI and IPv6 prefixes in MP_REACH_NLRI.
+func (s *Speaker) Announce(p *Peer, prefixes []*net.IPNet) error {
+	v4, v6 := split(prefixes)
+	for i := 0; i < len(v4); i += maxPrefixesPerUpdate {
+		up := &bgp.Update{
+			PathAttributes: append(p.pathAttributes(), bgp.PathAttribute{AttributeTypeFlags: 0x40, AttributeType: 3, Attribute: p.nextHop().To4()}),
+			NLRI:           encodePrefixes(v4[i:end(i, len(v4))]),
+		}
+		if err := s.routeMonitor(p, up); err != nil {
+			return err
+		}
+	}
+	for i := 0; i < len(v6); i += maxPrefixesPerUpdate {
+		// AFI 2, SAFI 1, Next Hop and Reserved byte
+		mp := append([]byte{0, 2, 1, 16}, p.nextHop().To16()...)
+		mp = append(mp, 0)
+		mp = append(mp, encodePrefixes(v6[i:end(i, len(v6))])...)
+		up := &bgp.Update{
+			PathAttributes: append(p.pathAttributes(), bgp.PathAttribute{AttributeTypeFlags: 0x80, AttributeType: bgp.MP_REACH_NLRI, Attribute: mp}),
+		}
+		if err := s.routeMonitor(p, up); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Withdraw sends Route Monitoring messages withdrawing prefixes of the peer, IPv4 prefixes are carried in
+// Withdrawn Routes and IPv6 prefixes in MP_UNREACH_NLRI.
+func (s *Speaker) Withdraw(p *Peer, prefixes []*net.IPNet) error {
+	v4, v6 := split(prefixes)
+	for i := 0; i < len(v4); i += maxPrefixesPerUpdate {
+		if err := s.routeMonitor(p, &bgp.Update{WithdrawnRoutes: encodePrefixes(v4[i:end(i, len(v4))])}); err != nil {
+			return err
+		}
+	}
+	for i := 0; i < len(v6); i += maxPrefixesPerUpdate {
+		mp := append([]byte{0, 2, 1}, encodePrefixes(v6[i:end(i, len(v6))])...)
+		up := &bgp.Update{
+			PathAttributes: []bgp.PathAttribute{{AttributeTypeFlags: 0x80, AttributeType: bgp.MP_UNREACH_NLRI, Attribute: mp}},
+		}
+		if err := s.routeMonitor(p, up); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// EndOfRIB sends End-of-RIB marker of IPv4 Unicast, or of IPv6 Unicast when ipv6 is true
+func (s *Speaker) EndOfRIB(p *Peer, ipv6 bool) error {
+	up := &bgp.Update{}
+	if ipv6 {
+		up.PathAttributes = []bgp.PathAttribute{{AttributeTypeFlags: 0x80, AttributeType: bgp.MP_UNREACH_NLRI, Attribute: []byte{0, 2, 1}}}
+	}
+
+	return s.routeMonitor(p, up)
+}
+
+func (s *Speaker) routeMonitor(p *Peer, up *bgp.Update) error {
+	rm := &bmp.RouteMonitor{Update: up}
+	b, err := rm.Serialize()
+	if err != nil {
+		return err
+	}
+
+	return s.send(bmp.SerializeMessage(bmp.RouteMonitorMsg, p.perPeerHeader(), b))
+}
+
+func (p *Peer) perPeerHeader() *bmp.PerPeerHeader {
+	return bmp.NewPerPeerHeader(p.Address, p.AS, p.BGPID, time.Now(), false)
+}
+
+// nextHop returns the peer's address in the family of the peer
+func (p *Peer) nextHop() net.IP {
+	if p.Address.To4() != nil {
+		return p.Address.To4()
+	}
+
+	return p.Address.To16()
+}
+
+// pathAttributes returns ORIGIN and AS_PATH with the peer's AS of advertised prefixes
+func (p *Peer) pathAttributes() []bgp.PathAttribute {
+	asPath := make([]byte, 6)
+	// AS_SEQUENCE of one 4 bytes AS
+	asPath[0] = 2
+	asPath[1] = 1
+	binary.BigEndian.PutUint32(asPath[2:], p.AS)
+
+	return []bgp.PathAttribute{
+		{AttributeTypeFlags: 0x40, AttributeType: 1, Attribute: []byte{0}},
+		{AttributeTypeFlags: 0x40, AttributeType: 2, Attribute: asPath},
+	}
+}
+
+func openMessage(as uint32, bgpID net.IP) *bgp.OpenMessage {
+	myAS := uint16(as)
+	if as > 0xffff {
+		// AS_TRANS
+		myAS = 23456
+	}
+	as4 := make([]byte, 4)
+	binary.BigEndian.PutUint32(as4, as)
+	id := make([]byte, 4)
+	copy(id, bgpID.To4())
+
+	return &bgp.OpenMessage{
+		MyAS:     myAS,
+		HoldTime: 180,
+		BGPID:    id,
+		Capabilities: bgp.Capability{
+			// Multiprotocol Extensions IPv4 Unicast and IPv6 Unicast
+			1: []*bgp.CapabilityData{{Value: []byte{0, 1, 0, 1}}, {Value: []byte{0, 2, 0, 1}}},
+			// Route Refresh
+			2: []*bgp.CapabilityData{{Value: []byte{}}},
+			// 4-octet AS number
+			65: []*bgp.CapabilityData{{Value: as4}},
+		},
+	}
+}
+
+// split returns IPv4 and IPv6 prefixes
+func split(prefixes []*net.IPNet) ([]*net.IPNet, []*net.IPNet) {
+	v4 := make([]*net.IPNet, 0, len(prefixes))
+	v6 := make([]*net.IPNet, 0)
+	for _, p := range prefixes {
+		if p.IP.To4() != nil {
+			v4 = append(v4, p)
+		} else {
+			v6 = append(v6, p)
+		}
+	}
+
+	return v4, v6
+}
+
+func encodePrefixes(prefixes []*net.IPNet) []byte {
+	b := make([]byte, 0, len(prefixes)*5)
+	for _, p := range prefixes {
+		ip := p.IP.To4()
+		if ip == nil {
+			ip = p.IP.To16()
+		}
+		l, _ := p.Mask.Size()
+		b = append(b, byte(l))
+		b = append(b, ip[:(l+7)/8]...)
+	}
+
+	return b
+}
+
+func end(i, n int) int {
+	if i+maxPrefixesPerUpdate > n {
+		return n
+	}
+
+	return i + maxPrefixesPerUpdate
+}
@@ -0,0 +1,140 @@
+package capture
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/golang/glog"
+)
+
+// MaxCount defines the maximum number of messages of a single capture
+const MaxCount = 100000
+
+// Capturer defines methods to capture raw BMP messages of a router on demand, captured messages are
+// written to a file as received, so the file is a BMP stream which can be replayed to a BMP listener.
+type Capturer interface {
+	// Start starts capturing of the next count messages of the router, it returns the capture
+	Start(router string, count int) (*Capture, error)
+	// Captures returns captures in progress
+	Captures() []*Capture
+	// Capture is called with every raw BMP message received from the router
+	Capture(router net.IP, msg []byte)
+}
+
+// Capture defines a capture of BMP messages of a router
+type Capture struct {
+	Router    string `json:"router"`
+	File      string `json:"file"`
+	Remaining int    `json:"remaining"`
+	f         *os.File
+}
+
+type capturer struct {
+	dir string
+	// active is the number of captures in progress, it is checked without the lock for every message
+	active   int32
+	mtx      sync.Mutex
+	captures map[string]*Capture
+}
+
+var _ Capturer = &capturer{}
+
+func (c *capturer) Start(router string, count int) (*Capture, error) {
+	ip := net.ParseIP(router)
+	if ip == nil {
+		return nil, fmt.Errorf("invalid router address %s", router)
+	}
+	if count <= 0 || count > MaxCount {
+		return nil, fmt.Errorf("invalid count %d, expected 1 to %d messages", count, MaxCount)
+	}
+	router = ip.String()
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	if _, ok := c.captures[router]; ok {
+		return nil, fmt.Errorf("capture of router %s is already in progress", router)
+	}
+	name := fmt.Sprintf("%s-%s.bmp", strings.ReplaceAll(router, ":", "_"), time.Now().UTC().Format("20060102T150405Z"))
+	f, err := os.Create(filepath.Join(c.dir, name))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create capture file with error: %+v", err)
+	}
+	cp := &Capture{
+		Router:    router,
+		File:      f.Name(),
+		Remaining: count,
+		f:         f,
+	}
+	c.captures[router] = cp
+	atomic.AddInt32(&c.active, 1)
+	glog.Infof("capturing %d BMP messages of router %s to %s", count, router, cp.File)
+
+	return cp.copy(), nil
+}
+
+func (c *capturer) Captures() []*Capture {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	captures := make([]*Capture, 0, len(c.captures))
+	for _, cp := range c.captures {
+		captures = append(captures, cp.copy())
+	}
+
+	return captures
+}
+
+func (c *capturer) Capture(router net.IP, msg []byte) {
+	if atomic.LoadInt32(&c.active) == 0 {
+		return
+	}
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	cp, ok := c.captures[router.String()]
+	if !ok {
+		return
+	}
+	if _, err := cp.f.Write(msg); err != nil {
+		glog.Errorf("failed to write BMP message of router %s to capture file %s with error: %+v", cp.Router, cp.File, err)
+		cp.Remaining = 0
+	} else {
+		cp.Remaining--
+	}
+	if cp.Remaining > 0 {
+		return
+	}
+	if err := cp.f.Close(); err != nil {
+		glog.Errorf("failed to close capture file %s with error: %+v", cp.File, err)
+	}
+	delete(c.captures, cp.Router)
+	atomic.AddInt32(&c.active, -1)
+	glog.Infof("capture of BMP messages of router %s to %s is completed", cp.Router, cp.File)
+}
+
+func (cp *Capture) copy() *Capture {
+	return &Capture{
+		Router:    cp.Router,
+		File:      cp.File,
+		Remaining: cp.Remaining,
+	}
+}
+
+// NewCapturer returns a new instance of Capturer writing capture files to dir
+func NewCapturer(dir string) (Capturer, error) {
+	fi, err := os.Stat(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to access capture directory with error: %+v", err)
+	}
+	if !fi.IsDir() {
+		return nil, fmt.Errorf("%s is not a directory", dir)
+	}
+
+	return &capturer{
+		dir:      dir,
+		captures: make(map[string]*Capture),
+	}, nil
+}
@@ -0,0 +1,116 @@
+package capture
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestCapture(t *testing.T) {
+	c, err := NewCapturer(t.TempDir())
+	if err != nil {
+		t.Fatalf("failed to create capturer with error: %+v", err)
+	}
+	router := net.ParseIP("192.0.2.1")
+	// Messages received before the capture is started are not captured
+	c.Capture(router, []byte{0})
+	cp, err := c.Start("192.0.2.1", 2)
+	if err != nil {
+		t.Fatalf("failed to start capture with error: %+v", err)
+	}
+	if _, err := c.Start("192.0.2.1", 2); err == nil {
+		t.Fatalf("second capture of the same router supposed to fail but succeeded")
+	}
+	c.Capture(net.ParseIP("192.0.2.2"), []byte{9})
+	c.Capture(router, []byte{1, 2})
+	if captures := c.Captures(); len(captures) != 1 || captures[0].Remaining != 1 {
+		t.Fatalf("expected 1 capture with 1 remaining message, got %+v", captures)
+	}
+	c.Capture(router, []byte{3})
+	c.Capture(router, []byte{4})
+	if captures := c.Captures(); len(captures) != 0 {
+		t.Fatalf("expected no captures in progress, got %+v", captures)
+	}
+	b, err := ioutil.ReadFile(cp.File)
+	if err != nil {
+		t.Fatalf("failed to read capture file with error: %+v", err)
+	}
+	if !bytes.Equal(b, []byte{1, 2, 3}) {
+		t.Fatalf("expected captured bytes 010203, got %x", b)
+	}
+}
+
+func TestStart(t *testing.T) {
+	tests := []struct {
+		name   string
+		router string
+		count  int
+		fail   bool
+	}{
+		{
+			name:   "ipv6 router",
+			router: "2001:db8::1",
+			count:  10,
+		},
+		{
+			name:   "invalid router",
+			router: "router1",
+			count:  10,
+			fail:   true,
+		},
+		{
+			name:   "zero count",
+			router: "192.0.2.1",
+			count:  0,
+			fail:   true,
+		},
+		{
+			name:   "count above maximum",
+			router: "192.0.2.1",
+			count:  MaxCount + 1,
+			fail:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := NewCapturer(t.TempDir())
+			if err != nil {
+				t.Fatalf("failed to create capturer with error: %+v", err)
+			}
+			_, err = c.Start(tt.router, tt.count)
+			if err != nil && !tt.fail {
+				t.Fatalf("supposed to succeed but failed with error: %+v", err)
+			}
+			if err == nil && tt.fail {
+				t.Fatalf("supposed to fail but succeeded")
+			}
+		})
+	}
+}
+
+func TestHandler(t *testing.T) {
+	c, err := NewCapturer(t.TempDir())
+	if err != nil {
+		t.Fatalf("failed to create capturer with error: %+v", err)
+	}
+	h := NewHandler(c)
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/debug/capture?router=192.0.2.1&count=5", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/debug/capture?router=192.0.2.1&count=many", nil))
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected status %d, got %d", http.StatusBadRequest, w.Code)
+	}
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/debug/capture", nil))
+	if w.Code != http.StatusOK || !bytes.Contains(w.Body.Bytes(), []byte(`"remaining":5`)) {
+		t.Fatalf("expected capture with 5 remaining messages, got %d: %s", w.Code, w.Body.String())
+	}
+	c.(*capturer).captures["192.0.2.1"].f.Close()
+}
@@ -0,0 +1,45 @@
+package capture
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/golang/glog"
+)
+
+// NewHandler returns http handler controlling captures of BMP messages:
+//
+//	GET /debug/capture
+//	POST /debug/capture?router={router address}&count={number of messages}
+//
+// GET returns captures in progress, POST starts a new capture and returns it.
+func NewHandler(c Capturer) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			writeJSON(w, c.Captures())
+		case http.MethodPost:
+			count, err := strconv.Atoi(r.URL.Query().Get("count"))
+			if err != nil {
+				http.Error(w, "invalid count "+r.URL.Query().Get("count"), http.StatusBadRequest)
+				return
+			}
+			cp, err := c.Start(r.URL.Query().Get("router"), count)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			writeJSON(w, cp)
+		default:
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		}
+	})
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		glog.Errorf("failed to send capture response with error: %+v", err)
+	}
+}
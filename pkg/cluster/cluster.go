@@ -0,0 +1,208 @@
+package cluster
+
+import (
+	"fmt"
+	"net"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/golang/glog"
+)
+
+// Cluster defines methods to find the owner of router's BMP session among gobmp instances, a router
+// is owned by exactly one live member, only the owner processes router's BMP session.
+type Cluster interface {
+	// Owner returns the address of the member owning the router
+	Owner(router net.IP) string
+	// IsOwner returns true if the local member owns the router
+	IsOwner(router net.IP) bool
+	// IsMember returns true if ip is the address of a cluster member
+	IsMember(ip net.IP) bool
+	// Leader returns the address of the member running singleton tasks, the leader is the first live
+	// member in the sorted list of members
+	Leader() string
+	// IsLeader returns true if the local member is the leader
+	IsLeader() bool
+	// Members returns all members with their state
+	Members() []*Member
+	Stop()
+}
+
+// Member defines the state of a cluster member
+type Member struct {
+	Address string `json:"address"`
+	Alive   bool   `json:"alive"`
+	Leader  bool   `json:"leader,omitempty"`
+	Self    bool   `json:"self,omitempty"`
+}
+
+type cluster struct {
+	sync.RWMutex
+	self    string
+	members []string
+	ips     map[string]bool
+	alive   map[string]bool
+	ring    *ring
+	dial    func(address string) error
+	stop    chan struct{}
+}
+
+var _ Cluster = &cluster{}
+
+// probeTimeout defines how long a member has to accept a probe connection
+const probeTimeout = 2 * time.Second
+
+// NewCluster instantiates cluster membership of the local member self, members are BMP listening
+// addresses host:port of all members including self. Every probe interval, other members are probed by
+// connecting to their BMP listening address, a member failing the probe is removed from the ring until
+// it accepts a probe again. 0 probe interval disables probing.
+func NewCluster(self string, members []string, probe time.Duration) (Cluster, error) {
+	c := &cluster{
+		self:    self,
+		members: make([]string, 0, len(members)),
+		ips:     make(map[string]bool),
+		alive:   make(map[string]bool),
+		dial: func(address string) error {
+			conn, err := net.DialTimeout("tcp", address, probeTimeout)
+			if err != nil {
+				return err
+			}
+			return conn.Close()
+		},
+		stop: make(chan struct{}),
+	}
+	found := false
+	for _, m := range members {
+		addr, err := net.ResolveTCPAddr("tcp", m)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve cluster member %s with error: %+v", m, err)
+		}
+		if m == self {
+			found = true
+		}
+		c.members = append(c.members, m)
+		c.ips[addr.IP.String()] = true
+		c.alive[m] = true
+	}
+	if !found {
+		return nil, fmt.Errorf("local member %s is not in the list of cluster members", self)
+	}
+	sort.Strings(c.members)
+	c.ring = newRing(c.members)
+	if probe > 0 {
+		go c.prober(probe)
+	}
+
+	return c, nil
+}
+
+func (c *cluster) Owner(router net.IP) string {
+	c.RLock()
+	defer c.RUnlock()
+
+	return c.ring.owner(router.String())
+}
+
+func (c *cluster) IsOwner(router net.IP) bool {
+	return c.Owner(router) == c.self
+}
+
+func (c *cluster) IsMember(ip net.IP) bool {
+	c.RLock()
+	defer c.RUnlock()
+
+	return c.ips[ip.String()]
+}
+
+func (c *cluster) Leader() string {
+	c.RLock()
+	defer c.RUnlock()
+
+	return c.leader()
+}
+
+func (c *cluster) IsLeader() bool {
+	return c.Leader() == c.self
+}
+
+// leader returns the first live member, members are sorted and the local member is always alive
+func (c *cluster) leader() string {
+	for _, m := range c.members {
+		if c.alive[m] {
+			return m
+		}
+	}
+
+	return ""
+}
+
+func (c *cluster) Members() []*Member {
+	c.RLock()
+	defer c.RUnlock()
+	leader := c.leader()
+	members := make([]*Member, 0, len(c.members))
+	for _, m := range c.members {
+		members = append(members, &Member{
+			Address: m,
+			Alive:   c.alive[m],
+			Leader:  m == leader,
+			Self:    m == c.self,
+		})
+	}
+
+	return members
+}
+
+func (c *cluster) Stop() {
+	close(c.stop)
+}
+
+func (c *cluster) prober(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			c.probe()
+		case <-c.stop:
+			return
+		}
+	}
+}
+
+// probe checks all other members and rebuilds the ring when any member changed its state
+func (c *cluster) probe() {
+	alive := make(map[string]bool, len(c.members))
+	for _, m := range c.members {
+		if m == c.self {
+			alive[m] = true
+			continue
+		}
+		alive[m] = c.dial(m) == nil
+	}
+	c.Lock()
+	defer c.Unlock()
+	changed := false
+	for m, a := range alive {
+		if c.alive[m] != a {
+			glog.Infof("cluster member %s alive: %t", m, a)
+			changed = true
+		}
+	}
+	if !changed {
+		return
+	}
+	leader := c.leader()
+	c.alive = alive
+	if l := c.leader(); l != leader {
+		glog.Infof("cluster leader changed from %s to %s", leader, l)
+	}
+	live := make([]string, 0, len(c.members))
+	for _, m := range c.members {
+		if alive[m] {
+			live = append(live, m)
+		}
+	}
+	c.ring = newRing(live)
+}
@@ -0,0 +1,120 @@
+package cluster
+
+import (
+	"fmt"
+	"net"
+	"testing"
+)
+
+func TestOwnership(t *testing.T) {
+	members := []string{"192.0.2.1:5000", "192.0.2.2:5000", "192.0.2.3:5000"}
+	clusters := make([]*cluster, len(members))
+	for i, m := range members {
+		c, err := NewCluster(m, members, 0)
+		if err != nil {
+			t.Fatalf("failed to create cluster with error: %+v", err)
+		}
+		clusters[i] = c.(*cluster)
+	}
+	// Reversed order of members must not change ownership
+	reversed := []string{members[2], members[1], members[0]}
+	rc, err := NewCluster(members[0], reversed, 0)
+	if err != nil {
+		t.Fatalf("failed to create cluster with error: %+v", err)
+	}
+	owned := make(map[string]int)
+	for i := 0; i < 300; i++ {
+		router := net.IPv4(10, 0, byte(i>>8), byte(i))
+		owners := 0
+		for _, c := range clusters {
+			if c.IsOwner(router) {
+				owners++
+			}
+		}
+		if owners != 1 {
+			t.Fatalf("router %s has %d owners", router, owners)
+		}
+		owner := clusters[0].Owner(router)
+		if rc.Owner(router) != owner {
+			t.Fatalf("ownership of router %s depends on the order of members", router)
+		}
+		owned[owner]++
+	}
+	for _, m := range members {
+		if owned[m] == 0 {
+			t.Errorf("member %s does not own any router", m)
+		}
+	}
+	if !clusters[0].IsMember(net.ParseIP("192.0.2.3")) || clusters[0].IsMember(net.ParseIP("10.0.0.1")) {
+		t.Errorf("unexpected cluster membership")
+	}
+}
+
+func TestProbe(t *testing.T) {
+	members := []string{"192.0.2.1:5000", "192.0.2.2:5000"}
+	c, err := NewCluster(members[0], members, 0)
+	if err != nil {
+		t.Fatalf("failed to create cluster with error: %+v", err)
+	}
+	cl := c.(*cluster)
+	down := false
+	cl.dial = func(address string) error {
+		if down {
+			return fmt.Errorf("connection refused")
+		}
+		return nil
+	}
+	routers := make([]net.IP, 0)
+	for i := 0; i < 100; i++ {
+		router := net.IPv4(10, 0, 0, byte(i))
+		if !c.IsOwner(router) {
+			routers = append(routers, router)
+		}
+	}
+	if len(routers) == 0 {
+		t.Fatalf("expected some routers to be owned by %s", members[1])
+	}
+	down = true
+	cl.probe()
+	for _, r := range routers {
+		if !c.IsOwner(r) {
+			t.Fatalf("expected router %s to be owned by local member after %s is down", r, members[1])
+		}
+	}
+	down = false
+	cl.probe()
+	for _, r := range routers {
+		if c.IsOwner(r) {
+			t.Fatalf("expected router %s to be owned by %s after it is up", r, members[1])
+		}
+	}
+}
+
+func TestNewClusterWithoutSelf(t *testing.T) {
+	if _, err := NewCluster("192.0.2.9:5000", []string{"192.0.2.1:5000"}, 0); err == nil {
+		t.Fatalf("expected to fail but succeeded")
+	}
+}
+
+func TestLeader(t *testing.T) {
+	members := []string{"192.0.2.2:5000", "192.0.2.1:5000"}
+	c, err := NewCluster(members[0], members, 0)
+	if err != nil {
+		t.Fatalf("failed to create cluster with error: %+v", err)
+	}
+	cl := c.(*cluster)
+	cl.dial = func(address string) error { return nil }
+	if c.Leader() != "192.0.2.1:5000" || c.IsLeader() {
+		t.Fatalf("expected 192.0.2.1:5000 to be the leader, got %s", c.Leader())
+	}
+	cl.dial = func(address string) error { return fmt.Errorf("connection refused") }
+	cl.probe()
+	if !c.IsLeader() {
+		t.Fatalf("expected local member to be the leader after 192.0.2.1:5000 is down, got %s", c.Leader())
+	}
+	for _, m := range c.Members() {
+		if m.Self != (m.Address == members[0]) || m.Leader != m.Self || m.Alive != m.Self {
+			t.Errorf("unexpected state of member %+v", m)
+		}
+	}
+}
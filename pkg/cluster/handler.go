@@ -0,0 +1,98 @@
+package cluster
+
+import (
+	"encoding/json"
+	"net"
+	"net/http"
+
+	"github.com/golang/glog"
+)
+
+// Placement defines the member owning the router and members currently serving its BMP session, a router
+// is served by a member other than the owner until its session is moved after membership change.
+type Placement struct {
+	Router  string   `json:"router"`
+	Owner   string   `json:"owner"`
+	Serving []string `json:"serving"`
+}
+
+type handler struct {
+	cluster  Cluster
+	registry Registry
+}
+
+// NewHandler returns http handler of the cluster API used by members to share their BMP sessions and by load
+// balancers to steer routers to their owners:
+//
+//	GET /cluster/members
+//	GET /cluster/sessions
+//	GET /cluster/sessions?scope=local
+//	GET /cluster/owner?router={router}
+//
+// members returns members with their state and the leader, sessions returns BMP sessions of all live members
+// or only of the local member. owner returns the BMP listening address of the member owning the router.
+func NewHandler(c Cluster, r Registry) http.Handler {
+	h := &handler{
+		cluster:  c,
+		registry: r,
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/cluster/members", h.handleMembers)
+	mux.HandleFunc("/cluster/sessions", h.handleSessions)
+	mux.HandleFunc("/cluster/owner", h.handleOwner)
+
+	return mux
+}
+
+func (h *handler) handleMembers(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	writeJSON(w, h.cluster.Members())
+}
+
+func (h *handler) handleSessions(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	switch scope := r.URL.Query().Get("scope"); scope {
+	case "":
+		writeJSON(w, h.registry.Sessions())
+	case "local":
+		writeJSON(w, h.registry.Local())
+	default:
+		http.Error(w, "invalid scope "+scope, http.StatusBadRequest)
+	}
+}
+
+func (h *handler) handleOwner(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	router := net.ParseIP(r.URL.Query().Get("router"))
+	if router == nil {
+		http.Error(w, "invalid router "+r.URL.Query().Get("router"), http.StatusBadRequest)
+		return
+	}
+	p := &Placement{
+		Router:  router.String(),
+		Owner:   h.cluster.Owner(router),
+		Serving: make([]string, 0),
+	}
+	for _, s := range h.registry.Sessions() {
+		if ip := net.ParseIP(s.Router); ip != nil && ip.Equal(router) {
+			p.Serving = append(p.Serving, s.Member)
+		}
+	}
+	writeJSON(w, p)
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		glog.Errorf("failed to send cluster API response with error: %+v", err)
+	}
+}
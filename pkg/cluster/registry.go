@@ -0,0 +1,169 @@
+package cluster
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/golang/glog"
+)
+
+// Session defines BMP session of a router served by a cluster member
+type Session struct {
+	Router  string    `json:"router"`
+	Member  string    `json:"member"`
+	Started time.Time `json:"started"`
+}
+
+// Registry defines methods of the cluster-wide registry of BMP sessions, every member serves its live sessions
+// on the cluster API port and pulls sessions of other live members, so the registry of any member can be used
+// to find which member serves the router.
+type Registry interface {
+	// Sessions returns BMP sessions of all live members sorted by the router
+	Sessions() []*Session
+	// Local returns BMP sessions served by the local member
+	Local() []*Session
+	Stop()
+}
+
+type registry struct {
+	sync.RWMutex
+	cluster Cluster
+	port    int
+	local   func() []*Session
+	// remote stores sessions pulled from other members keyed by the member
+	remote map[string][]*Session
+	fetch  func(url string) ([]*Session, error)
+	stop   chan struct{}
+}
+
+var _ Registry = &registry{}
+
+// NewRegistry instantiates the registry of BMP sessions of cluster members, port is the cluster API port
+// used by all members and local returns sessions served by the local member. Every pull interval, sessions
+// of other live members are pulled from their cluster API, 0 pull interval disables pulling.
+func NewRegistry(c Cluster, port int, local func() []*Session, pull time.Duration) Registry {
+	r := &registry{
+		cluster: c,
+		port:    port,
+		local:   local,
+		remote:  make(map[string][]*Session),
+		fetch:   fetchSessions,
+		stop:    make(chan struct{}),
+	}
+	if pull > 0 {
+		go r.puller(pull)
+	}
+
+	return r
+}
+
+func (r *registry) Local() []*Session {
+	self := ""
+	for _, m := range r.cluster.Members() {
+		if m.Self {
+			self = m.Address
+		}
+	}
+	sessions := r.local()
+	for _, s := range sessions {
+		s.Member = self
+	}
+
+	return sessions
+}
+
+func (r *registry) Sessions() []*Session {
+	sessions := r.Local()
+	r.RLock()
+	for _, s := range r.remote {
+		sessions = append(sessions, s...)
+	}
+	r.RUnlock()
+	sort.SliceStable(sessions, func(i, j int) bool {
+		if sessions[i].Router == sessions[j].Router {
+			return sessions[i].Member < sessions[j].Member
+		}
+		return sessions[i].Router < sessions[j].Router
+	})
+
+	return sessions
+}
+
+func (r *registry) Stop() {
+	close(r.stop)
+}
+
+func (r *registry) puller(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			r.pull()
+		case <-r.stop:
+			return
+		}
+	}
+}
+
+// pull replaces sessions of other members with sessions served by them, sessions of members which are down
+// or failed to respond are removed.
+func (r *registry) pull() {
+	remote := make(map[string][]*Session)
+	for _, m := range r.cluster.Members() {
+		if m.Self || !m.Alive {
+			continue
+		}
+		url, err := r.url(m.Address)
+		if err != nil {
+			glog.Errorf("failed to build cluster API address of member %s with error: %+v", m.Address, err)
+			continue
+		}
+		sessions, err := r.fetch(url)
+		if err != nil {
+			glog.Warningf("failed to pull BMP sessions of cluster member %s with error: %+v", m.Address, err)
+			continue
+		}
+		for _, s := range sessions {
+			s.Member = m.Address
+		}
+		remote[m.Address] = sessions
+	}
+	r.Lock()
+	defer r.Unlock()
+	r.remote = remote
+}
+
+// url returns the address of local sessions in the cluster API of the member
+func (r *registry) url(member string) (string, error) {
+	host, _, err := net.SplitHostPort(member)
+	if err != nil {
+		return "", err
+	}
+
+	return "http://" + net.JoinHostPort(host, strconv.Itoa(r.port)) + "/cluster/sessions?scope=local", nil
+}
+
+func fetchSessions(url string) ([]*Session, error) {
+	client := &http.Client{Timeout: probeTimeout}
+	resp, err := client.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	sessions := make([]*Session, 0)
+	if err := json.NewDecoder(resp.Body).Decode(&sessions); err != nil {
+		return nil, err
+	}
+
+	return sessions, nil
+}
@@ -0,0 +1,65 @@
+package cluster
+
+import (
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestRegistry(t *testing.T) {
+	members := []string{"127.0.0.1:5000", "127.0.0.1:5001"}
+	local, err := NewCluster(members[0], members, 0)
+	if err != nil {
+		t.Fatalf("failed to create cluster with error: %+v", err)
+	}
+	remote, err := NewCluster(members[1], members, 0)
+	if err != nil {
+		t.Fatalf("failed to create cluster with error: %+v", err)
+	}
+	started := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	rr := NewRegistry(remote, 0, func() []*Session {
+		return []*Session{{Router: "192.0.2.1", Started: started}}
+	}, 0)
+	srv := httptest.NewServer(NewHandler(remote, rr))
+	defer srv.Close()
+	_, port, _ := net.SplitHostPort(srv.Listener.Addr().String())
+	p, _ := strconv.Atoi(port)
+	lr := NewRegistry(local, p, func() []*Session {
+		return []*Session{{Router: "192.0.2.2", Started: started}}
+	}, 0)
+	lr.(*registry).pull()
+	sessions := lr.Sessions()
+	if len(sessions) != 2 {
+		t.Fatalf("expected 2 sessions, got %d", len(sessions))
+	}
+	if sessions[0].Router != "192.0.2.1" || sessions[0].Member != members[1] || !sessions[0].Started.Equal(started) {
+		t.Errorf("unexpected remote session %+v", sessions[0])
+	}
+	if sessions[1].Router != "192.0.2.2" || sessions[1].Member != members[0] {
+		t.Errorf("unexpected local session %+v", sessions[1])
+	}
+
+	resp, err := http.Get(srv.URL + "/cluster/owner?router=192.0.2.1")
+	if err != nil {
+		t.Fatalf("failed to get owner with error: %+v", err)
+	}
+	defer resp.Body.Close()
+	pl := &Placement{}
+	if err := json.NewDecoder(resp.Body).Decode(pl); err != nil {
+		t.Fatalf("failed to decode placement with error: %+v", err)
+	}
+	if pl.Owner != remote.Owner(net.ParseIP("192.0.2.1")) || len(pl.Serving) != 1 || pl.Serving[0] != members[1] {
+		t.Errorf("unexpected placement %+v", pl)
+	}
+
+	// Sessions of the member which failed to respond are removed
+	srv.Close()
+	lr.(*registry).pull()
+	if sessions := lr.Sessions(); len(sessions) != 1 || sessions[0].Member != members[0] {
+		t.Errorf("expected only local session, got %+v", sessions)
+	}
+}
@@ -0,0 +1,62 @@
+package cluster
+
+import (
+	"hash/fnv"
+	"sort"
+	"strconv"
+)
+
+// virtualNodes defines the number of points each member takes on the ring, more points give more even
+// distribution of routers between members.
+const virtualNodes = 128
+
+// ring defines consistent hash ring of cluster members
+type ring struct {
+	points  []uint32
+	members map[uint32]string
+}
+
+func hash(s string) uint32 {
+	h := fnv.New32a()
+	h.Write([]byte(s))
+
+	return h.Sum32()
+}
+
+// newRing builds consistent hash ring of members, the ring does not depend on the order of members.
+func newRing(members []string) *ring {
+	r := &ring{
+		points:  make([]uint32, 0, len(members)*virtualNodes),
+		members: make(map[uint32]string, len(members)*virtualNodes),
+	}
+	for _, m := range members {
+		for i := 0; i < virtualNodes; i++ {
+			p := hash(m + "#" + strconv.Itoa(i))
+			if o, ok := r.members[p]; ok && o < m {
+				// Collision, keeping the member with the lower name to stay deterministic
+				continue
+			}
+			if _, ok := r.members[p]; !ok {
+				r.points = append(r.points, p)
+			}
+			r.members[p] = m
+		}
+	}
+	sort.Slice(r.points, func(i, j int) bool { return r.points[i] < r.points[j] })
+
+	return r
+}
+
+// owner returns the member owning the key, empty string is returned when the ring has no members.
+func (r *ring) owner(key string) string {
+	if len(r.points) == 0 {
+		return ""
+	}
+	h := hash(key)
+	i := sort.Search(len(r.points), func(i int) bool { return r.points[i] >= h })
+	if i == len(r.points) {
+		i = 0
+	}
+
+	return r.members[r.points[i]]
+}
@@ -0,0 +1,86 @@
+package codec
+
+import (
+	"encoding/binary"
+	"math"
+)
+
+// CBOR major types, RFC 8949 Section 3.1
+const (
+	cborUint     = 0
+	cborNegative = 1
+	cborText     = 3
+	cborArray    = 4
+	cborMap      = 5
+	cborSimple   = 7
+)
+
+type cborWriter struct {
+	b []byte
+}
+
+// head writes the initial byte of major type and its argument in the shortest form
+func (w *cborWriter) head(major byte, arg uint64) {
+	m := major << 5
+	switch {
+	case arg < 24:
+		w.b = append(w.b, m|byte(arg))
+	case arg <= math.MaxUint8:
+		w.b = append(w.b, m|24, byte(arg))
+	case arg <= math.MaxUint16:
+		w.b = append(w.b, m|25)
+		w.b = append(w.b, make([]byte, 2)...)
+		binary.BigEndian.PutUint16(w.b[len(w.b)-2:], uint16(arg))
+	case arg <= math.MaxUint32:
+		w.b = append(w.b, m|26)
+		w.b = append(w.b, make([]byte, 4)...)
+		binary.BigEndian.PutUint32(w.b[len(w.b)-4:], uint32(arg))
+	default:
+		w.b = append(w.b, m|27)
+		w.b = append(w.b, make([]byte, 8)...)
+		binary.BigEndian.PutUint64(w.b[len(w.b)-8:], arg)
+	}
+}
+
+func (w *cborWriter) null() {
+	w.b = append(w.b, cborSimple<<5|22)
+}
+
+func (w *cborWriter) boolean(v bool) {
+	if v {
+		w.b = append(w.b, cborSimple<<5|21)
+		return
+	}
+	w.b = append(w.b, cborSimple<<5|20)
+}
+
+func (w *cborWriter) uint(v uint64) {
+	w.head(cborUint, v)
+}
+
+func (w *cborWriter) negative(n uint64) {
+	w.head(cborNegative, n)
+}
+
+func (w *cborWriter) float(v float64) {
+	w.b = append(w.b, cborSimple<<5|27)
+	w.b = append(w.b, make([]byte, 8)...)
+	binary.BigEndian.PutUint64(w.b[len(w.b)-8:], math.Float64bits(v))
+}
+
+func (w *cborWriter) str(s string) {
+	w.head(cborText, uint64(len(s)))
+	w.b = append(w.b, s...)
+}
+
+func (w *cborWriter) array(n int) {
+	w.head(cborArray, uint64(n))
+}
+
+func (w *cborWriter) object(n int) {
+	w.head(cborMap, uint64(n))
+}
+
+func (w *cborWriter) bytes() []byte {
+	return w.b
+}
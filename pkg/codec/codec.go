@@ -0,0 +1,158 @@
+package codec
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/sbezverk/gobmp/pkg/pub"
+)
+
+const (
+	// JSON is the default format, messages are published as produced
+	JSON = "json"
+	// CBOR is Concise Binary Object Representation defined in RFC 8949
+	CBOR = "cbor"
+	// MessagePack is MessagePack binary format
+	MessagePack = "msgpack"
+	// Flat is JSON format with nested objects flattened into dotted keys
+	Flat = "flat"
+)
+
+// writer defines methods of a binary encoding of values decoded from JSON
+type writer interface {
+	null()
+	boolean(bool)
+	uint(uint64)
+	// negative writes negative integer -1-n
+	negative(uint64)
+	float(float64)
+	str(string)
+	array(int)
+	object(int)
+	bytes() []byte
+}
+
+type codec struct {
+	format    string
+	publisher pub.Publisher
+}
+
+func (c *codec) PublishMessage(msgType int, msgHash []byte, msg []byte) error {
+	b, err := Encode(c.format, msg)
+	if err != nil {
+		return fmt.Errorf("failed to encode message of type %d to %s with error: %+v", msgType, c.format, err)
+	}
+
+	return c.publisher.PublishMessage(msgType, msgHash, b)
+}
+
+func (c *codec) Stop() {
+	c.publisher.Stop()
+}
+
+// NewPublisher returns a Publisher encoding JSON messages to format before they are published
+// to publisher, JSON format returns publisher as it is.
+func NewPublisher(format string, publisher pub.Publisher) (pub.Publisher, error) {
+	switch strings.ToLower(format) {
+	case JSON, "":
+		return publisher, nil
+	case CBOR:
+		return &codec{format: CBOR, publisher: publisher}, nil
+	case MessagePack:
+		return &codec{format: MessagePack, publisher: publisher}, nil
+	case Flat:
+		return &codec{format: Flat, publisher: publisher}, nil
+	}
+
+	return nil, fmt.Errorf("unsupported message format %s", format)
+}
+
+// Encode encodes JSON message to format, in binary formats keys of objects are sorted, integers
+// are encoded as integers and other numbers as 64 bit floats.
+func Encode(format string, msg []byte) ([]byte, error) {
+	var w writer
+	switch format {
+	case Flat:
+		return Flatten(msg)
+	case CBOR:
+		w = &cborWriter{}
+	case MessagePack:
+		w = &msgpackWriter{}
+	default:
+		return nil, fmt.Errorf("unsupported message format %s", format)
+	}
+	d := json.NewDecoder(bytes.NewReader(msg))
+	d.UseNumber()
+	var v interface{}
+	if err := d.Decode(&v); err != nil {
+		return nil, err
+	}
+	if err := encode(w, v); err != nil {
+		return nil, err
+	}
+
+	return w.bytes(), nil
+}
+
+func encode(w writer, v interface{}) error {
+	switch t := v.(type) {
+	case nil:
+		w.null()
+	case bool:
+		w.boolean(t)
+	case json.Number:
+		return encodeNumber(w, t)
+	case string:
+		w.str(t)
+	case []interface{}:
+		w.array(len(t))
+		for _, e := range t {
+			if err := encode(w, e); err != nil {
+				return err
+			}
+		}
+	case map[string]interface{}:
+		keys := make([]string, 0, len(t))
+		for k := range t {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		w.object(len(t))
+		for _, k := range keys {
+			w.str(k)
+			if err := encode(w, t[k]); err != nil {
+				return err
+			}
+		}
+	default:
+		return fmt.Errorf("unsupported value %v", v)
+	}
+
+	return nil
+}
+
+func encodeNumber(w writer, n json.Number) error {
+	s := n.String()
+	if !strings.ContainsAny(s, ".eE") {
+		if strings.HasPrefix(s, "-") {
+			if u, err := strconv.ParseUint(s[1:], 10, 64); err == nil && u != 0 {
+				w.negative(u - 1)
+				return nil
+			}
+		} else if u, err := strconv.ParseUint(s, 10, 64); err == nil {
+			w.uint(u)
+			return nil
+		}
+	}
+	f, err := n.Float64()
+	if err != nil {
+		return err
+	}
+	w.float(f)
+
+	return nil
+}
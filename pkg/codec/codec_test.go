@@ -0,0 +1,67 @@
+package codec
+
+import (
+	"encoding/hex"
+	"strings"
+	"testing"
+)
+
+func TestEncode(t *testing.T) {
+	long := `"` + strings.Repeat("a", 32) + `"`
+	tests := []struct {
+		name    string
+		json    string
+		cbor    string
+		msgpack string
+	}{
+		{name: "zero", json: `0`, cbor: "00", msgpack: "00"},
+		{name: "small integer", json: `23`, cbor: "17", msgpack: "17"},
+		{name: "one byte integer", json: `128`, cbor: "1880", msgpack: "cc80"},
+		{name: "two bytes integer", json: `1000`, cbor: "1903e8", msgpack: "cd03e8"},
+		{name: "four bytes integer", json: `1000000`, cbor: "1a000f4240", msgpack: "ce000f4240"},
+		{name: "eight bytes integer", json: `1000000000000`, cbor: "1b000000e8d4a51000", msgpack: "cf000000e8d4a51000"},
+		{name: "max uint64", json: `18446744073709551615`, cbor: "1bffffffffffffffff", msgpack: "cfffffffffffffffff"},
+		{name: "minus one", json: `-1`, cbor: "20", msgpack: "ff"},
+		{name: "negative fixint", json: `-32`, cbor: "381f", msgpack: "e0"},
+		{name: "negative one byte", json: `-100`, cbor: "3863", msgpack: "d09c"},
+		{name: "negative two bytes", json: `-1000`, cbor: "3903e7", msgpack: "d1fc18"},
+		{name: "negative eight bytes", json: `-2147483649`, cbor: "3a80000000", msgpack: "d3ffffffff7fffffff"},
+		{name: "float", json: `1.1`, cbor: "fb3ff199999999999a", msgpack: "cb3ff199999999999a"},
+		{name: "exponent", json: `1e2`, cbor: "fb4059000000000000", msgpack: "cb4059000000000000"},
+		{name: "false", json: `false`, cbor: "f4", msgpack: "c2"},
+		{name: "true", json: `true`, cbor: "f5", msgpack: "c3"},
+		{name: "null", json: `null`, cbor: "f6", msgpack: "c0"},
+		{name: "empty string", json: `""`, cbor: "60", msgpack: "a0"},
+		{name: "string", json: `"IETF"`, cbor: "6449455446", msgpack: "a449455446"},
+		{name: "long string", json: long, cbor: "7820" + strings.Repeat("61", 32), msgpack: "d920" + strings.Repeat("61", 32)},
+		{name: "array", json: `[1,2,3]`, cbor: "83010203", msgpack: "93010203"},
+		{name: "object with sorted keys", json: `{"b":[2,3],"a":1}`, cbor: "a26161016162820203", msgpack: "82a16101a162920203"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for format, expect := range map[string]string{CBOR: tt.cbor, MessagePack: tt.msgpack} {
+				b, err := Encode(format, []byte(tt.json))
+				if err != nil {
+					t.Fatalf("failed to encode %s to %s with error: %+v", tt.json, format, err)
+				}
+				if got := hex.EncodeToString(b); got != expect {
+					t.Errorf("%s encoding of %s expected %s, got %s", format, tt.json, expect, got)
+				}
+			}
+		})
+	}
+}
+
+func TestNewPublisher(t *testing.T) {
+	for _, f := range []string{"", "json", "CBOR", "msgpack"} {
+		if _, err := NewPublisher(f, nil); err != nil {
+			t.Errorf("format %s supposed to succeed but failed with error: %+v", f, err)
+		}
+	}
+	if _, err := NewPublisher("avro", nil); err == nil {
+		t.Errorf("format avro supposed to fail but succeeded")
+	}
+	if _, err := Encode(CBOR, []byte(`{"a":`)); err == nil {
+		t.Errorf("invalid json supposed to fail but succeeded")
+	}
+}
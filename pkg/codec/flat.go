@@ -0,0 +1,60 @@
+package codec
+
+import (
+	"bytes"
+	"encoding/json"
+	"strconv"
+	"strings"
+)
+
+// flatDelimiter separates values of arrays rendered as strings
+const flatDelimiter = ","
+
+// Flatten returns JSON message with nested objects flattened into dotted keys, e.g. base_attrs.as_path,
+// arrays of scalar values are rendered as strings of values separated by ",", elements of arrays
+// of objects are flattened with their index, e.g. sids.0.sid.
+func Flatten(msg []byte) ([]byte, error) {
+	d := json.NewDecoder(bytes.NewReader(msg))
+	d.UseNumber()
+	var v interface{}
+	if err := d.Decode(&v); err != nil {
+		return nil, err
+	}
+	o, ok := v.(map[string]interface{})
+	if !ok {
+		return msg, nil
+	}
+	flat := make(map[string]interface{}, len(o))
+	flatten(flat, "", o)
+
+	return json.Marshal(flat)
+}
+
+func flatten(flat map[string]interface{}, prefix string, v interface{}) {
+	switch t := v.(type) {
+	case map[string]interface{}:
+		for k, e := range t {
+			flatten(flat, prefix+k+".", e)
+		}
+	case []interface{}:
+		key := strings.TrimSuffix(prefix, ".")
+		values := make([]string, 0, len(t))
+		for i, e := range t {
+			switch s := e.(type) {
+			case map[string]interface{}, []interface{}:
+				flatten(flat, prefix+strconv.Itoa(i)+".", s)
+			case string:
+				values = append(values, s)
+			case json.Number:
+				values = append(values, s.String())
+			case bool:
+				values = append(values, strconv.FormatBool(s))
+			}
+		}
+		if len(values) != 0 || len(t) == 0 {
+			flat[key] = strings.Join(values, flatDelimiter)
+		}
+	default:
+		flat[strings.TrimSuffix(prefix, ".")] = v
+	}
+}
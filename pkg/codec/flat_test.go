@@ -0,0 +1,59 @@
+package codec
+
+import (
+	"testing"
+)
+
+func TestFlatten(t *testing.T) {
+	tests := []struct {
+		name   string
+		json   string
+		expect string
+		fail   bool
+	}{
+		{
+			name:   "flat message",
+			json:   `{"action":"add","prefix":"10.0.0.0","prefix_len":8,"is_ipv4":true}`,
+			expect: `{"action":"add","is_ipv4":true,"prefix":"10.0.0.0","prefix_len":8}`,
+		},
+		{
+			name: "nested attributes and arrays",
+			json: `{"prefix":"10.0.0.0","base_attrs":{"as_path":[65001,65002],"origin":"igp","community_list":"65000:1",` +
+				`"is_atomic_agg":false},"labels":[],"path_status":["invalid_as_loop","invalid_ibgp"]}`,
+			expect: `{"base_attrs.as_path":"65001,65002","base_attrs.community_list":"65000:1","base_attrs.is_atomic_agg":false,` +
+				`"base_attrs.origin":"igp","labels":"","path_status":"invalid_as_loop,invalid_ibgp","prefix":"10.0.0.0"}`,
+		},
+		{
+			name:   "arrays of objects",
+			json:   `{"sids":[{"sid":"2001:db8::1","flags":{"b":true}},{"sid":"2001:db8::2"}],"prefix_sid":null}`,
+			expect: `{"prefix_sid":null,"sids.0.flags.b":true,"sids.0.sid":"2001:db8::1","sids.1.sid":"2001:db8::2"}`,
+		},
+		{
+			name:   "message in envelope",
+			json:   `{"schema_version":1,"message":{"router_ip":"192.0.2.1","enrichment":{"region":"eu"}}}`,
+			expect: `{"message.enrichment.region":"eu","message.router_ip":"192.0.2.1","schema_version":1}`,
+		},
+		{
+			name: "invalid json",
+			json: `{"prefix":`,
+			fail: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := Encode(Flat, []byte(tt.json))
+			if err != nil && !tt.fail {
+				t.Fatalf("supposed to succeed but failed with error: %+v", err)
+			}
+			if err == nil && tt.fail {
+				t.Fatalf("supposed to fail but succeeded")
+			}
+			if err != nil {
+				return
+			}
+			if string(b) != tt.expect {
+				t.Errorf("expected %s, got %s", tt.expect, string(b))
+			}
+		})
+	}
+}
@@ -0,0 +1,125 @@
+package codec
+
+import (
+	"encoding/binary"
+	"math"
+)
+
+type msgpackWriter struct {
+	b []byte
+}
+
+// put writes prefix byte followed by v in size bytes big endian
+func (w *msgpackWriter) put(prefix byte, v uint64, size int) {
+	w.b = append(w.b, prefix)
+	w.b = append(w.b, make([]byte, size)...)
+	p := w.b[len(w.b)-size:]
+	switch size {
+	case 1:
+		p[0] = byte(v)
+	case 2:
+		binary.BigEndian.PutUint16(p, uint16(v))
+	case 4:
+		binary.BigEndian.PutUint32(p, uint32(v))
+	case 8:
+		binary.BigEndian.PutUint64(p, v)
+	}
+}
+
+func (w *msgpackWriter) null() {
+	w.b = append(w.b, 0xc0)
+}
+
+func (w *msgpackWriter) boolean(v bool) {
+	if v {
+		w.b = append(w.b, 0xc3)
+		return
+	}
+	w.b = append(w.b, 0xc2)
+}
+
+func (w *msgpackWriter) uint(v uint64) {
+	switch {
+	case v <= 0x7f:
+		// positive fixint
+		w.b = append(w.b, byte(v))
+	case v <= math.MaxUint8:
+		w.put(0xcc, v, 1)
+	case v <= math.MaxUint16:
+		w.put(0xcd, v, 2)
+	case v <= math.MaxUint32:
+		w.put(0xce, v, 4)
+	default:
+		w.put(0xcf, v, 8)
+	}
+}
+
+func (w *msgpackWriter) negative(n uint64) {
+	if n > math.MaxInt64 {
+		// Below the range of int64
+		w.float(-1 - float64(n))
+		return
+	}
+	v := -1 - int64(n)
+	switch {
+	case v >= -32:
+		// negative fixint
+		w.b = append(w.b, byte(v))
+	case v >= math.MinInt8:
+		w.put(0xd0, uint64(v), 1)
+	case v >= math.MinInt16:
+		w.put(0xd1, uint64(v), 2)
+	case v >= math.MinInt32:
+		w.put(0xd2, uint64(v), 4)
+	default:
+		w.put(0xd3, uint64(v), 8)
+	}
+}
+
+func (w *msgpackWriter) float(v float64) {
+	w.put(0xcb, math.Float64bits(v), 8)
+}
+
+func (w *msgpackWriter) str(s string) {
+	l := uint64(len(s))
+	switch {
+	case l < 32:
+		// fixstr
+		w.b = append(w.b, 0xa0|byte(l))
+	case l <= math.MaxUint8:
+		w.put(0xd9, l, 1)
+	case l <= math.MaxUint16:
+		w.put(0xda, l, 2)
+	default:
+		w.put(0xdb, l, 4)
+	}
+	w.b = append(w.b, s...)
+}
+
+func (w *msgpackWriter) array(n int) {
+	switch {
+	case n < 16:
+		// fixarray
+		w.b = append(w.b, 0x90|byte(n))
+	case n <= math.MaxUint16:
+		w.put(0xdc, uint64(n), 2)
+	default:
+		w.put(0xdd, uint64(n), 4)
+	}
+}
+
+func (w *msgpackWriter) object(n int) {
+	switch {
+	case n < 16:
+		// fixmap
+		w.b = append(w.b, 0x80|byte(n))
+	case n <= math.MaxUint16:
+		w.put(0xde, uint64(n), 2)
+	default:
+		w.put(0xdf, uint64(n), 4)
+	}
+}
+
+func (w *msgpackWriter) bytes() []byte {
+	return w.b
+}
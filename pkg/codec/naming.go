@@ -0,0 +1,102 @@
+package codec
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/sbezverk/gobmp/pkg/pub"
+)
+
+const (
+	// SnakeCase is the default naming of message fields, names of JSON tags of messages are used as they are
+	SnakeCase = "snake_case"
+	// CamelCase names message fields in lower camel case, for example base_attrs becomes baseAttrs
+	CamelCase = "camelCase"
+)
+
+type naming struct {
+	publisher pub.Publisher
+}
+
+func (n *naming) PublishMessage(msgType int, msgHash []byte, msg []byte) error {
+	b, err := ToCamelCase(msg)
+	if err != nil {
+		return fmt.Errorf("failed to rename fields of message of type %d with error: %+v", msgType, err)
+	}
+
+	return n.publisher.PublishMessage(msgType, msgHash, b)
+}
+
+func (n *naming) Stop() {
+	n.publisher.Stop()
+}
+
+// NewNamingPublisher returns a Publisher renaming fields of JSON messages to the naming before they
+// are published to publisher, snake_case naming returns publisher as it is.
+func NewNamingPublisher(name string, publisher pub.Publisher) (pub.Publisher, error) {
+	switch name {
+	case SnakeCase, "":
+		return publisher, nil
+	case CamelCase:
+		return &naming{publisher: publisher}, nil
+	}
+
+	return nil, fmt.Errorf("unsupported field naming %s", name)
+}
+
+// ToCamelCase returns JSON message with names of fields of all nested objects converted from snake_case
+// to camelCase, leading underscores of names as _key are kept.
+func ToCamelCase(msg []byte) ([]byte, error) {
+	d := json.NewDecoder(bytes.NewReader(msg))
+	d.UseNumber()
+	var v interface{}
+	if err := d.Decode(&v); err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(rename(v))
+}
+
+func rename(v interface{}) interface{} {
+	switch t := v.(type) {
+	case map[string]interface{}:
+		o := make(map[string]interface{}, len(t))
+		for k, e := range t {
+			o[camelCase(k)] = rename(e)
+		}
+		return o
+	case []interface{}:
+		for i, e := range t {
+			t[i] = rename(e)
+		}
+	}
+
+	return v
+}
+
+// camelCase returns camelCase name of snake_case name
+func camelCase(name string) string {
+	s := strings.TrimLeft(name, "_")
+	if !strings.Contains(s, "_") {
+		return name
+	}
+	var b strings.Builder
+	b.WriteString(name[:len(name)-len(s)])
+	upper := false
+	for _, r := range s {
+		if r == '_' {
+			upper = true
+			continue
+		}
+		if upper {
+			b.WriteString(strings.ToUpper(string(r)))
+			upper = false
+			continue
+		}
+		b.WriteRune(r)
+	}
+
+	return b.String()
+}
@@ -0,0 +1,49 @@
+package codec
+
+import (
+	"testing"
+)
+
+func TestToCamelCase(t *testing.T) {
+	tests := []struct {
+		name   string
+		json   string
+		expect string
+		fail   bool
+	}{
+		{
+			name:   "flat message",
+			json:   `{"_key":"k","action":"add","prefix_len":8,"is_adj_rib_in_post_policy":true,"ipv4_router_id":"192.0.2.1"}`,
+			expect: `{"_key":"k","action":"add","ipv4RouterId":"192.0.2.1","isAdjRibInPostPolicy":true,"prefixLen":8}`,
+		},
+		{
+			name: "nested objects and arrays of objects",
+			json: `{"base_attrs":{"as_path":[65001,4200000000],"ext_community_list":["rt=65000:1"]},` +
+				`"srv6_endx_sid":[{"endpoint_behavior":48}],"message":{"router_ip":"192.0.2.1"}}`,
+			expect: `{"baseAttrs":{"asPath":[65001,4200000000],"extCommunityList":["rt=65000:1"]},` +
+				`"message":{"routerIp":"192.0.2.1"},"srv6EndxSid":[{"endpointBehavior":48}]}`,
+		},
+		{
+			name: "invalid json",
+			json: `{"prefix":`,
+			fail: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := ToCamelCase([]byte(tt.json))
+			if err != nil && !tt.fail {
+				t.Fatalf("supposed to succeed but failed with error: %+v", err)
+			}
+			if err == nil && tt.fail {
+				t.Fatalf("supposed to fail but succeeded")
+			}
+			if err != nil {
+				return
+			}
+			if string(b) != tt.expect {
+				t.Errorf("expected %s, got %s", tt.expect, string(b))
+			}
+		})
+	}
+}
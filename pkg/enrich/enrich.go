@@ -0,0 +1,40 @@
+package enrich
+
+// Message defines key fields of a message about to be published, which are passed to enrichment plugins
+type Message struct {
+	// Type is the type of message, defined in pkg/bmp/consts.go
+	Type      int
+	RouterIP  string
+	PeerIP    string
+	PeerASN   uint32
+	Prefix    string
+	PrefixLen int32
+	OriginAS  uint32
+}
+
+// Enricher defines an interface of enrichment plugin invoked before a message is published,
+// returned fields are added to "enrichment" object of the published message, nil is returned
+// when plugin has nothing to add to the message.
+type Enricher interface {
+	Enrich(msg *Message) map[string]interface{}
+}
+
+// Enrich invokes all enrichers and returns combined fields, when several enrichers return
+// the same field, the value of the last enricher is used.
+func Enrich(enrichers []Enricher, msg *Message) map[string]interface{} {
+	var fields map[string]interface{}
+	for _, e := range enrichers {
+		f := e.Enrich(msg)
+		if len(f) == 0 {
+			continue
+		}
+		if fields == nil {
+			fields = make(map[string]interface{}, len(f))
+		}
+		for k, v := range f {
+			fields[k] = v
+		}
+	}
+
+	return fields
+}
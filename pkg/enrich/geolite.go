@@ -0,0 +1,148 @@
+package enrich
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+const (
+	geoLiteBlocksIPv4 = "GeoLite2-Country-Blocks-IPv4.csv"
+	geoLiteBlocksIPv6 = "GeoLite2-Country-Blocks-IPv6.csv"
+	geoLiteLocations  = "GeoLite2-Country-Locations-en.csv"
+)
+
+type country struct {
+	isoCode string
+	name    string
+}
+
+type geoBlock struct {
+	network *net.IPNet
+	// start is the first address of the network in 16 bytes form
+	start   net.IP
+	country *country
+}
+
+type geoLite struct {
+	blocks []*geoBlock
+}
+
+var _ Enricher = &geoLite{}
+
+// Enrich adds country_iso_code and country_name of the message's prefix
+func (g *geoLite) Enrich(msg *Message) map[string]interface{} {
+	if msg.Prefix == "" {
+		return nil
+	}
+	c := g.lookup(net.ParseIP(msg.Prefix))
+	if c == nil {
+		return nil
+	}
+
+	return map[string]interface{}{
+		"country_iso_code": c.isoCode,
+		"country_name":     c.name,
+	}
+}
+
+func (g *geoLite) lookup(ip net.IP) *country {
+	ip = ip.To16()
+	if ip == nil {
+		return nil
+	}
+	// Blocks do not overlap, the candidate is the last block starting at or before the address
+	i := sort.Search(len(g.blocks), func(i int) bool {
+		return bytes.Compare(g.blocks[i].start, ip) > 0
+	})
+	if i == 0 {
+		return nil
+	}
+	if b := g.blocks[i-1]; b.network.Contains(ip) {
+		return b.country
+	}
+
+	return nil
+}
+
+func readCSV(fn string) ([][]string, error) {
+	f, err := os.Open(fn)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	r := csv.NewReader(f)
+	r.FieldsPerRecord = -1
+	// Skipping header
+	if _, err := r.Read(); err != nil {
+		return nil, fmt.Errorf("failed to read header of %s with error: %+v", fn, err)
+	}
+	recs := make([][]string, 0)
+	for {
+		rec, err := r.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		recs = append(recs, rec)
+	}
+
+	return recs, nil
+}
+
+// NewGeoLite instantiates a reference enrichment plugin annotating messages with the country of the prefix,
+// dir is the directory with MaxMind GeoLite2 Country database in CSV format.
+func NewGeoLite(dir string) (Enricher, error) {
+	locs, err := readCSV(filepath.Join(dir, geoLiteLocations))
+	if err != nil {
+		return nil, err
+	}
+	// geoname_id,locale_code,continent_code,continent_name,country_iso_code,country_name,...
+	countries := make(map[string]*country, len(locs))
+	for _, rec := range locs {
+		if len(rec) < 6 {
+			continue
+		}
+		countries[rec[0]] = &country{isoCode: rec[4], name: rec[5]}
+	}
+	g := &geoLite{
+		blocks: make([]*geoBlock, 0),
+	}
+	for _, fn := range []string{geoLiteBlocksIPv4, geoLiteBlocksIPv6} {
+		blocks, err := readCSV(filepath.Join(dir, fn))
+		if err != nil {
+			return nil, err
+		}
+		// network,geoname_id,registered_country_geoname_id,...
+		for _, rec := range blocks {
+			if len(rec) < 3 {
+				continue
+			}
+			_, n, err := net.ParseCIDR(rec[0])
+			if err != nil {
+				return nil, fmt.Errorf("invalid network %s in %s", rec[0], fn)
+			}
+			id := rec[1]
+			if id == "" {
+				id = rec[2]
+			}
+			c, ok := countries[id]
+			if !ok {
+				continue
+			}
+			g.blocks = append(g.blocks, &geoBlock{network: n, start: n.IP.To16(), country: c})
+		}
+	}
+	sort.Slice(g.blocks, func(i, j int) bool {
+		return bytes.Compare(g.blocks[i].start, g.blocks[j].start) < 0
+	})
+
+	return g, nil
+}
@@ -0,0 +1,67 @@
+package enrich
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestGeoLite(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		geoLiteLocations: "geoname_id,locale_code,continent_code,continent_name,country_iso_code,country_name,is_in_european_union\n" +
+			"2077456,en,OC,Oceania,AU,Australia,0\n" +
+			"6252001,en,NA,\"North America\",US,\"United States\",0\n",
+		geoLiteBlocksIPv4: "network,geoname_id,registered_country_geoname_id,represented_country_geoname_id,is_anonymous_proxy,is_satellite_provider\n" +
+			"1.0.0.0/24,2077456,2077456,,0,0\n" +
+			"8.8.8.0/24,,6252001,,0,0\n",
+		geoLiteBlocksIPv6: "network,geoname_id,registered_country_geoname_id,represented_country_geoname_id,is_anonymous_proxy,is_satellite_provider\n" +
+			"2001:4860::/32,6252001,6252001,,0,0\n",
+	}
+	for fn, c := range files {
+		if err := ioutil.WriteFile(filepath.Join(dir, fn), []byte(c), 0644); err != nil {
+			t.Fatalf("failed to write %s with error: %+v", fn, err)
+		}
+	}
+	g, err := NewGeoLite(dir)
+	if err != nil {
+		t.Fatalf("failed to load GeoLite database with error: %+v", err)
+	}
+	tests := []struct {
+		name   string
+		msg    *Message
+		expect map[string]interface{}
+	}{
+		{
+			name:   "ipv4 prefix",
+			msg:    &Message{Prefix: "1.0.0.0", PrefixLen: 24},
+			expect: map[string]interface{}{"country_iso_code": "AU", "country_name": "Australia"},
+		},
+		{
+			name:   "ipv4 prefix with registered country",
+			msg:    &Message{Prefix: "8.8.8.0", PrefixLen: 24},
+			expect: map[string]interface{}{"country_iso_code": "US", "country_name": "United States"},
+		},
+		{
+			name:   "ipv6 prefix",
+			msg:    &Message{Prefix: "2001:4860:4860::", PrefixLen: 48},
+			expect: map[string]interface{}{"country_iso_code": "US", "country_name": "United States"},
+		},
+		{
+			name: "unknown prefix",
+			msg:  &Message{Prefix: "1.0.1.0", PrefixLen: 24},
+		},
+		{
+			name: "no prefix",
+			msg:  &Message{PeerIP: "1.0.0.1"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Enrich([]Enricher{g}, tt.msg); !reflect.DeepEqual(got, tt.expect) {
+				t.Fatalf("expected fields %+v do not match actual fields %+v", tt.expect, got)
+			}
+		})
+	}
+}
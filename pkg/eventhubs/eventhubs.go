@@ -0,0 +1,262 @@
+package eventhubs
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/sbezverk/gobmp/pkg/bmp"
+	"github.com/sbezverk/gobmp/pkg/pub"
+)
+
+const (
+	// queueLength defines the number of messages buffered for sending, when Event Hubs does not keep up,
+	// messages are dropped.
+	queueLength = 8192
+	// maxBatchLength and maxBatchSize define limits of a single batch of events
+	maxBatchLength = 100
+	maxBatchSize   = 1000 * 1000
+	// flushInterval defines how often a partial batch is sent
+	flushInterval = time.Second
+	// retries defines the number of retries of a failed request, retryBackoff is the wait before
+	// the first retry, it doubles with every retry.
+	retries      = 5
+	retryBackoff = 500 * time.Millisecond
+	// shutdownTimeout defines how long messages published before the stop are sent and retried
+	shutdownTimeout = 10 * time.Second
+	// tokenValidity defines validity of shared access signature of a request
+	tokenValidity = time.Hour
+)
+
+// Config defines the Event Hubs publisher, ConnectionString is the connection string of the event hub
+// or of its namespace, for example Endpoint=sb://ns.servicebus.windows.net/;SharedAccessKeyName=send;
+// SharedAccessKey=...;EntityPath=gobmp, EventHub is the name of the event hub, it is taken from EntityPath
+// when not set.
+type Config struct {
+	ConnectionString string
+	EventHub         string
+}
+
+type event struct {
+	Body             string            `json:"Body"`
+	UserProperties   map[string]string `json:"UserProperties"`
+	BrokerProperties map[string]string `json:"BrokerProperties,omitempty"`
+}
+
+type publisher struct {
+	url      string
+	resource string
+	keyName  string
+	key      string
+	client   *http.Client
+	queue    chan *event
+	stopCh   chan struct{}
+	done     chan struct{}
+	backoff  time.Duration
+	// ctx is cancelled when shutdown timeout expires after the stop, requests and retries are abandoned
+	ctx      context.Context
+	cancel   context.CancelFunc
+	shutdown time.Duration
+}
+
+func (p *publisher) PublishMessage(msgType int, msgHash []byte, msg []byte) error {
+	if len(msg) > maxBatchSize {
+		return fmt.Errorf("message of type %d of %d bytes exceeds maximum event size", msgType, len(msg))
+	}
+	e := &event{
+		Body:           string(msg),
+		UserProperties: map[string]string{"type": bmp.MsgTypeName(msgType)},
+	}
+	if len(msgHash) != 0 {
+		// Events with the same key are stored in the same partition
+		e.BrokerProperties = map[string]string{"PartitionKey": string(msgHash)}
+	}
+	select {
+	case p.queue <- e:
+	default:
+		glog.V(5).Infof("Event Hubs queue is full, dropping message of type %d", msgType)
+	}
+
+	return nil
+}
+
+func (p *publisher) Stop() {
+	close(p.stopCh)
+	t := time.AfterFunc(p.shutdown, p.cancel)
+	<-p.done
+	t.Stop()
+	p.cancel()
+}
+
+func (p *publisher) worker() {
+	defer close(p.done)
+	ticker := time.NewTicker(flushInterval)
+	defer ticker.Stop()
+	batch := make([]*event, 0, maxBatchLength)
+	size := 0
+	add := func(e *event) {
+		if size+len(e.Body) > maxBatchSize {
+			p.send(batch)
+			batch, size = batch[:0], 0
+		}
+		batch, size = append(batch, e), size+len(e.Body)
+		if len(batch) == maxBatchLength {
+			p.send(batch)
+			batch, size = batch[:0], 0
+		}
+	}
+	for {
+		select {
+		case e := <-p.queue:
+			add(e)
+		case <-ticker.C:
+			p.send(batch)
+			batch, size = batch[:0], 0
+		case <-p.stopCh:
+			// Sending messages published before the stop
+			for len(p.queue) != 0 {
+				add(<-p.queue)
+			}
+			p.send(batch)
+			return
+		}
+	}
+}
+
+// send sends the batch retrying failed requests, the batch is dropped when retries are exhausted
+// or shutdown timeout expires after the publisher is stopped.
+func (p *publisher) send(batch []*event) {
+	if len(batch) == 0 {
+		return
+	}
+	b, err := json.Marshal(batch)
+	if err != nil {
+		glog.Errorf("failed to marshal %d messages with error: %+v", len(batch), err)
+		return
+	}
+	backoff := p.backoff
+	for attempt := 0; ; attempt++ {
+		retry, err := p.request(b)
+		if err == nil {
+			return
+		}
+		if !retry || attempt == retries {
+			glog.Errorf("failed to send %d messages to %s with error: %+v", len(batch), p.resource, err)
+			return
+		}
+		select {
+		case <-time.After(backoff):
+		case <-p.ctx.Done():
+			glog.Errorf("gobmp is stopping, failed send of %d messages to %s is not retried", len(batch), p.resource)
+			return
+		}
+		backoff *= 2
+	}
+}
+
+// request sends a single batch of events, retry is true when the request failed and may succeed later
+func (p *publisher) request(b []byte) (bool, error) {
+	req, err := http.NewRequestWithContext(p.ctx, http.MethodPost, p.url, bytes.NewReader(b))
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Content-Type", "application/vnd.microsoft.servicebus.json")
+	req.Header.Set("Authorization", sasToken(p.resource, p.keyName, p.key, time.Now().Add(tokenValidity)))
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return true, err
+	}
+	io.Copy(ioutil.Discard, resp.Body)
+	resp.Body.Close()
+	if resp.StatusCode/100 == 2 {
+		return false, nil
+	}
+	err = fmt.Errorf("Event Hubs returned status: %s", resp.Status)
+
+	return resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode/100 == 5, err
+}
+
+// sasToken returns shared access signature of the resource valid until expiry
+func sasToken(resource, keyName, key string, expiry time.Time) string {
+	uri := url.QueryEscape(resource)
+	se := strconv.FormatInt(expiry.Unix(), 10)
+	mac := hmac.New(sha256.New, []byte(key))
+	mac.Write([]byte(uri + "\n" + se))
+	sig := base64.StdEncoding.EncodeToString(mac.Sum(nil))
+
+	return "SharedAccessSignature sr=" + uri + "&sig=" + url.QueryEscape(sig) + "&se=" + se + "&skn=" + keyName
+}
+
+// parseConnectionString returns key value pairs of the connection string, keys are case insensitive
+func parseConnectionString(s string) map[string]string {
+	kv := make(map[string]string)
+	for _, part := range strings.Split(s, ";") {
+		i := strings.Index(part, "=")
+		if i < 0 {
+			continue
+		}
+		kv[strings.ToLower(strings.TrimSpace(part[:i]))] = strings.TrimSpace(part[i+1:])
+	}
+
+	return kv
+}
+
+// NewPublisher returns a Publisher sending messages as events to the event hub, every event carries
+// the type of its message in "type" property, events are partitioned by keys of their messages.
+func NewPublisher(config *Config) (pub.Publisher, error) {
+	kv := parseConnectionString(config.ConnectionString)
+	endpoint, keyName, key := kv["endpoint"], kv["sharedaccesskeyname"], kv["sharedaccesskey"]
+	if endpoint == "" || keyName == "" || key == "" {
+		return nil, fmt.Errorf("Event Hubs connection string requires Endpoint, SharedAccessKeyName and SharedAccessKey")
+	}
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("invalid Event Hubs endpoint %s with error: %+v", endpoint, err)
+	}
+	switch u.Scheme {
+	case "sb":
+		u.Scheme = "https"
+	case "http", "https":
+	default:
+		return nil, fmt.Errorf("invalid Event Hubs endpoint %s, only sb, http and https endpoints are supported", endpoint)
+	}
+	hub := config.EventHub
+	if hub == "" {
+		hub = kv["entitypath"]
+	}
+	if hub == "" {
+		return nil, fmt.Errorf("Event Hubs event hub is not set and connection string does not carry EntityPath")
+	}
+	u.Path = "/" + hub
+	resource := u.String()
+	u.Path += "/messages"
+	u.RawQuery = "api-version=2014-01"
+	p := &publisher{
+		url:      u.String(),
+		resource: resource,
+		keyName:  keyName,
+		key:      key,
+		client:   &http.Client{Timeout: 10 * time.Second},
+		queue:    make(chan *event, queueLength),
+		stopCh:   make(chan struct{}),
+		done:     make(chan struct{}),
+		backoff:  retryBackoff,
+		shutdown: shutdownTimeout,
+	}
+	p.ctx, p.cancel = context.WithCancel(context.Background())
+	go p.worker()
+
+	return p, nil
+}
@@ -0,0 +1,157 @@
+package eventhubs
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/sbezverk/gobmp/pkg/bmp"
+)
+
+// verify returns true when the shared access signature of the request is signed by the key
+func verify(auth, key string) bool {
+	v, err := url.ParseQuery(strings.TrimPrefix(auth, "SharedAccessSignature "))
+	if err != nil {
+		return false
+	}
+	mac := hmac.New(sha256.New, []byte(key))
+	mac.Write([]byte(url.QueryEscape(v.Get("sr")) + "\n" + v.Get("se")))
+
+	return v.Get("skn") == "send" && v.Get("sig") == base64.StdEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func TestPublisher(t *testing.T) {
+	var mtx sync.Mutex
+	var batches [][]*event
+	statuses := []int{http.StatusServiceUnavailable}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.URL.Path != "/gobmp/messages" || !verify(req.Header.Get("Authorization"), "secret") {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		mtx.Lock()
+		defer mtx.Unlock()
+		if len(statuses) != 0 {
+			w.WriteHeader(statuses[0])
+			statuses = statuses[1:]
+			return
+		}
+		b, _ := ioutil.ReadAll(req.Body)
+		var batch []*event
+		json.Unmarshal(b, &batch)
+		batches = append(batches, batch)
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer srv.Close()
+	p, err := NewPublisher(&Config{
+		ConnectionString: "Endpoint=" + srv.URL + "/;SharedAccessKeyName=send;SharedAccessKey=secret;EntityPath=gobmp",
+	})
+	if err != nil {
+		t.Fatalf("failed to create Event Hubs publisher with error: %+v", err)
+	}
+	p.(*publisher).backoff = 0
+	if err := p.PublishMessage(bmp.LSNodeMsg, []byte("key"), []byte(`{"name":"r1"}`)); err != nil {
+		t.Fatalf("failed to publish message with error: %+v", err)
+	}
+	if err := p.PublishMessage(bmp.LSNodeMsg, nil, []byte(`{"name":"r2"}`)); err != nil {
+		t.Fatalf("failed to publish message with error: %+v", err)
+	}
+	// Stop sends the partial batch, the first request fails and is retried
+	p.Stop()
+	if len(batches) != 1 || len(batches[0]) != 2 {
+		t.Fatalf("expected a single batch of 2 events, got %d batches", len(batches))
+	}
+	e := batches[0][0]
+	if e.Body != `{"name":"r1"}` || e.UserProperties["type"] != "ls_node" || e.BrokerProperties["PartitionKey"] != "key" {
+		t.Errorf("unexpected event %+v", e)
+	}
+	if batches[0][1].BrokerProperties != nil {
+		t.Errorf("expected event without partition key, got %+v", batches[0][1])
+	}
+}
+
+func TestPublisherShutdownTimeout(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+	p, err := NewPublisher(&Config{
+		ConnectionString: "Endpoint=" + srv.URL + "/;SharedAccessKeyName=send;SharedAccessKey=secret;EntityPath=gobmp",
+	})
+	if err != nil {
+		t.Fatalf("failed to create Event Hubs publisher with error: %+v", err)
+	}
+	p.(*publisher).backoff = time.Hour
+	p.(*publisher).shutdown = 10 * time.Millisecond
+	if err := p.PublishMessage(bmp.LSNodeMsg, nil, []byte(`{"name":"r1"}`)); err != nil {
+		t.Fatalf("failed to publish message with error: %+v", err)
+	}
+	// Retries of the failed request are abandoned when shutdown timeout expires
+	stopped := make(chan struct{})
+	go func() {
+		p.Stop()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("publisher did not stop after shutdown timeout")
+	}
+}
+
+func TestNewPublisher(t *testing.T) {
+	tests := []struct {
+		name   string
+		config *Config
+		url    string
+		fail   bool
+	}{
+		{
+			name:   "event hub of connection string",
+			config: &Config{ConnectionString: "Endpoint=sb://ns.servicebus.windows.net/;SharedAccessKeyName=send;SharedAccessKey=a2V5=;EntityPath=gobmp"},
+			url:    "https://ns.servicebus.windows.net/gobmp/messages?api-version=2014-01",
+		},
+		{
+			name:   "event hub of config",
+			config: &Config{ConnectionString: "Endpoint=sb://ns.servicebus.windows.net/;SharedAccessKeyName=send;SharedAccessKey=a2V5=", EventHub: "bmp"},
+			url:    "https://ns.servicebus.windows.net/bmp/messages?api-version=2014-01",
+		},
+		{
+			name:   "missing event hub",
+			config: &Config{ConnectionString: "Endpoint=sb://ns.servicebus.windows.net/;SharedAccessKeyName=send;SharedAccessKey=a2V5="},
+			fail:   true,
+		},
+		{
+			name:   "missing key",
+			config: &Config{ConnectionString: "Endpoint=sb://ns.servicebus.windows.net/;SharedAccessKeyName=send;EntityPath=gobmp"},
+			fail:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := NewPublisher(tt.config)
+			if err != nil && !tt.fail {
+				t.Fatalf("supposed to succeed but failed with error: %+v", err)
+			}
+			if err == nil && tt.fail {
+				t.Fatalf("supposed to fail but succeeded")
+			}
+			if err != nil {
+				return
+			}
+			defer p.Stop()
+			if u := p.(*publisher).url; u != tt.url {
+				t.Errorf("expected url %s, got %s", tt.url, u)
+			}
+		})
+	}
+}
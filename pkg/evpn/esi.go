@@ -0,0 +1,92 @@
+package evpn
+
+import (
+	"encoding/binary"
+	"fmt"
+	"net"
+
+	"github.com/sbezverk/gobmp/pkg/addr"
+)
+
+// ESI types https://tools.ietf.org/html/rfc7432#section-5
+const (
+	// ESIArbitrary is ESI Type 0, arbitrary 9 octets value configured by the operator
+	ESIArbitrary = 0
+	// ESILACP is ESI Type 1, auto-generated from CE's LACP System MAC and Port Key
+	ESILACP = 1
+	// ESIBridge is ESI Type 2, auto-generated from Root Bridge MAC and Priority of the bridged LAN
+	ESIBridge = 2
+	// ESIMAC is ESI Type 3, auto-generated from System MAC and Local Discriminator
+	ESIMAC = 3
+	// ESIRouterID is ESI Type 4, auto-generated from Router ID and Local Discriminator
+	ESIRouterID = 4
+	// ESIAS is ESI Type 5, auto-generated from AS number and Local Discriminator
+	ESIAS = 5
+)
+
+// ESIFields defines fields of Ethernet Segment Identifier decoded according to its type, only fields
+// of the type are set.
+type ESIFields struct {
+	Type uint8 `json:"type"`
+	// Value of Type 0 ESI as hex string
+	Value string `json:"value,omitempty"`
+	// CE LACP System MAC and Port Key of Type 1 ESI
+	SystemMAC string `json:"system_mac,omitempty"`
+	PortKey   uint16 `json:"port_key,omitempty"`
+	// Root Bridge MAC and Priority of Type 2 ESI
+	RootBridgeMAC      string `json:"root_bridge_mac,omitempty"`
+	RootBridgePriority uint16 `json:"root_bridge_priority,omitempty"`
+	// Router ID of Type 4 ESI
+	RouterID string `json:"router_id,omitempty"`
+	// AS number of Type 5 ESI
+	ASN uint32 `json:"asn,omitempty"`
+	// Local Discriminator of Type 3, 4 and 5 ESIs, System MAC of Type 3 ESI is in SystemMAC
+	LocalDiscriminator uint32 `json:"local_discriminator,omitempty"`
+}
+
+// IsZero returns true when ESI is 0, the route is not associated with an Ethernet Segment
+func (esi *ESI) IsZero() bool {
+	return *esi == ESI{}
+}
+
+// IsMax returns true when ESI is MAX-ESI, all octets set to 0xff
+func (esi *ESI) IsMax() bool {
+	for _, b := range esi {
+		if b != 0xff {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Fields returns fields of ESI decoded according to its type, ESI of unknown types and MAX-ESI carry only
+// the type.
+func (esi *ESI) Fields() *ESIFields {
+	f := &ESIFields{Type: esi[0]}
+	if esi.IsMax() {
+		return f
+	}
+	v := esi[1:]
+	switch f.Type {
+	case ESIArbitrary:
+		f.Value = fmt.Sprintf("%x", v)
+	case ESILACP:
+		f.SystemMAC = net.HardwareAddr(v[0:6]).String()
+		f.PortKey = binary.BigEndian.Uint16(v[6:8])
+	case ESIBridge:
+		f.RootBridgeMAC = net.HardwareAddr(v[0:6]).String()
+		f.RootBridgePriority = binary.BigEndian.Uint16(v[6:8])
+	case ESIMAC:
+		f.SystemMAC = net.HardwareAddr(v[0:6]).String()
+		f.LocalDiscriminator = uint32(v[6])<<16 | uint32(v[7])<<8 | uint32(v[8])
+	case ESIRouterID:
+		f.RouterID = addr.String(v[0:4])
+		f.LocalDiscriminator = binary.BigEndian.Uint32(v[4:8])
+	case ESIAS:
+		f.ASN = binary.BigEndian.Uint32(v[0:4])
+		f.LocalDiscriminator = binary.BigEndian.Uint32(v[4:8])
+	}
+
+	return f
+}
@@ -0,0 +1,71 @@
+package evpn
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestESIFields(t *testing.T) {
+	tests := []struct {
+		name   string
+		input  []byte
+		zero   bool
+		expect *ESIFields
+	}{
+		{
+			name:   "zero",
+			input:  []byte{0, 0, 0, 0, 0, 0, 0, 0, 0, 0},
+			zero:   true,
+			expect: &ESIFields{Type: ESIArbitrary, Value: "000000000000000000"},
+		},
+		{
+			name:   "arbitrary",
+			input:  []byte{0, 1, 2, 3, 4, 5, 6, 7, 8, 9},
+			expect: &ESIFields{Type: ESIArbitrary, Value: "010203040506070809"},
+		},
+		{
+			name:   "lacp",
+			input:  []byte{1, 0x00, 0x11, 0x22, 0x33, 0x44, 0x55, 0x80, 0x00, 0},
+			expect: &ESIFields{Type: ESILACP, SystemMAC: "00:11:22:33:44:55", PortKey: 32768},
+		},
+		{
+			name:   "bridge",
+			input:  []byte{2, 0x00, 0x11, 0x22, 0x33, 0x44, 0x55, 0x10, 0x00, 0},
+			expect: &ESIFields{Type: ESIBridge, RootBridgeMAC: "00:11:22:33:44:55", RootBridgePriority: 4096},
+		},
+		{
+			name:   "mac",
+			input:  []byte{3, 0x00, 0x81, 0xc4, 0xbc, 0x77, 0x8a, 0x01, 0x00, 0x02},
+			expect: &ESIFields{Type: ESIMAC, SystemMAC: "00:81:c4:bc:77:8a", LocalDiscriminator: 65538},
+		},
+		{
+			name:   "router id",
+			input:  []byte{4, 192, 0, 2, 1, 0, 0, 0, 7, 0},
+			expect: &ESIFields{Type: ESIRouterID, RouterID: "192.0.2.1", LocalDiscriminator: 7},
+		},
+		{
+			name:   "as",
+			input:  []byte{5, 0xfa, 0x56, 0xea, 0x00, 0, 0, 1, 0, 0},
+			expect: &ESIFields{Type: ESIAS, ASN: 4200000000, LocalDiscriminator: 256},
+		},
+		{
+			name:   "max",
+			input:  []byte{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff},
+			expect: &ESIFields{Type: 0xff},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			esi, err := MakeESI(tt.input)
+			if err != nil {
+				t.Fatalf("failed to make ESI with error: %+v", err)
+			}
+			if esi.IsZero() != tt.zero {
+				t.Errorf("expected zero ESI %t, got %t", tt.zero, esi.IsZero())
+			}
+			if got := esi.Fields(); !reflect.DeepEqual(got, tt.expect) {
+				t.Errorf("expected ESI fields %+v, got %+v", tt.expect, got)
+			}
+		})
+	}
+}
@@ -1,21 +1,131 @@
 package filer
 
 import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
 	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
 	"os"
+	"sync"
 
+	"github.com/klauspost/compress/zstd"
 	"github.com/sbezverk/gobmp/pkg/pub"
 )
 
-// MsgOut defines structure of the message stored in the file.
+const (
+	// CompressionNone stores messages as they are
+	CompressionNone = "none"
+	// CompressionGzip compresses messages with gzip
+	CompressionGzip = "gzip"
+	// CompressionZstd compresses messages with zstd
+	CompressionZstd = "zstd"
+)
+
+var (
+	// zstdEncoder and zstdDecoder are shared by all messages, their EncodeAll and DecodeAll are safe
+	// for concurrent use.
+	zstdOnce    sync.Once
+	zstdEncoder *zstd.Encoder
+	zstdDecoder *zstd.Decoder
+	zstdErr     error
+)
+
+func initZstd() error {
+	zstdOnce.Do(func() {
+		if zstdEncoder, zstdErr = zstd.NewWriter(nil); zstdErr != nil {
+			return
+		}
+		zstdDecoder, zstdErr = zstd.NewReader(nil)
+	})
+
+	return zstdErr
+}
+
+// Compress returns b compressed by the compression, CompressionGzip or CompressionZstd
+func Compress(compression string, b []byte) ([]byte, error) {
+	switch compression {
+	case CompressionGzip:
+		var buf bytes.Buffer
+		w := gzip.NewWriter(&buf)
+		if _, err := w.Write(b); err != nil {
+			return nil, err
+		}
+		if err := w.Close(); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	case CompressionZstd:
+		if err := initZstd(); err != nil {
+			return nil, err
+		}
+		return zstdEncoder.EncodeAll(b, nil), nil
+	}
+
+	return nil, fmt.Errorf("unsupported compression %s", compression)
+}
+
+// MsgOut defines structure of the message stored in the file, Encoding is set when Value is compressed.
 type MsgOut struct {
-	Type  int    `json:"type,omitempty"`
-	Key   []byte `json:"key,omitempty"`
-	Value []byte `json:"value,omitempty"`
+	Type     int    `json:"type,omitempty"`
+	Key      []byte `json:"key,omitempty"`
+	Value    []byte `json:"value,omitempty"`
+	Encoding string `json:"encoding,omitempty"`
+}
+
+// Payload returns Value of the message decompressed according to its Encoding
+func (m *MsgOut) Payload() ([]byte, error) {
+	switch m.Encoding {
+	case "":
+		return m.Value, nil
+	case CompressionGzip:
+		r, err := gzip.NewReader(bytes.NewReader(m.Value))
+		if err != nil {
+			return nil, err
+		}
+		defer r.Close()
+		return ioutil.ReadAll(r)
+	case CompressionZstd:
+		if err := initZstd(); err != nil {
+			return nil, err
+		}
+		return zstdDecoder.DecodeAll(m.Value, nil)
+	}
+
+	return nil, fmt.Errorf("unsupported encoding %s", m.Encoding)
+}
+
+// Config defines compression of messages stored in the file. When BatchSize is 0, Value of every message
+// is compressed and its Encoding is set, otherwise the file is a sequence of gzip members or zstd frames of
+// BatchSize messages, a member is completed when it reaches BatchSize, when the file is reopened and on stop.
+type Config struct {
+	Compression string
+	BatchSize   int
+}
+
+// Reopener defines method of the publisher to reopen its file, it is used after the file was moved
+// away by log rotation.
+type Reopener interface {
+	Reopen() error
 }
 
 type pubfiler struct {
-	file *os.File
+	sync.Mutex
+	name        string
+	file        *os.File
+	compression string
+	batchSize   int
+	// zw compresses the current member or frame of the batch compressed file, batched counts its messages
+	zw      compressor
+	batched int
+}
+
+// compressor defines methods shared by gzip and zstd writers
+type compressor interface {
+	io.WriteCloser
+	Reset(w io.Writer)
 }
 
 func (p *pubfiler) PublishMessage(msgType int, msgHash []byte, msg []byte) error {
@@ -24,32 +134,135 @@ func (p *pubfiler) PublishMessage(msgType int, msgHash []byte, msg []byte) error
 		Key:   msgHash,
 		Value: msg,
 	}
+	if p.compression != CompressionNone && p.zw == nil {
+		v, err := Compress(p.compression, msg)
+		if err != nil {
+			return err
+		}
+		m.Value = v
+		m.Encoding = p.compression
+	}
 	b, err := json.Marshal(&m)
 	if err != nil {
 		return err
 	}
 	b = append(b, '\n')
-	_, err = p.file.Write(b)
+	p.Lock()
+	defer p.Unlock()
+	if p.zw == nil {
+		_, err = p.file.Write(b)
+		return err
+	}
+	if _, err := p.zw.Write(b); err != nil {
+		return err
+	}
+	p.batched++
+	if p.batched < p.batchSize {
+		return nil
+	}
+
+	return p.completeBatch()
+}
+
+// completeBatch completes the current compressed member or frame of the file, the next message starts a new member
+func (p *pubfiler) completeBatch() error {
+	if p.batched == 0 {
+		return nil
+	}
+	err := p.zw.Close()
+	p.zw.Reset(p.file)
+	p.batched = 0
+
+	return err
+}
+
+// Reopen closes the file and opens the file with the same name, new messages are appended to the file
+// when it still exists.
+func (p *pubfiler) Reopen() error {
+	f, err := os.OpenFile(p.name, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0644)
 	if err != nil {
 		return err
 	}
+	p.Lock()
+	defer p.Unlock()
+	if p.zw != nil {
+		if err := p.completeBatch(); err != nil {
+			f.Close()
+			return err
+		}
+		p.zw.Reset(f)
+	}
+	p.file.Close()
+	p.file = f
 
 	return nil
 }
 
 func (p *pubfiler) Stop() {
+	p.Lock()
+	defer p.Unlock()
+	if p.zw != nil {
+		p.completeBatch()
+	}
 	p.file.Close()
 }
 
 // NewFiler returns a new instance of message filer
 func NewFiler(file string) (pub.Publisher, error) {
+	return NewFilerWithConfig(file, nil)
+}
+
+// NewFilerWithConfig returns a new instance of message filer compressing messages according to the config,
+// nil config stores messages as they are.
+func NewFilerWithConfig(file string, config *Config) (pub.Publisher, error) {
+	pw := pubfiler{
+		name:        file,
+		compression: CompressionNone,
+	}
+	if config != nil {
+		switch config.Compression {
+		case CompressionNone, "":
+		case CompressionGzip, CompressionZstd:
+			pw.compression = config.Compression
+		default:
+			return nil, fmt.Errorf("unsupported compression %s", config.Compression)
+		}
+		if config.BatchSize < 0 {
+			return nil, fmt.Errorf("invalid batch size %d", config.BatchSize)
+		}
+		pw.batchSize = config.BatchSize
+	}
 	f, err := os.Create(file)
 	if err != nil {
 		return nil, err
 	}
-	pw := pubfiler{
-		file: f,
+	pw.file = f
+	if pw.batchSize > 0 {
+		switch pw.compression {
+		case CompressionGzip:
+			pw.zw = gzip.NewWriter(f)
+		case CompressionZstd:
+			if pw.zw, err = zstd.NewWriter(f); err != nil {
+				f.Close()
+				return nil, err
+			}
+		}
 	}
 
 	return &pw, nil
 }
+
+// NewReader returns a reader of messages of the file, batch compressed file is decompressed
+func NewReader(r io.Reader) (io.Reader, error) {
+	br := bufio.NewReader(r)
+	if magic, err := br.Peek(4); err == nil && bytes.Equal(magic, []byte{0x28, 0xb5, 0x2f, 0xfd}) {
+		return zstd.NewReader(br)
+	}
+	magic, err := br.Peek(2)
+	if err != nil || magic[0] != 0x1f || magic[1] != 0x8b {
+		// Empty, short or not compressed file
+		return br, nil
+	}
+
+	return gzip.NewReader(br)
+}
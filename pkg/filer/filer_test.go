@@ -0,0 +1,87 @@
+package filer
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestCompression(t *testing.T) {
+	dir, err := ioutil.TempDir("", "filer")
+	if err != nil {
+		t.Fatalf("failed to create directory with error: %+v", err)
+	}
+	defer os.RemoveAll(dir)
+	tests := []struct {
+		name     string
+		config   *Config
+		encoding string
+	}{
+		{name: "not compressed", config: nil},
+		{name: "compressed messages", config: &Config{Compression: CompressionGzip}, encoding: CompressionGzip},
+		{name: "compressed batches", config: &Config{Compression: CompressionGzip, BatchSize: 2}},
+		{name: "zstd compressed messages", config: &Config{Compression: CompressionZstd}, encoding: CompressionZstd},
+		{name: "zstd compressed batches", config: &Config{Compression: CompressionZstd, BatchSize: 2}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			name := filepath.Join(dir, tt.name)
+			p, err := NewFilerWithConfig(name, tt.config)
+			if err != nil {
+				t.Fatalf("failed to create filer with error: %+v", err)
+			}
+			for i := 0; i < 3; i++ {
+				if err := p.PublishMessage(i, []byte("key"), []byte(fmt.Sprintf(`{"sequence":%d}`, i))); err != nil {
+					t.Fatalf("failed to publish message with error: %+v", err)
+				}
+				// Completing the batch of the first two messages in the file before reopening
+				if i == 1 {
+					if err := p.(Reopener).Reopen(); err != nil {
+						t.Fatalf("failed to reopen file with error: %+v", err)
+					}
+				}
+			}
+			p.Stop()
+			f, err := os.Open(name)
+			if err != nil {
+				t.Fatalf("failed to open file with error: %+v", err)
+			}
+			defer f.Close()
+			r, err := NewReader(f)
+			if err != nil {
+				t.Fatalf("failed to read file with error: %+v", err)
+			}
+			s := bufio.NewScanner(r)
+			i := 0
+			for ; s.Scan(); i++ {
+				m := &MsgOut{}
+				if err := json.Unmarshal(s.Bytes(), m); err != nil {
+					t.Fatalf("failed to unmarshal message with error: %+v", err)
+				}
+				if m.Encoding != tt.encoding {
+					t.Errorf("expected encoding %q, got %q", tt.encoding, m.Encoding)
+				}
+				b, err := m.Payload()
+				if err != nil {
+					t.Fatalf("failed to get payload with error: %+v", err)
+				}
+				if m.Type != i || string(b) != fmt.Sprintf(`{"sequence":%d}`, i) {
+					t.Errorf("unexpected message %d of type %d: %s", i, m.Type, string(b))
+				}
+			}
+			if err := s.Err(); err != nil {
+				t.Fatalf("failed to read file with error: %+v", err)
+			}
+			if i != 3 {
+				t.Errorf("expected 3 messages, got %d", i)
+			}
+		})
+	}
+	if _, err := NewFilerWithConfig(filepath.Join(dir, "lz4"), &Config{Compression: "lz4"}); err == nil {
+		t.Errorf("expected unsupported compression to fail")
+	}
+}
@@ -0,0 +1,143 @@
+package fixture
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+const (
+	// HexExt is the extension of fixture files with hex encoded BMP messages
+	HexExt = ".hex"
+	// BMPExt is the extension of fixture files with raw BMP messages, as written by --capture-dir
+	BMPExt = ".bmp"
+	// GoldenExt is the extension of golden files with expected output of fixtures
+	GoldenExt = ".golden.json"
+)
+
+// Fixture defines a stream of BMP messages loaded from a fixture file
+type Fixture struct {
+	// Name is the name of fixture file without extension
+	Name string
+	// Data carries BMP messages of the fixture as received from a router
+	Data []byte
+	// Golden is the path of golden file of the fixture
+	Golden string
+}
+
+// Load returns fixtures found in dir sorted by name. Files with .hex extension carry hex encoded BMP
+// messages, whitespaces, commas and "0x" prefixes are ignored and "#" starts a comment till the end of
+// the line, so messages can be pasted from logs or Go byte slices. Files with .bmp extension carry raw
+// BMP messages.
+func Load(dir string) ([]*Fixture, error) {
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	fixtures := make([]*Fixture, 0)
+	for _, f := range files {
+		ext := filepath.Ext(f.Name())
+		if f.IsDir() || (ext != HexExt && ext != BMPExt) {
+			continue
+		}
+		b, err := ioutil.ReadFile(filepath.Join(dir, f.Name()))
+		if err != nil {
+			return nil, err
+		}
+		if ext == HexExt {
+			if b, err = DecodeHex(b); err != nil {
+				return nil, fmt.Errorf("failed to decode fixture %s with error: %+v", f.Name(), err)
+			}
+		}
+		name := strings.TrimSuffix(f.Name(), ext)
+		fixtures = append(fixtures, &Fixture{
+			Name:   name,
+			Data:   b,
+			Golden: filepath.Join(dir, name+GoldenExt),
+		})
+	}
+	sort.Slice(fixtures, func(i, j int) bool {
+		return fixtures[i].Name < fixtures[j].Name
+	})
+
+	return fixtures, nil
+}
+
+// DecodeHex returns bytes of hex encoded fixture
+func DecodeHex(b []byte) ([]byte, error) {
+	var s strings.Builder
+	for _, line := range strings.Split(string(b), "\n") {
+		if i := strings.Index(line, "#"); i != -1 {
+			line = line[:i]
+		}
+		line = strings.Replace(line, "[]byte", "", -1)
+		for _, f := range strings.FieldsFunc(line, func(r rune) bool {
+			return r == ' ' || r == '\t' || r == '\r' || r == ',' || r == '[' || r == ']' || r == '{' || r == '}'
+		}) {
+			if strings.HasPrefix(f, "0x") || strings.HasPrefix(f, "0X") {
+				f = f[2:]
+				if len(f) == 1 {
+					// Single digit byte, as in 0x0
+					f = "0" + f
+				}
+			}
+			s.WriteString(f)
+		}
+	}
+
+	return hex.DecodeString(s.String())
+}
+
+// Compare compares JSON encoding of actual with the golden file, objects' keys are sorted and the output
+// is indented, so golden files are stable and readable. When update is true, the golden file is written
+// with actual instead.
+func Compare(golden string, actual interface{}, update bool) error {
+	b, err := json.Marshal(actual)
+	if err != nil {
+		return err
+	}
+	// Round trip through generic values sorts keys of objects
+	var v interface{}
+	if err := json.Unmarshal(b, &v); err != nil {
+		return err
+	}
+	if b, err = json.MarshalIndent(v, "", "  "); err != nil {
+		return err
+	}
+	b = append(b, '\n')
+	if update {
+		return ioutil.WriteFile(golden, b, 0644)
+	}
+	expect, err := ioutil.ReadFile(golden)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return fmt.Errorf("golden file %s does not exist, run tests with -update to create it", golden)
+		}
+		return err
+	}
+	if bytes.Equal(expect, b) {
+		return nil
+	}
+	el := strings.Split(string(expect), "\n")
+	al := strings.Split(string(b), "\n")
+	for i := 0; i < len(el) || i < len(al); i++ {
+		var e, a string
+		if i < len(el) {
+			e = el[i]
+		}
+		if i < len(al) {
+			a = al[i]
+		}
+		if e != a {
+			return fmt.Errorf("output does not match golden file %s at line %d, expected: %q, actual: %q", golden, i+1, e, a)
+		}
+	}
+
+	return nil
+}
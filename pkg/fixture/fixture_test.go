@@ -0,0 +1,105 @@
+package fixture
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestDecodeHex(t *testing.T) {
+	tests := []struct {
+		name   string
+		input  string
+		expect []byte
+		fail   bool
+	}{
+		{
+			name:   "hex dump with comments",
+			input:  "# Initiation\n03 00 00 00 06 04 # common header\n\nff\n",
+			expect: []byte{3, 0, 0, 0, 6, 4, 0xff},
+		},
+		{
+			name:   "go byte slice",
+			input:  "[]byte{0x03, 0x0, 0xFF,\n\t0x4}",
+			expect: []byte{3, 0, 0xff, 4},
+		},
+		{
+			name:   "continuous hex string",
+			input:  "030000000604",
+			expect: []byte{3, 0, 0, 0, 6, 4},
+		},
+		{
+			name:  "odd number of digits",
+			input: "03 0",
+			fail:  true,
+		},
+		{
+			name:  "invalid digit",
+			input: "0g",
+			fail:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := DecodeHex([]byte(tt.input))
+			if err != nil {
+				if !tt.fail {
+					t.Fatalf("supposed to succeed but failed with error: %+v", err)
+				}
+				return
+			}
+			if tt.fail {
+				t.Fatalf("supposed to fail but succeeded")
+			}
+			if !reflect.DeepEqual(tt.expect, got) {
+				t.Fatalf("expected %v does not match actual %v", tt.expect, got)
+			}
+		})
+	}
+}
+
+func TestLoadAndCompare(t *testing.T) {
+	dir := t.TempDir()
+	if err := ioutil.WriteFile(filepath.Join(dir, "b.hex"), []byte("# fixture b\n01 02"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(dir, "a.bmp"), []byte{3, 4}, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(dir, "notes.txt"), []byte("not a fixture"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	fixtures, err := Load(dir)
+	if err != nil {
+		t.Fatalf("failed to load fixtures with error: %+v", err)
+	}
+	expect := []*Fixture{
+		{Name: "a", Data: []byte{3, 4}, Golden: filepath.Join(dir, "a.golden.json")},
+		{Name: "b", Data: []byte{1, 2}, Golden: filepath.Join(dir, "b.golden.json")},
+	}
+	if !reflect.DeepEqual(expect, fixtures) {
+		t.Fatalf("expected fixtures %+v do not match loaded %+v", expect, fixtures)
+	}
+	actual := map[string]interface{}{"b": 1, "a": []string{"x"}}
+	if err := Compare(fixtures[0].Golden, actual, false); err == nil {
+		t.Fatalf("comparison with missing golden file supposed to fail but succeeded")
+	}
+	if err := Compare(fixtures[0].Golden, actual, true); err != nil {
+		t.Fatalf("failed to update golden file with error: %+v", err)
+	}
+	b, err := ioutil.ReadFile(fixtures[0].Golden)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if golden := "{\n  \"a\": [\n    \"x\"\n  ],\n  \"b\": 1\n}\n"; string(b) != golden {
+		t.Fatalf("expected golden file %q does not match written %q", golden, string(b))
+	}
+	if err := Compare(fixtures[0].Golden, actual, false); err != nil {
+		t.Fatalf("comparison supposed to succeed but failed with error: %+v", err)
+	}
+	actual["b"] = 2
+	if err := Compare(fixtures[0].Golden, actual, false); err == nil {
+		t.Fatalf("comparison of changed output supposed to fail but succeeded")
+	}
+}
@@ -0,0 +1,218 @@
+package flap
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/sbezverk/gobmp/pkg/bmp"
+	"github.com/sbezverk/gobmp/pkg/pub"
+)
+
+// Detector defines a Publisher consuming unicast and l3vpn prefix messages and publishing prefix_flap
+// events for prefixes changing too often.
+type Detector interface {
+	pub.Publisher
+}
+
+// Peer defines a peer involved in flapping of a prefix with the number of changes it has reported
+type Peer struct {
+	RouterIP string `json:"router_ip"`
+	PeerIP   string `json:"peer_ip"`
+	Count    int    `json:"count"`
+}
+
+// Event defines prefix_flap event, Count is the number of changes of the prefix within the window
+type Event struct {
+	Timestamp string  `json:"timestamp"`
+	Prefix    string  `json:"prefix"`
+	PrefixLen int32   `json:"prefix_len"`
+	VPNRD     string  `json:"vpn_rd,omitempty"`
+	Count     int     `json:"count"`
+	Window    int64   `json:"window_sec"`
+	Peers     []*Peer `json:"peers"`
+}
+
+type change struct {
+	timestamp time.Time
+	routerIP  string
+	peerIP    string
+}
+
+type prefixState struct {
+	// lastSeen keeps the time of the last update of the prefix from each router and peer
+	lastSeen map[string]time.Time
+	changes  []*change
+	reported time.Time
+}
+
+type detector struct {
+	sync.Mutex
+	window    time.Duration
+	threshold int
+	events    pub.Publisher
+	prefixes  map[string]*prefixState
+	now       func() time.Time
+	stop      chan struct{}
+}
+
+var _ Detector = &detector{}
+
+// prefix defines fields of prefix messages used by the detector
+type prefix struct {
+	Action    string `json:"action"`
+	RouterIP  string `json:"router_ip"`
+	PeerIP    string `json:"peer_ip"`
+	Prefix    string `json:"prefix"`
+	PrefixLen int32  `json:"prefix_len"`
+	VPNRD     string `json:"vpn_rd"`
+}
+
+func (d *detector) PublishMessage(msgType int, msgHash []byte, msg []byte) error {
+	switch msgType {
+	case bmp.UnicastPrefixMsg, bmp.UnicastPrefixV4Msg, bmp.UnicastPrefixV6Msg:
+	case bmp.L3VPNMsg, bmp.L3VPNV4Msg, bmp.L3VPNV6Msg:
+	default:
+		return nil
+	}
+	p := &prefix{}
+	if err := json.Unmarshal(msg, p); err != nil {
+		return fmt.Errorf("failed to unmarshal prefix message with error: %+v", err)
+	}
+	if p.Prefix == "" {
+		return nil
+	}
+	if e := d.process(p); e != nil {
+		b, err := json.Marshal(e)
+		if err != nil {
+			return fmt.Errorf("failed to marshal prefix flap event with error: %+v", err)
+		}
+		return d.events.PublishMessage(bmp.PrefixFlapMsg, []byte(key(p)), b)
+	}
+
+	return nil
+}
+
+func (d *detector) Stop() {
+	close(d.stop)
+}
+
+func key(p *prefix) string {
+	k := p.Prefix + "/" + strconv.Itoa(int(p.PrefixLen))
+	if p.VPNRD != "" {
+		k = p.VPNRD + ":" + k
+	}
+
+	return k
+}
+
+// process records the change of the prefix and returns prefix_flap event when the number of changes
+// within the window reaches the threshold. The first advertisement of the prefix by a peer is not
+// a change, withdrawals and advertisements following an update within the window are.
+func (d *detector) process(p *prefix) *Event {
+	now := d.now()
+	k := key(p)
+	peer := p.RouterIP + "_" + p.PeerIP
+	d.Lock()
+	defer d.Unlock()
+	s, ok := d.prefixes[k]
+	if !ok {
+		s = &prefixState{lastSeen: make(map[string]time.Time)}
+		d.prefixes[k] = s
+	}
+	last, seen := s.lastSeen[peer]
+	s.lastSeen[peer] = now
+	if p.Action != "del" && (!seen || now.Sub(last) > d.window) {
+		return nil
+	}
+	s.changes = append(s.changes, &change{timestamp: now, routerIP: p.RouterIP, peerIP: p.PeerIP})
+	s.expire(now.Add(-d.window))
+	if len(s.changes) < d.threshold {
+		return nil
+	}
+	if !s.reported.IsZero() && now.Sub(s.reported) < d.window {
+		return nil
+	}
+	s.reported = now
+	e := &Event{
+		Timestamp: now.UTC().Format(time.RFC3339Nano),
+		Prefix:    p.Prefix,
+		PrefixLen: p.PrefixLen,
+		VPNRD:     p.VPNRD,
+		Count:     len(s.changes),
+		Window:    int64(d.window.Seconds()),
+		Peers:     make([]*Peer, 0),
+	}
+	peers := make(map[string]*Peer)
+	for _, c := range s.changes {
+		pr, ok := peers[c.routerIP+"_"+c.peerIP]
+		if !ok {
+			pr = &Peer{RouterIP: c.routerIP, PeerIP: c.peerIP}
+			peers[c.routerIP+"_"+c.peerIP] = pr
+			e.Peers = append(e.Peers, pr)
+		}
+		pr.Count++
+	}
+	sort.SliceStable(e.Peers, func(i, j int) bool { return e.Peers[i].Count > e.Peers[j].Count })
+
+	return e
+}
+
+// expire removes changes older than the time
+func (s *prefixState) expire(t time.Time) {
+	i := 0
+	for ; i < len(s.changes); i++ {
+		if s.changes[i].timestamp.After(t) {
+			break
+		}
+	}
+	s.changes = s.changes[i:]
+}
+
+// purge removes prefixes without any update within the window, it runs every window until
+// the detector is stopped.
+func (d *detector) purge() {
+	ticker := time.NewTicker(d.window)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			d.Lock()
+			t := d.now().Add(-d.window)
+			for k, s := range d.prefixes {
+				for peer, last := range s.lastSeen {
+					if !last.After(t) {
+						delete(s.lastSeen, peer)
+					}
+				}
+				if len(s.lastSeen) == 0 {
+					delete(d.prefixes, k)
+				}
+			}
+			glog.V(6).Infof("flap detector tracks %d prefixes", len(d.prefixes))
+			d.Unlock()
+		case <-d.stop:
+			return
+		}
+	}
+}
+
+// NewDetector instantiates a new prefix flap Detector, prefix_flap event is published to events publisher
+// when a prefix changes threshold times within the window.
+func NewDetector(window time.Duration, threshold int, events pub.Publisher) Detector {
+	d := &detector{
+		window:    window,
+		threshold: threshold,
+		events:    events,
+		prefixes:  make(map[string]*prefixState),
+		now:       time.Now,
+		stop:      make(chan struct{}),
+	}
+	go d.purge()
+
+	return d
+}
@@ -0,0 +1,131 @@
+package flap
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/go-test/deep"
+	"github.com/sbezverk/gobmp/pkg/bmp"
+)
+
+// eventCollector is a Publisher collecting prefix flap events
+type eventCollector struct {
+	events []*Event
+}
+
+func (c *eventCollector) PublishMessage(msgType int, msgHash []byte, msg []byte) error {
+	e := &Event{}
+	if err := json.Unmarshal(msg, e); err != nil {
+		return err
+	}
+	c.events = append(c.events, e)
+
+	return nil
+}
+
+func (c *eventCollector) Stop() {}
+
+type testMsg struct {
+	offset time.Duration
+	t      int
+	msg    string
+}
+
+func TestDetector(t *testing.T) {
+	start := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	add := `{"action":"add","router_ip":"192.0.2.1","peer_ip":"192.0.2.2","prefix":"10.0.0.0","prefix_len":24}`
+	del := `{"action":"del","router_ip":"192.0.2.1","peer_ip":"192.0.2.2","prefix":"10.0.0.0","prefix_len":24}`
+	tests := []struct {
+		name   string
+		msgs   []testMsg
+		expect []*Event
+	}{
+		{
+			name: "initial advertisements from many peers are not flaps",
+			msgs: []testMsg{
+				{0, bmp.UnicastPrefixV4Msg, `{"action":"add","router_ip":"192.0.2.1","peer_ip":"192.0.2.2","prefix":"10.0.0.0","prefix_len":24}`},
+				{0, bmp.UnicastPrefixV4Msg, `{"action":"add","router_ip":"192.0.2.1","peer_ip":"192.0.2.3","prefix":"10.0.0.0","prefix_len":24}`},
+				{0, bmp.UnicastPrefixV4Msg, `{"action":"add","router_ip":"192.0.2.1","peer_ip":"192.0.2.4","prefix":"10.0.0.0","prefix_len":24}`},
+				{0, bmp.UnicastPrefixV4Msg, `{"action":"add","router_ip":"192.0.2.5","peer_ip":"192.0.2.2","prefix":"10.0.0.0","prefix_len":24}`},
+			},
+		},
+		{
+			name: "flapping prefix is reported once per window",
+			msgs: []testMsg{
+				{0, bmp.UnicastPrefixV4Msg, add},
+				{time.Second, bmp.UnicastPrefixV4Msg, del},
+				{2 * time.Second, bmp.UnicastPrefixV4Msg, add},
+				{3 * time.Second, bmp.UnicastPrefixV4Msg, del},
+				{4 * time.Second, bmp.UnicastPrefixV4Msg, `{"action":"del","router_ip":"192.0.2.1","peer_ip":"192.0.2.3","prefix":"10.0.0.0","prefix_len":24}`},
+				{5 * time.Second, bmp.UnicastPrefixV4Msg, add},
+				{6 * time.Second, bmp.UnicastPrefixV4Msg, del},
+			},
+			expect: []*Event{
+				{
+					Timestamp: "2026-10-16T12:00:04Z",
+					Prefix:    "10.0.0.0",
+					PrefixLen: 24,
+					Count:     4,
+					Window:    60,
+					Peers: []*Peer{
+						{RouterIP: "192.0.2.1", PeerIP: "192.0.2.2", Count: 3},
+						{RouterIP: "192.0.2.1", PeerIP: "192.0.2.3", Count: 1},
+					},
+				},
+			},
+		},
+		{
+			name: "changes outside of the window are not counted",
+			msgs: []testMsg{
+				{0, bmp.UnicastPrefixV4Msg, add},
+				{time.Second, bmp.UnicastPrefixV4Msg, del},
+				{2 * time.Second, bmp.UnicastPrefixV4Msg, add},
+				{2 * time.Minute, bmp.UnicastPrefixV4Msg, del},
+				// Advertisement after the window since the previous update is not a change
+				{4 * time.Minute, bmp.UnicastPrefixV4Msg, add},
+				{4*time.Minute + time.Second, bmp.UnicastPrefixV4Msg, del},
+			},
+		},
+		{
+			name: "l3vpn prefixes are distinguished by route distinguisher",
+			msgs: []testMsg{
+				{0, bmp.L3VPNV4Msg, `{"action":"del","router_ip":"192.0.2.1","peer_ip":"192.0.2.2","prefix":"10.0.0.0","prefix_len":24,"vpn_rd":"100:1"}`},
+				{0, bmp.L3VPNV4Msg, `{"action":"del","router_ip":"192.0.2.1","peer_ip":"192.0.2.2","prefix":"10.0.0.0","prefix_len":24,"vpn_rd":"100:2"}`},
+				{0, bmp.L3VPNV4Msg, `{"action":"del","router_ip":"192.0.2.1","peer_ip":"192.0.2.2","prefix":"10.0.0.0","prefix_len":24,"vpn_rd":"100:1"}`},
+				{0, bmp.L3VPNV4Msg, `{"action":"del","router_ip":"192.0.2.1","peer_ip":"192.0.2.2","prefix":"10.0.0.0","prefix_len":24,"vpn_rd":"100:1"}`},
+				{0, bmp.L3VPNV4Msg, `{"action":"del","router_ip":"192.0.2.1","peer_ip":"192.0.2.2","prefix":"10.0.0.0","prefix_len":24,"vpn_rd":"100:1"}`},
+			},
+			expect: []*Event{
+				{
+					Timestamp: "2026-10-16T12:00:00Z",
+					Prefix:    "10.0.0.0",
+					PrefixLen: 24,
+					VPNRD:     "100:1",
+					Count:     4,
+					Window:    60,
+					Peers:     []*Peer{{RouterIP: "192.0.2.1", PeerIP: "192.0.2.2", Count: 4}},
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &eventCollector{}
+			d := NewDetector(time.Minute, 4, c).(*detector)
+			defer d.Stop()
+			for _, m := range tt.msgs {
+				now := start.Add(m.offset)
+				d.now = func() time.Time { return now }
+				if err := d.PublishMessage(m.t, nil, []byte(m.msg)); err != nil {
+					t.Fatalf("failed to publish message with error: %+v", err)
+				}
+			}
+			if !reflect.DeepEqual(c.events, tt.expect) {
+				t.Errorf("events do not match expected")
+				t.Logf("Differences: %+v", deep.Equal(c.events, tt.expect))
+			}
+		})
+	}
+}
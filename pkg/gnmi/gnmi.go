@@ -0,0 +1,272 @@
+package gnmi
+
+import (
+	"context"
+	"net"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang/glog"
+	gpb "github.com/openconfig/gnmi/proto/gnmi"
+	"github.com/sbezverk/gobmp/pkg/telemetry"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// gnmiVersion is the version of gNMI service implemented by the server
+const gnmiVersion = "0.7.0"
+
+type server struct {
+	state telemetry.Server
+}
+
+var _ gpb.GNMIServer = &server{}
+
+// NewServer returns gNMI server exposing the state of published objects kept by telemetry server. Objects are
+// identified by the paths of telemetry server, for example /bmp/router[address=192.0.2.1]/peer[address=192.0.2.2],
+// their values are published messages encoded as JSON_IETF. Get and Subscribe with STREAM subscriptions in ON_CHANGE,
+// SAMPLE or TARGET_DEFINED, which is served as ON_CHANGE, mode and ONCE subscriptions are supported, Set and POLL
+// subscriptions are not. Paths select all objects under them, wildcards are not supported.
+func NewServer(state telemetry.Server) gpb.GNMIServer {
+	return &server{state: state}
+}
+
+// ListenAndServe serves gNMI server over plaintext gRPC on the address
+func ListenAndServe(addr string, state telemetry.Server) error {
+	l, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+	s := grpc.NewServer()
+	gpb.RegisterGNMIServer(s, NewServer(state))
+
+	return s.Serve(l)
+}
+
+func (s *server) Capabilities(ctx context.Context, req *gpb.CapabilityRequest) (*gpb.CapabilityResponse, error) {
+	return &gpb.CapabilityResponse{
+		SupportedEncodings: []gpb.Encoding{gpb.Encoding_JSON_IETF},
+		GNMIVersion:        gnmiVersion,
+	}, nil
+}
+
+func (s *server) Get(ctx context.Context, req *gpb.GetRequest) (*gpb.GetResponse, error) {
+	if err := checkEncoding(req.GetEncoding()); err != nil {
+		return nil, err
+	}
+	paths := req.GetPath()
+	if len(paths) == 0 {
+		// Prefix alone selects all objects under it
+		paths = []*gpb.Path{{}}
+	}
+	resp := &gpb.GetResponse{}
+	for _, p := range paths {
+		prefix, err := statePath(req.GetPrefix(), p)
+		if err != nil {
+			return nil, err
+		}
+		for _, n := range s.state.Get(prefix) {
+			resp.Notification = append(resp.Notification, notification(n))
+		}
+	}
+
+	return resp, nil
+}
+
+func (s *server) Set(ctx context.Context, req *gpb.SetRequest) (*gpb.SetResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "state of BMP sessions is read only")
+}
+
+func (s *server) Subscribe(stream gpb.GNMI_SubscribeServer) error {
+	req, err := stream.Recv()
+	if err != nil {
+		return err
+	}
+	list := req.GetSubscribe()
+	if list == nil {
+		return status.Errorf(codes.InvalidArgument, "first subscribe request must carry subscription list")
+	}
+	if err := checkEncoding(list.GetEncoding()); err != nil {
+		return err
+	}
+	if len(list.GetSubscription()) == 0 {
+		return status.Errorf(codes.InvalidArgument, "subscription list is empty")
+	}
+	prefixes := make([]string, len(list.GetSubscription()))
+	for i, sub := range list.GetSubscription() {
+		if prefixes[i], err = statePath(list.GetPrefix(), sub.GetPath()); err != nil {
+			return err
+		}
+	}
+	switch list.GetMode() {
+	case gpb.SubscriptionList_ONCE:
+		return s.once(stream, prefixes)
+	case gpb.SubscriptionList_STREAM:
+		return s.stream(stream, list.GetSubscription(), prefixes)
+	default:
+		return status.Errorf(codes.Unimplemented, "subscription list mode %s is not supported", list.GetMode())
+	}
+}
+
+// once sends the current state of all prefixes followed by sync response
+func (s *server) once(stream gpb.GNMI_SubscribeServer, prefixes []string) error {
+	for _, prefix := range prefixes {
+		for _, n := range s.state.Get(prefix) {
+			if err := sendNotification(stream, n); err != nil {
+				return err
+			}
+		}
+	}
+
+	return sendSyncResponse(stream)
+}
+
+// stream subscribes to telemetry server for each prefix and streams notifications of all subscriptions until
+// the client cancels the stream or the telemetry server is stopped. Sync response is sent when the current state
+// of all subscriptions has been sent.
+func (s *server) stream(stream gpb.GNMI_SubscribeServer, subs []*gpb.Subscription, prefixes []string) error {
+	stop := make(chan struct{})
+	defer close(stop)
+	queue := make(chan *telemetry.Notification)
+	var wg sync.WaitGroup
+	for i, sub := range subs {
+		mode := telemetry.OnChange
+		var interval time.Duration
+		switch sub.GetMode() {
+		case gpb.SubscriptionMode_TARGET_DEFINED, gpb.SubscriptionMode_ON_CHANGE:
+		case gpb.SubscriptionMode_SAMPLE:
+			mode = telemetry.Sample
+			interval = time.Duration(sub.GetSampleInterval())
+		default:
+			return status.Errorf(codes.InvalidArgument, "subscription mode %s is not supported", sub.GetMode())
+		}
+		ch := s.state.Subscribe(prefixes[i], mode, interval, stop)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for n := range ch {
+				select {
+				case queue <- n:
+				case <-stop:
+				}
+			}
+		}()
+	}
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+	synced := 0
+	for {
+		select {
+		case n := <-queue:
+			if n.SyncResponse {
+				if synced++; synced == len(subs) {
+					if err := sendSyncResponse(stream); err != nil {
+						return err
+					}
+				}
+				continue
+			}
+			if err := sendNotification(stream, n); err != nil {
+				glog.V(5).Infof("gNMI subscriber is gone with error: %+v", err)
+				return err
+			}
+		case <-done:
+			return nil
+		case <-stream.Context().Done():
+			return stream.Context().Err()
+		}
+	}
+}
+
+func sendNotification(stream gpb.GNMI_SubscribeServer, n *telemetry.Notification) error {
+	return stream.Send(&gpb.SubscribeResponse{Response: &gpb.SubscribeResponse_Update{Update: notification(n)}})
+}
+
+func sendSyncResponse(stream gpb.GNMI_SubscribeServer) error {
+	return stream.Send(&gpb.SubscribeResponse{Response: &gpb.SubscribeResponse_SyncResponse{SyncResponse: true}})
+}
+
+func checkEncoding(e gpb.Encoding) error {
+	switch e {
+	case gpb.Encoding_JSON, gpb.Encoding_JSON_IETF:
+		return nil
+	}
+
+	return status.Errorf(codes.Unimplemented, "encoding %s is not supported, use JSON_IETF", e)
+}
+
+// notification returns gNMI notification of telemetry notification, updates carry published messages
+// encoded as JSON_IETF.
+func notification(n *telemetry.Notification) *gpb.Notification {
+	p := gnmiPath(n.Path)
+	if n.Delete {
+		return &gpb.Notification{Timestamp: n.Timestamp, Delete: []*gpb.Path{p}}
+	}
+
+	return &gpb.Notification{
+		Timestamp: n.Timestamp,
+		Update: []*gpb.Update{
+			{
+				Path: p,
+				Val:  &gpb.TypedValue{Value: &gpb.TypedValue_JsonIetfVal{JsonIetfVal: n.Update}},
+			},
+		},
+	}
+}
+
+// gnmiPath returns gNMI path of telemetry path, for example /bmp/router[address=192.0.2.1]
+func gnmiPath(s string) *gpb.Path {
+	p := &gpb.Path{}
+	for _, e := range strings.Split(strings.Trim(s, "/"), "/") {
+		if e == "" {
+			continue
+		}
+		elem := &gpb.PathElem{}
+		i := strings.Index(e, "[")
+		if i < 0 {
+			elem.Name = e
+			p.Elem = append(p.Elem, elem)
+			continue
+		}
+		elem.Name = e[:i]
+		elem.Key = make(map[string]string)
+		for _, kv := range strings.Split(strings.TrimSuffix(e[i+1:], "]"), "][") {
+			if j := strings.Index(kv, "="); j > 0 {
+				elem.Key[kv[:j]] = kv[j+1:]
+			}
+		}
+		p.Elem = append(p.Elem, elem)
+	}
+
+	return p
+}
+
+// statePath returns telemetry path of gNMI path under the prefix, keys of an element are sorted by name
+func statePath(prefix, path *gpb.Path) (string, error) {
+	var s strings.Builder
+	for _, e := range append(append([]*gpb.PathElem{}, prefix.GetElem()...), path.GetElem()...) {
+		if e.GetName() == "*" || e.GetName() == "..." {
+			return "", status.Errorf(codes.InvalidArgument, "wildcard path element %s is not supported", e.GetName())
+		}
+		s.WriteString("/" + e.GetName())
+		keys := make([]string, 0, len(e.GetKey()))
+		for k := range e.GetKey() {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			if e.GetKey()[k] == "*" {
+				return "", status.Errorf(codes.InvalidArgument, "wildcard key %s of path element %s is not supported", k, e.GetName())
+			}
+			s.WriteString("[" + k + "=" + e.GetKey()[k] + "]")
+		}
+	}
+
+	return s.String(), nil
+}
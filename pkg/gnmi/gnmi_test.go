@@ -0,0 +1,240 @@
+package gnmi
+
+import (
+	"context"
+	"net"
+	"reflect"
+	"testing"
+	"time"
+
+	gpb "github.com/openconfig/gnmi/proto/gnmi"
+	"github.com/sbezverk/gobmp/pkg/bmp"
+	"github.com/sbezverk/gobmp/pkg/telemetry"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+const (
+	prefix1 = `{"action":"add","hash":"h1","router_ip":"192.0.2.1","peer_ip":"192.0.2.2","prefix":"10.0.0.0"}`
+	prefix2 = `{"action":"add","hash":"h2","router_ip":"192.0.2.10","peer_ip":"192.0.2.2","prefix":"10.0.1.0"}`
+)
+
+func newClient(t *testing.T, state telemetry.Server) gpb.GNMIClient {
+	t.Helper()
+	l := bufconn.Listen(1 << 20)
+	s := grpc.NewServer()
+	gpb.RegisterGNMIServer(s, NewServer(state))
+	go s.Serve(l)
+	t.Cleanup(s.Stop)
+	conn, err := grpc.Dial("bufconn", grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithContextDialer(func(ctx context.Context, addr string) (net.Conn, error) {
+			return l.Dial()
+		}))
+	if err != nil {
+		t.Fatalf("failed to dial gNMI server with error: %+v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	return gpb.NewGNMIClient(conn)
+}
+
+func publish(t *testing.T, state telemetry.Server, msg string) {
+	t.Helper()
+	if err := state.PublishMessage(bmp.UnicastPrefixV4Msg, nil, []byte(msg)); err != nil {
+		t.Fatalf("failed to publish with error: %+v", err)
+	}
+}
+
+func routerPath(router string) *gpb.Path {
+	return &gpb.Path{Elem: []*gpb.PathElem{{Name: "bmp"}, {Name: "router", Key: map[string]string{"address": router}}}}
+}
+
+func prefixPath(router, hash string) *gpb.Path {
+	p := routerPath(router)
+	p.Elem = append(p.Elem,
+		&gpb.PathElem{Name: "peer", Key: map[string]string{"address": "192.0.2.2"}},
+		&gpb.PathElem{Name: "unicast_prefix_v4", Key: map[string]string{"hash": hash}})
+
+	return p
+}
+
+func receive(t *testing.T, stream gpb.GNMI_SubscribeClient) *gpb.SubscribeResponse {
+	t.Helper()
+	resp, err := stream.Recv()
+	if err != nil {
+		t.Fatalf("failed to receive subscribe response with error: %+v", err)
+	}
+
+	return resp
+}
+
+func checkUpdate(t *testing.T, resp *gpb.SubscribeResponse, path *gpb.Path, value string) {
+	t.Helper()
+	u := resp.GetUpdate().GetUpdate()
+	if len(u) != 1 || !reflect.DeepEqual(u[0].GetPath().GetElem(), path.GetElem()) || string(u[0].GetVal().GetJsonIetfVal()) != value {
+		t.Fatalf("unexpected update %+v", resp)
+	}
+}
+
+func TestSubscribeOnChange(t *testing.T) {
+	state := telemetry.NewServer()
+	defer state.Stop()
+	client := newClient(t, state)
+	publish(t, state, prefix1)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	stream, err := client.Subscribe(ctx)
+	if err != nil {
+		t.Fatalf("failed to subscribe with error: %+v", err)
+	}
+	if err := stream.Send(&gpb.SubscribeRequest{Request: &gpb.SubscribeRequest_Subscribe{Subscribe: &gpb.SubscriptionList{
+		Prefix:       &gpb.Path{Elem: []*gpb.PathElem{{Name: "bmp"}}},
+		Subscription: []*gpb.Subscription{{Path: &gpb.Path{Elem: routerPath("192.0.2.1").Elem[1:]}, Mode: gpb.SubscriptionMode_ON_CHANGE}},
+		Encoding:     gpb.Encoding_JSON_IETF,
+	}}}); err != nil {
+		t.Fatalf("failed to send subscribe request with error: %+v", err)
+	}
+	checkUpdate(t, receive(t, stream), prefixPath("192.0.2.1", "h1"), prefix1)
+	if resp := receive(t, stream); !resp.GetSyncResponse() {
+		t.Fatalf("expected sync response, got %+v", resp)
+	}
+	// Message of another router must not be delivered
+	publish(t, state, prefix2)
+	publish(t, state, `{"action":"del","hash":"h1","router_ip":"192.0.2.1","peer_ip":"192.0.2.2"}`)
+	resp := receive(t, stream)
+	if d := resp.GetUpdate().GetDelete(); len(d) != 1 || !reflect.DeepEqual(d[0].GetElem(), prefixPath("192.0.2.1", "h1").GetElem()) {
+		t.Fatalf("expected delete notification, got %+v", resp)
+	}
+}
+
+func TestSubscribeSample(t *testing.T) {
+	state := telemetry.NewServer()
+	defer state.Stop()
+	client := newClient(t, state)
+	publish(t, state, prefix1)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	stream, err := client.Subscribe(ctx)
+	if err != nil {
+		t.Fatalf("failed to subscribe with error: %+v", err)
+	}
+	if err := stream.Send(&gpb.SubscribeRequest{Request: &gpb.SubscribeRequest_Subscribe{Subscribe: &gpb.SubscriptionList{
+		Subscription: []*gpb.Subscription{{Path: routerPath("192.0.2.1"), Mode: gpb.SubscriptionMode_SAMPLE, SampleInterval: uint64(10 * time.Millisecond)}},
+	}}}); err != nil {
+		t.Fatalf("failed to send subscribe request with error: %+v", err)
+	}
+	checkUpdate(t, receive(t, stream), prefixPath("192.0.2.1", "h1"), prefix1)
+	if resp := receive(t, stream); !resp.GetSyncResponse() {
+		t.Fatalf("expected sync response, got %+v", resp)
+	}
+	// The state is sent again every sample interval
+	checkUpdate(t, receive(t, stream), prefixPath("192.0.2.1", "h1"), prefix1)
+}
+
+func TestSubscribeOnce(t *testing.T) {
+	state := telemetry.NewServer()
+	defer state.Stop()
+	client := newClient(t, state)
+	publish(t, state, prefix1)
+	publish(t, state, prefix2)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	stream, err := client.Subscribe(ctx)
+	if err != nil {
+		t.Fatalf("failed to subscribe with error: %+v", err)
+	}
+	if err := stream.Send(&gpb.SubscribeRequest{Request: &gpb.SubscribeRequest_Subscribe{Subscribe: &gpb.SubscriptionList{
+		Subscription: []*gpb.Subscription{{Path: routerPath("192.0.2.10")}},
+		Mode:         gpb.SubscriptionList_ONCE,
+	}}}); err != nil {
+		t.Fatalf("failed to send subscribe request with error: %+v", err)
+	}
+	checkUpdate(t, receive(t, stream), prefixPath("192.0.2.10", "h2"), prefix2)
+	if resp := receive(t, stream); !resp.GetSyncResponse() {
+		t.Fatalf("expected sync response, got %+v", resp)
+	}
+	if _, err := stream.Recv(); err == nil {
+		t.Fatalf("expected the stream to end after sync response")
+	}
+}
+
+func TestSubscribeErrors(t *testing.T) {
+	state := telemetry.NewServer()
+	defer state.Stop()
+	client := newClient(t, state)
+	tests := []struct {
+		name string
+		list *gpb.SubscriptionList
+		code codes.Code
+	}{
+		{
+			name: "poll",
+			list: &gpb.SubscriptionList{Subscription: []*gpb.Subscription{{Path: routerPath("192.0.2.1")}}, Mode: gpb.SubscriptionList_POLL},
+			code: codes.Unimplemented,
+		},
+		{
+			name: "proto encoding",
+			list: &gpb.SubscriptionList{Subscription: []*gpb.Subscription{{Path: routerPath("192.0.2.1")}}, Encoding: gpb.Encoding_PROTO},
+			code: codes.Unimplemented,
+		},
+		{
+			name: "wildcard",
+			list: &gpb.SubscriptionList{Subscription: []*gpb.Subscription{{Path: routerPath("*")}}},
+			code: codes.InvalidArgument,
+		},
+		{
+			name: "empty subscription list",
+			list: &gpb.SubscriptionList{},
+			code: codes.InvalidArgument,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			stream, err := client.Subscribe(ctx)
+			if err != nil {
+				t.Fatalf("failed to subscribe with error: %+v", err)
+			}
+			if err := stream.Send(&gpb.SubscribeRequest{Request: &gpb.SubscribeRequest_Subscribe{Subscribe: tt.list}}); err != nil {
+				t.Fatalf("failed to send subscribe request with error: %+v", err)
+			}
+			if _, err := stream.Recv(); status.Code(err) != tt.code {
+				t.Fatalf("expected error code %s but got %+v", tt.code, err)
+			}
+		})
+	}
+}
+
+func TestGet(t *testing.T) {
+	state := telemetry.NewServer()
+	defer state.Stop()
+	client := newClient(t, state)
+	publish(t, state, prefix1)
+	publish(t, state, prefix2)
+	resp, err := client.Get(context.Background(), &gpb.GetRequest{Path: []*gpb.Path{routerPath("192.0.2.1")}, Encoding: gpb.Encoding_JSON_IETF})
+	if err != nil {
+		t.Fatalf("failed to get with error: %+v", err)
+	}
+	n := resp.GetNotification()
+	if len(n) != 1 || len(n[0].GetUpdate()) != 1 || string(n[0].GetUpdate()[0].GetVal().GetJsonIetfVal()) != prefix1 {
+		t.Fatalf("unexpected get response %+v", resp)
+	}
+	if _, err := client.Set(context.Background(), &gpb.SetRequest{}); status.Code(err) != codes.Unimplemented {
+		t.Fatalf("expected set to be unimplemented, got %+v", err)
+	}
+}
+
+func TestPath(t *testing.T) {
+	s := "/bmp/router[address=2001:db8::1]/peer[address=192.0.2.2]/unicast_prefix_v6[hash=h1]"
+	p, err := statePath(nil, gnmiPath(s))
+	if err != nil {
+		t.Fatalf("failed to convert path with error: %+v", err)
+	}
+	if p != s {
+		t.Fatalf("expected path %s but got %s", s, p)
+	}
+}
@@ -4,34 +4,61 @@ import (
 	"fmt"
 	"io"
 	"net"
+	"time"
 
 	"github.com/golang/glog"
 	"github.com/sbezverk/gobmp/pkg/bmp"
+	"github.com/sbezverk/gobmp/pkg/capture"
+	"github.com/sbezverk/gobmp/pkg/cluster"
+	"github.com/sbezverk/gobmp/pkg/enrich"
+	"github.com/sbezverk/gobmp/pkg/latency"
 	"github.com/sbezverk/gobmp/pkg/message"
 	"github.com/sbezverk/gobmp/pkg/parser"
 	"github.com/sbezverk/gobmp/pkg/pub"
+	"github.com/sbezverk/gobmp/pkg/rpki"
+	"github.com/sbezverk/gobmp/pkg/state"
+	"github.com/sbezverk/gobmp/pkg/tracing"
 )
 
 // BMPServer defines methods to manage BMP Server
 type BMPServer interface {
 	Start()
 	Stop()
+	Serve(name string, r io.Reader) error
+	// Sessions returns BMP sessions currently served by the server
+	Sessions() []*SessionInfo
 }
 
 type bmpServer struct {
-	splitAF         bool
-	intercept       bool
-	publisher       pub.Publisher
-	sourcePort      int
-	destinationPort int
-	incoming        net.Listener
-	stop            chan struct{}
+	splitAF      bool
+	publisher    pub.Publisher
+	validator    rpki.Validator
+	tee          Tee
+	listeners    []*listener
+	enrichers    []enrich.Enricher
+	groups       []*routerGroup
+	limiter      RateLimiter
+	cluster      cluster.Cluster
+	store        state.Store
+	capturer     capture.Capturer
+	checkUpdates bool
+	attachRaw    bool
+	idle         time.Duration
+	latency      latency.Recorder
+	tracer       tracing.Tracer
+	adminID      string
+	sessions     sessions
+	stop         chan struct{}
 }
 
 func (srv *bmpServer) Start() {
 	// Starting bmp server server
-	glog.Infof("Starting gobmp server on %s, intercept mode: %t\n", srv.incoming.Addr().String(), srv.intercept)
-	go srv.server()
+	for _, l := range srv.listeners {
+		glog.Infof("Starting gobmp server listener %s on %s with %d acceptors, intercept mode: %t\n", l.name, l.incoming[0].Addr().String(), len(l.incoming), srv.tee != nil)
+		for _, in := range l.incoming {
+			go srv.server(l, in)
+		}
+	}
 }
 
 func (srv *bmpServer) Stop() {
@@ -39,54 +66,130 @@ func (srv *bmpServer) Stop() {
 	if srv.publisher != nil {
 		srv.publisher.Stop()
 	}
+	if srv.tracer != nil {
+		srv.tracer.Stop()
+	}
 	close(srv.stop)
 }
 
-func (srv *bmpServer) server() {
+func (srv *bmpServer) server(l *listener, incoming net.Listener) {
 	for {
-		client, err := srv.incoming.Accept()
+		client, err := incoming.Accept()
 		if err != nil {
-			glog.Errorf("fail to accept client connection with error: %+v", err)
+			glog.Errorf("fail to accept client connection on listener %s with error: %+v", l.name, err)
+			continue
+		}
+		if err := l.check(client.RemoteAddr()); err != nil {
+			glog.Warningf("rejected BMP session from %+v on listener %s: %+v", client.RemoteAddr(), l.name, err)
+			client.Close()
 			continue
 		}
-		glog.V(5).Infof("client %+v accepted, calling bmpWorker", client.RemoteAddr())
-		go srv.bmpWorker(client)
+		// Cluster ownership is defined only for routers connected over TCP
+		if srv.cluster != nil && remoteIP(client) != nil && !srv.accept(client) {
+			client.Close()
+			continue
+		}
+		if c, ok := client.(interface{ SetReadBuffer(int) error }); ok && l.receiveBuffer != 0 {
+			if err := c.SetReadBuffer(l.receiveBuffer); err != nil {
+				glog.Warningf("failed to set receive buffer of client %+v with error: %+v", client.RemoteAddr(), err)
+			}
+		}
+		glog.V(5).Infof("client %+v accepted by listener %s, calling bmpWorker", client.RemoteAddr(), l.name)
+		go srv.bmpWorker(client, l)
+	}
+}
+
+// accept returns true if BMP session of the client is owned by the local cluster member, connections from
+// other members are their liveness probes.
+func (srv *bmpServer) accept(client net.Conn) bool {
+	ip := remoteIP(client)
+	if srv.cluster.IsMember(ip) {
+		return false
+	}
+	if owner := srv.cluster.Owner(ip); owner != "" && !srv.cluster.IsOwner(ip) {
+		glog.V(5).Infof("client %+v is owned by cluster member %s, closing connection", client.RemoteAddr(), owner)
+		return false
+	}
+
+	return true
+}
+
+func remoteIP(conn net.Conn) net.IP {
+	if addr, ok := conn.RemoteAddr().(*net.TCPAddr); ok {
+		return addr.IP
 	}
+
+	return nil
 }
 
-func (srv *bmpServer) bmpWorker(client net.Conn) {
+// addrString returns text representation of the address, or empty string when the address is not known
+func addrString(ip net.IP) string {
+	if ip == nil {
+		return ""
+	}
+
+	return ip.String()
+}
+
+func (srv *bmpServer) bmpWorker(client net.Conn, l *listener) {
 	defer client.Close()
-	var server net.Conn
-	var err error
-	if srv.intercept {
-		server, err = net.Dial("tcp", ":"+fmt.Sprintf("%d", srv.destinationPort))
-		if err != nil {
-			glog.Errorf("failed to connect to destination with error: %+v", err)
-			return
-		}
-		defer server.Close()
-		glog.V(5).Infof("connection to destination server %v established, start intercepting", server.RemoteAddr())
+	var tee *teeSession
+	if srv.tee != nil {
+		tee = srv.tee.open(client)
+		defer tee.close()
 	}
 	var producerQueue chan bmp.Message
-	prod := message.NewProducer(srv.publisher, srv.splitAF)
+	router := remoteIP(client)
+	prod := message.NewProducerWithConfig(srv.publisher, &message.ProducerConfig{
+		SplitAF:        srv.splitAF,
+		Validator:      srv.validator,
+		Enrichers:      srv.sessionEnrichers(l, router),
+		Store:          srv.store,
+		CheckUpdates:   srv.checkUpdates,
+		AttachRaw:      srv.attachRaw,
+		OpenBMPAdminID: srv.adminID,
+		RouterIP:       addrString(router),
+	})
 	prodStop := make(chan struct{})
 	producerQueue = make(chan bmp.Message)
 	// Starting messages producer per client with dedicated work queue
 	go prod.Producer(producerQueue, prodStop)
 
-	parserQueue := make(chan []byte)
+	name := sessionName(router, l)
+	sess := srv.sessions.add(name, l.name, client.RemoteAddr().String())
+	defer srv.sessions.remove(sess)
+	parserQueue := make(chan parser.Frame)
 	parsStop := make(chan struct{})
 	// Starting parser per client with dedicated work queue
-	go parser.Parser(parserQueue, producerQueue, parsStop)
+	go parser.Parser(parserQueue, producerQueue, parsStop, srv.observe(name))
 	defer func() {
 		glog.V(5).Infof("all done with client %+v", client.RemoteAddr())
 		close(parsStop)
 		close(prodStop)
 	}()
+	var limiter SessionLimiter
+	if srv.limiter != nil {
+		limiter = srv.limiter.NewSession()
+		defer limiter.Close()
+	}
 	for {
+		if limiter != nil {
+			// Not reading from the session until it is allowed, the router is slowed down by TCP flow control
+			limiter.Wait()
+		}
+		if srv.cluster != nil && router != nil && !srv.cluster.IsOwner(router) {
+			// Router is owned by another member after cluster membership change, closing the session
+			// makes the router to reconnect and to send the full table to the new owner.
+			glog.Infof("client %+v is no longer owned by local cluster member, closing connection", client.RemoteAddr())
+			return
+		}
+		if srv.idle != 0 {
+			// Half-closed sessions never return from the read, the deadline is moved by every message
+			client.SetReadDeadline(time.Now().Add(srv.idle))
+		}
 		headerMsg := make([]byte, bmp.CommonHeaderLength)
 		if _, err := io.ReadAtLeast(client, headerMsg, bmp.CommonHeaderLength); err != nil {
-			glog.Errorf("fail to read from client %+v with error: %+v", client.RemoteAddr(), err)
+			srv.readFailed(client, prod, err)
 			return
 		}
 		// Recovering common header first
@@ -95,42 +198,218 @@ func (srv *bmpServer) bmpWorker(client net.Conn) {
 			glog.Errorf("fail to recover BMP message Common Header with error: %+v", err)
 			continue
 		}
+		span := srv.startSpan(name, header)
+		receive := span.Child("bmp.receive")
 		// Allocating space for the message body
 		msg := make([]byte, int(header.MessageLength)-bmp.CommonHeaderLength)
 		if _, err := io.ReadFull(client, msg); err != nil {
-			glog.Errorf("fail to read from client %+v with error: %+v", client.RemoteAddr(), err)
+			srv.readFailed(client, prod, err)
 			return
 		}
 
+		received := time.Now()
 		fullMsg := make([]byte, int(header.MessageLength))
 		copy(fullMsg, headerMsg)
 		copy(fullMsg[bmp.CommonHeaderLength:], msg)
-		// Sending information to the server only in intercept mode
-		if srv.intercept {
-			if _, err := server.Write(fullMsg); err != nil {
-				glog.Errorf("fail to write to server %+v with error: %+v", server.RemoteAddr(), err)
-				return
+		// Forwarding the exact frame to downstream collectors only in intercept mode
+		if tee != nil {
+			tee.forward(fullMsg, received)
+		}
+		if srv.capturer != nil {
+			srv.capturer.Capture(router, fullMsg)
+		}
+		receive.Finish()
+		sess.received()
+		parserQueue <- parser.Frame{Msg: fullMsg, Span: span}
+	}
+}
+
+// Serve reads BMP messages of a single BMP session from r until the end of the stream, for example a capture
+// piped to stdin. Messages are parsed and published in the order they were read, name identifies the session in logs.
+func (srv *bmpServer) Serve(name string, r io.Reader) error {
+	prod := message.NewProducerWithConfig(srv.publisher, &message.ProducerConfig{
+		SplitAF:        srv.splitAF,
+		Validator:      srv.validator,
+		Enrichers:      srv.enrichers,
+		Store:          srv.store,
+		CheckUpdates:   srv.checkUpdates,
+		AttachRaw:      srv.attachRaw,
+		OpenBMPAdminID: srv.adminID,
+	})
+	sess := srv.sessions.add(name, "", "")
+	defer srv.sessions.remove(sess)
+	headerMsg := make([]byte, bmp.CommonHeaderLength)
+	for n := 0; ; n++ {
+		if _, err := io.ReadFull(r, headerMsg); err != nil {
+			if err == io.EOF {
+				glog.Infof("BMP session %s ended after %d messages", name, n)
+				return nil
 			}
+			return fmt.Errorf("fail to read from %s with error: %+v", name, err)
+		}
+		header, err := bmp.UnmarshalCommonHeader(headerMsg)
+		if err != nil {
+			return fmt.Errorf("fail to recover BMP message Common Header from %s with error: %+v", name, err)
+		}
+		if int(header.MessageLength) < bmp.CommonHeaderLength {
+			return fmt.Errorf("invalid BMP message length %d read from %s", header.MessageLength, name)
 		}
-		parserQueue <- fullMsg
+		span := srv.startSpan(name, header)
+		receive := span.Child("bmp.receive")
+		fullMsg := make([]byte, int(header.MessageLength))
+		copy(fullMsg, headerMsg)
+		if _, err := io.ReadFull(r, fullMsg[bmp.CommonHeaderLength:]); err != nil {
+			return fmt.Errorf("fail to read from %s with error: %+v", name, err)
+		}
+		receive.Finish()
+		sess.received()
+		for _, msg := range parser.ParseTraced(fullMsg, srv.observe(name), span) {
+			prod.Produce(msg)
+		}
+	}
+}
+
+func (srv *bmpServer) Sessions() []*SessionInfo {
+	return srv.sessions.list()
+}
+
+// sessionName returns the name of BMP session of the router used in parse latency and traces, sessions without
+// router's address, for example on unix socket, are named by the name of the listener.
+func sessionName(router net.IP, l *listener) string {
+	if router == nil {
+		return l.name
+	}
+
+	return router.String()
+}
+
+func (srv *bmpServer) observe(name string) parser.Observer {
+	if srv.latency == nil {
+		return nil
+	}
+
+	return func(msgType uint8, d time.Duration) {
+		srv.latency.Observe(name, msgType, d)
 	}
 }
 
-// NewBMPServer instantiates a new instance of BMP Server
+// startSpan returns the root span of the BMP message received from the session, nil is returned when tracing
+// is not enabled or the message is not sampled.
+func (srv *bmpServer) startSpan(name string, header *bmp.CommonHeader) *tracing.Span {
+	if srv.tracer == nil {
+		return nil
+	}
+	span := srv.tracer.Start("bmp.message")
+	span.SetAttribute("bmp.session", name)
+	span.SetAttribute("bmp.message_type", header.MessageType)
+	span.SetAttribute("bmp.message_length", header.MessageLength)
+
+	return span
+}
+
+// readFailed logs the failed read from BMP session, when the session was idle for longer than the idle timeout,
+// peer down messages of the session's peers are published as the router will not send them.
+func (srv *bmpServer) readFailed(client net.Conn, prod message.Producer, err error) {
+	if nerr, ok := err.(net.Error); !ok || !nerr.Timeout() {
+		glog.Errorf("fail to read from client %+v with error: %+v", client.RemoteAddr(), err)
+		return
+	}
+	glog.Warningf("no messages received from client %+v for %s, closing idle BMP session", client.RemoteAddr(), srv.idle)
+	prod.TerminateSession(fmt.Sprintf("BMP session idle for %s", srv.idle))
+}
+
+// teeQueue defines the number of BMP messages buffered per BMP session in intercept mode of NewBMPServer
+const teeQueue = 1000
+
+// Config defines optional features of BMP Server. Tee forwards received BMP messages to downstream collectors
+// in intercept mode. If SplitAF is set to true, ipv4 and ipv6 messages go into separate topics. Validator
+// annotates unicast prefixes with Route Origin Validation state, Enrichers are enrichment plugins, RouterGroups
+// tag messages of their routers and RateLimiter limits ingestion rate of BMP sessions. When Cluster is set, only
+// BMP sessions of routers owned by the local member are accepted. Store resumes BMP sessions of known routers,
+// Capturer captures raw BMP messages of routers. When CheckUpdates is true, messages of BGP Updates are annotated
+// with results of semantic validation of the update, when AttachRaw is true, they carry the update and BMP headers
+// as received from the router. IdleTimeout is the time after which a BMP session without received messages is
+// closed and peer down messages of its peers are published, 0 disables the idle timeout. Latency records parse
+// latency of BMP messages and Tracer traces BMP messages from their receive to publishing. When OpenBMPAdminID
+// is not empty, hashes of published messages are calculated as by OpenBMP collector with the admin id.
+type Config struct {
+	Tee            Tee
+	SplitAF        bool
+	Validator      rpki.Validator
+	Enrichers      []enrich.Enricher
+	RouterGroups   []*RouterGroup
+	RateLimiter    RateLimiter
+	Cluster        cluster.Cluster
+	Store          state.Store
+	Capturer       capture.Capturer
+	CheckUpdates   bool
+	AttachRaw      bool
+	IdleTimeout    time.Duration
+	Latency        latency.Recorder
+	Tracer         tracing.Tracer
+	OpenBMPAdminID string
+}
+
+// NewBMPServer instantiates a new instance of BMP Server listening on sPort, when intercept is true, received
+// BMP messages are forwarded to the collector listening on dPort.
 func NewBMPServer(sPort, dPort int, intercept bool, p pub.Publisher, splitAF bool) (BMPServer, error) {
-	incoming, err := net.Listen("tcp", fmt.Sprintf(":%d", sPort))
-	if err != nil {
-		glog.Errorf("fail to setup listener on port %d with error: %+v", sPort, err)
-		return nil, err
+	config := &Config{SplitAF: splitAF}
+	if intercept {
+		t, err := NewTee([]string{fmt.Sprintf(":%d", dPort)}, teeQueue)
+		if err != nil {
+			return nil, err
+		}
+		config.Tee = t
+	}
+
+	return NewBMPServerWithConfig([]*ListenerConfig{
+		{
+			Name:    "default",
+			Address: fmt.Sprintf(":%d", sPort),
+		},
+	}, p, config)
+}
+
+// NewBMPServerWithConfig instantiates a new instance of BMP Server serving BMP sessions on all listeners,
+// each listener applies its own allowed sources and tags, nil config enables none of optional features.
+func NewBMPServerWithConfig(listeners []*ListenerConfig, p pub.Publisher, config *Config) (BMPServer, error) {
+	if config == nil {
+		config = &Config{}
 	}
 	bmp := bmpServer{
-		stop:            make(chan struct{}),
-		sourcePort:      sPort,
-		destinationPort: dPort,
-		intercept:       intercept,
-		publisher:       p,
-		incoming:        incoming,
-		splitAF:         splitAF,
+		stop:         make(chan struct{}),
+		tee:          config.Tee,
+		publisher:    p,
+		validator:    config.Validator,
+		listeners:    make([]*listener, 0, len(listeners)),
+		enrichers:    config.Enrichers,
+		limiter:      config.RateLimiter,
+		cluster:      config.Cluster,
+		store:        config.Store,
+		capturer:     config.Capturer,
+		splitAF:      config.SplitAF,
+		checkUpdates: config.CheckUpdates,
+		attachRaw:    config.AttachRaw,
+		idle:         config.IdleTimeout,
+		latency:      config.Latency,
+		tracer:       config.Tracer,
+		adminID:      config.OpenBMPAdminID,
+	}
+	var err error
+	if bmp.groups, err = newRouterGroups(config.RouterGroups); err != nil {
+		return nil, err
+	}
+	for _, c := range listeners {
+		l, err := newListener(c, config.Enrichers)
+		if err != nil {
+			glog.Errorf("%+v", err)
+			// Closing already opened listeners
+			for _, o := range bmp.listeners {
+				o.close()
+			}
+			return nil, err
+		}
+		bmp.listeners = append(bmp.listeners, l)
 	}
 
 	return &bmp, nil
@@ -0,0 +1,88 @@
+package gobmpsrv
+
+import (
+	"bytes"
+	"io"
+	"sync"
+	"testing"
+
+	"github.com/sbezverk/gobmp/pkg/bmp"
+	"github.com/sbezverk/gobmp/pkg/fixture"
+)
+
+// counter is a Publisher counting published messages by their types
+type counter struct {
+	sync.Mutex
+	types map[int]int
+}
+
+func (c *counter) PublishMessage(msgType int, msgHash []byte, msg []byte) error {
+	c.Lock()
+	defer c.Unlock()
+	c.types[msgType]++
+
+	return nil
+}
+
+func (c *counter) Stop() {}
+
+func TestServe(t *testing.T) {
+	fixtures, err := fixture.Load("../message/testdata")
+	if err != nil {
+		t.Fatalf("failed to load fixtures with error: %+v", err)
+	}
+	var data []byte
+	for _, f := range fixtures {
+		if f.Name == "unicast-v4" {
+			data = f.Data
+		}
+	}
+	if data == nil {
+		t.Fatalf("fixture unicast-v4 is not found")
+	}
+	c := &counter{types: make(map[int]int)}
+	srv, err := NewBMPServerWithConfig(nil, c, &Config{SplitAF: true})
+	if err != nil {
+		t.Fatalf("failed to create server with error: %+v", err)
+	}
+	if err := srv.Serve("test", bytes.NewReader(data)); err != nil {
+		t.Fatalf("failed to serve BMP session with error: %+v", err)
+	}
+	if c.types[bmp.PeerStateChangeMsg] != 1 || c.types[bmp.UnicastPrefixV4Msg] != 3 {
+		t.Errorf("expected 1 peer and 3 unicast prefix messages, got %+v", c.types)
+	}
+	// Stream ending in the middle of a message
+	if err := srv.Serve("test", bytes.NewReader(data[:len(data)-1])); err == nil {
+		t.Errorf("expected truncated stream to fail")
+	}
+}
+
+func TestSessions(t *testing.T) {
+	srv, err := NewBMPServerWithConfig(nil, &counter{types: make(map[int]int)}, &Config{SplitAF: true})
+	if err != nil {
+		t.Fatalf("failed to create server with error: %+v", err)
+	}
+	r, w := io.Pipe()
+	done := make(chan error)
+	go func() {
+		done <- srv.Serve("stdin", r)
+	}()
+	// BMP Initiation message without TLVs, the second message is read after the first is processed
+	if _, err := w.Write([]byte{3, 0, 0, 0, 6, 4, 3}); err != nil {
+		t.Fatalf("failed to write BMP message with error: %+v", err)
+	}
+	sessions := srv.Sessions()
+	if len(sessions) != 1 || sessions[0].Name != "stdin" || sessions[0].Messages != 1 {
+		t.Errorf("expected session stdin with 1 message, got %+v", sessions)
+	}
+	if _, err := w.Write([]byte{0, 0, 0, 6, 4}); err != nil {
+		t.Fatalf("failed to write BMP message with error: %+v", err)
+	}
+	w.Close()
+	if err := <-done; err != nil {
+		t.Fatalf("failed to serve BMP session with error: %+v", err)
+	}
+	if sessions := srv.Sessions(); len(sessions) != 0 {
+		t.Errorf("expected no sessions after the end of the stream, got %+v", sessions)
+	}
+}
@@ -0,0 +1,137 @@
+package gobmpsrv
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net"
+
+	"github.com/sbezverk/gobmp/pkg/enrich"
+)
+
+// GroupField is the field of "enrichment" object carrying the name of the router group
+const GroupField = "group"
+
+// RouterGroup defines a named group or tenant of routers, BMP sessions from Sources prefixes or addresses
+// belong to the group. The group name is added as "group" field and Tags to "enrichment" object of all
+// messages of the group's routers, TopicPrefix is prepended to Kafka topic names of the group's messages.
+type RouterGroup struct {
+	Name        string            `json:"name"`
+	Sources     []string          `json:"sources"`
+	Tags        map[string]string `json:"tags,omitempty"`
+	TopicPrefix string            `json:"topic_prefix,omitempty"`
+}
+
+// routerGroupsConfig defines the content of router groups configuration file
+type routerGroupsConfig struct {
+	Groups []*RouterGroup `json:"groups"`
+}
+
+// LoadRouterGroups reads router groups configuration from JSON file
+func LoadRouterGroups(file string) ([]*RouterGroup, error) {
+	b, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	c := &routerGroupsConfig{}
+	if err := json.Unmarshal(b, c); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal router groups configuration %s with error: %+v", file, err)
+	}
+	names := make(map[string]bool, len(c.Groups))
+	for i, g := range c.Groups {
+		if g.Name == "" {
+			return nil, fmt.Errorf("router group %d is missing name", i)
+		}
+		if names[g.Name] {
+			return nil, fmt.Errorf("router group %s is defined more than once", g.Name)
+		}
+		names[g.Name] = true
+		if len(g.Sources) == 0 {
+			return nil, fmt.Errorf("router group %s does not define any source", g.Name)
+		}
+		for _, s := range g.Sources {
+			if _, err := parseSource(s); err != nil {
+				return nil, fmt.Errorf("router group %s has invalid source %s with error: %+v", g.Name, s, err)
+			}
+		}
+	}
+
+	return c.Groups, nil
+}
+
+// TopicPrefixes returns topic prefixes of router groups indexed by group names
+func TopicPrefixes(groups []*RouterGroup) map[string]string {
+	prefixes := make(map[string]string)
+	for _, g := range groups {
+		if g.TopicPrefix != "" {
+			prefixes[g.Name] = g.TopicPrefix
+		}
+	}
+
+	return prefixes
+}
+
+// routerGroup defines a router group with its parsed sources and the enricher tagging its messages
+type routerGroup struct {
+	name    string
+	sources []*net.IPNet
+	tagger  *tagger
+}
+
+func newRouterGroups(groups []*RouterGroup) ([]*routerGroup, error) {
+	rgs := make([]*routerGroup, 0, len(groups))
+	for _, g := range groups {
+		rg := &routerGroup{
+			name:    g.Name,
+			sources: make([]*net.IPNet, 0, len(g.Sources)),
+			tagger:  &tagger{tags: make(map[string]string, len(g.Tags)+1)},
+		}
+		for _, s := range g.Sources {
+			n, err := parseSource(s)
+			if err != nil {
+				return nil, fmt.Errorf("router group %s has invalid source %s with error: %+v", g.Name, s, err)
+			}
+			rg.sources = append(rg.sources, n)
+		}
+		for k, v := range g.Tags {
+			rg.tagger.tags[k] = v
+		}
+		rg.tagger.tags[GroupField] = g.Name
+		rgs = append(rgs, rg)
+	}
+
+	return rgs, nil
+}
+
+// matchGroup returns the group of the router, when the router is within sources of several groups,
+// the group with the longest matching source is returned, nil is returned when the router does not
+// belong to any group.
+func matchGroup(groups []*routerGroup, router net.IP) *routerGroup {
+	var match *routerGroup
+	longest := -1
+	for _, g := range groups {
+		for _, n := range g.sources {
+			if !n.Contains(router) {
+				continue
+			}
+			if ones, _ := n.Mask.Size(); ones > longest {
+				match, longest = g, ones
+			}
+		}
+	}
+
+	return match
+}
+
+// sessionEnrichers returns enrichers of BMP session of the router accepted by the listener, the tagger
+// of the router's group is invoked after the listener's enrichers.
+func (srv *bmpServer) sessionEnrichers(l *listener, router net.IP) []enrich.Enricher {
+	g := matchGroup(srv.groups, router)
+	if g == nil {
+		return l.enrichers
+	}
+	e := make([]enrich.Enricher, 0, len(l.enrichers)+1)
+	e = append(e, l.enrichers...)
+
+	return append(e, g.tagger)
+}
@@ -0,0 +1,116 @@
+package gobmpsrv
+
+import (
+	"io/ioutil"
+	"net"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/sbezverk/gobmp/pkg/enrich"
+)
+
+func TestLoadRouterGroups(t *testing.T) {
+	tests := []struct {
+		name   string
+		config string
+		expect []*RouterGroup
+		fail   bool
+	}{
+		{
+			name:   "two groups",
+			config: `{"groups": [{"name": "tenant-a", "sources": ["10.0.0.0/8"], "topic_prefix": "tenant-a"}, {"name": "tenant-b", "sources": ["192.0.2.1"], "tags": {"region": "eu"}}]}`,
+			expect: []*RouterGroup{
+				{Name: "tenant-a", Sources: []string{"10.0.0.0/8"}, TopicPrefix: "tenant-a"},
+				{Name: "tenant-b", Sources: []string{"192.0.2.1"}, Tags: map[string]string{"region": "eu"}},
+			},
+		},
+		{
+			name:   "missing name",
+			config: `{"groups": [{"sources": ["10.0.0.0/8"]}]}`,
+			fail:   true,
+		},
+		{
+			name:   "duplicate name",
+			config: `{"groups": [{"name": "tenant-a", "sources": ["10.0.0.0/8"]}, {"name": "tenant-a", "sources": ["192.0.2.1"]}]}`,
+			fail:   true,
+		},
+		{
+			name:   "missing sources",
+			config: `{"groups": [{"name": "tenant-a"}]}`,
+			fail:   true,
+		},
+		{
+			name:   "invalid source",
+			config: `{"groups": [{"name": "tenant-a", "sources": ["router1"]}]}`,
+			fail:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := filepath.Join(t.TempDir(), "groups.json")
+			if err := ioutil.WriteFile(f, []byte(tt.config), 0644); err != nil {
+				t.Fatalf("failed to write configuration with error: %+v", err)
+			}
+			got, err := LoadRouterGroups(f)
+			if err != nil && !tt.fail {
+				t.Fatalf("expected to succeed but failed with error: %+v", err)
+			}
+			if err == nil && tt.fail {
+				t.Fatalf("expected to fail but succeeded")
+			}
+			if err != nil {
+				return
+			}
+			if !reflect.DeepEqual(tt.expect, got) {
+				t.Errorf("expected router groups %+v do not match loaded %+v", tt.expect, got)
+			}
+		})
+	}
+}
+
+func TestSessionEnrichers(t *testing.T) {
+	groups, err := newRouterGroups([]*RouterGroup{
+		{Name: "tenant-a", Sources: []string{"10.0.0.0/8"}, Tags: map[string]string{"region": "eu"}},
+		{Name: "tenant-b", Sources: []string{"10.1.0.0/16", "2001:db8::/32"}},
+	})
+	if err != nil {
+		t.Fatalf("failed to create router groups with error: %+v", err)
+	}
+	srv := &bmpServer{groups: groups}
+	l := &listener{enrichers: []enrich.Enricher{&tagger{tags: map[string]string{"domain": "core"}}}}
+	tests := []struct {
+		name   string
+		router string
+		expect map[string]interface{}
+	}{
+		{
+			name:   "router of group",
+			router: "10.2.0.1",
+			expect: map[string]interface{}{"domain": "core", "group": "tenant-a", "region": "eu"},
+		},
+		{
+			name:   "longest source wins",
+			router: "10.1.0.1",
+			expect: map[string]interface{}{"domain": "core", "group": "tenant-b"},
+		},
+		{
+			name:   "ipv6 router of group",
+			router: "2001:db8::1",
+			expect: map[string]interface{}{"domain": "core", "group": "tenant-b"},
+		},
+		{
+			name:   "router without group",
+			router: "192.0.2.1",
+			expect: map[string]interface{}{"domain": "core"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := enrich.Enrich(srv.sessionEnrichers(l, net.ParseIP(tt.router)), &enrich.Message{})
+			if !reflect.DeepEqual(got, tt.expect) {
+				t.Errorf("expected enrichment %+v does not match computed %+v", tt.expect, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,104 @@
+package gobmpsrv
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"syscall"
+	"time"
+	"unsafe"
+)
+
+// soReusePort defines SO_REUSEPORT socket option which is missing in syscall package, the value is common
+// to linux architectures except mips and sparc
+const soReusePort = 0xf
+
+// listen opens TCP listening socket, when vrf is not empty, the socket is bound to VRF or interface
+// device with this name. keepAlive is TCP keepalive period of accepted sessions, when reusePort is true,
+// the socket is opened with SO_REUSEPORT, so several sockets accept sessions on the same address.
+func listen(address, vrf string, keepAlive time.Duration, reusePort bool) (net.Listener, error) {
+	lc := net.ListenConfig{
+		KeepAlive: keepAlive,
+		Control: func(network, address string, c syscall.RawConn) error {
+			var err error
+			if cerr := c.Control(func(fd uintptr) {
+				if vrf != "" {
+					if err = syscall.SetsockoptString(int(fd), syscall.SOL_SOCKET, syscall.SO_BINDTODEVICE, vrf); err != nil {
+						return
+					}
+				}
+				if reusePort {
+					err = syscall.SetsockoptInt(int(fd), syscall.SOL_SOCKET, soReusePort, 1)
+				}
+			}); cerr != nil {
+				return cerr
+			}
+			return err
+		},
+	}
+
+	return lc.Listen(context.Background(), "tcp", address)
+}
+
+// tcpMD5SigLength defines the size of linux struct tcp_md5sig, sockaddr_storage (128 bytes), flags,
+// prefix length, key length, interface index and the key (80 bytes)
+const tcpMD5SigLength = 216
+
+// setMD5Keys installs TCP MD5 Signature keys of routers on listening socket, keys is the map of router
+// address and its key, accepted connections inherit the keys.
+func setMD5Keys(l net.Listener, keys map[string]string) error {
+	tl, ok := l.(*net.TCPListener)
+	if !ok {
+		return fmt.Errorf("unsupported listener type %T", l)
+	}
+	rc, err := tl.SyscallConn()
+	if err != nil {
+		return err
+	}
+	var serr error
+	if err := rc.Control(func(fd uintptr) {
+		family, err := syscall.GetsockoptInt(int(fd), syscall.SOL_SOCKET, syscall.SO_DOMAIN)
+		if err != nil {
+			serr = err
+			return
+		}
+		for addr, key := range keys {
+			sig, err := tcpMD5Sig(family, net.ParseIP(addr), key)
+			if err != nil {
+				serr = err
+				return
+			}
+			if err := syscall.SetsockoptString(int(fd), syscall.IPPROTO_TCP, syscall.TCP_MD5SIG, string(sig)); err != nil {
+				serr = fmt.Errorf("failed to set md5 key of router %s with error: %+v", addr, err)
+				return
+			}
+		}
+	}); err != nil {
+		return err
+	}
+
+	return serr
+}
+
+// tcpMD5Sig builds linux struct tcp_md5sig for the router address, IPv4 address of a router is encoded as
+// IPv4-mapped IPv6 address when the listening socket is IPv6 socket.
+func tcpMD5Sig(family int, ip net.IP, key string) ([]byte, error) {
+	b := make([]byte, tcpMD5SigLength)
+	switch {
+	case family == syscall.AF_INET && ip.To4() != nil:
+		// struct sockaddr_in, family is in host byte order
+		*(*uint16)(unsafe.Pointer(&b[0])) = syscall.AF_INET
+		copy(b[4:8], ip.To4())
+	case family == syscall.AF_INET6:
+		// struct sockaddr_in6, family is in host byte order
+		*(*uint16)(unsafe.Pointer(&b[0])) = syscall.AF_INET6
+		copy(b[8:24], ip.To16())
+	default:
+		return nil, fmt.Errorf("router address %s does not match listening socket family %d", ip, family)
+	}
+	// Key length follows sockaddr_storage, flags and prefix length, it is in host byte order
+	*(*uint16)(unsafe.Pointer(&b[130])) = uint16(len(key))
+	copy(b[136:], key)
+
+	return b, nil
+}
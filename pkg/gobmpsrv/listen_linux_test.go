@@ -0,0 +1,65 @@
+package gobmpsrv
+
+import (
+	"net"
+	"testing"
+)
+
+func TestSetMD5Keys(t *testing.T) {
+	tests := []struct {
+		name    string
+		address string
+		keys    map[string]string
+		fail    bool
+	}{
+		{
+			name:    "ipv4 socket",
+			address: "127.0.0.1:0",
+			keys:    map[string]string{"127.0.0.1": "secret"},
+		},
+		{
+			name:    "ipv4 socket with ipv6 router",
+			address: "127.0.0.1:0",
+			keys:    map[string]string{"2001:db8::1": "secret"},
+			fail:    true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l, err := net.Listen("tcp", tt.address)
+			if err != nil {
+				t.Skipf("failed to listen on %s with error: %+v", tt.address, err)
+			}
+			defer l.Close()
+			err = setMD5Keys(l, tt.keys)
+			if err != nil && !tt.fail {
+				t.Fatalf("expected to succeed but failed with error: %+v", err)
+			}
+			if err == nil && tt.fail {
+				t.Fatalf("expected to fail but succeeded")
+			}
+		})
+	}
+}
+
+func TestAcceptors(t *testing.T) {
+	l, err := newListener(&ListenerConfig{
+		Name:          "core",
+		Address:       "127.0.0.1:0",
+		KeepAlive:     30,
+		ReceiveBuffer: 1 << 20,
+		Acceptors:     4,
+	}, nil)
+	if err != nil {
+		t.Skipf("failed to create listener with error: %+v", err)
+	}
+	defer l.close()
+	if len(l.incoming) != 4 {
+		t.Fatalf("expected 4 listening sockets, got %d", len(l.incoming))
+	}
+	for _, in := range l.incoming[1:] {
+		if in.Addr().String() != l.incoming[0].Addr().String() {
+			t.Errorf("expected listening sockets on %s, got %s", l.incoming[0].Addr(), in.Addr())
+		}
+	}
+}
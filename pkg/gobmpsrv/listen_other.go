@@ -0,0 +1,29 @@
+//go:build !linux
+// +build !linux
+
+package gobmpsrv
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"time"
+)
+
+// listen opens TCP listening socket, binding to VRF and SO_REUSEPORT are supported only on linux.
+func listen(address, vrf string, keepAlive time.Duration, reusePort bool) (net.Listener, error) {
+	if vrf != "" {
+		return nil, fmt.Errorf("binding listener to vrf %s is not supported on this platform", vrf)
+	}
+	if reusePort {
+		return nil, fmt.Errorf("multiple acceptors are not supported on this platform")
+	}
+	lc := net.ListenConfig{KeepAlive: keepAlive}
+
+	return lc.Listen(context.Background(), "tcp", address)
+}
+
+// setMD5Keys returns error as TCP MD5 Signature is supported only on linux.
+func setMD5Keys(l net.Listener, keys map[string]string) error {
+	return fmt.Errorf("tcp md5 signature is not supported on this platform")
+}
@@ -0,0 +1,280 @@
+package gobmpsrv
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"os"
+	"time"
+
+	"github.com/sbezverk/gobmp/pkg/enrich"
+)
+
+// ListenerConfig defines BMP listening socket and its policies, Address is host:port to listen on,
+// when VRF is set, the socket is bound to VRF or interface device of this name. Only BMP sessions
+// from AllowedSources prefixes are accepted, empty AllowedSources accepts sessions from any source.
+// When Routers is not empty, only BMP sessions from the listed routers are accepted.
+// Tags are added to "enrichment" object of all messages produced from the listener's sessions.
+// Network is "tcp" (default) or "unix", Address of unix listener is the path of the socket, such listener
+// serves co-located exporters and does not support VRF, AllowedSources, Routers and socket options except
+// ReceiveBuffer. KeepAlive is TCP keepalive period of BMP sessions in seconds, 0 uses the default period and negative
+// value disables keepalives. ReceiveBuffer is the size in bytes of the socket receive buffer of BMP sessions,
+// 0 uses the system default. Acceptors is the number of listening sockets sharing Address with SO_REUSEPORT,
+// each accepting BMP sessions in its own goroutine, supported only on linux.
+type ListenerConfig struct {
+	Name           string            `json:"name,omitempty"`
+	Network        string            `json:"network,omitempty"`
+	Address        string            `json:"address"`
+	VRF            string            `json:"vrf,omitempty"`
+	AllowedSources []string          `json:"allowed_sources,omitempty"`
+	Routers        []*RouterConfig   `json:"routers,omitempty"`
+	Tags           map[string]string `json:"tags,omitempty"`
+	KeepAlive      int               `json:"keepalive,omitempty"`
+	ReceiveBuffer  int               `json:"receive_buffer,omitempty"`
+	Acceptors      int               `json:"acceptors,omitempty"`
+}
+
+// RouterConfig defines a router allowed to establish BMP session, when MD5Key is set, the router's
+// TCP segments must be signed with TCP MD5 Signature Option (RFC 2385), supported only on linux.
+type RouterConfig struct {
+	Address string `json:"address"`
+	MD5Key  string `json:"md5_key,omitempty"`
+}
+
+// maxMD5KeyLength defines the maximum length of TCP MD5 Signature key
+const maxMD5KeyLength = 80
+
+// listenersConfig defines the content of listeners configuration file
+type listenersConfig struct {
+	Listeners []*ListenerConfig `json:"listeners"`
+}
+
+// LoadListeners reads BMP listeners configuration from JSON file
+func LoadListeners(file string) ([]*ListenerConfig, error) {
+	b, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	c := &listenersConfig{}
+	if err := json.Unmarshal(b, c); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal listeners configuration %s with error: %+v", file, err)
+	}
+	if len(c.Listeners) == 0 {
+		return nil, fmt.Errorf("listeners configuration %s does not define any listener", file)
+	}
+	for i, l := range c.Listeners {
+		if l.Address == "" {
+			return nil, fmt.Errorf("listener %d is missing address", i)
+		}
+		if l.Name == "" {
+			l.Name = l.Address
+		}
+		if err := checkSocket(l); err != nil {
+			return nil, err
+		}
+		for _, r := range l.Routers {
+			if net.ParseIP(r.Address) == nil {
+				return nil, fmt.Errorf("listener %s has invalid router address %s", l.Name, r.Address)
+			}
+			if len(r.MD5Key) > maxMD5KeyLength {
+				return nil, fmt.Errorf("listener %s has md5 key of router %s longer than %d", l.Name, r.Address, maxMD5KeyLength)
+			}
+		}
+	}
+
+	return c.Listeners, nil
+}
+
+// checkSocket returns error if socket options of the listener are invalid
+func checkSocket(c *ListenerConfig) error {
+	switch c.Network {
+	case "", "tcp":
+	case "unix":
+		if c.VRF != "" || len(c.AllowedSources) != 0 || len(c.Routers) != 0 || c.KeepAlive != 0 || c.Acceptors > 1 {
+			return fmt.Errorf("unix listener %s supports only receive buffer option", c.Name)
+		}
+	default:
+		return fmt.Errorf("listener %s has unsupported network %s", c.Name, c.Network)
+	}
+	if c.ReceiveBuffer < 0 {
+		return fmt.Errorf("listener %s has negative receive buffer %d", c.Name, c.ReceiveBuffer)
+	}
+	if c.Acceptors < 0 {
+		return fmt.Errorf("listener %s has negative number of acceptors %d", c.Name, c.Acceptors)
+	}
+
+	return nil
+}
+
+// listener defines BMP listening sockets with their policies, all sockets of the listener share its address
+type listener struct {
+	name          string
+	incoming      []net.Listener
+	receiveBuffer int
+	allowed       []*net.IPNet
+	routers       map[string]bool
+	enrichers     []enrich.Enricher
+}
+
+// close closes all listening sockets of the listener
+func (l *listener) close() {
+	for _, in := range l.incoming {
+		in.Close()
+	}
+}
+
+// check returns error if BMP session from addr is not allowed by listener's allowed sources or
+// is not from a known router.
+func (l *listener) check(addr net.Addr) error {
+	if len(l.allowed) == 0 && len(l.routers) == 0 {
+		return nil
+	}
+	tcpAddr, ok := addr.(*net.TCPAddr)
+	if !ok {
+		return fmt.Errorf("unsupported address type %T", addr)
+	}
+	if len(l.allowed) != 0 {
+		allowed := false
+		for _, n := range l.allowed {
+			if n.Contains(tcpAddr.IP) {
+				allowed = true
+				break
+			}
+		}
+		if !allowed {
+			return fmt.Errorf("source %s is not in allowed sources", tcpAddr.IP)
+		}
+	}
+	if len(l.routers) != 0 && !l.routers[normalizeIP(tcpAddr.IP)] {
+		return fmt.Errorf("source %s is not a known router", tcpAddr.IP)
+	}
+
+	return nil
+}
+
+// normalizeIP returns string representation of IP address, IPv4-mapped IPv6 addresses are represented as IPv4
+func normalizeIP(ip net.IP) string {
+	if ip4 := ip.To4(); ip4 != nil {
+		return ip4.String()
+	}
+
+	return ip.String()
+}
+
+// newListener opens listening socket and builds listener's ACL and enrichers, e is the list of enrichment
+// plugins shared by all listeners.
+func newListener(c *ListenerConfig, e []enrich.Enricher) (*listener, error) {
+	allowed := make([]*net.IPNet, 0, len(c.AllowedSources))
+	for _, s := range c.AllowedSources {
+		n, err := parseSource(s)
+		if err != nil {
+			return nil, fmt.Errorf("listener %s has invalid allowed source %s with error: %+v", c.Name, s, err)
+		}
+		allowed = append(allowed, n)
+	}
+	routers := make(map[string]bool, len(c.Routers))
+	keys := make(map[string]string)
+	for _, r := range c.Routers {
+		ip := net.ParseIP(r.Address)
+		if ip == nil {
+			return nil, fmt.Errorf("listener %s has invalid router address %s", c.Name, r.Address)
+		}
+		routers[normalizeIP(ip)] = true
+		if r.MD5Key != "" {
+			keys[normalizeIP(ip)] = r.MD5Key
+		}
+	}
+	if err := checkSocket(c); err != nil {
+		return nil, err
+	}
+	acceptors := c.Acceptors
+	if acceptors == 0 {
+		acceptors = 1
+	}
+	keepAlive := time.Duration(c.KeepAlive) * time.Second
+	if c.KeepAlive < 0 {
+		keepAlive = -1
+	}
+	l := &listener{
+		name:          c.Name,
+		incoming:      make([]net.Listener, 0, acceptors),
+		receiveBuffer: c.ReceiveBuffer,
+	}
+	if c.Network == "unix" {
+		incoming, err := listenUnix(c.Address)
+		if err != nil {
+			return nil, fmt.Errorf("fail to setup listener %s on %s with error: %+v", c.Name, c.Address, err)
+		}
+		l.incoming = append(l.incoming, incoming)
+	} else {
+		address := c.Address
+		for i := 0; i < acceptors; i++ {
+			incoming, err := listen(address, c.VRF, keepAlive, acceptors > 1)
+			if err != nil {
+				l.close()
+				return nil, fmt.Errorf("fail to setup listener %s on %s with error: %+v", c.Name, address, err)
+			}
+			l.incoming = append(l.incoming, incoming)
+			// Following sockets are bound to the port of the first one, when Address has port 0
+			address = incoming.Addr().String()
+			if len(keys) != 0 {
+				if err := setMD5Keys(incoming, keys); err != nil {
+					l.close()
+					return nil, fmt.Errorf("fail to set md5 keys of listener %s with error: %+v", c.Name, err)
+				}
+			}
+		}
+	}
+	enrichers := e
+	if len(c.Tags) != 0 {
+		enrichers = make([]enrich.Enricher, 0, len(e)+1)
+		enrichers = append(enrichers, e...)
+		enrichers = append(enrichers, &tagger{tags: c.Tags})
+	}
+
+	l.allowed, l.routers, l.enrichers = allowed, routers, enrichers
+
+	return l, nil
+}
+
+// listenUnix opens unix listening socket, a socket file left by a previous run is removed
+func listenUnix(path string) (net.Listener, error) {
+	if fi, err := os.Stat(path); err == nil && fi.Mode()&os.ModeSocket != 0 {
+		if err := os.Remove(path); err != nil {
+			return nil, err
+		}
+	}
+
+	return net.Listen("unix", path)
+}
+
+// parseSource returns network of allowed source, a source is either a prefix or a single address
+func parseSource(s string) (*net.IPNet, error) {
+	if _, n, err := net.ParseCIDR(s); err == nil {
+		return n, nil
+	}
+	ip := net.ParseIP(s)
+	if ip == nil {
+		return nil, fmt.Errorf("not a prefix or an address")
+	}
+	if ip.To4() != nil {
+		return &net.IPNet{IP: ip.To4(), Mask: net.CIDRMask(32, 32)}, nil
+	}
+
+	return &net.IPNet{IP: ip, Mask: net.CIDRMask(128, 128)}, nil
+}
+
+// tagger is enrichment plugin adding listener's tags to all messages
+type tagger struct {
+	tags map[string]string
+}
+
+func (t *tagger) Enrich(msg *enrich.Message) map[string]interface{} {
+	f := make(map[string]interface{}, len(t.tags))
+	for k, v := range t.tags {
+		f[k] = v
+	}
+
+	return f
+}
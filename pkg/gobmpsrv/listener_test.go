@@ -0,0 +1,142 @@
+package gobmpsrv
+
+import (
+	"io/ioutil"
+	"net"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/sbezverk/gobmp/pkg/enrich"
+)
+
+func TestLoadListeners(t *testing.T) {
+	tests := []struct {
+		name   string
+		config string
+		expect []*ListenerConfig
+		fail   bool
+	}{
+		{
+			name:   "two listeners",
+			config: `{"listeners": [{"address": ":5000", "allowed_sources": ["10.0.0.0/8"]}, {"name": "customer", "address": ":5001", "vrf": "mgmt", "tags": {"domain": "customer"}}]}`,
+			expect: []*ListenerConfig{
+				{Name: ":5000", Address: ":5000", AllowedSources: []string{"10.0.0.0/8"}},
+				{Name: "customer", Address: ":5001", VRF: "mgmt", Tags: map[string]string{"domain": "customer"}},
+			},
+		},
+		{
+			name:   "socket options",
+			config: `{"listeners": [{"address": ":5000", "keepalive": 30, "receive_buffer": 4194304, "acceptors": 4}]}`,
+			expect: []*ListenerConfig{
+				{Name: ":5000", Address: ":5000", KeepAlive: 30, ReceiveBuffer: 4194304, Acceptors: 4},
+			},
+		},
+		{
+			name:   "negative acceptors",
+			config: `{"listeners": [{"address": ":5000", "acceptors": -1}]}`,
+			fail:   true,
+		},
+		{
+			name:   "invalid router address",
+			config: `{"listeners": [{"address": ":5000", "routers": [{"address": "router1"}]}]}`,
+			fail:   true,
+		},
+		{
+			name:   "no listeners",
+			config: `{"listeners": []}`,
+			fail:   true,
+		},
+		{
+			name:   "missing address",
+			config: `{"listeners": [{"name": "core"}]}`,
+			fail:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := filepath.Join(t.TempDir(), "listeners.json")
+			if err := ioutil.WriteFile(f, []byte(tt.config), 0644); err != nil {
+				t.Fatalf("failed to write configuration with error: %+v", err)
+			}
+			got, err := LoadListeners(f)
+			if err != nil && !tt.fail {
+				t.Fatalf("expected to succeed but failed with error: %+v", err)
+			}
+			if err == nil && tt.fail {
+				t.Fatalf("expected to fail but succeeded")
+			}
+			if err != nil {
+				return
+			}
+			if !reflect.DeepEqual(tt.expect, got) {
+				t.Fatalf("expected listeners %+v, got %+v", tt.expect, got)
+			}
+		})
+	}
+}
+
+func TestListenerPolicies(t *testing.T) {
+	l, err := newListener(&ListenerConfig{
+		Name:           "core",
+		Address:        "127.0.0.1:0",
+		AllowedSources: []string{"10.0.0.0/8", "192.0.2.1"},
+		Routers: []*RouterConfig{
+			{Address: "10.1.1.1"},
+			{Address: "192.0.2.1"},
+			{Address: "2001:db8::1"},
+		},
+		Tags: map[string]string{"domain": "core"},
+	}, nil)
+	if err != nil {
+		t.Fatalf("failed to create listener with error: %+v", err)
+	}
+	defer l.close()
+	tests := []struct {
+		addr    net.Addr
+		allowed bool
+	}{
+		{addr: &net.TCPAddr{IP: net.ParseIP("10.1.1.1"), Port: 30000}, allowed: true},
+		{addr: &net.TCPAddr{IP: net.ParseIP("::ffff:10.1.1.1"), Port: 30000}, allowed: true},
+		{addr: &net.TCPAddr{IP: net.ParseIP("192.0.2.1"), Port: 30000}, allowed: true},
+		// In allowed sources, but not a known router
+		{addr: &net.TCPAddr{IP: net.ParseIP("10.1.1.2"), Port: 30000}, allowed: false},
+		{addr: &net.TCPAddr{IP: net.ParseIP("192.0.2.2"), Port: 30000}, allowed: false},
+		// Known router, but not in allowed sources
+		{addr: &net.TCPAddr{IP: net.ParseIP("2001:db8::1"), Port: 30000}, allowed: false},
+	}
+	for _, tt := range tests {
+		if err := l.check(tt.addr); (err == nil) != tt.allowed {
+			t.Errorf("expected %s allowed %t, got error: %+v", tt.addr, tt.allowed, err)
+		}
+	}
+	fields := enrich.Enrich(l.enrichers, &enrich.Message{})
+	if !reflect.DeepEqual(fields, map[string]interface{}{"domain": "core"}) {
+		t.Errorf("expected listener tags, got %+v", fields)
+	}
+}
+
+func TestUnixListener(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "bmp.sock")
+	l, err := newListener(&ListenerConfig{Name: "unix", Network: "unix", Address: path}, nil)
+	if err != nil {
+		t.Fatalf("failed to create listener with error: %+v", err)
+	}
+	defer l.close()
+	conn, err := net.Dial("unix", path)
+	if err != nil {
+		t.Fatalf("failed to connect to %s with error: %+v", path, err)
+	}
+	defer conn.Close()
+	client, err := l.incoming[0].Accept()
+	if err != nil {
+		t.Fatalf("failed to accept connection with error: %+v", err)
+	}
+	defer client.Close()
+	if err := l.check(client.RemoteAddr()); err != nil {
+		t.Errorf("expected session to be allowed, got error: %+v", err)
+	}
+	if _, err := newListener(&ListenerConfig{Name: "unix", Network: "unix", Address: path, VRF: "mgmt"}, nil); err == nil {
+		t.Errorf("expected unix listener with vrf to fail")
+	}
+}
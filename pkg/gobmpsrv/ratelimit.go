@@ -0,0 +1,114 @@
+package gobmpsrv
+
+import (
+	"sync"
+	"time"
+)
+
+// RateLimiter limits ingestion rate of BMP sessions, each session is limited by per session rate and
+// by its fair share of total rate, the share is total rate divided by the number of active sessions.
+type RateLimiter interface {
+	// NewSession registers a new BMP session and returns its limiter
+	NewSession() SessionLimiter
+}
+
+// SessionLimiter limits ingestion rate of a single BMP session
+type SessionLimiter interface {
+	// Wait blocks until the session is allowed to process the next message
+	Wait()
+	// Close releases session's share of total rate
+	Close()
+}
+
+type rateLimiter struct {
+	sync.Mutex
+	session  float64
+	total    float64
+	sessions int
+	now      func() time.Time
+	sleep    func(time.Duration)
+}
+
+var _ RateLimiter = &rateLimiter{}
+
+// NewRateLimiter instantiates a rate limiter, session is the maximum number of messages per second of
+// a single BMP session and total is the maximum number of messages per second of all sessions,
+// 0 disables the corresponding limit.
+func NewRateLimiter(session, total int) RateLimiter {
+	return &rateLimiter{
+		session: float64(session),
+		total:   float64(total),
+		now:     time.Now,
+		sleep:   time.Sleep,
+	}
+}
+
+func (r *rateLimiter) NewSession() SessionLimiter {
+	r.Lock()
+	defer r.Unlock()
+	r.sessions++
+
+	return &sessionLimiter{r: r}
+}
+
+// rate returns the current rate of a session, 0 means the session is not limited
+func (r *rateLimiter) rate() float64 {
+	r.Lock()
+	defer r.Unlock()
+	rate := r.session
+	if r.total > 0 && r.sessions > 0 {
+		if share := r.total / float64(r.sessions); rate == 0 || share < rate {
+			rate = share
+		}
+	}
+
+	return rate
+}
+
+func (r *rateLimiter) release() {
+	r.Lock()
+	defer r.Unlock()
+	r.sessions--
+}
+
+// sessionLimiter is a token bucket with capacity of one second worth of messages
+type sessionLimiter struct {
+	r      *rateLimiter
+	tokens float64
+	last   time.Time
+	once   sync.Once
+}
+
+func (s *sessionLimiter) Wait() {
+	rate := s.r.rate()
+	if rate == 0 {
+		return
+	}
+	burst := rate
+	if burst < 1 {
+		burst = 1
+	}
+	now := s.r.now()
+	if s.last.IsZero() {
+		s.tokens = burst
+	} else {
+		s.tokens += now.Sub(s.last).Seconds() * rate
+		if s.tokens > burst {
+			s.tokens = burst
+		}
+	}
+	s.last = now
+	if s.tokens >= 1 {
+		s.tokens--
+		return
+	}
+	// Waiting for the missing part of the token
+	d := time.Duration((1 - s.tokens) / rate * float64(time.Second))
+	s.r.sleep(d)
+	s.tokens = 0
+	s.last = now.Add(d)
+}
+
+func (s *sessionLimiter) Close() {
+	s.once.Do(s.r.release)
+}
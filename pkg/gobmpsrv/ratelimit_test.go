@@ -0,0 +1,73 @@
+package gobmpsrv
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRateLimiter(t *testing.T) {
+	tests := []struct {
+		name     string
+		session  int
+		total    int
+		sessions int
+		messages int
+		expect   time.Duration
+	}{
+		{
+			name:     "session limit",
+			session:  10,
+			sessions: 1,
+			// The first 10 messages are the burst, the rest is 0.1s each
+			messages: 30,
+			expect:   2 * time.Second,
+		},
+		{
+			name:     "fair share of total limit",
+			total:    20,
+			sessions: 2,
+			messages: 30,
+			expect:   2 * time.Second,
+		},
+		{
+			name:     "session limit lower than fair share",
+			session:  5,
+			total:    100,
+			sessions: 2,
+			messages: 15,
+			expect:   2 * time.Second,
+		},
+		{
+			name:     "no limits",
+			sessions: 1,
+			messages: 1000,
+			expect:   0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			now := time.Unix(0, 0)
+			r := NewRateLimiter(tt.session, tt.total).(*rateLimiter)
+			r.now = func() time.Time { return now }
+			r.sleep = func(d time.Duration) { now = now.Add(d) }
+			limiters := make([]SessionLimiter, tt.sessions)
+			for i := range limiters {
+				limiters[i] = r.NewSession()
+			}
+			for i := 0; i < tt.messages; i++ {
+				limiters[0].Wait()
+			}
+			if elapsed := now.Sub(time.Unix(0, 0)); elapsed < tt.expect-time.Millisecond || elapsed > tt.expect+time.Millisecond {
+				t.Fatalf("expected %s to process %d messages, took %s", tt.expect, tt.messages, elapsed)
+			}
+			for _, l := range limiters {
+				l.Close()
+				// Close is idempotent
+				l.Close()
+			}
+			if r.sessions != 0 {
+				t.Fatalf("expected no active sessions, got %d", r.sessions)
+			}
+		})
+	}
+}
@@ -0,0 +1,78 @@
+package gobmpsrv
+
+import (
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// SessionInfo defines a BMP session served by the server, Listener is empty for sessions served from
+// a stream, for example stdin or Kafka.
+type SessionInfo struct {
+	Name     string    `json:"name"`
+	Listener string    `json:"listener,omitempty"`
+	Remote   string    `json:"remote,omitempty"`
+	Started  time.Time `json:"started"`
+	Messages uint64    `json:"messages"`
+}
+
+type session struct {
+	// messages is first to be 64 bit aligned for atomic access
+	messages uint64
+	info     SessionInfo
+}
+
+func (s *session) received() {
+	atomic.AddUint64(&s.messages, 1)
+}
+
+type sessions struct {
+	mtx sync.Mutex
+	m   map[*session]bool
+}
+
+func (ss *sessions) add(name, listener, remote string) *session {
+	s := &session{
+		info: SessionInfo{
+			Name:     name,
+			Listener: listener,
+			Remote:   remote,
+			Started:  time.Now(),
+		},
+	}
+	ss.mtx.Lock()
+	defer ss.mtx.Unlock()
+	if ss.m == nil {
+		ss.m = make(map[*session]bool)
+	}
+	ss.m[s] = true
+
+	return s
+}
+
+func (ss *sessions) remove(s *session) {
+	ss.mtx.Lock()
+	defer ss.mtx.Unlock()
+	delete(ss.m, s)
+}
+
+// list returns active sessions sorted by their start
+func (ss *sessions) list() []*SessionInfo {
+	ss.mtx.Lock()
+	infos := make([]*SessionInfo, 0, len(ss.m))
+	for s := range ss.m {
+		info := s.info
+		info.Messages = atomic.LoadUint64(&s.messages)
+		infos = append(infos, &info)
+	}
+	ss.mtx.Unlock()
+	sort.Slice(infos, func(i, j int) bool {
+		if infos[i].Started.Equal(infos[j].Started) {
+			return infos[i].Name < infos[j].Name
+		}
+		return infos[i].Started.Before(infos[j].Started)
+	})
+
+	return infos
+}
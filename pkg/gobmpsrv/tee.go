@@ -0,0 +1,211 @@
+package gobmpsrv
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/golang/glog"
+)
+
+// dialTimeout defines the time to establish connection to intercept destination
+const dialTimeout = 5 * time.Second
+
+// Tee forwards exact frames of BMP messages received in intercept mode to downstream collectors. Every BMP session
+// has its own connection and queue per destination, a slow or failed destination neither delays the session nor
+// other destinations, messages not fitting the destination's queue are dropped. Data sent by the first destination
+// is relayed back to the router, so the first destination sees the session as if it was connected directly.
+type Tee interface {
+	// Stats returns forwarding statistics of all destinations
+	Stats() []*TeeStats
+	open(router net.Conn) *teeSession
+}
+
+// TeeStats defines forwarding statistics of intercept destination, latency is the time from reading a message
+// from the router to writing it to the destination.
+type TeeStats struct {
+	Destination  string `json:"destination"`
+	Sessions     int    `json:"sessions"`
+	Forwarded    uint64 `json:"forwarded"`
+	Dropped      uint64 `json:"dropped"`
+	Failures     uint64 `json:"failures"`
+	LatencyAvgUs int64  `json:"latency_avg_us"`
+	LatencyMaxUs int64  `json:"latency_max_us"`
+}
+
+// destination defines intercept destination with its statistics, latency is the sum of latencies of
+// forwarded messages.
+type destination struct {
+	sync.Mutex
+	stats   TeeStats
+	latency time.Duration
+}
+
+type tee struct {
+	destinations []*destination
+	queue        int
+}
+
+var _ Tee = &tee{}
+
+func (t *tee) Stats() []*TeeStats {
+	stats := make([]*TeeStats, 0, len(t.destinations))
+	for _, d := range t.destinations {
+		d.Lock()
+		s := d.stats
+		if s.Forwarded != 0 {
+			s.LatencyAvgUs = int64(d.latency/time.Microsecond) / int64(s.Forwarded)
+		}
+		d.Unlock()
+		stats = append(stats, &s)
+	}
+
+	return stats
+}
+
+func (d *destination) update(f func(s *TeeStats)) {
+	d.Lock()
+	defer d.Unlock()
+	f(&d.stats)
+}
+
+func (d *destination) forwarded(latency time.Duration) {
+	d.Lock()
+	defer d.Unlock()
+	d.stats.Forwarded++
+	d.latency += latency
+	if us := int64(latency / time.Microsecond); us > d.stats.LatencyMaxUs {
+		d.stats.LatencyMaxUs = us
+	}
+}
+
+// frame defines a BMP message as it was received from the router
+type frame struct {
+	b        []byte
+	received time.Time
+}
+
+// teeTarget defines connection of BMP session to intercept destination
+type teeTarget struct {
+	d     *destination
+	conn  net.Conn
+	queue chan *frame
+}
+
+// teeSession defines connections of BMP session to all reachable intercept destinations
+type teeSession struct {
+	targets []*teeTarget
+}
+
+func (t *tee) open(router net.Conn) *teeSession {
+	s := &teeSession{targets: make([]*teeTarget, 0, len(t.destinations))}
+	for i, d := range t.destinations {
+		conn, err := net.DialTimeout("tcp", d.stats.Destination, dialTimeout)
+		if err != nil {
+			glog.Errorf("failed to connect to intercept destination %s for client %+v with error: %+v", d.stats.Destination, router.RemoteAddr(), err)
+			d.update(func(s *TeeStats) { s.Failures++ })
+			continue
+		}
+		glog.V(5).Infof("connection to intercept destination %v established, start intercepting client %+v", conn.RemoteAddr(), router.RemoteAddr())
+		d.update(func(s *TeeStats) { s.Sessions++ })
+		tt := &teeTarget{d: d, conn: conn, queue: make(chan *frame, t.queue)}
+		s.targets = append(s.targets, tt)
+		go tt.write()
+		if i == 0 {
+			go relay(conn, router)
+		}
+	}
+
+	return s
+}
+
+// forward queues the message to all destinations, the message is dropped for destinations with full queue
+func (s *teeSession) forward(b []byte, received time.Time) {
+	f := &frame{b: b, received: received}
+	for _, t := range s.targets {
+		select {
+		case t.queue <- f:
+		default:
+			t.d.update(func(s *TeeStats) { s.Dropped++ })
+		}
+	}
+}
+
+// close closes queues of all destinations, connections are closed when queued messages are written
+func (s *teeSession) close() {
+	for _, t := range s.targets {
+		close(t.queue)
+	}
+}
+
+// write writes queued messages to the destination, after a failed write remaining messages are dropped
+func (t *teeTarget) write() {
+	defer func() {
+		t.conn.Close()
+		t.d.update(func(s *TeeStats) { s.Sessions-- })
+	}()
+	failed := false
+	for f := range t.queue {
+		if failed {
+			t.d.update(func(s *TeeStats) { s.Dropped++ })
+			continue
+		}
+		if _, err := t.conn.Write(f.b); err != nil {
+			glog.Errorf("fail to write to intercept destination %+v with error: %+v", t.conn.RemoteAddr(), err)
+			t.d.update(func(s *TeeStats) { s.Failures++; s.Dropped++ })
+			failed = true
+			continue
+		}
+		t.d.forwarded(time.Since(f.received))
+	}
+}
+
+// relay copies data sent by the destination to the router until either connection is closed
+func relay(from, to net.Conn) {
+	if _, err := io.Copy(to, from); err != nil {
+		glog.V(5).Infof("stopped relaying from intercept destination %+v to client %+v with error: %+v", from.RemoteAddr(), to.RemoteAddr(), err)
+	}
+}
+
+// NewTeeHandler returns http handler returning forwarding statistics of intercept destinations:
+//
+//	GET /debug/intercept
+func NewTeeHandler(t Tee) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(t.Stats()); err != nil {
+			glog.Errorf("failed to send intercept statistics with error: %+v", err)
+		}
+	})
+}
+
+// NewTee instantiates a new instance of Tee forwarding BMP messages to destinations, host:port addresses
+// of downstream collectors, queue is the number of messages buffered per destination of a BMP session.
+func NewTee(destinations []string, queue int) (Tee, error) {
+	if len(destinations) == 0 {
+		return nil, fmt.Errorf("no intercept destinations")
+	}
+	if queue <= 0 {
+		return nil, fmt.Errorf("invalid intercept queue %d, must be positive", queue)
+	}
+	t := &tee{
+		destinations: make([]*destination, 0, len(destinations)),
+		queue:        queue,
+	}
+	for _, addr := range destinations {
+		if _, _, err := net.SplitHostPort(addr); err != nil {
+			return nil, fmt.Errorf("invalid intercept destination %s with error: %+v", addr, err)
+		}
+		t.destinations = append(t.destinations, &destination{stats: TeeStats{Destination: addr}})
+	}
+
+	return t, nil
+}
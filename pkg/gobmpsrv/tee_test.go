@@ -0,0 +1,99 @@
+package gobmpsrv
+
+import (
+	"bytes"
+	"io"
+	"net"
+	"testing"
+	"time"
+)
+
+func TestNewTee(t *testing.T) {
+	tests := []struct {
+		name         string
+		destinations []string
+		queue        int
+		fail         bool
+	}{
+		{
+			name:         "two destinations",
+			destinations: []string{":5050", "192.0.2.1:5000"},
+			queue:        10,
+		},
+		{
+			name:  "no destinations",
+			queue: 10,
+			fail:  true,
+		},
+		{
+			name:         "destination without port",
+			destinations: []string{"192.0.2.1"},
+			queue:        10,
+			fail:         true,
+		},
+		{
+			name:         "zero queue",
+			destinations: []string{":5050"},
+			fail:         true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewTee(tt.destinations, tt.queue)
+			if err != nil && !tt.fail {
+				t.Fatalf("expected to succeed but failed with error: %+v", err)
+			}
+			if err == nil && tt.fail {
+				t.Fatalf("expected to fail but succeeded")
+			}
+		})
+	}
+}
+
+func TestTee(t *testing.T) {
+	listeners := make([]net.Listener, 2)
+	destinations := make([]string, 2)
+	for i := range listeners {
+		l, err := net.Listen("tcp", "127.0.0.1:0")
+		if err != nil {
+			t.Skipf("failed to listen with error: %+v", err)
+		}
+		defer l.Close()
+		listeners[i], destinations[i] = l, l.Addr().String()
+	}
+	tee, err := NewTee(destinations, 10)
+	if err != nil {
+		t.Fatalf("failed to create tee with error: %+v", err)
+	}
+	router, client := net.Pipe()
+	defer router.Close()
+	defer client.Close()
+	s := tee.open(client)
+	frames := [][]byte{{3, 0, 0, 0, 6, 4}, {3, 0, 0, 0, 7, 5, 1}}
+	for _, f := range frames {
+		s.forward(f, time.Now())
+	}
+	s.close()
+	expect := bytes.Join(frames, nil)
+	conns := make([]net.Conn, len(listeners))
+	for i, l := range listeners {
+		conn, err := l.Accept()
+		if err != nil {
+			t.Fatalf("failed to accept connection with error: %+v", err)
+		}
+		defer conn.Close()
+		conns[i] = conn
+		got, err := io.ReadAll(conn)
+		if err != nil {
+			t.Fatalf("failed to read forwarded messages with error: %+v", err)
+		}
+		if !bytes.Equal(got, expect) {
+			t.Errorf("expected destination %s to receive %v, got %v", destinations[i], expect, got)
+		}
+	}
+	for i, st := range tee.Stats() {
+		if st.Destination != destinations[i] || st.Forwarded != uint64(len(frames)) || st.Dropped != 0 {
+			t.Errorf("unexpected statistics of destination %s: %+v", destinations[i], st)
+		}
+	}
+}
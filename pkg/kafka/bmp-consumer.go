@@ -0,0 +1,178 @@
+package kafka
+
+import (
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/Shopify/sarama"
+	"github.com/golang/glog"
+	"github.com/sbezverk/gobmp/pkg/logging"
+)
+
+// ServeFunc serves BMP session read from r until the end of the stream, name identifies the session
+type ServeFunc func(name string, r io.Reader) error
+
+// BMPConsumer defines methods of the consumer of raw BMP messages published to Kafka by forwarders
+type BMPConsumer interface {
+	Stop()
+}
+
+// consumedSession defines BMP session of a router which messages are consumed from Kafka, consumed records
+// are written to the pipe read by the session.
+type consumedSession struct {
+	w    *io.PipeWriter
+	done chan struct{}
+}
+
+type bmpConsumer struct {
+	sync.Mutex
+	consumer   sarama.Consumer
+	partitions []sarama.PartitionConsumer
+	serve      ServeFunc
+	sessions   map[string]*consumedSession
+	wg         sync.WaitGroup
+}
+
+// session returns BMP session of the router, a new session is started for the first record of the router
+func (c *bmpConsumer) session(name string) *consumedSession {
+	c.Lock()
+	defer c.Unlock()
+	if s, ok := c.sessions[name]; ok {
+		return s
+	}
+	r, w := io.Pipe()
+	s := &consumedSession{
+		w:    w,
+		done: make(chan struct{}),
+	}
+	c.sessions[name] = s
+	go func() {
+		defer close(s.done)
+		err := c.serve(name, r)
+		if err != nil {
+			glog.Errorf("BMP session %s consumed from Kafka failed with error: %+v", name, err)
+		}
+		// Unblocking writes of records of the failed session
+		r.CloseWithError(fmt.Errorf("BMP session %s ended", name))
+	}()
+
+	return s
+}
+
+// endSession removes failed BMP session, the next record of the router starts a new session
+func (c *bmpConsumer) endSession(name string, s *consumedSession) {
+	c.Lock()
+	defer c.Unlock()
+	if c.sessions[name] == s {
+		delete(c.sessions, name)
+	}
+}
+
+func (c *bmpConsumer) consumePartition(topic string, pc sarama.PartitionConsumer) {
+	defer c.wg.Done()
+	for msg := range pc.Messages() {
+		name := sessionName(msg)
+		s := c.session(name)
+		if _, err := s.w.Write(msg.Value); err != nil {
+			// The session failed to parse the stream, BMP messages of the router resume in a new session
+			glog.Warningf("restarting BMP session %s consumed from Kafka topic %s partition %d at offset %d", name, topic, msg.Partition, msg.Offset)
+			c.endSession(name, s)
+			if _, err := c.session(name).w.Write(msg.Value); err != nil {
+				glog.Errorf("failed to process record of BMP session %s at offset %d with error: %+v", name, msg.Offset, err)
+			}
+		}
+	}
+}
+
+// sessionName returns the name of BMP session of the record, records are keyed by router's address by
+// forwarders, records without key are served by a session per partition.
+func sessionName(msg *sarama.ConsumerMessage) string {
+	if len(msg.Key) != 0 {
+		return string(msg.Key)
+	}
+
+	return fmt.Sprintf("%s-%d", msg.Topic, msg.Partition)
+}
+
+func (c *bmpConsumer) Stop() {
+	for _, pc := range c.partitions {
+		pc.AsyncClose()
+	}
+	c.wg.Wait()
+	c.Lock()
+	sessions := make([]*consumedSession, 0, len(c.sessions))
+	for _, s := range c.sessions {
+		sessions = append(sessions, s)
+		// Sessions end at the end of the stream
+		s.w.Close()
+	}
+	c.Unlock()
+	for _, s := range sessions {
+		<-s.done
+	}
+	if err := c.consumer.Close(); err != nil {
+		glog.Errorf("failed to close Kafka consumer with error: %+v", err)
+	}
+}
+
+func newBMPConsumer(consumer sarama.Consumer, topic string, offset int64, serve ServeFunc) (BMPConsumer, error) {
+	partitions, err := consumer.Partitions(topic)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get partitions of topic %s with error: %+v", topic, err)
+	}
+	c := &bmpConsumer{
+		consumer:   consumer,
+		partitions: make([]sarama.PartitionConsumer, 0, len(partitions)),
+		serve:      serve,
+		sessions:   make(map[string]*consumedSession),
+	}
+	for _, p := range partitions {
+		pc, err := consumer.ConsumePartition(topic, p, offset)
+		if err != nil {
+			for _, o := range c.partitions {
+				o.Close()
+			}
+			return nil, fmt.Errorf("failed to consume partition %d of topic %s with error: %+v", p, topic, err)
+		}
+		c.partitions = append(c.partitions, pc)
+		c.wg.Add(1)
+		go c.consumePartition(topic, pc)
+	}
+	logging.V(logging.Kafka, 5).Infof("Consuming BMP messages from %d partitions of topic %s", len(partitions), topic)
+
+	return c, nil
+}
+
+// NewBMPConsumer instantiates a new instance of the consumer of raw BMP messages published to all partitions
+// of the topic by forwarders, records are keyed by the router and carry BMP messages as received from the router,
+// records of each router are served in order by serve as a single BMP session. When oldest is true, partitions
+// are consumed from the oldest retained record, otherwise only records published after the start are consumed.
+func NewBMPConsumer(kafkaSrv string, topic string, oldest bool, serve ServeFunc) (BMPConsumer, error) {
+	glog.Infof("Initializing Kafka consumer of BMP messages")
+	if err := validator(kafkaSrv); err != nil {
+		glog.Errorf("Failed to validate Kafka server address %s with error: %+v", kafkaSrv, err)
+		return nil, err
+	}
+	if topic == "" {
+		return nil, fmt.Errorf("topic of BMP messages is not set")
+	}
+	config := sarama.NewConfig()
+	config.ClientID = "gobmp"
+	offset := sarama.OffsetNewest
+	if oldest {
+		offset = sarama.OffsetOldest
+	}
+	config.Consumer.Offsets.Initial = offset
+	consumer, err := sarama.NewConsumer([]string{kafkaSrv}, config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start Kafka consumer with error: %+v", err)
+	}
+	c, err := newBMPConsumer(consumer, topic, offset, serve)
+	if err != nil {
+		consumer.Close()
+		return nil, err
+	}
+
+	return c, nil
+}
@@ -0,0 +1,163 @@
+package kafka
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"sync"
+	"testing"
+
+	"github.com/Shopify/sarama"
+	"github.com/go-test/deep"
+)
+
+type fakePartitionConsumer struct {
+	messages chan *sarama.ConsumerMessage
+	once     sync.Once
+}
+
+func (f *fakePartitionConsumer) AsyncClose() {
+	f.once.Do(func() { close(f.messages) })
+}
+
+func (f *fakePartitionConsumer) Close() error {
+	f.AsyncClose()
+	return nil
+}
+
+func (f *fakePartitionConsumer) Messages() <-chan *sarama.ConsumerMessage {
+	return f.messages
+}
+
+func (f *fakePartitionConsumer) Errors() <-chan *sarama.ConsumerError {
+	return nil
+}
+
+func (f *fakePartitionConsumer) HighWaterMarkOffset() int64 {
+	return 0
+}
+
+// fakeConsumer is a consumer of a topic with records already published to its partitions
+type fakeConsumer struct {
+	partitions map[int32]*fakePartitionConsumer
+	closed     bool
+}
+
+func (f *fakeConsumer) Topics() ([]string, error) {
+	return []string{"gobmp.raw"}, nil
+}
+
+func (f *fakeConsumer) Partitions(topic string) ([]int32, error) {
+	partitions := make([]int32, 0, len(f.partitions))
+	for p := range f.partitions {
+		partitions = append(partitions, p)
+	}
+
+	return partitions, nil
+}
+
+func (f *fakeConsumer) ConsumePartition(topic string, partition int32, offset int64) (sarama.PartitionConsumer, error) {
+	pc, ok := f.partitions[partition]
+	if !ok {
+		return nil, fmt.Errorf("partition %d does not exist", partition)
+	}
+
+	return pc, nil
+}
+
+func (f *fakeConsumer) HighWaterMarks() map[string]map[int32]int64 {
+	return nil
+}
+
+func (f *fakeConsumer) Close() error {
+	f.closed = true
+	return nil
+}
+
+func newFakeConsumer(records map[int32][]*sarama.ConsumerMessage) *fakeConsumer {
+	f := &fakeConsumer{partitions: make(map[int32]*fakePartitionConsumer)}
+	for p, rs := range records {
+		pc := &fakePartitionConsumer{messages: make(chan *sarama.ConsumerMessage, len(rs))}
+		for i, r := range rs {
+			r.Topic, r.Partition, r.Offset = "gobmp.raw", p, int64(i)
+			pc.messages <- r
+		}
+		f.partitions[p] = pc
+	}
+
+	return f
+}
+
+func TestBMPConsumer(t *testing.T) {
+	f := newFakeConsumer(map[int32][]*sarama.ConsumerMessage{
+		0: {
+			{Key: []byte("192.0.2.1"), Value: []byte{3, 0, 0}},
+			{Key: []byte("192.0.2.2"), Value: []byte{3, 0, 0, 0, 6, 4}},
+			// BMP message of the router spans multiple records
+			{Key: []byte("192.0.2.1"), Value: []byte{0, 6, 4}},
+		},
+		1: {
+			{Value: []byte{3, 0, 0, 0, 6, 5}},
+		},
+	})
+	mtx := sync.Mutex{}
+	served := make(map[string][]byte)
+	serve := func(name string, r io.Reader) error {
+		b, err := ioutil.ReadAll(r)
+		mtx.Lock()
+		served[name] = b
+		mtx.Unlock()
+		return err
+	}
+	c, err := newBMPConsumer(f, "gobmp.raw", sarama.OffsetOldest, serve)
+	if err != nil {
+		t.Fatalf("failed to create consumer with error: %+v", err)
+	}
+	// Partition consumers are closed after all records are consumed, the sessions end at the end of the stream
+	c.Stop()
+	expect := map[string][]byte{
+		"192.0.2.1":   {3, 0, 0, 0, 6, 4},
+		"192.0.2.2":   {3, 0, 0, 0, 6, 4},
+		"gobmp.raw-1": {3, 0, 0, 0, 6, 5},
+	}
+	if diff := deep.Equal(served, expect); len(diff) != 0 {
+		t.Errorf("expected and actual served sessions do not match, differences: %+v", diff)
+	}
+	if !f.closed {
+		t.Errorf("expected consumer to be closed")
+	}
+}
+
+func TestBMPConsumerRestartSession(t *testing.T) {
+	f := newFakeConsumer(map[int32][]*sarama.ConsumerMessage{
+		0: {
+			{Key: []byte("192.0.2.1"), Value: []byte{1}},
+			{Key: []byte("192.0.2.1"), Value: []byte{3, 0, 0, 0, 6, 4}},
+		},
+	})
+	mtx := sync.Mutex{}
+	sessions := make([][]byte, 0)
+	serve := func(name string, r io.Reader) error {
+		b := make([]byte, 1)
+		if _, err := io.ReadFull(r, b); err != nil {
+			return err
+		}
+		if b[0] != 3 {
+			// Invalid BMP version fails the session
+			return fmt.Errorf("invalid version %d", b[0])
+		}
+		rest, err := ioutil.ReadAll(r)
+		mtx.Lock()
+		sessions = append(sessions, append(b, rest...))
+		mtx.Unlock()
+		return err
+	}
+	c, err := newBMPConsumer(f, "gobmp.raw", sarama.OffsetOldest, serve)
+	if err != nil {
+		t.Fatalf("failed to create consumer with error: %+v", err)
+	}
+	c.Stop()
+	if diff := deep.Equal(sessions, [][]byte{{3, 0, 0, 0, 6, 4}}); len(diff) != 0 {
+		t.Errorf("expected the record after the failure to be served by a new session, differences: %+v", diff)
+	}
+}
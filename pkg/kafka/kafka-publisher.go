@@ -3,123 +3,134 @@ package kafka
 import (
 	"fmt"
 	"math"
-	"math/rand"
 	"net"
 	"strconv"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/Shopify/sarama"
 	"github.com/golang/glog"
 	"github.com/sbezverk/gobmp/pkg/bmp"
+	"github.com/sbezverk/gobmp/pkg/logging"
 	"github.com/sbezverk/gobmp/pkg/pub"
 )
 
-// Define constants for each topic name
-const (
-	peerTopic              = "gobmp.parsed.peer"
-	unicastMessageTopic    = "gobmp.parsed.unicast_prefix"
-	unicastMessageV4Topic  = "gobmp.parsed.unicast_prefix_v4"
-	unicastMessageV6Topic  = "gobmp.parsed.unicast_prefix_v6"
-	lsNodeMessageTopic     = "gobmp.parsed.ls_node"
-	lsLinkMessageTopic     = "gobmp.parsed.ls_link"
-	l3vpnMessageTopic      = "gobmp.parsed.l3vpn"
-	l3vpnMessageV4Topic    = "gobmp.parsed.l3vpn_v4"
-	l3vpnMessageV6Topic    = "gobmp.parsed.l3vpn_v6"
-	lsPrefixMessageTopic   = "gobmp.parsed.ls_prefix"
-	lsSRv6SIDMessageTopic  = "gobmp.parsed.ls_srv6_sid"
-	evpnMessageTopic       = "gobmp.parsed.evpn"
-	srPolicyMessageTopic   = "gobmp.parsed.sr_policy"
-	srPolicyMessageV4Topic = "gobmp.parsed.sr_policy_v4"
-	srPolicyMessageV6Topic = "gobmp.parsed.sr_policy_v6"
-	flowspecMessageTopic   = "gobmp.parsed.flowspec"
-	flowspecMessageV4Topic = "gobmp.parsed.flowspec_v4"
-	flowspecMessageV6Topic = "gobmp.parsed.flowspec_v6"
-	statsMessageTopic      = "gobmp.parsed.statistics"
-)
-
 var (
 	brockerConnectTimeout = 10 * time.Second
 	topicCreateTimeout    = 1 * time.Second
 	// goBMP topic's retention timer is 15 minutes
-	topicRetention = "900000"
+	topicRetention = 15 * time.Minute
 )
 
-var (
-	// topics defines a list of topic to initialize and connect,
-	// initialization is done as a part of NewKafkaPublisher func.
-	topicNames = []string{
-		peerTopic,
-		unicastMessageTopic,
-		unicastMessageV4Topic,
-		unicastMessageV6Topic,
-		lsNodeMessageTopic,
-		lsLinkMessageTopic,
-		l3vpnMessageTopic,
-		l3vpnMessageV4Topic,
-		l3vpnMessageV6Topic,
-		lsPrefixMessageTopic,
-		lsSRv6SIDMessageTopic,
-		evpnMessageTopic,
-		srPolicyMessageTopic,
-		srPolicyMessageV4Topic,
-		srPolicyMessageV6Topic,
-		flowspecMessageTopic,
-		flowspecMessageV4Topic,
-		flowspecMessageV6Topic,
-		statsMessageTopic,
+// TopicConfig defines settings of topics created by the publisher, existing topics are validated
+// against the settings and mismatches are logged, partitions and replicas of existing topics are not changed.
+// When Types is set, only topics of these message types are created at the start.
+type TopicConfig struct {
+	Partitions  int32
+	Replication int16
+	Retention   time.Duration
+	Types       map[int]bool
+	// Deferred defers creation of all topics to their first message, topics are created at start by
+	// a single gobmp instance, for example by the leader of the cluster.
+	Deferred bool
+}
+
+// DefaultTopicConfig returns settings of topics used when no settings are specified
+func DefaultTopicConfig() *TopicConfig {
+	return &TopicConfig{
+		Partitions:  1,
+		Replication: 1,
+		Retention:   topicRetention,
 	}
-)
+}
+
+// admin defines methods of the broker used to manage topics
+type admin interface {
+	CreateTopics(*sarama.CreateTopicsRequest) (*sarama.CreateTopicsResponse, error)
+	DescribeConfigs(*sarama.DescribeConfigsRequest) (*sarama.DescribeConfigsResponse, error)
+	GetMetadata(*sarama.MetadataRequest) (*sarama.MetadataResponse, error)
+}
+
+// messageTypes defines types of published messages, topics of all types are initialized as a part
+// of NewKafkaPublisher func, unless their names depend on the message or the type is not published.
+var messageTypes = []int{
+	bmp.PeerStateChangeMsg,
+	bmp.UnicastPrefixMsg,
+	bmp.UnicastPrefixV4Msg,
+	bmp.UnicastPrefixV6Msg,
+	bmp.LSNodeMsg,
+	bmp.LSLinkMsg,
+	bmp.L3VPNMsg,
+	bmp.L3VPNV4Msg,
+	bmp.L3VPNV6Msg,
+	bmp.LSPrefixMsg,
+	bmp.LSSRv6SIDMsg,
+	bmp.EVPNMsg,
+	bmp.SRPolicyMsg,
+	bmp.SRPolicyV4Msg,
+	bmp.SRPolicyV6Msg,
+	bmp.FlowspecMsg,
+	bmp.FlowspecV4Msg,
+	bmp.FlowspecV6Msg,
+	bmp.StatsReportMsg,
+	bmp.TopologyEventMsg,
+	bmp.PrefixFlapMsg,
+	bmp.AlertMsg,
+	bmp.RTConstraintMsg,
+	bmp.MUPMsg,
+	bmp.BGPEPEMsg,
+	bmp.MACEventMsg,
+}
 
 type publisher struct {
-	broker   *sarama.Broker
-	config   *sarama.Config
-	producer sarama.AsyncProducer
-	stopCh   chan struct{}
+	// delivered and failed count delivery reports, they are first to be 64 bit aligned for atomic access
+	delivered uint64
+	failed    uint64
+	broker    *sarama.Broker
+	config    *sarama.Config
+	producer  sarama.AsyncProducer
+	// done is closed when all delivery reports of the stopped producer are processed
+	done  chan struct{}
+	namer *topicNamer
+	tc    *TopicConfig
+	sync.RWMutex
+	// topics stores names of ensured topics
+	topics map[string]bool
 }
 
 func (p *publisher) PublishMessage(t int, key []byte, msg []byte) error {
-	switch t {
-	case bmp.PeerStateChangeMsg:
-		return p.produceMessage(peerTopic, key, msg)
-	case bmp.UnicastPrefixMsg:
-		return p.produceMessage(unicastMessageTopic, key, msg)
-	case bmp.UnicastPrefixV4Msg:
-		return p.produceMessage(unicastMessageV4Topic, key, msg)
-	case bmp.UnicastPrefixV6Msg:
-		return p.produceMessage(unicastMessageV6Topic, key, msg)
-	case bmp.LSNodeMsg:
-		return p.produceMessage(lsNodeMessageTopic, key, msg)
-	case bmp.LSLinkMsg:
-		return p.produceMessage(lsLinkMessageTopic, key, msg)
-	case bmp.L3VPNMsg:
-		return p.produceMessage(l3vpnMessageTopic, key, msg)
-	case bmp.L3VPNV4Msg:
-		return p.produceMessage(l3vpnMessageV4Topic, key, msg)
-	case bmp.L3VPNV6Msg:
-		return p.produceMessage(l3vpnMessageV6Topic, key, msg)
-	case bmp.LSPrefixMsg:
-		return p.produceMessage(lsPrefixMessageTopic, key, msg)
-	case bmp.LSSRv6SIDMsg:
-		return p.produceMessage(lsSRv6SIDMessageTopic, key, msg)
-	case bmp.EVPNMsg:
-		return p.produceMessage(evpnMessageTopic, key, msg)
-	case bmp.SRPolicyMsg:
-		return p.produceMessage(srPolicyMessageTopic, key, msg)
-	case bmp.SRPolicyV4Msg:
-		return p.produceMessage(srPolicyMessageV4Topic, key, msg)
-	case bmp.SRPolicyV6Msg:
-		return p.produceMessage(srPolicyMessageV6Topic, key, msg)
-	case bmp.FlowspecMsg:
-		return p.produceMessage(flowspecMessageTopic, key, msg)
-	case bmp.FlowspecV4Msg:
-		return p.produceMessage(flowspecMessageV4Topic, key, msg)
-	case bmp.FlowspecV6Msg:
-		return p.produceMessage(flowspecMessageV6Topic, key, msg)
-	case bmp.StatsReportMsg:
-		return p.produceMessage(statsMessageTopic, key, msg)
+	topic, err := p.namer.name(t, msg)
+	if err != nil {
+		return err
+	}
+	if err := p.ensureTopic(topic); err != nil {
+		return err
+	}
+
+	return p.produceMessage(topic, key, msg)
+}
+
+// ensureTopic creates the topic on the first use, topics which names depend on the message
+// are not known until the message is published.
+func (p *publisher) ensureTopic(topic string) error {
+	p.RLock()
+	ok := p.topics[topic]
+	p.RUnlock()
+	if ok {
+		return nil
+	}
+	p.Lock()
+	defer p.Unlock()
+	if p.topics[topic] {
+		return nil
+	}
+	if err := ensureTopic(p.broker, topicCreateTimeout, topic, p.tc); err != nil {
+		return fmt.Errorf("failed to ensure topic %s with error: %+v", topic, err)
 	}
+	p.topics[topic] = true
 
-	return fmt.Errorf("not implemented")
+	return nil
 }
 
 func (p *publisher) produceMessage(topic string, key []byte, msg []byte) error {
@@ -136,22 +147,63 @@ func (p *publisher) produceMessage(topic string, key []byte, msg []byte) error {
 	return nil
 }
 
+// deliveryReports processes delivery reports of produced messages until the producer is closed
+func (p *publisher) deliveryReports() {
+	defer close(p.done)
+	successes, errors := p.producer.Successes(), p.producer.Errors()
+	for successes != nil || errors != nil {
+		select {
+		case _, ok := <-successes:
+			if !ok {
+				successes = nil
+				continue
+			}
+			atomic.AddUint64(&p.delivered, 1)
+		case err, ok := <-errors:
+			if !ok {
+				errors = nil
+				continue
+			}
+			atomic.AddUint64(&p.failed, 1)
+			glog.Errorf("failed to produce message to topic %s with error: %+v", err.Msg.Topic, err.Err)
+		}
+	}
+}
+
 func (p *publisher) Stop() {
-	close(p.stopCh)
+	// Closing the producer flushes buffered messages and waits for their delivery reports
+	p.producer.AsyncClose()
+	<-p.done
+	glog.Infof("Kafka producer stopped, delivered messages: %d, failed messages: %d",
+		atomic.LoadUint64(&p.delivered), atomic.LoadUint64(&p.failed))
 	p.broker.Close()
 }
 
 // NewKafkaPublisher instantiates a new instance of a Kafka publisher
 func NewKafkaPublisher(kafkaSrv string) (pub.Publisher, error) {
+	return NewKafkaPublisherWithConfig(kafkaSrv, DefaultTopicConfig(), DefaultProducerConfig(), DefaultTopicNames())
+}
+
+// NewKafkaPublisherWithConfig instantiates a new instance of a Kafka publisher, missing topics are created
+// with partitions, replication factor and retention defined by tc, pc defines batching, compression and
+// delivery settings of the producer, tn defines templates of topic names.
+func NewKafkaPublisherWithConfig(kafkaSrv string, tc *TopicConfig, pc *ProducerConfig, tn *TopicNames) (pub.Publisher, error) {
 	glog.Infof("Initializing Kafka producer client")
+	if err := tc.validate(); err != nil {
+		return nil, err
+	}
+	namer, err := newTopicNamer(tn)
+	if err != nil {
+		return nil, err
+	}
+	config, err := pc.saramaConfig()
+	if err != nil {
+		return nil, err
+	}
 	if err := validator(kafkaSrv); err != nil {
 		glog.Errorf("Failed to validate Kafka server address %s with error: %+v", kafkaSrv, err)
 		return nil, err
 	}
-	config := sarama.NewConfig()
-	config.ClientID = "gobmp-producer" + "_" + strconv.Itoa(rand.Intn(1000))
-	config.Producer.Return.Successes = true
-	config.Version = sarama.V0_11_0_0
 
 	br := sarama.NewBroker(kafkaSrv)
 	if err := br.Open(config); err != nil {
@@ -163,40 +215,50 @@ func NewKafkaPublisher(kafkaSrv string) (pub.Publisher, error) {
 		glog.Errorf("failed to open connection to the broker with error: %+v\n", err)
 		return nil, err
 	}
-	glog.V(5).Infof("Connected to broker: %s id: %d\n", br.Addr(), br.ID())
+	logging.V(logging.Kafka, 5).Infof("Connected to broker: %s id: %d\n", br.Addr(), br.ID())
 
-	for _, t := range topicNames {
-		if err := ensureTopic(br, topicCreateTimeout, t); err != nil {
+	topics := make(map[string]bool)
+	for _, t := range messageTypes {
+		if tc.Deferred {
+			break
+		}
+		if tc.Types != nil && !tc.Types[t] {
+			continue
+		}
+		if !namer.static(t) {
+			continue
+		}
+		topic, err := namer.name(t, nil)
+		if err != nil {
+			return nil, err
+		}
+		if topics[topic] {
+			continue
+		}
+		if err := ensureTopic(br, topicCreateTimeout, topic, tc); err != nil {
 			glog.Errorf("New Kafka publisher failed to ensure requested topics with error: %+v", err)
 			return nil, err
 		}
+		topics[topic] = true
 	}
 	producer, err := sarama.NewAsyncProducer([]string{kafkaSrv}, config)
 	if err != nil {
 		glog.Errorf("New Kafka publisher failed to start new async producer with error: %+v", err)
 		return nil, err
 	}
-	glog.V(5).Infof("Initialized Kafka Async producer")
-	stopCh := make(chan struct{})
-	go func(producer sarama.AsyncProducer, stopCh <-chan struct{}) {
-		for {
-			select {
-			case <-producer.Successes():
-			case err := <-producer.Errors():
-				glog.Errorf("failed to produce message with error: %+v", *err)
-			case <-stopCh:
-				producer.Close()
-				return
-			}
-		}
-	}(producer, stopCh)
-
-	return &publisher{
-		stopCh:   stopCh,
+	logging.V(logging.Kafka, 5).Infof("Initialized Kafka Async producer")
+	p := &publisher{
+		done:     make(chan struct{}),
 		broker:   br,
 		config:   config,
 		producer: producer,
-	}, nil
+		namer:    namer,
+		tc:       tc,
+		topics:   topics,
+	}
+	go p.deliveryReports()
+
+	return p, nil
 }
 
 func validator(addr string) error {
@@ -221,27 +283,51 @@ func validator(addr string) error {
 	return nil
 }
 
-func ensureTopic(br *sarama.Broker, timeout time.Duration, topicName string) error {
+func (tc *TopicConfig) validate() error {
+	if tc.Partitions < 1 {
+		return fmt.Errorf("invalid number of topic partitions %d", tc.Partitions)
+	}
+	if tc.Replication < 1 {
+		return fmt.Errorf("invalid topic replication factor %d", tc.Replication)
+	}
+	if tc.Retention < time.Millisecond {
+		return fmt.Errorf("invalid topic retention %s", tc.Retention)
+	}
+
+	return nil
+}
+
+func (tc *TopicConfig) retentionMs() string {
+	return strconv.FormatInt(int64(tc.Retention/time.Millisecond), 10)
+}
+
+func ensureTopic(br admin, timeout time.Duration, topicName string, tc *TopicConfig) error {
+	retention := tc.retentionMs()
 	topic := &sarama.CreateTopicsRequest{
 		TopicDetails: map[string]*sarama.TopicDetail{
 			topicName: {
-				NumPartitions:     1,
-				ReplicationFactor: 1,
+				NumPartitions:     tc.Partitions,
+				ReplicationFactor: tc.Replication,
 				ConfigEntries: map[string]*string{
-					"retention.ms": &topicRetention,
+					"retention.ms": &retention,
 				},
 			},
 		},
 	}
 	ticker := time.NewTicker(100 * time.Millisecond)
+	defer ticker.Stop()
 	tout := time.NewTimer(timeout)
+	defer tout.Stop()
 	for {
 		t, err := br.CreateTopics(topic)
 		if err != nil {
 			return err
 		}
 		if e, ok := t.TopicErrors[topicName]; ok {
-			if e.Err == sarama.ErrTopicAlreadyExists || e.Err == sarama.ErrNoError {
+			if e.Err == sarama.ErrTopicAlreadyExists {
+				return validateTopic(br, topicName, tc)
+			}
+			if e.Err == sarama.ErrNoError {
 				return nil
 			}
 			if e.Err != sarama.ErrRequestTimedOut {
@@ -257,6 +343,54 @@ func ensureTopic(br *sarama.Broker, timeout time.Duration, topicName string) err
 	}
 }
 
+// validateTopic compares partitions, replication factor and retention of the existing topic with
+// the requested settings, mismatches are logged as existing topics are not modified by the publisher.
+func validateTopic(br admin, topicName string, tc *TopicConfig) error {
+	md, err := br.GetMetadata(&sarama.MetadataRequest{Topics: []string{topicName}})
+	if err != nil {
+		return fmt.Errorf("failed to get metadata of topic %s with error: %+v", topicName, err)
+	}
+	for _, t := range md.Topics {
+		if t.Name != topicName {
+			continue
+		}
+		if t.Err != sarama.ErrNoError {
+			return fmt.Errorf("failed to get metadata of topic %s with error: %+v", topicName, t.Err)
+		}
+		if int32(len(t.Partitions)) != tc.Partitions {
+			glog.Warningf("topic %s has %d partitions, configured %d", topicName, len(t.Partitions), tc.Partitions)
+		}
+		if len(t.Partitions) != 0 && len(t.Partitions[0].Replicas) != int(tc.Replication) {
+			glog.Warningf("topic %s has replication factor %d, configured %d", topicName, len(t.Partitions[0].Replicas), tc.Replication)
+		}
+	}
+	cfg, err := br.DescribeConfigs(&sarama.DescribeConfigsRequest{
+		Resources: []*sarama.ConfigResource{
+			{
+				Type:        sarama.TopicResource,
+				Name:        topicName,
+				ConfigNames: []string{"retention.ms"},
+			},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to describe configuration of topic %s with error: %+v", topicName, err)
+	}
+	retention := tc.retentionMs()
+	for _, r := range cfg.Resources {
+		if r.ErrorCode != 0 {
+			return fmt.Errorf("failed to describe configuration of topic %s with error: %s", topicName, r.ErrorMsg)
+		}
+		for _, c := range r.Configs {
+			if c.Name == "retention.ms" && c.Value != retention {
+				glog.Warningf("topic %s has retention.ms %s, configured %s", topicName, c.Value, retention)
+			}
+		}
+	}
+
+	return nil
+}
+
 func waitForBrokerConnection(br *sarama.Broker, timeout time.Duration) error {
 	ticker := time.NewTicker(100 * time.Millisecond)
 	tout := time.NewTimer(timeout)
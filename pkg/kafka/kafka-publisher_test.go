@@ -0,0 +1,165 @@
+package kafka
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/Shopify/sarama"
+	"github.com/go-test/deep"
+)
+
+// fakeAdmin is a broker with a single existing topic
+type fakeAdmin struct {
+	existing  string
+	metaErr   sarama.KError
+	configErr int16
+	created   map[string]*sarama.TopicDetail
+	validated bool
+}
+
+func (f *fakeAdmin) CreateTopics(r *sarama.CreateTopicsRequest) (*sarama.CreateTopicsResponse, error) {
+	resp := &sarama.CreateTopicsResponse{TopicErrors: make(map[string]*sarama.TopicError)}
+	for n, d := range r.TopicDetails {
+		if n == f.existing {
+			resp.TopicErrors[n] = &sarama.TopicError{Err: sarama.ErrTopicAlreadyExists}
+			continue
+		}
+		f.created[n] = d
+		resp.TopicErrors[n] = &sarama.TopicError{Err: sarama.ErrNoError}
+	}
+
+	return resp, nil
+}
+
+func (f *fakeAdmin) DescribeConfigs(r *sarama.DescribeConfigsRequest) (*sarama.DescribeConfigsResponse, error) {
+	f.validated = true
+	return &sarama.DescribeConfigsResponse{
+		Resources: []*sarama.ResourceResponse{
+			{
+				ErrorCode: f.configErr,
+				Type:      sarama.TopicResource,
+				Name:      f.existing,
+				Configs:   []*sarama.ConfigEntry{{Name: "retention.ms", Value: "900000"}},
+			},
+		},
+	}, nil
+}
+
+func (f *fakeAdmin) GetMetadata(r *sarama.MetadataRequest) (*sarama.MetadataResponse, error) {
+	return &sarama.MetadataResponse{
+		Topics: []*sarama.TopicMetadata{
+			{
+				Err:        f.metaErr,
+				Name:       f.existing,
+				Partitions: []*sarama.PartitionMetadata{{ID: 0, Replicas: []int32{1}}},
+			},
+		},
+	}, nil
+}
+
+func TestEnsureTopic(t *testing.T) {
+	peerTopic, alertTopic := "gobmp.parsed.peer", "gobmp.parsed.alert"
+	retention := "3600000"
+	tc := &TopicConfig{Partitions: 8, Replication: 3, Retention: time.Hour}
+	tests := []struct {
+		name      string
+		topic     string
+		admin     *fakeAdmin
+		created   map[string]*sarama.TopicDetail
+		validated bool
+		fail      bool
+	}{
+		{
+			name:  "missing topic is created with configured settings",
+			topic: peerTopic,
+			admin: &fakeAdmin{existing: alertTopic, created: map[string]*sarama.TopicDetail{}},
+			created: map[string]*sarama.TopicDetail{
+				peerTopic: {
+					NumPartitions:     8,
+					ReplicationFactor: 3,
+					ConfigEntries:     map[string]*string{"retention.ms": &retention},
+				},
+			},
+		},
+		{
+			name:      "existing topic is validated",
+			topic:     alertTopic,
+			admin:     &fakeAdmin{existing: alertTopic, created: map[string]*sarama.TopicDetail{}},
+			created:   map[string]*sarama.TopicDetail{},
+			validated: true,
+		},
+		{
+			name:    "metadata error of existing topic",
+			topic:   alertTopic,
+			admin:   &fakeAdmin{existing: alertTopic, metaErr: sarama.ErrUnknownTopicOrPartition, created: map[string]*sarama.TopicDetail{}},
+			created: map[string]*sarama.TopicDetail{},
+			fail:    true,
+		},
+		{
+			name:      "configuration error of existing topic",
+			topic:     alertTopic,
+			admin:     &fakeAdmin{existing: alertTopic, configErr: 29, created: map[string]*sarama.TopicDetail{}},
+			created:   map[string]*sarama.TopicDetail{},
+			validated: true,
+			fail:      true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ensureTopic(tt.admin, time.Second, tt.topic, tc)
+			if err != nil && !tt.fail {
+				t.Fatalf("supposed to succeed but failed with error: %+v", err)
+			}
+			if err == nil && tt.fail {
+				t.Fatalf("supposed to fail but succeeded")
+			}
+			if !reflect.DeepEqual(tt.admin.created, tt.created) {
+				t.Logf("Differences: %+v", deep.Equal(tt.admin.created, tt.created))
+				t.Errorf("created topics do not match expected")
+			}
+			if tt.admin.validated != tt.validated {
+				t.Errorf("expected validated %t, got %t", tt.validated, tt.admin.validated)
+			}
+		})
+	}
+}
+
+func TestTopicConfigValidate(t *testing.T) {
+	tests := []struct {
+		name string
+		tc   *TopicConfig
+		fail bool
+	}{
+		{
+			name: "default",
+			tc:   DefaultTopicConfig(),
+		},
+		{
+			name: "no partitions",
+			tc:   &TopicConfig{Partitions: 0, Replication: 1, Retention: time.Hour},
+			fail: true,
+		},
+		{
+			name: "no replicas",
+			tc:   &TopicConfig{Partitions: 1, Replication: 0, Retention: time.Hour},
+			fail: true,
+		},
+		{
+			name: "no retention",
+			tc:   &TopicConfig{Partitions: 1, Replication: 1},
+			fail: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.tc.validate()
+			if err != nil && !tt.fail {
+				t.Fatalf("supposed to succeed but failed with error: %+v", err)
+			}
+			if err == nil && tt.fail {
+				t.Fatalf("supposed to fail but succeeded")
+			}
+		})
+	}
+}
@@ -0,0 +1,79 @@
+package kafka
+
+import (
+	"fmt"
+	"math/rand"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Shopify/sarama"
+)
+
+// ProducerConfig defines batching, compression and delivery settings of Kafka producer
+type ProducerConfig struct {
+	// Linger is the time messages are collected into a batch before the batch is sent,
+	// 0 sends messages as soon as possible.
+	Linger time.Duration
+	// BatchSize is the number of bytes which triggers sending of the batch, 0 does not limit the batch
+	// by size.
+	BatchSize int
+	// Compression is one of "none", "gzip", "snappy", "lz4" or "zstd"
+	Compression string
+	// Idempotent enables idempotent producer, messages are acknowledged by all in sync replicas and
+	// retried without duplicates or reordering.
+	Idempotent bool
+}
+
+// DefaultProducerConfig returns settings of Kafka producer used when no settings are specified
+func DefaultProducerConfig() *ProducerConfig {
+	return &ProducerConfig{
+		Compression: "none",
+	}
+}
+
+var compressionCodecs = map[string]sarama.CompressionCodec{
+	"":       sarama.CompressionNone,
+	"none":   sarama.CompressionNone,
+	"gzip":   sarama.CompressionGZIP,
+	"snappy": sarama.CompressionSnappy,
+	"lz4":    sarama.CompressionLZ4,
+	"zstd":   sarama.CompressionZSTD,
+}
+
+// saramaConfig returns the configuration of Kafka client with producer settings applied
+func (pc *ProducerConfig) saramaConfig() (*sarama.Config, error) {
+	if pc.Linger < 0 {
+		return nil, fmt.Errorf("invalid producer linger %s", pc.Linger)
+	}
+	if pc.BatchSize < 0 {
+		return nil, fmt.Errorf("invalid producer batch size %d", pc.BatchSize)
+	}
+	codec, ok := compressionCodecs[strings.ToLower(pc.Compression)]
+	if !ok {
+		return nil, fmt.Errorf("unsupported producer compression %s", pc.Compression)
+	}
+	config := sarama.NewConfig()
+	config.ClientID = "gobmp-producer" + "_" + strconv.Itoa(rand.Intn(1000))
+	config.Producer.Return.Successes = true
+	config.Producer.Return.Errors = true
+	config.Version = sarama.V0_11_0_0
+	config.Producer.Flush.Frequency = pc.Linger
+	config.Producer.Flush.Bytes = pc.BatchSize
+	config.Producer.Compression = codec
+	if codec == sarama.CompressionZSTD {
+		// zstd compression is supported by brokers starting from version 2.1
+		config.Version = sarama.V2_1_0_0
+	}
+	if pc.Idempotent {
+		// Idempotent producer requires acknowledgement of all in sync replicas and a single in flight request
+		config.Producer.Idempotent = true
+		config.Producer.RequiredAcks = sarama.WaitForAll
+		config.Net.MaxOpenRequests = 1
+		if config.Producer.Retry.Max < 1 {
+			config.Producer.Retry.Max = 1
+		}
+	}
+
+	return config, nil
+}
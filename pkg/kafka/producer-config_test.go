@@ -0,0 +1,89 @@
+package kafka
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Shopify/sarama"
+)
+
+func TestProducerConfig(t *testing.T) {
+	tests := []struct {
+		name        string
+		pc          *ProducerConfig
+		compression sarama.CompressionCodec
+		version     sarama.KafkaVersion
+		acks        sarama.RequiredAcks
+		idempotent  bool
+		fail        bool
+	}{
+		{
+			name:        "default",
+			pc:          DefaultProducerConfig(),
+			compression: sarama.CompressionNone,
+			version:     sarama.V0_11_0_0,
+		},
+		{
+			name:        "lz4 with linger and batch size",
+			pc:          &ProducerConfig{Linger: 5 * time.Millisecond, BatchSize: 1 << 20, Compression: "lz4"},
+			compression: sarama.CompressionLZ4,
+			version:     sarama.V0_11_0_0,
+		},
+		{
+			name:        "zstd requires newer protocol version",
+			pc:          &ProducerConfig{Compression: "ZSTD"},
+			compression: sarama.CompressionZSTD,
+			version:     sarama.V2_1_0_0,
+		},
+		{
+			name:        "idempotent producer",
+			pc:          &ProducerConfig{Idempotent: true},
+			compression: sarama.CompressionNone,
+			version:     sarama.V0_11_0_0,
+			acks:        sarama.WaitForAll,
+			idempotent:  true,
+		},
+		{
+			name: "unsupported compression",
+			pc:   &ProducerConfig{Compression: "brotli"},
+			fail: true,
+		},
+		{
+			name: "negative linger",
+			pc:   &ProducerConfig{Linger: -time.Millisecond},
+			fail: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := tt.pc.saramaConfig()
+			if err != nil && !tt.fail {
+				t.Fatalf("supposed to succeed but failed with error: %+v", err)
+			}
+			if err == nil && tt.fail {
+				t.Fatalf("supposed to fail but succeeded")
+			}
+			if err != nil {
+				return
+			}
+			if c.Producer.Compression != tt.compression {
+				t.Errorf("expected compression %d, got %d", tt.compression, c.Producer.Compression)
+			}
+			if c.Version != tt.version {
+				t.Errorf("expected version %+v, got %+v", tt.version, c.Version)
+			}
+			if c.Producer.Flush.Frequency != tt.pc.Linger || c.Producer.Flush.Bytes != tt.pc.BatchSize {
+				t.Errorf("flush settings do not match linger %s and batch size %d", tt.pc.Linger, tt.pc.BatchSize)
+			}
+			if c.Producer.Idempotent != tt.idempotent {
+				t.Errorf("expected idempotent %t, got %t", tt.idempotent, c.Producer.Idempotent)
+			}
+			if tt.idempotent && (c.Producer.RequiredAcks != tt.acks || c.Net.MaxOpenRequests != 1 || c.Producer.Retry.Max < 1) {
+				t.Errorf("idempotent producer requires all acks, single in flight request and retries")
+			}
+			if !c.Producer.Return.Successes || !c.Producer.Return.Errors {
+				t.Errorf("delivery reports are not enabled")
+			}
+		})
+	}
+}
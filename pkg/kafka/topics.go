@@ -0,0 +1,185 @@
+package kafka
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"regexp"
+	"strings"
+
+	"github.com/sbezverk/gobmp/pkg/bmp"
+)
+
+const (
+	// defaultTopicTemplate produces goBMP topic names gobmp.parsed.{message type}
+	defaultTopicTemplate = "gobmp.parsed.{type}"
+	// maxTopicNameLength is the longest topic name accepted by Kafka
+	maxTopicNameLength = 249
+)
+
+var (
+	// placeholder matches placeholders of topic name template
+	placeholder = regexp.MustCompile(`\{[^{}]*\}`)
+	// invalidTopicChars matches characters which are not allowed in Kafka topic names
+	invalidTopicChars = regexp.MustCompile(`[^a-zA-Z0-9._-]`)
+)
+
+// TopicNames defines templates of topic names, Template applies to all message types, Topics
+// overrides the template of message types by their names, e.g. "unicast_prefix_v4". Templates
+// support placeholders {type} for the message type name, {router} for router's IP address,
+// {router_hash} for router's hash and {tag.<name>} for the tag of BMP listener. GroupPrefixes are prepended
+// to topic names of messages of router groups, keys are names of the groups.
+type TopicNames struct {
+	Template      string            `json:"template,omitempty"`
+	Topics        map[string]string `json:"topics,omitempty"`
+	GroupPrefixes map[string]string `json:"group_prefixes,omitempty"`
+}
+
+// DefaultTopicNames returns templates producing goBMP topic names gobmp.parsed.{type}
+func DefaultTopicNames() *TopicNames {
+	return &TopicNames{
+		Template: defaultTopicTemplate,
+	}
+}
+
+// LoadTopicNames reads templates of topic names from JSON file, message types without template
+// use the default template.
+func LoadTopicNames(file string) (*TopicNames, error) {
+	b, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	tn := &TopicNames{}
+	if err := json.Unmarshal(b, tn); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal topic names %s with error: %+v", file, err)
+	}
+	if tn.Template == "" {
+		tn.Template = defaultTopicTemplate
+	}
+
+	return tn, nil
+}
+
+// Static returns true when topic names do not depend on fields of messages, only the message type
+func (tn *TopicNames) Static() bool {
+	n, err := newTopicNamer(tn)
+	if err != nil {
+		return false
+	}
+	for _, t := range messageTypes {
+		if !n.static(t) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// topicNamer resolves topic names of messages
+type topicNamer struct {
+	templates map[int]string
+	prefixes  map[string]string
+}
+
+// routerFields defines fields of a message used by templates, Message is set when the message
+// is wrapped in the envelope.
+type routerFields struct {
+	RouterIP   string                 `json:"router_ip"`
+	RouterHash string                 `json:"router_hash"`
+	Enrichment map[string]interface{} `json:"enrichment"`
+	Message    *routerFields          `json:"message"`
+}
+
+func newTopicNamer(tn *TopicNames) (*topicNamer, error) {
+	n := &topicNamer{
+		templates: make(map[int]string, len(messageTypes)),
+		prefixes:  tn.GroupPrefixes,
+	}
+	for _, t := range messageTypes {
+		n.templates[t] = tn.Template
+	}
+	for name, tmpl := range tn.Topics {
+		t, ok := bmp.MsgTypeByName(name)
+		if !ok {
+			return nil, fmt.Errorf("unknown message type %s of topic template %s", name, tmpl)
+		}
+		n.templates[t] = tmpl
+	}
+	for t, tmpl := range n.templates {
+		if tmpl == "" {
+			return nil, fmt.Errorf("empty topic template of message type %s", bmp.MsgTypeName(t))
+		}
+		for _, p := range placeholder.FindAllString(tmpl, -1) {
+			switch {
+			case p == "{type}":
+			case p == "{router}":
+			case p == "{router_hash}":
+			case strings.HasPrefix(p, "{tag.") && len(p) > len("{tag.}"):
+			default:
+				return nil, fmt.Errorf("unknown placeholder %s in topic template %s", p, tmpl)
+			}
+		}
+	}
+
+	return n, nil
+}
+
+// static returns true when the topic name of the message type does not depend on the message
+func (n *topicNamer) static(t int) bool {
+	if len(n.prefixes) != 0 {
+		return false
+	}
+
+	return !placeholder.MatchString(strings.Replace(n.templates[t], "{type}", "", -1))
+}
+
+// name returns the topic name of the message, placeholders without value in the message are replaced
+// by "unknown" and characters not allowed in topic names are replaced by "_".
+func (n *topicNamer) name(t int, msg []byte) (string, error) {
+	tmpl, ok := n.templates[t]
+	if !ok {
+		return "", fmt.Errorf("not implemented")
+	}
+	var f *routerFields
+	fields := func() *routerFields {
+		if f == nil {
+			f = &routerFields{}
+			// Messages which can not be unmarshaled are published with unknown values
+			_ = json.Unmarshal(msg, f)
+			if f.Message != nil {
+				f = f.Message
+			}
+		}
+		return f
+	}
+	name := placeholder.ReplaceAllStringFunc(tmpl, func(p string) string {
+		if p == "{type}" {
+			return bmp.MsgTypeName(t)
+		}
+		v := ""
+		switch {
+		case p == "{router}":
+			v = fields().RouterIP
+		case p == "{router_hash}":
+			v = fields().RouterHash
+		default:
+			v, _ = fields().Enrichment[strings.TrimSuffix(strings.TrimPrefix(p, "{tag."), "}")].(string)
+		}
+		if v == "" {
+			return "unknown"
+		}
+		return v
+	})
+	if len(n.prefixes) != 0 {
+		// Router group is carried in "group" field of the enrichment
+		if g, ok := fields().Enrichment["group"].(string); ok && n.prefixes[g] != "" {
+			name = n.prefixes[g] + "." + name
+		}
+	}
+	name = invalidTopicChars.ReplaceAllString(name, "_")
+	if len(name) > maxTopicNameLength {
+		return "", fmt.Errorf("topic name %s is longer than %d characters", name, maxTopicNameLength)
+	}
+
+	return name, nil
+}
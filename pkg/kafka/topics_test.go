@@ -0,0 +1,116 @@
+package kafka
+
+import (
+	"testing"
+
+	"github.com/sbezverk/gobmp/pkg/bmp"
+)
+
+func TestTopicNamer(t *testing.T) {
+	msg := []byte(`{"action":"add","router_ip":"2001:db8::1","router_hash":"0123abcd","enrichment":{"region":"eu-west","tags":["a"]}}`)
+	tests := []struct {
+		name   string
+		tn     *TopicNames
+		t      int
+		msg    []byte
+		topic  string
+		static bool
+		fail   bool
+	}{
+		{
+			name:   "default template",
+			tn:     DefaultTopicNames(),
+			t:      bmp.UnicastPrefixV4Msg,
+			msg:    msg,
+			topic:  "gobmp.parsed.unicast_prefix_v4",
+			static: true,
+		},
+		{
+			name:  "router template, invalid characters are replaced",
+			tn:    &TopicNames{Template: "gobmp.{type}.{router}"},
+			t:     bmp.PeerStateChangeMsg,
+			msg:   msg,
+			topic: "gobmp.peer.2001_db8__1",
+		},
+		{
+			name:   "per type override",
+			tn:     &TopicNames{Template: "gobmp.{type}.{router}", Topics: map[string]string{"statistics": "bmp-stats"}},
+			t:      bmp.StatsReportMsg,
+			msg:    msg,
+			topic:  "bmp-stats",
+			static: true,
+		},
+		{
+			name:  "tag and router hash",
+			tn:    &TopicNames{Template: "{tag.region}.{router_hash}.{type}"},
+			t:     bmp.L3VPNV6Msg,
+			msg:   msg,
+			topic: "eu-west.0123abcd.l3vpn_v6",
+		},
+		{
+			name:  "message in envelope",
+			tn:    &TopicNames{Template: "gobmp.{type}.{router}"},
+			t:     bmp.UnicastPrefixV6Msg,
+			msg:   []byte(`{"schema_version":1,"collector_id":"c1","type":"unicast_prefix_v6","message":{"router_ip":"192.0.2.1"}}`),
+			topic: "gobmp.unicast_prefix_v6.192.0.2.1",
+		},
+		{
+			name:  "missing values",
+			tn:    &TopicNames{Template: "{tag.site}.{type}"},
+			t:     bmp.EVPNMsg,
+			msg:   []byte(`{"action":"add"}`),
+			topic: "unknown.evpn",
+		},
+		{
+			name:  "router group prefix",
+			tn:    &TopicNames{Template: "gobmp.parsed.{type}", GroupPrefixes: map[string]string{"tenant-a": "tenant-a"}},
+			t:     bmp.UnicastPrefixV4Msg,
+			msg:   []byte(`{"schema_version":1,"message":{"router_ip":"192.0.2.1","enrichment":{"group":"tenant-a"}}}`),
+			topic: "tenant-a.gobmp.parsed.unicast_prefix_v4",
+		},
+		{
+			name:  "router without group prefix",
+			tn:    &TopicNames{Template: "gobmp.parsed.{type}", GroupPrefixes: map[string]string{"tenant-a": "tenant-a"}},
+			t:     bmp.UnicastPrefixV4Msg,
+			msg:   []byte(`{"router_ip":"192.0.2.1","enrichment":{"group":"tenant-b"}}`),
+			topic: "gobmp.parsed.unicast_prefix_v4",
+		},
+		{
+			name: "unknown placeholder",
+			tn:   &TopicNames{Template: "gobmp.{peer}"},
+			fail: true,
+		},
+		{
+			name: "unknown message type",
+			tn:   &TopicNames{Template: "gobmp.{type}", Topics: map[string]string{"bmp_stats": "stats"}},
+			fail: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			n, err := newTopicNamer(tt.tn)
+			if err != nil && !tt.fail {
+				t.Fatalf("supposed to succeed but failed with error: %+v", err)
+			}
+			if err == nil && tt.fail {
+				t.Fatalf("supposed to fail but succeeded")
+			}
+			if err != nil {
+				return
+			}
+			topic, err := n.name(tt.t, tt.msg)
+			if err != nil {
+				t.Fatalf("failed to resolve topic name with error: %+v", err)
+			}
+			if topic != tt.topic {
+				t.Errorf("expected topic %s, got %s", tt.topic, topic)
+			}
+			if tt.tn.Static() != (tt.static && len(tt.tn.Topics) == 0) {
+				t.Errorf("topic names supposed to be static %t", tt.static && len(tt.tn.Topics) == 0)
+			}
+			if n.static(tt.t) != tt.static {
+				t.Errorf("expected static %t, got %t", tt.static, n.static(tt.t))
+			}
+		})
+	}
+}
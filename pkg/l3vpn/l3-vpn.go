@@ -16,8 +16,20 @@ func UnmarshalL3VPNNLRI(b []byte, pathID bool, srv6 ...bool) (*base.MPNLRI, erro
 	if len(srv6) == 1 {
 		srv6Flag = srv6[0]
 	}
+
+	return unmarshalL3VPNNLRI(b, pathID, srv6Flag, false)
+}
+
+// UnmarshalL3VPNWithdrawnNLRI instantiates a L3 VPN NLRI object of withdrawn routes of MP_UNREACH_NLRI, each route
+// carries a single label field which value is ignored by the receiver https://tools.ietf.org/html/rfc8277#section-2.4
+// Label field 0x800000 and 0x000000 sent by some implementations are not reported as labels.
+func UnmarshalL3VPNWithdrawnNLRI(b []byte, pathID bool) (*base.MPNLRI, error) {
+	return unmarshalL3VPNNLRI(b, pathID, false, true)
+}
+
+func unmarshalL3VPNNLRI(b []byte, pathID bool, srv6Flag bool, withdraw bool) (*base.MPNLRI, error) {
 	if glog.V(6) {
-		glog.Infof("L3VPN NLRI Raw: %s path ID flag: %t srv6 flag: %t ", tools.MessageHex(b), pathID, srv6Flag)
+		glog.Infof("L3VPN NLRI Raw: %s path ID flag: %t srv6 flag: %t withdraw: %t", tools.MessageHex(b), pathID, srv6Flag, withdraw)
 	}
 	if len(b) == 0 {
 		return nil, fmt.Errorf("NLRI length is 0")
@@ -55,10 +67,19 @@ func UnmarshalL3VPNNLRI(b []byte, pathID bool, srv6 ...bool) (*base.MPNLRI, erro
 			err = fmt.Errorf("not enough bytes to reconstruct l3vpn nlri")
 			goto error_handle
 		}
-		if bytes.Equal([]byte{0x80, 0x00, 0x00}, b[p:p+3]) {
+		if bytes.Equal([]byte{0x80, 0x00, 0x00}, b[p:p+3]) || (withdraw && bytes.Equal([]byte{0x00, 0x00, 0x00}, b[p:p+3])) {
 			up.Label = nil
 			compatibilityField = 3
 			p += 3
+		} else if withdraw {
+			// Withdrawn route carries a single label field, its Bottom of Stack bit is not always set
+			l, e := base.MakeLabel(b[p : p+3])
+			if e != nil {
+				err = e
+				goto error_handle
+			}
+			up.Label = []*base.Label{l}
+			p += 3
 		} else {
 			// Otherwise getting labels
 			up.Label = make([]*base.Label, 0)
@@ -121,7 +142,7 @@ error_handle:
 		// might be advertised and received, but BGP Update would not have PathID set due to some other conditions,
 		// example when bgp speakers are in different AS. In error handle, attempting to Unmarshal again with reversed
 		// value of PathID flag.
-		if mp, e := UnmarshalL3VPNNLRI(b, !pathID, srv6Flag); e == nil {
+		if mp, e := unmarshalL3VPNNLRI(b, !pathID, srv6Flag, withdraw); e == nil {
 			return mp, nil
 		}
 		glog.Errorf("failed to reconstruct l3vpn nlri from slice %s with error: %+v", tools.MessageHex(b), err)
@@ -241,6 +241,37 @@ func TestUnmarshalL3VPNNLRI(t *testing.T) {
 			srv6:   false,
 			pathID: true,
 		},
+		{
+			name:  "vpnv6 label stack rd type 2",
+			input: []byte{0x90, 0x00, 0x01, 0x00, 0x05, 0xdc, 0x31, 0x00, 0x02, 0x00, 0x00, 0xfd, 0xe8, 0x00, 0x64, 0x20, 0x01, 0x0d, 0xb8},
+			expect: &base.MPNLRI{
+				NLRI: []base.Route{
+					{
+						Length: 32,
+						Label: []*base.Label{
+							{
+								Value: 16,
+								Exp:   0,
+								BoS:   false,
+							},
+							{
+								Value: 24003,
+								Exp:   0,
+								BoS:   true,
+							},
+						},
+						RD: &base.RD{
+							Type:  2,
+							Value: []byte{0, 0, 253, 232, 0, 100},
+						},
+						Prefix: []byte{0x20, 0x01, 0x0d, 0xb8},
+					},
+				},
+			},
+			fail:   false,
+			srv6:   false,
+			pathID: false,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -260,3 +291,82 @@ func TestUnmarshalL3VPNNLRI(t *testing.T) {
 		})
 	}
 }
+
+func TestUnmarshalL3VPNWithdrawnNLRI(t *testing.T) {
+	tests := []struct {
+		name   string
+		input  []byte
+		expect *base.MPNLRI
+		pathID bool
+	}{
+		{
+			name:  "vpnv6 label field 0x000000 rd type 1",
+			input: []byte{0x98, 0x00, 0x00, 0x00, 0x00, 0x01, 0x0a, 0x00, 0x00, 0x07, 0x00, 0x01, 0x55, 0x55, 0x55, 0x55, 0x55, 0x55, 0x55, 0x55},
+			expect: &base.MPNLRI{
+				NLRI: []base.Route{
+					{
+						Length: 64,
+						RD: &base.RD{
+							Type:  1,
+							Value: []byte{10, 0, 0, 7, 0, 1},
+						},
+						Prefix: []byte{0x55, 0x55, 0x55, 0x55, 0x55, 0x55, 0x55, 0x55},
+					},
+				},
+			},
+		},
+		{
+			name:  "vpnv6 label without bottom of stack",
+			input: []byte{0x78, 0x00, 0x01, 0x00, 0x00, 0x00, 0x02, 0x2b, 0x00, 0x00, 0x02, 0x2b, 0x20, 0x01, 0x0d, 0xb8},
+			expect: &base.MPNLRI{
+				NLRI: []base.Route{
+					{
+						Length: 32,
+						Label: []*base.Label{
+							{
+								Value: 16,
+								Exp:   0,
+								BoS:   false,
+							},
+						},
+						RD: &base.RD{
+							Type:  0,
+							Value: []byte{2, 43, 0, 0, 2, 43},
+						},
+						Prefix: []byte{0x20, 0x01, 0x0d, 0xb8},
+					},
+				},
+			},
+		},
+		{
+			name:  "vpnv4 label field 0x800000 with path id",
+			input: []byte{0x00, 0x00, 0x00, 0x01, 0x70, 0x80, 0x00, 0x00, 0x00, 0x00, 0x02, 0x41, 0x00, 0x00, 0xfd, 0xeb, 0x0a, 0x00, 0x01},
+			expect: &base.MPNLRI{
+				NLRI: []base.Route{
+					{
+						PathID: 1,
+						Length: 24,
+						RD: &base.RD{
+							Type:  0,
+							Value: []byte{2, 65, 0, 0, 253, 235},
+						},
+						Prefix: []byte{10, 0, 1},
+					},
+				},
+			},
+			pathID: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := UnmarshalL3VPNWithdrawnNLRI(tt.input, tt.pathID)
+			if err != nil {
+				t.Fatalf("expected to succeed but failed with error: %+v", err)
+			}
+			if !reflect.DeepEqual(got, tt.expect) {
+				t.Logf("Differences: %+v", deep.Equal(tt.expect, got))
+				t.Fatal("test failed as expected nlri does not match actual nlri")
+			}
+		})
+	}
+}
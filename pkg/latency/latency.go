@@ -0,0 +1,166 @@
+package latency
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/golang/glog"
+)
+
+// buckets defines the number of buckets of a histogram, upper bound of bucket i is 2^i microseconds,
+// the last bucket counts all longer observations.
+const buckets = 25
+
+// Summary defines parse latency summary of a message type or a router, percentiles are upper bounds of
+// histogram buckets, they are capped by the longest observed latency.
+type Summary struct {
+	Count uint64 `json:"count"`
+	P50Us int64  `json:"p50_us"`
+	P99Us int64  `json:"p99_us"`
+	MaxUs int64  `json:"max_us"`
+}
+
+// Stats defines parse latency summaries indexed by BMP message type name and by router
+type Stats struct {
+	MessageTypes map[string]*Summary `json:"message_types"`
+	Routers      map[string]*Summary `json:"routers"`
+}
+
+// Recorder records the time of parsing BMP messages per BMP message type and per router
+type Recorder interface {
+	Observe(router string, msgType uint8, d time.Duration)
+	Stats() *Stats
+}
+
+type histogram struct {
+	counts [buckets]uint64
+	count  uint64
+	max    time.Duration
+}
+
+func (h *histogram) observe(d time.Duration) {
+	i := 0
+	for bound := time.Microsecond; i < buckets-1 && d > bound; bound *= 2 {
+		i++
+	}
+	h.counts[i]++
+	h.count++
+	if d > h.max {
+		h.max = d
+	}
+}
+
+// percentile returns the upper bound of the bucket where q share of observations is reached
+func (h *histogram) percentile(q float64) time.Duration {
+	target := uint64(math.Ceil(q * float64(h.count)))
+	var n uint64
+	bound := time.Microsecond
+	for i := 0; i < buckets; i++ {
+		n += h.counts[i]
+		if n >= target {
+			break
+		}
+		bound *= 2
+	}
+	if bound > h.max {
+		return h.max
+	}
+
+	return bound
+}
+
+func (h *histogram) summary() *Summary {
+	return &Summary{
+		Count: h.count,
+		P50Us: int64(h.percentile(0.5) / time.Microsecond),
+		P99Us: int64(h.percentile(0.99) / time.Microsecond),
+		MaxUs: int64(h.max / time.Microsecond),
+	}
+}
+
+type recorder struct {
+	sync.Mutex
+	types   map[uint8]*histogram
+	routers map[string]*histogram
+}
+
+var _ Recorder = &recorder{}
+
+func (r *recorder) Observe(router string, msgType uint8, d time.Duration) {
+	r.Lock()
+	defer r.Unlock()
+	h, ok := r.types[msgType]
+	if !ok {
+		h = &histogram{}
+		r.types[msgType] = h
+	}
+	h.observe(d)
+	if h, ok = r.routers[router]; !ok {
+		h = &histogram{}
+		r.routers[router] = h
+	}
+	h.observe(d)
+}
+
+func (r *recorder) Stats() *Stats {
+	r.Lock()
+	defer r.Unlock()
+	s := &Stats{
+		MessageTypes: make(map[string]*Summary, len(r.types)),
+		Routers:      make(map[string]*Summary, len(r.routers)),
+	}
+	for t, h := range r.types {
+		s.MessageTypes[typeName(t)] = h.summary()
+	}
+	for router, h := range r.routers {
+		s.Routers[router] = h.summary()
+	}
+
+	return s
+}
+
+var typeNames = map[uint8]string{
+	0: "route_monitoring",
+	1: "statistics_report",
+	2: "peer_down",
+	3: "peer_up",
+	4: "initiation",
+	5: "termination",
+	6: "route_mirroring",
+}
+
+func typeName(t uint8) string {
+	if n, ok := typeNames[t]; ok {
+		return n
+	}
+
+	return fmt.Sprintf("type_%d", t)
+}
+
+// NewHandler returns http handler returning parse latency summaries:
+//
+//	GET /debug/parse-latency
+func NewHandler(r Recorder) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.Method != http.MethodGet {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(r.Stats()); err != nil {
+			glog.Errorf("failed to send parse latency statistics with error: %+v", err)
+		}
+	})
+}
+
+// NewRecorder instantiates a new instance of parse latency Recorder
+func NewRecorder() Recorder {
+	return &recorder{
+		types:   make(map[uint8]*histogram),
+		routers: make(map[string]*histogram),
+	}
+}
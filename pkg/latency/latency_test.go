@@ -0,0 +1,60 @@
+package latency
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-test/deep"
+)
+
+func TestRecorder(t *testing.T) {
+	r := NewRecorder()
+	// 98 fast Route Monitoring messages and 2 slow ones of router 192.0.2.1
+	for i := 0; i < 98; i++ {
+		r.Observe("192.0.2.1", 0, 3*time.Microsecond)
+	}
+	r.Observe("192.0.2.1", 0, 900*time.Microsecond)
+	r.Observe("192.0.2.1", 0, 1500*time.Microsecond)
+	r.Observe("192.0.2.2", 3, 10*time.Microsecond)
+	r.Observe("192.0.2.2", 42, 500*time.Nanosecond)
+	expect := &Stats{
+		MessageTypes: map[string]*Summary{
+			"route_monitoring": {Count: 100, P50Us: 4, P99Us: 1024, MaxUs: 1500},
+			"peer_up":          {Count: 1, P50Us: 10, P99Us: 10, MaxUs: 10},
+			"type_42":          {Count: 1, P50Us: 0, P99Us: 0, MaxUs: 0},
+		},
+		Routers: map[string]*Summary{
+			"192.0.2.1": {Count: 100, P50Us: 4, P99Us: 1024, MaxUs: 1500},
+			"192.0.2.2": {Count: 2, P50Us: 1, P99Us: 10, MaxUs: 10},
+		},
+	}
+	if diff := deep.Equal(r.Stats(), expect); len(diff) != 0 {
+		t.Errorf("expected and actual parse latency statistics do not match, differences: %+v", diff)
+	}
+}
+
+func TestHandler(t *testing.T) {
+	r := NewRecorder()
+	r.Observe("192.0.2.1", 1, 20*time.Microsecond)
+	h := NewHandler(r)
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/debug/parse-latency", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, w.Code)
+	}
+	s := &Stats{}
+	if err := json.Unmarshal(w.Body.Bytes(), s); err != nil {
+		t.Fatalf("failed to unmarshal parse latency statistics with error: %+v", err)
+	}
+	if sum, ok := s.MessageTypes["statistics_report"]; !ok || sum.Count != 1 || sum.MaxUs != 20 {
+		t.Errorf("unexpected summary of statistics reports %+v", sum)
+	}
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/debug/parse-latency", nil))
+	if w.Code != http.StatusMethodNotAllowed {
+		t.Errorf("expected status %d, got %d", http.StatusMethodNotAllowed, w.Code)
+	}
+}
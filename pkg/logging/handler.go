@@ -0,0 +1,44 @@
+package logging
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/golang/glog"
+)
+
+// NewHandler returns http handler exposing verbosity of modules:
+//
+//	GET /debug/log-levels
+//	POST /debug/log-levels?module={module}&level={level}
+//
+// Both return verbosity of all modules.
+func NewHandler() http.Handler {
+	return http.HandlerFunc(handle)
+}
+
+func handle(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+	case http.MethodPost, http.MethodPut:
+		module := r.URL.Query().Get("module")
+		level, err := strconv.Atoi(r.URL.Query().Get("level"))
+		if err != nil {
+			http.Error(w, "invalid level "+r.URL.Query().Get("level"), http.StatusBadRequest)
+			return
+		}
+		if err := SetLevel(module, level); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		glog.Infof("verbosity of module %s is set to %d", module, level)
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(Levels()); err != nil {
+		glog.Errorf("failed to send log levels response with error: %+v", err)
+	}
+}
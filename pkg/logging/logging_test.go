@@ -0,0 +1,108 @@
+package logging
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/go-test/deep"
+)
+
+func TestSetLevels(t *testing.T) {
+	tests := []struct {
+		name   string
+		spec   string
+		expect map[string]int
+		fail   bool
+	}{
+		{
+			name:   "empty",
+			spec:   "",
+			expect: map[string]int{BMP: 0, BGP: 0, BGPLS: 0, SR: 0, Kafka: 0},
+		},
+		{
+			name:   "two modules",
+			spec:   "bgpls=6, kafka=5",
+			expect: map[string]int{BMP: 0, BGP: 0, BGPLS: 6, SR: 0, Kafka: 5},
+		},
+		{
+			name: "unknown module",
+			spec: "rpki=6",
+			fail: true,
+		},
+		{
+			name: "invalid level",
+			spec: "bmp=high",
+			fail: true,
+		},
+		{
+			name: "negative level",
+			spec: "bmp=-1",
+			fail: true,
+		},
+		{
+			name: "missing level",
+			spec: "bmp",
+			fail: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, m := range Modules() {
+				_ = SetLevel(m, 0)
+			}
+			err := SetLevels(tt.spec)
+			if err != nil && !tt.fail {
+				t.Fatalf("supposed to succeed but failed with error: %+v", err)
+			}
+			if err == nil && tt.fail {
+				t.Fatalf("supposed to fail but succeeded")
+			}
+			if err != nil {
+				return
+			}
+			if got := Levels(); !reflect.DeepEqual(got, tt.expect) {
+				t.Logf("Differences: %+v", deep.Equal(got, tt.expect))
+				t.Fatalf("levels do not match expected")
+			}
+		})
+	}
+}
+
+func TestV(t *testing.T) {
+	_ = SetLevel(BGPLS, 6)
+	defer SetLevel(BGPLS, 0)
+	if !V(BGPLS, 6) {
+		t.Errorf("expected level 6 of module %s to be enabled", BGPLS)
+	}
+	if V(BGPLS, 7) {
+		t.Errorf("expected level 7 of module %s to be disabled", BGPLS)
+	}
+	if V(BMP, 6) {
+		t.Errorf("expected level 6 of module %s to be disabled", BMP)
+	}
+}
+
+func TestHandler(t *testing.T) {
+	defer SetLevel(SR, 0)
+	h := NewHandler()
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/debug/log-levels?module=sr&level=6", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	if l := Levels()[SR]; l != 6 {
+		t.Fatalf("expected level 6 of module %s, got %d", SR, l)
+	}
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/debug/log-levels?module=unknown&level=6", nil))
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected status %d, got %d", http.StatusBadRequest, w.Code)
+	}
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodDelete, "/debug/log-levels", nil))
+	if w.Code != http.StatusMethodNotAllowed {
+		t.Fatalf("expected status %d, got %d", http.StatusMethodNotAllowed, w.Code)
+	}
+}
@@ -0,0 +1,376 @@
+package lookingglass
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/golang/glog"
+	"github.com/sbezverk/gobmp/pkg/bmp"
+	"github.com/sbezverk/gobmp/pkg/pub"
+)
+
+// maxRoutes defines the maximum number of routes returned by a single query
+const maxRoutes = 1000
+
+// LookingGlass defines a Publisher maintaining the latest routes and peers of monitored routers, they are
+// queried by read-only looking glass http endpoints:
+//
+//	GET /routes?prefix={prefix or address}&match={exact|longest|longer}&router={address}&peer={address}&rd={rd}
+//	GET /peers?router={address}
+//
+// When match is not set, a prefix is matched exactly and an address is matched by the longest prefix covering it,
+// longer returns routes of the prefix and all more specific prefixes. router, peer and rd are optional filters.
+type LookingGlass interface {
+	pub.Publisher
+	http.Handler
+}
+
+// Peer defines the state of a peer returned by /peers
+type Peer struct {
+	RouterIP   string `json:"router_ip"`
+	PeerIP     string `json:"peer_ip"`
+	PeerASN    uint32 `json:"peer_asn,omitempty"`
+	PeerBGPID  string `json:"peer_bgp_id,omitempty"`
+	State      string `json:"state"`
+	LastChange string `json:"last_change,omitempty"`
+	Routes     int    `json:"routes"`
+}
+
+// routes defines message types kept by the looking glass
+var routes = map[int]bool{
+	bmp.UnicastPrefixMsg:   true,
+	bmp.UnicastPrefixV4Msg: true,
+	bmp.UnicastPrefixV6Msg: true,
+	bmp.L3VPNMsg:           true,
+	bmp.L3VPNV4Msg:         true,
+	bmp.L3VPNV6Msg:         true,
+}
+
+// key defines fields of published messages used by the looking glass
+type key struct {
+	Action      string `json:"action"`
+	Hash        string `json:"hash"`
+	RouterIP    string `json:"router_ip"`
+	PeerIP      string `json:"peer_ip"`
+	PeerASN     uint32 `json:"peer_asn"`
+	RemoteIP    string `json:"remote_ip"`
+	RemoteASN   uint32 `json:"remote_asn"`
+	RemoteBGPID string `json:"remote_bgp_id"`
+	Timestamp   string `json:"timestamp"`
+	Prefix      string `json:"prefix"`
+	PrefixLen   int    `json:"prefix_len"`
+	VPNRD       string `json:"vpn_rd"`
+}
+
+type route struct {
+	routerIP string
+	peerIP   string
+	rd       string
+	msg      json.RawMessage
+}
+
+// prefix defines routes of a prefix indexed by the hash of the route
+type prefix struct {
+	network *net.IPNet
+	routes  map[string]*route
+}
+
+type lookingGlass struct {
+	sync.RWMutex
+	mux      *http.ServeMux
+	prefixes map[string]*prefix
+	// peers are indexed by router and peer addresses
+	peers map[string]map[string]*Peer
+}
+
+var _ LookingGlass = &lookingGlass{}
+
+// network returns the prefix of the address masked to the length
+func network(addr string, length int) (*net.IPNet, error) {
+	ip := net.ParseIP(addr)
+	if ip == nil {
+		return nil, fmt.Errorf("invalid address %q", addr)
+	}
+	bits := 8 * net.IPv6len
+	if ip4 := ip.To4(); ip4 != nil {
+		ip, bits = ip4, 8*net.IPv4len
+	}
+	if length < 0 || length > bits {
+		return nil, fmt.Errorf("invalid prefix length %d of address %s", length, addr)
+	}
+	mask := net.CIDRMask(length, bits)
+
+	return &net.IPNet{IP: ip.Mask(mask), Mask: mask}, nil
+}
+
+func (l *lookingGlass) peer(routerIP, peerIP string) *Peer {
+	r, ok := l.peers[routerIP]
+	if !ok {
+		r = make(map[string]*Peer)
+		l.peers[routerIP] = r
+	}
+	p, ok := r[peerIP]
+	if !ok {
+		p = &Peer{RouterIP: routerIP, PeerIP: peerIP, State: "unknown"}
+		r[peerIP] = p
+	}
+
+	return p
+}
+
+func (l *lookingGlass) PublishMessage(msgType int, msgHash []byte, msg []byte) error {
+	if msgType != bmp.PeerStateChangeMsg && !routes[msgType] {
+		return nil
+	}
+	k := &key{}
+	if err := json.Unmarshal(msg, k); err != nil {
+		return err
+	}
+	if k.RouterIP == "" {
+		return nil
+	}
+	l.Lock()
+	defer l.Unlock()
+	if msgType == bmp.PeerStateChangeMsg {
+		if k.RemoteIP == "" {
+			return nil
+		}
+		p := l.peer(k.RouterIP, k.RemoteIP)
+		p.State, p.LastChange = k.Action, k.Timestamp
+		if k.RemoteASN != 0 {
+			p.PeerASN = k.RemoteASN
+		}
+		if k.RemoteBGPID != "" {
+			p.PeerBGPID = k.RemoteBGPID
+		}
+		if k.Action == "down" {
+			l.withdrawPeer(k.RouterIP, k.RemoteIP)
+		}
+		return nil
+	}
+	if k.PeerIP == "" || k.Hash == "" {
+		return nil
+	}
+	n, err := network(k.Prefix, k.PrefixLen)
+	if err != nil {
+		return nil
+	}
+	p := l.peer(k.RouterIP, k.PeerIP)
+	if k.PeerASN != 0 {
+		p.PeerASN = k.PeerASN
+	}
+	pk := n.String()
+	pr, ok := l.prefixes[pk]
+	if k.Action == "del" {
+		if ok {
+			if _, ok := pr.routes[k.Hash]; ok {
+				delete(pr.routes, k.Hash)
+				p.Routes--
+			}
+			if len(pr.routes) == 0 {
+				delete(l.prefixes, pk)
+			}
+		}
+		return nil
+	}
+	if !ok {
+		pr = &prefix{network: n, routes: make(map[string]*route)}
+		l.prefixes[pk] = pr
+	}
+	if _, ok := pr.routes[k.Hash]; !ok {
+		p.Routes++
+	}
+	r := &route{routerIP: k.RouterIP, peerIP: k.PeerIP, rd: k.VPNRD, msg: make(json.RawMessage, len(msg))}
+	copy(r.msg, msg)
+	pr.routes[k.Hash] = r
+
+	return nil
+}
+
+// withdrawPeer removes all routes of the peer, BMP Peer Down implies that routes of the peer are gone
+func (l *lookingGlass) withdrawPeer(routerIP, peerIP string) {
+	for pk, pr := range l.prefixes {
+		for h, r := range pr.routes {
+			if r.routerIP == routerIP && r.peerIP == peerIP {
+				delete(pr.routes, h)
+			}
+		}
+		if len(pr.routes) == 0 {
+			delete(l.prefixes, pk)
+		}
+	}
+	l.peers[routerIP][peerIP].Routes = 0
+}
+
+func (l *lookingGlass) Stop() {}
+
+// result defines a route returned by the query
+type result struct {
+	prefix string
+	route  *route
+}
+
+// filter returns routes of the prefix accepted by keep
+func (pr *prefix) filter(keep func(*route) bool) []*result {
+	results := make([]*result, 0)
+	for _, rt := range pr.routes {
+		if keep(rt) {
+			results = append(results, &result{prefix: pr.network.String(), route: rt})
+		}
+	}
+
+	return results
+}
+
+// lookup returns routes of prefixes matching the query which are accepted by keep, the longest match
+// returns routes of the longest prefix which has routes accepted by keep.
+func (l *lookingGlass) lookup(q, match string, keep func(*route) bool) ([]*result, error) {
+	addr, length := q, -1
+	if i := strings.Index(q, "/"); i != -1 {
+		addr = q[:i]
+		n, err := strconv.Atoi(q[i+1:])
+		if err != nil || n < 0 {
+			return nil, fmt.Errorf("invalid prefix %q", q)
+		}
+		length = n
+	}
+	if match == "" {
+		match = "exact"
+		if length == -1 {
+			match = "longest"
+		}
+	}
+	if length == -1 {
+		length = 8 * net.IPv6len
+		if ip := net.ParseIP(addr); ip != nil && ip.To4() != nil {
+			length = 8 * net.IPv4len
+		}
+	}
+	n, err := network(addr, length)
+	if err != nil {
+		return nil, err
+	}
+	results := make([]*result, 0)
+	switch match {
+	case "exact":
+		if pr, ok := l.prefixes[n.String()]; ok {
+			results = pr.filter(keep)
+		}
+	case "longest":
+		for ; length >= 0 && len(results) == 0; length-- {
+			m, _ := network(addr, length)
+			if pr, ok := l.prefixes[m.String()]; ok {
+				results = pr.filter(keep)
+			}
+		}
+	case "longer":
+		ones, bits := n.Mask.Size()
+		for _, pr := range l.prefixes {
+			o, b := pr.network.Mask.Size()
+			if b == bits && o >= ones && n.Contains(pr.network.IP) {
+				results = append(results, pr.filter(keep)...)
+			}
+		}
+	default:
+		return nil, fmt.Errorf("invalid match %q, supported matches are exact, longest and longer", match)
+	}
+
+	return results, nil
+}
+
+func (l *lookingGlass) handleRoutes(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	if q.Get("prefix") == "" {
+		http.Error(w, "prefix is missing", http.StatusBadRequest)
+		return
+	}
+	routerIP, peerIP, rd := q.Get("router"), q.Get("peer"), q.Get("rd")
+	keep := func(rt *route) bool {
+		return (routerIP == "" || rt.routerIP == routerIP) && (peerIP == "" || rt.peerIP == peerIP) && (rd == "" || rt.rd == rd)
+	}
+	l.RLock()
+	results, err := l.lookup(q.Get("prefix"), q.Get("match"), keep)
+	l.RUnlock()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	sort.Slice(results, func(i, j int) bool {
+		a, b := results[i], results[j]
+		if a.prefix != b.prefix {
+			return a.prefix < b.prefix
+		}
+		if a.route.routerIP != b.route.routerIP {
+			return a.route.routerIP < b.route.routerIP
+		}
+		if a.route.peerIP != b.route.peerIP {
+			return a.route.peerIP < b.route.peerIP
+		}
+		return string(a.route.msg) < string(b.route.msg)
+	})
+	if len(results) > maxRoutes {
+		results = results[:maxRoutes]
+	}
+	msgs := make([]json.RawMessage, len(results))
+	for i, res := range results {
+		msgs[i] = res.route.msg
+	}
+	writeJSON(w, msgs)
+}
+
+func (l *lookingGlass) handlePeers(w http.ResponseWriter, r *http.Request) {
+	routerIP := r.URL.Query().Get("router")
+	l.RLock()
+	peers := make([]*Peer, 0)
+	for ip, rp := range l.peers {
+		if routerIP != "" && ip != routerIP {
+			continue
+		}
+		for _, p := range rp {
+			cp := *p
+			peers = append(peers, &cp)
+		}
+	}
+	l.RUnlock()
+	sort.Slice(peers, func(i, j int) bool {
+		if peers[i].RouterIP != peers[j].RouterIP {
+			return peers[i].RouterIP < peers[j].RouterIP
+		}
+		return peers[i].PeerIP < peers[j].PeerIP
+	})
+	writeJSON(w, peers)
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		glog.Errorf("failed to send looking glass response with error: %+v", err)
+	}
+}
+
+func (l *lookingGlass) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	l.mux.ServeHTTP(w, r)
+}
+
+// NewLookingGlass instantiates a new instance of LookingGlass
+func NewLookingGlass() LookingGlass {
+	l := &lookingGlass{
+		mux:      http.NewServeMux(),
+		prefixes: make(map[string]*prefix),
+		peers:    make(map[string]map[string]*Peer),
+	}
+	l.mux.HandleFunc("/routes", l.handleRoutes)
+	l.mux.HandleFunc("/peers", l.handlePeers)
+
+	return l
+}
@@ -0,0 +1,171 @@
+package lookingglass
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/go-test/deep"
+	"github.com/sbezverk/gobmp/pkg/bmp"
+)
+
+func publish(t *testing.T, l LookingGlass, msgType int, m map[string]interface{}) {
+	b, err := json.Marshal(m)
+	if err != nil {
+		t.Fatalf("failed to marshal message with error: %+v", err)
+	}
+	if err := l.PublishMessage(msgType, nil, b); err != nil {
+		t.Fatalf("failed to publish message with error: %+v", err)
+	}
+}
+
+func get(t *testing.T, l LookingGlass, url string, v interface{}) int {
+	w := httptest.NewRecorder()
+	l.ServeHTTP(w, httptest.NewRequest(http.MethodGet, url, nil))
+	if w.Code == http.StatusOK {
+		if err := json.Unmarshal(w.Body.Bytes(), v); err != nil {
+			t.Fatalf("failed to unmarshal response with error: %+v", err)
+		}
+	}
+
+	return w.Code
+}
+
+func TestRoutes(t *testing.T) {
+	l := NewLookingGlass()
+	publish(t, l, bmp.PeerStateChangeMsg, map[string]interface{}{"action": "up", "router_ip": "192.0.2.1", "remote_ip": "192.0.2.2", "remote_asn": 65002})
+	for _, p := range []struct {
+		hash   string
+		peer   string
+		prefix string
+		length int
+	}{
+		{"a", "192.0.2.2", "10.0.0.0", 8},
+		{"b", "192.0.2.2", "10.1.0.0", 16},
+		{"c", "192.0.2.3", "10.1.0.0", 16},
+		{"d", "192.0.2.2", "10.1.1.0", 24},
+		{"e", "192.0.2.2", "2001:db8::", 32},
+	} {
+		publish(t, l, bmp.UnicastPrefixMsg, map[string]interface{}{"action": "add", "hash": p.hash, "router_ip": "192.0.2.1",
+			"peer_ip": p.peer, "prefix": p.prefix, "prefix_len": p.length})
+	}
+	tests := []struct {
+		name   string
+		url    string
+		expect []string
+		code   int
+	}{
+		{
+			name:   "exact match",
+			url:    "/routes?prefix=10.1.0.0/16",
+			expect: []string{"b", "c"},
+		},
+		{
+			name:   "exact match of peer",
+			url:    "/routes?prefix=10.1.0.0/16&peer=192.0.2.3",
+			expect: []string{"c"},
+		},
+		{
+			name:   "longest match of address",
+			url:    "/routes?prefix=10.1.1.1",
+			expect: []string{"d"},
+		},
+		{
+			name:   "longest match of prefix",
+			url:    "/routes?prefix=10.2.0.0/16&match=longest",
+			expect: []string{"a"},
+		},
+		{
+			name:   "longest match of peer",
+			url:    "/routes?prefix=10.1.1.1&peer=192.0.2.3",
+			expect: []string{"c"},
+		},
+		{
+			name:   "longer prefixes",
+			url:    "/routes?prefix=10.1.0.0/16&match=longer",
+			expect: []string{"b", "c", "d"},
+		},
+		{
+			name:   "ipv6 longest match",
+			url:    "/routes?prefix=2001:db8::1",
+			expect: []string{"e"},
+		},
+		{
+			name:   "no match",
+			url:    "/routes?prefix=192.168.0.0/16",
+			expect: []string{},
+		},
+		{
+			name: "invalid prefix",
+			url:  "/routes?prefix=10.0.0.0/33",
+			code: http.StatusBadRequest,
+		},
+		{
+			name: "invalid match",
+			url:  "/routes?prefix=10.0.0.0/8&match=shorter",
+			code: http.StatusBadRequest,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			msgs := make([]map[string]interface{}, 0)
+			code := get(t, l, tt.url, &msgs)
+			if tt.code != 0 {
+				if code != tt.code {
+					t.Fatalf("expected status %d, got %d", tt.code, code)
+				}
+				return
+			}
+			if code != http.StatusOK {
+				t.Fatalf("expected status 200, got %d", code)
+			}
+			hashes := make([]string, 0)
+			for _, m := range msgs {
+				hashes = append(hashes, m["hash"].(string))
+			}
+			if !reflect.DeepEqual(hashes, tt.expect) {
+				t.Errorf("expected routes %+v do not match returned routes %+v", tt.expect, hashes)
+			}
+		})
+	}
+}
+
+func TestPeers(t *testing.T) {
+	l := NewLookingGlass()
+	publish(t, l, bmp.PeerStateChangeMsg, map[string]interface{}{"action": "up", "router_ip": "192.0.2.1", "remote_ip": "192.0.2.2",
+		"remote_asn": 65002, "remote_bgp_id": "192.0.2.2", "timestamp": "t1"})
+	publish(t, l, bmp.UnicastPrefixMsg, map[string]interface{}{"action": "add", "hash": "a", "router_ip": "192.0.2.1",
+		"peer_ip": "192.0.2.2", "peer_asn": 65002, "prefix": "10.0.0.0", "prefix_len": 8})
+	publish(t, l, bmp.UnicastPrefixMsg, map[string]interface{}{"action": "add", "hash": "b", "router_ip": "192.0.2.1",
+		"peer_ip": "192.0.2.2", "peer_asn": 65002, "prefix": "10.1.0.0", "prefix_len": 16})
+	publish(t, l, bmp.UnicastPrefixMsg, map[string]interface{}{"action": "del", "hash": "b", "router_ip": "192.0.2.1",
+		"peer_ip": "192.0.2.2", "prefix": "10.1.0.0", "prefix_len": 16})
+	publish(t, l, bmp.UnicastPrefixMsg, map[string]interface{}{"action": "add", "hash": "c", "router_ip": "192.0.2.4",
+		"peer_ip": "192.0.2.5", "peer_asn": 65005, "prefix": "10.0.0.0", "prefix_len": 8})
+	peers := make([]*Peer, 0)
+	if code := get(t, l, "/peers", &peers); code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", code)
+	}
+	expect := []*Peer{
+		{RouterIP: "192.0.2.1", PeerIP: "192.0.2.2", PeerASN: 65002, PeerBGPID: "192.0.2.2", State: "up", LastChange: "t1", Routes: 1},
+		{RouterIP: "192.0.2.4", PeerIP: "192.0.2.5", PeerASN: 65005, State: "unknown", Routes: 1},
+	}
+	if !reflect.DeepEqual(peers, expect) {
+		t.Logf("Differences: %+v", deep.Equal(peers, expect))
+		t.Errorf("expected peers do not match returned peers")
+	}
+	// Peer down removes routes of the peer
+	publish(t, l, bmp.PeerStateChangeMsg, map[string]interface{}{"action": "down", "router_ip": "192.0.2.1", "remote_ip": "192.0.2.2", "timestamp": "t2"})
+	peers = peers[:0]
+	get(t, l, "/peers?router=192.0.2.1", &peers)
+	if len(peers) != 1 || peers[0].State != "down" || peers[0].Routes != 0 {
+		t.Errorf("expected peer down without routes, got %+v", peers)
+	}
+	msgs := make([]map[string]interface{}, 0)
+	get(t, l, "/routes?prefix=10.0.0.0/8", &msgs)
+	if len(msgs) != 1 || msgs[0]["hash"] != "c" {
+		t.Errorf("expected routes of peer down to be removed, got %+v", msgs)
+	}
+}
@@ -16,10 +16,12 @@ import (
 type Element struct {
 	Type   uint16
 	Length uint16 // Not including Type and itself
-	LS     interface{}
+	// RD is Route Distinguisher of the object carried in BGP-LS-VPN SAFI 72, nil for SAFI 71
+	RD *base.RD
+	LS interface{}
 }
 
-// NLRI71 defines Link State NLRI object for SAFI 71
+// NLRI71 defines Link State NLRI object for SAFI 71 and BGP-LS-VPN SAFI 72
 // https://tools.ietf.org/html/rfc7752#section-3.2
 type NLRI71 struct {
 	Type   uint16
@@ -33,6 +35,21 @@ func UnmarshalLSNLRI71(b []byte) (*NLRI71, error) {
 	if glog.V(6) {
 		glog.Infof("LSNLRI71 Raw: %s ", tools.MessageHex(b))
 	}
+
+	return unmarshalLSNLRI(b, false)
+}
+
+// UnmarshalLSVPNNLRI72 builds Link State NLRI object for BGP-LS-VPN SAFI 72, each NLRI carries
+// Route Distinguisher in front of the NLRI of SAFI 71
+func UnmarshalLSVPNNLRI72(b []byte) (*NLRI71, error) {
+	if glog.V(6) {
+		glog.Infof("LSVPNNLRI72 Raw: %s ", tools.MessageHex(b))
+	}
+
+	return unmarshalLSNLRI(b, true)
+}
+
+func unmarshalLSNLRI(b []byte, vpn bool) (*NLRI71, error) {
 	if len(b) == 0 {
 		return nil, fmt.Errorf("NLRI length is 0")
 	}
@@ -45,6 +62,19 @@ func UnmarshalLSNLRI71(b []byte) (*NLRI71, error) {
 		p += 2
 		el.Length = binary.BigEndian.Uint16(b[p : p+2])
 		p += 2
+		if vpn {
+			if el.Length < 8 || p+8 > len(b) {
+				return nil, fmt.Errorf("not enough bytes to reconstruct route distinguisher of ls vpn nlri")
+			}
+			rd, err := base.MakeRD(b[p : p+8])
+			if err != nil {
+				return nil, err
+			}
+			el.RD = rd
+			p += 8
+			// The length of the object does not include Route Distinguisher
+			el.Length -= 8
+		}
 
 		switch el.Type {
 		case 1:
@@ -0,0 +1,234 @@
+package macmove
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sbezverk/gobmp/pkg/bgp"
+	"github.com/sbezverk/gobmp/pkg/bmp"
+	"github.com/sbezverk/gobmp/pkg/pub"
+)
+
+// Detector defines a Publisher consuming EVPN messages and publishing mac_event events for MACs moving
+// between locations.
+type Detector interface {
+	pub.Publisher
+}
+
+// EventType defines the type of MAC event
+type EventType string
+
+const (
+	// MACMove is generated when a MAC is advertised from a new location with a higher MAC Mobility sequence number
+	MACMove EventType = "mac_move"
+	// DuplicateMACSuspected is generated when a MAC moves threshold times within the window
+	DuplicateMACSuspected EventType = "duplicate_mac_suspected"
+)
+
+// Event defines MAC event, a location of a MAC is its Ethernet Segment for multi-homed MACs or the next hop
+// of the advertising PE for single-homed MACs. Moves is the number of moves of the MAC within the window.
+type Event struct {
+	Event        EventType `json:"event"`
+	Timestamp    string    `json:"timestamp"`
+	MAC          string    `json:"mac"`
+	RouteTargets []string  `json:"route_targets,omitempty"`
+	VPNRD        string    `json:"vpn_rd,omitempty"`
+	RouterIP     string    `json:"router_ip,omitempty"`
+	PeerIP       string    `json:"peer_ip,omitempty"`
+	Nexthop      string    `json:"nexthop,omitempty"`
+	ESI          string    `json:"eth_segment_id,omitempty"`
+	Sequence     uint32    `json:"sequence"`
+	Sticky       bool      `json:"sticky,omitempty"`
+	OldNexthop   string    `json:"old_nexthop,omitempty"`
+	OldESI       string    `json:"old_eth_segment_id,omitempty"`
+	OldSequence  uint32    `json:"old_sequence"`
+	Moves        int       `json:"moves"`
+	Window       int64     `json:"window_sec"`
+}
+
+type macState struct {
+	nexthop  string
+	esi      string
+	sequence uint32
+	moves    []time.Time
+	reported time.Time
+}
+
+type detector struct {
+	sync.Mutex
+	window    time.Duration
+	threshold int
+	events    pub.Publisher
+	macs      map[string]*macState
+	now       func() time.Time
+}
+
+var _ Detector = &detector{}
+
+// route defines fields of EVPN messages used by the detector
+type route struct {
+	Action         string              `json:"action"`
+	RouterIP       string              `json:"router_ip"`
+	PeerIP         string              `json:"peer_ip"`
+	BaseAttributes *bgp.BaseAttributes `json:"base_attrs"`
+	Nexthop        string              `json:"nexthop"`
+	VPNRD          string              `json:"vpn_rd"`
+	ESI            string              `json:"eth_segment_id"`
+	ESIFields      *json.RawMessage    `json:"eth_segment_id_fields"`
+	MAC            string              `json:"mac"`
+	RouteType      uint8               `json:"route_type"`
+}
+
+func (d *detector) PublishMessage(msgType int, msgHash []byte, msg []byte) error {
+	if msgType != bmp.EVPNMsg {
+		return nil
+	}
+	r := &route{}
+	if err := json.Unmarshal(msg, r); err != nil {
+		return fmt.Errorf("failed to unmarshal evpn message with error: %+v", err)
+	}
+	// Only advertisements of MAC/IP Advertisement routes carry MAC Mobility extended community
+	if r.RouteType != 2 || r.MAC == "" || r.Action == "del" {
+		return nil
+	}
+	for _, e := range d.process(r) {
+		b, err := json.Marshal(e)
+		if err != nil {
+			return fmt.Errorf("failed to marshal mac event with error: %+v", err)
+		}
+		if err := d.events.PublishMessage(bmp.MACEventMsg, []byte(e.MAC), b); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (d *detector) Stop() {}
+
+// mobility returns MAC Mobility sequence number and sticky flag of extended communities, MACs advertised
+// without the community have sequence number 0.
+func mobility(extComms []string) (uint32, bool) {
+	for _, c := range extComms {
+		if !strings.HasPrefix(c, bgp.ECPMACMobility) {
+			continue
+		}
+		v := strings.SplitN(strings.TrimPrefix(c, bgp.ECPMACMobility), ":", 2)
+		if len(v) != 2 {
+			return 0, false
+		}
+		flags, _ := strconv.Atoi(v[0])
+		seq, _ := strconv.ParseUint(v[1], 10, 32)
+		return uint32(seq), flags&0x01 != 0
+	}
+
+	return 0, false
+}
+
+// routeTargets returns sorted Route Targets of extended communities, they identify the EVPN instance of a MAC
+func routeTargets(extComms []string) []string {
+	rts := make([]string, 0)
+	for _, c := range extComms {
+		if strings.HasPrefix(c, bgp.ECPRouteTarget) {
+			rts = append(rts, strings.TrimPrefix(c, bgp.ECPRouteTarget))
+		}
+	}
+	sort.Strings(rts)
+
+	return rts
+}
+
+// process records the location and sequence number of the MAC and returns mac_move event when the MAC is
+// advertised from a new location with a higher sequence number, duplicate_mac_suspected event follows when
+// the number of moves within the window reaches the threshold. Advertisements of a known location or of
+// a lower or equal sequence number, re-advertised by many routers and peers, do not change the state.
+func (d *detector) process(r *route) []*Event {
+	var extComms []string
+	if r.BaseAttributes != nil {
+		extComms = r.BaseAttributes.ExtCommunityList
+	}
+	seq, sticky := mobility(extComms)
+	rts := routeTargets(extComms)
+	// MAC is single-homed when ESI is 0, then the PE is its location
+	esi := ""
+	if r.ESIFields != nil {
+		esi = r.ESI
+	}
+	k := strings.Join(rts, ",") + "_" + r.MAC
+	now := d.now()
+	d.Lock()
+	defer d.Unlock()
+	s, ok := d.macs[k]
+	if !ok {
+		d.macs[k] = &macState{nexthop: r.Nexthop, esi: esi, sequence: seq}
+		return nil
+	}
+	if seq <= s.sequence || (s.nexthop == r.Nexthop && s.esi == esi) {
+		if seq > s.sequence {
+			s.sequence = seq
+		}
+		return nil
+	}
+	s.moves = append(s.moves, now)
+	s.expire(now.Add(-d.window))
+	e := &Event{
+		Event:        MACMove,
+		Timestamp:    now.UTC().Format(time.RFC3339Nano),
+		MAC:          r.MAC,
+		RouteTargets: rts,
+		VPNRD:        r.VPNRD,
+		RouterIP:     r.RouterIP,
+		PeerIP:       r.PeerIP,
+		Nexthop:      r.Nexthop,
+		ESI:          esi,
+		Sequence:     seq,
+		Sticky:       sticky,
+		OldNexthop:   s.nexthop,
+		OldESI:       s.esi,
+		OldSequence:  s.sequence,
+		Moves:        len(s.moves),
+		Window:       int64(d.window.Seconds()),
+	}
+	s.nexthop, s.esi, s.sequence = r.Nexthop, esi, seq
+	events := []*Event{e}
+	if d.threshold == 0 || len(s.moves) < d.threshold {
+		return events
+	}
+	if !s.reported.IsZero() && now.Sub(s.reported) < d.window {
+		return events
+	}
+	s.reported = now
+	dup := *e
+	dup.Event = DuplicateMACSuspected
+
+	return append(events, &dup)
+}
+
+// expire removes moves older than the time
+func (s *macState) expire(t time.Time) {
+	i := 0
+	for ; i < len(s.moves); i++ {
+		if s.moves[i].After(t) {
+			break
+		}
+	}
+	s.moves = s.moves[i:]
+}
+
+// NewDetector instantiates a new MAC move Detector, mac_event events are published to events publisher,
+// duplicate_mac_suspected event is published when a MAC moves threshold times within the window,
+// threshold 0 disables duplicate MAC detection.
+func NewDetector(window time.Duration, threshold int, events pub.Publisher) Detector {
+	return &detector{
+		window:    window,
+		threshold: threshold,
+		events:    events,
+		macs:      make(map[string]*macState),
+		now:       time.Now,
+	}
+}
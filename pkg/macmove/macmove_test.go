@@ -0,0 +1,144 @@
+package macmove
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/go-test/deep"
+	"github.com/sbezverk/gobmp/pkg/bmp"
+)
+
+// eventCollector is a Publisher collecting MAC events
+type eventCollector struct {
+	events []*Event
+}
+
+func (c *eventCollector) PublishMessage(msgType int, msgHash []byte, msg []byte) error {
+	if msgType != bmp.MACEventMsg {
+		return fmt.Errorf("unexpected message type %d", msgType)
+	}
+	e := &Event{}
+	if err := json.Unmarshal(msg, e); err != nil {
+		return err
+	}
+	c.events = append(c.events, e)
+
+	return nil
+}
+
+func (c *eventCollector) Stop() {}
+
+// advertisement returns EVPN MAC/IP Advertisement route of the MAC from the next hop with MAC Mobility
+// sequence number, seq below 0 omits MAC Mobility extended community.
+func advertisement(nexthop string, esi string, seq int) string {
+	extComms := `"rt=65000:100"`
+	if seq >= 0 {
+		extComms += fmt.Sprintf(`,"macmob=0:%d"`, seq)
+	}
+	esiFields := ""
+	if esi != "00:00:00:00:00:00:00:00:00:00" {
+		esiFields = `,"eth_segment_id_fields":{"type":0}`
+	}
+	return fmt.Sprintf(`{"action":"add","router_ip":"192.0.2.1","peer_ip":"192.0.2.2","route_type":2,"mac":"00:81:c4:bc:77:8a",`+
+		`"vpn_rd":"%s:100","nexthop":"%s","eth_segment_id":"%s"%s,"base_attrs":{"ext_community_list":[%s]}}`, nexthop, nexthop, esi, esiFields, extComms)
+}
+
+func TestDetector(t *testing.T) {
+	zero := "00:00:00:00:00:00:00:00:00:00"
+	es := "00:01:02:03:04:05:06:07:08:09"
+	tests := []struct {
+		name   string
+		msgs   []string
+		expect []*Event
+	}{
+		{
+			name: "re-advertisements and stale sequence numbers are not moves",
+			msgs: []string{
+				advertisement("192.0.2.10", zero, -1),
+				advertisement("192.0.2.10", zero, 0),
+				advertisement("192.0.2.11", zero, 0),
+				advertisement("192.0.2.11", zero, 1),
+				advertisement("192.0.2.10", zero, 0),
+			},
+			expect: []*Event{
+				{Event: MACMove, MAC: "00:81:c4:bc:77:8a", RouteTargets: []string{"65000:100"}, VPNRD: "192.0.2.11:100", RouterIP: "192.0.2.1", PeerIP: "192.0.2.2",
+					Nexthop: "192.0.2.11", Sequence: 1, OldNexthop: "192.0.2.10", Moves: 1, Window: 180},
+			},
+		},
+		{
+			name: "move to ethernet segment",
+			msgs: []string{
+				advertisement("192.0.2.10", zero, 0),
+				advertisement("192.0.2.11", es, 1),
+				advertisement("192.0.2.12", es, 1),
+			},
+			expect: []*Event{
+				{Event: MACMove, MAC: "00:81:c4:bc:77:8a", RouteTargets: []string{"65000:100"}, VPNRD: "192.0.2.11:100", RouterIP: "192.0.2.1", PeerIP: "192.0.2.2",
+					Nexthop: "192.0.2.11", ESI: es, Sequence: 1, OldNexthop: "192.0.2.10", Moves: 1, Window: 180},
+			},
+		},
+		{
+			name: "duplicate mac is reported once per window",
+			msgs: []string{
+				advertisement("192.0.2.10", zero, 0),
+				advertisement("192.0.2.11", zero, 1),
+				advertisement("192.0.2.10", zero, 2),
+				advertisement("192.0.2.11", zero, 3),
+				advertisement("192.0.2.10", zero, 4),
+			},
+			expect: []*Event{
+				{Event: MACMove, MAC: "00:81:c4:bc:77:8a", RouteTargets: []string{"65000:100"}, VPNRD: "192.0.2.11:100", RouterIP: "192.0.2.1", PeerIP: "192.0.2.2",
+					Nexthop: "192.0.2.11", Sequence: 1, OldNexthop: "192.0.2.10", Moves: 1, Window: 180},
+				{Event: MACMove, MAC: "00:81:c4:bc:77:8a", RouteTargets: []string{"65000:100"}, VPNRD: "192.0.2.10:100", RouterIP: "192.0.2.1", PeerIP: "192.0.2.2",
+					Nexthop: "192.0.2.10", Sequence: 2, OldNexthop: "192.0.2.11", OldSequence: 1, Moves: 2, Window: 180},
+				{Event: MACMove, MAC: "00:81:c4:bc:77:8a", RouteTargets: []string{"65000:100"}, VPNRD: "192.0.2.11:100", RouterIP: "192.0.2.1", PeerIP: "192.0.2.2",
+					Nexthop: "192.0.2.11", Sequence: 3, OldNexthop: "192.0.2.10", OldSequence: 2, Moves: 3, Window: 180},
+				{Event: DuplicateMACSuspected, MAC: "00:81:c4:bc:77:8a", RouteTargets: []string{"65000:100"}, VPNRD: "192.0.2.11:100", RouterIP: "192.0.2.1", PeerIP: "192.0.2.2",
+					Nexthop: "192.0.2.11", Sequence: 3, OldNexthop: "192.0.2.10", OldSequence: 2, Moves: 3, Window: 180},
+				{Event: MACMove, MAC: "00:81:c4:bc:77:8a", RouteTargets: []string{"65000:100"}, VPNRD: "192.0.2.10:100", RouterIP: "192.0.2.1", PeerIP: "192.0.2.2",
+					Nexthop: "192.0.2.10", Sequence: 4, OldNexthop: "192.0.2.11", OldSequence: 3, Moves: 4, Window: 180},
+			},
+		},
+	}
+	start := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &eventCollector{}
+			d := NewDetector(180*time.Second, 3, c).(*detector)
+			now := start
+			d.now = func() time.Time { return now }
+			for _, m := range tt.msgs {
+				now = now.Add(time.Second)
+				if err := d.PublishMessage(bmp.EVPNMsg, nil, []byte(m)); err != nil {
+					t.Fatalf("failed to publish message with error: %+v", err)
+				}
+			}
+			for _, e := range c.events {
+				e.Timestamp = ""
+			}
+			if diff := deep.Equal(c.events, tt.expect); diff != nil {
+				t.Errorf("expected and actual events do not match, differences: %+v", diff)
+			}
+		})
+	}
+}
+
+func TestMobility(t *testing.T) {
+	tests := []struct {
+		extComms []string
+		seq      uint32
+		sticky   bool
+	}{
+		{extComms: nil},
+		{extComms: []string{"rt=65000:100", "macmob=0:7"}, seq: 7},
+		{extComms: []string{"macmob=1:4294967295"}, seq: 4294967295, sticky: true},
+	}
+	for _, tt := range tests {
+		seq, sticky := mobility(tt.extComms)
+		if seq != tt.seq || sticky != tt.sticky {
+			t.Errorf("extended communities %v expected sequence %d sticky %t, got %d %t", tt.extComms, tt.seq, tt.sticky, seq, sticky)
+		}
+	}
+}
@@ -2,8 +2,8 @@ package message
 
 import (
 	"fmt"
-	"net"
 
+	"github.com/sbezverk/gobmp/pkg/addr"
 	"github.com/sbezverk/gobmp/pkg/base"
 	"github.com/sbezverk/gobmp/pkg/bgp"
 	"github.com/sbezverk/gobmp/pkg/bmp"
@@ -37,28 +37,42 @@ func (p *producer) nlri(op int, ph *bmp.PerPeerHeader, update *bgp.Update) ([]Un
 	prfxs := make([]UnicastPrefix, 0)
 	for _, pr := range routes {
 		prfx := UnicastPrefix{
-			Action:         operation,
-			RouterHash:     p.speakerHash,
-			RouterIP:       p.speakerIP,
-			PeerHash:       ph.GetPeerHash(),
-			PeerASN:        ph.PeerAS,
-			Timestamp:      ph.GetPeerTimestamp(),
-			PeerType:       uint8(ph.PeerType),
-			PrefixLen:      int32(pr.Length),
-			PathID:         int32(pr.PathID),
-			BaseAttributes: update.BaseAttributes,
-		}
-		if ases := update.BaseAttributes.ASPath; len(ases) != 0 {
-			// Last element in AS_PATH would be the AS of the origin
-			prfx.OriginAS = int32(ases[len(ases)-1])
+			Action:                  operation,
+			RouterHash:              p.speakerHash,
+			RouterIP:                p.speakerIP,
+			PeerHash:                p.peerHash(ph),
+			PeerASN:                 ph.PeerAS,
+			Timestamp:               ph.GetPeerTimestamp(),
+			TimestampEpoch:          ph.GetPeerTimestampEpoch(),
+			CollectorTimestamp:      ph.GetCollectorTimestamp(),
+			CollectorTimestampEpoch: ph.GetCollectorTimestampEpoch(),
+			PeerType:                uint8(ph.PeerType),
+			PeerRD:                  ph.GetPeerDistinguisherString(),
+			Validation:              update.Validation,
+			PrefixLen:               int32(pr.Length),
+			PathID:                  int32(pr.PathID),
+			BaseAttributes:          update.BaseAttributes,
 		}
+		prfx.OriginAS = int32(update.BaseAttributes.OriginAS)
 		prfx.IsIPv4 = true
+		prfx.SAFI = 1
 		prfx.PeerIP = ph.GetPeerAddrString()
 		prfx.Nexthop = update.BaseAttributes.Nexthop
 		prfx.IsNexthopIPv4 = true
+		if prfx.Nexthop != "" {
+			prfx.NexthopAFI = 1
+		}
 		a := make([]byte, 4)
 		copy(a, pr.Prefix)
-		prfx.Prefix = net.IP(a).To4().String()
+		prfx.Prefix = addr.IPv4(a)
+		prfx.RPKIStatus = p.rpkiStatus(&prfx)
+		if op == 0 {
+			prfx.LeakSuspect = p.leakSuspect(ph, update.BaseAttributes)
+		}
+		// IPv4 Unicast over SRv6 core carries BGP Attribute 40 (Prefix SID) with SRv6 L3 Service
+		if psid, err := update.GetAttrPrefixSID(); err == nil {
+			prfx.PrefixSID = psid
+		}
 		if f, err := ph.IsAdjRIBInPost(); err == nil {
 			prfx.IsAdjRIBInPost = f
 		}
@@ -0,0 +1,66 @@
+package message
+
+import (
+	"github.com/sbezverk/gobmp/pkg/base"
+	"github.com/sbezverk/gobmp/pkg/bgp"
+)
+
+// bgpEPE returns BGP Egress Peer Engineering message of ls_link message of BGP protocol, nil is returned for links
+// of other protocols and for advertised links without Peer SIDs.
+func (p *producer) bgpEPE(link *LSLink, update *bgp.Update) *BGPEPE {
+	if link.ProtocolID != base.BGP {
+		return nil
+	}
+	msg := BGPEPE{
+		Action:                  link.Action,
+		RouterHash:              link.RouterHash,
+		RouterIP:                link.RouterIP,
+		PeerHash:                link.PeerHash,
+		PeerIP:                  link.PeerIP,
+		PeerType:                link.PeerType,
+		PeerRD:                  link.PeerRD,
+		VPNRD:                   link.VPNRD,
+		PeerASN:                 link.PeerASN,
+		Timestamp:               link.Timestamp,
+		TimestampEpoch:          link.TimestampEpoch,
+		CollectorTimestamp:      link.CollectorTimestamp,
+		CollectorTimestampEpoch: link.CollectorTimestampEpoch,
+		Validation:              link.Validation,
+		Nexthop:                 link.Nexthop,
+		LocalNodeHash:           link.LocalNodeHash,
+		RemoteNodeHash:          link.RemoteNodeHash,
+		LocalASN:                link.LocalNodeASN,
+		RemoteASN:               link.RemoteNodeASN,
+		MemberAS:                link.MemberAS,
+		BGPRouterID:             link.BGPRouterID,
+		BGPRemoteRouterID:       link.BGPRemoteRouterID,
+		LocalLinkID:             link.LocalLinkID,
+		RemoteLinkID:            link.RemoteLinkID,
+		LocalLinkIP:             link.LocalLinkIP,
+		RemoteLinkIP:            link.RemoteLinkIP,
+		PeerNodeSID:             link.PeerNodeSID,
+		PeerAdjSID:              link.PeerAdjSID,
+		IsAdjRIBInPost:          link.IsAdjRIBInPost,
+		IsAdjRIBOutPost:         link.IsAdjRIBOutPost,
+		IsLocRIBFiltered:        link.IsLocRIBFiltered,
+	}
+	if lslink, err := update.GetNLRI29(); err == nil {
+		if sids, err := lslink.GetPeerSetSIDs(); err == nil && len(sids) != 0 {
+			msg.PeerSetSIDs = sids
+		}
+	}
+	// Withdrawn links do not carry BGP-LS attribute, they are published regardless of Peer SIDs
+	if msg.Action == "add" && msg.PeerNodeSID == nil && msg.PeerAdjSID == nil && len(msg.PeerSetSIDs) == 0 {
+		return nil
+	}
+	switch {
+	case msg.PeerAdjSID != nil || msg.LocalLinkID != 0 || msg.RemoteLinkID != 0:
+		msg.EPEType = "peer_adj"
+	case msg.PeerNodeSID == nil && len(msg.PeerSetSIDs) != 0:
+		msg.EPEType = "peer_set"
+	default:
+		msg.EPEType = "peer_node"
+	}
+
+	return &msg
+}
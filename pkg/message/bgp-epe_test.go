@@ -0,0 +1,134 @@
+package message
+
+import (
+	"testing"
+
+	"github.com/go-test/deep"
+	"github.com/sbezverk/gobmp/pkg/base"
+	"github.com/sbezverk/gobmp/pkg/bgp"
+	"github.com/sbezverk/gobmp/pkg/sr"
+)
+
+func peerLabel(flags byte, label uint32) *sr.PeerSID {
+	sid, _ := sr.UnmarshalPeerSID([]byte{flags, 0x00, 0x00, 0x00, byte(label >> 16), byte(label >> 8), byte(label)})
+	return sid
+}
+
+func TestBGPEPE(t *testing.T) {
+	// Peer Node SID 15000 and PeerSet SIDs 15100 and 15101
+	attr := []byte{
+		0x04, 0x4d, 0x00, 0x07, 0xc0, 0x00, 0x00, 0x00, 0x00, 0x3a, 0x98,
+		0x04, 0x4f, 0x00, 0x07, 0xc0, 0x00, 0x00, 0x00, 0x00, 0x3a, 0xfc,
+		0x04, 0x4f, 0x00, 0x07, 0xc0, 0x00, 0x00, 0x00, 0x00, 0x3a, 0xfd,
+	}
+	sets := &bgp.Update{PathAttributes: []bgp.PathAttribute{{AttributeType: 29, AttributeLength: uint16(len(attr)), Attribute: attr}}}
+	node := peerLabel(0xc0, 15000)
+	adj := peerLabel(0xc0, 24000)
+	tests := []struct {
+		name   string
+		link   *LSLink
+		update *bgp.Update
+		expect *BGPEPE
+	}{
+		{
+			name: "peer node with peer sets",
+			link: &LSLink{
+				Action:            "add",
+				ProtocolID:        base.BGP,
+				LocalNodeASN:      65000,
+				RemoteNodeASN:     65001,
+				BGPRouterID:       "10.0.0.1",
+				BGPRemoteRouterID: "10.0.0.2",
+				LocalLinkIP:       "192.0.2.1",
+				RemoteLinkIP:      "192.0.2.2",
+				PeerNodeSID:       node,
+			},
+			update: sets,
+			expect: &BGPEPE{
+				Action:            "add",
+				EPEType:           "peer_node",
+				LocalASN:          65000,
+				RemoteASN:         65001,
+				BGPRouterID:       "10.0.0.1",
+				BGPRemoteRouterID: "10.0.0.2",
+				LocalLinkIP:       "192.0.2.1",
+				RemoteLinkIP:      "192.0.2.2",
+				PeerNodeSID:       node,
+				PeerSetSIDs:       []*sr.PeerSID{peerLabel(0xc0, 15100), peerLabel(0xc0, 15101)},
+			},
+		},
+		{
+			name: "peer adjacency",
+			link: &LSLink{
+				Action:       "add",
+				ProtocolID:   base.BGP,
+				LocalLinkID:  1,
+				RemoteLinkID: 2,
+				PeerAdjSID:   adj,
+			},
+			update: &bgp.Update{},
+			expect: &BGPEPE{
+				Action:       "add",
+				EPEType:      "peer_adj",
+				LocalLinkID:  1,
+				RemoteLinkID: 2,
+				PeerAdjSID:   adj,
+			},
+		},
+		{
+			name: "peer set only",
+			link: &LSLink{
+				Action:     "add",
+				ProtocolID: base.BGP,
+			},
+			update: &bgp.Update{PathAttributes: []bgp.PathAttribute{{AttributeType: 29, Attribute: attr[11:]}}},
+			expect: &BGPEPE{
+				Action:      "add",
+				EPEType:     "peer_set",
+				PeerSetSIDs: []*sr.PeerSID{peerLabel(0xc0, 15100), peerLabel(0xc0, 15101)},
+			},
+		},
+		{
+			name: "withdrawn peer node",
+			link: &LSLink{
+				Action:       "del",
+				ProtocolID:   base.BGP,
+				LocalLinkIP:  "192.0.2.1",
+				RemoteLinkIP: "192.0.2.2",
+			},
+			update: &bgp.Update{},
+			expect: &BGPEPE{
+				Action:       "del",
+				EPEType:      "peer_node",
+				LocalLinkIP:  "192.0.2.1",
+				RemoteLinkIP: "192.0.2.2",
+			},
+		},
+		{
+			name: "bgp link without peer sids",
+			link: &LSLink{
+				Action:     "add",
+				ProtocolID: base.BGP,
+			},
+			update: &bgp.Update{},
+		},
+		{
+			name: "isis link",
+			link: &LSLink{
+				Action:      "add",
+				ProtocolID:  base.ISISL2,
+				PeerNodeSID: node,
+			},
+			update: sets,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &producer{}
+			got := p.bgpEPE(tt.link, tt.update)
+			if diff := deep.Equal(got, tt.expect); len(diff) != 0 {
+				t.Errorf("expected and actual bgp_epe messages do not match, differences: %+v", diff)
+			}
+		})
+	}
+}
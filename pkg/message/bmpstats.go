@@ -3,6 +3,7 @@ package message
 import (
 	"encoding/binary"
 	"encoding/json"
+	"time"
 
 	"github.com/golang/glog"
 	"github.com/sbezverk/gobmp/pkg/bmp"
@@ -26,43 +27,125 @@ func (p *producer) produceStatsMessage(msg bmp.Message) {
 	}
 
 	m := Stats{
-		RemoteASN:  msg.PeerHeader.PeerAS,
-		PeerRD:     msg.PeerHeader.GetPeerDistinguisherString(),
-		Timestamp:  msg.PeerHeader.GetPeerTimestamp(),
-		RouterHash: p.speakerHash,
-		RouterIP:   p.speakerIP,
-		PeerType:   uint8(msg.PeerHeader.PeerType),
+		RemoteASN:               msg.PeerHeader.PeerAS,
+		PeerRD:                  msg.PeerHeader.GetPeerDistinguisherString(),
+		Timestamp:               msg.PeerHeader.GetPeerTimestamp(),
+		TimestampEpoch:          msg.PeerHeader.GetPeerTimestampEpoch(),
+		CollectorTimestamp:      msg.PeerHeader.GetCollectorTimestamp(),
+		CollectorTimestampEpoch: msg.PeerHeader.GetCollectorTimestampEpoch(),
+		RouterHash:              p.speakerHash,
+		RouterIP:                p.speakerIP,
+		PeerHash:                p.peerHash(msg.PeerHeader),
+		PeerType:                uint8(msg.PeerHeader.PeerType),
 	}
 	m.RemoteIP = msg.PeerHeader.GetPeerAddrString()
 	m.RemoteBGPID = msg.PeerHeader.GetPeerBGPIDString()
+	sample := &statsSample{values: make(map[string]uint64)}
 	for _, tlv := range StatsMsg.StatsTLV {
 		switch tlv.InformationType {
 		case 1:
 			m.DuplicatePrefixs = binary.BigEndian.Uint32(tlv.Information)
+			sample.values["duplicate_prefix"] = uint64(m.DuplicatePrefixs)
 		case 2:
 			m.DuplicateWithDraws = binary.BigEndian.Uint32(tlv.Information)
+			sample.values["duplicate_withdraws"] = uint64(m.DuplicateWithDraws)
 		case 3:
 			m.InvalidatedDueCluster = binary.BigEndian.Uint32(tlv.Information)
+			sample.values["invalidated_due_cluster"] = uint64(m.InvalidatedDueCluster)
 		case 4:
 			m.InvalidatedDueAspath = binary.BigEndian.Uint32(tlv.Information)
+			sample.values["invalidated_due_aspath"] = uint64(m.InvalidatedDueAspath)
 		case 5:
 			m.InvalidatedDueOriginatorId = binary.BigEndian.Uint32(tlv.Information)
+			sample.values["invalidated_due_originator_id"] = uint64(m.InvalidatedDueOriginatorId)
 		case 6:
 			m.InvalidatedAsConfed = binary.BigEndian.Uint32(tlv.Information)
+			sample.values["invalidated_due_asconfed"] = uint64(m.InvalidatedAsConfed)
 		case 7:
 			m.AdjRIBsIn = binary.BigEndian.Uint64(tlv.Information)
+			sample.values["ads_rib_in"] = m.AdjRIBsIn
 		case 8:
 			m.LocalRib = binary.BigEndian.Uint64(tlv.Information)
+			sample.values["local_rib"] = m.LocalRib
 		case 11:
 			m.UpdatesAsWithdraw = binary.BigEndian.Uint32(tlv.Information)
+			sample.values["updates_as_withdraw"] = uint64(m.UpdatesAsWithdraw)
 		case 12:
 			m.PrefixesAsWithdraw = binary.BigEndian.Uint32(tlv.Information)
+			sample.values["prefixes_as_withdraw"] = uint64(m.PrefixesAsWithdraw)
 		default:
-			glog.Warningf("unprocessed stats type:%v", tlv.InformationType)
+			m.VendorTLVs = append(m.VendorTLVs, bmp.DecodeVendorTLV(bmp.StatsReportMsg, 0, uint16(tlv.InformationType), tlv.Information))
 		}
 	}
+	sample.epoch = m.TimestampEpoch
+	if sample.epoch == 0 {
+		sample.epoch = m.CollectorTimestampEpoch
+	}
+	if prev := p.setStatsSample(m.PeerHash, sample); prev != nil {
+		m.Delta = statsDelta(prev, sample)
+	}
 	if err := p.marshalAndPublish(&m, bmp.StatsReportMsg, []byte(m.RouterHash), false); err != nil {
 		glog.Errorf("failed to process peer Stats Report message with error: %+v", err)
 		return
 	}
 }
+
+// statsGauges lists statistics which are numbers of routes rather than counters
+var statsGauges = map[string]bool{
+	"ads_rib_in": true,
+	"local_rib":  true,
+}
+
+// statsSample defines statistics of Stats Report indexed by their names, epoch is the report's timestamp in microseconds
+type statsSample struct {
+	epoch  int64
+	values map[string]uint64
+}
+
+// setStatsSample stores the latest Stats Report of the peer and returns the previous one, nil removes the peer's entry
+func (p *producer) setStatsSample(peerHash string, s *statsSample) *statsSample {
+	p.statsMtx.Lock()
+	defer p.statsMtx.Unlock()
+	prev := p.stats[peerHash]
+	if s == nil {
+		delete(p.stats, peerHash)
+		return prev
+	}
+	if p.stats == nil {
+		p.stats = make(map[string]*statsSample)
+	}
+	p.stats[peerHash] = s
+
+	return prev
+}
+
+// statsDelta returns changes of statistics between two consecutive Stats Reports of the peer
+func statsDelta(prev, cur *statsSample) *StatsDelta {
+	d := &StatsDelta{
+		IntervalUs: cur.epoch - prev.epoch,
+		Changes:    make(map[string]int64),
+	}
+	for name, v := range cur.values {
+		pv, ok := prev.values[name]
+		if !ok {
+			continue
+		}
+		switch {
+		case statsGauges[name]:
+			d.Changes[name] = int64(v) - int64(pv)
+		case v < pv:
+			// Counter was restarted
+			d.Changes[name] = int64(v)
+		default:
+			d.Changes[name] = int64(v - pv)
+		}
+	}
+	if d.IntervalUs > 0 {
+		d.Rates = make(map[string]float64, len(d.Changes))
+		for name, c := range d.Changes {
+			d.Rates[name] = float64(c) * float64(time.Second/time.Microsecond) / float64(d.IntervalUs)
+		}
+	}
+
+	return d
+}
@@ -0,0 +1,51 @@
+package message
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestStatsDelta(t *testing.T) {
+	tests := []struct {
+		name   string
+		prev   *statsSample
+		cur    *statsSample
+		expect *StatsDelta
+	}{
+		{
+			name: "counters and gauges",
+			prev: &statsSample{epoch: 1000000, values: map[string]uint64{"duplicate_prefix": 10, "ads_rib_in": 1500}},
+			cur:  &statsSample{epoch: 3000000, values: map[string]uint64{"duplicate_prefix": 30, "ads_rib_in": 1400}},
+			expect: &StatsDelta{
+				IntervalUs: 2000000,
+				Changes:    map[string]int64{"duplicate_prefix": 20, "ads_rib_in": -100},
+				Rates:      map[string]float64{"duplicate_prefix": 10, "ads_rib_in": -50},
+			},
+		},
+		{
+			name: "restarted counter",
+			prev: &statsSample{epoch: 1000000, values: map[string]uint64{"updates_as_withdraw": 100}},
+			cur:  &statsSample{epoch: 2000000, values: map[string]uint64{"updates_as_withdraw": 5}},
+			expect: &StatsDelta{
+				IntervalUs: 1000000,
+				Changes:    map[string]int64{"updates_as_withdraw": 5},
+				Rates:      map[string]float64{"updates_as_withdraw": 5},
+			},
+		},
+		{
+			name: "statistic missing in previous report",
+			prev: &statsSample{epoch: 1000000, values: map[string]uint64{}},
+			cur:  &statsSample{epoch: 1000000, values: map[string]uint64{"local_rib": 5}},
+			expect: &StatsDelta{
+				Changes: map[string]int64{},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := statsDelta(tt.prev, tt.cur); !reflect.DeepEqual(tt.expect, got) {
+				t.Fatalf("expected delta %+v, got %+v", tt.expect, got)
+			}
+		})
+	}
+}
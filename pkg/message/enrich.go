@@ -0,0 +1,51 @@
+package message
+
+import (
+	"encoding/json"
+
+	"github.com/sbezverk/gobmp/pkg/enrich"
+)
+
+// enrichMessage returns key fields of the message passed to enrichment plugins
+func enrichMessage(msgType int, msg interface{}) *enrich.Message {
+	em := &enrich.Message{
+		Type: msgType,
+	}
+	switch m := msg.(type) {
+	case *UnicastPrefix:
+		em.RouterIP = m.RouterIP
+		em.PeerIP = m.PeerIP
+		em.PeerASN = m.PeerASN
+		em.Prefix = m.Prefix
+		em.PrefixLen = m.PrefixLen
+		em.OriginAS = uint32(m.OriginAS)
+	case *L3VPNPrefix:
+		em.RouterIP = m.RouterIP
+		em.PeerIP = m.PeerIP
+		em.PeerASN = m.PeerASN
+		em.Prefix = m.Prefix
+		em.PrefixLen = m.PrefixLen
+		em.OriginAS = uint32(m.OriginAS)
+	case *PeerStateChange:
+		em.RouterIP = m.RouterIP
+		em.PeerIP = m.RemoteIP
+		em.PeerASN = m.RemoteASN
+	}
+
+	return em
+}
+
+// enrich invokes enrichment plugins and adds returned fields as "enrichment" object to json marshaled message,
+// returned fields are also passed to typed publishers.
+func (p *producer) enrich(msgType int, msg interface{}, j []byte) ([]byte, map[string]interface{}, error) {
+	fields := enrich.Enrich(p.enrichers, enrichMessage(msgType, msg))
+	if len(fields) == 0 || len(j) < 2 {
+		return j, fields, nil
+	}
+	e, err := json.Marshal(fields)
+	if err != nil {
+		return nil, nil, err
+	}
+	// Replacing closing bracket of the message with "enrichment" object
+	return addField(j, "enrichment", e), fields, nil
+}
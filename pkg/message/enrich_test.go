@@ -0,0 +1,52 @@
+package message
+
+import (
+	"testing"
+
+	"github.com/sbezverk/gobmp/pkg/bmp"
+	"github.com/sbezverk/gobmp/pkg/enrich"
+)
+
+type asNameEnricher struct{}
+
+func (e *asNameEnricher) Enrich(msg *enrich.Message) map[string]interface{} {
+	if msg.OriginAS != 65001 {
+		return nil
+	}
+
+	return map[string]interface{}{"origin_as_name": "EXAMPLE-AS"}
+}
+
+func TestEnrich(t *testing.T) {
+	p := &producer{enrichers: []enrich.Enricher{&asNameEnricher{}}}
+	tests := []struct {
+		name   string
+		msg    *UnicastPrefix
+		input  string
+		expect string
+	}{
+		{
+			name:   "enriched",
+			msg:    &UnicastPrefix{Prefix: "10.0.0.0", PrefixLen: 8, OriginAS: 65001},
+			input:  `{"prefix":"10.0.0.0"}`,
+			expect: `{"prefix":"10.0.0.0","enrichment":{"origin_as_name":"EXAMPLE-AS"}}`,
+		},
+		{
+			name:   "not enriched",
+			msg:    &UnicastPrefix{Prefix: "10.0.0.0", PrefixLen: 8, OriginAS: 65002},
+			input:  `{"prefix":"10.0.0.0"}`,
+			expect: `{"prefix":"10.0.0.0"}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, _, err := p.enrich(bmp.UnicastPrefixMsg, tt.msg, []byte(tt.input))
+			if err != nil {
+				t.Fatalf("expected to succeed but failed with error: %+v", err)
+			}
+			if string(got) != tt.expect {
+				t.Fatalf("expected message %s does not match actual message %s", tt.expect, string(got))
+			}
+		})
+	}
+}
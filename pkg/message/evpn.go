@@ -2,9 +2,9 @@ package message
 
 import (
 	"fmt"
-	"net"
 
 	"github.com/golang/glog"
+	"github.com/sbezverk/gobmp/pkg/addr"
 	"github.com/sbezverk/gobmp/pkg/bgp"
 	"github.com/sbezverk/gobmp/pkg/bmp"
 )
@@ -32,20 +32,22 @@ func (p *producer) evpn(nlri bgp.MPNLRI, op int, ph *bmp.PerPeerHeader, update *
 
 	for _, e := range evpn.Route {
 		prfx := EVPNPrefix{
-			Action:         operation,
-			PeerType:       uint8(ph.PeerType),
-			RouterHash:     p.speakerHash,
-			RouterIP:       p.speakerIP,
-			PeerHash:       ph.GetPeerHash(),
-			PeerASN:        ph.PeerAS,
-			Timestamp:      ph.GetPeerTimestamp(),
-			Nexthop:        nlri.GetNextHop(),
-			BaseAttributes: update.BaseAttributes,
-		}
-		if ases := update.BaseAttributes.ASPath; len(ases) != 0 {
-			// Last element in AS_PATH would be the AS of the origin
-			prfx.OriginAS = int32(ases[len(ases)-1])
+			Action:                  operation,
+			PeerType:                uint8(ph.PeerType),
+			PeerRD:                  ph.GetPeerDistinguisherString(),
+			Validation:              update.Validation,
+			RouterHash:              p.speakerHash,
+			RouterIP:                p.speakerIP,
+			PeerHash:                p.peerHash(ph),
+			PeerASN:                 ph.PeerAS,
+			Timestamp:               ph.GetPeerTimestamp(),
+			TimestampEpoch:          ph.GetPeerTimestampEpoch(),
+			CollectorTimestamp:      ph.GetCollectorTimestamp(),
+			CollectorTimestampEpoch: ph.GetCollectorTimestampEpoch(),
+			Nexthop:                 nlri.GetNextHop(),
+			BaseAttributes:          update.BaseAttributes,
 		}
+		prfx.OriginAS = int32(update.BaseAttributes.OriginAS)
 
 		prfx.PeerIP = ph.GetPeerAddrString()
 		prfx.RemoteBGPID = ph.GetPeerBGPIDString()
@@ -60,34 +62,38 @@ func (p *producer) evpn(nlri bgp.MPNLRI, op int, ph *bmp.PerPeerHeader, update *
 			if esi != nil {
 				// TODO Change 10 for a const for ESI length
 				for i := 0; i < 10; i++ {
-					prfx.ESI += fmt.Sprintf("%02d", esi[i])
+					prfx.ESI += fmt.Sprintf("%02x", esi[i])
 					// TODO same here ESI length -1
 					if i < 9 {
 						prfx.ESI += ":"
 					}
 				}
+				// Zero ESI of single-homed routes does not identify an Ethernet Segment
+				if !esi.IsZero() {
+					prfx.ESIFields = esi.Fields()
+				}
 			}
 			prfx.EthTag = e.GetEVPNTAG()
 			if ip := e.GetEVPNIPLength(); ip != nil {
 				prfx.IPLength = *ip
 				gw := e.GetEVPNGWAddr()
-				addr := e.GetEVPNIPAddr()
+				ipAddr := e.GetEVPNIPAddr()
 				// IPv4 should have IPLength set to 32
 				if prfx.IPLength <= 32 {
-					if addr != nil {
-						prfx.IPAddress = net.IP(addr).To4().String()
+					if ipAddr != nil {
+						prfx.IPAddress = addr.IPv4(ipAddr)
 					}
 					if gw != nil {
-						prfx.GWAddress = net.IP(gw).To4().String()
+						prfx.GWAddress = addr.IPv4(gw)
 					}
 				}
 				// Processing IPv6 IP and GW
 				if prfx.IPLength <= 128 {
-					if addr != nil {
-						prfx.IPAddress = net.IP(addr).To16().String()
+					if ipAddr != nil {
+						prfx.IPAddress = addr.IPv6(ipAddr)
 					}
 					if gw != nil {
-						prfx.GWAddress = net.IP(gw).To16().String()
+						prfx.GWAddress = addr.IPv6(gw)
 					}
 				}
 			}
@@ -27,19 +27,23 @@ func (p *producer) flowspec(nlri bgp.MPNLRI, op int, ph *bmp.PerPeerHeader, upda
 		return nil, err
 	}
 	fs := &Flowspec{
-		Action:         operation,
-		RouterIP:       p.speakerIP,
-		PeerType:       uint8(ph.PeerType),
-		PeerASN:        ph.PeerAS,
-		Timestamp:      ph.GetPeerTimestamp(),
-		BaseAttributes: update.BaseAttributes,
-		SpecHash:       fsnlri.GetSpecHash(),
+		Action:                  operation,
+		RouterHash:              p.speakerHash,
+		RouterIP:                p.speakerIP,
+		PeerHash:                p.peerHash(ph),
+		PeerType:                uint8(ph.PeerType),
+		PeerRD:                  ph.GetPeerDistinguisherString(),
+		Validation:              update.Validation,
+		PeerASN:                 ph.PeerAS,
+		Timestamp:               ph.GetPeerTimestamp(),
+		TimestampEpoch:          ph.GetPeerTimestampEpoch(),
+		CollectorTimestamp:      ph.GetCollectorTimestamp(),
+		CollectorTimestampEpoch: ph.GetCollectorTimestampEpoch(),
+		BaseAttributes:          update.BaseAttributes,
+		SpecHash:                fsnlri.GetSpecHash(),
 	}
 
-	if ases := update.BaseAttributes.ASPath; len(ases) != 0 {
-		// Last element in AS_PATH would be the AS of the origin
-		fs.OriginAS = int32(ases[len(ases)-1])
-	}
+	fs.OriginAS = int32(update.BaseAttributes.OriginAS)
 
 	fs.Nexthop = nlri.GetNextHop()
 	fs.Spec = fsnlri.Spec
@@ -73,6 +77,31 @@ func (fs *Flowspec) UnmarshalJSON(b []byte) error {
 	if err := json.Unmarshal(objmap["spec_hash"], &o.SpecHash); err != nil {
 		return err
 	}
+	if h, ok := objmap["hash"]; ok {
+		if err := json.Unmarshal(h, &o.Hash); err != nil {
+			return err
+		}
+	}
+	if s, ok := objmap["sequence"]; ok {
+		if err := json.Unmarshal(s, &o.Sequence); err != nil {
+			return err
+		}
+	}
+	if s, ok := objmap["session_id"]; ok {
+		if err := json.Unmarshal(s, &o.SessionID); err != nil {
+			return err
+		}
+	}
+	if h, ok := objmap["router_hash"]; ok {
+		if err := json.Unmarshal(h, &o.RouterHash); err != nil {
+			return err
+		}
+	}
+	if h, ok := objmap["peer_hash"]; ok {
+		if err := json.Unmarshal(h, &o.PeerHash); err != nil {
+			return err
+		}
+	}
 	if err := json.Unmarshal(objmap["base_attrs"], &o.BaseAttributes); err != nil {
 		return err
 	}
@@ -94,6 +123,21 @@ func (fs *Flowspec) UnmarshalJSON(b []byte) error {
 	if err := json.Unmarshal(objmap["timestamp"], &o.Timestamp); err != nil {
 		return err
 	}
+	if t, ok := objmap["timestamp_epoch_us"]; ok {
+		if err := json.Unmarshal(t, &o.TimestampEpoch); err != nil {
+			return err
+		}
+	}
+	if t, ok := objmap["collector_timestamp"]; ok {
+		if err := json.Unmarshal(t, &o.CollectorTimestamp); err != nil {
+			return err
+		}
+	}
+	if t, ok := objmap["collector_timestamp_epoch_us"]; ok {
+		if err := json.Unmarshal(t, &o.CollectorTimestampEpoch); err != nil {
+			return err
+		}
+	}
 	if s, ok := objmap["spec"]; ok {
 		var specs []map[string]interface{}
 		if err := json.Unmarshal(s, &specs); err != nil {
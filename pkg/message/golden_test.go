@@ -0,0 +1,75 @@
+package message
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"testing"
+
+	"github.com/sbezverk/gobmp/pkg/bmp"
+	"github.com/sbezverk/gobmp/pkg/fixture"
+	"github.com/sbezverk/gobmp/pkg/parser"
+	"github.com/sbezverk/gobmp/pkg/schema"
+)
+
+var update = flag.Bool("update", false, "update golden files of fixtures in testdata")
+
+// volatileFields lists fields of published messages which differ between runs
+var volatileFields = []string{"session_id", "collector_timestamp", "collector_timestamp_epoch_us"}
+
+type published struct {
+	Type    string                 `json:"type"`
+	Message map[string]interface{} `json:"message"`
+}
+
+// recorder is a Publisher recording messages validated against their schemas
+type recorder struct {
+	msgs []published
+	errs []error
+}
+
+func (r *recorder) PublishMessage(msgType int, msgHash []byte, msg []byte) error {
+	if err := schema.Validate(Schemas()[msgType], msg); err != nil {
+		r.errs = append(r.errs, fmt.Errorf("message %s does not match schema of type %s with error: %+v", string(msg), bmp.MsgTypeName(msgType), err))
+	}
+	m := make(map[string]interface{})
+	if err := json.Unmarshal(msg, &m); err != nil {
+		r.errs = append(r.errs, err)
+		return err
+	}
+	for _, f := range volatileFields {
+		delete(m, f)
+	}
+	r.msgs = append(r.msgs, published{Type: bmp.MsgTypeName(msgType), Message: m})
+
+	return nil
+}
+
+func (r *recorder) Stop() {}
+
+// TestGolden parses fixtures of BMP messages in testdata and compares published messages with golden files,
+// run "go test ./pkg/message -run TestGolden -update" to create golden files of new fixtures.
+func TestGolden(t *testing.T) {
+	fixtures, err := fixture.Load("testdata")
+	if err != nil {
+		t.Fatalf("failed to load fixtures with error: %+v", err)
+	}
+	if len(fixtures) == 0 {
+		t.Fatalf("no fixtures found in testdata")
+	}
+	for _, f := range fixtures {
+		t.Run(f.Name, func(t *testing.T) {
+			r := &recorder{msgs: make([]published, 0)}
+			p := NewProducerWithConfig(r, &ProducerConfig{SplitAF: true, CheckUpdates: true}).(*producer)
+			for _, msg := range parser.Parse(f.Data) {
+				p.producingWorker(msg)
+			}
+			for _, err := range r.errs {
+				t.Error(err)
+			}
+			if err := fixture.Compare(f.Golden, r.msgs, *update); err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
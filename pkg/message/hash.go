@@ -0,0 +1,113 @@
+package message
+
+import (
+	"crypto/md5"
+	"encoding/binary"
+	"encoding/hex"
+	"hash"
+
+	"github.com/sbezverk/gobmp/pkg/base"
+)
+
+// keyHasher calculates md5 hash of message key fields, strings are terminated by zero byte and numbers are
+// hashed in network byte order. OpenBMP compatible hashes are calculated by openbmp.go.
+type keyHasher struct {
+	hash.Hash
+}
+
+func newKeyHasher() *keyHasher {
+	return &keyHasher{md5.New()}
+}
+
+func (h *keyHasher) str(values ...string) *keyHasher {
+	for _, v := range values {
+		h.Write([]byte(v))
+		h.Write([]byte{0})
+	}
+
+	return h
+}
+
+func (h *keyHasher) uint(values ...uint64) *keyHasher {
+	b := make([]byte, 8)
+	for _, v := range values {
+		binary.BigEndian.PutUint64(b, v)
+		h.Write(b)
+	}
+
+	return h
+}
+
+func (h *keyHasher) mtid(mt *base.MultiTopologyIdentifier) *keyHasher {
+	if mt == nil {
+		return h
+	}
+
+	return h.uint(uint64(mt.MTID))
+}
+
+// vpnRD hashes Route Distinguisher of BGP-LS-VPN objects, hashes of BGP-LS objects without Route Distinguisher
+// stay the same as before BGP-LS-VPN support
+func (h *keyHasher) vpnRD(rd string) *keyHasher {
+	if rd == "" {
+		return h
+	}
+
+	return h.str(rd)
+}
+
+func (h *keyHasher) sum() string {
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// setHash sets the hash of message key fields, messages about the same object, for example the same prefix
+// advertised by the same peer, carry the same hash regardless of their attributes, so consumers can use it
+// to deduplicate messages. Changes of attributes are tracked by base_attrs hash.
+func setHash(msg interface{}) {
+	switch m := msg.(type) {
+	case *PeerStateChange:
+		m.Hash = newKeyHasher().str(m.RouterHash, m.PeerRD, m.RemoteIP, m.RemoteBGPID).sum()
+	case *UnicastPrefix:
+		h := newKeyHasher().str(m.RouterHash, m.PeerHash, m.Prefix).uint(uint64(m.PrefixLen), uint64(m.PathID))
+		if m.SAFI == 2 {
+			// Multicast prefix is a different object than the same unicast prefix, hashes of unicast prefixes
+			// do not include SAFI to stay the same as before multicast support
+			h.uint(uint64(m.SAFI))
+		}
+		m.Hash = h.sum()
+	case *L3VPNPrefix:
+		m.Hash = newKeyHasher().str(m.RouterHash, m.PeerHash, m.VPNRD, m.Prefix).uint(uint64(m.PrefixLen), uint64(m.PathID)).sum()
+	case *EVPNPrefix:
+		m.Hash = newKeyHasher().str(m.RouterHash, m.PeerHash, m.VPNRD, string(m.EthTag), m.ESI, m.MAC, m.IPAddress).
+			uint(uint64(m.RouteType), uint64(m.PathID)).sum()
+	case *SRPolicy:
+		m.Hash = newKeyHasher().str(m.RouterHash, m.PeerHash, string(m.Endpoint)).
+			uint(uint64(m.Distinguisher), uint64(m.Color), uint64(m.PathID)).sum()
+	case *Flowspec:
+		m.Hash = newKeyHasher().str(m.RouterHash, m.PeerHash, m.SpecHash).uint(uint64(m.PathID)).sum()
+	case *RTConstraint:
+		m.Hash = newKeyHasher().str(m.RouterHash, m.PeerHash, m.RouteTarget).
+			uint(uint64(m.OriginAS), uint64(m.PrefixLen), uint64(m.PathID)).sum()
+	case *MUPRoute:
+		m.Hash = newKeyHasher().str(m.RouterHash, m.PeerHash, m.VPNRD, m.Prefix, m.Address, m.EndpointAddress, m.SourceAddress).
+			uint(uint64(m.ArchitectureType), uint64(m.RouteType), uint64(m.PrefixLen), uint64(m.TEID), uint64(m.QFI), uint64(m.PathID)).sum()
+	case *LSNode:
+		m.Hash = newKeyHasher().str(m.RouterHash, m.PeerHash, m.AreaID, m.IGPRouterID).
+			uint(uint64(m.ProtocolID), uint64(m.DomainID), uint64(m.ASN), uint64(m.LSID)).vpnRD(m.VPNRD).sum()
+	case *LSLink:
+		m.Hash = newKeyHasher().str(m.RouterHash, m.PeerHash, m.LocalNodeHash, m.RemoteNodeHash, m.LocalLinkIP, m.RemoteLinkIP).
+			uint(uint64(m.ProtocolID), uint64(m.DomainID), uint64(m.LocalLinkID), uint64(m.RemoteLinkID)).mtid(m.MTID).
+			vpnRD(m.VPNRD).sum()
+	case *BGPEPE:
+		m.Hash = newKeyHasher().str(m.RouterHash, m.PeerHash, m.LocalNodeHash, m.RemoteNodeHash, m.LocalLinkIP, m.RemoteLinkIP).
+			uint(uint64(m.LocalLinkID), uint64(m.RemoteLinkID)).vpnRD(m.VPNRD).sum()
+	case *LSPrefix:
+		m.Hash = newKeyHasher().str(m.RouterHash, m.PeerHash, m.LocalNodeHash, m.Prefix).
+			uint(uint64(m.ProtocolID), uint64(m.DomainID), uint64(m.PrefixLen)).mtid(m.MTID).vpnRD(m.VPNRD).sum()
+	case *LSSRv6SID:
+		m.Hash = newKeyHasher().str(m.RouterHash, m.PeerHash, m.LocalNodeHash, m.SRv6SID).
+			uint(uint64(m.ProtocolID), uint64(m.DomainID)).mtid(m.MTID).vpnRD(m.VPNRD).sum()
+	case *Stats:
+		m.Hash = newKeyHasher().str(m.RouterHash, m.PeerRD, m.RemoteIP, m.Timestamp).sum()
+	}
+}
@@ -0,0 +1,72 @@
+package message
+
+import (
+	"testing"
+
+	"github.com/sbezverk/gobmp/pkg/bgp"
+)
+
+func TestSetHash(t *testing.T) {
+	prefix := func(nexthop string, pathID int32) *UnicastPrefix {
+		return &UnicastPrefix{
+			RouterHash:     "router",
+			PeerHash:       "peer",
+			Prefix:         "10.0.0.0",
+			PrefixLen:      8,
+			PathID:         pathID,
+			Nexthop:        nexthop,
+			BaseAttributes: &bgp.BaseAttributes{Nexthop: nexthop},
+		}
+	}
+	tests := []struct {
+		name  string
+		m1    *UnicastPrefix
+		m2    *UnicastPrefix
+		equal bool
+	}{
+		{
+			name:  "same prefix with different attributes",
+			m1:    prefix("192.0.2.1", 0),
+			m2:    prefix("192.0.2.2", 0),
+			equal: true,
+		},
+		{
+			name:  "different path id",
+			m1:    prefix("192.0.2.1", 1),
+			m2:    prefix("192.0.2.1", 2),
+			equal: false,
+		},
+		{
+			name: "unicast and labeled unicast",
+			m1:   prefix("192.0.2.1", 0),
+			m2: func() *UnicastPrefix {
+				m := prefix("192.0.2.1", 0)
+				m.SAFI = 4
+				return m
+			}(),
+			equal: true,
+		},
+		{
+			name: "unicast and multicast",
+			m1:   prefix("192.0.2.1", 0),
+			m2: func() *UnicastPrefix {
+				m := prefix("192.0.2.1", 0)
+				m.SAFI = 2
+				return m
+			}(),
+			equal: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setHash(tt.m1)
+			setHash(tt.m2)
+			if tt.m1.Hash == "" || tt.m2.Hash == "" {
+				t.Fatalf("hash is not set")
+			}
+			if (tt.m1.Hash == tt.m2.Hash) != tt.equal {
+				t.Fatalf("expected hashes equality %t, got hashes %s and %s", tt.equal, tt.m1.Hash, tt.m2.Hash)
+			}
+		})
+	}
+}
@@ -2,8 +2,9 @@ package message
 
 import (
 	"fmt"
-	"net"
 
+	"github.com/golang/glog"
+	"github.com/sbezverk/gobmp/pkg/addr"
 	"github.com/sbezverk/gobmp/pkg/bgp"
 	"github.com/sbezverk/gobmp/pkg/bmp"
 )
@@ -25,38 +26,47 @@ func (p *producer) l3vpn(nlri bgp.MPNLRI, op int, ph *bmp.PerPeerHeader, update
 	default:
 		return nil, fmt.Errorf("unknown operation %d", op)
 	}
+	var nhAFI uint16
+	if op == 0 {
+		nhAFI = p.nexthopAFI(nlri)
+	}
 	prfxs := make([]L3VPNPrefix, 0)
 	for _, e := range nlril3vpn.NLRI {
 		prfx := L3VPNPrefix{
-			Action:         operation,
-			RouterHash:     p.speakerHash,
-			RouterIP:       p.speakerIP,
-			PeerType:       uint8(ph.PeerType),
-			PeerHash:       ph.GetPeerHash(),
-			PeerASN:        ph.PeerAS,
-			Timestamp:      ph.GetPeerTimestamp(),
-			Nexthop:        nlri.GetNextHop(),
-			PrefixLen:      int32(e.Length),
-			PathID:         int32(e.PathID),
-			BaseAttributes: update.BaseAttributes,
+			Action:                  operation,
+			RouterHash:              p.speakerHash,
+			RouterIP:                p.speakerIP,
+			PeerType:                uint8(ph.PeerType),
+			PeerRD:                  ph.GetPeerDistinguisherString(),
+			Validation:              update.Validation,
+			PeerHash:                p.peerHash(ph),
+			PeerASN:                 ph.PeerAS,
+			Timestamp:               ph.GetPeerTimestamp(),
+			TimestampEpoch:          ph.GetPeerTimestampEpoch(),
+			CollectorTimestamp:      ph.GetCollectorTimestamp(),
+			CollectorTimestampEpoch: ph.GetCollectorTimestampEpoch(),
+			Nexthop:                 nlri.GetNextHop(),
+			NexthopAFI:              nhAFI,
+			NexthopLinkLocal:        nlri.GetNextHopLinkLocal(),
+			NexthopOriginal:         nlri.GetNextHopOriginal(),
+			PrefixLen:               int32(e.Length),
+			PathID:                  int32(e.PathID),
+			BaseAttributes:          update.BaseAttributes,
 		}
 
-		if ases := update.BaseAttributes.ASPath; len(ases) != 0 {
-			// Last element in AS_PATH would be the AS of the origin
-			prfx.OriginAS = int32(ases[len(ases)-1])
-		}
+		prfx.OriginAS = int32(update.BaseAttributes.OriginAS)
 		if nlri.IsIPv6NLRI() {
 			// IPv6 specific conversions
 			prfx.IsIPv4 = false
 			p := make([]byte, 16)
 			copy(p, e.Prefix)
-			prfx.Prefix = net.IP(p).To16().String()
+			prfx.Prefix = addr.IPv6(p)
 		} else {
 			// IPv4 specific conversions
 			prfx.IsIPv4 = true
 			p := make([]byte, 4)
 			copy(p, e.Prefix)
-			prfx.Prefix = net.IP(p).To4().String()
+			prfx.Prefix = addr.IPv4(p)
 		}
 		prfx.IsNexthopIPv4 = !nlri.IsNextHopIPv6()
 		prfx.PeerIP = ph.GetPeerAddrString()
@@ -74,9 +84,20 @@ func (p *producer) l3vpn(nlri bgp.MPNLRI, op int, ph *bmp.PerPeerHeader, update
 			prfx.Labels = append(prfx.Labels, l.Value)
 		}
 		prfx.VPNRD = e.RD.String()
+		if op == 0 {
+			prfx.LeakSuspect = p.leakSuspect(ph, update.BaseAttributes)
+		}
 		prfx.VPNRDType = e.RD.Type
 		if psid, err := update.GetAttrPrefixSID(); err == nil {
 			prfx.PrefixSID = psid
+			if psid.SRv6L3Service != nil && len(e.Label) != 0 {
+				// SRv6 L3VPN, the label field may carry transposed bits of SRv6 SID
+				if sid, err := psid.SRv6L3Service.SID(e.Label[0].Value); err == nil {
+					prfx.SRv6SID = sid.String()
+				} else {
+					glog.Errorf("failed to recover SRv6 SID of l3vpn prefix %s with error: %+v", prfx.Prefix, err)
+				}
+			}
 		}
 		prfxs = append(prfxs, prfx)
 	}
@@ -2,9 +2,9 @@ package message
 
 import (
 	"fmt"
-	"net"
 	"strconv"
 
+	"github.com/sbezverk/gobmp/pkg/addr"
 	"github.com/sbezverk/gobmp/pkg/base"
 	"github.com/sbezverk/gobmp/pkg/bgp"
 	"github.com/sbezverk/gobmp/pkg/bmp"
@@ -21,14 +21,19 @@ func (p *producer) lsLink(link *base.LinkNLRI, nextHop string, op int, ph *bmp.P
 		return nil, fmt.Errorf("unknown operation %d", op)
 	}
 	msg := LSLink{
-		Action:     operation,
-		RouterHash: p.speakerHash,
-		RouterIP:   p.speakerIP,
-		PeerType:   uint8(ph.PeerType),
-		PeerHash:   ph.GetPeerHash(),
-		PeerASN:    ph.PeerAS,
-		Timestamp:  ph.GetPeerTimestamp(),
-		DomainID:   link.GetIdentifier(),
+		Action:                  operation,
+		RouterHash:              p.speakerHash,
+		RouterIP:                p.speakerIP,
+		PeerType:                uint8(ph.PeerType),
+		PeerRD:                  ph.GetPeerDistinguisherString(),
+		Validation:              update.Validation,
+		PeerHash:                p.peerHash(ph),
+		PeerASN:                 ph.PeerAS,
+		Timestamp:               ph.GetPeerTimestamp(),
+		TimestampEpoch:          ph.GetPeerTimestampEpoch(),
+		CollectorTimestamp:      ph.GetCollectorTimestamp(),
+		CollectorTimestampEpoch: ph.GetCollectorTimestampEpoch(),
+		DomainID:                link.GetIdentifier(),
 	}
 	if f, err := ph.IsAdjRIBInPost(); err == nil {
 		msg.IsAdjRIBInPost = f
@@ -60,8 +65,13 @@ func (p *producer) lsLink(link *base.LinkNLRI, nextHop string, op int, ph *bmp.P
 	msg.LocalNodeASN = link.GetLocalASN()
 	msg.RemoteNodeASN = link.GetRemoteASN()
 	msg.RemoteIGPRouterID = link.GetRemoteIGPRouterID()
+	msg.RemoteIsPseudonode = link.IsRemotePseudonode()
 	msg.IGPRouterID = link.GetLocalIGPRouterID()
+	msg.IsPseudonode = link.IsLocalPseudonode()
 	msg.MTID = link.Link.GetLinkMTID()
+	if msg.MTID != nil {
+		msg.TopologyID = msg.MTID.MTID
+	}
 	switch link.ProtocolID {
 	case base.ISISL1:
 		fallthrough
@@ -77,8 +87,8 @@ func (p *producer) lsLink(link *base.LinkNLRI, nextHop string, op int, ph *bmp.P
 		msg.AreaID = link.LocalNode.GetOSPFAreaID()
 	case base.BGP:
 		msg.AreaID = strconv.Itoa(int(link.LocalNode.GetASN()))
-		msg.BGPRouterID = net.IP(link.LocalNode.GetBGPRouterID()).To4().String()
-		msg.BGPRemoteRouterID = net.IP(link.RemoteNode.GetBGPRouterID()).To4().String()
+		msg.BGPRouterID = addr.IPv4(link.LocalNode.GetBGPRouterID())
+		msg.BGPRemoteRouterID = addr.IPv4(link.RemoteNode.GetBGPRouterID())
 		msg.MemberAS = link.LocalNode.GetConfedMemberASN()
 	default:
 		msg.AreaID = "0"
@@ -113,9 +123,16 @@ func (p *producer) lsLink(link *base.LinkNLRI, nextHop string, op int, ph *bmp.P
 		msg.UnResvBWKbps = lslink.GetUnreservedLinkBandwidthKbps()
 		msg.TEDefaultMetric = lslink.GetTEDefaultMetric()
 		msg.LinkProtection = lslink.GetLinkProtectionType()
+		if p, err := lslink.GetLinkProtectionTypeFlags(); err == nil {
+			msg.LinkProtectionFlags = p
+		}
 		msg.MPLSProtoMask = lslink.GetLinkMPLSProtocolMask()
+		if mask, err := lslink.GetLinkMPLSProtocolMaskFlags(); err == nil {
+			msg.MPLSProtoMaskFlags = mask
+		}
 		msg.SRLG = lslink.GetSRLG()
 		msg.LinkName = lslink.GetLinkName()
+		msg.OpaqueLinkAttr = lslink.GetLinkOpaqueAttribute()
 		msg.SRv6BGPPeerNodeSID = lslink.GetSRv6BGPPeerNodeSID()
 		if sid, err := lslink.GetLSSRv6ENDXSID(); err == nil {
 			msg.SRv6ENDXSID = sid
@@ -133,6 +150,9 @@ func (p *producer) lsLink(link *base.LinkNLRI, nextHop string, op int, ph *bmp.P
 		if adj, err := lslink.GetSRAdjacencySID(msg.ProtocolID); err == nil {
 			msg.LSAdjacencySID = adj
 		}
+		if adj, err := lslink.GetSRLANAdjacencySID(msg.ProtocolID); err == nil {
+			msg.LSLANAdjacencySID = adj
+		}
 		if msg.ProtocolID == base.BGP {
 			if sid, err := lslink.GetPeerNodeSID(); err == nil {
 				msg.PeerNodeSID = sid
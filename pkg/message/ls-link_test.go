@@ -0,0 +1,128 @@
+package message
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/go-test/deep"
+	"github.com/sbezverk/gobmp/pkg/base"
+	"github.com/sbezverk/gobmp/pkg/bgpls"
+	"github.com/sbezverk/gobmp/pkg/bmp"
+	"github.com/sbezverk/gobmp/pkg/schema"
+	"github.com/sbezverk/gobmp/pkg/sr"
+	"github.com/sbezverk/gobmp/pkg/srv6"
+)
+
+func TestRoundTripLSLink(t *testing.T) {
+	lanLabel := uint32(24002)
+	original := &LSLink{
+		Key:                 "Key",
+		ID:                  "ID",
+		Rev:                 "Rev",
+		IGPRouterID:         "0000.0000.0001",
+		RouterID:            "10.0.0.1",
+		Protocol:            "IS-IS Level 2",
+		ProtocolID:          base.ISISL2,
+		AreaID:              "49.0001",
+		MTID:                &base.MultiTopologyIdentifier{MTID: 2},
+		LocalLinkID:         1,
+		RemoteLinkID:        2,
+		LocalLinkIP:         "10.1.1.0",
+		RemoteLinkIP:        "10.1.1.1",
+		IGPMetric:           10,
+		AdminGroup:          1,
+		MaxLinkBW:           1000000,
+		MaxResvBW:           1000000,
+		UnResvBW:            []uint32{1, 2, 3, 4, 5, 6, 7, 8},
+		TEDefaultMetric:     10,
+		LinkProtection:      0x0800,
+		LinkProtectionFlags: &bgpls.LinkProtectionType{Dedicated1to1: true},
+		MPLSProtoMask:       0x80,
+		MPLSProtoMaskFlags:  &bgpls.MPLSProtocolMask{LFlag: true},
+		SRLG:                []uint32{100, 200},
+		LinkName:            "xr-1_to_xr-2",
+		OpaqueLinkAttr:      "0102ab",
+		RemoteIGPRouterID:   "0000.0000.0002",
+		RemoteRouterID:      "10.0.0.2",
+		LocalNodeASN:        65000,
+		RemoteNodeASN:       65000,
+		PeerNodeSID: &sr.PeerSID{
+			Flags:  &sr.PeerFlags{VFlag: true, LFlag: true},
+			Weight: 1,
+			SID:    24000,
+		},
+		SRv6BGPPeerNodeSID: &srv6.BGPPeerNodeSID{
+			Flags:   &srv6.BGPPeerNodeFlags{BFlag: true},
+			Weight:  1,
+			PeerASN: 65001,
+			PeerID:  []byte{10, 0, 0, 2},
+		},
+		SRv6ENDXSID: []*srv6.EndXSIDTLV{
+			{
+				Type:             1106,
+				Length:           28,
+				EndpointBehavior: 57,
+				Flags:            &srv6.EndXSIDFlags{BFlag: true},
+				Algorithm:        128,
+				Weight:           1,
+				SID:              "fc00:0:1:e001::",
+			},
+		},
+		LSAdjacencySID: []*sr.AdjacencySIDTLV{
+			{
+				Flags: &sr.AdjISISFlags{
+					VFlag: true,
+					LFlag: true,
+				},
+				Weight: 0,
+				SID:    24001,
+			},
+		},
+		LSLANAdjacencySID: []*sr.LANAdjacencySIDTLV{
+			{
+				Flags:      &sr.AdjISISFlags{VFlag: true, LFlag: true},
+				NeighborID: "0000.0000.0002",
+				SID:        24002,
+				SIDValue:   &sr.SIDValue{Type: sr.SIDTypeLabel, Label: &lanLabel},
+			},
+			{
+				Flags:      &sr.AdjISISFlags{BFlag: true, VFlag: true, LFlag: true},
+				Weight:     1,
+				NeighborID: "0000.0000.0003",
+				SID:        24003,
+			},
+		},
+		LinkMSD: []*base.MSDTV{
+			{Type: 1, Value: 10, Name: "Base MPLS Imposition MSD"},
+		},
+		AppSpecLinkAttr: []*bgpls.AppSpecLinkAttr{
+			{
+				SAIBMLen: 1,
+				SAIBM:    []byte{0x10},
+				SubTLV: []*base.SubTLV{
+					{Type: 1088, Length: 4, Value: []byte{0, 0, 0, 1}},
+				},
+			},
+		},
+		UnidirLinkDelay:       100,
+		UnidirLinkDelayMinMax: []uint32{50, 150},
+		IsLocRIBFiltered:      true,
+	}
+	b, err := json.Marshal(original)
+	if err != nil {
+		t.Fatalf("TestRoundTripLSLink Marshal failed with error: %+v but supposed to succeed", err)
+	}
+
+	if err := schema.Validate(Schemas()[bmp.LSLinkMsg], b); err != nil {
+		t.Fatalf("TestRoundTripLSLink message does not match schema with error: %+v", err)
+	}
+	recovered := &LSLink{}
+	if err := json.Unmarshal(b, recovered); err != nil {
+		t.Fatalf("TestRoundTripLSLink Unmarshal failed with error: %+v but supposed to succeed", err)
+	}
+	if !reflect.DeepEqual(original, recovered) {
+		t.Logf("Differences: %+v", deep.Equal(original, recovered))
+		t.Fatalf("TestRoundTripLSLink failed as original %+v does not match recovered: %+v", *original, *recovered)
+	}
+}
@@ -19,14 +19,19 @@ func (p *producer) lsNode(node *base.NodeNLRI, nextHop string, op int, ph *bmp.P
 		return nil, fmt.Errorf("unknown operation %d", op)
 	}
 	msg := LSNode{
-		Action:     operation,
-		RouterHash: p.speakerHash,
-		RouterIP:   p.speakerIP,
-		PeerType:   uint8(ph.PeerType),
-		PeerHash:   ph.GetPeerHash(),
-		PeerASN:    ph.PeerAS,
-		Timestamp:  ph.GetPeerTimestamp(),
-		DomainID:   node.GetIdentifier(),
+		Action:                  operation,
+		RouterHash:              p.speakerHash,
+		RouterIP:                p.speakerIP,
+		PeerType:                uint8(ph.PeerType),
+		PeerRD:                  ph.GetPeerDistinguisherString(),
+		Validation:              update.Validation,
+		PeerHash:                p.peerHash(ph),
+		PeerASN:                 ph.PeerAS,
+		Timestamp:               ph.GetPeerTimestamp(),
+		TimestampEpoch:          ph.GetPeerTimestampEpoch(),
+		CollectorTimestamp:      ph.GetCollectorTimestamp(),
+		CollectorTimestampEpoch: ph.GetCollectorTimestampEpoch(),
+		DomainID:                node.GetIdentifier(),
 	}
 	if f, err := ph.IsAdjRIBInPost(); err == nil {
 		msg.IsAdjRIBInPost = f
@@ -41,6 +46,7 @@ func (p *producer) lsNode(node *base.NodeNLRI, nextHop string, op int, ph *bmp.P
 	msg.Protocol = node.GetNodeProtocolID()
 	msg.ProtocolID = node.ProtocolID
 	msg.IGPRouterID = node.GetNodeIGPRouterID()
+	msg.IsPseudonode = node.IsPseudonode()
 	msg.LSID = node.GetNodeLSID()
 	msg.ASN = node.GetNodeASN()
 	switch node.ProtocolID {
@@ -55,6 +61,7 @@ func (p *producer) lsNode(node *base.NodeNLRI, nextHop string, op int, ph *bmp.P
 		if f, err := lsnode.GetNodeFlags(); err == nil {
 			msg.NodeFlags = f
 		}
+		msg.OpaqueNodeAttr = lsnode.GetNodeOpaqueAttribute()
 		msg.Name = lsnode.GetNodeName()
 		msg.MTID = lsnode.GetMTID()
 		switch node.ProtocolID {
@@ -62,20 +69,31 @@ func (p *producer) lsNode(node *base.NodeNLRI, nextHop string, op int, ph *bmp.P
 			fallthrough
 		case base.ISISL2:
 			msg.AreaID = lsnode.GetISISAreaID()
+			if areas := lsnode.GetISISAreaIDs(); len(areas) != 0 {
+				msg.ISISAreaIDs = areas
+			}
 		}
+		msg.IPv4RouterID = lsnode.GetLocalIPv4RouterID()
+		msg.IPv6RouterID = lsnode.GetLocalIPv6RouterID()
+		// Router ID of the session's address family, or of the other family when the node has only one
 		if isIPv6 {
-			msg.RouterID = lsnode.GetLocalIPv6RouterID()
+			msg.RouterID = msg.IPv6RouterID
 		} else {
-			msg.RouterID = lsnode.GetLocalIPv4RouterID()
+			msg.RouterID = msg.IPv4RouterID
+		}
+		if msg.RouterID == "" {
+			msg.RouterID = msg.IPv4RouterID + msg.IPv6RouterID
 		}
 		if msd, err := lsnode.GetNodeMSD(); err == nil {
 			msg.NodeMSD = msd
 		}
 		if cap, err := lsnode.GetNodeSRCapabilities(msg.ProtocolID); err == nil {
 			msg.SRCapabilities = cap
+			msg.SRGB = cap.GetRanges()
 		}
 		msg.SRAlgorithm = lsnode.GetSRAlgorithm()
 		msg.SRLocalBlock = lsnode.GetNodeSRLocalBlock()
+		msg.SRLB = msg.SRLocalBlock.GetRanges()
 		if cap, err := lsnode.GetNodeSRv6CapabilitiesTLV(); err == nil {
 			msg.SRv6CapabilitiesTLV = cap
 		}
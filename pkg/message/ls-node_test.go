@@ -0,0 +1,97 @@
+package message
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/go-test/deep"
+	"github.com/sbezverk/gobmp/pkg/base"
+	"github.com/sbezverk/gobmp/pkg/bgpls"
+	"github.com/sbezverk/gobmp/pkg/bmp"
+	"github.com/sbezverk/gobmp/pkg/schema"
+	"github.com/sbezverk/gobmp/pkg/sr"
+	"github.com/sbezverk/gobmp/pkg/srv6"
+)
+
+func TestRoundTripLSNode(t *testing.T) {
+	label := uint32(15000)
+	original := &LSNode{
+		Key:          "Key",
+		ID:           "ID",
+		Rev:          "Rev",
+		DomainID:     0,
+		IGPRouterID:  "0000.0000.0001",
+		RouterID:     "10.0.0.1",
+		IPv4RouterID: "10.0.0.1",
+		IPv6RouterID: "2001:db8::1",
+		ASN:          65000,
+		LSID:         1,
+		MTID: []*base.MultiTopologyIdentifier{
+			{OFlag: true, MTID: 2},
+		},
+		AreaID:      "49.0001",
+		ISISAreaIDs: []string{"49.0001", "49.0002"},
+		Protocol:    "IS-IS Level 2",
+		ProtocolID:  base.ISISL2,
+		NodeFlags: &bgpls.NodeAttrFlags{
+			TFlag: true,
+		},
+		OpaqueNodeAttr: "0102ab",
+		Name:           "xr-1",
+		SRCapabilities: &sr.Capability{
+			Flags: &sr.ISISCapFlags{
+				IFlag: true,
+				VFlag: true,
+			},
+			SubTLV: []sr.CapabilitySubTLV{
+				{Range: 64000, SID: 16000},
+			},
+		},
+		SRAlgorithm: []int{0, 1, 128},
+		SRLocalBlock: &sr.LocalBlock{
+			TLV: []sr.LocalBlockTLV{
+				{SubRange: 1000, Label: &label},
+			},
+		},
+		SRGB: []*sr.SIDRange{
+			{FirstSID: 16000, Range: 64000},
+		},
+		SRLB: []*sr.SIDRange{
+			{FirstSID: 15000, Range: 1000},
+		},
+		SRv6CapabilitiesTLV: &srv6.CapabilityTLV{
+			OFlag: true,
+		},
+		NodeMSD: []*base.MSDTV{
+			{Type: 1, Value: 10, Name: "Base MPLS Imposition MSD"},
+		},
+		FlexAlgoDefinition: []*bgpls.FlexAlgoDefinition{
+			{
+				FlexAlgorithm: 128,
+				Priority:      128,
+				SubTLV: &bgpls.FADSubTLV{
+					ExcludeAny: []uint32{0x80000000},
+					Flags:      &bgpls.FADSubTLVFlags{MFLag: true},
+				},
+			},
+		},
+		IsAdjRIBInPost: true,
+	}
+	b, err := json.Marshal(original)
+	if err != nil {
+		t.Fatalf("TestRoundTripLSNode Marshal failed with error: %+v but supposed to succeed", err)
+	}
+
+	if err := schema.Validate(Schemas()[bmp.LSNodeMsg], b); err != nil {
+		t.Fatalf("TestRoundTripLSNode message does not match schema with error: %+v", err)
+	}
+	recovered := &LSNode{}
+	if err := json.Unmarshal(b, recovered); err != nil {
+		t.Fatalf("TestRoundTripLSNode Unmarshal failed with error: %+v but supposed to succeed", err)
+	}
+	if !reflect.DeepEqual(original, recovered) {
+		t.Logf("Differences: %+v", deep.Equal(original, recovered))
+		t.Fatalf("TestRoundTripLSNode failed as original %+v does not match recovered: %+v", *original, *recovered)
+	}
+}
@@ -2,8 +2,8 @@ package message
 
 import (
 	"fmt"
-	"net"
 
+	"github.com/sbezverk/gobmp/pkg/addr"
 	"github.com/sbezverk/gobmp/pkg/base"
 	"github.com/sbezverk/gobmp/pkg/bgp"
 	"github.com/sbezverk/gobmp/pkg/bmp"
@@ -20,14 +20,19 @@ func (p *producer) lsPrefix(prfx *base.PrefixNLRI, nextHop string, op int, ph *b
 		return nil, fmt.Errorf("unknown operation %d", op)
 	}
 	msg := LSPrefix{
-		Action:     operation,
-		RouterHash: p.speakerHash,
-		RouterIP:   p.speakerIP,
-		PeerType:   uint8(ph.PeerType),
-		PeerHash:   ph.GetPeerHash(),
-		PeerASN:    ph.PeerAS,
-		Timestamp:  ph.GetPeerTimestamp(),
-		DomainID:   prfx.GetIdentifier(),
+		Action:                  operation,
+		RouterHash:              p.speakerHash,
+		RouterIP:                p.speakerIP,
+		PeerType:                uint8(ph.PeerType),
+		PeerRD:                  ph.GetPeerDistinguisherString(),
+		Validation:              update.Validation,
+		PeerHash:                p.peerHash(ph),
+		PeerASN:                 ph.PeerAS,
+		Timestamp:               ph.GetPeerTimestamp(),
+		TimestampEpoch:          ph.GetPeerTimestampEpoch(),
+		CollectorTimestamp:      ph.GetCollectorTimestamp(),
+		CollectorTimestampEpoch: ph.GetCollectorTimestampEpoch(),
+		DomainID:                prfx.GetIdentifier(),
 	}
 	if f, err := ph.IsAdjRIBInPost(); err == nil {
 		msg.IsAdjRIBInPost = f
@@ -46,13 +51,16 @@ func (p *producer) lsPrefix(prfx *base.PrefixNLRI, nextHop string, op int, ph *b
 	msg.LocalNodeHash = prfx.LocalNodeHash
 	msg.IGPRouterID = prfx.GetLocalIGPRouterID()
 	msg.MTID = prfx.Prefix.GetPrefixMTID()
+	if msg.MTID != nil {
+		msg.TopologyID = msg.MTID.MTID
+	}
 	route := prfx.Prefix.GetPrefixIPReachability(ipv4)
 	msg.PrefixLen = int32(route.Length)
 	pr := prfx.Prefix.GetPrefixIPReachability(ipv4).Prefix
 	if !ipv4 {
-		msg.Prefix = net.IP(pr).To16().String()
+		msg.Prefix = addr.IPv6(pr)
 	} else {
-		msg.Prefix = net.IP(pr).To4().String()
+		msg.Prefix = addr.IPv4(pr)
 	}
 	switch prfx.ProtocolID {
 	case base.ISISL1:
@@ -8,6 +8,8 @@ import (
 	"github.com/go-test/deep"
 	"github.com/sbezverk/gobmp/pkg/base"
 	"github.com/sbezverk/gobmp/pkg/bgpls"
+	"github.com/sbezverk/gobmp/pkg/bmp"
+	"github.com/sbezverk/gobmp/pkg/schema"
 	"github.com/sbezverk/gobmp/pkg/sr"
 )
 
@@ -32,6 +34,23 @@ func TestRoundTripLSPrefix(t *testing.T) {
 					SID:       20007,
 				},
 			},
+			Range: []*bgpls.PrefixRangeTLV{
+				{
+					RangeSize: 16,
+					LSPrefixSID: []*sr.PrefixSIDTLV{
+						{
+							Flags: &sr.ISISFlags{
+								NFlag: true,
+							},
+							SID: 100,
+						},
+					},
+				},
+			},
+			Flags: &bgpls.ISISFlags{
+				NFlag: true,
+			},
+			SourceRouterID: "10.0.0.1",
 		},
 	}
 	b, err := json.Marshal(original)
@@ -39,6 +58,9 @@ func TestRoundTripLSPrefix(t *testing.T) {
 		t.Fatalf("TestRoundTripLSPrefix Marshal failed with error: %+v but supposed to succeed", err)
 	}
 
+	if err := schema.Validate(Schemas()[bmp.LSPrefixMsg], b); err != nil {
+		t.Fatalf("TestRoundTripLSPrefix message does not match schema with error: %+v", err)
+	}
 	recovered := &LSPrefix{}
 	if err := json.Unmarshal(b, recovered); err != nil {
 		t.Fatalf("TestRoundTripLSPrefix Unmarshal failed with error: %+v but supposed to succeed", err)
@@ -19,14 +19,19 @@ func (p *producer) lsSRv6SID(nlri6 *srv6.SIDNLRI, nextHop string, op int, ph *bm
 		return nil, fmt.Errorf("unknown operation %d", op)
 	}
 	msg := LSSRv6SID{
-		Action:     operation,
-		RouterHash: p.speakerHash,
-		RouterIP:   p.speakerIP,
-		PeerType:   uint8(ph.PeerType),
-		PeerHash:   ph.GetPeerHash(),
-		PeerASN:    ph.PeerAS,
-		Timestamp:  ph.GetPeerTimestamp(),
-		DomainID:   nlri6.GetIdentifier(),
+		Action:                  operation,
+		RouterHash:              p.speakerHash,
+		RouterIP:                p.speakerIP,
+		PeerType:                uint8(ph.PeerType),
+		PeerRD:                  ph.GetPeerDistinguisherString(),
+		Validation:              update.Validation,
+		PeerHash:                p.peerHash(ph),
+		PeerASN:                 ph.PeerAS,
+		Timestamp:               ph.GetPeerTimestamp(),
+		TimestampEpoch:          ph.GetPeerTimestampEpoch(),
+		CollectorTimestamp:      ph.GetCollectorTimestamp(),
+		CollectorTimestampEpoch: ph.GetCollectorTimestampEpoch(),
+		DomainID:                nlri6.GetIdentifier(),
 	}
 	if f, err := ph.IsAdjRIBInPost(); err == nil {
 		msg.IsAdjRIBInPost = f
@@ -46,6 +51,9 @@ func (p *producer) lsSRv6SID(nlri6 *srv6.SIDNLRI, nextHop string, op int, ph *bm
 	msg.IGPRouterID = nlri6.GetSRv6SIDIGPRouterID()
 	msg.LocalNodeASN = nlri6.GetSRv6SIDASN()
 	msg.MTID = nlri6.GetSRv6SIDMTID()
+	if msg.MTID != nil {
+		msg.TopologyID = msg.MTID.MTID
+	}
 	msg.SRv6SID = nlri6.GetSRv6SID()
 	ls, err := update.GetNLRI29()
 	if err == nil {
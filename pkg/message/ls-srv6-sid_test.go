@@ -0,0 +1,62 @@
+package message
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/go-test/deep"
+	"github.com/sbezverk/gobmp/pkg/base"
+	"github.com/sbezverk/gobmp/pkg/bmp"
+	"github.com/sbezverk/gobmp/pkg/schema"
+	"github.com/sbezverk/gobmp/pkg/srv6"
+)
+
+func TestRoundTripLSSRv6SID(t *testing.T) {
+	original := &LSSRv6SID{
+		Key:         "Key",
+		ID:          "ID",
+		Rev:         "Rev",
+		IGPRouterID: "0000.0000.0001",
+		RouterID:    "10.0.0.1",
+		AreaID:      "49.0001",
+		Protocol:    "IS-IS Level 2",
+		ProtocolID:  base.ISISL2,
+		MTID:        &base.MultiTopologyIdentifier{MTID: 2},
+		IGPFlags:    0x80,
+		IGPMetric:   10,
+		SRv6SID:     "fc00:0:1:e000::",
+		SRv6EndpointBehavior: &srv6.EndpointBehavior{
+			EndpointBehavior: 48,
+			Name:             "End with NEXT-CSID, PSP & USD",
+			Algorithm:        128,
+		},
+		SRv6BGPPeerNodeSID: &srv6.BGPPeerNodeSID{
+			Flags:   &srv6.BGPPeerNodeFlags{PFlag: true},
+			Weight:  1,
+			PeerASN: 65001,
+			PeerID:  []byte{10, 0, 0, 2},
+		},
+		SRv6SIDStructure: &srv6.SIDStructure{
+			LBLength:  32,
+			LNLength:  16,
+			FunLength: 16,
+		},
+	}
+	b, err := json.Marshal(original)
+	if err != nil {
+		t.Fatalf("TestRoundTripLSSRv6SID Marshal failed with error: %+v but supposed to succeed", err)
+	}
+
+	if err := schema.Validate(Schemas()[bmp.LSSRv6SIDMsg], b); err != nil {
+		t.Fatalf("TestRoundTripLSSRv6SID message does not match schema with error: %+v", err)
+	}
+	recovered := &LSSRv6SID{}
+	if err := json.Unmarshal(b, recovered); err != nil {
+		t.Fatalf("TestRoundTripLSSRv6SID Unmarshal failed with error: %+v but supposed to succeed", err)
+	}
+	if !reflect.DeepEqual(original, recovered) {
+		t.Logf("Differences: %+v", deep.Equal(original, recovered))
+		t.Fatalf("TestRoundTripLSSRv6SID failed as original %+v does not match recovered: %+v", *original, *recovered)
+	}
+}
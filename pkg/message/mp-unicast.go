@@ -2,14 +2,24 @@ package message
 
 import (
 	"fmt"
-	"net"
 
+	"github.com/sbezverk/gobmp/pkg/addr"
 	"github.com/sbezverk/gobmp/pkg/base"
 	"github.com/sbezverk/gobmp/pkg/bgp"
 	"github.com/sbezverk/gobmp/pkg/bmp"
 )
 
-// unicast process nlri 14 afi 1/2 safi 1 messages and generates UnicastPrefix messages
+// unicastSAFI maps NLRI types processed as unicast prefixes to their SAFI
+var unicastSAFI = map[int]uint8{
+	1:  1,
+	2:  1,
+	3:  2,
+	4:  2,
+	16: 4,
+	17: 4,
+}
+
+// unicast process nlri 14 afi 1/2 safi 1, 2 and 4 messages and generates UnicastPrefix messages
 func (p *producer) unicast(nlri bgp.MPNLRI, op int, ph *bmp.PerPeerHeader, update *bgp.Update, label bool) ([]UnicastPrefix, error) {
 	var err error
 	var operation string
@@ -35,18 +45,28 @@ func (p *producer) unicast(nlri bgp.MPNLRI, op int, ph *bmp.PerPeerHeader, updat
 			return nil, err
 		}
 	}
+	var nhAFI uint16
+	if op == 0 {
+		nhAFI = p.nexthopAFI(nlri)
+	}
 	for _, e := range u.NLRI {
 		prfx := UnicastPrefix{
-			Action:         operation,
-			RouterHash:     p.speakerHash,
-			RouterIP:       p.speakerIP,
-			PeerType:       uint8(ph.PeerType),
-			PeerHash:       ph.GetPeerHash(),
-			PeerASN:        ph.PeerAS,
-			Timestamp:      ph.GetPeerTimestamp(),
-			PrefixLen:      int32(e.Length),
-			PathID:         int32(e.PathID),
-			BaseAttributes: update.BaseAttributes,
+			Action:                  operation,
+			RouterHash:              p.speakerHash,
+			RouterIP:                p.speakerIP,
+			PeerType:                uint8(ph.PeerType),
+			PeerRD:                  ph.GetPeerDistinguisherString(),
+			Validation:              update.Validation,
+			PeerHash:                p.peerHash(ph),
+			PeerASN:                 ph.PeerAS,
+			Timestamp:               ph.GetPeerTimestamp(),
+			TimestampEpoch:          ph.GetPeerTimestampEpoch(),
+			CollectorTimestamp:      ph.GetCollectorTimestamp(),
+			CollectorTimestampEpoch: ph.GetCollectorTimestampEpoch(),
+			PrefixLen:               int32(e.Length),
+			PathID:                  int32(e.PathID),
+			BaseAttributes:          update.BaseAttributes,
+			SAFI:                    unicastSAFI[nlri.GetAFISAFIType()],
 		}
 		if f, err := ph.IsAdjRIBInPost(); err == nil {
 			prfx.IsAdjRIBInPost = f
@@ -57,35 +77,42 @@ func (p *producer) unicast(nlri bgp.MPNLRI, op int, ph *bmp.PerPeerHeader, updat
 		if f, err := ph.IsLocRIBFiltered(); err == nil {
 			prfx.IsLocRIBFiltered = f
 		}
-		if ases := update.BaseAttributes.ASPath; len(ases) != 0 {
-			// Last element in AS_PATH would be the AS of the origin
-			prfx.OriginAS = int32(ases[len(ases)-1])
-		}
+		prfx.OriginAS = int32(update.BaseAttributes.OriginAS)
 		prfx.PeerIP = ph.GetPeerAddrString()
 		prfx.Nexthop = nlri.GetNextHop()
+		prfx.NexthopAFI = nhAFI
+		prfx.NexthopLinkLocal = nlri.GetNextHopLinkLocal()
+		prfx.NexthopOriginal = nlri.GetNextHopOriginal()
 		if nlri.IsIPv6NLRI() {
 			// IPv6 specific conversions
 			prfx.IsIPv4 = false
-			prfx.IsNexthopIPv4 = false
+			// Labeled IPv6 NLRI may carry IPv4-mapped IPv6 next hop, 6PE RFC 4798
+			prfx.IsNexthopIPv4 = !nlri.IsNextHopIPv6()
 			a := make([]byte, 16)
 			copy(a, e.Prefix)
-			prfx.Prefix = net.IP(a).To16().String()
+			prfx.Prefix = addr.IPv6(a)
 		} else {
 			// IPv4 specific conversions
 			prfx.IsIPv4 = true
-			prfx.IsNexthopIPv4 = true
+			// IPv4 NLRI may carry IPv6 next hop, RFC 8950
+			prfx.IsNexthopIPv4 = !nlri.IsNextHopIPv6()
 			a := make([]byte, 4)
 			copy(a, e.Prefix)
-			prfx.Prefix = net.IP(a).To4().String()
+			prfx.Prefix = addr.IPv4(a)
+		}
+		prfx.RPKIStatus = p.rpkiStatus(&prfx)
+		if op == 0 {
+			prfx.LeakSuspect = p.leakSuspect(ph, update.BaseAttributes)
 		}
 		if label {
 			for _, l := range e.Label {
 				prfx.Labels = append(prfx.Labels, l.Value)
 			}
-			// Some Label Unicast may carry BGP Attribute 40 (Prefix SID)
-			if psid, err := update.GetAttrPrefixSID(); err == nil {
-				prfx.PrefixSID = psid
-			}
+		}
+		// Label Unicast may carry BGP Attribute 40 (Prefix SID) with Label Index and Originator SRGB,
+		// Unicast over SRv6 core carries it with SRv6 L3 Service
+		if psid, err := update.GetAttrPrefixSID(); err == nil {
+			prfx.PrefixSID = psid
 		}
 		prfxs = append(prfxs, prfx)
 	}
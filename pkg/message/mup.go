@@ -0,0 +1,82 @@
+package message
+
+import (
+	"fmt"
+
+	"github.com/sbezverk/gobmp/pkg/addr"
+	"github.com/sbezverk/gobmp/pkg/bgp"
+	"github.com/sbezverk/gobmp/pkg/bmp"
+)
+
+// ipString returns a string representation of an address, or an empty string when the address is not set
+func ipString(b []byte) string {
+	if len(b) == 0 {
+		return ""
+	}
+
+	return addr.String(b)
+}
+
+// mup process MP_REACH_NLRI and MP_UNREACH_NLRI AFI 1 or 2 SAFI 85 update message and returns
+// BGP-MUP route objects.
+func (p *producer) mup(nlri bgp.MPNLRI, op int, ph *bmp.PerPeerHeader, update *bgp.Update) ([]MUPRoute, error) {
+	var operation string
+	switch op {
+	case 0:
+		operation = "add"
+	case 1:
+		operation = "del"
+	default:
+		return nil, fmt.Errorf("unknown operation %d", op)
+	}
+	r, err := nlri.GetNLRIMUP()
+	if err != nil {
+		return nil, err
+	}
+	msgs := make([]MUPRoute, 0, len(r.Route))
+	for _, e := range r.Route {
+		m := MUPRoute{
+			Action:                  operation,
+			RouterHash:              p.speakerHash,
+			RouterIP:                p.speakerIP,
+			PeerHash:                p.peerHash(ph),
+			PeerIP:                  ph.GetPeerAddrString(),
+			PeerType:                uint8(ph.PeerType),
+			PeerRD:                  ph.GetPeerDistinguisherString(),
+			Validation:              update.Validation,
+			PeerASN:                 ph.PeerAS,
+			Timestamp:               ph.GetPeerTimestamp(),
+			TimestampEpoch:          ph.GetPeerTimestampEpoch(),
+			CollectorTimestamp:      ph.GetCollectorTimestamp(),
+			CollectorTimestampEpoch: ph.GetCollectorTimestampEpoch(),
+			BaseAttributes:          update.BaseAttributes,
+			OriginAS:                int32(update.BaseAttributes.OriginAS),
+			IsIPv4:                  !nlri.IsIPv6NLRI(),
+			Nexthop:                 nlri.GetNextHop(),
+			IsNexthopIPv4:           !nlri.IsNextHopIPv6(),
+			PathID:                  int32(e.PathID),
+			ArchitectureType:        e.ArchitectureType,
+			RouteType:               e.RouteType,
+			VPNRD:                   e.GetRD(),
+			Prefix:                  ipString(e.Prefix),
+			PrefixLen:               int32(e.PrefixLength),
+			Address:                 ipString(e.Address),
+			TEID:                    e.TEID,
+			QFI:                     e.QFI,
+			EndpointAddress:         ipString(e.EndpointAddress),
+			SourceAddress:           ipString(e.SourceAddress),
+		}
+		if f, err := ph.IsAdjRIBInPost(); err == nil {
+			m.IsAdjRIBInPost = f
+		}
+		if f, err := ph.IsAdjRIBOutPost(); err == nil {
+			m.IsAdjRIBOutPost = f
+		}
+		if f, err := ph.IsLocRIBFiltered(); err == nil {
+			m.IsLocRIBFiltered = f
+		}
+		msgs = append(msgs, m)
+	}
+
+	return msgs, nil
+}
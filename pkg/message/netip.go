@@ -0,0 +1,89 @@
+//go:build go1.18
+// +build go1.18
+
+package message
+
+import (
+	"fmt"
+	"net/netip"
+)
+
+// Address fields of messages stay strings, so JSON of messages and builds with Go releases older than 1.18
+// are not affected, accessors below return them as net/netip values when gobmp is built with Go 1.18 or later.
+// Fields are not migrated to netip.Addr and netip.Prefix types, the accessors are the only net/netip API.
+
+func netipAddr(s string) (netip.Addr, error) {
+	if s == "" {
+		return netip.Addr{}, fmt.Errorf("address is not set")
+	}
+	return netip.ParseAddr(s)
+}
+
+func netipPrefix(s string, l int32) (netip.Prefix, error) {
+	a, err := netipAddr(s)
+	if err != nil {
+		return netip.Prefix{}, err
+	}
+
+	return a.Prefix(int(l))
+}
+
+// NetipPeerIP returns address of the peer
+func (p *PeerStateChange) NetipPeerIP() (netip.Addr, error) {
+	return netipAddr(p.RemoteIP)
+}
+
+// NetipLocalIP returns local address of the peering session
+func (p *PeerStateChange) NetipLocalIP() (netip.Addr, error) {
+	return netipAddr(p.LocalIP)
+}
+
+// NetipPrefix returns the prefix of the message
+func (u *UnicastPrefix) NetipPrefix() (netip.Prefix, error) {
+	return netipPrefix(u.Prefix, u.PrefixLen)
+}
+
+// NetipNexthop returns the next hop of the prefix
+func (u *UnicastPrefix) NetipNexthop() (netip.Addr, error) {
+	return netipAddr(u.Nexthop)
+}
+
+// NetipPrefix returns the prefix of the message
+func (l *L3VPNPrefix) NetipPrefix() (netip.Prefix, error) {
+	return netipPrefix(l.Prefix, l.PrefixLen)
+}
+
+// NetipNexthop returns the next hop of the prefix
+func (l *L3VPNPrefix) NetipNexthop() (netip.Addr, error) {
+	return netipAddr(l.Nexthop)
+}
+
+// NetipPrefix returns the prefix of the message
+func (l *LSPrefix) NetipPrefix() (netip.Prefix, error) {
+	return netipPrefix(l.Prefix, l.PrefixLen)
+}
+
+// NetipRouterID returns TE Router ID of the node
+func (n *LSNode) NetipRouterID() (netip.Addr, error) {
+	return netipAddr(n.RouterID)
+}
+
+// NetipLocalLinkIP returns local address of the link
+func (l *LSLink) NetipLocalLinkIP() (netip.Addr, error) {
+	return netipAddr(l.LocalLinkIP)
+}
+
+// NetipRemoteLinkIP returns remote address of the link
+func (l *LSLink) NetipRemoteLinkIP() (netip.Addr, error) {
+	return netipAddr(l.RemoteLinkIP)
+}
+
+// NetipIPAddress returns IP address of MAC/IP Advertisement route
+func (e *EVPNPrefix) NetipIPAddress() (netip.Addr, error) {
+	return netipAddr(e.IPAddress)
+}
+
+// NetipNexthop returns the next hop of the route
+func (e *EVPNPrefix) NetipNexthop() (netip.Addr, error) {
+	return netipAddr(e.Nexthop)
+}
@@ -0,0 +1,41 @@
+//go:build go1.18
+// +build go1.18
+
+package message
+
+import (
+	"net/netip"
+	"testing"
+)
+
+func TestNetip(t *testing.T) {
+	u := &UnicastPrefix{Prefix: "10.0.0.0", PrefixLen: 24, Nexthop: "2001:db8::1"}
+	p, err := u.NetipPrefix()
+	if err != nil {
+		t.Fatalf("failed to get prefix with error: %+v", err)
+	}
+	if p != netip.MustParsePrefix("10.0.0.0/24") {
+		t.Errorf("expected prefix 10.0.0.0/24, got %s", p)
+	}
+	nh, err := u.NetipNexthop()
+	if err != nil {
+		t.Fatalf("failed to get next hop with error: %+v", err)
+	}
+	if nh != netip.MustParseAddr("2001:db8::1") {
+		t.Errorf("expected next hop 2001:db8::1, got %s", nh)
+	}
+	l := &L3VPNPrefix{Prefix: "2001:db8::", PrefixLen: 129}
+	if _, err := l.NetipPrefix(); err == nil {
+		t.Errorf("expected prefix of invalid length to fail")
+	}
+	if _, err := (&LSLink{}).NetipLocalLinkIP(); err == nil {
+		t.Errorf("expected unset address to fail")
+	}
+	a, err := (&PeerStateChange{RemoteIP: "::ffff:192.0.2.1"}).NetipPeerIP()
+	if err != nil {
+		t.Fatalf("failed to get peer address with error: %+v", err)
+	}
+	if !a.Is4In6() || a.Unmap() != netip.MustParseAddr("192.0.2.1") {
+		t.Errorf("expected IPv4-mapped peer address of 192.0.2.1, got %s", a)
+	}
+}
@@ -0,0 +1,19 @@
+package message
+
+import (
+	"github.com/golang/glog"
+	"github.com/sbezverk/gobmp/pkg/bgp"
+)
+
+// nexthopAFI returns AFI of MP_REACH_NLRI next hop, IPv6 next hop of IPv4 or VPN-IPv4 NLRI is expected only
+// when Extended Next Hop Encoding was negotiated by the peers, otherwise the mismatch is logged and
+// the next hop is still published as received.
+func (p *producer) nexthopAFI(nlri bgp.MPNLRI) uint16 {
+	afi := nlri.GetNextHopAFI()
+	if afi == 2 && !nlri.IsIPv6NLRI() && !p.extNexthop[nlri.GetAFISAFIType()] {
+		glog.Warningf("speaker %s sent IPv6 next hop %s for IPv4 nlri type %d without Extended Next Hop Encoding capability",
+			p.speakerIP, nlri.GetNextHop(), nlri.GetAFISAFIType())
+	}
+
+	return afi
+}
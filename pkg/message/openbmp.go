@@ -0,0 +1,99 @@
+package message
+
+import (
+	"crypto/md5"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+
+	"github.com/sbezverk/gobmp/pkg/bmp"
+)
+
+// OpenBMP collector identifies routers, peers, base attributes and prefixes by md5 hashes of their text
+// representation chained with the binary hash of the parent object:
+//   collector_hash = md5(admin_id)
+//   router_hash    = md5(router_ip, collector_hash)
+//   peer_hash      = md5(peer_addr, peer_rd, router_hash)
+//   base_attr_hash = md5(as_path, next_hop, aggregator, origin, med, local_pref, community_list,
+//                        ext_community_list, peer_hash)
+//   hash_id        = md5(prefix, prefix_len, peer_hash, path_id)
+// Integers are hashed in host, little endian, byte order, path_id only when it is not 0.
+
+func openBMPCollectorHash(adminID string) string {
+	return fmt.Sprintf("%x", md5.Sum([]byte(adminID)))
+}
+
+func openBMPRouterHash(routerIP, collectorHash string) string {
+	h := md5.New()
+	h.Write([]byte(routerIP))
+	h.Write(binaryHash(collectorHash))
+
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func openBMPPeerHash(peerAddr, peerRD, routerHash string) string {
+	h := md5.New()
+	h.Write([]byte(peerAddr))
+	h.Write([]byte(peerRD))
+	h.Write(binaryHash(routerHash))
+
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func openBMPPrefixHash(prefix string, prefixLen int32, pathID int32, peerHash string) string {
+	h := md5.New()
+	h.Write([]byte(prefix))
+	h.Write([]byte{uint8(prefixLen)})
+	h.Write(binaryHash(peerHash))
+	if pathID > 0 {
+		b := make([]byte, 4)
+		binary.LittleEndian.PutUint32(b, uint32(pathID))
+		h.Write(b)
+	}
+
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func binaryHash(s string) []byte {
+	b, _ := hex.DecodeString(s)
+	return b
+}
+
+// routerHash returns router_hash of the speaker, OpenBMP router_hash is calculated from the address of
+// BMP session when it is known, otherwise from router_ip.
+func (p *producer) routerHash() string {
+	if p.collectorHash == "" {
+		return fmt.Sprintf("%x", md5.Sum([]byte(p.speakerIP)))
+	}
+	ip := p.routerIP
+	if ip == "" {
+		ip = p.speakerIP
+	}
+
+	return openBMPRouterHash(ip, p.collectorHash)
+}
+
+// peerHash returns peer_hash of the peer of per-peer header
+func (p *producer) peerHash(ph *bmp.PerPeerHeader) string {
+	if p.collectorHash == "" {
+		return ph.GetPeerHash()
+	}
+
+	return openBMPPeerHash(ph.GetPeerAddrString(), ph.GetPeerDistinguisherString(), p.speakerHash)
+}
+
+// setHash sets the hash of message key fields, with OpenBMP hashes peers are identified by peer_hash
+// and unicast prefixes by OpenBMP hash_id, other messages carry the same hash as without them.
+func (p *producer) setHash(msg interface{}) {
+	if p.collectorHash != "" {
+		switch m := msg.(type) {
+		case *PeerStateChange:
+			m.Hash = m.PeerHash
+			return
+		case *UnicastPrefix:
+			m.Hash = openBMPPrefixHash(m.Prefix, m.PrefixLen, m.PathID, m.PeerHash)
+			return
+		}
+	}
+	setHash(msg)
+}
@@ -0,0 +1,70 @@
+package message
+
+import (
+	"testing"
+)
+
+// Expected values are calculated independently of gobmp by md5 of fields concatenated in the order and
+// encoding OpenBMP collector uses.
+func TestOpenBMPHashes(t *testing.T) {
+	collector := openBMPCollectorHash("collector1")
+	if collector != "9ae8148974c9ca01ec9271753426d214" {
+		t.Fatalf("unexpected collector_hash %s", collector)
+	}
+	router := openBMPRouterHash("192.0.2.254", collector)
+	if router != "7d37c017ec02afeb1c4588b27ca2072f" {
+		t.Fatalf("unexpected router_hash %s", router)
+	}
+	tests := []struct {
+		name   string
+		hash   string
+		expect string
+	}{
+		{
+			name:   "global peer",
+			hash:   openBMPPeerHash("192.0.2.1", "0:0", router),
+			expect: "cd024a93c02f19508b0627b524aa770e",
+		},
+		{
+			name:   "rd instance peer",
+			hash:   openBMPPeerHash("192.0.2.1", "65000:100", router),
+			expect: "142c9e4fd215a9f2db91372553ed40df",
+		},
+		{
+			name:   "prefix",
+			hash:   openBMPPrefixHash("10.0.0.0", 8, 0, "cd024a93c02f19508b0627b524aa770e"),
+			expect: "7c241347c635091a53749a32777c59cd",
+		},
+		{
+			name:   "prefix with path id",
+			hash:   openBMPPrefixHash("10.0.0.0", 8, 5, "cd024a93c02f19508b0627b524aa770e"),
+			expect: "244bef64683ccabd370e4fbd7c511618",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.hash != tt.expect {
+				t.Fatalf("expected hash %s but got %s", tt.expect, tt.hash)
+			}
+		})
+	}
+}
+
+func TestProducerOpenBMPHash(t *testing.T) {
+	p := NewProducerWithConfig(nil, &ProducerConfig{OpenBMPAdminID: "collector1", RouterIP: "192.0.2.254"}).(*producer)
+	p.speakerIP = "192.0.2.100"
+	p.speakerHash = p.routerHash()
+	if p.speakerHash != "7d37c017ec02afeb1c4588b27ca2072f" {
+		t.Fatalf("router_hash is not calculated from the address of BMP session, got %s", p.speakerHash)
+	}
+	peer := &PeerStateChange{PeerHash: "cd024a93c02f19508b0627b524aa770e"}
+	p.setHash(peer)
+	if peer.Hash != peer.PeerHash {
+		t.Fatalf("expected hash of peer message %s but got %s", peer.PeerHash, peer.Hash)
+	}
+	prefix := &UnicastPrefix{PeerHash: "cd024a93c02f19508b0627b524aa770e", Prefix: "10.0.0.0", PrefixLen: 8, PathID: 5}
+	p.setHash(prefix)
+	if prefix.Hash != "244bef64683ccabd370e4fbd7c511618" {
+		t.Fatalf("expected hash of unicast prefix message 244bef64683ccabd370e4fbd7c511618 but got %s", prefix.Hash)
+	}
+}
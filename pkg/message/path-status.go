@@ -0,0 +1,26 @@
+package message
+
+import (
+	"github.com/golang/glog"
+	"github.com/sbezverk/gobmp/pkg/bmp"
+)
+
+// pathStatus returns path status and reason of NLRI with index, NLRIs are indexed starting with 1,
+// carried in Path Status TLV of BMP v4 Route Monitoring message.
+func pathStatus(rm *bmp.RouteMonitor, index int) ([]string, string) {
+	if rm == nil {
+		return nil, ""
+	}
+	tlvs := rm.NLRITLVs(bmp.PathStatusTLV, uint16(index))
+	if len(tlvs) == 0 {
+		return nil, ""
+	}
+	// When both TLV for all NLRIs and TLV specific to the NLRI are present, the last one wins
+	ps, err := bmp.UnmarshalPathStatus(tlvs[len(tlvs)-1].Value)
+	if err != nil {
+		glog.Errorf("failed to unmarshal path status tlv with error: %+v", err)
+		return nil, ""
+	}
+
+	return ps.Names(), ps.ReasonName()
+}
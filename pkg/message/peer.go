@@ -1,11 +1,8 @@
 package message
 
 import (
-	"crypto/md5"
-	"fmt"
-	"net"
-
 	"github.com/golang/glog"
+	"github.com/sbezverk/gobmp/pkg/addr"
 	"github.com/sbezverk/gobmp/pkg/bmp"
 )
 
@@ -27,15 +24,18 @@ func (p *producer) producePeerMessage(op int, msg bmp.Message) {
 			return
 		}
 		m = PeerStateChange{
-			Action:         action,
-			RemoteASN:      msg.PeerHeader.PeerAS,
-			PeerType:       uint8(msg.PeerHeader.PeerType),
-			PeerRD:         msg.PeerHeader.GetPeerDistinguisherString(),
-			RemotePort:     int(peerUpMsg.RemotePort),
-			Timestamp:      msg.PeerHeader.GetPeerTimestamp(),
-			LocalPort:      int(peerUpMsg.LocalPort),
-			AdvHolddown:    int(peerUpMsg.SentOpen.HoldTime),
-			RemoteHolddown: int(peerUpMsg.ReceivedOpen.HoldTime),
+			Action:                  action,
+			RemoteASN:               msg.PeerHeader.PeerAS,
+			PeerType:                uint8(msg.PeerHeader.PeerType),
+			PeerRD:                  msg.PeerHeader.GetPeerDistinguisherString(),
+			RemotePort:              int(peerUpMsg.RemotePort),
+			Timestamp:               msg.PeerHeader.GetPeerTimestamp(),
+			TimestampEpoch:          msg.PeerHeader.GetPeerTimestampEpoch(),
+			CollectorTimestamp:      msg.PeerHeader.GetCollectorTimestamp(),
+			CollectorTimestampEpoch: msg.PeerHeader.GetCollectorTimestampEpoch(),
+			LocalPort:               int(peerUpMsg.LocalPort),
+			AdvHolddown:             int(peerUpMsg.SentOpen.HoldTime),
+			RemoteHolddown:          int(peerUpMsg.ReceivedOpen.HoldTime),
 		}
 		if f, err := msg.PeerHeader.IsAdjRIBInPost(); err == nil {
 			m.IsAdjRIBInPost = f
@@ -48,14 +48,20 @@ func (p *producer) producePeerMessage(op int, msg bmp.Message) {
 		}
 		m.RemoteIP = msg.PeerHeader.GetPeerAddrString()
 		m.RemoteBGPID = msg.PeerHeader.GetPeerBGPIDString()
-		m.LocalBGPID = net.IP(peerUpMsg.SentOpen.BGPID).To4().String()
+		m.LocalBGPID = addr.IPv4(peerUpMsg.SentOpen.BGPID)
 		m.IsIPv4 = !msg.PeerHeader.IsRemotePeerIPv6()
 		m.LocalIP = peerUpMsg.GetLocalAddressString()
 		// Saving local bgp speaker identities.
 		p.speakerIP = m.LocalIP
-		p.speakerHash = fmt.Sprintf("%x", md5.Sum([]byte(p.speakerIP)))
+		p.speakerHash = p.routerHash()
+		p.storeOnce.Do(p.restoreSession)
 		m.RouterIP = p.speakerIP
 		m.RouterHash = p.speakerHash
+		m.PeerHash = p.peerHash(msg.PeerHeader)
+		p.setPeerTableName(m.PeerHash, peerUpMsg.TableName())
+		for _, tlv := range peerUpMsg.VendorInformation() {
+			m.VendorTLVs = append(m.VendorTLVs, bmp.DecodeVendorTLV(bmp.PeerUpMsg, 0, uint16(tlv.InformationType), tlv.Information))
+		}
 
 		m.LocalASN = uint32(peerUpMsg.SentOpen.MyAS)
 		if lasn, ok := peerUpMsg.SentOpen.Is4BytesASCapable(); ok {
@@ -77,6 +83,15 @@ func (p *producer) producePeerMessage(op int, msg bmp.Message) {
 				}
 			}
 		}
+		// Extended Next Hop Encoding is in effect only for NLRI types advertised by both peers
+		if lExtNH := peerUpMsg.SentOpen.ExtendedNextHopCapability(); len(lExtNH) != 0 {
+			rExtNH := peerUpMsg.ReceivedOpen.ExtendedNextHopCapability()
+			for k := range lExtNH {
+				if rExtNH[k] {
+					p.extNexthop[k] = true
+				}
+			}
+		}
 		m.AdvCapabilities = peerUpMsg.SentOpen.GetCapabilities()
 		m.RcvCapabilities = peerUpMsg.ReceivedOpen.GetCapabilities()
 		if glog.V(6) {
@@ -89,14 +104,18 @@ func (p *producer) producePeerMessage(op int, msg bmp.Message) {
 			return
 		}
 		m = PeerStateChange{
-			Action:     "down",
-			RouterIP:   p.speakerIP,
-			PeerType:   uint8(msg.PeerHeader.PeerType),
-			RouterHash: p.speakerHash,
-			BMPReason:  int(peerDownMsg.Reason),
-			RemoteASN:  msg.PeerHeader.PeerAS,
-			PeerRD:     msg.PeerHeader.GetPeerDistinguisherString(),
-			Timestamp:  msg.PeerHeader.GetPeerTimestamp(),
+			Action:                  "down",
+			RouterIP:                p.speakerIP,
+			PeerType:                uint8(msg.PeerHeader.PeerType),
+			RouterHash:              p.speakerHash,
+			PeerHash:                p.peerHash(msg.PeerHeader),
+			BMPReason:               int(peerDownMsg.Reason),
+			RemoteASN:               msg.PeerHeader.PeerAS,
+			PeerRD:                  msg.PeerHeader.GetPeerDistinguisherString(),
+			Timestamp:               msg.PeerHeader.GetPeerTimestamp(),
+			TimestampEpoch:          msg.PeerHeader.GetPeerTimestampEpoch(),
+			CollectorTimestamp:      msg.PeerHeader.GetCollectorTimestamp(),
+			CollectorTimestampEpoch: msg.PeerHeader.GetCollectorTimestampEpoch(),
 		}
 		m.RemoteIP = msg.PeerHeader.GetPeerAddrString()
 		m.RemoteBGPID = msg.PeerHeader.GetPeerBGPIDString()
@@ -107,6 +126,14 @@ func (p *producer) producePeerMessage(op int, msg bmp.Message) {
 	}
 	if err := p.marshalAndPublish(&m, bmp.PeerStateChangeMsg, []byte(m.RouterHash), false); err != nil {
 		glog.Errorf("failed to process peer message with error: %+v", err)
-		return
+	}
+	if op == peerDown {
+		// The peer's table name is not valid past Peer Down, a new Peer Up advertises it again
+		p.setPeerTableName(m.PeerHash, "")
+		p.setPeerUp(m.PeerHash, nil)
+		// Statistics of the next session of the peer are not compared with the ended one
+		p.setStatsSample(m.PeerHash, nil)
+	} else {
+		p.setPeerUp(m.PeerHash, &m)
 	}
 }
@@ -8,10 +8,19 @@ import (
 	"github.com/sbezverk/gobmp/pkg/srv6"
 )
 
-func (p *producer) processMPUpdate(nlri bgp.MPNLRI, operation int, ph *bmp.PerPeerHeader, update *bgp.Update) {
+// processMPUpdate produces messages from MP_REACH_NLRI or MP_UNREACH_NLRI, rm is the Route Monitoring message
+// carrying the update, its TLVs provide path status of advertised unicast and l3vpn prefixes, produced messages
+// are collected in b.
+func (p *producer) processMPUpdate(nlri bgp.MPNLRI, operation int, ph *bmp.PerPeerHeader, update *bgp.Update, rm *bmp.RouteMonitor, b *updateBatch) {
 	labeled := false
 	labeledSet := false
 	switch nlri.GetAFISAFIType() {
+	case 3:
+		// MP_REACH_NLRI AFI 1 SAFI 2, multicast prefixes are published as unicast prefixes with safi 2
+		fallthrough
+	case 4:
+		// MP_REACH_NLRI AFI 2 SAFI 2
+		fallthrough
 	case 1:
 		// MP_REACH_NLRI AFI 1 SAFI 1
 		if !labeledSet {
@@ -44,7 +53,11 @@ func (p *producer) processMPUpdate(nlri bgp.MPNLRI, operation int, ph *bmp.PerPe
 			return
 		}
 		// Loop through and publish all collected messages
-		for _, m := range msgs {
+		for i, m := range msgs {
+			if operation == AddPrefix {
+				m.PathStatus, m.PathStatusReason = pathStatus(rm, i+1)
+				m.VendorTLVs = enterpriseTLVs(rm, i+1)
+			}
 			topicType := bmp.UnicastPrefixMsg
 			if p.splitAF {
 				if m.IsIPv4 {
@@ -53,7 +66,7 @@ func (p *producer) processMPUpdate(nlri bgp.MPNLRI, operation int, ph *bmp.PerPe
 					topicType = bmp.UnicastPrefixV6Msg
 				}
 			}
-			if err := p.marshalAndPublish(&m, topicType, []byte(m.RouterHash), false); err != nil {
+			if err := p.marshalAndBatch(b, &m, topicType, []byte(m.RouterHash), false); err != nil {
 				glog.Errorf("failed to process Unicast Prefix message with error: %+v", err)
 				return
 			}
@@ -66,7 +79,11 @@ func (p *producer) processMPUpdate(nlri bgp.MPNLRI, operation int, ph *bmp.PerPe
 			glog.Errorf("failed to produce l3vpn messages with error: %+v", err)
 			return
 		}
-		for _, m := range msgs {
+		for i, m := range msgs {
+			if operation == AddPrefix {
+				m.PathStatus, m.PathStatusReason = pathStatus(rm, i+1)
+				m.VendorTLVs = enterpriseTLVs(rm, i+1)
+			}
 			topicType := bmp.L3VPNMsg
 			if p.splitAF {
 				if m.IsIPv4 {
@@ -75,7 +92,7 @@ func (p *producer) processMPUpdate(nlri bgp.MPNLRI, operation int, ph *bmp.PerPe
 					topicType = bmp.L3VPNV6Msg
 				}
 			}
-			if err := p.marshalAndPublish(&m, topicType, []byte(m.RouterHash), false); err != nil {
+			if err := p.marshalAndBatch(b, &m, topicType, []byte(m.RouterHash), false); err != nil {
 				glog.Errorf("failed to process L3VPN message with error: %+v", err)
 				return
 			}
@@ -87,7 +104,7 @@ func (p *producer) processMPUpdate(nlri bgp.MPNLRI, operation int, ph *bmp.PerPe
 			return
 		}
 		for _, msg := range msgs {
-			if err := p.marshalAndPublish(&msg, bmp.EVPNMsg, []byte(msg.RouterHash), false); err != nil {
+			if err := p.marshalAndBatch(b, &msg, bmp.EVPNMsg, []byte(msg.RouterHash), false); err != nil {
 				glog.Errorf("failed to process EVPNP message with error: %+v", err)
 				return
 			}
@@ -109,7 +126,7 @@ func (p *producer) processMPUpdate(nlri bgp.MPNLRI, operation int, ph *bmp.PerPe
 					topicType = bmp.SRPolicyV6Msg
 				}
 			}
-			if err := p.marshalAndPublish(&m, topicType, []byte(m.RouterHash), false); err != nil {
+			if err := p.marshalAndBatch(b, &m, topicType, []byte(m.RouterHash), false); err != nil {
 				glog.Errorf("failed to process SRPolicy message with error: %+v", err)
 				return
 			}
@@ -129,18 +146,46 @@ func (p *producer) processMPUpdate(nlri bgp.MPNLRI, operation int, ph *bmp.PerPe
 					topicType = bmp.FlowspecV6Msg
 				}
 			}
-			if err := p.marshalAndPublish(&m, topicType, []byte(m.SpecHash), false); err != nil {
+			if err := p.marshalAndBatch(b, &m, topicType, []byte(m.SpecHash), false); err != nil {
 				glog.Errorf("failed to process Flowspec message with error: %+v", err)
 				return
 			}
 		}
+	case 28:
+		msgs, err := p.rtc(nlri, operation, ph, update)
+		if err != nil {
+			glog.Errorf("failed to produce rt_constraint messages with error: %+v", err)
+			return
+		}
+		for _, m := range msgs {
+			if err := p.marshalAndBatch(b, &m, bmp.RTConstraintMsg, []byte(m.RouterHash), false); err != nil {
+				glog.Errorf("failed to process RT Constraint message with error: %+v", err)
+				return
+			}
+		}
+	case 29:
+		fallthrough
+	case 30:
+		msgs, err := p.mup(nlri, operation, ph, update)
+		if err != nil {
+			glog.Errorf("failed to produce mup messages with error: %+v", err)
+			return
+		}
+		for _, m := range msgs {
+			if err := p.marshalAndBatch(b, &m, bmp.MUPMsg, []byte(m.RouterHash), false); err != nil {
+				glog.Errorf("failed to process MUP message with error: %+v", err)
+				return
+			}
+		}
 	case 71:
-		p.processNLRI71SubTypes(nlri, operation, ph, update)
+		fallthrough
+	case 72:
+		p.processNLRI71SubTypes(nlri, operation, ph, update, b)
 	}
 }
 
-func (p *producer) processNLRI71SubTypes(nlri bgp.MPNLRI, operation int, ph *bmp.PerPeerHeader, update *bgp.Update) {
-	// NLRI 71 carries 6 known sub type
+func (p *producer) processNLRI71SubTypes(nlri bgp.MPNLRI, operation int, ph *bmp.PerPeerHeader, update *bgp.Update, b *updateBatch) {
+	// NLRI 71 and BGP-LS-VPN NLRI 72 carry 6 known sub type
 	ls, err := nlri.GetNLRI71()
 	if err != nil {
 		glog.Errorf("failed to NLRI 71 with error: %+v", err)
@@ -149,6 +194,11 @@ func (p *producer) processNLRI71SubTypes(nlri bgp.MPNLRI, operation int, ph *bmp
 	for _, e := range ls.NLRI {
 		// ipv4Flag used to differentiate between IPv4 and IPv6 Prefix NLRI messages
 		ipv4Flag := false
+		// Route Distinguisher of BGP-LS-VPN NLRI tells apart objects of different instances
+		var rd string
+		if e.RD != nil {
+			rd = e.RD.String()
+		}
 		switch e.Type {
 		case 1:
 			n, ok := e.LS.(*base.NodeNLRI)
@@ -161,7 +211,8 @@ func (p *producer) processNLRI71SubTypes(nlri bgp.MPNLRI, operation int, ph *bmp
 				glog.Errorf("failed to produce ls_node message with error: %+v", err)
 				continue
 			}
-			if err := p.marshalAndPublish(&msg, bmp.LSNodeMsg, []byte(msg.RouterHash), false); err != nil {
+			msg.VPNRD = rd
+			if err := p.marshalAndBatch(b, msg, bmp.LSNodeMsg, []byte(msg.RouterHash), false); err != nil {
 				glog.Errorf("failed to process LSNode message with error: %+v", err)
 				continue
 			}
@@ -176,10 +227,17 @@ func (p *producer) processNLRI71SubTypes(nlri bgp.MPNLRI, operation int, ph *bmp
 				glog.Errorf("failed to produce ls_link message with error: %+v", err)
 				continue
 			}
-			if err := p.marshalAndPublish(&msg, bmp.LSLinkMsg, []byte(msg.RouterHash), false); err != nil {
+			msg.VPNRD = rd
+			if err := p.marshalAndBatch(b, msg, bmp.LSLinkMsg, []byte(msg.RouterHash), false); err != nil {
 				glog.Errorf("failed to process LSLink message with error: %+v", err)
 				continue
 			}
+			if epe := p.bgpEPE(msg, update); epe != nil {
+				if err := p.marshalAndBatch(b, epe, bmp.BGPEPEMsg, []byte(epe.RouterHash), false); err != nil {
+					glog.Errorf("failed to process BGP EPE message with error: %+v", err)
+					continue
+				}
+			}
 		case 3:
 			ipv4Flag = true
 			fallthrough
@@ -194,7 +252,8 @@ func (p *producer) processNLRI71SubTypes(nlri bgp.MPNLRI, operation int, ph *bmp
 				glog.Errorf("failed to produce ls_prefix message with error: %+v", err)
 				continue
 			}
-			if err := p.marshalAndPublish(&msg, bmp.LSPrefixMsg, []byte(msg.RouterHash), false); err != nil {
+			msg.VPNRD = rd
+			if err := p.marshalAndBatch(b, msg, bmp.LSPrefixMsg, []byte(msg.RouterHash), false); err != nil {
 				glog.Errorf("failed to process LSPrefix message with error: %+v", err)
 				continue
 			}
@@ -209,7 +268,8 @@ func (p *producer) processNLRI71SubTypes(nlri bgp.MPNLRI, operation int, ph *bmp
 				glog.Errorf("failed to produce ls_srv6_sid message with error: %+v", err)
 				continue
 			}
-			if err := p.marshalAndPublish(&msg, bmp.LSSRv6SIDMsg, []byte(msg.RouterHash), false); err != nil {
+			msg.VPNRD = rd
+			if err := p.marshalAndBatch(b, msg, bmp.LSSRv6SIDMsg, []byte(msg.RouterHash), false); err != nil {
 				glog.Errorf("failed to process LSSRv6SID message with error: %+v", err)
 				continue
 			}
@@ -1,9 +1,15 @@
 package message
 
 import (
+	"net"
+	"sync"
+
 	"github.com/golang/glog"
 	"github.com/sbezverk/gobmp/pkg/bmp"
+	"github.com/sbezverk/gobmp/pkg/enrich"
 	"github.com/sbezverk/gobmp/pkg/pub"
+	"github.com/sbezverk/gobmp/pkg/rpki"
+	"github.com/sbezverk/gobmp/pkg/state"
 )
 
 const (
@@ -14,6 +20,8 @@ const (
 // Producer defines methods to act as a message producer
 type Producer interface {
 	Producer(queue chan bmp.Message, stop chan struct{})
+	Produce(msg bmp.Message)
+	TerminateSession(reason string)
 }
 
 type producer struct {
@@ -21,16 +29,48 @@ type producer struct {
 	speakerIP      string
 	speakerHash    string
 	addPathCapable map[int]bool
+	// extNexthop stores NLRI types for which both peers negotiated IPv6 next hop for IPv4 NLRI
+	extNexthop map[int]bool
 	// If splitAF is set to true, ipv4 and ipv6 messages will go into separate topics
 	splitAF bool
+	// If validator is not nil, unicast prefixes are annotated with Route Origin Validation state
+	validator rpki.Validator
+	// enrichers are invoked before a message is published to add plugins' fields
+	enrichers []enrich.Enricher
+	// sessionID identifies BMP session served by the producer
+	sessionID string
+	seqMtx    sync.Mutex
+	// sequence stores the sequence number of the last published message per peer hash
+	sequence map[string]int
+	// If store is not nil, BMP session state is saved and resumed after restart
+	store     state.Store
+	storeOnce sync.Once
+	tblMtx    sync.RWMutex
+	// tableName stores the name of VRF or table advertised in Peer Up message per peer hash
+	tableName map[string]string
+	// If checkUpdates is set to true, messages of BGP Updates are annotated with semantic validation results
+	checkUpdates bool
+	peerMtx      sync.Mutex
+	// upPeers stores Peer Up messages of peers which are up in BMP session per peer hash
+	upPeers  map[string]*PeerStateChange
+	statsMtx sync.Mutex
+	// stats stores the latest Stats Report per peer hash, it is used to compute changes of statistics
+	stats map[string]*statsSample
+	// If attachRaw is set to true, messages of BGP Updates carry raw BGP UPDATE and BMP headers
+	attachRaw bool
+	// If collectorHash is not empty, hashes are calculated as by OpenBMP collector with the hash
+	collectorHash string
+	// routerIP is the address of BMP session, it is used to calculate OpenBMP router_hash
+	routerIP string
 }
 
-// Producer dispatches kafka workers upon request received from the channel
+// Producer produces messages of BMP messages received from the channel one by one, so messages of BMP session
+// are published and numbered in the order they were received
 func (p *producer) Producer(queue chan bmp.Message, stop chan struct{}) {
 	for {
 		select {
 		case msg := <-queue:
-			go p.producingWorker(msg)
+			p.producingWorker(msg)
 		case <-stop:
 			glog.Infof("received interrupt, stopping.")
 			return
@@ -38,7 +78,17 @@ func (p *producer) Producer(queue chan bmp.Message, stop chan struct{}) {
 	}
 }
 
+// Produce publishes messages of a single BMP message synchronously
+func (p *producer) Produce(msg bmp.Message) {
+	p.producingWorker(msg)
+}
+
 func (p *producer) producingWorker(msg bmp.Message) {
+	publish := msg.Span.Child("bmp.publish")
+	defer func() {
+		publish.Finish()
+		msg.Span.Finish()
+	}()
 	switch obj := msg.Payload.(type) {
 	case *bmp.PeerUpMessage:
 		p.producePeerMessage(peerUP, msg)
@@ -53,11 +103,66 @@ func (p *producer) producingWorker(msg bmp.Message) {
 	}
 }
 
+// rpkiStatus returns Route Origin Validation state of the unicast prefix, or empty string
+// when RPKI validation is not enabled.
+func (p *producer) rpkiStatus(prfx *UnicastPrefix) string {
+	if p.validator == nil {
+		return ""
+	}
+
+	return string(p.validator.Validate(net.ParseIP(prfx.Prefix), int(prfx.PrefixLen), uint32(prfx.OriginAS)))
+}
+
+// ProducerConfig defines optional features of a producer. If SplitAF is set to true, ipv4 and ipv6 messages go
+// into separate topics. Validator, when not nil, enables RPKI Route Origin Validation of unicast prefixes.
+// Enrichers are plugins invoked before a message is published. Store, when not nil, resumes BMP session state
+// from the previous session of the router. When CheckUpdates is true, messages of BGP Updates are annotated with
+// results of semantic validation of the update, when AttachRaw is true, messages of BGP Updates carry the update
+// and BMP headers as received from the router. When OpenBMPAdminID is not empty, router_hash, peer_hash,
+// base_attr_hash and hashes of peers and unicast prefixes are calculated as by OpenBMP collector with the admin id,
+// RouterIP is the address of BMP session used for OpenBMP router_hash, router_ip is used when it is empty.
+type ProducerConfig struct {
+	SplitAF        bool
+	Validator      rpki.Validator
+	Enrichers      []enrich.Enricher
+	Store          state.Store
+	CheckUpdates   bool
+	AttachRaw      bool
+	OpenBMPAdminID string
+	RouterIP       string
+}
+
 // NewProducer instantiates a new instance of a producer with Publisher interface
 func NewProducer(publisher pub.Publisher, splitAF bool) Producer {
-	return &producer{
+	return NewProducerWithConfig(publisher, &ProducerConfig{SplitAF: splitAF})
+}
+
+// NewProducerWithConfig instantiates a new instance of a producer with Publisher interface and optional features
+// of the config, nil config enables none of them.
+func NewProducerWithConfig(publisher pub.Publisher, config *ProducerConfig) Producer {
+	if config == nil {
+		config = &ProducerConfig{}
+	}
+	p := &producer{
 		publisher:      publisher,
-		splitAF:        splitAF,
+		splitAF:        config.SplitAF,
 		addPathCapable: make(map[int]bool),
+		extNexthop:     make(map[int]bool),
+		validator:      config.Validator,
+		enrichers:      config.Enrichers,
+		sessionID:      newSessionID(),
+		sequence:       make(map[string]int),
+		store:          config.Store,
+		tableName:      make(map[string]string),
+		checkUpdates:   config.CheckUpdates,
+		upPeers:        make(map[string]*PeerStateChange),
+		stats:          make(map[string]*statsSample),
+		attachRaw:      config.AttachRaw,
+		routerIP:       config.RouterIP,
+	}
+	if config.OpenBMPAdminID != "" {
+		p.collectorHash = openBMPCollectorHash(config.OpenBMPAdminID)
 	}
+
+	return p
 }
@@ -0,0 +1,47 @@
+package message
+
+import (
+	"encoding/json"
+
+	"github.com/sbezverk/gobmp/pkg/bmp"
+)
+
+// RawUpdate defines BGP UPDATE and BMP headers of Route Monitoring message as received from the router, it is
+// attached as "raw" object to messages produced from the update, byte slices are base64 encoded in json.
+type RawUpdate struct {
+	BMPHeaders []byte `json:"bmp_headers,omitempty"`
+	BGPUpdate  []byte `json:"bgp_update"`
+}
+
+// rawUpdate returns json marshaled raw payload of Route Monitoring message, nil is returned when attaching
+// of raw payload is not enabled.
+func (p *producer) rawUpdate(msg bmp.Message, rm *bmp.RouteMonitor) []byte {
+	if !p.attachRaw || len(rm.RawUpdate) == 0 {
+		return nil
+	}
+	b, err := json.Marshal(&RawUpdate{
+		BMPHeaders: msg.RawHeaders,
+		BGPUpdate:  rm.RawUpdate,
+	})
+	if err != nil {
+		return nil
+	}
+
+	return b
+}
+
+// addField returns json marshaled message with the field added before the closing bracket of the message
+func addField(j []byte, key string, value []byte) []byte {
+	b := make([]byte, 0, len(j)+len(key)+len(value)+4)
+	b = append(b, j[:len(j)-1]...)
+	if len(j) > 2 {
+		b = append(b, ',')
+	}
+	b = append(b, '"')
+	b = append(b, key...)
+	b = append(b, '"', ':')
+	b = append(b, value...)
+	b = append(b, '}')
+
+	return b
+}
@@ -0,0 +1,54 @@
+package message
+
+import (
+	"bytes"
+	"encoding/base64"
+	"testing"
+
+	"github.com/sbezverk/gobmp/pkg/bmp"
+	"github.com/sbezverk/gobmp/pkg/fixture"
+	"github.com/sbezverk/gobmp/pkg/parser"
+)
+
+func TestRawUpdate(t *testing.T) {
+	fixtures, err := fixture.Load("testdata")
+	if err != nil {
+		t.Fatalf("failed to load fixtures with error: %+v", err)
+	}
+	var data []byte
+	for _, f := range fixtures {
+		if f.Name == "unicast-v4" {
+			data = f.Data
+		}
+	}
+	r := &recorder{msgs: make([]published, 0)}
+	p := NewProducerWithConfig(r, &ProducerConfig{SplitAF: true, AttachRaw: true}).(*producer)
+	for _, msg := range parser.Parse(data) {
+		p.producingWorker(msg)
+	}
+	for _, err := range r.errs {
+		t.Error(err)
+	}
+	marker := bytes.Repeat([]byte{0xff}, 16)
+	for _, m := range r.msgs {
+		raw, ok := m.Message["raw"].(map[string]interface{})
+		if m.Type == bmp.MsgTypeName(bmp.PeerStateChangeMsg) {
+			// Peer messages are not produced from BGP Updates
+			if ok {
+				t.Errorf("peer message carries raw payload %+v", raw)
+			}
+			continue
+		}
+		if !ok {
+			t.Fatalf("message %+v does not carry raw payload", m.Message)
+		}
+		headers, err := base64.StdEncoding.DecodeString(raw["bmp_headers"].(string))
+		if err != nil || len(headers) != bmp.CommonHeaderLength+bmp.PerPeerHeaderLength || headers[5] != bmp.RouteMonitorMsg {
+			t.Errorf("invalid raw bmp headers %v of message %+v", headers, m.Message)
+		}
+		update, err := base64.StdEncoding.DecodeString(raw["bgp_update"].(string))
+		if err != nil || !bytes.HasPrefix(update, marker) || update[18] != 2 {
+			t.Errorf("invalid raw bgp update %v of message %+v", update, m.Message)
+		}
+	}
+}
@@ -0,0 +1,50 @@
+package message
+
+import (
+	"github.com/sbezverk/gobmp/pkg/bgp"
+	"github.com/sbezverk/gobmp/pkg/bmp"
+)
+
+// bgpRoleCapability is the code of BGP Role capability https://tools.ietf.org/html/rfc9234#section-4.1
+const bgpRoleCapability = 9
+
+// localRole returns BGP Role of the monitored router in its session with the peer as advertised in BGP Role
+// capability of the router's OPEN, empty string is returned when Peer Up of the peer was not received or
+// the router does not advertise the role.
+func (p *producer) localRole(peerHash string) string {
+	p.peerMtx.Lock()
+	up := p.upPeers[peerHash]
+	p.peerMtx.Unlock()
+	if up == nil {
+		return ""
+	}
+	for _, c := range up.AdvCapabilities[bgpRoleCapability] {
+		if c.Parameters != nil {
+			return c.Parameters.Role
+		}
+	}
+
+	return ""
+}
+
+// leakSuspect returns true when the route violates Only to Customer procedures of https://tools.ietf.org/html/rfc9234#section-5
+// in the session where the monitored router advertises its BGP Role. A route with OTC attribute received from a customer
+// or RS-client, or received from a peer with OTC other than the peer's AS, is a leak. In Adj-RIB-Out, a route with OTC
+// advertised to a provider, peer or route server is a leak.
+func (p *producer) leakSuspect(ph *bmp.PerPeerHeader, attrs *bgp.BaseAttributes) bool {
+	if attrs == nil || attrs.OTC == 0 || ph.PeerType == bmp.PeerType3 {
+		return false
+	}
+	role := p.localRole(p.peerHash(ph))
+	if out, _ := ph.IsAdjRIBOutPost(); out {
+		return role == "customer" || role == "peer" || role == "rs_client"
+	}
+	switch role {
+	case "provider", "rs":
+		return true
+	case "peer":
+		return attrs.OTC != ph.PeerAS
+	}
+
+	return false
+}
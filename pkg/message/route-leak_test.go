@@ -0,0 +1,70 @@
+package message
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/sbezverk/gobmp/pkg/bgp"
+	"github.com/sbezverk/gobmp/pkg/bmp"
+)
+
+func TestLeakSuspect(t *testing.T) {
+	ph := bmp.NewPerPeerHeader(net.ParseIP("192.0.2.2"), 65002, net.ParseIP("192.0.2.2"), time.Now(), false)
+	tests := []struct {
+		name   string
+		role   string
+		otc    uint32
+		expect bool
+	}{
+		{
+			name:   "otc from customer",
+			role:   "provider",
+			otc:    65010,
+			expect: true,
+		},
+		{
+			name:   "otc from rs-client",
+			role:   "rs",
+			otc:    65010,
+			expect: true,
+		},
+		{
+			name: "no otc from customer",
+			role: "provider",
+		},
+		{
+			name: "otc of peer's as from peer",
+			role: "peer",
+			otc:  65002,
+		},
+		{
+			name:   "otc of other as from peer",
+			role:   "peer",
+			otc:    65010,
+			expect: true,
+		},
+		{
+			name: "otc from provider",
+			role: "customer",
+			otc:  65002,
+		},
+		{
+			name: "role not advertised",
+			otc:  65010,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := NewProducer(nil, true).(*producer)
+			caps := bgp.Capability{}
+			if tt.role != "" {
+				caps[bgpRoleCapability] = []*bgp.CapabilityData{{Parameters: &bgp.CapabilityParameters{Role: tt.role}}}
+			}
+			p.setPeerUp(ph.GetPeerHash(), &PeerStateChange{AdvCapabilities: caps})
+			if leak := p.leakSuspect(ph, &bgp.BaseAttributes{OTC: tt.otc}); leak != tt.expect {
+				t.Errorf("expected leak suspect %t, got %t", tt.expect, leak)
+			}
+		})
+	}
+}
@@ -33,6 +33,16 @@ func (p *producer) produceRouteMonitorMessage(msg bmp.Message) {
 	if routeMonitorMsg.Update == nil {
 		return
 	}
+	if p.collectorHash != "" && routeMonitorMsg.Update.BaseAttributes != nil {
+		routeMonitorMsg.Update.BaseAttributes.BaseAttrHash = routeMonitorMsg.Update.BaseAttributes.OpenBMPHash(p.peerHash(msg.PeerHeader))
+	}
+	if p.checkUpdates {
+		routeMonitorMsg.Update.Validation = routeMonitorMsg.Update.Validate()
+	}
+	// Messages of all prefixes of the update are published together
+	b := newUpdateBatch()
+	b.raw = p.rawUpdate(msg, routeMonitorMsg)
+	defer p.publishBatch(b)
 	attrType := uint8(0)
 	index := 0
 	if len(routeMonitorMsg.Update.PathAttributes) != 0 {
@@ -46,14 +56,14 @@ func (p *producer) produceRouteMonitorMessage(msg bmp.Message) {
 		if err != nil {
 			glog.Errorf("failed to process MP_REACH_NLRI with error: %+v", err)
 		}
-		p.processMPUpdate(nlri, AddPrefix, msg.PeerHeader, routeMonitorMsg.Update)
+		p.processMPUpdate(nlri, AddPrefix, msg.PeerHeader, routeMonitorMsg.Update, routeMonitorMsg, b)
 	case 15:
 		// MP_UNREACH_NLRI
 		nlri, err := bgp.UnmarshalMPUnReachNLRI(routeMonitorMsg.Update.PathAttributes[index].Attribute, p.addPathCapable)
 		if err != nil {
 			glog.Errorf("failed to process MP_UNREACH_NLRI with error: %+v", err)
 		}
-		p.processMPUpdate(nlri, DelPrefix, msg.PeerHeader, routeMonitorMsg.Update)
+		p.processMPUpdate(nlri, DelPrefix, msg.PeerHeader, routeMonitorMsg.Update, routeMonitorMsg, b)
 	default:
 		t := bmp.UnicastPrefixMsg
 		if p.splitAF {
@@ -74,10 +84,14 @@ func (p *producer) produceRouteMonitorMessage(msg bmp.Message) {
 			glog.Errorf("failed to produce original NLRI Withdraw message with error: %+v", err)
 			return
 		}
+		for i := range msg {
+			msg[i].PathStatus, msg[i].PathStatusReason = pathStatus(routeMonitorMsg, i+1)
+			msg[i].VendorTLVs = enterpriseTLVs(routeMonitorMsg, i+1)
+		}
 		msgs = append(msgs, msg...)
 		// Loop through and publish all collected messages
 		for _, m := range msgs {
-			if err := p.marshalAndPublish(&m, t, []byte(m.RouterHash), false); err != nil {
+			if err := p.marshalAndBatch(b, &m, t, []byte(m.RouterHash), false); err != nil {
 				glog.Errorf("failed to process Unicast Prefix message with error: %+v", err)
 				return
 			}
@@ -86,13 +100,41 @@ func (p *producer) produceRouteMonitorMessage(msg bmp.Message) {
 }
 
 func (p *producer) marshalAndPublish(msg interface{}, msgType int, hash []byte, debug bool) error {
+	return p.marshalAndBatch(nil, msg, msgType, hash, debug)
+}
+
+// marshalAndBatch marshals the message and collects it in b to be published with other messages of the same
+// BGP Update, the message is published immediately when b is nil.
+func (p *producer) marshalAndBatch(b *updateBatch, msg interface{}, msgType int, hash []byte, debug bool) error {
+	p.setHash(msg)
+	p.setSequence(msg)
+	p.setTableName(msg)
 	j, err := json.Marshal(msg)
 	if err != nil {
 		return fmt.Errorf("failed to marshal a message of type %d with error: %+v", msgType, err)
 	}
-	if err := p.publisher.PublishMessage(msgType, hash, j); err != nil {
+	var fields map[string]interface{}
+	if len(p.enrichers) != 0 {
+		if j, fields, err = p.enrich(msgType, msg, j); err != nil {
+			return fmt.Errorf("failed to enrich a message of type %d with error: %+v", msgType, err)
+		}
+	}
+	if b != nil && b.raw != nil {
+		j = addField(j, "raw", b.raw)
+	}
+	if b != nil {
+		b.add(msgType, hash, j)
+	} else if err := p.publisher.PublishMessage(msgType, hash, j); err != nil {
 		return fmt.Errorf("failed to push a message of type %d to kafka with error: %+v", msgType, err)
 	}
+	if tp, ok := p.publisher.(TypedPublisher); ok {
+		tp.PublishTyped(&Message{
+			Type:       msgType,
+			Hash:       hash,
+			Payload:    copyPayload(msg),
+			Enrichment: fields,
+		})
+	}
 	if debug {
 		glog.Infof("message of type: %+v json: %s", msgType, string(j))
 	}
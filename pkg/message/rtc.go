@@ -0,0 +1,63 @@
+package message
+
+import (
+	"fmt"
+
+	"github.com/sbezverk/gobmp/pkg/bgp"
+	"github.com/sbezverk/gobmp/pkg/bmp"
+)
+
+// rtc process MP_REACH_NLRI and MP_UNREACH_NLRI AFI 1 SAFI 132 update message and returns
+// Route Target Constraint objects.
+func (p *producer) rtc(nlri bgp.MPNLRI, op int, ph *bmp.PerPeerHeader, update *bgp.Update) ([]RTConstraint, error) {
+	var operation string
+	switch op {
+	case 0:
+		operation = "add"
+	case 1:
+		operation = "del"
+	default:
+		return nil, fmt.Errorf("unknown operation %d", op)
+	}
+	r, err := nlri.GetNLRIRTC()
+	if err != nil {
+		return nil, err
+	}
+	msgs := make([]RTConstraint, 0, len(r.Route))
+	for _, e := range r.Route {
+		m := RTConstraint{
+			Action:                  operation,
+			RouterHash:              p.speakerHash,
+			RouterIP:                p.speakerIP,
+			PeerHash:                p.peerHash(ph),
+			PeerIP:                  ph.GetPeerAddrString(),
+			PeerType:                uint8(ph.PeerType),
+			PeerRD:                  ph.GetPeerDistinguisherString(),
+			Validation:              update.Validation,
+			PeerASN:                 ph.PeerAS,
+			Timestamp:               ph.GetPeerTimestamp(),
+			TimestampEpoch:          ph.GetPeerTimestampEpoch(),
+			CollectorTimestamp:      ph.GetCollectorTimestamp(),
+			CollectorTimestampEpoch: ph.GetCollectorTimestampEpoch(),
+			BaseAttributes:          update.BaseAttributes,
+			OriginAS:                e.OriginAS,
+			RouteTarget:             e.GetRouteTarget(),
+			PrefixLen:               int32(e.Length),
+			Nexthop:                 nlri.GetNextHop(),
+			IsNexthopIPv4:           !nlri.IsNextHopIPv6(),
+			PathID:                  int32(e.PathID),
+		}
+		if f, err := ph.IsAdjRIBInPost(); err == nil {
+			m.IsAdjRIBInPost = f
+		}
+		if f, err := ph.IsAdjRIBOutPost(); err == nil {
+			m.IsAdjRIBOutPost = f
+		}
+		if f, err := ph.IsLocRIBFiltered(); err == nil {
+			m.IsLocRIBFiltered = f
+		}
+		msgs = append(msgs, m)
+	}
+
+	return msgs, nil
+}
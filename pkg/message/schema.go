@@ -0,0 +1,53 @@
+package message
+
+import (
+	"github.com/sbezverk/gobmp/pkg/bmp"
+	"github.com/sbezverk/gobmp/pkg/schema"
+)
+
+// schemaTypes defines Go types of messages published by the producer
+var schemaTypes = map[int]interface{}{
+	bmp.PeerStateChangeMsg: &PeerStateChange{},
+	bmp.UnicastPrefixMsg:   &UnicastPrefix{},
+	bmp.UnicastPrefixV4Msg: &UnicastPrefix{},
+	bmp.UnicastPrefixV6Msg: &UnicastPrefix{},
+	bmp.LSNodeMsg:          &LSNode{},
+	bmp.LSLinkMsg:          &LSLink{},
+	bmp.L3VPNMsg:           &L3VPNPrefix{},
+	bmp.L3VPNV4Msg:         &L3VPNPrefix{},
+	bmp.L3VPNV6Msg:         &L3VPNPrefix{},
+	bmp.LSPrefixMsg:        &LSPrefix{},
+	bmp.LSSRv6SIDMsg:       &LSSRv6SID{},
+	bmp.EVPNMsg:            &EVPNPrefix{},
+	bmp.SRPolicyMsg:        &SRPolicy{},
+	bmp.SRPolicyV4Msg:      &SRPolicy{},
+	bmp.SRPolicyV6Msg:      &SRPolicy{},
+	bmp.FlowspecMsg:        &Flowspec{},
+	bmp.FlowspecV4Msg:      &Flowspec{},
+	bmp.FlowspecV6Msg:      &Flowspec{},
+	bmp.StatsReportMsg:     &Stats{},
+	bmp.RTConstraintMsg:    &RTConstraint{},
+	bmp.MUPMsg:             &MUPRoute{},
+	bmp.BGPEPEMsg:          &BGPEPE{},
+}
+
+// Schemas returns JSON Schemas of messages published by the producer indexed by message type,
+// messages carry optional enrichment object added by enrichment plugins and optional raw object
+// with BGP UPDATE and BMP headers of messages of BGP Updates.
+func Schemas() map[int]*schema.Schema {
+	schemas := make(map[int]*schema.Schema, len(schemaTypes))
+	for t, v := range schemaTypes {
+		schemas[t] = schema.Generate(bmp.MsgTypeName(t), v, map[string]*schema.Schema{
+			"enrichment": {Type: "object"},
+			"raw": {
+				Type: "object",
+				Properties: map[string]*schema.Schema{
+					"bmp_headers": {Type: "string"},
+					"bgp_update":  {Type: "string"},
+				},
+			},
+		})
+	}
+
+	return schemas
+}
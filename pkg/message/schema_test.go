@@ -0,0 +1,70 @@
+package message
+
+import (
+	"testing"
+
+	"github.com/sbezverk/gobmp/pkg/bgp"
+	"github.com/sbezverk/gobmp/pkg/bmp"
+	"github.com/sbezverk/gobmp/pkg/enrich"
+	"github.com/sbezverk/gobmp/pkg/schema"
+)
+
+// discard is a Publisher dropping all messages
+type discard struct{}
+
+func (d *discard) PublishMessage(msgType int, msgHash []byte, msg []byte) error { return nil }
+
+func (d *discard) Stop() {}
+
+func TestSchemas(t *testing.T) {
+	p := &producer{
+		publisher: schema.NewValidator(Schemas(), &discard{}),
+		sessionID: newSessionID(),
+		sequence:  make(map[string]int),
+		enrichers: []enrich.Enricher{&asNameEnricher{}},
+	}
+	tests := []struct {
+		name    string
+		msgType int
+		msg     interface{}
+	}{
+		{
+			name:    "peer",
+			msgType: bmp.PeerStateChangeMsg,
+			msg:     &PeerStateChange{Action: "add", RouterIP: "192.0.2.1", RemoteBGPID: "192.0.2.2"},
+		},
+		{
+			name:    "enriched unicast prefix with attributes",
+			msgType: bmp.UnicastPrefixV4Msg,
+			msg: &UnicastPrefix{
+				Action:         "add",
+				Prefix:         "10.0.0.0",
+				PrefixLen:      8,
+				OriginAS:       65001,
+				Labels:         []uint32{16000},
+				PathStatus:     []string{"invalid_as_loop"},
+				BaseAttributes: &bgp.BaseAttributes{ASPath: []uint32{65001}, Origin: "igp", LocalPref: 100},
+			},
+		},
+		{
+			name:    "l3vpn prefix",
+			msgType: bmp.L3VPNV6Msg,
+			msg:     &L3VPNPrefix{Action: "del", Prefix: "2001:db8::", PrefixLen: 32, VPNRD: "65000:1"},
+		},
+	}
+	for typ, v := range schemaTypes {
+		// Zero value messages include all fields which are never omitted
+		tests = append(tests, struct {
+			name    string
+			msgType int
+			msg     interface{}
+		}{name: "empty " + bmp.MsgTypeName(typ), msgType: typ, msg: v})
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := p.marshalAndPublish(tt.msg, tt.msgType, []byte("key"), false); err != nil {
+				t.Fatalf("supposed to succeed but failed with error: %+v", err)
+			}
+		})
+	}
+}
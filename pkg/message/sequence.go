@@ -0,0 +1,104 @@
+package message
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/sbezverk/gobmp/pkg/state"
+)
+
+// newSessionID returns a random identifier of BMP session, if random generator fails,
+// the current time is used instead.
+func newSessionID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return fmt.Sprintf("%x", time.Now().UnixNano())
+	}
+
+	return hex.EncodeToString(b)
+}
+
+// nextSequence returns BMP session ID and the next sequence number of a message of the peer, sequence numbers
+// start with 1 and are maintained per peer for the duration of BMP session.
+func (p *producer) nextSequence(peerHash string) (string, int) {
+	p.seqMtx.Lock()
+	defer p.seqMtx.Unlock()
+	p.sequence[peerHash]++
+
+	return p.sessionID, p.sequence[peerHash]
+}
+
+// sessionState returns a copy of BMP session ID and sequence numbers, it is saved by the state store.
+func (p *producer) sessionState() *state.SessionState {
+	p.seqMtx.Lock()
+	defer p.seqMtx.Unlock()
+	s := &state.SessionState{
+		SessionID: p.sessionID,
+		Sequence:  make(map[string]int, len(p.sequence)),
+	}
+	for k, v := range p.sequence {
+		s.Sequence[k] = v
+	}
+
+	return s
+}
+
+// restoreSession registers BMP session of the speaker with the state store, when the speaker is known,
+// BMP session ID and sequence numbers of the previous session are resumed.
+func (p *producer) restoreSession() {
+	if p.store == nil {
+		return
+	}
+	restored := p.store.Session(p.speakerIP, p.sessionState)
+	if restored == nil {
+		return
+	}
+	p.seqMtx.Lock()
+	defer p.seqMtx.Unlock()
+	if restored.SessionID != "" {
+		p.sessionID = restored.SessionID
+	}
+	for k, v := range restored.Sequence {
+		// Messages published before the session was restored are counted on top of the restored numbers
+		p.sequence[k] += v
+	}
+	glog.Infof("producer for speaker ip: %s resumed BMP session %s", p.speakerIP, p.sessionID)
+}
+
+// setSequence stamps the message with BMP session ID and per peer sequence number, consumers can use them
+// to detect lost or reordered messages and to detect a new BMP session which requires resync.
+func (p *producer) setSequence(msg interface{}) {
+	switch m := msg.(type) {
+	case *PeerStateChange:
+		m.SessionID, m.Sequence = p.nextSequence(m.PeerHash)
+	case *UnicastPrefix:
+		m.SessionID, m.Sequence = p.nextSequence(m.PeerHash)
+	case *L3VPNPrefix:
+		m.SessionID, m.Sequence = p.nextSequence(m.PeerHash)
+	case *EVPNPrefix:
+		m.SessionID, m.Sequence = p.nextSequence(m.PeerHash)
+	case *SRPolicy:
+		m.SessionID, m.Sequence = p.nextSequence(m.PeerHash)
+	case *Flowspec:
+		m.SessionID, m.Sequence = p.nextSequence(m.PeerHash)
+	case *LSNode:
+		m.SessionID, m.Sequence = p.nextSequence(m.PeerHash)
+	case *LSLink:
+		m.SessionID, m.Sequence = p.nextSequence(m.PeerHash)
+	case *LSPrefix:
+		m.SessionID, m.Sequence = p.nextSequence(m.PeerHash)
+	case *LSSRv6SID:
+		m.SessionID, m.Sequence = p.nextSequence(m.PeerHash)
+	case *RTConstraint:
+		m.SessionID, m.Sequence = p.nextSequence(m.PeerHash)
+	case *MUPRoute:
+		m.SessionID, m.Sequence = p.nextSequence(m.PeerHash)
+	case *BGPEPE:
+		m.SessionID, m.Sequence = p.nextSequence(m.PeerHash)
+	case *Stats:
+		m.SessionID, m.Sequence = p.nextSequence(m.PeerHash)
+	}
+}
@@ -0,0 +1,115 @@
+package message
+
+import (
+	"encoding/json"
+	"sync"
+	"testing"
+
+	"github.com/sbezverk/gobmp/pkg/bmp"
+	"github.com/sbezverk/gobmp/pkg/fixture"
+	"github.com/sbezverk/gobmp/pkg/parser"
+)
+
+func TestSetSequence(t *testing.T) {
+	p := &producer{
+		sessionID: newSessionID(),
+		sequence:  make(map[string]int),
+	}
+	var wg sync.WaitGroup
+	for i := 0; i < 100; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			p.setSequence(&UnicastPrefix{PeerHash: "peer1"})
+		}()
+		go func() {
+			defer wg.Done()
+			p.setSequence(&PeerStateChange{PeerHash: "peer2"})
+		}()
+	}
+	wg.Wait()
+	m1 := &UnicastPrefix{PeerHash: "peer1"}
+	p.setSequence(m1)
+	if m1.Sequence != 101 {
+		t.Errorf("expected sequence 101 of peer1, got %d", m1.Sequence)
+	}
+	if m1.SessionID != p.sessionID || len(m1.SessionID) != 32 {
+		t.Errorf("expected session id %s, got %s", p.sessionID, m1.SessionID)
+	}
+	m2 := &Stats{PeerHash: "peer2"}
+	p.setSequence(m2)
+	if m2.Sequence != 101 {
+		t.Errorf("expected sequence 101 of peer2, got %d", m2.Sequence)
+	}
+	m3 := &LSNode{PeerHash: "peer3"}
+	p.setSequence(m3)
+	if m3.Sequence != 1 {
+		t.Errorf("expected sequence 1 of peer3, got %d", m3.Sequence)
+	}
+}
+
+// orderedCollector is a Publisher storing published messages in the order they were published
+type orderedCollector struct {
+	sync.Mutex
+	msgs []json.RawMessage
+}
+
+func (c *orderedCollector) PublishMessage(msgType int, msgHash []byte, msg []byte) error {
+	c.Lock()
+	defer c.Unlock()
+	c.msgs = append(c.msgs, json.RawMessage(msg))
+	return nil
+}
+
+func (c *orderedCollector) Stop() {}
+
+func TestProducerSequence(t *testing.T) {
+	fixtures, err := fixture.Load("testdata")
+	if err != nil {
+		t.Fatalf("failed to load fixtures with error: %+v", err)
+	}
+	var data []byte
+	for _, f := range fixtures {
+		if f.Name == "unicast-v4" {
+			data = f.Data
+		}
+	}
+	// Peer Up is followed by an update advertising 2 prefixes and an update withdrawing one of them
+	msgs := parser.Parse(data)
+	c := &orderedCollector{}
+	p := NewProducer(c, false)
+	queue := make(chan bmp.Message)
+	stop := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		p.Producer(queue, stop)
+		close(done)
+	}()
+	queue <- msgs[0]
+	for i := 0; i < 100; i++ {
+		for _, msg := range msgs[1:] {
+			queue <- msg
+		}
+	}
+	close(stop)
+	<-done
+	expect := []string{"add", "add", "del"}
+	var n int
+	for _, msg := range c.msgs {
+		m := &UnicastPrefix{}
+		if err := json.Unmarshal(msg, m); err != nil {
+			t.Fatalf("failed to unmarshal message %s with error: %+v", string(msg), err)
+		}
+		if m.Sequence != n+1 {
+			t.Fatalf("expected sequence %d, got %d", n+1, m.Sequence)
+		}
+		// Actions of prefixes are published in the order updates were received
+		if n != 0 && m.Action != expect[(n-1)%len(expect)] {
+			t.Fatalf("expected action %s of message %d, got %s", expect[(n-1)%len(expect)], n, m.Action)
+		}
+		n++
+	}
+	if n != 301 {
+		t.Fatalf("expected 301 messages, got %d", n)
+	}
+}
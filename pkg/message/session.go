@@ -0,0 +1,55 @@
+package message
+
+import (
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/sbezverk/gobmp/pkg/bmp"
+)
+
+// setPeerUp stores Peer Up message of the peer which is up in BMP session, nil removes the peer's entry
+func (p *producer) setPeerUp(peerHash string, m *PeerStateChange) {
+	p.peerMtx.Lock()
+	defer p.peerMtx.Unlock()
+	if m == nil {
+		delete(p.upPeers, peerHash)
+		return
+	}
+	if p.upPeers == nil {
+		p.upPeers = make(map[string]*PeerStateChange)
+	}
+	p.upPeers[peerHash] = m
+}
+
+// TerminateSession publishes synthetic Peer Down messages of all peers which are up in BMP session, it is called
+// when BMP session is torn down by the collector without BMP Termination message, reason is carried in error_text.
+func (p *producer) TerminateSession(reason string) {
+	p.peerMtx.Lock()
+	peers := p.upPeers
+	p.upPeers = make(map[string]*PeerStateChange)
+	p.peerMtx.Unlock()
+	now := time.Now().UTC()
+	for _, up := range peers {
+		m := &PeerStateChange{
+			Action:                  "down",
+			RouterIP:                up.RouterIP,
+			RouterHash:              up.RouterHash,
+			PeerHash:                up.PeerHash,
+			PeerType:                up.PeerType,
+			PeerRD:                  up.PeerRD,
+			RemoteASN:               up.RemoteASN,
+			RemoteIP:                up.RemoteIP,
+			RemoteBGPID:             up.RemoteBGPID,
+			IsIPv4:                  up.IsIPv4,
+			ErrorText:               reason,
+			Timestamp:               now.Format(time.RFC3339Nano),
+			TimestampEpoch:          now.UnixNano() / int64(time.Microsecond),
+			CollectorTimestamp:      now.Format(time.RFC3339Nano),
+			CollectorTimestampEpoch: now.UnixNano() / int64(time.Microsecond),
+		}
+		if err := p.marshalAndPublish(m, bmp.PeerStateChangeMsg, []byte(m.RouterHash), false); err != nil {
+			glog.Errorf("failed to publish peer down of terminated session with error: %+v", err)
+		}
+		p.setPeerTableName(m.PeerHash, "")
+	}
+}
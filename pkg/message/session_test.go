@@ -0,0 +1,63 @@
+package message
+
+import (
+	"testing"
+
+	"github.com/sbezverk/gobmp/pkg/fixture"
+	"github.com/sbezverk/gobmp/pkg/parser"
+)
+
+func TestTerminateSession(t *testing.T) {
+	fixtures, err := fixture.Load("testdata")
+	if err != nil {
+		t.Fatalf("failed to load fixtures with error: %+v", err)
+	}
+	data := make(map[string][]byte)
+	for _, f := range fixtures {
+		data[f.Name] = f.Data
+	}
+	tests := []struct {
+		name    string
+		fixture string
+		downs   int
+	}{
+		{
+			name:    "peer up without peer down",
+			fixture: "unicast-v4",
+			downs:   1,
+		},
+		{
+			name:    "peer up followed by peer down",
+			fixture: "peer",
+			downs:   0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &recorder{msgs: make([]published, 0)}
+			p := NewProducerWithConfig(r, &ProducerConfig{SplitAF: true, CheckUpdates: true}).(*producer)
+			for _, msg := range parser.Parse(data[tt.fixture]) {
+				p.producingWorker(msg)
+			}
+			n := len(r.msgs)
+			p.TerminateSession("idle")
+			// Peers are forgotten after the session is terminated
+			p.TerminateSession("idle")
+			for _, err := range r.errs {
+				t.Error(err)
+			}
+			downs := r.msgs[n:]
+			if len(downs) != tt.downs {
+				t.Fatalf("expected %d peer down messages but got %d", tt.downs, len(downs))
+			}
+			for _, m := range downs {
+				if m.Type != "peer" || m.Message["action"] != "down" || m.Message["error_text"] != "idle" {
+					t.Errorf("unexpected message of terminated session %+v", m)
+				}
+				if m.Message["remote_ip"] != "192.168.80.103" {
+					t.Errorf("expected peer down of peer 192.168.80.103 but got %+v", m.Message["remote_ip"])
+				}
+			}
+		})
+	}
+}
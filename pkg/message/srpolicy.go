@@ -25,15 +25,20 @@ func (p *producer) srpolicy(nlri bgp.MPNLRI, op int, ph *bmp.PerPeerHeader, upda
 		return nil, fmt.Errorf("unknown operation %d", op)
 	}
 	prfx := SRPolicy{
-		Action:         operation,
-		RouterHash:     p.speakerHash,
-		RouterIP:       p.speakerIP,
-		PeerType:       uint8(ph.PeerType),
-		PeerHash:       ph.GetPeerHash(),
-		PeerASN:        ph.PeerAS,
-		Timestamp:      ph.GetPeerTimestamp(),
-		Nexthop:        nlri.GetNextHop(),
-		BaseAttributes: update.BaseAttributes,
+		Action:                  operation,
+		RouterHash:              p.speakerHash,
+		RouterIP:                p.speakerIP,
+		PeerType:                uint8(ph.PeerType),
+		PeerRD:                  ph.GetPeerDistinguisherString(),
+		Validation:              update.Validation,
+		PeerHash:                p.peerHash(ph),
+		PeerASN:                 ph.PeerAS,
+		Timestamp:               ph.GetPeerTimestamp(),
+		TimestampEpoch:          ph.GetPeerTimestampEpoch(),
+		CollectorTimestamp:      ph.GetCollectorTimestamp(),
+		CollectorTimestampEpoch: ph.GetCollectorTimestampEpoch(),
+		Nexthop:                 nlri.GetNextHop(),
+		BaseAttributes:          update.BaseAttributes,
 	}
 	if f, err := ph.IsAdjRIBInPost(); err == nil {
 		prfx.IsAdjRIBInPost = f
@@ -44,10 +49,7 @@ func (p *producer) srpolicy(nlri bgp.MPNLRI, op int, ph *bmp.PerPeerHeader, upda
 	if f, err := ph.IsLocRIBFiltered(); err == nil {
 		prfx.IsLocRIBFiltered = f
 	}
-	if ases := update.BaseAttributes.ASPath; len(ases) != 0 {
-		// Last element in AS_PATH would be the AS of the origin
-		prfx.OriginAS = int32(ases[len(ases)-1])
-	}
+	prfx.OriginAS = int32(update.BaseAttributes.OriginAS)
 	prfx.PeerIP = ph.GetPeerAddrString()
 	prfx.IsIPv4 = true
 	prfx.IsNexthopIPv4 = true
@@ -0,0 +1,171 @@
+package message
+
+import (
+	"reflect"
+	"sync"
+
+	"github.com/sbezverk/gobmp/pkg/pub"
+)
+
+// Message defines a message published by the producer as it is delivered to Go consumers, without
+// encoding it to JSON.
+type Message struct {
+	// Type is the type of message, defined in pkg/bmp/consts.go
+	Type int
+	// Hash is the key the message is published with
+	Hash []byte
+	// Payload is a pointer to one of PeerStateChange, UnicastPrefix, LSNode, LSLink, LSPrefix, L3VPNPrefix,
+	// EVPNPrefix, LSSRv6SID, SRPolicy, Flowspec, RTConstraint, MUPRoute, BGPEPE or Stats,
+	// depending on Type
+	Payload interface{}
+	// Enrichment carries fields added by enrichment plugins, nil when the message is not enriched
+	Enrichment map[string]interface{}
+}
+
+// TypedPublisher defines a Publisher which receives published messages as Go types in addition to JSON,
+// the producer calls PublishTyped after the message is published with PublishMessage.
+type TypedPublisher interface {
+	pub.Publisher
+	PublishTyped(msg *Message)
+}
+
+// Subscription defines a subscription of a Go consumer to published messages
+type Subscription interface {
+	// C returns the channel of messages, it is closed when the subscription is cancelled or the broker is stopped
+	C() <-chan *Message
+	Cancel()
+}
+
+// Broker defines a TypedPublisher distributing published messages to subscriptions
+type Broker interface {
+	TypedPublisher
+	// Subscribe returns a subscription to messages of listed types, or to all messages when no types are listed,
+	// size is the capacity of the subscription's channel.
+	Subscribe(size int, types ...int) Subscription
+}
+
+type broker struct {
+	publisher pub.Publisher
+	mtx       sync.RWMutex
+	subs      map[*subscription]bool
+}
+
+type subscription struct {
+	broker *broker
+	types  map[int]bool
+	ch     chan *Message
+	done   chan struct{}
+	once   sync.Once
+	// mtx protects ch from being closed while a message is sent to it
+	mtx    sync.RWMutex
+	closed bool
+}
+
+func (s *subscription) C() <-chan *Message {
+	return s.ch
+}
+
+func (s *subscription) Cancel() {
+	s.once.Do(func() {
+		// Unblocking a send to the full channel before closing it
+		close(s.done)
+		s.mtx.Lock()
+		s.closed = true
+		close(s.ch)
+		s.mtx.Unlock()
+		s.broker.mtx.Lock()
+		delete(s.broker.subs, s)
+		s.broker.mtx.Unlock()
+	})
+}
+
+func (s *subscription) send(msg *Message) {
+	if len(s.types) != 0 && !s.types[msg.Type] {
+		return
+	}
+	s.mtx.RLock()
+	defer s.mtx.RUnlock()
+	if s.closed {
+		return
+	}
+	select {
+	case s.ch <- msg:
+	case <-s.done:
+	}
+}
+
+func (b *broker) Subscribe(size int, types ...int) Subscription {
+	s := &subscription{
+		broker: b,
+		types:  make(map[int]bool, len(types)),
+		ch:     make(chan *Message, size),
+		done:   make(chan struct{}),
+	}
+	for _, t := range types {
+		s.types[t] = true
+	}
+	b.mtx.Lock()
+	b.subs[s] = true
+	b.mtx.Unlock()
+
+	return s
+}
+
+func (b *broker) subscriptions() []*subscription {
+	b.mtx.RLock()
+	defer b.mtx.RUnlock()
+	subs := make([]*subscription, 0, len(b.subs))
+	for s := range b.subs {
+		subs = append(subs, s)
+	}
+
+	return subs
+}
+
+// PublishMessage passes JSON encoded message to the wrapped publisher, if any
+func (b *broker) PublishMessage(msgType int, msgHash []byte, msg []byte) error {
+	if b.publisher == nil {
+		return nil
+	}
+
+	return b.publisher.PublishMessage(msgType, msgHash, msg)
+}
+
+// PublishTyped delivers the message to all subscriptions to its type, it blocks until subscriptions
+// have room for the message, so slow consumers slow down the producer instead of losing messages.
+func (b *broker) PublishTyped(msg *Message) {
+	for _, s := range b.subscriptions() {
+		s.send(msg)
+	}
+}
+
+// Stop cancels all subscriptions and stops the wrapped publisher, if any
+func (b *broker) Stop() {
+	for _, s := range b.subscriptions() {
+		s.Cancel()
+	}
+	if b.publisher != nil {
+		b.publisher.Stop()
+	}
+}
+
+// NewBroker returns a Broker to be passed to the producer as its Publisher, JSON encoded messages are
+// passed to publisher, which is optional and can be nil when messages are consumed only by Go subscribers.
+func NewBroker(publisher pub.Publisher) Broker {
+	return &broker{
+		publisher: publisher,
+		subs:      make(map[*subscription]bool),
+	}
+}
+
+// copyPayload returns a copy of the message, so the producer can reuse the message after it is published
+func copyPayload(msg interface{}) interface{} {
+	v := reflect.ValueOf(msg)
+	if v.Kind() != reflect.Ptr || v.IsNil() {
+		return msg
+	}
+	c := reflect.New(v.Elem().Type())
+	c.Elem().Set(v.Elem())
+
+	return c.Interface()
+}
@@ -0,0 +1,64 @@
+package message
+
+import (
+	"testing"
+
+	"github.com/sbezverk/gobmp/pkg/bmp"
+	"github.com/sbezverk/gobmp/pkg/fixture"
+	"github.com/sbezverk/gobmp/pkg/parser"
+)
+
+func TestBroker(t *testing.T) {
+	fixtures, err := fixture.Load("testdata")
+	if err != nil {
+		t.Fatalf("failed to load fixtures with error: %+v", err)
+	}
+	var data []byte
+	for _, f := range fixtures {
+		if f.Name == "unicast-v4" {
+			data = f.Data
+		}
+	}
+	if data == nil {
+		t.Fatalf("fixture unicast-v4 is not found")
+	}
+	r := &recorder{msgs: make([]published, 0)}
+	b := NewBroker(r)
+	all := b.Subscribe(16)
+	prefixes := b.Subscribe(16, bmp.UnicastPrefixV4Msg)
+	cancelled := b.Subscribe(16)
+	cancelled.Cancel()
+	p := NewProducer(b, true)
+	for _, msg := range parser.Parse(data) {
+		p.Produce(msg)
+	}
+	b.Stop()
+	if len(r.msgs) != 4 {
+		t.Fatalf("expected 4 messages published to the wrapped publisher, got %d", len(r.msgs))
+	}
+	types := make([]int, 0)
+	for msg := range all.C() {
+		types = append(types, msg.Type)
+	}
+	if len(types) != 4 || types[0] != bmp.PeerStateChangeMsg {
+		t.Fatalf("expected peer and 3 unicast prefix messages, got types %v", types)
+	}
+	seen := make(map[string]bool)
+	for msg := range prefixes.C() {
+		up, ok := msg.Payload.(*UnicastPrefix)
+		if !ok {
+			t.Fatalf("expected payload of type *UnicastPrefix, got %T", msg.Payload)
+		}
+		if string(msg.Hash) != up.RouterHash {
+			t.Fatalf("expected hash %s, got %s", up.RouterHash, string(msg.Hash))
+		}
+		seen[up.Action+" "+up.Prefix] = true
+	}
+	// Payloads are copies, not the message reused by the producer
+	if len(seen) != 3 {
+		t.Fatalf("expected 3 different prefix messages, got %v", seen)
+	}
+	if _, ok := <-cancelled.C(); ok {
+		t.Fatalf("expected channel of cancelled subscription to be closed")
+	}
+}
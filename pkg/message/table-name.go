@@ -0,0 +1,55 @@
+package message
+
+// setPeerTableName stores the name of VRF or table advertised in Peer Up message of the peer,
+// empty name removes the peer's entry.
+func (p *producer) setPeerTableName(peerHash, name string) {
+	p.tblMtx.Lock()
+	defer p.tblMtx.Unlock()
+	if name == "" {
+		delete(p.tableName, peerHash)
+		return
+	}
+	p.tableName[peerHash] = name
+}
+
+func (p *producer) peerTableName(peerHash string) string {
+	p.tblMtx.RLock()
+	defer p.tblMtx.RUnlock()
+
+	return p.tableName[peerHash]
+}
+
+// setTableName labels the message with the name of VRF or table of the peer, so messages of per VRF
+// peers, for example RD instance peers, can be told apart without tracking Peer Up messages.
+func (p *producer) setTableName(msg interface{}) {
+	switch m := msg.(type) {
+	case *PeerStateChange:
+		m.TableName = p.peerTableName(m.PeerHash)
+	case *UnicastPrefix:
+		m.TableName = p.peerTableName(m.PeerHash)
+	case *L3VPNPrefix:
+		m.TableName = p.peerTableName(m.PeerHash)
+	case *EVPNPrefix:
+		m.TableName = p.peerTableName(m.PeerHash)
+	case *SRPolicy:
+		m.TableName = p.peerTableName(m.PeerHash)
+	case *Flowspec:
+		m.TableName = p.peerTableName(m.PeerHash)
+	case *LSNode:
+		m.TableName = p.peerTableName(m.PeerHash)
+	case *LSLink:
+		m.TableName = p.peerTableName(m.PeerHash)
+	case *LSPrefix:
+		m.TableName = p.peerTableName(m.PeerHash)
+	case *LSSRv6SID:
+		m.TableName = p.peerTableName(m.PeerHash)
+	case *RTConstraint:
+		m.TableName = p.peerTableName(m.PeerHash)
+	case *MUPRoute:
+		m.TableName = p.peerTableName(m.PeerHash)
+	case *BGPEPE:
+		m.TableName = p.peerTableName(m.PeerHash)
+	case *Stats:
+		m.TableName = p.peerTableName(m.PeerHash)
+	}
+}
@@ -0,0 +1,28 @@
+package message
+
+import (
+	"testing"
+)
+
+func TestSetTableName(t *testing.T) {
+	p := &producer{
+		tableName: make(map[string]string),
+	}
+	p.setPeerTableName("peer1", "vrf-red")
+	m1 := &L3VPNPrefix{PeerHash: "peer1"}
+	p.setTableName(m1)
+	if m1.TableName != "vrf-red" {
+		t.Errorf("expected table name vrf-red of peer1, got %s", m1.TableName)
+	}
+	m2 := &UnicastPrefix{PeerHash: "peer2"}
+	p.setTableName(m2)
+	if m2.TableName != "" {
+		t.Errorf("expected no table name of peer2, got %s", m2.TableName)
+	}
+	p.setPeerTableName("peer1", "")
+	m3 := &Stats{PeerHash: "peer1"}
+	p.setTableName(m3)
+	if m3.TableName != "" {
+		t.Errorf("expected no table name of peer1 after peer down, got %s", m3.TableName)
+	}
+}
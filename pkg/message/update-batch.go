@@ -0,0 +1,42 @@
+package message
+
+import (
+	"github.com/golang/glog"
+	"github.com/sbezverk/gobmp/pkg/pub"
+)
+
+// updateBatch collects messages produced from prefixes of a single BGP Update per message type, messages
+// are published together when the update is processed, publishers combining messages of the update receive
+// them at once, other publishers receive messages one by one in the order they were produced.
+type updateBatch struct {
+	// raw is json marshaled RawUpdate attached to messages of the update, nil when raw payload is not attached
+	raw    []byte
+	types  []int
+	hashes map[int][]byte
+	msgs   map[int][][]byte
+}
+
+func (b *updateBatch) add(msgType int, hash []byte, msg []byte) {
+	if _, ok := b.msgs[msgType]; !ok {
+		b.types = append(b.types, msgType)
+		// Key of the combined message is the key of the first message of the type
+		b.hashes[msgType] = hash
+	}
+	b.msgs[msgType] = append(b.msgs[msgType], msg)
+}
+
+func newUpdateBatch() *updateBatch {
+	return &updateBatch{
+		types:  make([]int, 0),
+		hashes: make(map[int][]byte),
+		msgs:   make(map[int][][]byte),
+	}
+}
+
+func (p *producer) publishBatch(b *updateBatch) {
+	for _, t := range b.types {
+		if err := pub.PublishUpdate(p.publisher, t, b.hashes[t], b.msgs[t]); err != nil {
+			glog.Errorf("failed to push %d messages of type %d to kafka with error: %+v", len(b.msgs[t]), t, err)
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package message
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/sbezverk/gobmp/pkg/bmp"
+	"github.com/sbezverk/gobmp/pkg/fixture"
+	"github.com/sbezverk/gobmp/pkg/parser"
+	"github.com/sbezverk/gobmp/pkg/pub"
+)
+
+// typedCollector is a Publisher storing published messages per message type
+type typedCollector struct {
+	msgs map[int][]json.RawMessage
+}
+
+func (c *typedCollector) PublishMessage(msgType int, msgHash []byte, msg []byte) error {
+	c.msgs[msgType] = append(c.msgs[msgType], json.RawMessage(msg))
+	return nil
+}
+
+func (c *typedCollector) Stop() {}
+
+func TestUpdateBatch(t *testing.T) {
+	fixtures, err := fixture.Load("testdata")
+	if err != nil {
+		t.Fatalf("failed to load fixtures with error: %+v", err)
+	}
+	var data []byte
+	for _, f := range fixtures {
+		if f.Name == "unicast-v4" {
+			data = f.Data
+		}
+	}
+	c := &typedCollector{msgs: make(map[int][]json.RawMessage)}
+	p := NewProducer(pub.NewCombiner(map[int]bool{bmp.UnicastPrefixV4Msg: true}, c), true).(*producer)
+	for _, msg := range parser.Parse(data) {
+		p.producingWorker(msg)
+	}
+	// The first update advertises 2 prefixes, the second one withdraws a prefix
+	expect := [][]string{{"10.0.130.0", "192.0.2.0"}, {"10.0.130.0"}}
+	if len(c.msgs[bmp.UnicastPrefixV4Msg]) != len(expect) {
+		t.Fatalf("expected %d combined messages, got %d", len(expect), len(c.msgs[bmp.UnicastPrefixV4Msg]))
+	}
+	for i, msg := range c.msgs[bmp.UnicastPrefixV4Msg] {
+		var prefixes []*UnicastPrefix
+		if err := json.Unmarshal(msg, &prefixes); err != nil {
+			t.Fatalf("failed to unmarshal combined message %s with error: %+v", string(msg), err)
+		}
+		got := make([]string, 0, len(prefixes))
+		for _, prfx := range prefixes {
+			got = append(got, prfx.Prefix)
+		}
+		if !reflect.DeepEqual(got, expect[i]) {
+			t.Errorf("expected prefixes %v in combined message, got %v", expect[i], got)
+		}
+	}
+	// Peer messages are not produced from BGP Updates, they are not combined
+	if len(c.msgs[bmp.PeerStateChangeMsg]) != 1 || c.msgs[bmp.PeerStateChangeMsg][0][0] != '{' {
+		t.Errorf("expected a single peer message, got %v", c.msgs[bmp.PeerStateChangeMsg])
+	}
+}
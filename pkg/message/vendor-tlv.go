@@ -0,0 +1,25 @@
+package message
+
+import (
+	"github.com/golang/glog"
+	"github.com/sbezverk/gobmp/pkg/bmp"
+)
+
+// enterpriseTLVs returns enterprise specific TLVs applying to NLRI with index, NLRIs are indexed starting with 1,
+// carried in BMP v4 Route Monitoring message.
+func enterpriseTLVs(rm *bmp.RouteMonitor, index int) []*bmp.VendorTLV {
+	if rm == nil {
+		return nil
+	}
+	var tlvs []*bmp.VendorTLV
+	for _, tlv := range rm.EnterpriseTLVs(uint16(index)) {
+		t, err := bmp.DecodeEnterpriseTLV(bmp.RouteMonitorMsg, tlv)
+		if err != nil {
+			glog.Errorf("failed to decode enterprise specific tlv with error: %+v", err)
+			continue
+		}
+		tlvs = append(tlvs, t)
+	}
+
+	return tlvs
+}
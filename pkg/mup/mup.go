@@ -0,0 +1,231 @@
+package mup
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	"github.com/golang/glog"
+	"github.com/sbezverk/gobmp/pkg/base"
+	"github.com/sbezverk/tools"
+)
+
+// ArchType3GPP5G defines BGP-MUP Architecture Type of 3GPP 5G mobile user plane
+const ArchType3GPP5G = 1
+
+// BGP-MUP Route Types
+// https://datatracker.ietf.org/doc/html/draft-mpmz-bess-mup-safi#section-3.1
+const (
+	// InterworkSegmentDiscovery defines Interwork Segment Discovery route type
+	InterworkSegmentDiscovery = 1
+	// DirectSegmentDiscovery defines Direct Segment Discovery route type
+	DirectSegmentDiscovery = 2
+	// Type1SessionTransformed defines Type 1 Session Transformed route type
+	Type1SessionTransformed = 3
+	// Type2SessionTransformed defines Type 2 Session Transformed route type
+	Type2SessionTransformed = 4
+)
+
+// NLRI defines a collection of BGP-MUP NLRI objects
+type NLRI struct {
+	Route []*Route
+}
+
+// Route defines a single BGP-MUP NLRI object, route type specific fields are set depending on the route type,
+// routes of unknown architecture or route type carry only their Value.
+type Route struct {
+	PathID           uint32
+	ArchitectureType uint8
+	RouteType        uint16
+	Length           uint8
+	RD               *base.RD
+	// PrefixLength and Prefix are set for Interwork Segment Discovery and Type 1 Session Transformed routes
+	PrefixLength uint8
+	Prefix       []byte
+	// Address is set for Direct Segment Discovery routes
+	Address []byte
+	// TEID is set for Type 1 and Type 2 Session Transformed routes, TEID of Type 2 route may be shorter than
+	// 32 bits, it is aligned to the most significant bits
+	TEID uint32
+	// QFI is set for Type 1 Session Transformed routes
+	QFI uint8
+	// EndpointAddress is set for Type 1 and Type 2 Session Transformed routes
+	EndpointAddress []byte
+	// SourceAddress is set for Type 1 Session Transformed routes when the route carries it
+	SourceAddress []byte
+	Value         []byte
+}
+
+// GetRD returns a string representation of Route Distinguisher of the route
+func (r *Route) GetRD() string {
+	if r.RD == nil {
+		return ""
+	}
+
+	return r.RD.String()
+}
+
+// UnmarshalMUPNLRI builds BGP-MUP NLRI object from the slice of bytes, ipv6 is true when AFI of NLRI is 2
+func UnmarshalMUPNLRI(b []byte, ipv6 bool, pathID bool) (*NLRI, error) {
+	if glog.V(6) {
+		glog.Infof("BGP-MUP NLRI Raw: %s path id flag: %t", tools.MessageHex(b), pathID)
+	}
+	if len(b) == 0 {
+		return nil, fmt.Errorf("NLRI length is 0")
+	}
+	n := &NLRI{
+		Route: make([]*Route, 0),
+	}
+	for p := 0; p < len(b); {
+		r := &Route{}
+		if pathID {
+			if p+4 > len(b) {
+				return nil, fmt.Errorf("not enough bytes to reconstruct mup nlri")
+			}
+			r.PathID = binary.BigEndian.Uint32(b[p : p+4])
+			p += 4
+		}
+		if p+4 > len(b) {
+			return nil, fmt.Errorf("not enough bytes to reconstruct mup nlri")
+		}
+		r.ArchitectureType = b[p]
+		r.RouteType = binary.BigEndian.Uint16(b[p+1 : p+3])
+		r.Length = b[p+3]
+		p += 4
+		if p+int(r.Length) > len(b) {
+			return nil, fmt.Errorf("not enough bytes to reconstruct mup nlri of route type %d", r.RouteType)
+		}
+		r.Value = make([]byte, r.Length)
+		copy(r.Value, b[p:p+int(r.Length)])
+		p += int(r.Length)
+		if r.ArchitectureType == ArchType3GPP5G {
+			if err := r.unmarshalRouteType(ipv6); err != nil {
+				return nil, err
+			}
+		}
+		n.Route = append(n.Route, r)
+	}
+
+	return n, nil
+}
+
+func addressLength(ipv6 bool) int {
+	if ipv6 {
+		return 16
+	}
+
+	return 4
+}
+
+// prefix returns the prefix of length l bits at the beginning of b
+func prefix(b []byte, l uint8, ipv6 bool) ([]byte, error) {
+	if int(l) > addressLength(ipv6)*8 {
+		return nil, fmt.Errorf("invalid prefix length %d", l)
+	}
+	bl := (int(l) + 7) / 8
+	if bl > len(b) {
+		return nil, fmt.Errorf("not enough bytes to reconstruct prefix of length %d", l)
+	}
+	pfx := make([]byte, addressLength(ipv6))
+	copy(pfx, b[:bl])
+
+	return pfx, nil
+}
+
+// address returns the address of length l bits at the beginning of b, the length must be 32 or 128 bits
+func address(b []byte, l uint8) ([]byte, error) {
+	if l != 32 && l != 128 {
+		return nil, fmt.Errorf("invalid address length %d", l)
+	}
+	if int(l)/8 > len(b) {
+		return nil, fmt.Errorf("not enough bytes to reconstruct address of length %d", l)
+	}
+	addr := make([]byte, l/8)
+	copy(addr, b)
+
+	return addr, nil
+}
+
+func (r *Route) unmarshalRouteType(ipv6 bool) error {
+	b := r.Value
+	if r.RouteType < InterworkSegmentDiscovery || r.RouteType > Type2SessionTransformed {
+		return nil
+	}
+	if len(b) < 8 {
+		return fmt.Errorf("not enough bytes to reconstruct rd of mup route type %d", r.RouteType)
+	}
+	var err error
+	if r.RD, err = base.MakeRD(b[:8]); err != nil {
+		return err
+	}
+	p := 8
+	switch r.RouteType {
+	case InterworkSegmentDiscovery:
+		if p+1 > len(b) {
+			return fmt.Errorf("not enough bytes to reconstruct interwork segment discovery route")
+		}
+		r.PrefixLength = b[p]
+		p++
+		if r.Prefix, err = prefix(b[p:], r.PrefixLength, ipv6); err != nil {
+			return err
+		}
+	case DirectSegmentDiscovery:
+		l := len(b) - p
+		if l != 4 && l != 16 {
+			return fmt.Errorf("invalid address length %d of direct segment discovery route", l)
+		}
+		r.Address = make([]byte, l)
+		copy(r.Address, b[p:])
+	case Type1SessionTransformed:
+		if p+1 > len(b) {
+			return fmt.Errorf("not enough bytes to reconstruct type 1 session transformed route")
+		}
+		r.PrefixLength = b[p]
+		p++
+		if r.Prefix, err = prefix(b[p:], r.PrefixLength, ipv6); err != nil {
+			return err
+		}
+		p += (int(r.PrefixLength) + 7) / 8
+		// TEID, QFI and Endpoint Address Length
+		if p+6 > len(b) {
+			return fmt.Errorf("not enough bytes to reconstruct type 1 session transformed route")
+		}
+		r.TEID = binary.BigEndian.Uint32(b[p : p+4])
+		r.QFI = b[p+4]
+		l := b[p+5]
+		p += 6
+		if r.EndpointAddress, err = address(b[p:], l); err != nil {
+			return err
+		}
+		p += int(l) / 8
+		// Source Address is optional
+		if p < len(b) {
+			l := b[p]
+			p++
+			if r.SourceAddress, err = address(b[p:], l); err != nil {
+				return err
+			}
+		}
+	case Type2SessionTransformed:
+		if p+1 > len(b) {
+			return fmt.Errorf("not enough bytes to reconstruct type 2 session transformed route")
+		}
+		// Endpoint Length includes the length of the address and the length of TEID
+		l := int(b[p])
+		p++
+		al := addressLength(ipv6) * 8
+		if l < al || l > al+32 {
+			return fmt.Errorf("invalid endpoint length %d of type 2 session transformed route", l)
+		}
+		if p+(l+7)/8 > len(b) {
+			return fmt.Errorf("not enough bytes to reconstruct type 2 session transformed route")
+		}
+		r.EndpointAddress = make([]byte, al/8)
+		copy(r.EndpointAddress, b[p:p+al/8])
+		p += al / 8
+		teid := make([]byte, 4)
+		copy(teid, b[p:p+(l-al+7)/8])
+		r.TEID = binary.BigEndian.Uint32(teid)
+	}
+
+	return nil
+}
@@ -0,0 +1,130 @@
+package mup
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/go-test/deep"
+	"github.com/sbezverk/gobmp/pkg/base"
+)
+
+func TestUnmarshalMUPNLRI(t *testing.T) {
+	rd := []byte{0x00, 0x00, 0xfd, 0xe8, 0x00, 0x00, 0x00, 0x01}
+	tests := []struct {
+		name   string
+		input  []byte
+		ipv6   bool
+		pathID bool
+		expect *NLRI
+		fail   bool
+	}{
+		{
+			name:  "interwork segment discovery",
+			input: append(append([]byte{0x01, 0x00, 0x01, 0x0c}, rd...), 0x18, 0x0a, 0x00, 0x01),
+			expect: &NLRI{
+				Route: []*Route{
+					{
+						ArchitectureType: 1, RouteType: 1, Length: 12,
+						RD:           &base.RD{Type: 0, Value: []byte{0xfd, 0xe8, 0x00, 0x00, 0x00, 0x01}},
+						PrefixLength: 24, Prefix: []byte{0x0a, 0x00, 0x01, 0x00},
+						Value: append(append([]byte{}, rd...), 0x18, 0x0a, 0x00, 0x01),
+					},
+				},
+			},
+		},
+		{
+			name:   "direct segment discovery with path id",
+			input:  append(append([]byte{0x00, 0x00, 0x00, 0x07, 0x01, 0x00, 0x02, 0x0c}, rd...), 0xc0, 0x00, 0x02, 0x01),
+			pathID: true,
+			expect: &NLRI{
+				Route: []*Route{
+					{
+						PathID: 7, ArchitectureType: 1, RouteType: 2, Length: 12,
+						RD:      &base.RD{Type: 0, Value: []byte{0xfd, 0xe8, 0x00, 0x00, 0x00, 0x01}},
+						Address: []byte{0xc0, 0x00, 0x02, 0x01},
+						Value:   append(append([]byte{}, rd...), 0xc0, 0x00, 0x02, 0x01),
+					},
+				},
+			},
+		},
+		{
+			name: "type 1 session transformed with source address",
+			input: append(append([]byte{0x01, 0x00, 0x03, 0x1c}, rd...),
+				0x20, 0x0a, 0x00, 0x00, 0x01, 0x00, 0x00, 0x30, 0x39, 0x09, 0x20, 0xc0, 0x00, 0x02, 0x01, 0x20, 0xc0, 0x00, 0x02, 0x02),
+			expect: &NLRI{
+				Route: []*Route{
+					{
+						ArchitectureType: 1, RouteType: 3, Length: 28,
+						RD:           &base.RD{Type: 0, Value: []byte{0xfd, 0xe8, 0x00, 0x00, 0x00, 0x01}},
+						PrefixLength: 32, Prefix: []byte{0x0a, 0x00, 0x00, 0x01},
+						TEID: 12345, QFI: 9,
+						EndpointAddress: []byte{0xc0, 0x00, 0x02, 0x01},
+						SourceAddress:   []byte{0xc0, 0x00, 0x02, 0x02},
+						Value: append(append([]byte{}, rd...),
+							0x20, 0x0a, 0x00, 0x00, 0x01, 0x00, 0x00, 0x30, 0x39, 0x09, 0x20, 0xc0, 0x00, 0x02, 0x01, 0x20, 0xc0, 0x00, 0x02, 0x02),
+					},
+				},
+			},
+		},
+		{
+			name: "ipv6 type 2 session transformed with 16 bits teid",
+			input: append(append([]byte{0x01, 0x00, 0x04, 0x1b}, rd...),
+				0x90, 0x20, 0x01, 0x0d, 0xb8, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0x01, 0x30, 0x39),
+			ipv6: true,
+			expect: &NLRI{
+				Route: []*Route{
+					{
+						ArchitectureType: 1, RouteType: 4, Length: 27,
+						RD:              &base.RD{Type: 0, Value: []byte{0xfd, 0xe8, 0x00, 0x00, 0x00, 0x01}},
+						EndpointAddress: []byte{0x20, 0x01, 0x0d, 0xb8, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0x01},
+						TEID:            0x30390000,
+						Value: append(append([]byte{}, rd...),
+							0x90, 0x20, 0x01, 0x0d, 0xb8, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0x01, 0x30, 0x39),
+					},
+				},
+			},
+		},
+		{
+			name:  "unknown architecture type",
+			input: []byte{0x02, 0x00, 0x01, 0x02, 0xaa, 0xbb},
+			expect: &NLRI{
+				Route: []*Route{
+					{ArchitectureType: 2, RouteType: 1, Length: 2, Value: []byte{0xaa, 0xbb}},
+				},
+			},
+		},
+		{
+			name:  "truncated route",
+			input: append([]byte{0x01, 0x00, 0x01, 0x0c}, rd...),
+			fail:  true,
+		},
+		{
+			name:  "invalid direct segment discovery address",
+			input: append(append([]byte{0x01, 0x00, 0x02, 0x0b}, rd...), 0xc0, 0x00, 0x02),
+			fail:  true,
+		},
+		{
+			name:  "invalid type 2 session transformed endpoint length",
+			input: append(append([]byte{0x01, 0x00, 0x04, 0x0e}, rd...), 0x48, 0xc0, 0x00, 0x02, 0x01, 0x00),
+			fail:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := UnmarshalMUPNLRI(tt.input, tt.ipv6, tt.pathID)
+			if err != nil && !tt.fail {
+				t.Fatalf("expected to succeed but failed with error: %+v", err)
+			}
+			if err == nil && tt.fail {
+				t.Fatalf("expected to fail but succeeded")
+			}
+			if err != nil {
+				return
+			}
+			if !reflect.DeepEqual(tt.expect, got) {
+				t.Logf("Differences: %+v", deep.Equal(tt.expect, got))
+				t.Fatalf("expected nlri %+v does not match actual nlri %+v", tt.expect, got)
+			}
+		})
+	}
+}
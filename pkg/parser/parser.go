@@ -1,17 +1,30 @@
 package parser
 
 import (
+	"time"
+
 	"github.com/golang/glog"
 	"github.com/sbezverk/gobmp/pkg/bmp"
+	"github.com/sbezverk/gobmp/pkg/tracing"
 	"github.com/sbezverk/tools"
 )
 
-// Parser dispatches workers upon request received from the channel
-func Parser(queue chan []byte, producerQueue chan bmp.Message, stop chan struct{}) {
+// Observer is called with the type of every parsed BMP message and the time it took to parse it
+type Observer func(msgType uint8, d time.Duration)
+
+// Frame defines BMP messages received from BMP session, Span is optional span tracing the messages
+type Frame struct {
+	Msg  []byte
+	Span *tracing.Span
+}
+
+// Parser parses frames received from the channel one by one, so messages are passed to the producer in the order
+// they were received, observe is optional Observer of parse latency of messages.
+func Parser(queue chan Frame, producerQueue chan bmp.Message, stop chan struct{}, observe Observer) {
 	for {
 		select {
 		case msg := <-queue:
-			go parsingWorker(msg, producerQueue)
+			parsingWorker(msg, producerQueue, stop, observe)
 		case <-stop:
 			glog.Infof("received interrupt, stopping.")
 			return
@@ -19,28 +32,78 @@ func Parser(queue chan []byte, producerQueue chan bmp.Message, stop chan struct{
 	}
 }
 
-func parsingWorker(b []byte, producerQueue chan bmp.Message) {
+func parsingWorker(f Frame, producerQueue chan bmp.Message, stop chan struct{}, observe Observer) {
+	msgs := ParseTraced(f.Msg, observe, f.Span)
+	if producerQueue == nil {
+		f.Span.Finish()
+		return
+	}
+	for _, msg := range msgs {
+		select {
+		case producerQueue <- msg:
+		case <-stop:
+			// The producer of the closed session does not receive messages anymore
+			return
+		}
+	}
+}
+
+// Parse returns BMP messages found in the slice in the order they were received, Initiation, Termination
+// and Route Mirroring messages are not returned, parsing stops at the first message which cannot be recovered.
+func Parse(b []byte) []bmp.Message {
+	return ParseObserved(b, nil)
+}
+
+// ParseObserved returns BMP messages found in the slice as Parse does, observe is optional Observer called
+// for every successfully parsed message.
+func ParseObserved(b []byte, observe Observer) []bmp.Message {
+	return ParseTraced(b, observe, nil)
+}
+
+// ParseTraced returns BMP messages found in the slice as ParseObserved does, span is optional span of the received
+// messages, parsing is recorded as its child and returned messages carry the span to the producer which finishes it,
+// the span is finished when no messages are returned.
+func ParseTraced(b []byte, observe Observer, span *tracing.Span) []bmp.Message {
+	msgs := make([]bmp.Message, 0)
+	defer func() {
+		if len(msgs) == 0 {
+			span.Finish()
+		}
+	}()
+	parse := span.Child("bmp.parse")
+	defer parse.Finish()
+	// received is collector's receive timestamp, it is shared by all BMP messages found in the slice
+	received := time.Now()
 	perPerHeaderLen := 0
 	var bmpMsg bmp.Message
 	// Loop through all found Common Headers in the slice and process them
 	for p := 0; p < len(b); {
+		start := time.Now()
 		bmpMsg.PeerHeader = nil
 		bmpMsg.Payload = nil
+		bmpMsg.Span = span
+		bmpMsg.RawHeaders = nil
 		// Recovering common header first
 		ch, err := bmp.UnmarshalCommonHeader(b[p : p+bmp.CommonHeaderLength])
 		if err != nil {
 			glog.Errorf("fail to recover BMP message Common Header with error: %+v", err)
-			return
+			return msgs
 		}
 		p += bmp.CommonHeaderLength
 		switch ch.MessageType {
 		case bmp.RouteMonitorMsg:
 			if bmpMsg.PeerHeader, err = bmp.UnmarshalPerPeerHeader(b[p : p+bmp.PerPeerHeaderLength]); err != nil {
 				glog.Errorf("fail to recover BMP Per Peer Header with error: %+v", err)
-				return
+				return msgs
 			}
 			perPerHeaderLen = bmp.PerPeerHeaderLength
-			rm, err := bmp.UnmarshalBMPRouteMonitorMessage(b[p+perPerHeaderLen : p+int(ch.MessageLength)-bmp.CommonHeaderLength])
+			bmpMsg.RawHeaders = b[p-bmp.CommonHeaderLength : p+perPerHeaderLen]
+			var rm *bmp.RouteMonitor
+			if ch.Version == 4 {
+				rm, err = bmp.UnmarshalBMPRouteMonitorV4Message(b[p+perPerHeaderLen : p+int(ch.MessageLength)-bmp.CommonHeaderLength])
+			} else {
+				rm, err = bmp.UnmarshalBMPRouteMonitorMessage(b[p+perPerHeaderLen : p+int(ch.MessageLength)-bmp.CommonHeaderLength])
+			}
 			if err != nil {
 				glog.Errorf("fail to recover BMP Route Monitoring with error: %+v", err)
 				if glog.V(5) {
@@ -48,47 +111,43 @@ func parsingWorker(b []byte, producerQueue chan bmp.Message) {
 					glog.Infof("per peer header content: %s", tools.MessageHex(b[p:p+bmp.PerPeerHeaderLength]))
 					glog.Infof("message content: %s", tools.MessageHex(b[p+perPerHeaderLen:p+int(ch.MessageLength)-bmp.CommonHeaderLength]))
 				}
-				return
+				return msgs
 			}
 			bmpMsg.Payload = rm
-			p += perPerHeaderLen
 		case bmp.StatsReportMsg:
 			if bmpMsg.PeerHeader, err = bmp.UnmarshalPerPeerHeader(b[p : p+int(ch.MessageLength-bmp.CommonHeaderLength)]); err != nil {
 				glog.Errorf("fail to recover BMP Per Peer Header with error: %+v", err)
-				return
+				return msgs
 			}
 			perPerHeaderLen = bmp.PerPeerHeaderLength
-			if bmpMsg.Payload, err = bmp.UnmarshalBMPStatsReportMessage(b[p+perPerHeaderLen:]); err != nil {
+			if bmpMsg.Payload, err = bmp.UnmarshalBMPStatsReportMessage(b[p+perPerHeaderLen : p+int(ch.MessageLength)-bmp.CommonHeaderLength]); err != nil {
 				glog.Errorf("fail to recover BMP Stats Reports message with error: %+v", err)
-				return
+				return msgs
 			}
-			p += perPerHeaderLen
 		case bmp.PeerDownMsg:
 			if bmpMsg.PeerHeader, err = bmp.UnmarshalPerPeerHeader(b[p : p+int(ch.MessageLength-bmp.CommonHeaderLength)]); err != nil {
 				glog.Errorf("fail to recover BMP Per Peer Header with error: %+v", err)
-				return
+				return msgs
 			}
 			perPerHeaderLen = bmp.PerPeerHeaderLength
 			if bmpMsg.Payload, err = bmp.UnmarshalPeerDownMessage(b[p+perPerHeaderLen : p+int(ch.MessageLength)-bmp.CommonHeaderLength]); err != nil {
 				glog.Errorf("fail to recover BMP Peer Down message with error: %+v", err)
-				return
+				return msgs
 			}
-			p += perPerHeaderLen
 		case bmp.PeerUpMsg:
 			if bmpMsg.PeerHeader, err = bmp.UnmarshalPerPeerHeader(b[p : p+int(ch.MessageLength-bmp.CommonHeaderLength)]); err != nil {
 				glog.Errorf("fail to recover BMP Per Peer Header with error: %+v", err)
-				return
+				return msgs
 			}
 			perPerHeaderLen = bmp.PerPeerHeaderLength
 			if bmpMsg.Payload, err = bmp.UnmarshalPeerUpMessage(b[p+perPerHeaderLen:p+int(ch.MessageLength)-bmp.CommonHeaderLength], bmpMsg.PeerHeader.IsRemotePeerIPv6()); err != nil {
 				glog.Errorf("fail to recover BMP Peer Up message with error: %+v", err)
-				return
+				return msgs
 			}
-			p += perPerHeaderLen
 		case bmp.InitiationMsg:
 			if _, err := bmp.UnmarshalInitiationMessage(b[p : p+(int(ch.MessageLength)-bmp.CommonHeaderLength)]); err != nil {
 				glog.Errorf("fail to recover BMP Initiation message with error: %+v", err)
-				return
+				return msgs
 			}
 		case bmp.TerminationMsg:
 			glog.V(5).Infof("Termination message")
@@ -101,10 +160,18 @@ func parsingWorker(b []byte, producerQueue chan bmp.Message) {
 				glog.Infof("Content:%s", tools.MessageHex(b))
 			}
 		}
+		if bmpMsg.PeerHeader != nil {
+			bmpMsg.PeerHeader.SetCollectorTimestamp(received)
+		}
+		if observe != nil {
+			observe(ch.MessageType, time.Since(start))
+		}
 		perPerHeaderLen = 0
 		p += (int(ch.MessageLength) - bmp.CommonHeaderLength)
-		if producerQueue != nil && bmpMsg.Payload != nil {
-			producerQueue <- bmpMsg
+		if bmpMsg.Payload != nil {
+			msgs = append(msgs, bmpMsg)
 		}
 	}
+
+	return msgs
 }
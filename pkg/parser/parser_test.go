@@ -1,6 +1,15 @@
 package parser
 
-import "testing"
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/sbezverk/gobmp/pkg/bmp"
+	"github.com/sbezverk/gobmp/pkg/tracing"
+)
 
 func TestParsingWorker(t *testing.T) {
 	tests := []struct {
@@ -14,7 +23,105 @@ func TestParsingWorker(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			parsingWorker(tt.input, nil)
+			parsingWorker(Frame{Msg: tt.input}, nil, nil, nil)
 		})
 	}
 }
+
+func TestParse(t *testing.T) {
+	// Initiation, Peer Up and Peer Down messages of the same peer
+	initiation := []byte{3, 0, 0, 0, 10, 4, 0, 2, 0, 0}
+	pph := make([]byte, bmp.PerPeerHeaderLength)
+	copy(pph[22:], []byte{192, 0, 2, 2, 0, 0, 0xfd, 0xe9, 192, 0, 2, 2})
+	peerDown := append(append([]byte{3, 0, 0, 0, 51, 2}, pph...), 2, 0, 2)
+	input := append(append(append([]byte{}, initiation...), peerDown...), peerDown...)
+	msgs := Parse(input)
+	if len(msgs) != 2 {
+		t.Fatalf("expected 2 messages, got %d", len(msgs))
+	}
+	for _, msg := range msgs {
+		if msg.PeerHeader == nil || msg.PeerHeader.GetPeerAddrString() != "192.0.2.2" {
+			t.Fatalf("message %+v does not carry per peer header of 192.0.2.2", msg)
+		}
+	}
+}
+
+func TestParseObserved(t *testing.T) {
+	initiation := []byte{3, 0, 0, 0, 10, 4, 0, 2, 0, 0}
+	pph := make([]byte, bmp.PerPeerHeaderLength)
+	copy(pph[22:], []byte{192, 0, 2, 2, 0, 0, 0xfd, 0xe9, 192, 0, 2, 2})
+	peerDown := append(append([]byte{3, 0, 0, 0, 51, 2}, pph...), 2, 0, 2)
+	types := make([]uint8, 0)
+	ParseObserved(append(append([]byte{}, initiation...), peerDown...), func(msgType uint8, d time.Duration) {
+		types = append(types, msgType)
+	})
+	if !reflect.DeepEqual(types, []uint8{bmp.InitiationMsg, bmp.PeerDownMsg}) {
+		t.Errorf("expected observed types of initiation and peer down messages, got %v", types)
+	}
+}
+
+func TestParseTraced(t *testing.T) {
+	collector := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer collector.Close()
+	tr, err := tracing.NewTracer(&tracing.ExporterConfig{Endpoint: collector.URL}, 1)
+	if err != nil {
+		t.Fatalf("failed to create tracer with error: %+v", err)
+	}
+	defer tr.Stop()
+	pph := make([]byte, bmp.PerPeerHeaderLength)
+	copy(pph[22:], []byte{192, 0, 2, 2, 0, 0, 0xfd, 0xe9, 192, 0, 2, 2})
+	peerDown := append(append([]byte{3, 0, 0, 0, 51, 2}, pph...), 2, 0, 2)
+	span := tr.Start("bmp.message")
+	msgs := ParseTraced(peerDown, nil, span)
+	if len(msgs) != 1 || msgs[0].Span != span {
+		t.Fatalf("expected peer down message carrying the span of the received message, got %+v", msgs)
+	}
+	if !span.End.IsZero() {
+		t.Errorf("span of the message is supposed to be finished by the producer")
+	}
+	// Initiation message is not passed to the producer, its span is finished by the parser
+	span = tr.Start("bmp.message")
+	if msgs := ParseTraced([]byte{3, 0, 0, 0, 10, 4, 0, 2, 0, 0}, nil, span); len(msgs) != 0 {
+		t.Fatalf("expected no messages, got %+v", msgs)
+	}
+	if span.End.IsZero() {
+		t.Errorf("span of the initiation message is supposed to be finished by the parser")
+	}
+}
+
+func TestParseConsecutiveMessages(t *testing.T) {
+	// Route Monitoring, Stats Report and Peer Down messages of the same peer in a single buffer, each one
+	// must be parsed from the offset following the previous message and only from its own bytes
+	pph := make([]byte, bmp.PerPeerHeaderLength)
+	copy(pph[22:], []byte{192, 0, 2, 2, 0, 0, 0xfd, 0xe9, 192, 0, 2, 2})
+	update := []byte{
+		0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+		0, 23, 2, 0, 0, 0, 0,
+	}
+	routeMonitor := append(append([]byte{3, 0, 0, 0, byte(6 + len(pph) + len(update)), 0}, pph...), update...)
+	stats := append(append([]byte{3, 0, 0, 0, 60, 1}, pph...), 0, 0, 0, 1, 0, 2, 0, 4, 0, 0, 0, 7)
+	peerDown := append(append([]byte{3, 0, 0, 0, 51, 2}, pph...), 2, 0, 2)
+	input := append(append(append([]byte{}, routeMonitor...), stats...), peerDown...)
+	msgs := Parse(input)
+	if len(msgs) != 3 {
+		t.Fatalf("expected 3 messages, got %d", len(msgs))
+	}
+	if _, ok := msgs[0].Payload.(*bmp.RouteMonitor); !ok {
+		t.Errorf("expected route monitoring message, got %T", msgs[0].Payload)
+	}
+	sr, ok := msgs[1].Payload.(*bmp.StatsReport)
+	if !ok {
+		t.Fatalf("expected stats report message, got %T", msgs[1].Payload)
+	}
+	if len(sr.StatsTLV) != 1 {
+		t.Errorf("expected 1 stats tlv, got %d", len(sr.StatsTLV))
+	}
+	if _, ok := msgs[2].Payload.(*bmp.PeerDownMessage); !ok {
+		t.Errorf("expected peer down message, got %T", msgs[2].Payload)
+	}
+	for _, msg := range msgs {
+		if msg.PeerHeader == nil || msg.PeerHeader.GetPeerAddrString() != "192.0.2.2" {
+			t.Errorf("message %+v does not carry per peer header of 192.0.2.2", msg)
+		}
+	}
+}
@@ -2,6 +2,7 @@ package prefixsid
 
 import (
 	"encoding/binary"
+	"fmt"
 
 	"github.com/golang/glog"
 	"github.com/sbezverk/gobmp/pkg/srv6"
@@ -52,7 +53,13 @@ func UnmarshalBGPAttrPrefixSID(b []byte) (*PSid, error) {
 		OriginatorSRGB: nil,
 	}
 	for p := 0; p < len(b); {
-		// Determin the type, currently only type 1 and 3 are supported
+		if p+3 > len(b) {
+			return nil, fmt.Errorf("not enough bytes to unmarshal prefix sid tlv")
+		}
+		if l := int(binary.BigEndian.Uint16(b[p+1 : p+3])); p+3+l > len(b) {
+			return nil, fmt.Errorf("invalid length %d of prefix sid tlv type %d", l, b[p])
+		}
+		// Determin the type, currently types 1, 3, 5 and 6 are supported
 		switch b[p] {
 		case 1:
 			p++
@@ -60,6 +67,9 @@ func UnmarshalBGPAttrPrefixSID(b []byte) (*PSid, error) {
 			psid.LabelIndex.Type = 1
 			psid.LabelIndex.Length = binary.BigEndian.Uint16(b[p : p+2])
 			p += 2
+			if psid.LabelIndex.Length != 7 {
+				return nil, fmt.Errorf("invalid length %d of label index tlv", psid.LabelIndex.Length)
+			}
 			// Skip reserved byte
 			p++
 			psid.LabelIndex.Flags = binary.BigEndian.Uint16(b[p : p+2])
@@ -72,6 +82,9 @@ func UnmarshalBGPAttrPrefixSID(b []byte) (*PSid, error) {
 			psid.OriginatorSRGB.Type = 1
 			psid.OriginatorSRGB.Length = binary.BigEndian.Uint16(b[p : p+2])
 			p += 2
+			if psid.OriginatorSRGB.Length < 2 || (psid.OriginatorSRGB.Length-2)%6 != 0 {
+				return nil, fmt.Errorf("invalid length %d of originator srgb tlv", psid.OriginatorSRGB.Length)
+			}
 			psid.OriginatorSRGB.Flags = binary.BigEndian.Uint16(b[p : p+2])
 			p += 2
 			// Multiple SRGB are possible, loop through, each SRGB takes 6 bytes. Subtrack 2 (length of Flags)
@@ -79,12 +92,13 @@ func UnmarshalBGPAttrPrefixSID(b []byte) (*PSid, error) {
 			psid.OriginatorSRGB.SRGB = make([]SRGB, 0)
 			for i := 0; i < int(psid.OriginatorSRGB.Length-2)/6; i++ {
 				srgb := SRGB{}
+				// First label and range are 3 bytes each
 				t := make([]byte, 4)
-				copy(t, b[p:p+3])
+				copy(t[1:], b[p:p+3])
 				srgb.First = binary.BigEndian.Uint32(t)
 				p += 3
 				t = make([]byte, 4)
-				copy(t, b[p:p+3])
+				copy(t[1:], b[p:p+3])
 				srgb.Number = binary.BigEndian.Uint32(t)
 				p += 3
 				psid.OriginatorSRGB.SRGB = append(psid.OriginatorSRGB.SRGB, srgb)
@@ -99,6 +113,16 @@ func UnmarshalBGPAttrPrefixSID(b []byte) (*PSid, error) {
 			}
 			psid.SRv6L3Service = l3
 			p += int(l)
+		case 6:
+			p++
+			l := binary.BigEndian.Uint16(b[p : p+2])
+			p += 2
+			l2, err := srv6.UnmarshalSRv6L2Service(b[p : p+int(l)])
+			if err != nil {
+				return nil, err
+			}
+			psid.SRv6L2Service = l2
+			p += int(l)
 		default:
 			// Skip unknown type, length 2 bytes and the value
 			p++
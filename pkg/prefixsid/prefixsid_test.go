@@ -14,6 +14,7 @@ func TestUnmarshalBGPAttrPrefixSID(t *testing.T) {
 		name   string
 		input  []byte
 		expect *PSid
+		fail   bool
 	}{
 		{
 			name:  "mp unicast nlri 1",
@@ -56,13 +57,72 @@ func TestUnmarshalBGPAttrPrefixSID(t *testing.T) {
 				},
 			},
 		},
+		{
+			name:  "prefix sid type 6",
+			input: []byte{0x06, 0x00, 0x22, 0x00, 0x01, 0x00, 0x1e, 0x00, 0x20, 0x01, 0x00, 0x00, 0x00, 0x05, 0x00, 0x03, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x15, 0x00, 0x01, 0x00, 0x06, 0x28, 0x18, 0x10, 0x00, 0x10, 0x40},
+			expect: &PSid{
+				SRv6L2Service: &srv6.L2Service{
+					SubTLVs: map[uint8][]srv6.SvcSubTLV{
+						1: {
+							&srv6.InformationSubTLV{
+								SID:              net.IP([]byte{0x20, 0x01, 0x00, 0x00, 0x00, 0x05, 0x00, 0x03, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00}).To16().String(),
+								Flags:            0,
+								EndpointBehavior: 21,
+								SubSubTLVs: map[uint8][]srv6.SvcSubSubTLV{
+									1: {
+										&srv6.SIDStructureSubSubTLV{
+											LocalBlockLength:    0x28,
+											LocalNodeLength:     0x18,
+											FunctionLength:      0x10,
+											ArgumentLength:      0,
+											TranspositionLength: 0x10,
+											TranspositionOffset: 0x40,
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "label index and originator srgb",
+			input: []byte{
+				0x01, 0x00, 0x07, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x0a,
+				0x03, 0x00, 0x08, 0x00, 0x00, 0x00, 0x3e, 0x80, 0x00, 0x1f, 0x40,
+			},
+			expect: &PSid{
+				LabelIndex: &LabelIndexTLV{
+					Type:       1,
+					Length:     7,
+					LabelIndex: 10,
+				},
+				OriginatorSRGB: &OriginatorSRGBTLV{
+					Type:   1,
+					Length: 8,
+					SRGB:   []SRGB{{First: 16000, Number: 8000}},
+				},
+			},
+		},
+		{
+			name:  "truncated label index",
+			input: []byte{0x01, 0x00, 0x07, 0x00, 0x00, 0x00, 0x00},
+			fail:  true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			got, err := UnmarshalBGPAttrPrefixSID(tt.input)
-			if err != nil {
+			if err != nil && !tt.fail {
 				t.Fatalf("test failed with error: %+v", err)
 			}
+			if err == nil && tt.fail {
+				t.Fatalf("test expected to fail but succeeded")
+			}
+			if err != nil {
+				return
+			}
 			if !reflect.DeepEqual(tt.expect, got) {
 				t.Errorf("Diffs: %+v\n", deep.Equal(tt.expect, got))
 				t.Fatalf("test failed as expected prefix sid %+v does not match the actual %+v", tt.expect, got)
@@ -0,0 +1,62 @@
+package pub
+
+import (
+	"bytes"
+)
+
+// UpdatePublisher is implemented by publishers which publish messages of a single BGP Update together,
+// msgs are json marshaled messages of msgType produced from prefixes of the update.
+type UpdatePublisher interface {
+	PublishUpdate(msgType int, msgHash []byte, msgs [][]byte) error
+}
+
+// PublishUpdate publishes messages of a single BGP Update to publisher, publishers which do not implement
+// UpdatePublisher receive the messages one by one, the first error is returned.
+func PublishUpdate(publisher Publisher, msgType int, msgHash []byte, msgs [][]byte) error {
+	if up, ok := publisher.(UpdatePublisher); ok {
+		return up.PublishUpdate(msgType, msgHash, msgs)
+	}
+	var err error
+	for _, msg := range msgs {
+		if e := publisher.PublishMessage(msgType, msgHash, msg); e != nil && err == nil {
+			err = e
+		}
+	}
+
+	return err
+}
+
+type combiner struct {
+	publisher Publisher
+	types     map[int]bool
+}
+
+func (c *combiner) PublishMessage(msgType int, msgHash []byte, msg []byte) error {
+	return c.publisher.PublishMessage(msgType, msgHash, msg)
+}
+
+func (c *combiner) PublishUpdate(msgType int, msgHash []byte, msgs [][]byte) error {
+	if !c.types[msgType] {
+		return PublishUpdate(c.publisher, msgType, msgHash, msgs)
+	}
+
+	return c.publisher.PublishMessage(msgType, msgHash, Combine(msgs))
+}
+
+func (c *combiner) Stop() {
+	c.publisher.Stop()
+}
+
+// Combine returns json array of messages
+func Combine(msgs [][]byte) []byte {
+	return append(append([]byte{'['}, bytes.Join(msgs, []byte{','})...), ']')
+}
+
+// NewCombiner returns a Publisher publishing messages of types produced from a single BGP Update
+// as a single message carrying json array of the messages, messages of other types are published one by one.
+func NewCombiner(types map[int]bool, publisher Publisher) Publisher {
+	return &combiner{
+		publisher: publisher,
+		types:     types,
+	}
+}
@@ -0,0 +1,57 @@
+package pub
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/sbezverk/gobmp/pkg/bmp"
+)
+
+// collector is a Publisher storing all published messages
+type collector struct {
+	msgs []string
+}
+
+func (c *collector) PublishMessage(msgType int, msgHash []byte, msg []byte) error {
+	c.msgs = append(c.msgs, string(msg))
+	return nil
+}
+
+func (c *collector) Stop() {}
+
+func TestCombiner(t *testing.T) {
+	update := [][]byte{[]byte(`{"prefix":"10.0.0.0"}`), []byte(`{"prefix":"10.1.0.0"}`)}
+	tests := []struct {
+		name   string
+		t      int
+		expect []string
+	}{
+		{
+			name:   "combined unicast prefixes",
+			t:      bmp.UnicastPrefixV4Msg,
+			expect: []string{`[{"prefix":"10.0.0.0"},{"prefix":"10.1.0.0"}]`},
+		},
+		{
+			name:   "split ls prefixes",
+			t:      bmp.LSPrefixMsg,
+			expect: []string{`{"prefix":"10.0.0.0"}`, `{"prefix":"10.1.0.0"}`},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &collector{}
+			// Multi publisher passes the update to the combiner and messages one by one to other publishers
+			other := &collector{}
+			p := NewMulti(NewCombiner(map[int]bool{bmp.UnicastPrefixV4Msg: true}, c), other)
+			if err := PublishUpdate(p, tt.t, []byte("key"), update); err != nil {
+				t.Fatalf("failed to publish update with error: %+v", err)
+			}
+			if !reflect.DeepEqual(c.msgs, tt.expect) {
+				t.Errorf("expected messages %v, got %v", tt.expect, c.msgs)
+			}
+			if len(other.msgs) != len(update) {
+				t.Errorf("expected %d messages published one by one, got %v", len(update), other.msgs)
+			}
+		})
+	}
+}
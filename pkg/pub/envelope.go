@@ -0,0 +1,80 @@
+package pub
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/sbezverk/gobmp/pkg/bmp"
+)
+
+// SchemaVersion is the version of the schema of published messages, it is incremented on changes
+// of message fields which are not backward compatible.
+const SchemaVersion = 1
+
+// Envelope defines the envelope of a published message
+type Envelope struct {
+	SchemaVersion           int             `json:"schema_version"`
+	CollectorID             string          `json:"collector_id,omitempty"`
+	Type                    string          `json:"type"`
+	CollectorTimestamp      string          `json:"collector_timestamp,omitempty"`
+	CollectorTimestampEpoch int64           `json:"collector_timestamp_epoch_us,omitempty"`
+	PublishedTimestamp      string          `json:"published_timestamp"`
+	PublishedTimestampEpoch int64           `json:"published_timestamp_epoch_us"`
+	Message                 json.RawMessage `json:"message"`
+}
+
+// captureTimestamps defines timestamps of the message copied to the envelope
+type captureTimestamps struct {
+	CollectorTimestamp      string `json:"collector_timestamp"`
+	CollectorTimestampEpoch int64  `json:"collector_timestamp_epoch_us"`
+}
+
+type envelope struct {
+	publisher   Publisher
+	collectorID string
+	now         func() time.Time
+}
+
+func (e *envelope) PublishMessage(msgType int, msgHash []byte, msg []byte) error {
+	ts := &captureTimestamps{}
+	// Messages without collector's receive timestamp are published without capture timestamp
+	if len(msg) != 0 && msg[0] == '[' {
+		// Combined messages of a single BGP Update share the receive timestamp
+		var combined []captureTimestamps
+		if err := json.Unmarshal(msg, &combined); err == nil && len(combined) != 0 {
+			ts = &combined[0]
+		}
+	} else {
+		_ = json.Unmarshal(msg, ts)
+	}
+	now := e.now()
+	b, err := json.Marshal(&Envelope{
+		SchemaVersion:           SchemaVersion,
+		CollectorID:             e.collectorID,
+		Type:                    bmp.MsgTypeName(msgType),
+		CollectorTimestamp:      ts.CollectorTimestamp,
+		CollectorTimestampEpoch: ts.CollectorTimestampEpoch,
+		PublishedTimestamp:      now.UTC().Format(time.RFC3339Nano),
+		PublishedTimestampEpoch: now.UnixNano() / int64(time.Microsecond),
+		Message:                 msg,
+	})
+	if err != nil {
+		return err
+	}
+
+	return e.publisher.PublishMessage(msgType, msgHash, b)
+}
+
+func (e *envelope) Stop() {
+	e.publisher.Stop()
+}
+
+// NewEnvelope returns a Publisher wrapping each message in the versioned envelope identifying
+// the collector before the message is published to publisher.
+func NewEnvelope(collectorID string, publisher Publisher) Publisher {
+	return &envelope{
+		publisher:   publisher,
+		collectorID: collectorID,
+		now:         time.Now,
+	}
+}
@@ -0,0 +1,114 @@
+package pub
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/go-test/deep"
+	"github.com/sbezverk/gobmp/pkg/bmp"
+)
+
+// recorder is a Publisher storing the last published message
+type recorder struct {
+	msgType int
+	key     []byte
+	msg     []byte
+}
+
+func (r *recorder) PublishMessage(msgType int, msgHash []byte, msg []byte) error {
+	r.msgType, r.key, r.msg = msgType, msgHash, msg
+	return nil
+}
+
+func (r *recorder) Stop() {}
+
+func TestEnvelope(t *testing.T) {
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name   string
+		t      int
+		msg    string
+		expect *Envelope
+		fail   bool
+	}{
+		{
+			name: "unicast prefix with capture timestamp",
+			t:    bmp.UnicastPrefixV4Msg,
+			msg:  `{"action":"add","prefix":"10.0.0.0","prefix_len":8,"collector_timestamp":"2026-10-16T11:59:59Z","collector_timestamp_epoch_us":1792151999000000}`,
+			expect: &Envelope{
+				SchemaVersion:           SchemaVersion,
+				CollectorID:             "collector-1",
+				Type:                    "unicast_prefix_v4",
+				CollectorTimestamp:      "2026-10-16T11:59:59Z",
+				CollectorTimestampEpoch: 1792151999000000,
+				PublishedTimestamp:      "2026-10-16T12:00:00Z",
+				PublishedTimestampEpoch: now.UnixNano() / int64(time.Microsecond),
+				Message:                 json.RawMessage(`{"action":"add","prefix":"10.0.0.0","prefix_len":8,"collector_timestamp":"2026-10-16T11:59:59Z","collector_timestamp_epoch_us":1792151999000000}`),
+			},
+		},
+		{
+			name: "alert without capture timestamp",
+			t:    bmp.AlertMsg,
+			msg:  `{"router_ip":"192.0.2.1"}`,
+			expect: &Envelope{
+				SchemaVersion:           SchemaVersion,
+				CollectorID:             "collector-1",
+				Type:                    "alert",
+				PublishedTimestamp:      "2026-10-16T12:00:00Z",
+				PublishedTimestampEpoch: now.UnixNano() / int64(time.Microsecond),
+				Message:                 json.RawMessage(`{"router_ip":"192.0.2.1"}`),
+			},
+		},
+		{
+			name: "combined messages of bgp update",
+			t:    bmp.UnicastPrefixV4Msg,
+			msg:  `[{"prefix":"10.0.0.0","collector_timestamp":"2026-10-16T11:59:59Z","collector_timestamp_epoch_us":1792151999000000},{"prefix":"10.1.0.0","collector_timestamp":"2026-10-16T11:59:59Z","collector_timestamp_epoch_us":1792151999000000}]`,
+			expect: &Envelope{
+				SchemaVersion:           SchemaVersion,
+				CollectorID:             "collector-1",
+				Type:                    "unicast_prefix_v4",
+				CollectorTimestamp:      "2026-10-16T11:59:59Z",
+				CollectorTimestampEpoch: 1792151999000000,
+				PublishedTimestamp:      "2026-10-16T12:00:00Z",
+				PublishedTimestampEpoch: now.UnixNano() / int64(time.Microsecond),
+				Message:                 json.RawMessage(`[{"prefix":"10.0.0.0","collector_timestamp":"2026-10-16T11:59:59Z","collector_timestamp_epoch_us":1792151999000000},{"prefix":"10.1.0.0","collector_timestamp":"2026-10-16T11:59:59Z","collector_timestamp_epoch_us":1792151999000000}]`),
+			},
+		},
+		{
+			name: "invalid message",
+			t:    bmp.AlertMsg,
+			msg:  `{"router_ip":`,
+			fail: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &recorder{}
+			e := NewEnvelope("collector-1", r)
+			e.(*envelope).now = func() time.Time { return now }
+			err := e.PublishMessage(tt.t, []byte("key"), []byte(tt.msg))
+			if err != nil && !tt.fail {
+				t.Fatalf("supposed to succeed but failed with error: %+v", err)
+			}
+			if err == nil && tt.fail {
+				t.Fatalf("supposed to fail but succeeded")
+			}
+			if err != nil {
+				return
+			}
+			if r.msgType != tt.t || string(r.key) != "key" {
+				t.Errorf("message type %d or key %s do not match", r.msgType, string(r.key))
+			}
+			env := &Envelope{}
+			if err := json.Unmarshal(r.msg, env); err != nil {
+				t.Fatalf("failed to unmarshal envelope with error: %+v", err)
+			}
+			if !reflect.DeepEqual(env, tt.expect) {
+				t.Logf("Differences: %+v", deep.Equal(env, tt.expect))
+				t.Errorf("envelope does not match expected")
+			}
+		})
+	}
+}
@@ -0,0 +1,142 @@
+package pub
+
+import (
+	"fmt"
+	"path"
+	"strings"
+	"sync"
+
+	"github.com/sbezverk/gobmp/pkg/bmp"
+)
+
+// TypeFilter defines a Publisher publishing only messages of enabled types, enabled types can be changed
+// at runtime, nil types enable all types.
+type TypeFilter interface {
+	Publisher
+	Types() map[int]bool
+	SetTypes(types map[int]bool)
+}
+
+type filter struct {
+	publisher Publisher
+	mtx       sync.RWMutex
+	types     map[int]bool
+}
+
+func (f *filter) enabled(msgType int) bool {
+	f.mtx.RLock()
+	defer f.mtx.RUnlock()
+
+	return f.types == nil || f.types[msgType]
+}
+
+func (f *filter) PublishMessage(msgType int, msgHash []byte, msg []byte) error {
+	if !f.enabled(msgType) {
+		return nil
+	}
+
+	return f.publisher.PublishMessage(msgType, msgHash, msg)
+}
+
+func (f *filter) PublishUpdate(msgType int, msgHash []byte, msgs [][]byte) error {
+	if !f.enabled(msgType) {
+		return nil
+	}
+
+	return PublishUpdate(f.publisher, msgType, msgHash, msgs)
+}
+
+func (f *filter) Types() map[int]bool {
+	f.mtx.RLock()
+	defer f.mtx.RUnlock()
+	if f.types == nil {
+		return nil
+	}
+	types := make(map[int]bool, len(f.types))
+	for t := range f.types {
+		types[t] = true
+	}
+
+	return types
+}
+
+func (f *filter) SetTypes(types map[int]bool) {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	f.types = types
+}
+
+func (f *filter) Stop() {
+	f.publisher.Stop()
+}
+
+// NewFilter returns a Publisher publishing only messages of enabled types, messages of other types are dropped,
+// nil types enable all types.
+func NewFilter(types map[int]bool, publisher Publisher) TypeFilter {
+	return &filter{
+		publisher: publisher,
+		types:     types,
+	}
+}
+
+// ParseTypes returns enabled message types from comma separated list of message type names, names may carry
+// "*" wildcards, for example ls_*, and names prefixed with "!" disable matching types. When the list has only
+// disabling names, all other types are enabled, nil is returned for empty list meaning all types are enabled.
+func ParseTypes(s string) (map[int]bool, error) {
+	if strings.TrimSpace(s) == "" {
+		return nil, nil
+	}
+	var enable, disable []string
+	for _, name := range strings.Split(s, ",") {
+		name = strings.TrimSpace(name)
+		if strings.HasPrefix(name, "!") {
+			disable = append(disable, name[1:])
+			continue
+		}
+		enable = append(enable, name)
+	}
+	types := make(map[int]bool)
+	if len(enable) == 0 {
+		for _, t := range bmp.MsgTypes() {
+			types[t] = true
+		}
+	}
+	for _, pattern := range enable {
+		matched, err := matchTypes(pattern)
+		if err != nil {
+			return nil, err
+		}
+		for _, t := range matched {
+			types[t] = true
+		}
+	}
+	for _, pattern := range disable {
+		matched, err := matchTypes(pattern)
+		if err != nil {
+			return nil, err
+		}
+		for _, t := range matched {
+			delete(types, t)
+		}
+	}
+
+	return types, nil
+}
+
+func matchTypes(pattern string) ([]int, error) {
+	var matched []int
+	for _, t := range bmp.MsgTypes() {
+		ok, err := path.Match(pattern, bmp.MsgTypeName(t))
+		if err != nil {
+			return nil, fmt.Errorf("invalid message type pattern %q with error: %+v", pattern, err)
+		}
+		if ok {
+			matched = append(matched, t)
+		}
+	}
+	if len(matched) == 0 {
+		return nil, fmt.Errorf("message type pattern %q does not match any message type", pattern)
+	}
+
+	return matched, nil
+}
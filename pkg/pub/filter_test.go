@@ -0,0 +1,91 @@
+package pub
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/sbezverk/gobmp/pkg/bmp"
+)
+
+func TestParseTypes(t *testing.T) {
+	all := make(map[int]bool)
+	for _, t := range bmp.MsgTypes() {
+		all[t] = true
+	}
+	delete(all, bmp.StatsReportMsg)
+	tests := []struct {
+		name   string
+		spec   string
+		expect map[int]bool
+		fail   bool
+	}{
+		{
+			name: "all types",
+		},
+		{
+			name:   "wildcard and name",
+			spec:   "ls_*, unicast_prefix_v4",
+			expect: map[int]bool{bmp.LSNodeMsg: true, bmp.LSLinkMsg: true, bmp.LSPrefixMsg: true, bmp.LSSRv6SIDMsg: true, bmp.UnicastPrefixV4Msg: true},
+		},
+		{
+			name:   "disabled type of enabled types",
+			spec:   "ls_*,!ls_srv6_sid",
+			expect: map[int]bool{bmp.LSNodeMsg: true, bmp.LSLinkMsg: true, bmp.LSPrefixMsg: true},
+		},
+		{
+			name:   "all types but disabled",
+			spec:   "!statistics",
+			expect: all,
+		},
+		{
+			name: "unknown type",
+			spec: "ls_nodes",
+			fail: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			types, err := ParseTypes(tt.spec)
+			if err != nil && !tt.fail {
+				t.Fatalf("supposed to succeed but failed with error: %+v", err)
+			}
+			if err == nil && tt.fail {
+				t.Fatalf("supposed to fail but succeeded")
+			}
+			if !reflect.DeepEqual(types, tt.expect) {
+				t.Errorf("expected types %v, got %v", tt.expect, types)
+			}
+		})
+	}
+}
+
+func TestFilter(t *testing.T) {
+	c := &collector{}
+	p := NewFilter(map[int]bool{bmp.LSNodeMsg: true}, c)
+	if err := p.PublishMessage(bmp.LSNodeMsg, nil, []byte(`{"name":"r1"}`)); err != nil {
+		t.Fatalf("failed to publish message with error: %+v", err)
+	}
+	if err := p.PublishMessage(bmp.UnicastPrefixV4Msg, nil, []byte(`{"prefix":"10.0.0.0"}`)); err != nil {
+		t.Fatalf("failed to publish message with error: %+v", err)
+	}
+	if err := PublishUpdate(p, bmp.UnicastPrefixV4Msg, nil, [][]byte{[]byte(`{"prefix":"10.1.0.0"}`)}); err != nil {
+		t.Fatalf("failed to publish update with error: %+v", err)
+	}
+	if expect := []string{`{"name":"r1"}`}; !reflect.DeepEqual(c.msgs, expect) {
+		t.Errorf("expected messages %v, got %v", expect, c.msgs)
+	}
+}
+
+func TestFilterSetTypes(t *testing.T) {
+	c := &collector{}
+	f := NewFilter(nil, c)
+	f.PublishMessage(bmp.PeerStateChangeMsg, nil, []byte(`{"action":"add"}`))
+	f.SetTypes(map[int]bool{bmp.LSNodeMsg: true})
+	f.PublishMessage(bmp.PeerStateChangeMsg, nil, []byte(`{"action":"del"}`))
+	if expect := []string{`{"action":"add"}`}; !reflect.DeepEqual(c.msgs, expect) {
+		t.Errorf("expected messages %v, got %v", expect, c.msgs)
+	}
+	if types := f.Types(); !reflect.DeepEqual(types, map[int]bool{bmp.LSNodeMsg: true}) {
+		t.Errorf("expected enabled ls_node type, got %v", types)
+	}
+}
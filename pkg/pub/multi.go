@@ -0,0 +1,41 @@
+package pub
+
+type multi struct {
+	publishers []Publisher
+}
+
+func (m *multi) PublishMessage(msgType int, msgHash []byte, msg []byte) error {
+	var err error
+	for _, p := range m.publishers {
+		if e := p.PublishMessage(msgType, msgHash, msg); e != nil && err == nil {
+			err = e
+		}
+	}
+
+	return err
+}
+
+func (m *multi) PublishUpdate(msgType int, msgHash []byte, msgs [][]byte) error {
+	var err error
+	for _, p := range m.publishers {
+		if e := PublishUpdate(p, msgType, msgHash, msgs); e != nil && err == nil {
+			err = e
+		}
+	}
+
+	return err
+}
+
+func (m *multi) Stop() {
+	for _, p := range m.publishers {
+		p.Stop()
+	}
+}
+
+// NewMulti returns a Publisher publishing each message to all publishers, the message is published
+// to all publishers even if some of them fail, the first error is returned.
+func NewMulti(publishers ...Publisher) Publisher {
+	return &multi{
+		publishers: publishers,
+	}
+}
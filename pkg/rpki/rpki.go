@@ -0,0 +1,108 @@
+package rpki
+
+import (
+	"net"
+	"sync"
+)
+
+// Status defines Route Origin Validation state, RFC 6811
+type Status string
+
+const (
+	// Valid is the state of a route covered by at least one VRP matching the route's origin AS and prefix length
+	Valid Status = "valid"
+	// Invalid is the state of a route covered by VRPs none of which matches the route
+	Invalid Status = "invalid"
+	// NotFound is the state of a route not covered by any VRP
+	NotFound Status = "not-found"
+)
+
+// VRP defines Validated ROA Payload
+type VRP struct {
+	Prefix    *net.IPNet
+	MaxLength int
+	ASN       uint32
+}
+
+// Validator defines methods to validate the origin of routes against a set of VRPs
+type Validator interface {
+	Validate(prefix net.IP, prefixLen int, originAS uint32) Status
+	Update(vrps []*VRP)
+}
+
+type validator struct {
+	sync.RWMutex
+	// vrps is indexed by the prefix length first and then by the prefix itself,
+	// IPv4 prefixes are stored in 4 bytes form and IPv6 in 16 bytes form.
+	vrps map[int]map[string][]*VRP
+}
+
+var _ Validator = &validator{}
+
+// Validate returns Route Origin Validation state of a route as per RFC 6811 Section 2,
+// originAS 0 is used when the origin of the route cannot be determined, such route never matches VRP.
+func (v *validator) Validate(prefix net.IP, prefixLen int, originAS uint32) Status {
+	addr := normalize(prefix)
+	if addr == nil || prefixLen < 0 || prefixLen > len(addr)*8 {
+		return NotFound
+	}
+	v.RLock()
+	defer v.RUnlock()
+	covered := false
+	for l := 0; l <= prefixLen; l++ {
+		vrps, ok := v.vrps[l]
+		if !ok {
+			continue
+		}
+		key := string(addr.Mask(net.CIDRMask(l, len(addr)*8)))
+		for _, vrp := range vrps[key] {
+			covered = true
+			if originAS != 0 && vrp.ASN == originAS && prefixLen <= vrp.MaxLength {
+				return Valid
+			}
+		}
+	}
+	if covered {
+		return Invalid
+	}
+
+	return NotFound
+}
+
+// Update replaces the set of VRPs used by Validator
+func (v *validator) Update(vrps []*VRP) {
+	m := make(map[int]map[string][]*VRP)
+	for _, vrp := range vrps {
+		addr := normalize(vrp.Prefix.IP)
+		if addr == nil {
+			continue
+		}
+		l, _ := vrp.Prefix.Mask.Size()
+		if _, ok := m[l]; !ok {
+			m[l] = make(map[string][]*VRP)
+		}
+		key := string(addr.Mask(net.CIDRMask(l, len(addr)*8)))
+		m[l][key] = append(m[l][key], vrp)
+	}
+	v.Lock()
+	defer v.Unlock()
+	v.vrps = m
+}
+
+// normalize returns IPv4 address in 4 bytes form and IPv6 address in 16 bytes form, IPv4-mapped IPv6
+// addresses are treated as IPv4.
+func normalize(ip net.IP) net.IP {
+	if ip4 := ip.To4(); ip4 != nil {
+		return ip4
+	}
+
+	return ip.To16()
+}
+
+// NewValidator instantiates a new instance of Validator with the set of VRPs
+func NewValidator(vrps []*VRP) Validator {
+	v := &validator{}
+	v.Update(vrps)
+
+	return v
+}
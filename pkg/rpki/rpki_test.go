@@ -0,0 +1,92 @@
+package rpki
+
+import (
+	"net"
+	"reflect"
+	"testing"
+)
+
+func TestUnmarshalVRPs(t *testing.T) {
+	_, p4, _ := net.ParseCIDR("1.0.0.0/24")
+	_, p6, _ := net.ParseCIDR("2001:db8::/32")
+	expect := []*VRP{
+		{Prefix: p4, MaxLength: 24, ASN: 13335},
+		{Prefix: p6, MaxLength: 48, ASN: 65001},
+	}
+	tests := []struct {
+		name  string
+		input string
+		fail  bool
+	}{
+		{
+			name:  "json",
+			input: `{"metadata":{"counts":2},"roas":[{"asn":"AS13335","prefix":"1.0.0.0/24","maxLength":24,"ta":"apnic"},{"asn":65001,"prefix":"2001:db8::/32","maxLength":48,"ta":"ripe"}]}`,
+		},
+		{
+			name:  "csv",
+			input: "ASN,IP Prefix,Max Length,Trust Anchor\nAS13335,1.0.0.0/24,24,apnic\nAS65001,2001:db8::/32,48,ripe\n",
+		},
+		{
+			name:  "invalid max length",
+			input: `{"roas":[{"asn":"AS13335","prefix":"1.0.0.0/24","maxLength":16}]}`,
+			fail:  true,
+		},
+		{
+			name:  "invalid asn",
+			input: "ASX,1.0.0.0/24,24\n",
+			fail:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := UnmarshalVRPs([]byte(tt.input))
+			if err != nil && !tt.fail {
+				t.Fatalf("expected to succeed but failed with error: %+v", err)
+			}
+			if err == nil && tt.fail {
+				t.Fatalf("expected to fail but succeeded")
+			}
+			if err == nil && !reflect.DeepEqual(got, expect) {
+				t.Fatalf("expected VRPs %+v do not match actual VRPs %+v", expect, got)
+			}
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	vrps, err := UnmarshalVRPs([]byte(`{"roas":[
+		{"asn":"AS13335","prefix":"1.0.0.0/24","maxLength":24},
+		{"asn":"AS65001","prefix":"10.0.0.0/8","maxLength":16},
+		{"asn":"AS65002","prefix":"10.1.0.0/16","maxLength":24},
+		{"asn":"AS0","prefix":"192.0.2.0/24","maxLength":32},
+		{"asn":"AS65001","prefix":"2001:db8::/32","maxLength":48}]}`))
+	if err != nil {
+		t.Fatalf("failed to unmarshal VRPs with error: %+v", err)
+	}
+	v := NewValidator(vrps)
+	tests := []struct {
+		name      string
+		prefix    string
+		prefixLen int
+		originAS  uint32
+		expect    Status
+	}{
+		{name: "exact match", prefix: "1.0.0.0", prefixLen: 24, originAS: 13335, expect: Valid},
+		{name: "wrong origin", prefix: "1.0.0.0", prefixLen: 24, originAS: 65535, expect: Invalid},
+		{name: "too specific", prefix: "1.0.0.128", prefixLen: 25, originAS: 13335, expect: Invalid},
+		{name: "within max length", prefix: "10.2.0.0", prefixLen: 16, originAS: 65001, expect: Valid},
+		{name: "matched by more specific vrp", prefix: "10.1.2.0", prefixLen: 24, originAS: 65002, expect: Valid},
+		{name: "covered but not matched", prefix: "10.1.2.0", prefixLen: 24, originAS: 65001, expect: Invalid},
+		{name: "as0 vrp", prefix: "192.0.2.0", prefixLen: 24, originAS: 0, expect: Invalid},
+		{name: "not covered", prefix: "8.8.8.0", prefixLen: 24, originAS: 15169, expect: NotFound},
+		{name: "ipv6 valid", prefix: "2001:db8:1::", prefixLen: 48, originAS: 65001, expect: Valid},
+		{name: "ipv6 not covered", prefix: "2001:db9::", prefixLen: 32, originAS: 65001, expect: NotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := v.Validate(net.ParseIP(tt.prefix), tt.prefixLen, tt.originAS); got != tt.expect {
+				t.Fatalf("expected status %s but got %s", tt.expect, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,176 @@
+package rpki
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/golang/glog"
+)
+
+// roa defines a single entry of VRP JSON export produced by rpki-client, Routinator or similar validators
+type roa struct {
+	Prefix    string      `json:"prefix"`
+	MaxLength int         `json:"maxLength"`
+	ASN       interface{} `json:"asn"`
+}
+
+type roas struct {
+	ROAs []roa `json:"roas"`
+}
+
+// UnmarshalVRPs returns a slice of VRPs from either JSON export {"roas":[{"prefix","maxLength","asn"}]}
+// or CSV export with ASN,IP Prefix,Max Length[,Trust Anchor] columns.
+func UnmarshalVRPs(b []byte) ([]*VRP, error) {
+	b = bytes.TrimSpace(b)
+	if len(b) == 0 {
+		return nil, fmt.Errorf("empty VRP data")
+	}
+	if b[0] == '{' {
+		return unmarshalJSONVRPs(b)
+	}
+
+	return unmarshalCSVVRPs(b)
+}
+
+func unmarshalJSONVRPs(b []byte) ([]*VRP, error) {
+	r := &roas{}
+	if err := json.Unmarshal(b, r); err != nil {
+		return nil, err
+	}
+	vrps := make([]*VRP, 0, len(r.ROAs))
+	for _, roa := range r.ROAs {
+		var asn string
+		switch v := roa.ASN.(type) {
+		case string:
+			asn = v
+		case float64:
+			asn = strconv.FormatFloat(v, 'f', -1, 64)
+		default:
+			return nil, fmt.Errorf("invalid asn %v of VRP %s", roa.ASN, roa.Prefix)
+		}
+		vrp, err := newVRP(asn, roa.Prefix, roa.MaxLength)
+		if err != nil {
+			return nil, err
+		}
+		vrps = append(vrps, vrp)
+	}
+
+	return vrps, nil
+}
+
+func unmarshalCSVVRPs(b []byte) ([]*VRP, error) {
+	r := csv.NewReader(bytes.NewReader(b))
+	r.FieldsPerRecord = -1
+	vrps := make([]*VRP, 0)
+	for {
+		rec, err := r.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if len(rec) < 3 {
+			return nil, fmt.Errorf("invalid VRP record %v", rec)
+		}
+		ml, err := strconv.Atoi(strings.TrimSpace(rec[2]))
+		if err != nil {
+			// Skipping header
+			if len(vrps) == 0 {
+				continue
+			}
+			return nil, fmt.Errorf("invalid max length of VRP record %v", rec)
+		}
+		vrp, err := newVRP(rec[0], rec[1], ml)
+		if err != nil {
+			return nil, err
+		}
+		vrps = append(vrps, vrp)
+	}
+
+	return vrps, nil
+}
+
+func newVRP(asn, prefix string, maxLength int) (*VRP, error) {
+	as, err := strconv.ParseUint(strings.TrimPrefix(strings.ToUpper(strings.TrimSpace(asn)), "AS"), 10, 32)
+	if err != nil {
+		return nil, fmt.Errorf("invalid asn %s of VRP %s", asn, prefix)
+	}
+	_, ipnet, err := net.ParseCIDR(strings.TrimSpace(prefix))
+	if err != nil {
+		return nil, err
+	}
+	l, bits := ipnet.Mask.Size()
+	if maxLength == 0 {
+		maxLength = l
+	}
+	if maxLength < l || maxLength > bits {
+		return nil, fmt.Errorf("invalid max length %d of VRP %s", maxLength, prefix)
+	}
+
+	return &VRP{
+		Prefix:    ipnet,
+		MaxLength: maxLength,
+		ASN:       uint32(as),
+	}, nil
+}
+
+// LoadVRPs returns a slice of VRPs read from a file or downloaded when the source is http or https URL
+func LoadVRPs(source string) ([]*VRP, error) {
+	var b []byte
+	var err error
+	if strings.HasPrefix(source, "http://") || strings.HasPrefix(source, "https://") {
+		b, err = download(source)
+	} else {
+		b, err = ioutil.ReadFile(source)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return UnmarshalVRPs(b)
+}
+
+// Refresh periodically reloads VRPs from the source and updates Validator until stop channel is closed,
+// when reload fails, Validator keeps using previously loaded VRPs.
+func Refresh(v Validator, source string, interval time.Duration, stop <-chan struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			vrps, err := LoadVRPs(source)
+			if err != nil {
+				glog.Errorf("failed to reload VRPs from %s with error: %+v", source, err)
+				continue
+			}
+			v.Update(vrps)
+			glog.V(5).Infof("reloaded %d VRPs from %s", len(vrps), source)
+		case <-stop:
+			return
+		}
+	}
+}
+
+func download(url string) ([]byte, error) {
+	client := &http.Client{Timeout: time.Minute}
+	resp, err := client.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to download VRPs from %s, status: %s", url, resp.Status)
+	}
+
+	return ioutil.ReadAll(resp.Body)
+}
@@ -0,0 +1,102 @@
+package rtc
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+
+	"github.com/golang/glog"
+	"github.com/sbezverk/gobmp/pkg/addr"
+	"github.com/sbezverk/tools"
+)
+
+const (
+	// originASLength defines the length in bits of Origin AS field of Route Target membership NLRI
+	originASLength = 32
+	// maxLength defines the length in bits of Route Target membership NLRI with complete Route Target
+	maxLength = 96
+)
+
+// NLRI defines a collection of Route Target membership NLRI objects
+type NLRI struct {
+	Route []*Route
+}
+
+// Route defines a single Route Target membership NLRI object, the route with zero Length is
+// the default route target membership, more specific routes carry Origin AS and a prefix of
+// Route Target extended community.
+// https://tools.ietf.org/html/rfc4684#section-4
+type Route struct {
+	PathID      uint32
+	Length      uint8
+	OriginAS    uint32
+	RouteTarget []byte
+}
+
+// GetRouteTarget returns a string representation of the route target, the complete route target is
+// formatted the same way as Route Target extended community, the prefix of route target is hex encoded.
+func (r *Route) GetRouteTarget() string {
+	if len(r.RouteTarget) == 0 {
+		return ""
+	}
+	if len(r.RouteTarget) < 8 {
+		return hex.EncodeToString(r.RouteTarget)
+	}
+	v := r.RouteTarget[2:]
+	switch r.RouteTarget[0] & 0x3f {
+	case 0:
+		return fmt.Sprintf("%d:%d", binary.BigEndian.Uint16(v[0:2]), binary.BigEndian.Uint32(v[2:]))
+	case 1:
+		return fmt.Sprintf("%s:%d", addr.IPv4(v[0:4]), binary.BigEndian.Uint16(v[4:]))
+	case 2:
+		return fmt.Sprintf("%d:%d", binary.BigEndian.Uint32(v[0:4]), binary.BigEndian.Uint16(v[4:]))
+	}
+
+	return hex.EncodeToString(r.RouteTarget)
+}
+
+// UnmarshalRTCNLRI builds Route Target membership NLRI object from the slice of bytes
+func UnmarshalRTCNLRI(b []byte, pathID bool) (*NLRI, error) {
+	if glog.V(6) {
+		glog.Infof("Route Target membership NLRI Raw: %s path id flag: %t", tools.MessageHex(b), pathID)
+	}
+	if len(b) == 0 {
+		return nil, fmt.Errorf("NLRI length is 0")
+	}
+	n := &NLRI{
+		Route: make([]*Route, 0),
+	}
+	for p := 0; p < len(b); {
+		r := &Route{}
+		if pathID {
+			if p+4 > len(b) {
+				return nil, fmt.Errorf("not enough bytes to reconstruct route target membership nlri")
+			}
+			r.PathID = binary.BigEndian.Uint32(b[p : p+4])
+			p += 4
+		}
+		if p+1 > len(b) {
+			return nil, fmt.Errorf("not enough bytes to reconstruct route target membership nlri")
+		}
+		r.Length = b[p]
+		p++
+		if r.Length != 0 && (r.Length < originASLength || r.Length > maxLength) {
+			return nil, fmt.Errorf("invalid route target membership nlri length %d", r.Length)
+		}
+		l := (int(r.Length) + 7) / 8
+		if p+l > len(b) {
+			return nil, fmt.Errorf("not enough bytes to reconstruct route target membership nlri")
+		}
+		if r.Length != 0 {
+			r.OriginAS = binary.BigEndian.Uint32(b[p : p+4])
+			if l > 4 {
+				r.RouteTarget = make([]byte, l-4)
+				copy(r.RouteTarget, b[p+4:p+l])
+			}
+		}
+		p += l
+		n.Route = append(n.Route, r)
+	}
+
+	return n, nil
+}
@@ -0,0 +1,107 @@
+package rtc
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/go-test/deep"
+)
+
+func TestUnmarshalRTCNLRI(t *testing.T) {
+	tests := []struct {
+		name   string
+		input  []byte
+		pathID bool
+		expect *NLRI
+		rt     []string
+		fail   bool
+	}{
+		{
+			name:  "two octet as route target and default membership",
+			input: []byte{0x60, 0x00, 0x00, 0xfd, 0xe9, 0x00, 0x02, 0xfd, 0xe8, 0x00, 0x00, 0x00, 0x01, 0x00},
+			expect: &NLRI{
+				Route: []*Route{
+					{Length: 96, OriginAS: 65001, RouteTarget: []byte{0x00, 0x02, 0xfd, 0xe8, 0x00, 0x00, 0x00, 0x01}},
+					{Length: 0},
+				},
+			},
+			rt: []string{"65000:1", ""},
+		},
+		{
+			name:   "ipv4 and four octet as route targets with path id",
+			input:  []byte{0x00, 0x00, 0x00, 0x01, 0x60, 0x00, 0x00, 0xfd, 0xe9, 0x01, 0x02, 0xc0, 0x00, 0x02, 0x01, 0x00, 0x64, 0x00, 0x00, 0x00, 0x02, 0x60, 0x00, 0x00, 0xfd, 0xe9, 0x02, 0x02, 0x00, 0x01, 0x00, 0x00, 0x00, 0x0a},
+			pathID: true,
+			expect: &NLRI{
+				Route: []*Route{
+					{PathID: 1, Length: 96, OriginAS: 65001, RouteTarget: []byte{0x01, 0x02, 0xc0, 0x00, 0x02, 0x01, 0x00, 0x64}},
+					{PathID: 2, Length: 96, OriginAS: 65001, RouteTarget: []byte{0x02, 0x02, 0x00, 0x01, 0x00, 0x00, 0x00, 0x0a}},
+				},
+			},
+			rt: []string{"192.0.2.1:100", "65536:10"},
+		},
+		{
+			name:  "route target prefix",
+			input: []byte{0x36, 0x00, 0x00, 0xfd, 0xe9, 0x00, 0x02, 0xfc},
+			expect: &NLRI{
+				Route: []*Route{
+					{Length: 54, OriginAS: 65001, RouteTarget: []byte{0x00, 0x02, 0xfc}},
+				},
+			},
+			rt: []string{"0002fc"},
+		},
+		{
+			name:  "origin as only",
+			input: []byte{0x20, 0x00, 0x00, 0xfd, 0xe9},
+			expect: &NLRI{
+				Route: []*Route{
+					{Length: 32, OriginAS: 65001},
+				},
+			},
+			rt: []string{""},
+		},
+		{
+			name:  "length shorter than origin as",
+			input: []byte{0x10, 0x00, 0x00},
+			fail:  true,
+		},
+		{
+			name:  "length longer than route target",
+			input: []byte{0x68, 0x00, 0x00, 0xfd, 0xe9, 0x00, 0x02, 0xfd, 0xe8, 0x00, 0x00, 0x00, 0x01, 0x00},
+			fail:  true,
+		},
+		{
+			name:  "truncated route target",
+			input: []byte{0x60, 0x00, 0x00, 0xfd, 0xe9, 0x00, 0x02},
+			fail:  true,
+		},
+		{
+			name:   "truncated path id",
+			input:  []byte{0x00, 0x00},
+			pathID: true,
+			fail:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := UnmarshalRTCNLRI(tt.input, tt.pathID)
+			if err != nil && !tt.fail {
+				t.Fatalf("expected to succeed but failed with error: %+v", err)
+			}
+			if err == nil && tt.fail {
+				t.Fatalf("expected to fail but succeeded")
+			}
+			if err != nil {
+				return
+			}
+			if !reflect.DeepEqual(tt.expect, got) {
+				t.Logf("Differences: %+v", deep.Equal(tt.expect, got))
+				t.Fatalf("expected nlri %+v does not match actual nlri %+v", tt.expect, got)
+			}
+			for i, r := range got.Route {
+				if s := r.GetRouteTarget(); s != tt.rt[i] {
+					t.Fatalf("expected route target %q does not match actual route target %q", tt.rt[i], s)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,37 @@
+package schema
+
+import (
+	"fmt"
+
+	"github.com/sbezverk/gobmp/pkg/pub"
+)
+
+type validator struct {
+	schemas   map[int]*Schema
+	publisher pub.Publisher
+}
+
+func (v *validator) PublishMessage(msgType int, msgHash []byte, msg []byte) error {
+	s, ok := v.schemas[msgType]
+	if !ok {
+		return fmt.Errorf("no schema of message type %d", msgType)
+	}
+	if err := Validate(s, msg); err != nil {
+		return fmt.Errorf("message of type %d does not match schema with error: %+v", msgType, err)
+	}
+
+	return v.publisher.PublishMessage(msgType, msgHash, msg)
+}
+
+func (v *validator) Stop() {
+	v.publisher.Stop()
+}
+
+// NewValidator returns a Publisher validating messages against schemas of their types, messages
+// which do not match the schema are not published to publisher and the error is returned.
+func NewValidator(schemas map[int]*Schema, publisher pub.Publisher) pub.Publisher {
+	return &validator{
+		schemas:   schemas,
+		publisher: publisher,
+	}
+}
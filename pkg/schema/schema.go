@@ -0,0 +1,185 @@
+package schema
+
+import (
+	"encoding"
+	"encoding/json"
+	"path"
+	"reflect"
+	"strings"
+)
+
+// Draft is JSON Schema dialect of generated schemas
+const Draft = "https://json-schema.org/draft/2020-12/schema"
+
+// Schema defines a subset of JSON Schema used to describe published messages
+type Schema struct {
+	Schema      string             `json:"$schema,omitempty"`
+	ID          string             `json:"$id,omitempty"`
+	Title       string             `json:"title,omitempty"`
+	Ref         string             `json:"$ref,omitempty"`
+	Type        string             `json:"type,omitempty"`
+	Properties  map[string]*Schema `json:"properties,omitempty"`
+	Required    []string           `json:"required,omitempty"`
+	Items       *Schema            `json:"items,omitempty"`
+	AnyOf       []*Schema          `json:"anyOf,omitempty"`
+	Definitions map[string]*Schema `json:"$defs,omitempty"`
+	// AdditionalProperties is either false when an object does not allow properties not listed
+	// in Properties or a *Schema of values of a map.
+	AdditionalProperties interface{} `json:"additionalProperties,omitempty"`
+}
+
+var (
+	jsonMarshaler = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	textMarshaler = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+	rawMessage    = reflect.TypeOf(json.RawMessage{})
+)
+
+type generator struct {
+	defs map[string]*Schema
+}
+
+// Generate returns JSON Schema of JSON encoding of v, v is a struct or a pointer to a struct, title
+// is the title of the schema. Named struct types are defined in $defs, types with custom JSON marshaling
+// accept any value. extra defines additional properties of the top level object which are not fields of v.
+func Generate(title string, v interface{}, extra map[string]*Schema) *Schema {
+	g := &generator{defs: make(map[string]*Schema)}
+	t := reflect.TypeOf(v)
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	s := g.object(t)
+	for n, p := range extra {
+		s.Properties[n] = p
+	}
+	s.Schema = Draft
+	s.Title = title
+	if len(g.defs) != 0 {
+		s.Definitions = g.defs
+	}
+
+	return s
+}
+
+// defName returns the name of the definition of a named type, e.g. bgp.BaseAttributes
+func defName(t reflect.Type) string {
+	return path.Base(t.PkgPath()) + "." + t.Name()
+}
+
+func (g *generator) schema(t reflect.Type) *Schema {
+	if t == rawMessage {
+		return &Schema{}
+	}
+	if t.Implements(jsonMarshaler) || reflect.PtrTo(t).Implements(jsonMarshaler) {
+		return &Schema{}
+	}
+	if t.Implements(textMarshaler) || reflect.PtrTo(t).Implements(textMarshaler) {
+		return &Schema{Type: "string"}
+	}
+	switch t.Kind() {
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &Schema{Type: "integer"}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number"}
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Slice:
+		if t.Elem().Kind() == reflect.Uint8 {
+			// []byte is encoded as base64 string
+			return &Schema{Type: "string"}
+		}
+		return &Schema{Type: "array", Items: g.schema(t.Elem())}
+	case reflect.Array:
+		return &Schema{Type: "array", Items: g.schema(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: g.schema(t.Elem())}
+	case reflect.Ptr:
+		return g.schema(t.Elem())
+	case reflect.Struct:
+		if t.Name() == "" {
+			return g.object(t)
+		}
+		n := defName(t)
+		if _, ok := g.defs[n]; !ok {
+			// Reserving the definition before the object is generated stops recursion of self referencing types
+			g.defs[n] = &Schema{}
+			*g.defs[n] = *g.object(t)
+		}
+		return &Schema{Ref: "#/$defs/" + n}
+	}
+
+	// Interfaces accept any value
+	return &Schema{}
+}
+
+// object returns the schema of JSON object encoding struct t
+func (g *generator) object(t reflect.Type) *Schema {
+	s := &Schema{
+		Type:                 "object",
+		Properties:           make(map[string]*Schema),
+		AdditionalProperties: false,
+	}
+	g.fields(t, s)
+
+	return s
+}
+
+// fields adds properties of fields of struct t to s, fields of embedded structs are promoted
+// as they are by JSON encoding.
+func (g *generator) fields(t reflect.Type, s *Schema) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, opts := tag, ""
+		if i := strings.Index(tag, ","); i != -1 {
+			name, opts = tag[:i], tag[i+1:]
+		}
+		if f.Anonymous && name == "" {
+			et := f.Type
+			if et.Kind() == reflect.Ptr {
+				et = et.Elem()
+			}
+			if et.Kind() == reflect.Struct {
+				g.fields(et, s)
+				continue
+			}
+		}
+		if f.PkgPath != "" {
+			// Unexported field
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		omitempty := false
+		asString := false
+		for _, o := range strings.Split(opts, ",") {
+			switch o {
+			case "omitempty":
+				omitempty = true
+			case "string":
+				asString = true
+			}
+		}
+		p := g.schema(f.Type)
+		if asString && p.Type != "" && p.Type != "object" && p.Type != "array" {
+			p = &Schema{Type: "string"}
+		}
+		if !omitempty {
+			s.Required = append(s.Required, name)
+			switch f.Type.Kind() {
+			case reflect.Ptr, reflect.Slice, reflect.Map, reflect.Interface:
+				// nil values are encoded as null
+				if p.Type != "" || p.Ref != "" {
+					p = &Schema{AnyOf: []*Schema{p, {Type: "null"}}}
+				}
+			}
+		}
+		s.Properties[name] = p
+	}
+}
@@ -0,0 +1,146 @@
+package schema
+
+import (
+	"net"
+	"reflect"
+	"testing"
+
+	"github.com/go-test/deep"
+)
+
+type testNode struct {
+	Name     string      `json:"name"`
+	Children []*testNode `json:"children,omitempty"`
+}
+
+type testCustom struct{}
+
+func (c *testCustom) MarshalJSON() ([]byte, error) { return []byte(`[1,"a"]`), nil }
+
+type testEmbedded struct {
+	Embedded string `json:"embedded"`
+}
+
+type testMessage struct {
+	testEmbedded
+	Action   string            `json:"action,omitempty"`
+	Count    int               `json:"count"`
+	Ratio    float64           `json:"ratio,omitempty"`
+	Valid    bool              `json:"valid"`
+	Labels   []uint32          `json:"labels"`
+	Key      []byte            `json:"key,omitempty"`
+	Address  net.IP            `json:"address,omitempty"`
+	Tags     map[string]string `json:"tags,omitempty"`
+	Root     *testNode         `json:"root,omitempty"`
+	Custom   *testCustom       `json:"custom,omitempty"`
+	Any      interface{}       `json:"any,omitempty"`
+	Skipped  string            `json:"-"`
+	internal string
+}
+
+func TestGenerate(t *testing.T) {
+	s := Generate("test", &testMessage{}, map[string]*Schema{"enrichment": {Type: "object"}})
+	expect := &Schema{
+		Schema: Draft,
+		Title:  "test",
+		Type:   "object",
+		Properties: map[string]*Schema{
+			"embedded":   {Type: "string"},
+			"action":     {Type: "string"},
+			"count":      {Type: "integer"},
+			"ratio":      {Type: "number"},
+			"valid":      {Type: "boolean"},
+			"labels":     {AnyOf: []*Schema{{Type: "array", Items: &Schema{Type: "integer"}}, {Type: "null"}}},
+			"key":        {Type: "string"},
+			"address":    {Type: "string"},
+			"tags":       {Type: "object", AdditionalProperties: &Schema{Type: "string"}},
+			"root":       {Ref: "#/$defs/schema.testNode"},
+			"custom":     {},
+			"any":        {},
+			"enrichment": {Type: "object"},
+		},
+		Required:             []string{"embedded", "count", "valid", "labels"},
+		AdditionalProperties: false,
+		Definitions: map[string]*Schema{
+			"schema.testNode": {
+				Type: "object",
+				Properties: map[string]*Schema{
+					"name":     {Type: "string"},
+					"children": {Type: "array", Items: &Schema{Ref: "#/$defs/schema.testNode"}},
+				},
+				Required:             []string{"name"},
+				AdditionalProperties: false,
+			},
+		},
+	}
+	if !reflect.DeepEqual(s, expect) {
+		t.Logf("Differences: %+v", deep.Equal(s, expect))
+		t.Fatalf("generated schema does not match expected")
+	}
+}
+
+func TestValidate(t *testing.T) {
+	s := Generate("test", &testMessage{}, map[string]*Schema{"enrichment": {Type: "object"}})
+	tests := []struct {
+		name string
+		data string
+		fail bool
+	}{
+		{
+			name: "valid message",
+			data: `{"embedded":"e","action":"add","count":1,"ratio":0.5,"valid":true,"labels":[16000],"key":"AQI=",` +
+				`"address":"192.0.2.1","tags":{"site":"a"},"root":{"name":"r","children":[{"name":"c"}]},"custom":[1,"a"],` +
+				`"any":{"x":1},"enrichment":{"origin_as_name":"EXAMPLE"}}`,
+		},
+		{
+			name: "null labels",
+			data: `{"embedded":"e","count":0,"valid":false,"labels":null}`,
+		},
+		{
+			name: "missing required property",
+			data: `{"embedded":"e","count":1,"labels":[]}`,
+			fail: true,
+		},
+		{
+			name: "unknown property",
+			data: `{"embedded":"e","count":1,"valid":true,"labels":[],"peer":"x"}`,
+			fail: true,
+		},
+		{
+			name: "number instead of integer",
+			data: `{"embedded":"e","count":1.5,"valid":true,"labels":[]}`,
+			fail: true,
+		},
+		{
+			name: "invalid item of array",
+			data: `{"embedded":"e","count":1,"valid":true,"labels":["16000"]}`,
+			fail: true,
+		},
+		{
+			name: "invalid property of referenced definition",
+			data: `{"embedded":"e","count":1,"valid":true,"labels":[],"root":{"name":"r","children":[{"name":1}]}}`,
+			fail: true,
+		},
+		{
+			name: "invalid map value",
+			data: `{"embedded":"e","count":1,"valid":true,"labels":[],"tags":{"site":1}}`,
+			fail: true,
+		},
+		{
+			name: "not json",
+			data: `{"embedded"`,
+			fail: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Validate(s, []byte(tt.data))
+			if err != nil && !tt.fail {
+				t.Fatalf("supposed to succeed but failed with error: %+v", err)
+			}
+			if err == nil && tt.fail {
+				t.Fatalf("supposed to fail but succeeded")
+			}
+		})
+	}
+}
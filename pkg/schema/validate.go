@@ -0,0 +1,115 @@
+package schema
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Validate validates JSON encoded data against the schema, only the subset of JSON Schema
+// produced by Generate is supported.
+func Validate(s *Schema, data []byte) error {
+	d := json.NewDecoder(bytes.NewReader(data))
+	d.UseNumber()
+	var v interface{}
+	if err := d.Decode(&v); err != nil {
+		return fmt.Errorf("failed to decode data with error: %+v", err)
+	}
+
+	return validate(s, s, v, "")
+}
+
+func validate(root, s *Schema, v interface{}, path string) error {
+	if s.Ref != "" {
+		d, ok := root.Definitions[strings.TrimPrefix(s.Ref, "#/$defs/")]
+		if !ok {
+			return fmt.Errorf("%s: unknown reference %s", path, s.Ref)
+		}
+		return validate(root, d, v, path)
+	}
+	if len(s.AnyOf) != 0 {
+		for _, a := range s.AnyOf {
+			if validate(root, a, v, path) == nil {
+				return nil
+			}
+		}
+		return fmt.Errorf("%s: value %v does not match any of allowed schemas", path, v)
+	}
+	switch s.Type {
+	case "":
+		return nil
+	case "null":
+		if v != nil {
+			return fmt.Errorf("%s: expected null, got %v", path, v)
+		}
+	case "boolean":
+		if _, ok := v.(bool); !ok {
+			return fmt.Errorf("%s: expected boolean, got %v", path, v)
+		}
+	case "string":
+		if _, ok := v.(string); !ok {
+			return fmt.Errorf("%s: expected string, got %v", path, v)
+		}
+	case "number":
+		if _, ok := v.(json.Number); !ok {
+			return fmt.Errorf("%s: expected number, got %v", path, v)
+		}
+	case "integer":
+		n, ok := v.(json.Number)
+		if !ok || strings.ContainsAny(n.String(), ".eE") {
+			return fmt.Errorf("%s: expected integer, got %v", path, v)
+		}
+	case "array":
+		a, ok := v.([]interface{})
+		if !ok {
+			return fmt.Errorf("%s: expected array, got %v", path, v)
+		}
+		if s.Items != nil {
+			for i, e := range a {
+				if err := validate(root, s.Items, e, fmt.Sprintf("%s[%d]", path, i)); err != nil {
+					return err
+				}
+			}
+		}
+	case "object":
+		return validateObject(root, s, v, path)
+	default:
+		return fmt.Errorf("%s: unsupported type %s", path, s.Type)
+	}
+
+	return nil
+}
+
+func validateObject(root, s *Schema, v interface{}, path string) error {
+	o, ok := v.(map[string]interface{})
+	if !ok {
+		return fmt.Errorf("%s: expected object, got %v", path, v)
+	}
+	for _, r := range s.Required {
+		if _, ok := o[r]; !ok {
+			return fmt.Errorf("%s: missing required property %s", path, r)
+		}
+	}
+	for k, e := range o {
+		p, ok := s.Properties[k]
+		if !ok {
+			switch a := s.AdditionalProperties.(type) {
+			case bool:
+				if !a {
+					return fmt.Errorf("%s: property %s is not allowed", path, k)
+				}
+				continue
+			case *Schema:
+				p = a
+			default:
+				continue
+			}
+		}
+		if err := validate(root, p, e, path+"/"+k); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
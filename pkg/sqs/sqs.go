@@ -0,0 +1,327 @@
+package sqs
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/sbezverk/gobmp/pkg/bmp"
+	"github.com/sbezverk/gobmp/pkg/pub"
+)
+
+const (
+	// queueLength defines the number of messages buffered for sending, when SQS does not keep up,
+	// messages are dropped.
+	queueLength = 8192
+	// maxBatchLength and maxBatchSize define limits of a single SendMessageBatch request
+	maxBatchLength = 10
+	maxBatchSize   = 256 * 1024
+	// flushInterval defines how often a partial batch is sent
+	flushInterval = time.Second
+	// retries defines the number of retries of a failed request, retryBackoff is the wait before
+	// the first retry, it doubles with every retry.
+	retries      = 5
+	retryBackoff = 500 * time.Millisecond
+	// shutdownTimeout defines how long messages published before the stop are sent and retried
+	shutdownTimeout = 10 * time.Second
+)
+
+// Config defines the SQS publisher, QueueURL is the url of the queue, for example
+// https://sqs.us-east-1.amazonaws.com/123456789012/gobmp, Region is taken from QueueURL when not set.
+// When Credentials are not set, they are taken from AWS_ACCESS_KEY_ID, AWS_SECRET_ACCESS_KEY and
+// AWS_SESSION_TOKEN environment variables.
+type Config struct {
+	QueueURL    string
+	Region      string
+	Credentials *Credentials
+}
+
+type attribute struct {
+	DataType    string `json:"DataType"`
+	StringValue string `json:"StringValue"`
+}
+
+type entry struct {
+	ID                     string                `json:"Id"`
+	MessageBody            string                `json:"MessageBody"`
+	MessageAttributes      map[string]*attribute `json:"MessageAttributes"`
+	MessageGroupID         string                `json:"MessageGroupId,omitempty"`
+	MessageDeduplicationID string                `json:"MessageDeduplicationId,omitempty"`
+}
+
+// size returns the size of the entry counted towards SQS limits of a message and a batch, message attributes
+// count with their names, data types and values, group and deduplication ids are counted as well.
+func (e *entry) size() int {
+	size := len(e.MessageBody) + len(e.MessageGroupID) + len(e.MessageDeduplicationID)
+	for name, a := range e.MessageAttributes {
+		size += len(name) + len(a.DataType) + len(a.StringValue)
+	}
+
+	return size
+}
+
+type sendMessageBatch struct {
+	QueueURL string   `json:"QueueUrl"`
+	Entries  []*entry `json:"Entries"`
+}
+
+type sendMessageBatchResult struct {
+	Failed []struct {
+		ID          string `json:"Id"`
+		Code        string `json:"Code"`
+		Message     string `json:"Message"`
+		SenderFault bool   `json:"SenderFault"`
+	} `json:"Failed"`
+}
+
+type publisher struct {
+	queueURL string
+	endpoint string
+	region   string
+	cred     *Credentials
+	fifo     bool
+	client   *http.Client
+	queue    chan *entry
+	stopCh   chan struct{}
+	done     chan struct{}
+	backoff  time.Duration
+	// ctx is cancelled when shutdown timeout expires after the stop, requests and retries are abandoned
+	ctx      context.Context
+	cancel   context.CancelFunc
+	shutdown time.Duration
+	// sequence makes deduplication ids of messages of FIFO queue unique
+	sequence uint64
+}
+
+func (p *publisher) PublishMessage(msgType int, msgHash []byte, msg []byte) error {
+	e := &entry{
+		MessageBody: string(msg),
+		MessageAttributes: map[string]*attribute{
+			"type": {DataType: "String", StringValue: bmp.MsgTypeName(msgType)},
+		},
+	}
+	if p.fifo {
+		// Messages with the same key are delivered in order
+		e.MessageGroupID = string(msgHash)
+		if e.MessageGroupID == "" {
+			e.MessageGroupID = bmp.MsgTypeName(msgType)
+		}
+		e.MessageDeduplicationID = strconv.FormatUint(atomic.AddUint64(&p.sequence, 1), 10) + "-" +
+			strconv.FormatInt(time.Now().UnixNano(), 36)
+	}
+	if e.size() > maxBatchSize {
+		return fmt.Errorf("message of type %d of %d bytes exceeds maximum SQS message size", msgType, e.size())
+	}
+	select {
+	case p.queue <- e:
+	default:
+		glog.V(5).Infof("SQS queue is full, dropping message of type %d", msgType)
+	}
+
+	return nil
+}
+
+func (p *publisher) Stop() {
+	close(p.stopCh)
+	t := time.AfterFunc(p.shutdown, p.cancel)
+	<-p.done
+	t.Stop()
+	p.cancel()
+}
+
+func (p *publisher) worker() {
+	defer close(p.done)
+	ticker := time.NewTicker(flushInterval)
+	defer ticker.Stop()
+	batch := make([]*entry, 0, maxBatchLength)
+	size := 0
+	add := func(e *entry) {
+		if size+e.size() > maxBatchSize {
+			p.send(batch)
+			batch, size = batch[:0], 0
+		}
+		batch, size = append(batch, e), size+e.size()
+		if len(batch) == maxBatchLength {
+			p.send(batch)
+			batch, size = batch[:0], 0
+		}
+	}
+	for {
+		select {
+		case e := <-p.queue:
+			add(e)
+		case <-ticker.C:
+			p.send(batch)
+			batch, size = batch[:0], 0
+		case <-p.stopCh:
+			// Sending messages published before the stop
+			for len(p.queue) != 0 {
+				add(<-p.queue)
+			}
+			p.send(batch)
+			return
+		}
+	}
+}
+
+// send sends the batch retrying failed requests and messages SQS failed without sender's fault, e.g. when
+// throttled, messages failed by sender's fault are dropped. Messages are dropped when retries are exhausted
+// or shutdown timeout expires after the publisher is stopped.
+func (p *publisher) send(batch []*entry) {
+	if len(batch) == 0 {
+		return
+	}
+	backoff := p.backoff
+	for attempt := 0; ; attempt++ {
+		unsent, retry, err := p.request(batch)
+		if err == nil {
+			return
+		}
+		if !retry || attempt == retries {
+			glog.Errorf("failed to send %d messages to %s with error: %+v", len(unsent), p.queueURL, err)
+			return
+		}
+		select {
+		case <-time.After(backoff):
+		case <-p.ctx.Done():
+			glog.Errorf("gobmp is stopping, failed send of %d messages to %s is not retried", len(unsent), p.queueURL)
+			return
+		}
+		batch = unsent
+		backoff *= 2
+	}
+}
+
+// request sends a single SendMessageBatch request, it returns messages which were not sent and retry set
+// when they may be sent later, messages SQS failed by sender's fault are logged and not returned.
+func (p *publisher) request(batch []*entry) ([]*entry, bool, error) {
+	for i, e := range batch {
+		e.ID = strconv.Itoa(i)
+	}
+	b, err := json.Marshal(&sendMessageBatch{QueueURL: p.queueURL, Entries: batch})
+	if err != nil {
+		return batch, false, fmt.Errorf("failed to marshal messages with error: %+v", err)
+	}
+	req, err := http.NewRequestWithContext(p.ctx, http.MethodPost, p.endpoint, bytes.NewReader(b))
+	if err != nil {
+		return batch, false, err
+	}
+	req.Header.Set("Content-Type", "application/x-amz-json-1.0")
+	req.Header.Set("X-Amz-Target", "AmazonSQS.SendMessageBatch")
+	sign(req, b, p.cred, p.region, "sqs", time.Now())
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return batch, true, err
+	}
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return batch, true, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		err = fmt.Errorf("SQS returned status: %s, %s", resp.Status, string(body))
+		return batch, resp.StatusCode/100 == 5 || bytes.Contains(body, []byte("Throttl")), err
+	}
+	result := &sendMessageBatchResult{}
+	if err := json.Unmarshal(body, result); err != nil {
+		return batch, false, fmt.Errorf("failed to unmarshal SQS response with error: %+v", err)
+	}
+	unsent := make([]*entry, 0, len(result.Failed))
+	rejected := 0
+	var code, msg string
+	for _, f := range result.Failed {
+		i, err := strconv.Atoi(f.ID)
+		if err != nil || i < 0 || i >= len(batch) {
+			continue
+		}
+		if f.SenderFault {
+			if rejected == 0 {
+				glog.Errorf("SQS rejected message of %d bytes with error: %s %s", batch[i].size(), f.Code, f.Message)
+			}
+			rejected++
+			continue
+		}
+		if len(unsent) == 0 {
+			code, msg = f.Code, f.Message
+		}
+		unsent = append(unsent, batch[i])
+	}
+	if rejected != 0 {
+		glog.Errorf("SQS rejected %d of %d messages by sender's fault, they are dropped", rejected, len(batch))
+	}
+	if len(unsent) == 0 {
+		return nil, false, nil
+	}
+
+	return unsent, true, fmt.Errorf("SQS failed %d of %d messages, first failure: %s %s", len(unsent), len(batch), code, msg)
+}
+
+// region returns the region of SQS endpoints sqs.<region>.amazonaws.com and <region>.queue.amazonaws.com
+func region(host string) string {
+	parts := strings.Split(host, ".")
+	if len(parts) < 4 {
+		return ""
+	}
+	switch {
+	case parts[0] == "sqs":
+		return parts[1]
+	case parts[1] == "queue":
+		return parts[0]
+	}
+
+	return ""
+}
+
+// NewPublisher returns a Publisher sending messages to SQS queue, every message carries its type
+// in "type" message attribute, messages of FIFO queue are grouped by their keys.
+func NewPublisher(config *Config) (pub.Publisher, error) {
+	u, err := url.Parse(config.QueueURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid SQS queue url %s with error: %+v", config.QueueURL, err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, fmt.Errorf("invalid SQS queue url %s, only http and https urls are supported", config.QueueURL)
+	}
+	p := &publisher{
+		queueURL: u.String(),
+		endpoint: u.Scheme + "://" + u.Host + "/",
+		region:   config.Region,
+		cred:     config.Credentials,
+		fifo:     strings.HasSuffix(u.Path, ".fifo"),
+		client:   &http.Client{Timeout: 10 * time.Second},
+		queue:    make(chan *entry, queueLength),
+		stopCh:   make(chan struct{}),
+		done:     make(chan struct{}),
+		backoff:  retryBackoff,
+		shutdown: shutdownTimeout,
+	}
+	if p.region == "" {
+		if p.region = region(u.Hostname()); p.region == "" {
+			return nil, fmt.Errorf("SQS region is not set and can not be found in queue url %s", config.QueueURL)
+		}
+	}
+	if p.cred == nil {
+		p.cred = &Credentials{
+			AccessKeyID:     os.Getenv("AWS_ACCESS_KEY_ID"),
+			SecretAccessKey: os.Getenv("AWS_SECRET_ACCESS_KEY"),
+			SessionToken:    os.Getenv("AWS_SESSION_TOKEN"),
+		}
+	}
+	if p.cred.AccessKeyID == "" || p.cred.SecretAccessKey == "" {
+		return nil, fmt.Errorf("AWS credentials are not set")
+	}
+	p.ctx, p.cancel = context.WithCancel(context.Background())
+	go p.worker()
+
+	return p, nil
+}
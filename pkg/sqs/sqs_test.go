@@ -0,0 +1,208 @@
+package sqs
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/sbezverk/gobmp/pkg/bmp"
+)
+
+func TestSign(t *testing.T) {
+	// get-vanilla request of AWS Signature Version 4 test suite
+	req, _ := http.NewRequest(http.MethodGet, "https://example.amazonaws.com/", nil)
+	sign(req, nil, &Credentials{
+		AccessKeyID:     "AKIDEXAMPLE",
+		SecretAccessKey: "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY",
+	}, "us-east-1", "service", time.Date(2015, 8, 30, 12, 36, 0, 0, time.UTC))
+	expect := "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20150830/us-east-1/service/aws4_request, " +
+		"SignedHeaders=host;x-amz-date, Signature=5fa00fa31553b73ebf1942676e86291e8372ff2a2260956d9b8aae1d763fbf31"
+	if auth := req.Header.Get("Authorization"); auth != expect {
+		t.Errorf("expected authorization %s, got %s", expect, auth)
+	}
+}
+
+func TestRegion(t *testing.T) {
+	tests := map[string]string{
+		"sqs.eu-west-1.amazonaws.com":     "eu-west-1",
+		"us-east-2.queue.amazonaws.com":   "us-east-2",
+		"sqs.cn-north-1.amazonaws.com.cn": "cn-north-1",
+		"localhost":                       "",
+		"elasticmq.example.com":           "",
+	}
+	for host, expect := range tests {
+		if r := region(host); r != expect {
+			t.Errorf("expected region %q of %s, got %q", expect, host, r)
+		}
+	}
+}
+
+func TestPublisher(t *testing.T) {
+	var mtx sync.Mutex
+	var batches []*sendMessageBatch
+	var requests int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.Header.Get("X-Amz-Target") != "AmazonSQS.SendMessageBatch" ||
+			!strings.HasPrefix(req.Header.Get("Authorization"), "AWS4-HMAC-SHA256 Credential=key/") {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		mtx.Lock()
+		defer mtx.Unlock()
+		requests++
+		if requests == 2 {
+			// The request of the partial batch sent by Stop fails and is retried
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		b, _ := ioutil.ReadAll(req.Body)
+		batch := &sendMessageBatch{}
+		json.Unmarshal(b, batch)
+		batches = append(batches, batch)
+		w.Write([]byte(`{"Successful":[]}`))
+	}))
+	defer srv.Close()
+	p, err := NewPublisher(&Config{
+		QueueURL:    srv.URL + "/123456789012/gobmp.fifo",
+		Region:      "us-east-1",
+		Credentials: &Credentials{AccessKeyID: "key", SecretAccessKey: "secret"},
+	})
+	if err != nil {
+		t.Fatalf("failed to create SQS publisher with error: %+v", err)
+	}
+	p.(*publisher).backoff = 0
+	for i := 0; i < 12; i++ {
+		if err := p.PublishMessage(bmp.UnicastPrefixV4Msg, []byte("key"), []byte(`{"prefix":"10.0.0.0"}`)); err != nil {
+			t.Fatalf("failed to publish message with error: %+v", err)
+		}
+	}
+	// Stop sends the partial batch
+	p.Stop()
+	var lengths []int
+	for _, b := range batches {
+		lengths = append(lengths, len(b.Entries))
+	}
+	if !reflect.DeepEqual(lengths, []int{10, 2}) {
+		t.Fatalf("expected batches of [10 2] messages, got %v", lengths)
+	}
+	e := batches[1].Entries[1]
+	if batches[1].QueueURL != srv.URL+"/123456789012/gobmp.fifo" || e.ID != "1" || e.MessageGroupID != "key" ||
+		e.MessageDeduplicationID == "" || e.MessageAttributes["type"].StringValue != "unicast_prefix_v4" {
+		t.Errorf("unexpected message %+v of queue %s", e, batches[1].QueueURL)
+	}
+}
+
+func TestPublisherFailedMessages(t *testing.T) {
+	var mtx sync.Mutex
+	var batches []*sendMessageBatch
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		mtx.Lock()
+		defer mtx.Unlock()
+		b, _ := ioutil.ReadAll(req.Body)
+		batch := &sendMessageBatch{}
+		json.Unmarshal(b, batch)
+		batches = append(batches, batch)
+		if len(batches) == 1 {
+			// The first message is rejected by sender's fault, the second one is throttled and resent
+			w.Write([]byte(`{"Successful":[{"Id":"2"}],"Failed":[` +
+				`{"Id":"0","Code":"InvalidMessageContents","Message":"invalid","SenderFault":true},` +
+				`{"Id":"1","Code":"ThrottlingException","Message":"throttled","SenderFault":false}]}`))
+			return
+		}
+		w.Write([]byte(`{"Successful":[{"Id":"0"}]}`))
+	}))
+	defer srv.Close()
+	p, err := NewPublisher(&Config{
+		QueueURL:    srv.URL + "/123456789012/gobmp",
+		Region:      "us-east-1",
+		Credentials: &Credentials{AccessKeyID: "key", SecretAccessKey: "secret"},
+	})
+	if err != nil {
+		t.Fatalf("failed to create SQS publisher with error: %+v", err)
+	}
+	p.(*publisher).backoff = 0
+	for _, msg := range []string{`{"prefix":"10.0.0.0"}`, `{"prefix":"10.0.1.0"}`, `{"prefix":"10.0.2.0"}`} {
+		if err := p.PublishMessage(bmp.UnicastPrefixV4Msg, nil, []byte(msg)); err != nil {
+			t.Fatalf("failed to publish message with error: %+v", err)
+		}
+	}
+	p.Stop()
+	if len(batches) != 2 {
+		t.Fatalf("expected 2 requests, got %d", len(batches))
+	}
+	if len(batches[1].Entries) != 1 || batches[1].Entries[0].MessageBody != `{"prefix":"10.0.1.0"}` {
+		t.Errorf("expected throttled message to be resent, got %+v", batches[1].Entries)
+	}
+}
+
+func TestPublisherBatchSize(t *testing.T) {
+	var mtx sync.Mutex
+	var lengths []int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		mtx.Lock()
+		defer mtx.Unlock()
+		b, _ := ioutil.ReadAll(req.Body)
+		batch := &sendMessageBatch{}
+		json.Unmarshal(b, batch)
+		lengths = append(lengths, len(batch.Entries))
+		w.Write([]byte(`{"Successful":[]}`))
+	}))
+	defer srv.Close()
+	p, err := NewPublisher(&Config{
+		QueueURL:    srv.URL + "/123456789012/gobmp",
+		Region:      "us-east-1",
+		Credentials: &Credentials{AccessKeyID: "key", SecretAccessKey: "secret"},
+	})
+	if err != nil {
+		t.Fatalf("failed to create SQS publisher with error: %+v", err)
+	}
+	// Bodies of both messages fit a single batch, with their attributes they do not
+	msg := []byte(strings.Repeat("a", maxBatchSize/2-10))
+	for i := 0; i < 2; i++ {
+		if err := p.PublishMessage(bmp.UnicastPrefixV4Msg, nil, msg); err != nil {
+			t.Fatalf("failed to publish message with error: %+v", err)
+		}
+	}
+	// Body fits the maximum message size, with its attributes it does not
+	if err := p.PublishMessage(bmp.UnicastPrefixV4Msg, nil, []byte(strings.Repeat("a", maxBatchSize-10))); err == nil {
+		t.Errorf("message exceeding maximum size with its attributes is supposed to be rejected")
+	}
+	p.Stop()
+	if !reflect.DeepEqual(lengths, []int{1, 1}) {
+		t.Errorf("expected batches of [1 1] messages, got %v", lengths)
+	}
+}
+
+func TestNewPublisher(t *testing.T) {
+	cred := &Credentials{AccessKeyID: "key", SecretAccessKey: "secret"}
+	tests := []struct {
+		name   string
+		config *Config
+	}{
+		{
+			name:   "invalid url",
+			config: &Config{QueueURL: "sqs.us-east-1.amazonaws.com/123456789012/gobmp", Credentials: cred},
+		},
+		{
+			name:   "unknown region",
+			config: &Config{QueueURL: "http://localhost:9324/123456789012/gobmp", Credentials: cred},
+		},
+		{
+			name:   "missing credentials",
+			config: &Config{QueueURL: "https://sqs.us-east-1.amazonaws.com/123456789012/gobmp", Credentials: &Credentials{}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewPublisher(tt.config); err == nil {
+				t.Errorf("supposed to fail but succeeded")
+			}
+		})
+	}
+}
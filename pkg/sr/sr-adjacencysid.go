@@ -7,12 +7,22 @@ import (
 
 	"github.com/golang/glog"
 	"github.com/sbezverk/gobmp/pkg/base"
+	"github.com/sbezverk/gobmp/pkg/logging"
 	"github.com/sbezverk/tools"
 )
 
-// AdjacencySIDFlag defines Adjecency SID Flag interface
+// AdjacencySIDFlag defines Adjecency SID Flag interface, accessors return flags of the same meaning regardless
+// of their position in ISIS and OSPF flags, all flags of unknown protocols are reported as not set.
 type AdjacencySIDFlags interface {
 	GetAdjSIDFlagByte() byte
+	// Backup returns B flag, the Adjacency SID is eligible for protection
+	Backup() bool
+	// ValueFlag returns V flag, the Adjacency SID carries a value instead of an index
+	ValueFlag() bool
+	// LocalFlag returns L flag, the value or index of the Adjacency SID has local significance
+	LocalFlag() bool
+	// Persistent returns P flag, the Adjacency SID is persistently allocated
+	Persistent() bool
 }
 
 // AdjacencySIDTLV defines Adjacency SID TLV Object
@@ -21,6 +31,8 @@ type AdjacencySIDTLV struct {
 	Flags  AdjacencySIDFlags `json:"flags,omitempty"`
 	Weight uint8             `json:"weight"`
 	SID    uint32            `json:"sid,omitempty"`
+	// SIDValue is SID normalized by its length and V flag
+	SIDValue *SIDValue `json:"sid_value,omitempty"`
 }
 
 func (a *AdjacencySIDTLV) MarshalJSON() ([]byte, error) {
@@ -28,35 +40,41 @@ func (a *AdjacencySIDTLV) MarshalJSON() ([]byte, error) {
 	case *AdjISISFlags:
 		f := a.Flags.(*AdjISISFlags)
 		return json.Marshal(struct {
-			Flags  *AdjISISFlags `json:"flags,omitempty"`
-			Weight uint8         `json:"weight"`
-			SID    uint32        `json:"sid,omitempty"`
+			Flags    *AdjISISFlags `json:"flags,omitempty"`
+			Weight   uint8         `json:"weight"`
+			SID      uint32        `json:"sid,omitempty"`
+			SIDValue *SIDValue     `json:"sid_value,omitempty"`
 		}{
-			Flags:  f,
-			Weight: a.Weight,
-			SID:    a.SID,
+			Flags:    f,
+			Weight:   a.Weight,
+			SID:      a.SID,
+			SIDValue: a.SIDValue,
 		})
 	case *AdjOSPFFlags:
 		f := a.Flags.(*AdjOSPFFlags)
 		return json.Marshal(struct {
-			Flags  *AdjOSPFFlags `json:"flags,omitempty"`
-			Weight uint8         `json:"weight"`
-			SID    uint32        `json:"sid,omitempty"`
+			Flags    *AdjOSPFFlags `json:"flags,omitempty"`
+			Weight   uint8         `json:"weight"`
+			SID      uint32        `json:"sid,omitempty"`
+			SIDValue *SIDValue     `json:"sid_value,omitempty"`
 		}{
-			Flags:  f,
-			Weight: a.Weight,
-			SID:    a.SID,
+			Flags:    f,
+			Weight:   a.Weight,
+			SID:      a.SID,
+			SIDValue: a.SIDValue,
 		})
 	default:
 		f := a.Flags.(*UnknownProtoFlags)
 		return json.Marshal(struct {
-			Flags  *UnknownProtoFlags `json:"flags,omitempty"`
-			Weight uint8              `json:"weight"`
-			SID    uint32             `json:"sid,omitempty"`
+			Flags    *UnknownProtoFlags `json:"flags,omitempty"`
+			Weight   uint8              `json:"weight"`
+			SID      uint32             `json:"sid,omitempty"`
+			SIDValue *SIDValue          `json:"sid_value,omitempty"`
 		}{
-			Flags:  f,
-			Weight: a.Weight,
-			SID:    a.SID,
+			Flags:    f,
+			Weight:   a.Weight,
+			SID:      a.SID,
+			SIDValue: a.SIDValue,
 		})
 	}
 }
@@ -107,6 +125,12 @@ func (a *AdjacencySIDTLV) UnmarshalJSON(b []byte) error {
 			return err
 		}
 	}
+	// SIDValue *SIDValue `json:"sid_value,omitempty"`
+	if v, ok := objVal["sid_value"]; ok {
+		if err := json.Unmarshal(v, &result.SIDValue); err != nil {
+			return err
+		}
+	}
 	*a = *result
 
 	return nil
@@ -114,7 +138,7 @@ func (a *AdjacencySIDTLV) UnmarshalJSON(b []byte) error {
 
 // UnmarshalAdjacencySIDTLV builds Adjacency SID TLV Object
 func UnmarshalAdjacencySIDTLV(b []byte, proto base.ProtoID) (*AdjacencySIDTLV, error) {
-	if glog.V(6) {
+	if logging.V(logging.SR, 6) {
 		glog.Infof("Adjacency SID TLV Raw: %s for proto: %+v", tools.MessageHex(b), proto)
 	}
 	asid := AdjacencySIDTLV{}
@@ -159,10 +183,34 @@ func UnmarshalAdjacencySIDTLV(b []byte, proto base.ProtoID) (*AdjacencySIDTLV, e
 		return nil, fmt.Errorf("invalid length %d for Adjacency SID TLV", len(b))
 	}
 	asid.SID = binary.BigEndian.Uint32(s)
+	v, err := NewSIDValue(b[p:], asid.Flags.ValueFlag())
+	if err != nil {
+		return nil, err
+	}
+	asid.SIDValue = v
 
 	return &asid, nil
 }
 
+// Marshal returns a wire format representation of Adjacency SID TLV value, when V and L flags are set
+// SID is encoded as 3 bytes label, otherwise as 4 bytes index.
+func (a *AdjacencySIDTLV) Marshal() ([]byte, error) {
+	if a.Flags == nil {
+		return nil, fmt.Errorf("Adjacency SID TLV flags are not set")
+	}
+	b := []byte{a.Flags.GetAdjSIDFlagByte(), a.Weight, 0, 0}
+	s := make([]byte, 4)
+	binary.BigEndian.PutUint32(s, a.SID)
+	if a.Flags.ValueFlag() && a.Flags.LocalFlag() {
+		if a.SID > 0x000fffff {
+			return nil, fmt.Errorf("label %d does not fit into 20 bits", a.SID)
+		}
+		return append(b, s[1:]...), nil
+	}
+
+	return append(b, s...), nil
+}
+
 // UnmarshalISISFlags build Adjacency SID ISIS Flag Object
 func UnmarshalAdjISISFlags(b []byte) (*AdjISISFlags, error) {
 	if len(b) < 1 {
@@ -234,6 +282,26 @@ func (f *AdjISISFlags) GetAdjSIDFlagByte() byte {
 	return b
 }
 
+// Backup returns B flag of ISIS Adjacency SID flags
+func (f *AdjISISFlags) Backup() bool {
+	return f.BFlag
+}
+
+// ValueFlag returns V flag of ISIS Adjacency SID flags
+func (f *AdjISISFlags) ValueFlag() bool {
+	return f.VFlag
+}
+
+// LocalFlag returns L flag of ISIS Adjacency SID flags
+func (f *AdjISISFlags) LocalFlag() bool {
+	return f.LFlag
+}
+
+// Persistent returns P flag of ISIS Adjacency SID flags
+func (f *AdjISISFlags) Persistent() bool {
+	return f.PFlag
+}
+
 // https://www.rfc-editor.org/rfc/rfc8665.html#section-6.1
 // https://www.rfc-editor.org/rfc/rfc8666.html#section-7.1
 // 0 1 2 3 4 5 6 7
@@ -272,7 +340,47 @@ func (f *AdjOSPFFlags) GetAdjSIDFlagByte() byte {
 	return b
 }
 
+// Backup returns B flag of OSPF Adjacency SID flags
+func (f *AdjOSPFFlags) Backup() bool {
+	return f.BFlag
+}
+
+// ValueFlag returns V flag of OSPF Adjacency SID flags
+func (f *AdjOSPFFlags) ValueFlag() bool {
+	return f.VFlag
+}
+
+// LocalFlag returns L flag of OSPF Adjacency SID flags
+func (f *AdjOSPFFlags) LocalFlag() bool {
+	return f.LFlag
+}
+
+// Persistent returns P flag of OSPF Adjacency SID flags
+func (f *AdjOSPFFlags) Persistent() bool {
+	return f.PFlag
+}
+
 //GetAdjSIDFlagByte returns a byte represenation for an Unknown Protocol
 func (f *UnknownProtoFlags) GetAdjSIDFlagByte() byte {
 	return f.Flags
 }
+
+// Backup returns false as the layout of flags of an Unknown Protocol is not known
+func (f *UnknownProtoFlags) Backup() bool {
+	return false
+}
+
+// ValueFlag returns false as the layout of flags of an Unknown Protocol is not known
+func (f *UnknownProtoFlags) ValueFlag() bool {
+	return false
+}
+
+// LocalFlag returns false as the layout of flags of an Unknown Protocol is not known
+func (f *UnknownProtoFlags) LocalFlag() bool {
+	return false
+}
+
+// Persistent returns false as the layout of flags of an Unknown Protocol is not known
+func (f *UnknownProtoFlags) Persistent() bool {
+	return false
+}
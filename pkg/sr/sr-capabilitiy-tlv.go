@@ -5,6 +5,7 @@ import (
 	"fmt"
 
 	"github.com/golang/glog"
+	"github.com/sbezverk/gobmp/pkg/logging"
 	"github.com/sbezverk/tools"
 )
 
@@ -17,11 +18,15 @@ type CapabilitySubTLV struct {
 
 // UnmarshalSRCapabilitySubTLV builds SR Capability TLV object
 func UnmarshalSRCapabilitySubTLV(b []byte) ([]CapabilitySubTLV, error) {
-	if glog.V(6) {
+	if logging.V(logging.SR, 6) {
 		glog.Infof("SR Capability TLV Raw: %s", tools.MessageHex(b))
 	}
 	caps := make([]CapabilitySubTLV, 0)
 	for p := 0; p < len(b); {
+		// Each entry carries at least 3 bytes of Range and 4 bytes of SID/Label sub tlv header
+		if p+7 > len(b) {
+			return nil, fmt.Errorf("not enough bytes to unmarshal SR Capability tlv")
+		}
 		cap := CapabilitySubTLV{}
 		r := make([]byte, 4)
 		// Copy 3 bytes of Range into 4 byte slice to convert it into uint32
@@ -39,19 +44,63 @@ func UnmarshalSRCapabilitySubTLV(b []byte) ([]CapabilitySubTLV, error) {
 		default:
 			return nil, fmt.Errorf("unknown SR Capability tlv type %d", t)
 		}
+		if p+int(l) > len(b) {
+			return nil, fmt.Errorf("not enough bytes to unmarshal SR Capability SID/Label sub tlv")
+		}
 		s := make([]byte, 4)
 		switch l {
 		case 3:
 			copy(s[1:], b[p:p+int(l)])
+			// Length 3 indicates a label, only 20 rightmost bits are used
+			cap.SID = binary.BigEndian.Uint32(s) & 0x000fffff
 		case 4:
 			copy(s, b[p:p+int(l)])
+			cap.SID = binary.BigEndian.Uint32(s)
 		default:
-			return nil, fmt.Errorf("invalid length %d for Prefix SID TLV", len(b))
+			return nil, fmt.Errorf("invalid length %d for SR Capability SID/Label sub tlv", l)
 		}
-		cap.SID = binary.BigEndian.Uint32(s)
 		p += int(l)
 		caps = append(caps, cap)
 	}
 
 	return caps, nil
 }
+
+// MarshalSRCapabilitySubTLV returns a wire format representation of a slice of SR Capability ranges,
+// SID fitting into 20 bits is encoded as 3 bytes label, otherwise as 4 bytes index.
+func MarshalSRCapabilitySubTLV(caps []CapabilitySubTLV) ([]byte, error) {
+	b := make([]byte, 0)
+	for _, cap := range caps {
+		if cap.Range > 0x00ffffff {
+			return nil, fmt.Errorf("range %d does not fit into 3 bytes", cap.Range)
+		}
+		b = append(b, marshalRange(cap.Range)...)
+		b = append(b, marshalSIDLabelSubTLV(cap.SID, cap.SID <= 0x000fffff)...)
+	}
+
+	return b, nil
+}
+
+// marshalRange returns 3 bytes of Range field
+func marshalRange(r uint32) []byte {
+	v := make([]byte, 4)
+	binary.BigEndian.PutUint32(v, r)
+
+	return v[1:]
+}
+
+// marshalSIDLabelSubTLV returns SID/Label sub tlv 1161, a label is encoded as 3 bytes value,
+// an index as 4 bytes value.
+func marshalSIDLabelSubTLV(sid uint32, label bool) []byte {
+	b := make([]byte, 4)
+	binary.BigEndian.PutUint16(b[0:2], 1161)
+	v := make([]byte, 4)
+	binary.BigEndian.PutUint32(v, sid)
+	if label {
+		binary.BigEndian.PutUint16(b[2:4], 3)
+		return append(b, v[1:]...)
+	}
+	binary.BigEndian.PutUint16(b[2:4], 4)
+
+	return append(b, v...)
+}
@@ -6,6 +6,7 @@ import (
 
 	"github.com/golang/glog"
 	"github.com/sbezverk/gobmp/pkg/base"
+	"github.com/sbezverk/gobmp/pkg/logging"
 	"github.com/sbezverk/tools"
 )
 
@@ -91,9 +92,12 @@ func (c *Capability) UnmarshalJSON(b []byte) error {
 
 // UnmarshalSRCapability builds SR Capability object
 func UnmarshalSRCapability(b []byte, proto base.ProtoID) (*Capability, error) {
-	if glog.V(6) {
+	if logging.V(logging.SR, 6) {
 		glog.Infof("SR Capability Raw: %s", tools.MessageHex(b))
 	}
+	if len(b) < 2 {
+		return nil, fmt.Errorf("not enough bytes to unmarshal SR Capability")
+	}
 	cap := Capability{}
 	p := 0
 	switch proto {
@@ -124,6 +128,20 @@ func UnmarshalSRCapability(b []byte, proto base.ProtoID) (*Capability, error) {
 	return &cap, nil
 }
 
+// Marshal returns a wire format representation of SR Capabilities TLV value
+func (c *Capability) Marshal() ([]byte, error) {
+	if c.Flags == nil {
+		return nil, fmt.Errorf("SR Capability flags are not set")
+	}
+	b := []byte{c.Flags.GetCapabilityFlagByte(), 0}
+	s, err := MarshalSRCapabilitySubTLV(c.SubTLV)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(b, s...), nil
+}
+
 //  0 1 2 3 4 5 6 7
 // +-+-+-+-+-+-+-+-+
 // |I|V|           |
@@ -0,0 +1,163 @@
+package sr
+
+import (
+	"encoding/json"
+)
+
+// JSON of Prefix SID and Adjacency SID flags carries the raw byte of flags in "raw" key alongside decoded flags,
+// either form is accepted when flags are unmarshaled, the raw byte takes precedence over decoded flags.
+
+// rawFlags returns the raw byte of flags from JSON of flags, nil is returned when JSON does not carry the raw byte
+func rawFlags(b []byte) ([]byte, error) {
+	var v struct {
+		Raw *uint8 `json:"raw"`
+	}
+	if err := json.Unmarshal(b, &v); err != nil {
+		return nil, err
+	}
+	if v.Raw == nil {
+		return nil, nil
+	}
+
+	return []byte{*v.Raw}, nil
+}
+
+func (f *ISISFlags) MarshalJSON() ([]byte, error) {
+	type isisFlags ISISFlags
+	return json.Marshal(struct {
+		isisFlags
+		Raw uint8 `json:"raw"`
+	}{
+		isisFlags: isisFlags(*f),
+		Raw:       f.GetPrefixSIDFlagByte(),
+	})
+}
+
+func (f *ISISFlags) UnmarshalJSON(b []byte) error {
+	type isisFlags ISISFlags
+	if err := json.Unmarshal(b, (*isisFlags)(f)); err != nil {
+		return err
+	}
+	raw, err := rawFlags(b)
+	if err != nil || raw == nil {
+		return err
+	}
+	nf, err := UnmarshalISISFlags(raw)
+	if err != nil {
+		return err
+	}
+	*f = *nf
+
+	return nil
+}
+
+func (f *OSPFFlags) MarshalJSON() ([]byte, error) {
+	type ospfFlags OSPFFlags
+	return json.Marshal(struct {
+		ospfFlags
+		Raw uint8 `json:"raw"`
+	}{
+		ospfFlags: ospfFlags(*f),
+		Raw:       f.GetPrefixSIDFlagByte(),
+	})
+}
+
+func (f *OSPFFlags) UnmarshalJSON(b []byte) error {
+	type ospfFlags OSPFFlags
+	if err := json.Unmarshal(b, (*ospfFlags)(f)); err != nil {
+		return err
+	}
+	raw, err := rawFlags(b)
+	if err != nil || raw == nil {
+		return err
+	}
+	nf, err := UnmarshalOSPFFlags(raw)
+	if err != nil {
+		return err
+	}
+	*f = *nf
+
+	return nil
+}
+
+func (f *AdjISISFlags) MarshalJSON() ([]byte, error) {
+	type adjISISFlags AdjISISFlags
+	return json.Marshal(struct {
+		adjISISFlags
+		Raw uint8 `json:"raw"`
+	}{
+		adjISISFlags: adjISISFlags(*f),
+		Raw:          f.GetAdjSIDFlagByte(),
+	})
+}
+
+func (f *AdjISISFlags) UnmarshalJSON(b []byte) error {
+	type adjISISFlags AdjISISFlags
+	if err := json.Unmarshal(b, (*adjISISFlags)(f)); err != nil {
+		return err
+	}
+	raw, err := rawFlags(b)
+	if err != nil || raw == nil {
+		return err
+	}
+	nf, err := UnmarshalAdjISISFlags(raw)
+	if err != nil {
+		return err
+	}
+	*f = *nf
+
+	return nil
+}
+
+func (f *AdjOSPFFlags) MarshalJSON() ([]byte, error) {
+	type adjOSPFFlags AdjOSPFFlags
+	return json.Marshal(struct {
+		adjOSPFFlags
+		Raw uint8 `json:"raw"`
+	}{
+		adjOSPFFlags: adjOSPFFlags(*f),
+		Raw:          f.GetAdjSIDFlagByte(),
+	})
+}
+
+func (f *AdjOSPFFlags) UnmarshalJSON(b []byte) error {
+	type adjOSPFFlags AdjOSPFFlags
+	if err := json.Unmarshal(b, (*adjOSPFFlags)(f)); err != nil {
+		return err
+	}
+	raw, err := rawFlags(b)
+	if err != nil || raw == nil {
+		return err
+	}
+	nf, err := UnmarshalAdjOSPFFlags(raw)
+	if err != nil {
+		return err
+	}
+	*f = *nf
+
+	return nil
+}
+
+func (f *UnknownProtoFlags) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Flags uint8 `json:"flags"`
+		Raw   uint8 `json:"raw"`
+	}{
+		Flags: f.Flags,
+		Raw:   f.Flags,
+	})
+}
+
+func (f *UnknownProtoFlags) UnmarshalJSON(b []byte) error {
+	type unknownProtoFlags UnknownProtoFlags
+	if err := json.Unmarshal(b, (*unknownProtoFlags)(f)); err != nil {
+		return err
+	}
+	raw, err := rawFlags(b)
+	if err != nil || raw == nil {
+		return err
+	}
+	f.Flags = raw[0]
+
+	return nil
+}
@@ -0,0 +1,128 @@
+package sr
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/golang/glog"
+	"github.com/sbezverk/gobmp/pkg/addr"
+	"github.com/sbezverk/gobmp/pkg/base"
+	"github.com/sbezverk/gobmp/pkg/logging"
+	"github.com/sbezverk/tools"
+)
+
+// LANAdjacencySIDTLV defines LAN Adjacency SID TLV Object, Neighbor ID is IS-IS System ID formatted as dot
+// separated groups of 2 bytes or OSPF Router ID.
+// https://www.rfc-editor.org/rfc/rfc9085.html#section-2.2.2
+type LANAdjacencySIDTLV struct {
+	Flags      AdjacencySIDFlags `json:"flags,omitempty"`
+	Weight     uint8             `json:"weight"`
+	NeighborID string            `json:"neighbor_id"`
+	SID        uint32            `json:"sid,omitempty"`
+	SIDValue   *SIDValue         `json:"sid_value,omitempty"`
+}
+
+func (l *LANAdjacencySIDTLV) UnmarshalJSON(b []byte) error {
+	// Flags, Weight, SID and SIDValue are the same as of Adjacency SID TLV
+	adj := &AdjacencySIDTLV{}
+	if err := adj.UnmarshalJSON(b); err != nil {
+		return err
+	}
+	result := &LANAdjacencySIDTLV{
+		Flags:    adj.Flags,
+		Weight:   adj.Weight,
+		SID:      adj.SID,
+		SIDValue: adj.SIDValue,
+	}
+	var objVal map[string]json.RawMessage
+	if err := json.Unmarshal(b, &objVal); err != nil {
+		return err
+	}
+	// NeighborID string `json:"neighbor_id"`
+	if v, ok := objVal["neighbor_id"]; ok {
+		if err := json.Unmarshal(v, &result.NeighborID); err != nil {
+			return err
+		}
+	}
+	*l = *result
+
+	return nil
+}
+
+// UnmarshalLANAdjacencySIDTLV builds LAN Adjacency SID TLV Object, Neighbor ID is 6 bytes IS-IS System ID
+// or 4 bytes OSPF Router ID, for unknown protocols it is derived from the length of the TLV.
+func UnmarshalLANAdjacencySIDTLV(b []byte, proto base.ProtoID) (*LANAdjacencySIDTLV, error) {
+	if logging.V(logging.SR, 6) {
+		glog.Infof("LAN Adjacency SID TLV Raw: %s for proto: %+v", tools.MessageHex(b), proto)
+	}
+	// Flags 1 byte, Weight 1 byte and 2 bytes Reserved
+	p := 4
+	n := 4
+	switch proto {
+	case base.ISISL1:
+		fallthrough
+	case base.ISISL2:
+		n = 6
+	case base.OSPFv2:
+		fallthrough
+	case base.OSPFv3:
+	default:
+		if len(b) > p+n+4 {
+			n = 6
+		}
+	}
+	if len(b) != p+n+3 && len(b) != p+n+4 {
+		return nil, fmt.Errorf("invalid length %d for LAN Adjacency SID TLV", len(b))
+	}
+	// Flags, Weight and SID are decoded as of Adjacency SID TLV without Neighbor ID
+	adj, err := UnmarshalAdjacencySIDTLV(append(append([]byte{}, b[:p]...), b[p+n:]...), proto)
+	if err != nil {
+		return nil, err
+	}
+	lsid := &LANAdjacencySIDTLV{
+		Flags:    adj.Flags,
+		Weight:   adj.Weight,
+		SID:      adj.SID,
+		SIDValue: adj.SIDValue,
+	}
+	if n == 4 {
+		lsid.NeighborID = addr.IPv4(b[p : p+n])
+	} else {
+		lsid.NeighborID = fmt.Sprintf("%04x.%04x.%04x", binary.BigEndian.Uint16(b[p:p+2]),
+			binary.BigEndian.Uint16(b[p+2:p+4]), binary.BigEndian.Uint16(b[p+4:p+6]))
+	}
+
+	return lsid, nil
+}
+
+// Marshal returns a wire format representation of LAN Adjacency SID TLV value, Neighbor ID is encoded as 4 bytes
+// OSPF Router ID when it is IPv4 address, otherwise as 6 bytes IS-IS System ID.
+func (l *LANAdjacencySIDTLV) Marshal() ([]byte, error) {
+	adj := &AdjacencySIDTLV{Flags: l.Flags, Weight: l.Weight, SID: l.SID}
+	b, err := adj.Marshal()
+	if err != nil {
+		return nil, err
+	}
+	nbr, err := marshalNeighborID(l.NeighborID)
+	if err != nil {
+		return nil, err
+	}
+	// Neighbor ID follows Flags, Weight and 2 bytes Reserved
+	return append(append(b[:4:4], nbr...), b[4:]...), nil
+}
+
+func marshalNeighborID(id string) ([]byte, error) {
+	if ip := net.ParseIP(id).To4(); ip != nil {
+		return ip, nil
+	}
+	b, err := hex.DecodeString(strings.ReplaceAll(id, ".", ""))
+	if err != nil || len(b) != 6 {
+		return nil, fmt.Errorf("invalid Neighbor ID %s", id)
+	}
+
+	return b, nil
+}
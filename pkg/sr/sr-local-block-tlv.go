@@ -5,6 +5,7 @@ import (
 	"fmt"
 
 	"github.com/golang/glog"
+	"github.com/sbezverk/gobmp/pkg/logging"
 	"github.com/sbezverk/tools"
 )
 
@@ -18,11 +19,15 @@ type LocalBlockTLV struct {
 
 // UnmarshalSRLocalBlockTLV builds SR LocalBlock TLV object
 func UnmarshalSRLocalBlockTLV(b []byte) ([]LocalBlockTLV, error) {
-	if glog.V(6) {
+	if logging.V(logging.SR, 6) {
 		glog.Infof("SR LocalBlock TLV Raw: %s", tools.MessageHex(b))
 	}
 	tlvs := make([]LocalBlockTLV, 0)
 	for p := 0; p < len(b); {
+		// Each entry carries at least 3 bytes of Range and 4 bytes of SID/Label sub tlv header
+		if p+7 > len(b) {
+			return nil, fmt.Errorf("not enough bytes to unmarshal SR LocalBlock tlv")
+		}
 		tlv := LocalBlockTLV{}
 		r := make([]byte, 4)
 		// Copy 3 bytes of Range into 4 byte slice to convert it into uint32
@@ -34,6 +39,12 @@ func UnmarshalSRLocalBlockTLV(b []byte) ([]LocalBlockTLV, error) {
 		p += 2
 		l := binary.BigEndian.Uint16(b[p : p+2])
 		p += 2
+		if l != 3 && l != 4 {
+			return nil, fmt.Errorf("invalid length %d for SR LocalBlock SID/Label sub tlv", l)
+		}
+		if p+int(l) > len(b) {
+			return nil, fmt.Errorf("not enough bytes to unmarshal SR LocalBlock SID/Label sub tlv")
+		}
 		v := make([]byte, 4)
 		if l == 3 {
 			copy(v[1:], b[p:p+int(l)])
@@ -62,3 +73,27 @@ func UnmarshalSRLocalBlockTLV(b []byte) ([]LocalBlockTLV, error) {
 
 	return tlvs, nil
 }
+
+// MarshalSRLocalBlockTLV returns a wire format representation of a slice of SR Local Block sub ranges
+func MarshalSRLocalBlockTLV(tlvs []LocalBlockTLV) ([]byte, error) {
+	b := make([]byte, 0)
+	for _, tlv := range tlvs {
+		if tlv.SubRange > 0x00ffffff {
+			return nil, fmt.Errorf("range %d does not fit into 3 bytes", tlv.SubRange)
+		}
+		b = append(b, marshalRange(tlv.SubRange)...)
+		switch {
+		case tlv.Label != nil:
+			if *tlv.Label > 0x000fffff {
+				return nil, fmt.Errorf("label %d does not fit into 20 bits", *tlv.Label)
+			}
+			b = append(b, marshalSIDLabelSubTLV(*tlv.Label, true)...)
+		case tlv.Index != nil:
+			b = append(b, marshalSIDLabelSubTLV(*tlv.Index, false)...)
+		default:
+			return nil, fmt.Errorf("SR Local Block sub range carries neither label nor index")
+		}
+	}
+
+	return b, nil
+}
@@ -1,7 +1,10 @@
 package sr
 
 import (
+	"fmt"
+
 	"github.com/golang/glog"
+	"github.com/sbezverk/gobmp/pkg/logging"
 	"github.com/sbezverk/tools"
 )
 
@@ -14,9 +17,12 @@ type LocalBlock struct {
 
 // UnmarshalSRLocalBlock builds SR Local Block object
 func UnmarshalSRLocalBlock(b []byte) (*LocalBlock, error) {
-	if glog.V(6) {
+	if logging.V(logging.SR, 6) {
 		glog.Infof("SR Local BLock Raw: %s", tools.MessageHex(b))
 	}
+	if len(b) < 2 {
+		return nil, fmt.Errorf("not enough bytes to unmarshal SR Local Block")
+	}
 	lb := LocalBlock{}
 	p := 0
 	lb.Flags = b[p]
@@ -31,3 +37,14 @@ func UnmarshalSRLocalBlock(b []byte) (*LocalBlock, error) {
 
 	return &lb, nil
 }
+
+// Marshal returns a wire format representation of SR Local Block TLV value
+func (lb *LocalBlock) Marshal() ([]byte, error) {
+	b := []byte{lb.Flags, 0}
+	s, err := MarshalSRLocalBlockTLV(lb.TLV)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(b, s...), nil
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 
 	"github.com/golang/glog"
+	"github.com/sbezverk/gobmp/pkg/logging"
 	"github.com/sbezverk/tools"
 )
 
@@ -29,12 +30,33 @@ func UnmarshalPeerFlags(b []byte) (*PeerFlags, error) {
 	}, nil
 }
 
+// GetPeerFlagByte returns a byte represenation for Peer SID flags
+func (f *PeerFlags) GetPeerFlagByte() byte {
+	b := byte(0)
+	if f.VFlag {
+		b += 0x80
+	}
+	if f.LFlag {
+		b += 0x40
+	}
+	if f.BFlag {
+		b += 0x20
+	}
+	if f.PFlag {
+		b += 0x10
+	}
+
+	return b
+}
+
 // PeerSID defines Peer SID TLV Object
 // https://datatracker.ietf.org/doc/draft-ietf-idr-bgpls-segment-routing-epe Section 4
 type PeerSID struct {
 	Flags  *PeerFlags `json:"flags"`
 	Weight uint8      `json:"weight"`
 	SID    uint32     `json:"sid,omitempty"`
+	// SIDValue is SID normalized by its length and V flag
+	SIDValue *SIDValue `json:"sid_value,omitempty"`
 }
 
 func (p *PeerSID) String() string {
@@ -44,7 +66,7 @@ func (p *PeerSID) String() string {
 
 // UnmarshalPeerSID builds PeerSID TLV Object
 func UnmarshalPeerSID(b []byte) (*PeerSID, error) {
-	if glog.V(6) {
+	if logging.V(logging.SR, 6) {
 		glog.Infof("Peer SID TLV Raw: %s", tools.MessageHex(b))
 	}
 	if len(b) != 7 && len(b) != 8 {
@@ -83,6 +105,31 @@ func UnmarshalPeerSID(b []byte) (*PeerSID, error) {
 		return nil, fmt.Errorf("software bug in peer sid processing logic, byte slice: %s", tools.MessageHex(b))
 	}
 	psid.SID = binary.BigEndian.Uint32(s)
+	if psid.SIDValue, err = NewSIDValue(b[p:p+l], psid.Flags.VFlag); err != nil {
+		return nil, err
+	}
 
 	return &psid, nil
 }
+
+// Marshal returns a wire format representation of Peer SID TLV value, when V flag is set
+// SID is encoded as 3 bytes label, otherwise as 4 bytes index.
+func (p *PeerSID) Marshal() ([]byte, error) {
+	if p.Flags == nil {
+		return nil, fmt.Errorf("Peer SID TLV flags are not set")
+	}
+	b := []byte{p.Flags.GetPeerFlagByte(), p.Weight, 0, 0}
+	s := make([]byte, 4)
+	binary.BigEndian.PutUint32(s, p.SID)
+	if p.Flags.VFlag {
+		if !p.Flags.LFlag {
+			return nil, fmt.Errorf("peer sid label requires both V and L flags to be set to \"true\"")
+		}
+		if p.SID > 0x000fffff {
+			return nil, fmt.Errorf("label %d does not fit into 20 bits", p.SID)
+		}
+		return append(b, s[1:]...), nil
+	}
+
+	return append(b, s...), nil
+}
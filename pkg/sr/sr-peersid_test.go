@@ -22,8 +22,9 @@ func TestUnmarshalPeerSID(t *testing.T) {
 					LFlag: true,
 					PFlag: true,
 				},
-				Weight: 0,
-				SID:    15016,
+				Weight:   0,
+				SID:      15016,
+				SIDValue: &SIDValue{Type: SIDTypeLabel, Label: pUint32(15016)},
 			},
 		},
 	}
@@ -43,3 +44,34 @@ func TestUnmarshalPeerSID(t *testing.T) {
 		})
 	}
 }
+
+func TestMarshalPeerSID(t *testing.T) {
+	tests := []struct {
+		name  string
+		input []byte
+	}{
+		{
+			name:  "label",
+			input: []byte{0xD0, 0x00, 0x00, 0x00, 0x00, 0x3A, 0xA8},
+		},
+		{
+			name:  "index",
+			input: []byte{0x20, 0x01, 0x00, 0x00, 0x00, 0x00, 0x00, 0x64},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			psid, err := UnmarshalPeerSID(tt.input)
+			if err != nil {
+				t.Fatalf("failed to unmarshal Peer SID with error: %+v", err)
+			}
+			result, err := psid.Marshal()
+			if err != nil {
+				t.Fatalf("failed to marshal Peer SID with error: %+v", err)
+			}
+			if !reflect.DeepEqual(tt.input, result) {
+				t.Fatalf("expected %+v does not match marshaled %+v", tt.input, result)
+			}
+		})
+	}
+}
@@ -7,6 +7,7 @@ import (
 
 	"github.com/golang/glog"
 	"github.com/sbezverk/gobmp/pkg/base"
+	"github.com/sbezverk/gobmp/pkg/logging"
 	"github.com/sbezverk/tools"
 )
 
@@ -21,6 +22,8 @@ type PrefixSIDTLV struct {
 	Flags     PrefixSIDFlags `json:"flags,omitempty"`
 	Algorithm uint8          `json:"algo"`
 	SID       uint32         `json:"prefix_sid,omitempty"`
+	// SIDValue is SID normalized by its length and V flag
+	SIDValue *SIDValue `json:"sid_value,omitempty"`
 }
 
 func (p *PrefixSIDTLV) MarshalJSON() ([]byte, error) {
@@ -31,10 +34,12 @@ func (p *PrefixSIDTLV) MarshalJSON() ([]byte, error) {
 			Flags     *ISISFlags `json:"flags,omitempty"`
 			Algorithm uint8      `json:"algo"`
 			SID       uint32     `json:"prefix_sid,omitempty"`
+			SIDValue  *SIDValue  `json:"sid_value,omitempty"`
 		}{
 			Flags:     f,
 			Algorithm: p.Algorithm,
 			SID:       p.SID,
+			SIDValue:  p.SIDValue,
 		})
 	case *OSPFFlags:
 		f := p.Flags.(*OSPFFlags)
@@ -42,10 +47,12 @@ func (p *PrefixSIDTLV) MarshalJSON() ([]byte, error) {
 			Flags     *OSPFFlags `json:"flags,omitempty"`
 			Algorithm uint8      `json:"algo"`
 			SID       uint32     `json:"prefix_sid,omitempty"`
+			SIDValue  *SIDValue  `json:"sid_value,omitempty"`
 		}{
 			Flags:     f,
 			Algorithm: p.Algorithm,
 			SID:       p.SID,
+			SIDValue:  p.SIDValue,
 		})
 	default:
 		f := p.Flags.(*UnknownProtoFlags)
@@ -53,10 +60,12 @@ func (p *PrefixSIDTLV) MarshalJSON() ([]byte, error) {
 			Flags     *UnknownProtoFlags `json:"flags,omitempty"`
 			Algorithm uint8              `json:"algo"`
 			SID       uint32             `json:"prefix_sid,omitempty"`
+			SIDValue  *SIDValue          `json:"sid_value,omitempty"`
 		}{
 			Flags:     f,
 			Algorithm: p.Algorithm,
 			SID:       p.SID,
+			SIDValue:  p.SIDValue,
 		})
 	}
 }
@@ -107,6 +116,12 @@ func (p *PrefixSIDTLV) UnmarshalJSON(b []byte) error {
 			return err
 		}
 	}
+	// SIDValue *SIDValue `json:"sid_value,omitempty"`
+	if v, ok := objVal["sid_value"]; ok {
+		if err := json.Unmarshal(v, &result.SIDValue); err != nil {
+			return err
+		}
+	}
 	*p = *result
 
 	return nil
@@ -114,7 +129,7 @@ func (p *PrefixSIDTLV) UnmarshalJSON(b []byte) error {
 
 // UnmarshalPrefixSIDTLV builds Prefix SID TLV Object
 func UnmarshalPrefixSIDTLV(b []byte, proto base.ProtoID) (*PrefixSIDTLV, error) {
-	if glog.V(6) {
+	if logging.V(logging.SR, 6) {
 		glog.Infof("Prefix SID TLV Raw: %s for proto: %+v", tools.MessageHex(b), proto)
 	}
 	psid := PrefixSIDTLV{}
@@ -159,10 +174,48 @@ func UnmarshalPrefixSIDTLV(b []byte, proto base.ProtoID) (*PrefixSIDTLV, error)
 		return nil, fmt.Errorf("invalid length %d for Prefix SID TLV", len(b))
 	}
 	psid.SID = binary.BigEndian.Uint32(s)
+	v, err := NewSIDValue(b[p:], prefixVFlag(psid.Flags))
+	if err != nil {
+		return nil, err
+	}
+	psid.SIDValue = v
 
 	return &psid, nil
 }
 
+// prefixVFlag returns V flag of ISIS or OSPF flags, V flag of flags of unknown protocol is not known
+func prefixVFlag(f PrefixSIDFlags) bool {
+	switch f := f.(type) {
+	case *ISISFlags:
+		return f.VFlag
+	case *OSPFFlags:
+		return f.VFlag
+	}
+
+	return false
+}
+
+// Marshal returns a wire format representation of Prefix SID TLV value, when V and L flags are set
+// SID is encoded as 3 bytes label, otherwise as 4 bytes index.
+func (p *PrefixSIDTLV) Marshal() ([]byte, error) {
+	if p.Flags == nil {
+		return nil, fmt.Errorf("Prefix SID TLV flags are not set")
+	}
+	f := p.Flags.GetPrefixSIDFlagByte()
+	b := []byte{f, p.Algorithm, 0, 0}
+	s := make([]byte, 4)
+	binary.BigEndian.PutUint32(s, p.SID)
+	// V and L flags are located at the same positions for ISIS and OSPF
+	if _, ok := p.Flags.(*UnknownProtoFlags); !ok && f&0x0c == 0x0c {
+		if p.SID > 0x000fffff {
+			return nil, fmt.Errorf("label %d does not fit into 20 bits", p.SID)
+		}
+		return append(b, s[1:]...), nil
+	}
+
+	return append(b, s...), nil
+}
+
 // UnmarshalISISFlags build Prefix SID ISIS Flag Object
 func UnmarshalISISFlags(b []byte) (*ISISFlags, error) {
 	if len(b) < 1 {
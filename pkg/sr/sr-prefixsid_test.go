@@ -31,6 +31,7 @@ func TestUnmarshalPrefixSIDTLV(t *testing.T) {
 				},
 				Algorithm: 129,
 				SID:       20007,
+				SIDValue:  &SIDValue{Type: SIDTypeIndex, Index: pUint32(20007)},
 			},
 			fail: false,
 		},
@@ -49,6 +50,7 @@ func TestUnmarshalPrefixSIDTLV(t *testing.T) {
 				},
 				Algorithm: 0,
 				SID:       8,
+				SIDValue:  &SIDValue{Type: SIDTypeIndex, Index: pUint32(8)},
 			},
 			fail: false,
 		},
@@ -66,6 +68,7 @@ func TestUnmarshalPrefixSIDTLV(t *testing.T) {
 				},
 				Algorithm: 0,
 				SID:       212,
+				SIDValue:  &SIDValue{Type: SIDTypeIndex, Index: pUint32(212)},
 			},
 			fail: false,
 		},
@@ -160,3 +163,47 @@ func TestRoundTripPrefixSIDTLV(t *testing.T) {
 		})
 	}
 }
+
+func TestMarshalPrefixSIDTLV(t *testing.T) {
+	tests := []struct {
+		name  string
+		input []byte
+		proto base.ProtoID
+	}{
+		{
+			name:  "isis index",
+			input: []byte{0x40, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x01},
+			proto: base.ISISL2,
+		},
+		{
+			name:  "isis label",
+			input: []byte{0x0c, 0x00, 0x00, 0x00, 0x00, 0x3e, 0x80},
+			proto: base.ISISL1,
+		},
+		{
+			name:  "ospf index",
+			input: []byte{0x40, 0x80, 0x00, 0x00, 0x00, 0x00, 0x4e, 0x27},
+			proto: base.OSPFv2,
+		},
+		{
+			name:  "unknown protocol",
+			input: []byte{0x0c, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x10},
+			proto: base.BGP,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			psid, err := UnmarshalPrefixSIDTLV(tt.input, tt.proto)
+			if err != nil {
+				t.Fatalf("failed to unmarshal Prefix SID with error: %+v", err)
+			}
+			result, err := psid.Marshal()
+			if err != nil {
+				t.Fatalf("failed to marshal Prefix SID with error: %+v", err)
+			}
+			if !reflect.DeepEqual(tt.input, result) {
+				t.Fatalf("expected %+v does not match marshaled %+v", tt.input, result)
+			}
+		})
+	}
+}
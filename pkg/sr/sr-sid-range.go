@@ -0,0 +1,46 @@
+package sr
+
+// SIDRange defines a normalized representation of a single SRGB or SRLB range,
+// FirstSID carries either the first label or the first index of the range.
+type SIDRange struct {
+	FirstSID uint32 `json:"first_sid"`
+	Range    uint32 `json:"range"`
+}
+
+// GetRanges returns a list of SRGB ranges advertised in SR Capabilities TLV
+func (c *Capability) GetRanges() []*SIDRange {
+	if c == nil || len(c.SubTLV) == 0 {
+		return nil
+	}
+	ranges := make([]*SIDRange, 0, len(c.SubTLV))
+	for _, tlv := range c.SubTLV {
+		ranges = append(ranges, &SIDRange{
+			FirstSID: tlv.SID,
+			Range:    tlv.Range,
+		})
+	}
+
+	return ranges
+}
+
+// GetRanges returns a list of SRLB ranges advertised in SR Local Block TLV
+func (lb *LocalBlock) GetRanges() []*SIDRange {
+	if lb == nil || len(lb.TLV) == 0 {
+		return nil
+	}
+	ranges := make([]*SIDRange, 0, len(lb.TLV))
+	for _, tlv := range lb.TLV {
+		r := &SIDRange{
+			Range: tlv.SubRange,
+		}
+		switch {
+		case tlv.Label != nil:
+			r.FirstSID = *tlv.Label
+		case tlv.Index != nil:
+			r.FirstSID = *tlv.Index
+		}
+		ranges = append(ranges, r)
+	}
+
+	return ranges
+}
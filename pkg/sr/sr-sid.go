@@ -0,0 +1,46 @@
+package sr
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	"github.com/sbezverk/gobmp/pkg/addr"
+)
+
+// Types of normalized SID
+const (
+	SIDTypeLabel = "label"
+	SIDTypeIndex = "index"
+	SIDTypeIPv6  = "ipv6"
+)
+
+// SIDValue defines SID/Label/Index field of SR TLVs normalized by the length of the field and V flag, only one
+// of Label, Index and IPv6 is set as per Type.
+type SIDValue struct {
+	Type  string  `json:"type"`
+	Label *uint32 `json:"label,omitempty"`
+	Index *uint32 `json:"index,omitempty"`
+	IPv6  string  `json:"ipv6,omitempty"`
+}
+
+// NewSIDValue returns normalized SID/Label/Index field, 3 bytes field carries 20 bits MPLS label in its rightmost
+// bits, 4 bytes field carries an index into SRGB or SRLB, or a label when V (value) flag is set, 16 bytes field
+// carries IPv6 SID.
+func NewSIDValue(b []byte, vFlag bool) (*SIDValue, error) {
+	switch len(b) {
+	case 3:
+		label := uint32(b[0])<<16 | uint32(b[1])<<8 | uint32(b[2])
+		label &= 0x000fffff
+		return &SIDValue{Type: SIDTypeLabel, Label: &label}, nil
+	case 4:
+		v := binary.BigEndian.Uint32(b)
+		if vFlag {
+			return &SIDValue{Type: SIDTypeLabel, Label: &v}, nil
+		}
+		return &SIDValue{Type: SIDTypeIndex, Index: &v}, nil
+	case 16:
+		return &SIDValue{Type: SIDTypeIPv6, IPv6: addr.IPv6(b)}, nil
+	}
+
+	return nil, fmt.Errorf("invalid length %d of SID/Label/Index", len(b))
+}
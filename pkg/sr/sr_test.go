@@ -1,6 +1,7 @@
 package sr
 
 import (
+	"encoding/json"
 	"reflect"
 	"testing"
 
@@ -32,6 +33,31 @@ func TestUnmarshalSRCapabilities(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "multiple ranges",
+			raw: []byte{
+				0x80, 0x00,
+				0x00, 0x1f, 0x40, 0x04, 0x89, 0x00, 0x03, 0x00, 0x3e, 0x80,
+				0x00, 0x03, 0xe8, 0x04, 0x89, 0x00, 0x03, 0x01, 0x86, 0xa0,
+			},
+			proto: base.ISISL2,
+			expected: &Capability{
+				Flags: &ISISCapFlags{
+					IFlag: true,
+					VFlag: false,
+				},
+				SubTLV: []CapabilitySubTLV{
+					{
+						Range: 8000,
+						SID:   16000,
+					},
+					{
+						Range: 1000,
+						SID:   100000,
+					},
+				},
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -46,6 +72,66 @@ func TestUnmarshalSRCapabilities(t *testing.T) {
 	}
 }
 
+func TestUnmarshalSRCapabilitiesMalformed(t *testing.T) {
+	tests := []struct {
+		name string
+		raw  []byte
+	}{
+		{
+			name: "no flags",
+			raw:  []byte{0x80},
+		},
+		{
+			name: "truncated range",
+			raw:  []byte{0x80, 0x00, 0x00, 0xfa, 0x00, 0x04},
+		},
+		{
+			name: "truncated sid",
+			raw:  []byte{0x80, 0x00, 0x00, 0xfa, 0x00, 0x04, 0x89, 0x00, 0x03, 0x01},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := UnmarshalSRCapability(tt.raw, base.ISISL2); err == nil {
+				t.Errorf("supposed to fail but succeeded")
+			}
+		})
+	}
+}
+
+func TestSIDRanges(t *testing.T) {
+	c := &Capability{
+		SubTLV: []CapabilitySubTLV{
+			{Range: 8000, SID: 16000},
+			{Range: 1000, SID: 100000},
+		},
+	}
+	expect := []*SIDRange{
+		{FirstSID: 16000, Range: 8000},
+		{FirstSID: 100000, Range: 1000},
+	}
+	if diff := deep.Equal(c.GetRanges(), expect); len(diff) != 0 {
+		t.Errorf("srgb ranges do not match, differences: %+v", diff)
+	}
+	lb := &LocalBlock{
+		TLV: []LocalBlockTLV{
+			{SubRange: 1000, Label: pUint32(15000)},
+			{SubRange: 100, Index: pUint32(5)},
+		},
+	}
+	expect = []*SIDRange{
+		{FirstSID: 15000, Range: 1000},
+		{FirstSID: 5, Range: 100},
+	}
+	if diff := deep.Equal(lb.GetRanges(), expect); len(diff) != 0 {
+		t.Errorf("srlb ranges do not match, differences: %+v", diff)
+	}
+	var nilLB *LocalBlock
+	if r := nilLB.GetRanges(); r != nil {
+		t.Errorf("expected nil ranges for nil local block, got %+v", r)
+	}
+}
+
 func pUint32(n uint32) *uint32 {
 	return &n
 }
@@ -82,3 +168,320 @@ func TestUnmarshalSRLocalBlock(t *testing.T) {
 		})
 	}
 }
+
+func TestMarshalAdjacencySIDTLV(t *testing.T) {
+	tests := []struct {
+		name  string
+		input []byte
+		proto base.ProtoID
+	}{
+		{
+			name:  "isis label",
+			input: []byte{0x30, 0x00, 0x00, 0x00, 0x00, 0x5d, 0xc1},
+			proto: base.ISISL2,
+		},
+		{
+			name:  "ospf label",
+			input: []byte{0x60, 0x01, 0x00, 0x00, 0x00, 0x5d, 0xc2},
+			proto: base.OSPFv2,
+		},
+		{
+			name:  "isis index",
+			input: []byte{0x80, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x05},
+			proto: base.ISISL1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			asid, err := UnmarshalAdjacencySIDTLV(tt.input, tt.proto)
+			if err != nil {
+				t.Fatalf("failed to unmarshal Adjacency SID with error: %+v", err)
+			}
+			result, err := asid.Marshal()
+			if err != nil {
+				t.Fatalf("failed to marshal Adjacency SID with error: %+v", err)
+			}
+			if !reflect.DeepEqual(tt.input, result) {
+				t.Fatalf("expected %+v does not match marshaled %+v", tt.input, result)
+			}
+		})
+	}
+}
+
+func TestMarshalSRCapabilityAndLocalBlock(t *testing.T) {
+	capInput := []byte{0xc0, 0x00, 0x00, 0xfa, 0x00, 0x04, 0x89, 0x00, 0x03, 0x01, 0x86, 0xa0}
+	cap, err := UnmarshalSRCapability(capInput, base.ISISL2)
+	if err != nil {
+		t.Fatalf("failed to unmarshal SR Capability with error: %+v", err)
+	}
+	result, err := cap.Marshal()
+	if err != nil {
+		t.Fatalf("failed to marshal SR Capability with error: %+v", err)
+	}
+	if !reflect.DeepEqual(capInput, result) {
+		t.Fatalf("expected %+v does not match marshaled %+v", capInput, result)
+	}
+	lbInput := []byte{0x00, 0x00, 0x00, 0x03, 0xe8, 0x04, 0x89, 0x00, 0x03, 0x00, 0x3a, 0x98, 0x00, 0x00, 0x64, 0x04, 0x89, 0x00, 0x04, 0x00, 0x00, 0x00, 0x0a}
+	lb, err := UnmarshalSRLocalBlock(lbInput)
+	if err != nil {
+		t.Fatalf("failed to unmarshal SR Local Block with error: %+v", err)
+	}
+	result, err = lb.Marshal()
+	if err != nil {
+		t.Fatalf("failed to marshal SR Local Block with error: %+v", err)
+	}
+	if !reflect.DeepEqual(lbInput, result) {
+		t.Fatalf("expected %+v does not match marshaled %+v", lbInput, result)
+	}
+}
+
+func TestNewSIDValue(t *testing.T) {
+	tests := []struct {
+		name   string
+		input  []byte
+		vFlag  bool
+		expect *SIDValue
+		fail   bool
+	}{
+		{
+			name:   "3 bytes label",
+			input:  []byte{0xf0, 0x3a, 0xa8},
+			expect: &SIDValue{Type: SIDTypeLabel, Label: pUint32(15016)},
+		},
+		{
+			name:   "4 bytes index",
+			input:  []byte{0x00, 0x00, 0x00, 0x64},
+			expect: &SIDValue{Type: SIDTypeIndex, Index: pUint32(100)},
+		},
+		{
+			name:   "4 bytes label",
+			input:  []byte{0x00, 0x00, 0x3a, 0xa8},
+			vFlag:  true,
+			expect: &SIDValue{Type: SIDTypeLabel, Label: pUint32(15016)},
+		},
+		{
+			name:   "ipv6",
+			input:  []byte{0x20, 0x01, 0x0d, 0xb8, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0x01},
+			expect: &SIDValue{Type: SIDTypeIPv6, IPv6: "2001:db8::1"},
+		},
+		{
+			name:  "invalid length",
+			input: []byte{0x00, 0x64},
+			fail:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewSIDValue(tt.input, tt.vFlag)
+			if err != nil && !tt.fail {
+				t.Fatalf("supposed to succeed but failed with error: %+v", err)
+			}
+			if err == nil && tt.fail {
+				t.Fatalf("supposed to fail but succeeded")
+			}
+			if diff := deep.Equal(got, tt.expect); len(diff) != 0 {
+				t.Errorf("expected and actual SID values do not match, differences: %+v", diff)
+			}
+		})
+	}
+}
+
+func TestUnmarshalLANAdjacencySIDTLV(t *testing.T) {
+	tests := []struct {
+		name   string
+		input  []byte
+		proto  base.ProtoID
+		expect *LANAdjacencySIDTLV
+		fail   bool
+	}{
+		{
+			name:  "isis label",
+			input: []byte{0x30, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0x00, 0x5d, 0xc1},
+			proto: base.ISISL2,
+			expect: &LANAdjacencySIDTLV{
+				Flags:      &AdjISISFlags{VFlag: true, LFlag: true},
+				NeighborID: "0000.0000.0002",
+				SID:        24001,
+				SIDValue:   &SIDValue{Type: SIDTypeLabel, Label: pUint32(24001)},
+			},
+		},
+		{
+			name:  "ospf index",
+			input: []byte{0x00, 0x01, 0x00, 0x00, 0xc0, 0x00, 0x02, 0x01, 0x00, 0x00, 0x00, 0x05},
+			proto: base.OSPFv2,
+			expect: &LANAdjacencySIDTLV{
+				Flags:      &AdjOSPFFlags{},
+				Weight:     1,
+				NeighborID: "192.0.2.1",
+				SID:        5,
+				SIDValue:   &SIDValue{Type: SIDTypeIndex, Index: pUint32(5)},
+			},
+		},
+		{
+			name:  "invalid length",
+			input: []byte{0x30, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x5d, 0xc1},
+			proto: base.ISISL2,
+			fail:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := UnmarshalLANAdjacencySIDTLV(tt.input, tt.proto)
+			if err != nil && !tt.fail {
+				t.Fatalf("supposed to succeed but failed with error: %+v", err)
+			}
+			if err == nil && tt.fail {
+				t.Fatalf("supposed to fail but succeeded")
+			}
+			if err != nil {
+				return
+			}
+			if diff := deep.Equal(got, tt.expect); len(diff) != 0 {
+				t.Errorf("expected and actual LAN Adjacency SID do not match, differences: %+v", diff)
+			}
+			b, err := json.Marshal(got)
+			if err != nil {
+				t.Fatalf("failed to marshal LAN Adjacency SID with error: %+v", err)
+			}
+			result := &LANAdjacencySIDTLV{}
+			if err := json.Unmarshal(b, result); err != nil {
+				t.Fatalf("failed to unmarshal LAN Adjacency SID with error: %+v", err)
+			}
+			if diff := deep.Equal(result, tt.expect); len(diff) != 0 {
+				t.Errorf("LAN Adjacency SID does not survive json round trip, differences: %+v", diff)
+			}
+			wire, err := got.Marshal()
+			if err != nil {
+				t.Fatalf("failed to marshal LAN Adjacency SID with error: %+v", err)
+			}
+			if !reflect.DeepEqual(tt.input, wire) {
+				t.Errorf("expected %+v does not match marshaled %+v", tt.input, wire)
+			}
+		})
+	}
+}
+
+func TestAdjacencySIDFlags(t *testing.T) {
+	tests := []struct {
+		name       string
+		input      []byte
+		proto      base.ProtoID
+		flags      AdjacencySIDFlags
+		backup     bool
+		value      bool
+		local      bool
+		persistent bool
+	}{
+		{
+			name:       "isis",
+			input:      []byte{0x74, 0x00, 0x00, 0x00, 0x00, 0x5d, 0xc1},
+			proto:      base.ISISL2,
+			flags:      &AdjISISFlags{BFlag: true, VFlag: true, LFlag: true, PFlag: true},
+			backup:     true,
+			value:      true,
+			local:      true,
+			persistent: true,
+		},
+		{
+			name:  "ospf uses ospf layout",
+			input: []byte{0x60, 0x00, 0x00, 0x00, 0x00, 0x5d, 0xc1},
+			proto: base.OSPFv2,
+			flags: &AdjOSPFFlags{VFlag: true, LFlag: true},
+			value: true,
+			local: true,
+		},
+		{
+			name:       "ospfv3 backup and persistent",
+			input:      []byte{0x88, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x05},
+			proto:      base.OSPFv3,
+			flags:      &AdjOSPFFlags{BFlag: true, PFlag: true},
+			backup:     true,
+			persistent: true,
+		},
+		{
+			name:  "unknown protocol",
+			input: []byte{0xff, 0x00, 0x00, 0x00, 0x00, 0x5d, 0xc1},
+			proto: base.BGP,
+			flags: &UnknownProtoFlags{Flags: 0xff},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			asid, err := UnmarshalAdjacencySIDTLV(tt.input, tt.proto)
+			if err != nil {
+				t.Fatalf("failed to unmarshal Adjacency SID with error: %+v", err)
+			}
+			if diff := deep.Equal(asid.Flags, tt.flags); len(diff) != 0 {
+				t.Fatalf("expected and actual flags do not match, differences: %+v", diff)
+			}
+			f := asid.Flags
+			if f.Backup() != tt.backup || f.ValueFlag() != tt.value || f.LocalFlag() != tt.local || f.Persistent() != tt.persistent {
+				t.Errorf("expected backup %t, value %t, local %t, persistent %t, got %t, %t, %t, %t", tt.backup, tt.value, tt.local,
+					tt.persistent, f.Backup(), f.ValueFlag(), f.LocalFlag(), f.Persistent())
+			}
+		})
+	}
+}
+
+func TestFlagsJSON(t *testing.T) {
+	tests := []struct {
+		name   string
+		flags  interface{}
+		json   string
+		input  string
+		result interface{}
+	}{
+		{
+			name:   "prefix sid isis flags",
+			flags:  &ISISFlags{NFlag: true, LFlag: true},
+			json:   `{"r_flag":false,"n_flag":true,"p_flag":false,"e_flag":false,"v_flag":false,"l_flag":true,"raw":68}`,
+			input:  `{"raw":68}`,
+			result: &ISISFlags{},
+		},
+		{
+			name:   "prefix sid ospf flags",
+			flags:  &OSPFFlags{NPFlag: true},
+			json:   `{"np_flag":true,"m_flag":false,"e_flag":false,"v_flag":false,"l_flag":false,"raw":64}`,
+			input:  `{"np_flag":true}`,
+			result: &OSPFFlags{},
+		},
+		{
+			name:   "adjacency sid isis flags",
+			flags:  &AdjISISFlags{VFlag: true, LFlag: true},
+			json:   `{"f_flag":false,"b_flag":false,"v_flag":true,"l_flag":true,"s_flag":false,"p_flag":false,"raw":48}`,
+			input:  `{"f_flag":true,"raw":48}`,
+			result: &AdjISISFlags{},
+		},
+		{
+			name:   "adjacency sid ospf flags",
+			flags:  &AdjOSPFFlags{VFlag: true, LFlag: true},
+			json:   `{"b_flag":false,"v_flag":true,"l_flag":true,"g_flag":false,"p_flag":false,"raw":96}`,
+			input:  `{"raw":96}`,
+			result: &AdjOSPFFlags{},
+		},
+		{
+			name:   "unknown protocol flags",
+			flags:  &UnknownProtoFlags{Flags: 0xc0},
+			json:   `{"flags":192,"raw":192}`,
+			input:  `{"raw":192}`,
+			result: &UnknownProtoFlags{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := json.Marshal(tt.flags)
+			if err != nil {
+				t.Fatalf("failed to marshal flags with error: %+v", err)
+			}
+			if string(b) != tt.json {
+				t.Errorf("expected json %s does not match marshaled %s", tt.json, string(b))
+			}
+			if err := json.Unmarshal([]byte(tt.input), tt.result); err != nil {
+				t.Fatalf("failed to unmarshal flags with error: %+v", err)
+			}
+			if diff := deep.Equal(tt.result, tt.flags); len(diff) != 0 {
+				t.Errorf("expected and unmarshaled flags do not match, differences: %+v", diff)
+			}
+		})
+	}
+}
@@ -26,6 +26,22 @@ func UnmarshalBGPPeerNodeFlags(b []byte) (*BGPPeerNodeFlags, error) {
 	}, nil
 }
 
+// GetBGPPeerNodeFlagByte returns a byte represenation for BGP Peer Node SID flags
+func (f *BGPPeerNodeFlags) GetBGPPeerNodeFlagByte() byte {
+	b := byte(0)
+	if f.BFlag {
+		b += 0x80
+	}
+	if f.SFlag {
+		b += 0x40
+	}
+	if f.PFlag {
+		b += 0x20
+	}
+
+	return b
+}
+
 // BGPPeerNodeSID defines SRv6 BGP Peer Node SID TLV object
 // No RFC yet
 type BGPPeerNodeSID struct {
@@ -40,6 +56,9 @@ func UnmarshalSRv6BGPPeerNodeSIDTLV(b []byte) (*BGPPeerNodeSID, error) {
 	if glog.V(6) {
 		glog.Infof("SRv6 BGP Peer Node SID TLV Raw: %s", tools.MessageHex(b))
 	}
+	if len(b) < 12 {
+		return nil, fmt.Errorf("not enough bytes to unmarshal SRv6 BGP Peer Node SID TLV")
+	}
 	bgp := BGPPeerNodeSID{}
 	p := 0
 	f, err := UnmarshalBGPPeerNodeFlags(b[p : p+1])
@@ -49,6 +68,7 @@ func UnmarshalSRv6BGPPeerNodeSIDTLV(b []byte) (*BGPPeerNodeSID, error) {
 	bgp.Flags = f
 	p++
 	bgp.Weight = b[p]
+	p++
 	// Skip reserved 2 bytes
 	p += 2
 	bgp.PeerASN = binary.BigEndian.Uint32(b[p : p+4])
@@ -58,3 +78,19 @@ func UnmarshalSRv6BGPPeerNodeSIDTLV(b []byte) (*BGPPeerNodeSID, error) {
 
 	return &bgp, nil
 }
+
+// Marshal returns a wire format representation of SRv6 BGP Peer Node SID TLV value
+func (bgp *BGPPeerNodeSID) Marshal() ([]byte, error) {
+	if len(bgp.PeerID) != 4 {
+		return nil, fmt.Errorf("invalid length %d of BGP Peer ID", len(bgp.PeerID))
+	}
+	b := make([]byte, 8)
+	if bgp.Flags != nil {
+		b[0] = bgp.Flags.GetBGPPeerNodeFlagByte()
+	}
+	b[1] = bgp.Weight
+	// b[2] and b[3] are reserved
+	binary.BigEndian.PutUint32(b[4:8], bgp.PeerASN)
+
+	return append(b, bgp.PeerID...), nil
+}
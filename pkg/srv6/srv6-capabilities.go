@@ -27,3 +27,14 @@ func UnmarshalSRv6CapabilityTLV(b []byte) (*CapabilityTLV, error) {
 
 	return &cap, nil
 }
+
+// Marshal returns a wire format representation of SRv6 Capability TLV value
+func (cap *CapabilityTLV) Marshal() []byte {
+	// 2 bytes of Flags followed by 2 bytes Reserved
+	b := make([]byte, 4)
+	if cap.OFlag {
+		b[0] = 0x40
+	}
+
+	return b
+}
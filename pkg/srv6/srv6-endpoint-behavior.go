@@ -11,6 +11,7 @@ import (
 // No RFC yet
 type EndpointBehavior struct {
 	EndpointBehavior uint16 `json:"endpoint_behavior"`
+	Name             string `json:"endpoint_behavior_name,omitempty"`
 	Flag             uint8  `json:"flag"`
 	Algorithm        uint8  `json:"algo"`
 }
@@ -23,6 +24,7 @@ func UnmarshalSRv6EndpointBehaviorTLV(b []byte) (*EndpointBehavior, error) {
 	e := EndpointBehavior{}
 	p := 0
 	e.EndpointBehavior = binary.BigEndian.Uint16(b[p : p+2])
+	e.Name = BehaviorName(e.EndpointBehavior)
 	p += 2
 	e.Flag = b[p]
 	p++
@@ -30,3 +32,93 @@ func UnmarshalSRv6EndpointBehaviorTLV(b []byte) (*EndpointBehavior, error) {
 
 	return &e, nil
 }
+
+// Marshal returns a wire format representation of SRv6 Endpoint Behavior TLV value
+func (e *EndpointBehavior) Marshal() []byte {
+	b := make([]byte, 4)
+	binary.BigEndian.PutUint16(b[0:2], e.EndpointBehavior)
+	b[2] = e.Flag
+	b[3] = e.Algorithm
+
+	return b
+}
+
+// behaviorNames maps SRv6 Endpoint Behavior code points to their names as registered by IANA in "SRv6 Endpoint
+// Behaviors" registry, RFC 8986, RFC 9433 and RFC 9800. Behaviors with NEXT-CSID flavor are known as uN, uA, uDT4
+// and so on in SRv6 micro SID deployments.
+var behaviorNames = map[uint16]string{
+	1:      "End",
+	2:      "End with PSP",
+	3:      "End with USP",
+	4:      "End with PSP & USP",
+	5:      "End.X",
+	6:      "End.X with PSP",
+	7:      "End.X with USP",
+	8:      "End.X with PSP & USP",
+	9:      "End.T",
+	10:     "End.T with PSP",
+	11:     "End.T with USP",
+	12:     "End.T with PSP & USP",
+	14:     "End.B6.Encaps",
+	15:     "End.BM",
+	16:     "End.DX6",
+	17:     "End.DX4",
+	18:     "End.DT6",
+	19:     "End.DT4",
+	20:     "End.DT46",
+	21:     "End.DX2",
+	22:     "End.DX2V",
+	23:     "End.DT2U",
+	24:     "End.DT2M",
+	27:     "End.B6.Encaps.Red",
+	28:     "End with USD",
+	29:     "End with PSP & USD",
+	30:     "End with USP & USD",
+	31:     "End with PSP, USP & USD",
+	32:     "End.X with USD",
+	33:     "End.X with PSP & USD",
+	34:     "End.X with USP & USD",
+	35:     "End.X with PSP, USP & USD",
+	36:     "End.T with USD",
+	37:     "End.T with PSP & USD",
+	38:     "End.T with USP & USD",
+	39:     "End.T with PSP, USP & USD",
+	42:     "End with NEXT-ONLY-CSID",
+	43:     "End with NEXT-CSID",
+	44:     "End with NEXT-CSID & PSP",
+	45:     "End with NEXT-CSID & USP",
+	46:     "End with NEXT-CSID, PSP & USP",
+	47:     "End with NEXT-CSID & USD",
+	48:     "End with NEXT-CSID, PSP & USD",
+	49:     "End with NEXT-CSID, USP & USD",
+	50:     "End with NEXT-CSID, PSP, USP & USD",
+	51:     "End.X with NEXT-ONLY-CSID",
+	52:     "End.X with NEXT-CSID",
+	53:     "End.X with NEXT-CSID & PSP",
+	54:     "End.X with NEXT-CSID & USP",
+	55:     "End.X with NEXT-CSID, PSP & USP",
+	56:     "End.X with NEXT-CSID & USD",
+	57:     "End.X with NEXT-CSID, PSP & USD",
+	58:     "End.X with NEXT-CSID, USP & USD",
+	59:     "End.X with NEXT-CSID, PSP, USP & USD",
+	60:     "End.DX6 with NEXT-CSID",
+	61:     "End.DX4 with NEXT-CSID",
+	62:     "End.DT6 with NEXT-CSID",
+	63:     "End.DT4 with NEXT-CSID",
+	64:     "End.DT46 with NEXT-CSID",
+	65:     "End.DX2 with NEXT-CSID",
+	66:     "End.DX2V with NEXT-CSID",
+	67:     "End.DT2U with NEXT-CSID",
+	68:     "End.DT2M with NEXT-CSID",
+	69:     "End.M.GTP6.D",
+	70:     "End.M.GTP6.Di",
+	71:     "End.M.GTP6.E",
+	72:     "End.M.GTP4.E",
+	0xffff: "Opaque",
+}
+
+// BehaviorName returns IANA name of SRv6 Endpoint Behavior code point, empty string is returned for reserved
+// and not assigned code points.
+func BehaviorName(behavior uint16) string {
+	return behaviorNames[behavior]
+}
@@ -0,0 +1,46 @@
+package srv6
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestUnmarshalSRv6EndpointBehaviorTLV(t *testing.T) {
+	tests := []struct {
+		name   string
+		input  []byte
+		expect *EndpointBehavior
+	}{
+		{
+			name:   "End.DT4",
+			input:  []byte{0x00, 0x13, 0x00, 0x00},
+			expect: &EndpointBehavior{EndpointBehavior: 19, Name: "End.DT4"},
+		},
+		{
+			name:   "uN",
+			input:  []byte{0x00, 0x2b, 0x00, 0x80},
+			expect: &EndpointBehavior{EndpointBehavior: 43, Name: "End with NEXT-CSID", Algorithm: 128},
+		},
+		{
+			name:   "opaque",
+			input:  []byte{0xff, 0xff, 0x00, 0x00},
+			expect: &EndpointBehavior{EndpointBehavior: 0xffff, Name: "Opaque"},
+		},
+		{
+			name:   "unassigned",
+			input:  []byte{0x10, 0x00, 0x00, 0x00},
+			expect: &EndpointBehavior{EndpointBehavior: 0x1000},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := UnmarshalSRv6EndpointBehaviorTLV(tt.input)
+			if err != nil {
+				t.Fatalf("supposed to succeed but failed with error: %+v", err)
+			}
+			if !reflect.DeepEqual(tt.expect, got) {
+				t.Fatalf("expected %+v, got %+v", tt.expect, got)
+			}
+		})
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"net"
 
 	"github.com/golang/glog"
+	"github.com/sbezverk/gobmp/pkg/addr"
 	"github.com/sbezverk/tools"
 )
 
@@ -39,6 +40,22 @@ func UnmarshalEndXSIDFlags(b []byte) (*EndXSIDFlags, error) {
 	}, nil
 }
 
+// GetEndXSIDFlagByte returns a byte represenation for End.X SID flags
+func (f *EndXSIDFlags) GetEndXSIDFlagByte() byte {
+	b := byte(0)
+	if f.BFlag {
+		b += 0x80
+	}
+	if f.SFlag {
+		b += 0x40
+	}
+	if f.PFlag {
+		b += 0x20
+	}
+
+	return b
+}
+
 // EndXSIDTLV defines SRv6 End.X SID TLV object
 // No RFC yet
 type EndXSIDTLV struct {
@@ -97,7 +114,7 @@ func (e *EndXSIDTLV) UnmarshalJSON(b []byte) error {
 		}
 	}
 	// Weight           uint8         `json:"weight,omitempty"`
-	if v, ok := objVal["weigh"]; ok {
+	if v, ok := objVal["weight"]; ok {
 		if err := json.Unmarshal(v, &result.Weight); err != nil {
 			return err
 		}
@@ -171,7 +188,7 @@ func UnmarshalSRv6EndXSIDTLV(b []byte) (*EndXSIDTLV, error) {
 	if sid.To16() == nil {
 		return nil, fmt.Errorf("invalid sid format")
 	}
-	e.SID = sid.To16().String()
+	e.SID = addr.IPv6(sid)
 	p += 16
 	if len(b) > p {
 		stlvs, err := UnmarshalAllSRv6SubTLV(b[p:])
@@ -183,3 +200,26 @@ func UnmarshalSRv6EndXSIDTLV(b []byte) (*EndXSIDTLV, error) {
 
 	return &e, nil
 }
+
+// Marshal returns a wire format representation of SRv6 End.X SID TLV value including its Sub TLVs
+func (e *EndXSIDTLV) Marshal() ([]byte, error) {
+	sid := net.ParseIP(e.SID)
+	if sid == nil || sid.To16() == nil {
+		return nil, fmt.Errorf("invalid sid %s", e.SID)
+	}
+	b := make([]byte, 6)
+	binary.BigEndian.PutUint16(b[0:2], e.EndpointBehavior)
+	if e.Flags != nil {
+		b[2] = e.Flags.GetEndXSIDFlagByte()
+	}
+	b[3] = e.Algorithm
+	b[4] = e.Weight
+	// b[5] is reserved
+	b = append(b, sid.To16()...)
+	stlvs, err := MarshalAllSRv6SubTLV(e.SubTLVs)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(b, stlvs...), nil
+}
@@ -49,3 +49,43 @@ func TestUnmarshalSRv6EndXSIDTLV(t *testing.T) {
 		})
 	}
 }
+
+func TestMarshalSRv6EndXSIDTLV(t *testing.T) {
+	input := []byte{0x00, 0x06, 0x00, 0x80, 0x00, 0x00, 0x20, 0x01, 0x04, 0x20, 0xFF, 0xFF, 0x10, 0x77, 0x00, 0x40, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x04, 0xE4, 0x00, 0x04, 0x28, 0x18, 0x10, 0x00}
+	e, err := UnmarshalSRv6EndXSIDTLV(input)
+	if err != nil {
+		t.Fatalf("failed to unmarshal End.X SID with error: %+v", err)
+	}
+	result, err := e.Marshal()
+	if err != nil {
+		t.Fatalf("failed to marshal End.X SID with error: %+v", err)
+	}
+	if !reflect.DeepEqual(input, result) {
+		t.Fatalf("expected %+v does not match marshaled %+v", input, result)
+	}
+}
+
+func TestUnmarshalSRv6BGPPeerNodeSIDTLV(t *testing.T) {
+	input := []byte{0x80, 0x0a, 0x00, 0x00, 0x00, 0x00, 0xfd, 0xe9, 0x0a, 0x00, 0x00, 0x02}
+	expect := &BGPPeerNodeSID{
+		Flags:   &BGPPeerNodeFlags{BFlag: true},
+		Weight:  10,
+		PeerASN: 65001,
+		PeerID:  []byte{10, 0, 0, 2},
+	}
+	result, err := UnmarshalSRv6BGPPeerNodeSIDTLV(input)
+	if err != nil {
+		t.Fatalf("failed to unmarshal BGP Peer Node SID with error: %+v", err)
+	}
+	if !reflect.DeepEqual(expect, result) {
+		t.Logf("Differences: %+v", deep.Equal(expect, result))
+		t.Fatalf("Expected object: %+v does not match result: %+v", *expect, *result)
+	}
+	b, err := result.Marshal()
+	if err != nil {
+		t.Fatalf("failed to marshal BGP Peer Node SID with error: %+v", err)
+	}
+	if !reflect.DeepEqual(input, b) {
+		t.Fatalf("expected %+v does not match marshaled %+v", input, b)
+	}
+}
@@ -1,6 +1,44 @@
 package srv6
 
-// L2Service defines SRv6 L2 Service message structure
+import (
+	"fmt"
+
+	"github.com/golang/glog"
+	"github.com/sbezverk/tools"
+)
+
+// L2Service defines SRv6 L2 Service message structure, L2 Service TLV carries the same Sub TLVs as L3 Service TLV
 // https://tools.ietf.org/html/draft-dawra-bess-srv6-services-02#section-2
 type L2Service struct {
+	SubTLVs map[uint8][]SvcSubTLV `json:"sub_tlvs,omitempty"`
+}
+
+// UnmarshalJSON unmarshals a slice of byte into L2Service object
+func (l2s *L2Service) UnmarshalJSON(b []byte) error {
+	l3s := &L3Service{}
+	if err := l3s.UnmarshalJSON(b); err != nil {
+		return err
+	}
+	l2s.SubTLVs = l3s.SubTLVs
+
+	return nil
+}
+
+// UnmarshalSRv6L2Service instantiate from the slice of byte SRv6 L2 Service Object
+func UnmarshalSRv6L2Service(b []byte) (*L2Service, error) {
+	if glog.V(6) {
+		glog.Infof("SRv6 L2 Service Raw: %s", tools.MessageHex(b))
+	}
+	if len(b) == 0 {
+		return nil, fmt.Errorf("not enough bytes to unmarshal SRv6 L2 Service")
+	}
+	// Skipping reserved byte
+	stlv, err := UnmarshalSRv6L3ServiceSubTLV(b[1:])
+	if err != nil {
+		return nil, err
+	}
+
+	return &L2Service{
+		SubTLVs: stlv,
+	}, nil
 }
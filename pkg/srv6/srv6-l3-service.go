@@ -8,6 +8,7 @@ import (
 	"strconv"
 
 	"github.com/golang/glog"
+	"github.com/sbezverk/gobmp/pkg/addr"
 	"github.com/sbezverk/tools"
 )
 
@@ -24,7 +25,9 @@ type SIDStructureSubSubTLV struct {
 
 // UnmarshalSIDStructureSubSubTLV instantiates SID Structure Sub Sub TLV
 func UnmarshalSIDStructureSubSubTLV(b []byte) (*SIDStructureSubSubTLV, error) {
-	// Skip Resrved byte
+	if len(b) != 6 {
+		return nil, fmt.Errorf("invalid length %d of SID Structure Sub Sub TLV", len(b))
+	}
 	p := 0
 	tlv := &SIDStructureSubSubTLV{}
 	tlv.LocalBlockLength = b[p]
@@ -96,10 +99,14 @@ func (istlv *InformationSubTLV) UnmarshalJSON(b []byte) error {
 
 // UnmarshalInformationSubTLV instantiates Information SubT LV
 func UnmarshalInformationSubTLV(b []byte) (*InformationSubTLV, error) {
+	// Reserved byte, SID, Flags, Endpoint Behavior and Reserved byte
+	if len(b) < 21 {
+		return nil, fmt.Errorf("invalid length %d of SRv6 Information Sub TLV", len(b))
+	}
 	// Skip Resrved byte
 	p := 1
 	tlv := &InformationSubTLV{}
-	tlv.SID = net.IP(b[p : p+16]).To16().String()
+	tlv.SID = addr.IPv6(b[p : p+16])
 	p += 16
 	tlv.Flags = b[p]
 	p++
@@ -171,6 +178,9 @@ func UnmarshalSRv6L3Service(b []byte) (*L3Service, error) {
 	if glog.V(6) {
 		glog.Infof("SRv6 L3 Service Raw: %s", tools.MessageHex(b))
 	}
+	if len(b) == 0 {
+		return nil, fmt.Errorf("not enough bytes to unmarshal SRv6 L3 Service")
+	}
 	l3 := L3Service{
 		SubTLVs: make(map[uint8][]SvcSubTLV),
 	}
@@ -189,10 +199,16 @@ func UnmarshalSRv6L3ServiceSubTLV(b []byte) (map[uint8][]SvcSubTLV, error) {
 	m := make(map[uint8][]SvcSubTLV)
 	var err error
 	for p := 0; p < len(b); {
+		if p+3 > len(b) {
+			return nil, fmt.Errorf("not enough bytes to unmarshal SRv6 Service Sub TLV")
+		}
 		t := b[p]
 		p++
 		l := binary.BigEndian.Uint16(b[p : p+2])
 		p += 2
+		if p+int(l) > len(b) {
+			return nil, fmt.Errorf("invalid length %d of SRv6 Service Sub TLV type %d", l, t)
+		}
 		var s SvcSubTLV
 		switch t {
 		case 1:
@@ -220,10 +236,16 @@ func UnmarshalSRv6L3ServiceSubSubTLV(b []byte) (map[uint8][]SvcSubSubTLV, error)
 	var err error
 	m := make(map[uint8][]SvcSubSubTLV)
 	for p := 1; p < len(b); {
+		if p+3 > len(b) {
+			return nil, fmt.Errorf("not enough bytes to unmarshal SRv6 Service Sub Sub TLV")
+		}
 		t := b[p]
 		p++
 		l := binary.BigEndian.Uint16(b[p : p+2])
 		p += 2
+		if p+int(l) > len(b) {
+			return nil, fmt.Errorf("invalid length %d of SRv6 Service Sub Sub TLV type %d", l, t)
+		}
 		var s SvcSubSubTLV
 		switch t {
 		case 1:
@@ -245,3 +267,55 @@ func UnmarshalSRv6L3ServiceSubSubTLV(b []byte) (map[uint8][]SvcSubSubTLV, error)
 	}
 	return m, nil
 }
+
+// SID returns SRv6 SID of the first SRv6 SID Information Sub TLV, when SID Structure Sub Sub TLV carries
+// non zero Transposition Length, the transposed bits are recovered from label field. label is 24 bits
+// label field of NLRI as it is received, transposed bits are the high order bits of the field.
+// https://tools.ietf.org/html/rfc9252#section-4
+func (l3s *L3Service) SID(label uint32) (net.IP, error) {
+	stlvs, ok := l3s.SubTLVs[1]
+	if !ok || len(stlvs) == 0 {
+		return nil, fmt.Errorf("SRv6 SID Information Sub TLV is not found")
+	}
+	info, ok := stlvs[0].(*InformationSubTLV)
+	if !ok {
+		return nil, fmt.Errorf("unexpected type %T of SRv6 SID Information Sub TLV", stlvs[0])
+	}
+	sid := net.ParseIP(info.SID).To16()
+	if sid == nil {
+		return nil, fmt.Errorf("invalid SRv6 SID %s", info.SID)
+	}
+	for _, sstlv := range info.SubSubTLVs[1] {
+		structure, ok := sstlv.(*SIDStructureSubSubTLV)
+		if !ok || structure.TranspositionLength == 0 {
+			continue
+		}
+		return TransposeSID(sid, label, structure.TranspositionOffset, structure.TranspositionLength)
+	}
+
+	return sid, nil
+}
+
+// TransposeSID returns a copy of SRv6 SID with length bits starting at offset bit of SID replaced by
+// the high order bits of 24 bits label field.
+func TransposeSID(sid net.IP, label uint32, offset, length uint8) (net.IP, error) {
+	if len(sid) != net.IPv6len {
+		return nil, fmt.Errorf("invalid SRv6 SID length %d", len(sid))
+	}
+	if length > 24 || int(offset)+int(length) > 128 {
+		return nil, fmt.Errorf("invalid transposition offset %d and length %d", offset, length)
+	}
+	full := make(net.IP, net.IPv6len)
+	copy(full, sid)
+	for i := 0; i < int(length); i++ {
+		bit := int(offset) + i
+		mask := byte(0x80) >> uint(bit%8)
+		if label>>uint(23-i)&0x1 == 1 {
+			full[bit/8] |= mask
+		} else {
+			full[bit/8] &^= mask
+		}
+	}
+
+	return full, nil
+}
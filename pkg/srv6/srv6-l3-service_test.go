@@ -56,3 +56,78 @@ func TestUnmarshalSRv6L3Service(t *testing.T) {
 		})
 	}
 }
+
+func TestL3ServiceSID(t *testing.T) {
+	service := func(tl, to uint8) *L3Service {
+		return &L3Service{
+			SubTLVs: map[uint8][]SvcSubTLV{
+				1: {
+					&InformationSubTLV{
+						SID:              "2001:0:5:3::",
+						EndpointBehavior: 17,
+						SubSubTLVs: map[uint8][]SvcSubSubTLV{
+							1: {
+								&SIDStructureSubSubTLV{
+									LocalBlockLength:    40,
+									LocalNodeLength:     24,
+									FunctionLength:      16,
+									TranspositionLength: tl,
+									TranspositionOffset: to,
+								},
+							},
+						},
+					},
+				},
+			},
+		}
+	}
+	tests := []struct {
+		name    string
+		service *L3Service
+		label   uint32
+		expect  string
+		fail    bool
+	}{
+		{
+			name:    "function transposed into label",
+			service: service(16, 64),
+			// Label field 0x00, 0x10, 0x00
+			label:  0x001000,
+			expect: "2001:0:5:3:10::",
+		},
+		{
+			name:    "no transposition",
+			service: service(0, 0),
+			label:   0x001000,
+			expect:  "2001:0:5:3::",
+		},
+		{
+			name:    "transposition beyond sid",
+			service: service(16, 120),
+			label:   0x001000,
+			fail:    true,
+		},
+		{
+			name:    "no sid information",
+			service: &L3Service{SubTLVs: map[uint8][]SvcSubTLV{}},
+			fail:    true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sid, err := tt.service.SID(tt.label)
+			if err != nil && !tt.fail {
+				t.Fatalf("expected to succeed but failed with error: %+v", err)
+			}
+			if err == nil && tt.fail {
+				t.Fatalf("expected to fail but succeeded")
+			}
+			if err != nil {
+				return
+			}
+			if sid.String() != tt.expect {
+				t.Fatalf("expected sid %s, got %s", tt.expect, sid.String())
+			}
+		})
+	}
+}
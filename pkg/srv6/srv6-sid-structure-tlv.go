@@ -44,6 +44,11 @@ func UnmarshalSRv6SIDStructureTLV(b []byte) (*SIDStructure, error) {
 	return &st, nil
 }
 
+// Marshal returns a wire format representation of SRv6 SID Structure TLV value
+func (s *SIDStructure) Marshal() []byte {
+	return []byte{s.LBLength, s.LNLength, s.FunLength, s.ArgLength}
+}
+
 func UnmarshalJSONSRv6SIDStructureTLV(stlv map[string]json.RawMessage) (*SIDStructure, error) {
 	result := &SIDStructure{}
 	// Type      uint16 `json:"type,omitempty"`
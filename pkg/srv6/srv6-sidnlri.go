@@ -4,9 +4,9 @@ import (
 	"crypto/md5"
 	"encoding/binary"
 	"fmt"
-	"net"
 
 	"github.com/golang/glog"
+	"github.com/sbezverk/gobmp/pkg/addr"
 	"github.com/sbezverk/gobmp/pkg/base"
 	"github.com/sbezverk/tools"
 )
@@ -48,7 +48,7 @@ func (sr *SIDNLRI) GetSRv6SIDLSID() uint32 {
 
 // GetSRv6SIDIGPRouterID returns a value of a local node Descriptor TLV IGP Router ID
 func (sr *SIDNLRI) GetSRv6SIDIGPRouterID() string {
-	return sr.LocalNode.GetIGPRouterID()
+	return sr.LocalNode.GetProtocolIGPRouterID(sr.ProtocolID)
 }
 
 // GetSRv6SIDASN returns Autonomous System Number used to uniqely identify BGP-LS domain
@@ -70,7 +70,7 @@ func (sr *SIDNLRI) GetSRv6SIDMTID() *base.MultiTopologyIdentifier {
 
 // GetSRv6SID returns a slice of SIDs
 func (sr *SIDNLRI) GetSRv6SID() string {
-	return net.IP(sr.SRv6SID.SID).To16().String()
+	return addr.IPv6(sr.SRv6SID.SID)
 }
 
 // UnmarshalSRv6SIDNLRI builds SRv6SIDNLRI NLRI object
@@ -150,3 +150,35 @@ func (u *UnknownSrv6SubTLV) GetType() uint16 {
 func (u *UnknownSrv6SubTLV) GetLen() uint16 {
 	return u.Length
 }
+
+// MarshalSRv6SubTLV returns a wire format representation of SRv6 Sub TLV including type and length.
+func MarshalSRv6SubTLV(s SubTLV) ([]byte, error) {
+	var v []byte
+	switch stlv := s.(type) {
+	case *SIDStructure:
+		v = stlv.Marshal()
+	case *UnknownSrv6SubTLV:
+		v = stlv.Value
+	default:
+		return nil, fmt.Errorf("unsupported SRv6 Sub TLV type %d", s.GetType())
+	}
+	b := make([]byte, 4)
+	binary.BigEndian.PutUint16(b[0:2], s.GetType())
+	binary.BigEndian.PutUint16(b[2:4], uint16(len(v)))
+
+	return append(b, v...), nil
+}
+
+// MarshalAllSRv6SubTLV returns a wire format representation of a slice of SRv6 Sub TLVs.
+func MarshalAllSRv6SubTLV(stlvs []SubTLV) ([]byte, error) {
+	b := make([]byte, 0)
+	for _, stlv := range stlvs {
+		s, err := MarshalSRv6SubTLV(stlv)
+		if err != nil {
+			return nil, err
+		}
+		b = append(b, s...)
+	}
+
+	return b, nil
+}
@@ -0,0 +1,94 @@
+package state
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+)
+
+// ExportFormat defines the format of exported cached prefixes
+type ExportFormat string
+
+const (
+	// ExportJSON writes cached prefixes as newline delimited json messages, as they were published
+	ExportJSON ExportFormat = "json"
+	// ExportMRT writes cached unicast prefixes as MRT TABLE_DUMP_V2 records, RFC 6396
+	ExportMRT ExportFormat = "mrt"
+)
+
+// ribMessage defines fields of cached messages used to select and order exported prefixes
+type ribMessage struct {
+	PeerIP    string `json:"peer_ip"`
+	Prefix    string `json:"prefix"`
+	PrefixLen int32  `json:"prefix_len"`
+	PathID    int32  `json:"path_id"`
+}
+
+type ribExport struct {
+	msgType int
+	msg     ribMessage
+	raw     json.RawMessage
+}
+
+func (s *store) Export(w io.Writer, routerIP, peerIP string, format ExportFormat) error {
+	if format != ExportJSON && format != ExportMRT {
+		return fmt.Errorf("unsupported export format %q", format)
+	}
+	s.Lock()
+	r, ok := s.routers[routerIP]
+	if !ok {
+		s.Unlock()
+		return fmt.Errorf("router %s is not known", routerIP)
+	}
+	entries := make([]*ribExport, 0, len(r.RIB))
+	for _, e := range r.RIB {
+		// Snapshots saved by previous versions do not carry advertisements
+		if e.Message == nil {
+			continue
+		}
+		entries = append(entries, &ribExport{msgType: e.Type, raw: e.Message})
+	}
+	s.Unlock()
+	selected := entries[:0]
+	for _, e := range entries {
+		if err := json.Unmarshal(e.raw, &e.msg); err != nil {
+			continue
+		}
+		if peerIP != "" && e.msg.PeerIP != peerIP {
+			continue
+		}
+		selected = append(selected, e)
+	}
+	sort.Slice(selected, func(i, j int) bool {
+		a, b := selected[i].msg, selected[j].msg
+		if a.Prefix != b.Prefix {
+			return a.Prefix < b.Prefix
+		}
+		if a.PrefixLen != b.PrefixLen {
+			return a.PrefixLen < b.PrefixLen
+		}
+		if a.PeerIP != b.PeerIP {
+			return a.PeerIP < b.PeerIP
+		}
+		return a.PathID < b.PathID
+	})
+	bw := bufio.NewWriter(w)
+	if format == ExportMRT {
+		if err := writeMRT(bw, routerIP, selected); err != nil {
+			return err
+		}
+		return bw.Flush()
+	}
+	for _, e := range selected {
+		if _, err := bw.Write(e.raw); err != nil {
+			return err
+		}
+		if err := bw.WriteByte('\n'); err != nil {
+			return err
+		}
+	}
+
+	return bw.Flush()
+}
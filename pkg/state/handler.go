@@ -0,0 +1,54 @@
+package state
+
+import (
+	"bytes"
+	"net/http"
+	"strings"
+
+	"github.com/golang/glog"
+)
+
+// NewHandler returns http handler exporting cached prefixes of a router:
+//
+//	GET /debug/rib?router={router address}&peer={peer address}&format={json|mrt}
+//
+// peer is optional, when it is not set prefixes of all peers of the router are exported. The default
+// format is json. The response is sent as an attachment, so it can be saved to a file for audits.
+func NewHandler(s Store) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		routerIP := r.URL.Query().Get("router")
+		if routerIP == "" {
+			http.Error(w, "router is not specified", http.StatusBadRequest)
+			return
+		}
+		peerIP := r.URL.Query().Get("peer")
+		format := ExportFormat(r.URL.Query().Get("format"))
+		if format == "" {
+			format = ExportJSON
+		}
+		var b bytes.Buffer
+		if err := s.Export(&b, routerIP, peerIP, format); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		name := []string{"rib", routerIP}
+		if peerIP != "" {
+			name = append(name, peerIP)
+		}
+		// Colons of IPv6 addresses are not valid in file names on all platforms
+		file := strings.ReplaceAll(strings.Join(name, "_"), ":", "-") + "." + string(format)
+		if format == ExportMRT {
+			w.Header().Set("Content-Type", "application/octet-stream")
+		} else {
+			w.Header().Set("Content-Type", "application/x-ndjson")
+		}
+		w.Header().Set("Content-Disposition", "attachment; filename=\""+file+"\"")
+		if _, err := b.WriteTo(w); err != nil {
+			glog.Errorf("failed to send rib export of router %s with error: %+v", routerIP, err)
+		}
+	})
+}
@@ -0,0 +1,348 @@
+package state
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"io"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sbezverk/gobmp/pkg/bgp"
+	"github.com/sbezverk/gobmp/pkg/bmp"
+)
+
+// MRT TABLE_DUMP_V2 type and subtypes, RFC 6396 and RFC 8050
+const (
+	mrtTableDumpV2           = 13
+	mrtPeerIndexTable        = 1
+	mrtRIBIPv4Unicast        = 2
+	mrtRIBIPv6Unicast        = 4
+	mrtRIBIPv4UnicastAddPath = 8
+	mrtRIBIPv6UnicastAddPath = 10
+)
+
+// mrtPrefix defines fields of cached unicast prefixes written to RIB entries
+type mrtPrefix struct {
+	PeerIP           string              `json:"peer_ip"`
+	PeerASN          uint32              `json:"peer_asn"`
+	IsIPv4           bool                `json:"is_ipv4"`
+	SAFI             uint8               `json:"safi"`
+	Nexthop          string              `json:"nexthop"`
+	NexthopLinkLocal string              `json:"nexthop_link_local"`
+	TimestampEpoch   int64               `json:"timestamp_epoch_us"`
+	BaseAttributes   *bgp.BaseAttributes `json:"base_attrs"`
+}
+
+// mrtPeer defines an entry of PEER_INDEX_TABLE
+type mrtPeer struct {
+	ip  net.IP
+	asn uint32
+}
+
+// writeMRT writes PEER_INDEX_TABLE record followed by a RIB record per prefix, only unicast prefixes
+// are written, L3VPN, multicast and labeled unicast prefixes have no TABLE_DUMP_V2 RIB subtype.
+func writeMRT(w io.Writer, routerIP string, entries []*ribExport) error {
+	now := time.Now()
+	prefixes := make([]*mrtPrefix, 0, len(entries))
+	rib := make([]*ribExport, 0, len(entries))
+	peers := make(map[string]*mrtPeer)
+	for _, e := range entries {
+		if e.msgType != bmp.UnicastPrefixMsg && e.msgType != bmp.UnicastPrefixV4Msg && e.msgType != bmp.UnicastPrefixV6Msg {
+			continue
+		}
+		p := &mrtPrefix{}
+		if err := json.Unmarshal(e.raw, p); err != nil || p.SAFI > 1 {
+			continue
+		}
+		ip := net.ParseIP(p.PeerIP)
+		if ip == nil || !validPrefix(e.msg.Prefix, int(e.msg.PrefixLen), p.IsIPv4) {
+			continue
+		}
+		if _, ok := peers[p.PeerIP]; !ok {
+			peers[p.PeerIP] = &mrtPeer{ip: ip, asn: p.PeerASN}
+		}
+		prefixes = append(prefixes, p)
+		rib = append(rib, e)
+	}
+	// Peers are indexed in the order of their addresses
+	addrs := make([]string, 0, len(peers))
+	for addr := range peers {
+		addrs = append(addrs, addr)
+	}
+	sort.Strings(addrs)
+	index := make(map[string]uint16, len(addrs))
+	table := make([]byte, 0)
+	// Collector BGP ID is not known, view name carries the router address
+	table = append(table, 0, 0, 0, 0)
+	table = appendUint16(table, uint16(len(routerIP)))
+	table = append(table, routerIP...)
+	table = appendUint16(table, uint16(len(addrs)))
+	for i, addr := range addrs {
+		index[addr] = uint16(i)
+		p := peers[addr]
+		// Peer AS is always encoded as 4 bytes AS
+		t := byte(0x02)
+		ip := p.ip.To4()
+		if ip == nil {
+			t |= 0x01
+			ip = p.ip.To16()
+		}
+		table = append(table, t, 0, 0, 0, 0)
+		table = append(table, ip...)
+		table = appendUint32(table, p.asn)
+	}
+	if err := writeMRTRecord(w, now, mrtPeerIndexTable, table); err != nil {
+		return err
+	}
+	seq := uint32(0)
+	for i := 0; i < len(rib); {
+		// RIB record carries all entries of the prefix with or without path id
+		j := i + 1
+		for j < len(rib) && rib[j].msg.Prefix == rib[i].msg.Prefix && rib[j].msg.PrefixLen == rib[i].msg.PrefixLen &&
+			(rib[j].msg.PathID != 0) == (rib[i].msg.PathID != 0) {
+			j++
+		}
+		if err := writeMRTRecord(w, now, ribSubtype(prefixes[i].IsIPv4, rib[i].msg.PathID != 0),
+			mrtRIB(seq, rib[i:j], prefixes[i:j], index, now)); err != nil {
+			return err
+		}
+		seq++
+		i = j
+	}
+
+	return nil
+}
+
+// validPrefix returns true when the prefix is an address of the family and the length fits the address
+func validPrefix(prefix string, length int, ipv4 bool) bool {
+	ip := net.ParseIP(prefix)
+	if ip == nil {
+		return false
+	}
+	if ipv4 {
+		return ip.To4() != nil && length >= 0 && length <= 32
+	}
+
+	return ip.To4() == nil && length >= 0 && length <= 128
+}
+
+func ribSubtype(ipv4, addPath bool) uint16 {
+	switch {
+	case ipv4 && addPath:
+		return mrtRIBIPv4UnicastAddPath
+	case ipv4:
+		return mrtRIBIPv4Unicast
+	case addPath:
+		return mrtRIBIPv6UnicastAddPath
+	}
+
+	return mrtRIBIPv6Unicast
+}
+
+// mrtRIB returns the body of RIB record of a prefix
+func mrtRIB(seq uint32, rib []*ribExport, prefixes []*mrtPrefix, index map[string]uint16, now time.Time) []byte {
+	b := appendUint32(nil, seq)
+	ip := net.ParseIP(rib[0].msg.Prefix)
+	if prefixes[0].IsIPv4 {
+		ip = ip.To4()
+	}
+	l := int(rib[0].msg.PrefixLen)
+	b = append(b, byte(l))
+	b = append(b, ip[:(l+7)/8]...)
+	b = appendUint16(b, uint16(len(rib)))
+	for i, e := range rib {
+		p := prefixes[i]
+		originated := now.Unix()
+		if p.TimestampEpoch != 0 {
+			originated = p.TimestampEpoch / int64(time.Second/time.Microsecond)
+		}
+		b = appendUint16(b, index[p.PeerIP])
+		b = appendUint32(b, uint32(originated))
+		if e.msg.PathID != 0 {
+			b = appendUint32(b, uint32(e.msg.PathID))
+		}
+		attrs := mrtAttributes(p)
+		b = appendUint16(b, uint16(len(attrs)))
+		b = append(b, attrs...)
+	}
+
+	return b
+}
+
+// mrtAttributes encodes path attributes of the prefix, MP_REACH_NLRI of IPv6 prefixes carries only the next hop
+// as per RFC 6396 Section 4.3.4. Attributes which are not decoded by gobmp into base attributes are not written.
+func mrtAttributes(p *mrtPrefix) []byte {
+	b := make([]byte, 0)
+	ba := p.BaseAttributes
+	if ba == nil {
+		ba = &bgp.BaseAttributes{}
+	}
+	switch ba.Origin {
+	case "igp":
+		b = appendAttribute(b, 0x40, 1, []byte{0})
+	case "egp":
+		b = appendAttribute(b, 0x40, 1, []byte{1})
+	case "incomplete":
+		b = appendAttribute(b, 0x40, 1, []byte{2})
+	}
+	b = appendAttribute(b, 0x40, 2, asPath(ba))
+	if p.IsIPv4 {
+		if nh := net.ParseIP(p.Nexthop).To4(); nh != nil {
+			b = appendAttribute(b, 0x40, 3, nh)
+		}
+	}
+	if ba.MED != 0 {
+		b = appendAttribute(b, 0x80, 4, appendUint32(nil, ba.MED))
+	}
+	if ba.LocalPref != 0 {
+		b = appendAttribute(b, 0x40, 5, appendUint32(nil, ba.LocalPref))
+	}
+	if ba.IsAtomicAgg {
+		b = appendAttribute(b, 0x40, 6, nil)
+	}
+	if agg := net.ParseIP(ba.AggregatorAddress).To4(); agg != nil {
+		b = appendAttribute(b, 0xc0, 7, append(appendUint32(nil, ba.AggregatorAS), agg...))
+	}
+	if v := communities(ba.CommunityList, 2); len(v) != 0 {
+		b = appendAttribute(b, 0xc0, 8, v)
+	}
+	if id := net.ParseIP(ba.OriginatorID).To4(); id != nil {
+		b = appendAttribute(b, 0x80, 9, id)
+	}
+	if len(ba.ClusterIDs) != 0 {
+		v := make([]byte, 0, 4*len(ba.ClusterIDs))
+		for _, id := range ba.ClusterIDs {
+			if ip := net.ParseIP(id).To4(); ip != nil {
+				v = append(v, ip...)
+			}
+		}
+		b = appendAttribute(b, 0x80, 10, v)
+	}
+	if !p.IsIPv4 {
+		if nh := net.ParseIP(p.Nexthop).To16(); nh != nil {
+			v := append([]byte{16}, nh...)
+			if ll := net.ParseIP(p.NexthopLinkLocal).To16(); ll != nil {
+				v[0] = 32
+				v = append(v, ll...)
+			}
+			b = appendAttribute(b, 0x80, 14, v)
+		}
+	}
+	if v := communities(ba.LgCommunityList, 3); len(v) != 0 {
+		b = appendAttribute(b, 0xc0, 32, v)
+	}
+
+	return b
+}
+
+// asPathSegmentCodes maps json names of AS_PATH segment types to their codes
+var asPathSegmentCodes = map[string]byte{
+	"as_set":             bgp.ASSet,
+	"as_sequence":        bgp.ASSequence,
+	"as_confed_sequence": bgp.ASConfedSequence,
+	"as_confed_set":      bgp.ASConfedSet,
+}
+
+// asPath encodes AS_PATH with 4 bytes ASes, when segments are not known the path is encoded as AS_SEQUENCE
+func asPath(ba *bgp.BaseAttributes) []byte {
+	segments := ba.ASPathSegments
+	if len(segments) == 0 && len(ba.ASPath) != 0 {
+		segments = []bgp.ASPathSegment{{Type: "as_sequence", ASN: ba.ASPath}}
+	}
+	b := make([]byte, 0)
+	for _, s := range segments {
+		t, ok := asPathSegmentCodes[s.Type]
+		if !ok {
+			continue
+		}
+		// Segment carries at most 255 ASes
+		for asn := s.ASN; len(asn) != 0; {
+			n := len(asn)
+			if n > 255 {
+				n = 255
+			}
+			b = append(b, t, byte(n))
+			for _, as := range asn[:n] {
+				b = appendUint32(b, as)
+			}
+			asn = asn[n:]
+		}
+	}
+
+	return b
+}
+
+// communities encodes communities or large communities of n colon separated numbers
+func communities(list []string, n int) []byte {
+	b := make([]byte, 0)
+	for _, c := range list {
+		parts := strings.Split(c, ":")
+		if len(parts) != n {
+			continue
+		}
+		v := make([]uint64, n)
+		valid := true
+		for i, p := range parts {
+			bits := 32
+			if n == 2 {
+				bits = 16
+			}
+			x, err := strconv.ParseUint(p, 10, bits)
+			if err != nil {
+				valid = false
+				break
+			}
+			v[i] = x
+		}
+		if !valid {
+			continue
+		}
+		for _, x := range v {
+			if n == 2 {
+				b = appendUint16(b, uint16(x))
+			} else {
+				b = appendUint32(b, uint32(x))
+			}
+		}
+	}
+
+	return b
+}
+
+func appendAttribute(b []byte, flags, t byte, v []byte) []byte {
+	if len(v) > 255 {
+		b = append(b, flags|0x10, t)
+		b = appendUint16(b, uint16(len(v)))
+	} else {
+		b = append(b, flags, t, byte(len(v)))
+	}
+
+	return append(b, v...)
+}
+
+func writeMRTRecord(w io.Writer, t time.Time, subtype uint16, body []byte) error {
+	h := appendUint32(nil, uint32(t.Unix()))
+	h = appendUint16(h, mrtTableDumpV2)
+	h = appendUint16(h, subtype)
+	h = appendUint32(h, uint32(len(body)))
+	if _, err := w.Write(h); err != nil {
+		return err
+	}
+	_, err := w.Write(body)
+
+	return err
+}
+
+func appendUint16(b []byte, v uint16) []byte {
+	return append(b, byte(v>>8), byte(v))
+}
+
+func appendUint32(b []byte, v uint32) []byte {
+	var x [4]byte
+	binary.BigEndian.PutUint32(x[:], v)
+
+	return append(b, x[:]...)
+}
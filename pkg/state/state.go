@@ -0,0 +1,432 @@
+package state
+
+import (
+	"bufio"
+	"crypto/md5"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/sbezverk/gobmp/pkg/bmp"
+	"github.com/sbezverk/gobmp/pkg/pub"
+)
+
+// SessionState defines the state of BMP session of a router which is preserved across gobmp restarts
+type SessionState struct {
+	SessionID string         `json:"session_id,omitempty"`
+	Sequence  map[string]int `json:"sequence,omitempty"`
+}
+
+// Store defines methods of the state store, the store is a Publisher which maintains the cache of published
+// unicast and L3VPN prefixes per router, the cache and the state of BMP sessions are periodically saved
+// to the snapshot file and restored on start.
+type Store interface {
+	pub.Publisher
+	// Session registers BMP session of the router, f is called on every snapshot to get the current state
+	// of the session. The state saved by the previous session of the router is returned, nil is returned
+	// when the router is not known.
+	Session(routerIP string, f func() *SessionState) *SessionState
+	// Export writes cached prefixes of the router in the format to w, when peerIP is not empty only prefixes
+	// of the peer are written.
+	Export(w io.Writer, routerIP, peerIP string, format ExportFormat) error
+}
+
+// cached defines message types maintained in the cache, they carry the bulk of initial tables
+var cached = map[int]bool{
+	bmp.UnicastPrefixMsg:   true,
+	bmp.UnicastPrefixV4Msg: true,
+	bmp.UnicastPrefixV6Msg: true,
+	bmp.L3VPNMsg:           true,
+	bmp.L3VPNV4Msg:         true,
+	bmp.L3VPNV6Msg:         true,
+}
+
+// volatile defines message fields which differ between advertisements of the same prefix and are not
+// a part of the prefix fingerprint.
+var volatile = []string{
+	"timestamp",
+	"timestamp_epoch_us",
+	"collector_timestamp",
+	"collector_timestamp_epoch_us",
+	"sequence",
+	"session_id",
+}
+
+// withdrawn defines message fields which are not carried by withdrawals
+var withdrawn = []string{
+	"base_attrs",
+	"prefix_sid",
+	"enrichment",
+}
+
+type ribEntry struct {
+	Type        int    `json:"type"`
+	Key         []byte `json:"key,omitempty"`
+	Fingerprint string `json:"fingerprint"`
+	// Withdraw is the withdrawal of the prefix carried by snapshots saved by previous versions, which do not
+	// carry advertisements, the withdrawal is derived from Message otherwise.
+	Withdraw json.RawMessage `json:"withdraw,omitempty"`
+	// Message is the last published advertisement of the prefix
+	Message json.RawMessage `json:"message,omitempty"`
+	// refreshed is set when the prefix is advertised during resync
+	refreshed bool
+}
+
+type router struct {
+	Session *SessionState        `json:"session,omitempty"`
+	RIB     map[string]*ribEntry `json:"rib,omitempty"`
+	// session returns the current state of the registered BMP session
+	session func() *SessionState
+	// resync is not nil while the router re-sends its tables
+	resync *time.Timer
+}
+
+type snapshot struct {
+	Routers map[string]*router `json:"routers"`
+}
+
+type store struct {
+	sync.Mutex
+	publisher pub.Publisher
+	file      string
+	window    time.Duration
+	routers   map[string]*router
+	stop      chan struct{}
+	done      chan struct{}
+}
+
+var _ Store = &store{}
+
+func (s *store) PublishMessage(msgType int, msgHash []byte, msg []byte) error {
+	if s.suppress(msgType, msgHash, msg) {
+		return nil
+	}
+
+	return s.publisher.PublishMessage(msgType, msgHash, msg)
+}
+
+// PublishUpdate publishes messages of a single BGP Update which are not suppressed
+func (s *store) PublishUpdate(msgType int, msgHash []byte, msgs [][]byte) error {
+	publish := make([][]byte, 0, len(msgs))
+	for _, msg := range msgs {
+		if !s.suppress(msgType, msgHash, msg) {
+			publish = append(publish, msg)
+		}
+	}
+	if len(publish) == 0 {
+		return nil
+	}
+
+	return pub.PublishUpdate(s.publisher, msgType, msgHash, publish)
+}
+
+// suppress caches the message and returns true when the prefix has not changed while gobmp or BMP session
+// was down, such message is not published again.
+func (s *store) suppress(msgType int, msgHash []byte, msg []byte) bool {
+	if !cached[msgType] {
+		return false
+	}
+	m := make(map[string]interface{})
+	if err := json.Unmarshal(msg, &m); err != nil {
+		return false
+	}
+	routerIP, _ := m["router_ip"].(string)
+	hash, _ := m["hash"].(string)
+	action, _ := m["action"].(string)
+	if routerIP == "" || hash == "" {
+		return false
+	}
+	s.Lock()
+	defer s.Unlock()
+	r, ok := s.routers[routerIP]
+	if !ok {
+		r = &router{}
+		s.routers[routerIP] = r
+	}
+	if r.RIB == nil {
+		r.RIB = make(map[string]*ribEntry)
+	}
+	if action == "del" {
+		delete(r.RIB, hash)
+		return false
+	}
+	fp, err := fingerprint(m)
+	if err != nil {
+		return false
+	}
+	if e, ok := r.RIB[hash]; ok && r.resync != nil && e.Fingerprint == fp {
+		e.refreshed = true
+		return true
+	}
+	r.RIB[hash] = &ribEntry{
+		Type:        msgType,
+		Key:         msgHash,
+		Fingerprint: fp,
+		Message:     msg,
+		refreshed:   true,
+	}
+
+	return false
+}
+
+// fingerprint returns md5 hash of the message without volatile fields
+func fingerprint(m map[string]interface{}) (string, error) {
+	for _, f := range volatile {
+		delete(m, f)
+	}
+	b, err := json.Marshal(m)
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("%x", md5.Sum(b)), nil
+}
+
+// withdrawal returns the withdrawal of the cached prefix, it is the advertisement without volatile fields
+// and fields which are not carried by withdrawals.
+func (e *ribEntry) withdrawal() (map[string]interface{}, error) {
+	m := make(map[string]interface{})
+	if e.Message == nil {
+		return m, json.Unmarshal(e.Withdraw, &m)
+	}
+	if err := json.Unmarshal(e.Message, &m); err != nil {
+		return nil, err
+	}
+	for _, f := range volatile {
+		delete(m, f)
+	}
+	for _, f := range withdrawn {
+		delete(m, f)
+	}
+	m["action"] = "del"
+
+	return m, nil
+}
+
+func (s *store) Session(routerIP string, f func() *SessionState) *SessionState {
+	s.Lock()
+	defer s.Unlock()
+	r, ok := s.routers[routerIP]
+	if !ok {
+		s.routers[routerIP] = &router{session: f}
+		return nil
+	}
+	restored := r.Session
+	r.session = f
+	if len(r.RIB) != 0 && r.resync == nil {
+		for _, e := range r.RIB {
+			e.refreshed = false
+		}
+		glog.Infof("router %s resync of %d cached prefixes started", routerIP, len(r.RIB))
+		r.resync = time.AfterFunc(s.window, func() { s.endResync(routerIP) })
+	}
+
+	return restored
+}
+
+// endResync withdraws all cached prefixes of the router which have not been advertised during resync
+func (s *store) endResync(routerIP string) {
+	s.Lock()
+	r, ok := s.routers[routerIP]
+	if !ok {
+		s.Unlock()
+		return
+	}
+	r.resync = nil
+	stale := make([]*ribEntry, 0)
+	for hash, e := range r.RIB {
+		if !e.refreshed {
+			stale = append(stale, e)
+			delete(r.RIB, hash)
+		}
+	}
+	s.Unlock()
+	glog.Infof("router %s resync completed, withdrawing %d stale prefixes", routerIP, len(stale))
+	now := time.Now()
+	for _, e := range stale {
+		m, err := e.withdrawal()
+		if err != nil {
+			continue
+		}
+		m["collector_timestamp"] = now.UTC().Format(time.RFC3339Nano)
+		m["collector_timestamp_epoch_us"] = now.UnixNano() / int64(time.Microsecond)
+		b, err := json.Marshal(m)
+		if err != nil {
+			continue
+		}
+		if err := s.publisher.PublishMessage(e.Type, e.Key, b); err != nil {
+			glog.Errorf("failed to publish withdrawal of stale prefix of router %s with error: %+v", routerIP, err)
+		}
+	}
+}
+
+// router returns a copy of the router carrying the current state of BMP session and references to cached
+// entries, entries are replaced and not modified by published messages, so the copy is marshaled unlocked.
+func (s *store) router(routerIP string) (*router, bool) {
+	s.Lock()
+	defer s.Unlock()
+	r, ok := s.routers[routerIP]
+	if !ok {
+		return nil, false
+	}
+	if r.session != nil {
+		r.Session = r.session()
+	}
+	c := &router{Session: r.Session}
+	if len(r.RIB) != 0 {
+		c.RIB = make(map[string]*ribEntry, len(r.RIB))
+		for hash, e := range r.RIB {
+			c.RIB[hash] = e
+		}
+	}
+
+	return c, true
+}
+
+// write writes the snapshot router by router, the store is locked only while references to cached entries
+// of a router are copied, so saving large tables does not stall publishing of messages.
+func (s *store) write(w io.Writer) error {
+	s.Lock()
+	routers := make([]string, 0, len(s.routers))
+	for ip := range s.routers {
+		routers = append(routers, ip)
+	}
+	s.Unlock()
+	if _, err := io.WriteString(w, `{"routers":{`); err != nil {
+		return err
+	}
+	first := true
+	for _, ip := range routers {
+		r, ok := s.router(ip)
+		if !ok {
+			// Router is gone since the list was taken
+			continue
+		}
+		k, err := json.Marshal(ip)
+		if err != nil {
+			return err
+		}
+		b, err := json.Marshal(r)
+		if err != nil {
+			return err
+		}
+		if !first {
+			if _, err := io.WriteString(w, ","); err != nil {
+				return err
+			}
+		}
+		first = false
+		if _, err := w.Write(append(append(k, ':'), b...)); err != nil {
+			return err
+		}
+	}
+	_, err := io.WriteString(w, "}}")
+
+	return err
+}
+
+// save writes the snapshot to a temporary file which then replaces the snapshot file
+func (s *store) save() error {
+	tmp := s.file + ".tmp"
+	f, err := os.Create(tmp)
+	if err != nil {
+		return fmt.Errorf("failed to create snapshot %s with error: %+v", tmp, err)
+	}
+	w := bufio.NewWriter(f)
+	if err = s.write(w); err == nil {
+		err = w.Flush()
+	}
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return fmt.Errorf("failed to write snapshot to %s with error: %+v", tmp, err)
+	}
+	if err := os.Rename(tmp, s.file); err != nil {
+		return fmt.Errorf("failed to replace snapshot %s with error: %+v", s.file, err)
+	}
+
+	return nil
+}
+
+func (s *store) saver(interval time.Duration) {
+	defer close(s.done)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			if err := s.save(); err != nil {
+				glog.Errorf("%+v", err)
+			}
+		case <-s.stop:
+			return
+		}
+	}
+}
+
+func (s *store) Stop() {
+	close(s.stop)
+	<-s.done
+	s.Lock()
+	for _, r := range s.routers {
+		if r.resync != nil {
+			r.resync.Stop()
+			r.resync = nil
+		}
+	}
+	s.Unlock()
+	if err := s.save(); err != nil {
+		glog.Errorf("%+v", err)
+	}
+	s.publisher.Stop()
+}
+
+// load restores the snapshot from the file, a missing file is not an error
+func (s *store) load() error {
+	b, err := ioutil.ReadFile(s.file)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to read snapshot %s with error: %+v", s.file, err)
+	}
+	snap := &snapshot{}
+	if err := json.Unmarshal(b, snap); err != nil {
+		return fmt.Errorf("failed to unmarshal snapshot %s with error: %+v", s.file, err)
+	}
+	if snap.Routers != nil {
+		s.routers = snap.Routers
+	}
+
+	return nil
+}
+
+// NewStore returns a Store publishing messages to publisher, the snapshot is restored from file and saved
+// every interval. When a known router establishes BMP session, cached prefixes which are advertised
+// again unchanged within window are not published, cached prefixes not advertised within window
+// are withdrawn.
+func NewStore(file string, interval, window time.Duration, publisher pub.Publisher) (Store, error) {
+	if interval <= 0 {
+		return nil, fmt.Errorf("invalid snapshot interval %s", interval)
+	}
+	s := &store{
+		publisher: publisher,
+		file:      file,
+		window:    window,
+		routers:   make(map[string]*router),
+		stop:      make(chan struct{}),
+		done:      make(chan struct{}),
+	}
+	if err := s.load(); err != nil {
+		return nil, err
+	}
+	go s.saver(interval)
+
+	return s, nil
+}
@@ -0,0 +1,255 @@
+package state
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-test/deep"
+	"github.com/sbezverk/gobmp/pkg/bmp"
+)
+
+// collector is a Publisher collecting published messages
+type collector struct {
+	msgs []map[string]interface{}
+}
+
+func (c *collector) PublishMessage(msgType int, msgHash []byte, msg []byte) error {
+	m := make(map[string]interface{})
+	if err := json.Unmarshal(msg, &m); err != nil {
+		return err
+	}
+	c.msgs = append(c.msgs, m)
+
+	return nil
+}
+
+func (c *collector) Stop() {}
+
+func prefix(hash, nexthop string, sequence int) []byte {
+	return []byte(fmt.Sprintf(`{"action":"add","router_ip":"192.0.2.1","peer_ip":"192.0.2.2","hash":"%s",`+
+		`"prefix":"10.0.0.0","prefix_len":24,"nexthop":"%s","base_attrs":{"local_pref":100},`+
+		`"timestamp":"2026-10-16T12:00:%02dZ","sequence":%d}`, hash, nexthop, sequence, sequence))
+}
+
+func TestStore(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "state.json")
+	c := &collector{}
+	s, err := NewStore(file, time.Hour, time.Hour, c)
+	if err != nil {
+		t.Fatalf("failed to create store with error: %+v", err)
+	}
+	seq := &SessionState{SessionID: "0123456789abcdef", Sequence: map[string]int{"peer": 4}}
+	if restored := s.Session("192.0.2.1", func() *SessionState { return seq }); restored != nil {
+		t.Fatalf("expected unknown router, got restored state %+v", restored)
+	}
+	for _, msg := range [][]byte{prefix("a", "192.0.2.2", 1), prefix("b", "192.0.2.2", 2), prefix("d", "192.0.2.2", 3)} {
+		if err := s.PublishMessage(bmp.UnicastPrefixV4Msg, []byte("router"), msg); err != nil {
+			t.Fatalf("failed to publish with error: %+v", err)
+		}
+	}
+	// Peer messages are not cached
+	if err := s.PublishMessage(bmp.PeerStateChangeMsg, nil, []byte(`{"action":"up","router_ip":"192.0.2.1"}`)); err != nil {
+		t.Fatalf("failed to publish with error: %+v", err)
+	}
+	s.Stop()
+	if len(c.msgs) != 4 {
+		t.Fatalf("expected 4 published messages, got %d", len(c.msgs))
+	}
+
+	// Restarted store resumes the session and suppresses unchanged prefixes
+	c = &collector{}
+	s, err = NewStore(file, time.Hour, time.Hour, c)
+	if err != nil {
+		t.Fatalf("failed to restore store with error: %+v", err)
+	}
+	restored := s.Session("192.0.2.1", func() *SessionState { return &SessionState{} })
+	if !reflect.DeepEqual(restored, seq) {
+		t.Logf("Differences: %+v", deep.Equal(restored, seq))
+		t.Fatalf("restored session state does not match saved state")
+	}
+	for _, msg := range [][]byte{prefix("a", "192.0.2.2", 5), prefix("b", "192.0.2.3", 6), prefix("c", "192.0.2.2", 7)} {
+		if err := s.PublishMessage(bmp.UnicastPrefixV4Msg, []byte("router"), msg); err != nil {
+			t.Fatalf("failed to publish with error: %+v", err)
+		}
+	}
+	if len(c.msgs) != 2 || c.msgs[0]["hash"] != "b" || c.msgs[1]["hash"] != "c" {
+		t.Fatalf("expected changed prefix b and new prefix c to be published, got %+v", c.msgs)
+	}
+	// Prefix d was not re-sent, it is withdrawn when resync completes
+	s.(*store).endResync("192.0.2.1")
+	if len(c.msgs) != 3 {
+		t.Fatalf("expected withdrawal of stale prefix, got %+v", c.msgs)
+	}
+	w := c.msgs[2]
+	if w["hash"] != "d" || w["action"] != "del" || w["base_attrs"] != nil || w["collector_timestamp"] == nil {
+		t.Errorf("invalid withdrawal of stale prefix %+v", w)
+	}
+	// After resync, unchanged prefixes are published
+	if err := s.PublishMessage(bmp.UnicastPrefixV4Msg, []byte("router"), prefix("a", "192.0.2.2", 8)); err != nil {
+		t.Fatalf("failed to publish with error: %+v", err)
+	}
+	if len(c.msgs) != 4 {
+		t.Errorf("expected prefix to be published after resync, got %d messages", len(c.msgs))
+	}
+	s.Stop()
+}
+
+func unicast(peerIP string, peerASN int, prefix string, length int, nexthop string) []byte {
+	return []byte(fmt.Sprintf(`{"action":"add","router_ip":"192.0.2.1","peer_ip":"%s","peer_asn":%d,"hash":"%s",`+
+		`"prefix":"%s","prefix_len":%d,"is_ipv4":%t,"nexthop":"%s","timestamp_epoch_us":1000000,`+
+		`"base_attrs":{"origin":"igp","as_path":[%d],"local_pref":100}}`,
+		peerIP, peerASN, peerIP+prefix, prefix, length, !strings.Contains(prefix, ":"), nexthop, peerASN))
+}
+
+func TestExport(t *testing.T) {
+	s, err := NewStore(filepath.Join(t.TempDir(), "state.json"), time.Hour, time.Hour, &collector{})
+	if err != nil {
+		t.Fatalf("failed to create store with error: %+v", err)
+	}
+	defer s.Stop()
+	for _, msg := range [][]byte{
+		unicast("192.0.2.2", 65002, "10.0.0.0", 24, "192.0.2.2"),
+		unicast("192.0.2.3", 65003, "10.0.0.0", 24, "192.0.2.3"),
+		unicast("192.0.2.3", 65003, "2001:db8::", 32, "2001:db8::3"),
+	} {
+		if err := s.PublishMessage(bmp.UnicastPrefixMsg, []byte("router"), msg); err != nil {
+			t.Fatalf("failed to publish with error: %+v", err)
+		}
+	}
+	var b bytes.Buffer
+	if err := s.Export(&b, "192.0.2.1", "192.0.2.3", ExportJSON); err != nil {
+		t.Fatalf("failed to export with error: %+v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(b.String()), "\n")
+	if len(lines) != 2 || !strings.Contains(lines[0], `"prefix":"10.0.0.0"`) || !strings.Contains(lines[1], `"prefix":"2001:db8::"`) {
+		t.Fatalf("invalid json export of peer 192.0.2.3: %s", b.String())
+	}
+	if err := s.Export(&b, "192.0.2.9", "", ExportJSON); err == nil {
+		t.Fatalf("export of unknown router supposed to fail but succeeded")
+	}
+
+	b.Reset()
+	if err := s.Export(&b, "192.0.2.1", "", ExportMRT); err != nil {
+		t.Fatalf("failed to export with error: %+v", err)
+	}
+	records := make(map[uint16][][]byte)
+	subtypes := make([]uint16, 0)
+	for p := b.Bytes(); len(p) != 0; {
+		if len(p) < 12 || binary.BigEndian.Uint16(p[4:6]) != mrtTableDumpV2 {
+			t.Fatalf("invalid mrt record header %x", p)
+		}
+		subtype, l := binary.BigEndian.Uint16(p[6:8]), int(binary.BigEndian.Uint32(p[8:12]))
+		records[subtype] = append(records[subtype], p[12:12+l])
+		subtypes = append(subtypes, subtype)
+		p = p[12+l:]
+	}
+	if !reflect.DeepEqual(subtypes, []uint16{mrtPeerIndexTable, mrtRIBIPv4Unicast, mrtRIBIPv6Unicast}) {
+		t.Fatalf("expected peer index table, ipv4 and ipv6 rib records, got subtypes %+v", subtypes)
+	}
+	peerIndex := []byte{0, 0, 0, 0, 0, 9, '1', '9', '2', '.', '0', '.', '2', '.', '1', 0, 2,
+		0x02, 0, 0, 0, 0, 192, 0, 2, 2, 0, 0, 0xfd, 0xea,
+		0x02, 0, 0, 0, 0, 192, 0, 2, 3, 0, 0, 0xfd, 0xeb}
+	if !bytes.Equal(records[mrtPeerIndexTable][0], peerIndex) {
+		t.Errorf("expected peer index table %x does not match exported %x", peerIndex, records[mrtPeerIndexTable][0])
+	}
+	entry := func(peer uint16, asn byte, nexthop byte) []byte {
+		return []byte{0, byte(peer), 0, 0, 0, 1, 0, 27,
+			0x40, 1, 1, 0,
+			0x40, 2, 6, 2, 1, 0, 0, 0xfd, asn,
+			0x40, 3, 4, 192, 0, 2, nexthop,
+			0x40, 5, 4, 0, 0, 0, 100}
+	}
+	rib := append([]byte{0, 0, 0, 0, 24, 10, 0, 0, 0, 2}, append(entry(0, 0xea, 2), entry(1, 0xeb, 3)...)...)
+	if !bytes.Equal(records[mrtRIBIPv4Unicast][0], rib) {
+		t.Errorf("expected ipv4 rib record %x does not match exported %x", rib, records[mrtRIBIPv4Unicast][0])
+	}
+	// IPv6 next hop is carried in MP_REACH_NLRI
+	if v6 := records[mrtRIBIPv6Unicast][0]; !bytes.Contains(v6, []byte{0x80, 14, 17, 16, 0x20, 0x01, 0x0d, 0xb8}) {
+		t.Errorf("ipv6 rib record %x does not carry MP_REACH_NLRI next hop", v6)
+	}
+}
+
+func TestHandler(t *testing.T) {
+	s, err := NewStore(filepath.Join(t.TempDir(), "state.json"), time.Hour, time.Hour, &collector{})
+	if err != nil {
+		t.Fatalf("failed to create store with error: %+v", err)
+	}
+	defer s.Stop()
+	if err := s.PublishMessage(bmp.UnicastPrefixMsg, []byte("router"), unicast("192.0.2.2", 65002, "10.0.0.0", 24, "192.0.2.2")); err != nil {
+		t.Fatalf("failed to publish with error: %+v", err)
+	}
+	h := NewHandler(s)
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/debug/rib?router=192.0.2.1&peer=192.0.2.2&format=mrt", nil))
+	if w.Code != http.StatusOK || w.Header().Get("Content-Disposition") != `attachment; filename="rib_192.0.2.1_192.0.2.2.mrt"` {
+		t.Errorf("unexpected response %d %+v", w.Code, w.Header())
+	}
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/debug/rib?router=192.0.2.1&format=bgpdump", nil))
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected bad request for unsupported format, got %d", w.Code)
+	}
+}
+
+func TestSnapshot(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "state.json")
+	// Snapshots saved by previous versions carry withdrawals of prefixes without advertisements
+	legacy := `{"routers":{"192.0.2.1":{"rib":{"d":{"type":` + fmt.Sprint(bmp.UnicastPrefixV4Msg) + `,"fingerprint":"f",` +
+		`"withdraw":{"action":"del","router_ip":"192.0.2.1","hash":"d","prefix":"10.0.0.0","prefix_len":24}}}}}}`
+	if err := ioutil.WriteFile(file, []byte(legacy), 0644); err != nil {
+		t.Fatalf("failed to write snapshot with error: %+v", err)
+	}
+	c := &collector{}
+	s, err := NewStore(file, time.Hour, time.Hour, c)
+	if err != nil {
+		t.Fatalf("failed to restore store with error: %+v", err)
+	}
+	s.Session("192.0.2.1", func() *SessionState { return &SessionState{SessionID: "s"} })
+	s.(*store).endResync("192.0.2.1")
+	if len(c.msgs) != 1 || c.msgs[0]["hash"] != "d" || c.msgs[0]["action"] != "del" {
+		t.Fatalf("expected withdrawal of stale prefix of legacy snapshot, got %+v", c.msgs)
+	}
+	// Messages are published while the snapshot is saved
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 100; i++ {
+			if err := s.PublishMessage(bmp.UnicastPrefixV4Msg, nil, prefix(fmt.Sprint(i), "192.0.2.2", i%60)); err != nil {
+				t.Errorf("failed to publish with error: %+v", err)
+			}
+		}
+	}()
+	for i := 0; i < 10; i++ {
+		if err := s.(*store).save(); err != nil {
+			t.Fatalf("failed to save snapshot with error: %+v", err)
+		}
+	}
+	<-done
+	s.Stop()
+	b, err := ioutil.ReadFile(file)
+	if err != nil {
+		t.Fatalf("failed to read snapshot with error: %+v", err)
+	}
+	snap := &snapshot{}
+	if err := json.Unmarshal(b, snap); err != nil {
+		t.Fatalf("failed to unmarshal snapshot %s with error: %+v", string(b), err)
+	}
+	r := snap.Routers["192.0.2.1"]
+	if r == nil || len(r.RIB) != 100 || r.Session.SessionID != "s" {
+		t.Fatalf("expected snapshot of session s with 100 prefixes, got %s", string(b))
+	}
+	// Withdrawals are derived from advertisements, they are not saved
+	if e := r.RIB["0"]; e.Message == nil || e.Withdraw != nil {
+		t.Errorf("expected entry with advertisement only, got %+v", e)
+	}
+}
@@ -0,0 +1,83 @@
+package systemd
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/golang/glog"
+)
+
+// Notification states sent to the service manager
+const (
+	// Ready tells the service manager that the service has completed its startup
+	Ready = "READY=1"
+	// Stopping tells the service manager that the service is beginning its shutdown
+	Stopping = "STOPPING=1"
+	// WatchdogPing resets the watchdog timer of the service
+	WatchdogPing = "WATCHDOG=1"
+)
+
+// Notify sends the state to the service manager over the socket of NOTIFY_SOCKET environment variable as
+// described in sd_notify(3), when the variable is not set the service is not run by systemd and the state
+// is not sent.
+func Notify(state string) error {
+	addr := os.Getenv("NOTIFY_SOCKET")
+	if addr == "" {
+		return nil
+	}
+	// Leading @ denotes the socket in the abstract namespace
+	if addr[0] == '@' {
+		addr = "\x00" + addr[1:]
+	}
+	conn, err := net.DialUnix("unixgram", nil, &net.UnixAddr{Name: addr, Net: "unixgram"})
+	if err != nil {
+		return fmt.Errorf("failed to connect to notify socket %s with error: %+v", addr, err)
+	}
+	defer conn.Close()
+	if _, err := conn.Write([]byte(state)); err != nil {
+		return fmt.Errorf("failed to send %s to notify socket %s with error: %+v", state, addr, err)
+	}
+
+	return nil
+}
+
+// WatchdogInterval returns the watchdog timeout of the service set by the service manager in WATCHDOG_USEC
+// environment variable, 0 is returned when the watchdog is not enabled for the process.
+func WatchdogInterval() time.Duration {
+	usec, err := strconv.ParseInt(os.Getenv("WATCHDOG_USEC"), 10, 64)
+	if err != nil || usec <= 0 {
+		return 0
+	}
+	if p := os.Getenv("WATCHDOG_PID"); p != "" {
+		if pid, err := strconv.Atoi(p); err != nil || pid != os.Getpid() {
+			return 0
+		}
+	}
+
+	return time.Duration(usec) * time.Microsecond
+}
+
+// Watchdog pings the service manager's watchdog at half of its timeout until stop is closed, it returns
+// immediately when the watchdog is not enabled.
+func Watchdog(stop <-chan struct{}) {
+	interval := WatchdogInterval() / 2
+	if interval <= 0 {
+		return
+	}
+	glog.Infof("pinging systemd watchdog every %s", interval)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			if err := Notify(WatchdogPing); err != nil {
+				glog.Errorf("failed to ping systemd watchdog with error: %+v", err)
+			}
+		case <-stop:
+			return
+		}
+	}
+}
@@ -0,0 +1,70 @@
+package systemd
+
+import (
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestNotify(t *testing.T) {
+	dir, err := ioutil.TempDir("", "systemd")
+	if err != nil {
+		t.Fatalf("failed to create temporary directory with error: %+v", err)
+	}
+	defer os.RemoveAll(dir)
+	addr := filepath.Join(dir, "notify")
+	conn, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: addr, Net: "unixgram"})
+	if err != nil {
+		t.Fatalf("failed to listen on notify socket with error: %+v", err)
+	}
+	defer conn.Close()
+
+	os.Unsetenv("NOTIFY_SOCKET")
+	if err := Notify(Ready); err != nil {
+		t.Fatalf("expected no error without notify socket, got: %+v", err)
+	}
+	os.Setenv("NOTIFY_SOCKET", addr)
+	defer os.Unsetenv("NOTIFY_SOCKET")
+	if err := Notify(Ready); err != nil {
+		t.Fatalf("failed to notify with error: %+v", err)
+	}
+	b := make([]byte, 64)
+	conn.SetReadDeadline(time.Now().Add(time.Second))
+	n, err := conn.Read(b)
+	if err != nil {
+		t.Fatalf("failed to read notification with error: %+v", err)
+	}
+	if string(b[:n]) != Ready {
+		t.Errorf("expected %s, got %s", Ready, string(b[:n]))
+	}
+}
+
+func TestWatchdogInterval(t *testing.T) {
+	defer os.Unsetenv("WATCHDOG_USEC")
+	defer os.Unsetenv("WATCHDOG_PID")
+	tests := []struct {
+		name   string
+		usec   string
+		pid    string
+		expect time.Duration
+	}{
+		{name: "not enabled", expect: 0},
+		{name: "enabled", usec: "30000000", expect: 30 * time.Second},
+		{name: "this process", usec: "1000", pid: strconv.Itoa(os.Getpid()), expect: time.Millisecond},
+		{name: "other process", usec: "1000", pid: strconv.Itoa(os.Getpid() + 1), expect: 0},
+		{name: "invalid", usec: "abc", expect: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			os.Setenv("WATCHDOG_USEC", tt.usec)
+			os.Setenv("WATCHDOG_PID", tt.pid)
+			if got := WatchdogInterval(); got != tt.expect {
+				t.Errorf("expected interval %s, got %s", tt.expect, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,93 @@
+package telemetry
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/golang/glog"
+)
+
+type handler struct {
+	srv Server
+}
+
+// NewHandler returns http handler exposing telemetry server, paths are passed in "path" query parameter:
+//
+//	GET /state/get?path=/bmp/router[address=192.0.2.1]
+//	GET /state/subscribe?path=/bmp/router[address=192.0.2.1]&mode=on_change
+//	GET /state/subscribe?path=/bmp&mode=sample&interval=30s
+//
+// Get returns json array of notifications, subscribe streams notifications as newline delimited json.
+// gNMI collectors subscribe to the state of the server through pkg/gnmi.
+func NewHandler(srv Server) http.Handler {
+	mux := http.NewServeMux()
+	h := &handler{srv: srv}
+	mux.HandleFunc("/state/get", h.get)
+	mux.HandleFunc("/state/subscribe", h.subscribe)
+
+	return mux
+}
+
+func (h *handler) get(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(h.srv.Get(r.URL.Query().Get("path"))); err != nil {
+		glog.Errorf("failed to send telemetry get response with error: %+v", err)
+	}
+}
+
+func (h *handler) subscribe(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming is not supported", http.StatusInternalServerError)
+		return
+	}
+	q := r.URL.Query()
+	mode := OnChange
+	switch strings.ToLower(q.Get("mode")) {
+	case "", "on_change":
+	case "sample":
+		mode = Sample
+	default:
+		http.Error(w, "invalid subscription mode "+q.Get("mode"), http.StatusBadRequest)
+		return
+	}
+	var interval time.Duration
+	if i := q.Get("interval"); i != "" {
+		var err error
+		if interval, err = time.ParseDuration(i); err != nil || interval <= 0 {
+			http.Error(w, "invalid sample interval "+i, http.StatusBadRequest)
+			return
+		}
+	}
+	stop := make(chan struct{})
+	defer close(stop)
+	notifications := h.srv.Subscribe(q.Get("path"), mode, interval, stop)
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	enc := json.NewEncoder(w)
+	done := r.Context().Done()
+	for {
+		select {
+		case n, ok := <-notifications:
+			if !ok {
+				return
+			}
+			if err := enc.Encode(n); err != nil {
+				glog.V(5).Infof("telemetry subscriber %s is gone with error: %+v", r.RemoteAddr, err)
+				return
+			}
+			flusher.Flush()
+		case <-done:
+			return
+		}
+	}
+}
@@ -0,0 +1,238 @@
+package telemetry
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/sbezverk/gobmp/pkg/bmp"
+	"github.com/sbezverk/gobmp/pkg/pub"
+)
+
+// Mode defines subscription mode, modes are modelled after gNMI subscription modes
+type Mode int
+
+const (
+	// OnChange subscription receives an update every time the value of a path changes
+	OnChange Mode = iota
+	// Sample subscription receives values of all subscribed paths every sample interval
+	Sample
+)
+
+const (
+	// subscriptionQueueLength defines the number of notifications buffered per subscription,
+	// when subscriber does not keep up, notifications are dropped.
+	subscriptionQueueLength = 1024
+	// defaultSampleInterval is used by Sample subscriptions which do not specify the interval
+	defaultSampleInterval = 10 * time.Second
+)
+
+// Notification defines a single telemetry notification, its structure is modelled after gNMI Notification
+type Notification struct {
+	Timestamp    int64           `json:"timestamp"`
+	Path         string          `json:"path,omitempty"`
+	Update       json.RawMessage `json:"update,omitempty"`
+	Delete       bool            `json:"delete,omitempty"`
+	SyncResponse bool            `json:"sync_response,omitempty"`
+}
+
+// Server defines methods of the telemetry server, the server is a Publisher, it maintains the latest state
+// of all published objects indexed by paths in gNMI path format and streams the state to subscribers.
+type Server interface {
+	pub.Publisher
+	Get(prefix string) []*Notification
+	Subscribe(prefix string, mode Mode, interval time.Duration, stop <-chan struct{}) <-chan *Notification
+}
+
+type entry struct {
+	timestamp int64
+	value     json.RawMessage
+}
+
+type subscription struct {
+	prefix string
+	queue  chan *Notification
+}
+
+type server struct {
+	sync.RWMutex
+	state         map[string]*entry
+	subscriptions map[*subscription]struct{}
+}
+
+var _ Server = &server{}
+
+// key defines fields of published messages used to build the message's path
+type key struct {
+	Action   string `json:"action"`
+	Hash     string `json:"hash"`
+	RouterIP string `json:"router_ip"`
+	PeerIP   string `json:"peer_ip"`
+	RemoteIP string `json:"remote_ip"`
+}
+
+// path returns the path of a published message in gNMI path format, for example:
+// /bmp/router[address=192.0.2.1]/peer[address=192.0.2.2]/unicast_prefix_v4[hash=...]
+func path(msgType int, k *key) string {
+	peer := k.PeerIP
+	if peer == "" {
+		peer = k.RemoteIP
+	}
+	p := fmt.Sprintf("/bmp/router[address=%s]/peer[address=%s]", k.RouterIP, peer)
+	switch msgType {
+	case bmp.PeerStateChangeMsg:
+		return p + "/state"
+	case bmp.StatsReportMsg:
+		return p + "/statistics"
+	}
+
+	return fmt.Sprintf("%s/%s[hash=%s]", p, bmp.MsgTypeName(msgType), k.Hash)
+}
+
+// matches returns true when the path is equal to the prefix or is located under the prefix
+func matches(path, prefix string) bool {
+	prefix = strings.TrimSuffix(prefix, "/")
+	if prefix == "" {
+		return true
+	}
+
+	return path == prefix || strings.HasPrefix(path, prefix+"/")
+}
+
+func (s *server) PublishMessage(msgType int, msgHash []byte, msg []byte) error {
+	// Events and alerts do not represent a state, they are not kept
+	switch msgType {
+	case bmp.TopologyEventMsg, bmp.PrefixFlapMsg, bmp.AlertMsg, bmp.MACEventMsg:
+		return nil
+	}
+	if bmp.MsgTypeName(msgType) == "" {
+		return nil
+	}
+	k := &key{}
+	if err := json.Unmarshal(msg, k); err != nil {
+		return err
+	}
+	n := &Notification{
+		Timestamp: time.Now().UnixNano(),
+		Path:      path(msgType, k),
+	}
+	s.Lock()
+	defer s.Unlock()
+	// Peer down and withdraw messages remove the object from the state
+	if k.Action == "del" || k.Action == "down" {
+		if _, ok := s.state[n.Path]; !ok {
+			return nil
+		}
+		delete(s.state, n.Path)
+		n.Delete = true
+	} else {
+		n.Update = make(json.RawMessage, len(msg))
+		copy(n.Update, msg)
+		s.state[n.Path] = &entry{timestamp: n.Timestamp, value: n.Update}
+	}
+	for sub := range s.subscriptions {
+		if matches(n.Path, sub.prefix) {
+			sub.notify(n)
+		}
+	}
+
+	return nil
+}
+
+func (s *server) Stop() {
+	s.Lock()
+	defer s.Unlock()
+	for sub := range s.subscriptions {
+		delete(s.subscriptions, sub)
+		close(sub.queue)
+	}
+}
+
+// Get returns the current state of all paths under the prefix
+func (s *server) Get(prefix string) []*Notification {
+	s.RLock()
+	defer s.RUnlock()
+
+	return s.get(prefix)
+}
+
+func (s *server) get(prefix string) []*Notification {
+	ns := make([]*Notification, 0)
+	for p, e := range s.state {
+		if matches(p, prefix) {
+			ns = append(ns, &Notification{Timestamp: e.timestamp, Path: p, Update: e.value})
+		}
+	}
+
+	return ns
+}
+
+// Subscribe returns a channel streaming notifications of paths under the prefix, the current state is sent first
+// and followed by a notification with SyncResponse set. OnChange subscription then receives every update and delete,
+// Sample subscription receives the state of all paths every interval. The channel is closed when stop channel
+// is closed or the server is stopped.
+func (s *server) Subscribe(prefix string, mode Mode, interval time.Duration, stop <-chan struct{}) <-chan *Notification {
+	sub := &subscription{
+		prefix: prefix,
+		queue:  make(chan *Notification, subscriptionQueueLength),
+	}
+	s.Lock()
+	for _, n := range s.get(prefix) {
+		sub.notify(n)
+	}
+	sub.notify(&Notification{Timestamp: time.Now().UnixNano(), SyncResponse: true})
+	if mode == OnChange {
+		s.subscriptions[sub] = struct{}{}
+	}
+	s.Unlock()
+	go func() {
+		var tick <-chan time.Time
+		if mode == Sample {
+			if interval <= 0 {
+				interval = defaultSampleInterval
+			}
+			ticker := time.NewTicker(interval)
+			defer ticker.Stop()
+			tick = ticker.C
+		}
+		for {
+			select {
+			case <-tick:
+				s.RLock()
+				for _, n := range s.get(prefix) {
+					sub.notify(n)
+				}
+				s.RUnlock()
+			case <-stop:
+				s.Lock()
+				if _, ok := s.subscriptions[sub]; ok || mode == Sample {
+					delete(s.subscriptions, sub)
+					close(sub.queue)
+				}
+				s.Unlock()
+				return
+			}
+		}
+	}()
+
+	return sub.queue
+}
+
+func (sub *subscription) notify(n *Notification) {
+	select {
+	case sub.queue <- n:
+	default:
+		glog.Warningf("telemetry subscription for %s is not keeping up, dropping notification for %s", sub.prefix, n.Path)
+	}
+}
+
+// NewServer instantiates a new instance of telemetry server
+func NewServer() Server {
+	return &server{
+		state:         make(map[string]*entry),
+		subscriptions: make(map[*subscription]struct{}),
+	}
+}
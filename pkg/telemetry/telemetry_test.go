@@ -0,0 +1,71 @@
+package telemetry
+
+import (
+	"testing"
+	"time"
+
+	"github.com/sbezverk/gobmp/pkg/bmp"
+)
+
+func receive(t *testing.T, ch <-chan *Notification) *Notification {
+	t.Helper()
+	select {
+	case n := <-ch:
+		return n
+	case <-time.After(time.Second):
+		t.Fatalf("timed out waiting for notification")
+	}
+	return nil
+}
+
+func TestOnChangeSubscription(t *testing.T) {
+	s := NewServer()
+	defer s.Stop()
+	r1 := `{"action":"add","hash":"h1","router_ip":"192.0.2.1","peer_ip":"192.0.2.2","prefix":"10.0.0.0"}`
+	r2 := `{"action":"add","hash":"h2","router_ip":"192.0.2.10","peer_ip":"192.0.2.2","prefix":"10.0.1.0"}`
+	if err := s.PublishMessage(bmp.UnicastPrefixV4Msg, nil, []byte(r1)); err != nil {
+		t.Fatalf("failed to publish with error: %+v", err)
+	}
+	stop := make(chan struct{})
+	defer close(stop)
+	ch := s.Subscribe("/bmp/router[address=192.0.2.1]", OnChange, 0, stop)
+	n := receive(t, ch)
+	if n.Path != "/bmp/router[address=192.0.2.1]/peer[address=192.0.2.2]/unicast_prefix_v4[hash=h1]" || string(n.Update) != r1 {
+		t.Fatalf("unexpected initial notification %+v", n)
+	}
+	if n = receive(t, ch); !n.SyncResponse {
+		t.Fatalf("expected sync response, got %+v", n)
+	}
+	// Message of another router must not be delivered
+	if err := s.PublishMessage(bmp.UnicastPrefixV4Msg, nil, []byte(r2)); err != nil {
+		t.Fatalf("failed to publish with error: %+v", err)
+	}
+	if err := s.PublishMessage(bmp.UnicastPrefixV4Msg, nil, []byte(`{"action":"del","hash":"h1","router_ip":"192.0.2.1","peer_ip":"192.0.2.2"}`)); err != nil {
+		t.Fatalf("failed to publish with error: %+v", err)
+	}
+	if n = receive(t, ch); !n.Delete || n.Path != "/bmp/router[address=192.0.2.1]/peer[address=192.0.2.2]/unicast_prefix_v4[hash=h1]" {
+		t.Fatalf("expected delete notification, got %+v", n)
+	}
+	if got := s.Get("/bmp"); len(got) != 1 || string(got[0].Update) != r2 {
+		t.Fatalf("expected state with a single prefix, got %+v", got)
+	}
+}
+
+func TestSampleSubscription(t *testing.T) {
+	s := NewServer()
+	defer s.Stop()
+	peer := `{"action":"up","router_ip":"192.0.2.1","remote_ip":"192.0.2.2"}`
+	if err := s.PublishMessage(bmp.PeerStateChangeMsg, nil, []byte(peer)); err != nil {
+		t.Fatalf("failed to publish with error: %+v", err)
+	}
+	stop := make(chan struct{})
+	ch := s.Subscribe("/bmp/", Sample, 10*time.Millisecond, stop)
+	receive(t, ch)
+	receive(t, ch)
+	if n := receive(t, ch); n.Path != "/bmp/router[address=192.0.2.1]/peer[address=192.0.2.2]/state" || string(n.Update) != peer {
+		t.Fatalf("unexpected sample notification %+v", n)
+	}
+	close(stop)
+	for range ch {
+	}
+}
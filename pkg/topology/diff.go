@@ -0,0 +1,121 @@
+package topology
+
+import (
+	"reflect"
+	"sort"
+)
+
+// Delta defines changes of the topology graph between two snapshots
+type Delta struct {
+	From            uint64    `json:"from"`
+	To              uint64    `json:"to"`
+	AddedNodes      []*Node   `json:"added_nodes,omitempty"`
+	RemovedNodes    []*Node   `json:"removed_nodes,omitempty"`
+	UpdatedNodes    []*Node   `json:"updated_nodes,omitempty"`
+	AddedEdges      []*Edge   `json:"added_edges,omitempty"`
+	RemovedEdges    []*Edge   `json:"removed_edges,omitempty"`
+	UpdatedEdges    []*Edge   `json:"updated_edges,omitempty"`
+	AddedPrefixes   []*Prefix `json:"added_prefixes,omitempty"`
+	RemovedPrefixes []*Prefix `json:"removed_prefixes,omitempty"`
+	UpdatedPrefixes []*Prefix `json:"updated_prefixes,omitempty"`
+}
+
+// IsEmpty returns true if Delta does not carry any change
+func (d *Delta) IsEmpty() bool {
+	return len(d.AddedNodes) == 0 && len(d.RemovedNodes) == 0 && len(d.UpdatedNodes) == 0 &&
+		len(d.AddedEdges) == 0 && len(d.RemovedEdges) == 0 && len(d.UpdatedEdges) == 0 &&
+		len(d.AddedPrefixes) == 0 && len(d.RemovedPrefixes) == 0 && len(d.UpdatedPrefixes) == 0
+}
+
+// diffKeys compares keys of two maps of graph objects and returns sorted keys of added, removed and
+// updated objects.
+func diffKeys(from, to map[string]interface{}) ([]string, []string, []string) {
+	added, removed, updated := make([]string, 0), make([]string, 0), make([]string, 0)
+	for k, v := range to {
+		o, ok := from[k]
+		switch {
+		case !ok:
+			added = append(added, k)
+		case !reflect.DeepEqual(o, v):
+			updated = append(updated, k)
+		}
+	}
+	for k := range from {
+		if _, ok := to[k]; !ok {
+			removed = append(removed, k)
+		}
+	}
+	sort.Strings(added)
+	sort.Strings(removed)
+	sort.Strings(updated)
+
+	return added, removed, updated
+}
+
+func nodes(m map[string]*Node) map[string]interface{} {
+	r := make(map[string]interface{}, len(m))
+	for k, v := range m {
+		r[k] = v
+	}
+	return r
+}
+
+func edges(m map[string]*Edge) map[string]interface{} {
+	r := make(map[string]interface{}, len(m))
+	for k, v := range m {
+		r[k] = v
+	}
+	return r
+}
+
+func prefixes(m map[string]*Prefix) map[string]interface{} {
+	r := make(map[string]interface{}, len(m))
+	for k, v := range m {
+		r[k] = v
+	}
+	return r
+}
+
+// Diff returns changes required to transform "from" snapshot into "to" snapshot, nil "from" snapshot
+// is treated as an empty graph.
+func Diff(from, to *Snapshot) *Delta {
+	if from == nil {
+		from = &Snapshot{}
+	}
+	d := &Delta{
+		From: from.Revision,
+		To:   to.Revision,
+	}
+	added, removed, updated := diffKeys(nodes(from.Nodes), nodes(to.Nodes))
+	for _, k := range added {
+		d.AddedNodes = append(d.AddedNodes, to.Nodes[k])
+	}
+	for _, k := range removed {
+		d.RemovedNodes = append(d.RemovedNodes, from.Nodes[k])
+	}
+	for _, k := range updated {
+		d.UpdatedNodes = append(d.UpdatedNodes, to.Nodes[k])
+	}
+	added, removed, updated = diffKeys(edges(from.Edges), edges(to.Edges))
+	for _, k := range added {
+		d.AddedEdges = append(d.AddedEdges, to.Edges[k])
+	}
+	for _, k := range removed {
+		d.RemovedEdges = append(d.RemovedEdges, from.Edges[k])
+	}
+	for _, k := range updated {
+		d.UpdatedEdges = append(d.UpdatedEdges, to.Edges[k])
+	}
+	added, removed, updated = diffKeys(prefixes(from.Prefixes), prefixes(to.Prefixes))
+	for _, k := range added {
+		d.AddedPrefixes = append(d.AddedPrefixes, to.Prefixes[k])
+	}
+	for _, k := range removed {
+		d.RemovedPrefixes = append(d.RemovedPrefixes, from.Prefixes[k])
+	}
+	for _, k := range updated {
+		d.UpdatedPrefixes = append(d.UpdatedPrefixes, to.Prefixes[k])
+	}
+
+	return d
+}
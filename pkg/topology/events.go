@@ -0,0 +1,106 @@
+package topology
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/sbezverk/gobmp/pkg/bmp"
+)
+
+// EventType defines the type of topology event
+type EventType string
+
+const (
+	// NodeAppeared is generated when a new node is advertised
+	NodeAppeared EventType = "node_appeared"
+	// NodeDisappeared is generated when a node is withdrawn
+	NodeDisappeared EventType = "node_disappeared"
+	// SRGBChange is generated when a node advertises a different SRGB
+	SRGBChange EventType = "srgb_change"
+	// LinkUp is generated when a new link is advertised
+	LinkUp EventType = "link_up"
+	// LinkDown is generated when a link is withdrawn
+	LinkDown EventType = "link_down"
+	// MetricChange is generated for each metric type which value of a link has changed
+	MetricChange EventType = "metric_change"
+)
+
+// Event defines a high level event derived from consecutive states of BGP-LS topology,
+// Old and New carry previous and current values of changed metric or SRGB.
+type Event struct {
+	Event             EventType   `json:"event"`
+	Timestamp         string      `json:"timestamp"`
+	RouterIP          string      `json:"router_ip,omitempty"`
+	IGPRouterID       string      `json:"igp_router_id"`
+	Name              string      `json:"name,omitempty"`
+	RemoteIGPRouterID string      `json:"remote_igp_router_id,omitempty"`
+	Link              string      `json:"link,omitempty"`
+	Metric            MetricType  `json:"metric,omitempty"`
+	Old               interface{} `json:"old,omitempty"`
+	New               interface{} `json:"new,omitempty"`
+}
+
+// key returns the key of the event's topology object
+func (e *Event) key() string {
+	if e.Link != "" {
+		return e.Link
+	}
+
+	return e.IGPRouterID
+}
+
+func newNodeEvent(t EventType, routerIP string, n *Node) *Event {
+	return &Event{
+		Event:       t,
+		Timestamp:   time.Now().UTC().Format(time.RFC3339Nano),
+		RouterIP:    routerIP,
+		IGPRouterID: n.IGPRouterID,
+		Name:        n.Name,
+	}
+}
+
+func newLinkEvent(t EventType, routerIP string, e *Edge) *Event {
+	return &Event{
+		Event:             t,
+		Timestamp:         time.Now().UTC().Format(time.RFC3339Nano),
+		RouterIP:          routerIP,
+		IGPRouterID:       e.Local,
+		RemoteIGPRouterID: e.Remote,
+		Link:              e.Key,
+	}
+}
+
+// metricChanges returns metric_change events for every metric which differs between old and current edges
+func metricChanges(routerIP string, old, cur *Edge) []*Event {
+	events := make([]*Event, 0)
+	for _, t := range []MetricType{IGPMetric, TEMetric, DelayMetric} {
+		o, _ := old.metric(t)
+		n, _ := cur.metric(t)
+		if o == n {
+			continue
+		}
+		e := newLinkEvent(MetricChange, routerIP, cur)
+		e.Metric, e.Old, e.New = t, o, n
+		events = append(events, e)
+	}
+
+	return events
+}
+
+func (g *graph) publishEvents(events []*Event) error {
+	if g.events == nil {
+		return nil
+	}
+	for _, e := range events {
+		b, err := json.Marshal(e)
+		if err != nil {
+			return fmt.Errorf("failed to marshal topology event with error: %+v", err)
+		}
+		if err := g.events.PublishMessage(bmp.TopologyEventMsg, []byte(e.key()), b); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,86 @@
+package topology
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/go-test/deep"
+	"github.com/sbezverk/gobmp/pkg/bmp"
+)
+
+// eventCollector is a Publisher collecting topology events
+type eventCollector struct {
+	events []*Event
+}
+
+func (c *eventCollector) PublishMessage(msgType int, msgHash []byte, msg []byte) error {
+	if msgType != bmp.TopologyEventMsg {
+		return nil
+	}
+	e := &Event{}
+	if err := json.Unmarshal(msg, e); err != nil {
+		return err
+	}
+	// Timestamp is not predictable
+	e.Timestamp = ""
+	c.events = append(c.events, e)
+
+	return nil
+}
+
+func (c *eventCollector) Stop() {}
+
+func TestEvents(t *testing.T) {
+	tests := []struct {
+		name   string
+		msgs   []testMsg
+		expect []*Event
+	}{
+		{
+			name: "node appeared, srgb change and disappeared",
+			msgs: []testMsg{
+				{bmp.LSNodeMsg, `{"action":"add","router_ip":"192.0.2.1","igp_router_id":"a","name":"r1","srgb":[{"first_sid":16000,"range":8000}]}`},
+				{bmp.LSNodeMsg, `{"action":"add","router_ip":"192.0.2.1","igp_router_id":"a","name":"r1","srgb":[{"first_sid":16000,"range":8000}]}`},
+				{bmp.LSNodeMsg, `{"action":"add","router_ip":"192.0.2.1","igp_router_id":"a","name":"r1","srgb":[{"first_sid":17000,"range":1000}]}`},
+				{bmp.LSNodeMsg, `{"action":"del","router_ip":"192.0.2.1","igp_router_id":"a"}`},
+			},
+			expect: []*Event{
+				{Event: NodeAppeared, RouterIP: "192.0.2.1", IGPRouterID: "a", Name: "r1"},
+				{
+					Event: SRGBChange, RouterIP: "192.0.2.1", IGPRouterID: "a", Name: "r1",
+					Old: []interface{}{map[string]interface{}{"first_sid": float64(16000), "range": float64(8000)}},
+					New: []interface{}{map[string]interface{}{"first_sid": float64(17000), "range": float64(1000)}},
+				},
+				{Event: NodeDisappeared, RouterIP: "192.0.2.1", IGPRouterID: "a", Name: "r1"},
+			},
+		},
+		{
+			name: "link up, metric change and link down",
+			msgs: []testMsg{
+				{bmp.LSLinkMsg, `{"action":"add","igp_router_id":"a","remote_igp_router_id":"b","local_link_id":1,"remote_link_id":2,"igp_metric":10}`},
+				{bmp.LSLinkMsg, `{"action":"add","igp_router_id":"a","remote_igp_router_id":"b","local_link_id":1,"remote_link_id":2,"igp_metric":20,"unidir_link_delay":100}`},
+				// Changes of attributes which are not metrics do not generate events
+				{bmp.LSLinkMsg, `{"action":"add","igp_router_id":"a","remote_igp_router_id":"b","local_link_id":1,"remote_link_id":2,"igp_metric":20,"unidir_link_delay":100,"srlg":[1]}`},
+				{bmp.LSLinkMsg, `{"action":"del","igp_router_id":"a","remote_igp_router_id":"b","local_link_id":1,"remote_link_id":2}`},
+				{bmp.LSLinkMsg, `{"action":"del","igp_router_id":"a","remote_igp_router_id":"b","local_link_id":1,"remote_link_id":2}`},
+			},
+			expect: []*Event{
+				{Event: LinkUp, IGPRouterID: "a", RemoteIGPRouterID: "b", Link: "a_1_b_2"},
+				{Event: MetricChange, IGPRouterID: "a", RemoteIGPRouterID: "b", Link: "a_1_b_2", Metric: IGPMetric, Old: float64(10), New: float64(20)},
+				{Event: MetricChange, IGPRouterID: "a", RemoteIGPRouterID: "b", Link: "a_1_b_2", Metric: DelayMetric, Old: float64(0), New: float64(100)},
+				{Event: LinkDown, IGPRouterID: "a", RemoteIGPRouterID: "b", Link: "a_1_b_2"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &eventCollector{}
+			publish(t, NewGraph(c), tt.msgs)
+			if !reflect.DeepEqual(c.events, tt.expect) {
+				t.Errorf("events do not match expected")
+				t.Logf("Differences: %+v", deep.Equal(c.events, tt.expect))
+			}
+		})
+	}
+}
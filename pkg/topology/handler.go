@@ -0,0 +1,129 @@
+package topology
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"sync"
+
+	"github.com/golang/glog"
+)
+
+// maxSnapshots defines the number of snapshots kept by the handler to compute diffs
+const maxSnapshots = 16
+
+type handler struct {
+	sync.Mutex
+	g Graph
+	// snapshots keeps recently served snapshots by revision, clients pass the revision
+	// of the snapshot they have to get the diff.
+	snapshots map[uint64]*Snapshot
+	order     []uint64
+}
+
+// NewHandler returns http handler exposing topology graph:
+//
+//	GET /topology/snapshot
+//	GET /topology/diff?from={revision}
+//	GET /topology/path?src={igp router-id}&dst={igp router-id}&metric={igp|te|delay}&algo={0-255}&exclude_any={admin group mask}&mt_id={0-4095}
+//
+// Diff is available only from revisions of snapshots and diffs recently served by the handler.
+func NewHandler(g Graph) http.Handler {
+	mux := http.NewServeMux()
+	h := &handler{
+		g:         g,
+		snapshots: make(map[uint64]*Snapshot),
+	}
+	mux.HandleFunc("/topology/snapshot", h.snapshot)
+	mux.HandleFunc("/topology/diff", h.diff)
+	mux.HandleFunc("/topology/path", h.path)
+
+	return mux
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		glog.Errorf("failed to send topology response with error: %+v", err)
+	}
+}
+
+// current returns the current snapshot of the graph and keeps it for future diffs
+func (h *handler) current() *Snapshot {
+	s := h.g.Snapshot()
+	h.Lock()
+	defer h.Unlock()
+	if _, ok := h.snapshots[s.Revision]; !ok {
+		h.snapshots[s.Revision] = s
+		h.order = append(h.order, s.Revision)
+		if len(h.order) > maxSnapshots {
+			delete(h.snapshots, h.order[0])
+			h.order = h.order[1:]
+		}
+	}
+
+	return s
+}
+
+func (h *handler) snapshot(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, h.current())
+}
+
+func (h *handler) diff(w http.ResponseWriter, r *http.Request) {
+	from, err := strconv.ParseUint(r.URL.Query().Get("from"), 10, 64)
+	if err != nil {
+		http.Error(w, "invalid from revision "+r.URL.Query().Get("from"), http.StatusBadRequest)
+		return
+	}
+	h.Lock()
+	s, ok := h.snapshots[from]
+	h.Unlock()
+	if !ok {
+		http.Error(w, "snapshot of revision "+strconv.FormatUint(from, 10)+" is not available, get a new snapshot", http.StatusGone)
+		return
+	}
+	writeJSON(w, Diff(s, h.current()))
+}
+
+func (h *handler) path(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	c := &Constraints{
+		Metric: MetricType(q.Get("metric")),
+	}
+	switch c.Metric {
+	case "", IGPMetric, TEMetric, DelayMetric:
+	default:
+		http.Error(w, "invalid metric type "+q.Get("metric"), http.StatusBadRequest)
+		return
+	}
+	if a := q.Get("algo"); a != "" {
+		algo, err := strconv.ParseUint(a, 10, 8)
+		if err != nil {
+			http.Error(w, "invalid algorithm "+a, http.StatusBadRequest)
+			return
+		}
+		c.Algorithm = uint8(algo)
+	}
+	if e := q.Get("exclude_any"); e != "" {
+		mask, err := strconv.ParseUint(e, 0, 32)
+		if err != nil {
+			http.Error(w, "invalid exclude_any "+e, http.StatusBadRequest)
+			return
+		}
+		c.ExcludeAny = uint32(mask)
+	}
+	if m := q.Get("mt_id"); m != "" {
+		mtid, err := strconv.ParseUint(m, 10, 12)
+		if err != nil {
+			http.Error(w, "invalid mt_id "+m, http.StatusBadRequest)
+			return
+		}
+		c.MTID = uint16(mtid)
+	}
+	p, err := ComputePath(h.g.Snapshot(), q.Get("src"), q.Get("dst"), c)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	writeJSON(w, p)
+}
@@ -0,0 +1,243 @@
+package topology
+
+import (
+	"container/heap"
+	"fmt"
+	"sort"
+)
+
+// MetricType defines the link metric used by path computation
+type MetricType string
+
+const (
+	// IGPMetric selects IGP metric of links
+	IGPMetric MetricType = "igp"
+	// TEMetric selects TE default metric of links
+	TEMetric MetricType = "te"
+	// DelayMetric selects unidirectional link delay
+	DelayMetric MetricType = "delay"
+)
+
+// Constraints defines constraints of path computation, Algorithm other than 0 restricts the computation
+// to nodes participating in the flexible algorithm, links with any of ExcludeAny affinity bits set in
+// their admin group are pruned. The computation uses links of the topology MTID, the standard topology by default.
+type Constraints struct {
+	Metric     MetricType `json:"metric,omitempty"`
+	Algorithm  uint8      `json:"algo,omitempty"`
+	ExcludeAny uint32     `json:"exclude_any,omitempty"`
+	MTID       uint16     `json:"mt_id,omitempty"`
+}
+
+// Path defines the result of path computation. Labels carry SR label stack steering traffic along the path,
+// the stack is empty when nodes along the path do not advertise required SR information.
+type Path struct {
+	Source      string   `json:"source"`
+	Destination string   `json:"destination"`
+	Metric      uint64   `json:"metric"`
+	Nodes       []string `json:"nodes"`
+	Edges       []*Edge  `json:"edges"`
+	Labels      []uint32 `json:"labels,omitempty"`
+}
+
+// metric returns the metric of the edge for the metric type, the second return value is false
+// when the edge does not carry the metric.
+func (e *Edge) metric(t MetricType) (uint64, bool) {
+	switch t {
+	case TEMetric:
+		return uint64(e.TEMetric), e.TEMetric != 0
+	case DelayMetric:
+		return uint64(e.Delay), e.Delay != 0
+	default:
+		return uint64(e.IGPMetric), true
+	}
+}
+
+func (n *Node) supportsAlgorithm(algo uint8) bool {
+	for _, a := range n.SRAlgorithm {
+		if a == int(algo) {
+			return true
+		}
+	}
+
+	return false
+}
+
+type spfEntry struct {
+	node   string
+	metric uint64
+}
+
+type spfQueue []*spfEntry
+
+func (q spfQueue) Len() int { return len(q) }
+func (q spfQueue) Less(i, j int) bool {
+	if q[i].metric == q[j].metric {
+		return q[i].node < q[j].node
+	}
+	return q[i].metric < q[j].metric
+}
+func (q spfQueue) Swap(i, j int)       { q[i], q[j] = q[j], q[i] }
+func (q *spfQueue) Push(x interface{}) { *q = append(*q, x.(*spfEntry)) }
+func (q *spfQueue) Pop() interface{} {
+	old := *q
+	e := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return e
+}
+
+// adjacencies returns edges of the snapshot satisfying the constraints grouped by the local node,
+// edges of each node are sorted by key to make the computation deterministic. Pseudonodes do not advertise
+// algorithms, they participate in all algorithms.
+func (s *Snapshot) adjacencies(c *Constraints) map[string][]*Edge {
+	participates := func(id string) bool {
+		if c.Algorithm == 0 {
+			return true
+		}
+		n, ok := s.Nodes[id]
+		return ok && (n.Pseudonode || n.supportsAlgorithm(c.Algorithm))
+	}
+	adj := make(map[string][]*Edge)
+	for _, e := range s.Edges {
+		if _, ok := e.metric(c.Metric); !ok {
+			continue
+		}
+		if e.AdminGroup&c.ExcludeAny != 0 {
+			continue
+		}
+		if e.MTID != c.MTID {
+			continue
+		}
+		if !participates(e.Local) || !participates(e.Remote) {
+			continue
+		}
+		adj[e.Local] = append(adj[e.Local], e)
+	}
+	for _, edges := range adj {
+		sort.Slice(edges, func(i, j int) bool { return edges[i].Key < edges[j].Key })
+	}
+
+	return adj
+}
+
+// ComputePath computes the shortest path from src to dst nodes identified by IGP router-id
+// satisfying the constraints.
+func ComputePath(s *Snapshot, src, dst string, c *Constraints) (*Path, error) {
+	cc := Constraints{}
+	if c != nil {
+		cc = *c
+	}
+	c = &cc
+	switch c.Metric {
+	case "":
+		c.Metric = IGPMetric
+	case IGPMetric, TEMetric, DelayMetric:
+	default:
+		return nil, fmt.Errorf("invalid metric type %s", c.Metric)
+	}
+	if src == dst {
+		return nil, fmt.Errorf("source and destination are the same node %s", src)
+	}
+	adj := s.adjacencies(c)
+	dist := map[string]uint64{src: 0}
+	prev := make(map[string]*Edge)
+	done := make(map[string]bool)
+	q := &spfQueue{{node: src}}
+	for q.Len() != 0 {
+		e := heap.Pop(q).(*spfEntry)
+		if done[e.node] {
+			continue
+		}
+		done[e.node] = true
+		if e.node == dst {
+			break
+		}
+		for _, edge := range adj[e.node] {
+			m, _ := edge.metric(c.Metric)
+			d := e.metric + m
+			if cur, ok := dist[edge.Remote]; ok && cur <= d {
+				continue
+			}
+			dist[edge.Remote] = d
+			prev[edge.Remote] = edge
+			heap.Push(q, &spfEntry{node: edge.Remote, metric: d})
+		}
+	}
+	if !done[dst] {
+		return nil, fmt.Errorf("no path from %s to %s satisfying constraints", src, dst)
+	}
+	p := &Path{
+		Source:      src,
+		Destination: dst,
+		Metric:      dist[dst],
+		Nodes:       []string{dst},
+	}
+	for n := dst; n != src; n = prev[n].Local {
+		p.Edges = append([]*Edge{prev[n]}, p.Edges...)
+		p.Nodes = append([]string{prev[n].Local}, p.Nodes...)
+	}
+	p.Labels = s.labelStack(p, c)
+
+	return p, nil
+}
+
+// labelStack returns SR label stack of the path. When the path follows the algorithm's shortest path,
+// the stack is the Prefix SID of the destination for the algorithm, otherwise the path is expressed
+// by Adjacency SIDs of all links along the path.
+func (s *Snapshot) labelStack(p *Path, c *Constraints) []uint32 {
+	if c.Algorithm != 0 || (c.Metric == IGPMetric && c.ExcludeAny == 0) {
+		if l, ok := s.nodeLabel(p.Source, p.Destination, c.Algorithm); ok {
+			return []uint32{l}
+		}
+		return nil
+	}
+	labels := make([]uint32, 0, len(p.Edges))
+	for _, e := range p.Edges {
+		if len(e.AdjSIDs) == 0 {
+			return nil
+		}
+		labels = append(labels, e.AdjSIDs[0])
+	}
+
+	return labels
+}
+
+// nodeLabel returns the label of dst node's Prefix SID for the algorithm as seen by src node,
+// host prefixes are preferred.
+func (s *Snapshot) nodeLabel(src, dst string, algo uint8) (uint32, bool) {
+	var sid *PrefixSID
+	var plen int32 = -1
+	keys := make([]string, 0)
+	for k, prfx := range s.Prefixes {
+		if prfx.Node == dst {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		prfx := s.Prefixes[k]
+		for _, ps := range prfx.SIDs {
+			if ps.Algorithm == algo && prfx.PrefixLen > plen {
+				sid, plen = ps, prfx.PrefixLen
+			}
+		}
+	}
+	if sid == nil {
+		return 0, false
+	}
+	if sid.Value {
+		return sid.SID, true
+	}
+	n, ok := s.Nodes[src]
+	if !ok {
+		return 0, false
+	}
+	index := sid.SID
+	for _, r := range n.SRGB {
+		if index < r.Range {
+			return r.FirstSID + index, true
+		}
+		index -= r.Range
+	}
+
+	return 0, false
+}
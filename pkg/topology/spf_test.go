@@ -0,0 +1,178 @@
+package topology
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"reflect"
+	"strconv"
+	"testing"
+
+	"github.com/go-test/deep"
+	"github.com/sbezverk/gobmp/pkg/bmp"
+)
+
+// diamondGraph builds topology of 4 nodes a, b, c and d, where a-b-d is IGP shortest path,
+// a-c-d is TE shortest path, link a-b is colored with affinity bit 0x1 and b does not participate
+// in flexible algorithm 128, link a-d belongs only to topology 2.
+func diamondGraph(t *testing.T) Graph {
+	g := NewGraph(nil)
+	publish(t, g, []testMsg{
+		{bmp.LSNodeMsg, `{"action":"add","igp_router_id":"a","srgb":[{"first_sid":16000,"range":8000}],"sr_algorithm":[0,128]}`},
+		{bmp.LSNodeMsg, `{"action":"add","igp_router_id":"b","srgb":[{"first_sid":16000,"range":8000}],"sr_algorithm":[0]}`},
+		{bmp.LSNodeMsg, `{"action":"add","igp_router_id":"c","srgb":[{"first_sid":16000,"range":8000}],"sr_algorithm":[0,128]}`},
+		{bmp.LSNodeMsg, `{"action":"add","igp_router_id":"d","srgb":[{"first_sid":16000,"range":8000}],"sr_algorithm":[0,128]}`},
+		{bmp.LSLinkMsg, `{"action":"add","igp_router_id":"a","remote_igp_router_id":"b","local_link_id":1,"remote_link_id":1,"igp_metric":10,"te_default_metric":100,"admin_group":1,"ls_adjacency_sid":[{"sid":24001}]}`},
+		{bmp.LSLinkMsg, `{"action":"add","igp_router_id":"b","remote_igp_router_id":"d","local_link_id":2,"remote_link_id":1,"igp_metric":10,"te_default_metric":100,"ls_adjacency_sid":[{"sid":24002}]}`},
+		{bmp.LSLinkMsg, `{"action":"add","igp_router_id":"a","remote_igp_router_id":"c","local_link_id":2,"remote_link_id":1,"igp_metric":5,"te_default_metric":10,"ls_adjacency_sid":[{"sid":24003}]}`},
+		{bmp.LSLinkMsg, `{"action":"add","igp_router_id":"c","remote_igp_router_id":"d","local_link_id":2,"remote_link_id":2,"igp_metric":30,"te_default_metric":10,"ls_adjacency_sid":[{"sid":24004}]}`},
+		{bmp.LSLinkMsg, `{"action":"add","igp_router_id":"a","remote_igp_router_id":"d","local_link_id":3,"remote_link_id":3,"mt_id":2,"igp_metric":5,"te_default_metric":5,"ls_adjacency_sid":[{"sid":24005}]}`},
+		{bmp.LSPrefixMsg, `{"action":"add","igp_router_id":"d","prefix":"10.1.0.0","prefix_len":24,"prefix_attr_tlvs":{"ls_prefix_sid":[{"algo":0,"prefix_sid":40}]}}`},
+		{bmp.LSPrefixMsg, `{"action":"add","igp_router_id":"d","prefix":"10.0.0.4","prefix_len":32,"prefix_attr_tlvs":{"ls_prefix_sid":[{"algo":0,"prefix_sid":4},{"algo":128,"prefix_sid":104}]}}`},
+	})
+
+	return g
+}
+
+func TestComputePath(t *testing.T) {
+	tests := []struct {
+		name   string
+		c      *Constraints
+		nodes  []string
+		metric uint64
+		labels []uint32
+		fail   bool
+	}{
+		{
+			name:   "igp shortest path",
+			nodes:  []string{"a", "b", "d"},
+			metric: 20,
+			labels: []uint32{16004},
+		},
+		{
+			name:   "te shortest path",
+			c:      &Constraints{Metric: TEMetric},
+			nodes:  []string{"a", "c", "d"},
+			metric: 20,
+			labels: []uint32{24003, 24004},
+		},
+		{
+			name:   "affinity exclusion",
+			c:      &Constraints{ExcludeAny: 0x1},
+			nodes:  []string{"a", "c", "d"},
+			metric: 35,
+			labels: []uint32{24003, 24004},
+		},
+		{
+			name:   "flexible algorithm",
+			c:      &Constraints{Algorithm: 128},
+			nodes:  []string{"a", "c", "d"},
+			metric: 35,
+			labels: []uint32{16104},
+		},
+		{
+			name:   "multi-topology",
+			c:      &Constraints{MTID: 2},
+			nodes:  []string{"a", "d"},
+			metric: 5,
+			labels: []uint32{16004},
+		},
+		{
+			name: "no path with delay metric",
+			c:    &Constraints{Metric: DelayMetric},
+			fail: true,
+		},
+		{
+			name: "invalid metric",
+			c:    &Constraints{Metric: "hops"},
+			fail: true,
+		},
+	}
+	g := diamondGraph(t)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := ComputePath(g.Snapshot(), "a", "d", tt.c)
+			if err != nil {
+				if !tt.fail {
+					t.Fatalf("supposed to succeed but failed with error: %+v", err)
+				}
+				return
+			}
+			if tt.fail {
+				t.Fatalf("supposed to fail but succeeded")
+			}
+			if !reflect.DeepEqual(p.Nodes, tt.nodes) {
+				t.Errorf("path nodes do not match expected")
+				t.Logf("Differences: %+v", deep.Equal(p.Nodes, tt.nodes))
+			}
+			if p.Metric != tt.metric {
+				t.Errorf("expected metric %d but got %d", tt.metric, p.Metric)
+			}
+			if !reflect.DeepEqual(p.Labels, tt.labels) {
+				t.Errorf("label stack does not match expected")
+				t.Logf("Differences: %+v", deep.Equal(p.Labels, tt.labels))
+			}
+		})
+	}
+}
+
+func TestComputePathPseudonode(t *testing.T) {
+	g := NewGraph(nil)
+	publish(t, g, []testMsg{
+		{bmp.LSNodeMsg, `{"action":"add","igp_router_id":"0000.0000.0001","sr_algorithm":[0,128]}`},
+		{bmp.LSNodeMsg, `{"action":"add","igp_router_id":"0000.0000.0002","sr_algorithm":[0,128]}`},
+		{bmp.LSNodeMsg, `{"action":"add","igp_router_id":"0000.0000.0001.01","is_pseudonode":true}`},
+		{bmp.LSLinkMsg, `{"action":"add","igp_router_id":"0000.0000.0001","remote_igp_router_id":"0000.0000.0001.01","remote_is_pseudonode":true,"local_link_ip":"10.1.1.1","igp_metric":10}`},
+		{bmp.LSLinkMsg, `{"action":"add","igp_router_id":"0000.0000.0001.01","is_pseudonode":true,"remote_igp_router_id":"0000.0000.0002","igp_metric":0}`},
+	})
+	p, err := ComputePath(g.Snapshot(), "0000.0000.0001", "0000.0000.0002", &Constraints{Algorithm: 128})
+	if err != nil {
+		t.Fatalf("supposed to succeed but failed with error: %+v", err)
+	}
+	if nodes := []string{"0000.0000.0001", "0000.0000.0001.01", "0000.0000.0002"}; !reflect.DeepEqual(p.Nodes, nodes) {
+		t.Errorf("path nodes do not match expected")
+		t.Logf("Differences: %+v", deep.Equal(p.Nodes, nodes))
+	}
+	if !g.Snapshot().Nodes["0000.0000.0001.01"].Pseudonode {
+		t.Errorf("pseudonode is not flagged")
+	}
+}
+
+func TestHandler(t *testing.T) {
+	g := diamondGraph(t)
+	h := NewHandler(g)
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest("GET", "/topology/path?src=a&dst=d&metric=te", nil))
+	p := &Path{}
+	if err := json.Unmarshal(w.Body.Bytes(), p); err != nil {
+		t.Fatalf("failed to unmarshal path with error: %+v", err)
+	}
+	if !reflect.DeepEqual(p.Nodes, []string{"a", "c", "d"}) {
+		t.Errorf("unexpected path %+v", p.Nodes)
+	}
+
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest("GET", "/topology/snapshot", nil))
+	s := &Snapshot{}
+	if err := json.Unmarshal(w.Body.Bytes(), s); err != nil {
+		t.Fatalf("failed to unmarshal snapshot with error: %+v", err)
+	}
+	publish(t, g, []testMsg{
+		{bmp.LSLinkMsg, `{"action":"del","igp_router_id":"a","remote_igp_router_id":"c","local_link_id":2,"remote_link_id":1}`},
+	})
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest("GET", "/topology/diff?from="+strconv.FormatUint(s.Revision, 10), nil))
+	d := &Delta{}
+	if err := json.Unmarshal(w.Body.Bytes(), d); err != nil {
+		t.Fatalf("failed to unmarshal diff with error: %+v", err)
+	}
+	if len(d.RemovedEdges) != 1 || d.RemovedEdges[0].Key != "a_2_c_1" {
+		t.Errorf("unexpected diff %+v", d)
+	}
+
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest("GET", "/topology/diff?from=1", nil))
+	if w.Code != 410 {
+		t.Errorf("expected diff from unknown revision to fail, got code %d", w.Code)
+	}
+}
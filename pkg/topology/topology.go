@@ -0,0 +1,376 @@
+package topology
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sync"
+
+	"github.com/sbezverk/gobmp/pkg/bmp"
+	"github.com/sbezverk/gobmp/pkg/pub"
+	"github.com/sbezverk/gobmp/pkg/sr"
+)
+
+// Node defines a node of the topology graph, nodes are keyed by IGP router-id, pseudonodes representing
+// broadcast networks are keyed by IS-IS LAN ID or OSPF Designated Router's router-id and interface
+type Node struct {
+	IGPRouterID string         `json:"igp_router_id"`
+	RouterID    string         `json:"router_id,omitempty"`
+	Name        string         `json:"name,omitempty"`
+	ASN         uint32         `json:"asn,omitempty"`
+	Protocol    string         `json:"protocol,omitempty"`
+	AreaID      string         `json:"area_id,omitempty"`
+	DomainID    int64          `json:"domain_id"`
+	SRGB        []*sr.SIDRange `json:"srgb,omitempty"`
+	SRAlgorithm []int          `json:"sr_algorithm,omitempty"`
+	Pseudonode  bool           `json:"pseudonode,omitempty"`
+}
+
+// Edge defines an unidirectional link between two nodes of the topology graph
+type Edge struct {
+	Key           string   `json:"key"`
+	Local         string   `json:"local"`
+	Remote        string   `json:"remote"`
+	LocalIP       string   `json:"local_ip,omitempty"`
+	RemoteIP      string   `json:"remote_ip,omitempty"`
+	LocalLinkID   uint32   `json:"local_link_id,omitempty"`
+	RemoteLinkID  uint32   `json:"remote_link_id,omitempty"`
+	MTID          uint16   `json:"mt_id,omitempty"`
+	Protocol      string   `json:"protocol,omitempty"`
+	IGPMetric     uint32   `json:"igp_metric"`
+	TEMetric      uint32   `json:"te_metric,omitempty"`
+	Delay         uint32   `json:"delay,omitempty"`
+	AdminGroup    uint32   `json:"admin_group,omitempty"`
+	MaxLinkBWKbps uint64   `json:"max_link_bw_kbps,omitempty"`
+	SRLG          []uint32 `json:"srlg,omitempty"`
+	AdjSIDs       []uint32 `json:"adj_sids,omitempty"`
+	SRv6SIDs      []string `json:"srv6_sids,omitempty"`
+}
+
+// PrefixSID defines Prefix SID of a prefix for a specific algorithm, when Value is false,
+// SID is an index into originating node's SRGB, otherwise SID is the label.
+type PrefixSID struct {
+	SID       uint32 `json:"sid"`
+	Algorithm uint8  `json:"algo"`
+	Value     bool   `json:"value,omitempty"`
+}
+
+// Prefix defines a prefix originated by a node of the topology graph
+type Prefix struct {
+	Key       string       `json:"key"`
+	Node      string       `json:"node"`
+	Prefix    string       `json:"prefix"`
+	PrefixLen int32        `json:"prefix_len"`
+	MTID      uint16       `json:"mt_id,omitempty"`
+	Metric    uint32       `json:"metric"`
+	SIDs      []*PrefixSID `json:"sids,omitempty"`
+}
+
+// Snapshot defines a consistent copy of the topology graph at a specific revision,
+// the revision is incremented by every change of the graph, re-advertisements without
+// changes do not increment the revision.
+type Snapshot struct {
+	Revision uint64             `json:"revision"`
+	Nodes    map[string]*Node   `json:"nodes"`
+	Edges    map[string]*Edge   `json:"edges"`
+	Prefixes map[string]*Prefix `json:"prefixes"`
+}
+
+// Graph defines a Publisher consuming ls_node, ls_link and ls_prefix messages and maintaining
+// in-memory topology graph, changes of the graph are published as topology_event messages.
+type Graph interface {
+	pub.Publisher
+	Snapshot() *Snapshot
+}
+
+type graph struct {
+	sync.RWMutex
+	events   pub.Publisher
+	revision uint64
+	nodes    map[string]*Node
+	edges    map[string]*Edge
+	prefixes map[string]*Prefix
+}
+
+var _ Graph = &graph{}
+
+// lsNode defines fields of ls_node message used by the graph
+type lsNode struct {
+	Action      string         `json:"action"`
+	RouterIP    string         `json:"router_ip"`
+	IGPRouterID string         `json:"igp_router_id"`
+	RouterID    string         `json:"router_id"`
+	Name        string         `json:"name"`
+	ASN         uint32         `json:"asn"`
+	Protocol    string         `json:"protocol"`
+	AreaID      string         `json:"area_id"`
+	DomainID    int64          `json:"domain_id"`
+	SRGB        []*sr.SIDRange `json:"srgb"`
+	SRAlgorithm []int          `json:"sr_algorithm"`
+	Pseudonode  bool           `json:"is_pseudonode"`
+}
+
+// lsLink defines fields of ls_link message used by the graph
+type lsLink struct {
+	Action            string   `json:"action"`
+	RouterIP          string   `json:"router_ip"`
+	IGPRouterID       string   `json:"igp_router_id"`
+	RemoteIGPRouterID string   `json:"remote_igp_router_id"`
+	LocalLinkIP       string   `json:"local_link_ip"`
+	RemoteLinkIP      string   `json:"remote_link_ip"`
+	LocalLinkID       uint32   `json:"local_link_id"`
+	RemoteLinkID      uint32   `json:"remote_link_id"`
+	MTID              uint16   `json:"mt_id"`
+	Protocol          string   `json:"protocol"`
+	IGPMetric         uint32   `json:"igp_metric"`
+	TEDefaultMetric   uint32   `json:"te_default_metric"`
+	UnidirLinkDelay   uint32   `json:"unidir_link_delay"`
+	AdminGroup        uint32   `json:"admin_group"`
+	MaxLinkBWKbps     uint64   `json:"max_link_bw_kbps"`
+	SRLG              []uint32 `json:"srlg"`
+	LSAdjacencySID    []*struct {
+		SID uint32 `json:"sid"`
+	} `json:"ls_adjacency_sid"`
+	SRv6ENDXSID []*struct {
+		SID string `json:"sid"`
+	} `json:"srv6_endx_sid"`
+}
+
+// lsPrefix defines fields of ls_prefix message used by the graph
+type lsPrefix struct {
+	Action         string `json:"action"`
+	IGPRouterID    string `json:"igp_router_id"`
+	Prefix         string `json:"prefix"`
+	PrefixLen      int32  `json:"prefix_len"`
+	MTID           uint16 `json:"mt_id"`
+	PrefixMetric   uint32 `json:"prefix_metric"`
+	PrefixAttrTLVs *struct {
+		LSPrefixSID []*struct {
+			Flags *struct {
+				VFlag bool `json:"v_flag"`
+			} `json:"flags"`
+			Algorithm uint8  `json:"algo"`
+			SID       uint32 `json:"prefix_sid"`
+		} `json:"ls_prefix_sid"`
+	} `json:"prefix_attr_tlvs"`
+}
+
+func (g *graph) PublishMessage(msgType int, msgHash []byte, msg []byte) error {
+	var events []*Event
+	switch msgType {
+	case bmp.LSNodeMsg:
+		n := &lsNode{}
+		if err := json.Unmarshal(msg, n); err != nil {
+			return fmt.Errorf("failed to unmarshal ls_node message with error: %+v", err)
+		}
+		events = g.processNode(n)
+	case bmp.LSLinkMsg:
+		l := &lsLink{}
+		if err := json.Unmarshal(msg, l); err != nil {
+			return fmt.Errorf("failed to unmarshal ls_link message with error: %+v", err)
+		}
+		events = g.processLink(l)
+	case bmp.LSPrefixMsg:
+		p := &lsPrefix{}
+		if err := json.Unmarshal(msg, p); err != nil {
+			return fmt.Errorf("failed to unmarshal ls_prefix message with error: %+v", err)
+		}
+		g.processPrefix(p)
+	}
+
+	return g.publishEvents(events)
+}
+
+func (g *graph) Stop() {
+}
+
+func (g *graph) processNode(n *lsNode) []*Event {
+	if n.IGPRouterID == "" {
+		return nil
+	}
+	g.Lock()
+	defer g.Unlock()
+	old, ok := g.nodes[n.IGPRouterID]
+	if n.Action == "del" {
+		if ok {
+			delete(g.nodes, n.IGPRouterID)
+			g.revision++
+			return []*Event{newNodeEvent(NodeDisappeared, n.RouterIP, old)}
+		}
+		return nil
+	}
+	node := &Node{
+		IGPRouterID: n.IGPRouterID,
+		RouterID:    n.RouterID,
+		Name:        n.Name,
+		ASN:         n.ASN,
+		Protocol:    n.Protocol,
+		AreaID:      n.AreaID,
+		DomainID:    n.DomainID,
+		SRGB:        n.SRGB,
+		SRAlgorithm: n.SRAlgorithm,
+		Pseudonode:  n.Pseudonode,
+	}
+	if ok && reflect.DeepEqual(old, node) {
+		return nil
+	}
+	g.nodes[node.IGPRouterID] = node
+	g.revision++
+	if !ok {
+		return []*Event{newNodeEvent(NodeAppeared, n.RouterIP, node)}
+	}
+	if !reflect.DeepEqual(old.SRGB, node.SRGB) {
+		e := newNodeEvent(SRGBChange, n.RouterIP, node)
+		e.Old, e.New = old.SRGB, node.SRGB
+		return []*Event{e}
+	}
+
+	return nil
+}
+
+// mtKey returns the suffix of keys of objects of a topology, objects of the standard topology have no suffix
+func mtKey(mtid uint16) string {
+	if mtid == 0 {
+		return ""
+	}
+
+	return fmt.Sprintf("_mt%d", mtid)
+}
+
+// linkKey builds the key of an edge from its local and remote nodes and interfaces, the same link
+// of different topologies is a different edge
+func linkKey(l *lsLink) string {
+	local, remote := l.LocalLinkIP, l.RemoteLinkIP
+	if local == "" {
+		local = fmt.Sprintf("%d", l.LocalLinkID)
+	}
+	if remote == "" {
+		remote = fmt.Sprintf("%d", l.RemoteLinkID)
+	}
+
+	return l.IGPRouterID + "_" + local + "_" + l.RemoteIGPRouterID + "_" + remote + mtKey(l.MTID)
+}
+
+func (g *graph) processLink(l *lsLink) []*Event {
+	if l.IGPRouterID == "" || l.RemoteIGPRouterID == "" {
+		return nil
+	}
+	key := linkKey(l)
+	g.Lock()
+	defer g.Unlock()
+	old, ok := g.edges[key]
+	if l.Action == "del" {
+		if ok {
+			delete(g.edges, key)
+			g.revision++
+			return []*Event{newLinkEvent(LinkDown, l.RouterIP, old)}
+		}
+		return nil
+	}
+	e := &Edge{
+		Key:           key,
+		Local:         l.IGPRouterID,
+		Remote:        l.RemoteIGPRouterID,
+		LocalIP:       l.LocalLinkIP,
+		RemoteIP:      l.RemoteLinkIP,
+		LocalLinkID:   l.LocalLinkID,
+		RemoteLinkID:  l.RemoteLinkID,
+		MTID:          l.MTID,
+		Protocol:      l.Protocol,
+		IGPMetric:     l.IGPMetric,
+		TEMetric:      l.TEDefaultMetric,
+		Delay:         l.UnidirLinkDelay,
+		AdminGroup:    l.AdminGroup,
+		MaxLinkBWKbps: l.MaxLinkBWKbps,
+		SRLG:          l.SRLG,
+	}
+	for _, sid := range l.LSAdjacencySID {
+		e.AdjSIDs = append(e.AdjSIDs, sid.SID)
+	}
+	for _, sid := range l.SRv6ENDXSID {
+		e.SRv6SIDs = append(e.SRv6SIDs, sid.SID)
+	}
+	if ok && reflect.DeepEqual(old, e) {
+		return nil
+	}
+	g.edges[key] = e
+	g.revision++
+	if !ok {
+		return []*Event{newLinkEvent(LinkUp, l.RouterIP, e)}
+	}
+
+	return metricChanges(l.RouterIP, old, e)
+}
+
+func (g *graph) processPrefix(p *lsPrefix) {
+	if p.IGPRouterID == "" || p.Prefix == "" {
+		return
+	}
+	key := fmt.Sprintf("%s_%s/%d", p.IGPRouterID, p.Prefix, p.PrefixLen) + mtKey(p.MTID)
+	g.Lock()
+	defer g.Unlock()
+	if p.Action == "del" {
+		if _, ok := g.prefixes[key]; ok {
+			delete(g.prefixes, key)
+			g.revision++
+		}
+		return
+	}
+	prfx := &Prefix{
+		Key:       key,
+		Node:      p.IGPRouterID,
+		Prefix:    p.Prefix,
+		PrefixLen: p.PrefixLen,
+		MTID:      p.MTID,
+		Metric:    p.PrefixMetric,
+	}
+	if p.PrefixAttrTLVs != nil {
+		for _, sid := range p.PrefixAttrTLVs.LSPrefixSID {
+			psid := &PrefixSID{SID: sid.SID, Algorithm: sid.Algorithm}
+			if sid.Flags != nil {
+				psid.Value = sid.Flags.VFlag
+			}
+			prfx.SIDs = append(prfx.SIDs, psid)
+		}
+	}
+	if old, ok := g.prefixes[key]; ok && reflect.DeepEqual(old, prfx) {
+		return
+	}
+	g.prefixes[key] = prfx
+	g.revision++
+}
+
+// Snapshot returns a copy of the graph, nodes, edges and prefixes are never modified once stored
+// in the graph, so the snapshot shares them with the graph.
+func (g *graph) Snapshot() *Snapshot {
+	g.RLock()
+	defer g.RUnlock()
+	s := &Snapshot{
+		Revision: g.revision,
+		Nodes:    make(map[string]*Node, len(g.nodes)),
+		Edges:    make(map[string]*Edge, len(g.edges)),
+		Prefixes: make(map[string]*Prefix, len(g.prefixes)),
+	}
+	for k, v := range g.nodes {
+		s.Nodes[k] = v
+	}
+	for k, v := range g.edges {
+		s.Edges[k] = v
+	}
+	for k, v := range g.prefixes {
+		s.Prefixes[k] = v
+	}
+
+	return s
+}
+
+// NewGraph instantiates a new empty topology Graph, when events publisher is not nil, events derived
+// from changes of the graph are published to it.
+func NewGraph(events pub.Publisher) Graph {
+	return &graph{
+		events:   events,
+		nodes:    make(map[string]*Node),
+		edges:    make(map[string]*Edge),
+		prefixes: make(map[string]*Prefix),
+	}
+}
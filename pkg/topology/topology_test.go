@@ -0,0 +1,152 @@
+package topology
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/go-test/deep"
+	"github.com/sbezverk/gobmp/pkg/bmp"
+	"github.com/sbezverk/gobmp/pkg/sr"
+)
+
+type testMsg struct {
+	t   int
+	msg string
+}
+
+func publish(t *testing.T, g Graph, msgs []testMsg) {
+	for _, m := range msgs {
+		if err := g.PublishMessage(m.t, nil, []byte(m.msg)); err != nil {
+			t.Fatalf("failed to publish message with error: %+v", err)
+		}
+	}
+}
+
+func srgb(first, rng uint32) []*sr.SIDRange {
+	return []*sr.SIDRange{{FirstSID: first, Range: rng}}
+}
+
+func TestGraph(t *testing.T) {
+	tests := []struct {
+		name   string
+		msgs   []testMsg
+		expect *Snapshot
+	}{
+		{
+			name: "nodes, links and prefixes",
+			msgs: []testMsg{
+				{bmp.LSNodeMsg, `{"action":"add","igp_router_id":"0000.0000.0001","router_id":"10.0.0.1","name":"r1","protocol":"IS-IS Level 2","domain_id":0,"srgb":[{"first_sid":16000,"range":8000}],"sr_algorithm":[0,1]}`},
+				{bmp.LSNodeMsg, `{"action":"add","igp_router_id":"0000.0000.0002","router_id":"10.0.0.2","name":"r2","protocol":"IS-IS Level 2","domain_id":0}`},
+				{bmp.LSLinkMsg, `{"action":"add","igp_router_id":"0000.0000.0001","remote_igp_router_id":"0000.0000.0002","local_link_ip":"10.1.1.1","remote_link_ip":"10.1.1.2","igp_metric":10,"te_default_metric":20,"unidir_link_delay":1000,"srlg":[1,2],"ls_adjacency_sid":[{"flags":{},"weight":0,"sid":24000}]}`},
+				{bmp.LSPrefixMsg, `{"action":"add","igp_router_id":"0000.0000.0001","prefix":"10.0.0.1","prefix_len":32,"prefix_metric":0,"prefix_attr_tlvs":{"ls_prefix_sid":[{"flags":{},"algo":0,"prefix_sid":1}]}}`},
+			},
+			expect: &Snapshot{
+				Revision: 4,
+				Nodes: map[string]*Node{
+					"0000.0000.0001": {IGPRouterID: "0000.0000.0001", RouterID: "10.0.0.1", Name: "r1", Protocol: "IS-IS Level 2", SRGB: srgb(16000, 8000), SRAlgorithm: []int{0, 1}},
+					"0000.0000.0002": {IGPRouterID: "0000.0000.0002", RouterID: "10.0.0.2", Name: "r2", Protocol: "IS-IS Level 2"},
+				},
+				Edges: map[string]*Edge{
+					"0000.0000.0001_10.1.1.1_0000.0000.0002_10.1.1.2": {
+						Key:       "0000.0000.0001_10.1.1.1_0000.0000.0002_10.1.1.2",
+						Local:     "0000.0000.0001",
+						Remote:    "0000.0000.0002",
+						LocalIP:   "10.1.1.1",
+						RemoteIP:  "10.1.1.2",
+						IGPMetric: 10,
+						TEMetric:  20,
+						Delay:     1000,
+						SRLG:      []uint32{1, 2},
+						AdjSIDs:   []uint32{24000},
+					},
+				},
+				Prefixes: map[string]*Prefix{
+					"0000.0000.0001_10.0.0.1/32": {Key: "0000.0000.0001_10.0.0.1/32", Node: "0000.0000.0001", Prefix: "10.0.0.1", PrefixLen: 32, SIDs: []*PrefixSID{{SID: 1}}},
+				},
+			},
+		},
+		{
+			name: "withdrawn link and unnumbered link",
+			msgs: []testMsg{
+				{bmp.LSLinkMsg, `{"action":"add","igp_router_id":"a","remote_igp_router_id":"b","local_link_ip":"10.1.1.1","remote_link_ip":"10.1.1.2","igp_metric":10}`},
+				{bmp.LSLinkMsg, `{"action":"add","igp_router_id":"a","remote_igp_router_id":"b","local_link_id":5,"remote_link_id":6,"igp_metric":20}`},
+				{bmp.LSLinkMsg, `{"action":"del","igp_router_id":"a","remote_igp_router_id":"b","local_link_ip":"10.1.1.1","remote_link_ip":"10.1.1.2"}`},
+				{bmp.LSNodeMsg, `{"action":"del","igp_router_id":"c"}`},
+			},
+			expect: &Snapshot{
+				Revision: 3,
+				Nodes:    map[string]*Node{},
+				Edges: map[string]*Edge{
+					"a_5_b_6": {Key: "a_5_b_6", Local: "a", Remote: "b", LocalLinkID: 5, RemoteLinkID: 6, IGPMetric: 20},
+				},
+				Prefixes: map[string]*Prefix{},
+			},
+		},
+		{
+			name: "link and prefix of multiple topologies",
+			msgs: []testMsg{
+				{bmp.LSLinkMsg, `{"action":"add","igp_router_id":"a","remote_igp_router_id":"b","local_link_ip":"10.1.1.1","remote_link_ip":"10.1.1.2","mt_id":0,"igp_metric":10}`},
+				{bmp.LSLinkMsg, `{"action":"add","igp_router_id":"a","remote_igp_router_id":"b","local_link_ip":"10.1.1.1","remote_link_ip":"10.1.1.2","mt_id":3,"igp_metric":30}`},
+				{bmp.LSPrefixMsg, `{"action":"add","igp_router_id":"a","prefix":"239.1.0.0","prefix_len":16,"mt_id":3}`},
+			},
+			expect: &Snapshot{
+				Revision: 3,
+				Nodes:    map[string]*Node{},
+				Edges: map[string]*Edge{
+					"a_10.1.1.1_b_10.1.1.2":     {Key: "a_10.1.1.1_b_10.1.1.2", Local: "a", Remote: "b", LocalIP: "10.1.1.1", RemoteIP: "10.1.1.2", IGPMetric: 10},
+					"a_10.1.1.1_b_10.1.1.2_mt3": {Key: "a_10.1.1.1_b_10.1.1.2_mt3", Local: "a", Remote: "b", LocalIP: "10.1.1.1", RemoteIP: "10.1.1.2", MTID: 3, IGPMetric: 30},
+				},
+				Prefixes: map[string]*Prefix{
+					"a_239.1.0.0/16_mt3": {Key: "a_239.1.0.0/16_mt3", Node: "a", Prefix: "239.1.0.0", PrefixLen: 16, MTID: 3},
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewGraph(nil)
+			publish(t, g, tt.msgs)
+			s := g.Snapshot()
+			if !reflect.DeepEqual(s, tt.expect) {
+				t.Errorf("snapshot does not match expected")
+				t.Logf("Differences: %+v", deep.Equal(s, tt.expect))
+			}
+		})
+	}
+}
+
+func TestDiff(t *testing.T) {
+	g := NewGraph(nil)
+	publish(t, g, []testMsg{
+		{bmp.LSNodeMsg, `{"action":"add","igp_router_id":"a","name":"a"}`},
+		{bmp.LSNodeMsg, `{"action":"add","igp_router_id":"b","name":"b"}`},
+		{bmp.LSLinkMsg, `{"action":"add","igp_router_id":"a","remote_igp_router_id":"b","local_link_id":1,"remote_link_id":2,"igp_metric":10}`},
+	})
+	from := g.Snapshot()
+	publish(t, g, []testMsg{
+		{bmp.LSNodeMsg, `{"action":"del","igp_router_id":"b"}`},
+		{bmp.LSNodeMsg, `{"action":"add","igp_router_id":"c","name":"c"}`},
+		{bmp.LSLinkMsg, `{"action":"add","igp_router_id":"a","remote_igp_router_id":"b","local_link_id":1,"remote_link_id":2,"igp_metric":30}`},
+		// Re-advertisement without changes does not show up in the delta
+		{bmp.LSNodeMsg, `{"action":"add","igp_router_id":"a","name":"a"}`},
+	})
+	to := g.Snapshot()
+	expect := &Delta{
+		From:         3,
+		To:           6,
+		AddedNodes:   []*Node{{IGPRouterID: "c", Name: "c"}},
+		RemovedNodes: []*Node{{IGPRouterID: "b", Name: "b"}},
+		UpdatedEdges: []*Edge{{Key: "a_1_b_2", Local: "a", Remote: "b", LocalLinkID: 1, RemoteLinkID: 2, IGPMetric: 30}},
+	}
+	d := Diff(from, to)
+	if !reflect.DeepEqual(d, expect) {
+		t.Errorf("delta does not match expected")
+		t.Logf("Differences: %+v", deep.Equal(d, expect))
+	}
+	if !Diff(to, g.Snapshot()).IsEmpty() {
+		t.Errorf("expected empty delta between identical snapshots")
+	}
+	if d := Diff(nil, from); len(d.AddedNodes) != 2 || len(d.AddedEdges) != 1 {
+		t.Errorf("expected all objects to be added when diffing from nil snapshot, got %+v", d)
+	}
+}
@@ -0,0 +1,286 @@
+package tracing
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/golang/glog"
+)
+
+const (
+	// exportQueueLength defines the number of ended spans buffered for export, when the collector
+	// does not keep up, spans are dropped.
+	exportQueueLength = 8192
+	// exportBatchSize defines the maximum number of spans posted in a single export request
+	exportBatchSize = 512
+	// exportInterval defines how often buffered spans are exported
+	exportInterval = 5 * time.Second
+	// tracesPath is the path of OTLP/HTTP traces endpoint, it is used when the endpoint does not carry a path
+	tracesPath = "/v1/traces"
+	// instrumentationScope identifies spans of gobmp in tracing backends
+	instrumentationScope = "github.com/sbezverk/gobmp"
+)
+
+// OTLP span kinds
+const (
+	spanKindInternal = 1
+	spanKindServer   = 2
+)
+
+// ExporterConfig defines OTLP/HTTP exporter configuration, Endpoint is the url of OpenTelemetry collector,
+// for example http://localhost:4318, Headers are added to every export request, for example authentication
+// of a tracing backend.
+type ExporterConfig struct {
+	Endpoint    string
+	ServiceName string
+	Headers     map[string]string
+}
+
+type exporter struct {
+	url      string
+	service  string
+	headers  map[string]string
+	client   *http.Client
+	queue    chan *Span
+	stopCh   chan struct{}
+	done     chan struct{}
+	interval time.Duration
+}
+
+func (e *exporter) add(s *Span) {
+	select {
+	case e.queue <- s:
+	default:
+		glog.V(5).Infof("trace export queue is full, dropping span %s", s.Name)
+	}
+}
+
+func (e *exporter) stop() {
+	close(e.stopCh)
+	<-e.done
+}
+
+func (e *exporter) worker() {
+	defer close(e.done)
+	ticker := time.NewTicker(e.interval)
+	defer ticker.Stop()
+	batch := make([]*Span, 0, exportBatchSize)
+	for {
+		select {
+		case s := <-e.queue:
+			if batch = append(batch, s); len(batch) == exportBatchSize {
+				e.post(batch)
+				batch = batch[:0]
+			}
+		case <-ticker.C:
+			e.post(batch)
+			batch = batch[:0]
+		case <-e.stopCh:
+			// Exporting spans ended before the stop
+			for len(e.queue) != 0 {
+				batch = append(batch, <-e.queue)
+			}
+			e.post(batch)
+			return
+		}
+	}
+}
+
+func (e *exporter) post(batch []*Span) {
+	if len(batch) == 0 {
+		return
+	}
+	b, err := json.Marshal(e.request(batch))
+	if err != nil {
+		glog.Errorf("failed to marshal %d spans with error: %+v", len(batch), err)
+		return
+	}
+	req, err := http.NewRequest(http.MethodPost, e.url, bytes.NewReader(b))
+	if err != nil {
+		glog.Errorf("failed to create trace export request with error: %+v", err)
+		return
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range e.headers {
+		req.Header.Set(k, v)
+	}
+	resp, err := e.client.Do(req)
+	if err != nil {
+		glog.Errorf("failed to export %d spans to %s with error: %+v", len(batch), e.url, err)
+		return
+	}
+	resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		glog.Errorf("failed to export %d spans to %s, collector returned status: %s", len(batch), e.url, resp.Status)
+	}
+}
+
+// OTLP/JSON encoding of ExportTraceServiceRequest, trace and span ids are hex encoded and timestamps
+// are strings of nanoseconds since epoch.
+type otlpValue struct {
+	StringValue *string `json:"stringValue,omitempty"`
+	IntValue    *string `json:"intValue,omitempty"`
+	BoolValue   *bool   `json:"boolValue,omitempty"`
+}
+
+type otlpAttribute struct {
+	Key   string    `json:"key"`
+	Value otlpValue `json:"value"`
+}
+
+type otlpSpan struct {
+	TraceID           string          `json:"traceId"`
+	SpanID            string          `json:"spanId"`
+	ParentSpanID      string          `json:"parentSpanId,omitempty"`
+	Name              string          `json:"name"`
+	Kind              int             `json:"kind"`
+	StartTimeUnixNano string          `json:"startTimeUnixNano"`
+	EndTimeUnixNano   string          `json:"endTimeUnixNano"`
+	Attributes        []otlpAttribute `json:"attributes,omitempty"`
+}
+
+type otlpScope struct {
+	Name string `json:"name"`
+}
+
+type otlpScopeSpans struct {
+	Scope otlpScope   `json:"scope"`
+	Spans []*otlpSpan `json:"spans"`
+}
+
+type otlpResource struct {
+	Attributes []otlpAttribute `json:"attributes"`
+}
+
+type otlpResourceSpans struct {
+	Resource   otlpResource     `json:"resource"`
+	ScopeSpans []otlpScopeSpans `json:"scopeSpans"`
+}
+
+type otlpRequest struct {
+	ResourceSpans []otlpResourceSpans `json:"resourceSpans"`
+}
+
+func (e *exporter) request(batch []*Span) *otlpRequest {
+	spans := make([]*otlpSpan, 0, len(batch))
+	for _, s := range batch {
+		spans = append(spans, newOTLPSpan(s))
+	}
+
+	return &otlpRequest{
+		ResourceSpans: []otlpResourceSpans{
+			{
+				Resource: otlpResource{
+					Attributes: []otlpAttribute{newOTLPAttribute("service.name", e.service)},
+				},
+				ScopeSpans: []otlpScopeSpans{
+					{
+						Scope: otlpScope{Name: instrumentationScope},
+						Spans: spans,
+					},
+				},
+			},
+		},
+	}
+}
+
+func newOTLPSpan(s *Span) *otlpSpan {
+	o := &otlpSpan{
+		TraceID:           hex.EncodeToString(s.TraceID[:]),
+		SpanID:            hex.EncodeToString(s.SpanID[:]),
+		Name:              s.Name,
+		Kind:              spanKindServer,
+		StartTimeUnixNano: strconv.FormatInt(s.Start.UnixNano(), 10),
+		EndTimeUnixNano:   strconv.FormatInt(s.End.UnixNano(), 10),
+	}
+	// Root span of a BMP message is the receive of the message by the collector, other spans are its stages
+	if s.ParentID != [8]byte{} {
+		o.ParentSpanID = hex.EncodeToString(s.ParentID[:])
+		o.Kind = spanKindInternal
+	}
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	keys := make([]string, 0, len(s.Attributes))
+	for k := range s.Attributes {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		o.Attributes = append(o.Attributes, newOTLPAttribute(k, s.Attributes[k]))
+	}
+
+	return o
+}
+
+func newOTLPAttribute(key string, value interface{}) otlpAttribute {
+	a := otlpAttribute{Key: key}
+	switch v := value.(type) {
+	case bool:
+		a.Value.BoolValue = &v
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		i := fmt.Sprintf("%d", v)
+		a.Value.IntValue = &i
+	case string:
+		a.Value.StringValue = &v
+	default:
+		str := fmt.Sprintf("%v", v)
+		a.Value.StringValue = &str
+	}
+
+	return a
+}
+
+// ParseHeaders returns headers of export requests from comma separated list of key=value pairs
+func ParseHeaders(s string) (map[string]string, error) {
+	headers := make(map[string]string)
+	if s == "" {
+		return headers, nil
+	}
+	for _, h := range strings.Split(s, ",") {
+		kv := strings.SplitN(h, "=", 2)
+		if len(kv) != 2 || strings.TrimSpace(kv[0]) == "" {
+			return nil, fmt.Errorf("invalid header %q, header must be in key=value format", h)
+		}
+		headers[strings.TrimSpace(kv[0])] = strings.TrimSpace(kv[1])
+	}
+
+	return headers, nil
+}
+
+func newExporter(config *ExporterConfig) (*exporter, error) {
+	u, err := url.Parse(config.Endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("invalid OTLP endpoint %s with error: %+v", config.Endpoint, err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, fmt.Errorf("invalid OTLP endpoint %s, only http and https endpoints are supported", config.Endpoint)
+	}
+	if u.Path == "" || u.Path == "/" {
+		u.Path = tracesPath
+	}
+	service := config.ServiceName
+	if service == "" {
+		service = "gobmp"
+	}
+	e := &exporter{
+		url:      u.String(),
+		service:  service,
+		headers:  config.Headers,
+		client:   &http.Client{Timeout: 10 * time.Second},
+		queue:    make(chan *Span, exportQueueLength),
+		stopCh:   make(chan struct{}),
+		done:     make(chan struct{}),
+		interval: exportInterval,
+	}
+	go e.worker()
+
+	return e, nil
+}
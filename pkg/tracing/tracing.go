@@ -0,0 +1,128 @@
+package tracing
+
+import (
+	"fmt"
+	"math/rand"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Span defines a single stage of processing of a BMP message, spans of the same BMP message share the trace id.
+// Methods of Span are safe to call on nil Span, nil is returned by Tracer for messages which are not sampled,
+// so stages of the pipeline do not need to check whether tracing is enabled.
+type Span struct {
+	TraceID    [16]byte
+	SpanID     [8]byte
+	ParentID   [8]byte
+	Name       string
+	Start      time.Time
+	End        time.Time
+	Attributes map[string]interface{}
+	tracer     *tracer
+	mtx        sync.Mutex
+	ended      int32
+}
+
+// Tracer defines methods to start root spans of BMP messages and to stop exporting of ended spans
+type Tracer interface {
+	Start(name string) *Span
+	Stop()
+}
+
+// Child starts a new span of the stage of the same BMP message
+func (s *Span) Child(name string) *Span {
+	if s == nil {
+		return nil
+	}
+	c := s.tracer.newSpan(name)
+	c.TraceID = s.TraceID
+	c.ParentID = s.SpanID
+
+	return c
+}
+
+// SetAttribute sets the attribute of the span, values are strings, integers or booleans, other values
+// are exported as strings.
+func (s *Span) SetAttribute(key string, value interface{}) {
+	if s == nil {
+		return
+	}
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	s.Attributes[key] = value
+}
+
+// Finish ends the span and passes it to the exporter, only the first call ends the span
+func (s *Span) Finish() {
+	if s == nil || !atomic.CompareAndSwapInt32(&s.ended, 0, 1) {
+		return
+	}
+	s.End = time.Now()
+	s.tracer.export(s)
+}
+
+type tracer struct {
+	sync.Mutex
+	rnd      *rand.Rand
+	ratio    float64
+	exporter *exporter
+}
+
+var _ Tracer = &tracer{}
+
+// Start starts the root span of a BMP message, nil is returned when the message is not sampled
+func (t *tracer) Start(name string) *Span {
+	t.Lock()
+	sampled := t.ratio >= 1 || t.rnd.Float64() < t.ratio
+	t.Unlock()
+	if !sampled {
+		return nil
+	}
+	s := t.newSpan(name)
+	t.Lock()
+	t.rnd.Read(s.TraceID[:])
+	t.Unlock()
+
+	return s
+}
+
+func (t *tracer) Stop() {
+	t.exporter.stop()
+}
+
+func (t *tracer) newSpan(name string) *Span {
+	s := &Span{
+		Name:       name,
+		Start:      time.Now(),
+		Attributes: make(map[string]interface{}),
+		tracer:     t,
+	}
+	t.Lock()
+	t.rnd.Read(s.SpanID[:])
+	t.Unlock()
+
+	return s
+}
+
+func (t *tracer) export(s *Span) {
+	t.exporter.add(s)
+}
+
+// NewTracer instantiates a new instance of Tracer exporting spans to OpenTelemetry collector over OTLP/HTTP,
+// ratio is the share of BMP messages which are traced, 1 traces all messages.
+func NewTracer(config *ExporterConfig, ratio float64) (Tracer, error) {
+	if ratio <= 0 || ratio > 1 {
+		return nil, fmt.Errorf("invalid trace sampling ratio %v, the ratio must be greater than 0 and not greater than 1", ratio)
+	}
+	e, err := newExporter(config)
+	if err != nil {
+		return nil, err
+	}
+
+	return &tracer{
+		rnd:      rand.New(rand.NewSource(time.Now().UnixNano())),
+		ratio:    ratio,
+		exporter: e,
+	}, nil
+}
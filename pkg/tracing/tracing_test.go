@@ -0,0 +1,140 @@
+package tracing
+
+import (
+	"encoding/json"
+	"math/rand"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-test/deep"
+)
+
+func TestTracer(t *testing.T) {
+	requests := make(chan *otlpRequest, 1)
+	auth := ""
+	collector := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != tracesPath {
+			t.Errorf("expected export to %s, got %s", tracesPath, r.URL.Path)
+		}
+		auth = r.Header.Get("Authorization")
+		req := &otlpRequest{}
+		if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+			t.Errorf("failed to decode export request with error: %+v", err)
+		}
+		requests <- req
+	}))
+	defer collector.Close()
+	tr, err := NewTracer(&ExporterConfig{Endpoint: collector.URL, Headers: map[string]string{"Authorization": "Bearer token"}}, 1)
+	if err != nil {
+		t.Fatalf("failed to create tracer with error: %+v", err)
+	}
+	root := tr.Start("bmp.message")
+	root.SetAttribute("bmp.session", "192.0.2.1")
+	root.SetAttribute("bmp.message_type", uint8(0))
+	parse := root.Child("bmp.parse")
+	parse.Finish()
+	root.Finish()
+	// Finishing the span again does not export it again
+	root.Finish()
+	tr.Stop()
+	req := <-requests
+	if auth != "Bearer token" {
+		t.Errorf("expected authorization header of export request, got %q", auth)
+	}
+	if len(req.ResourceSpans) != 1 || len(req.ResourceSpans[0].ScopeSpans) != 1 {
+		t.Fatalf("expected a single resource and scope, got %+v", req)
+	}
+	service := "gobmp"
+	if diff := deep.Equal(req.ResourceSpans[0].Resource.Attributes, []otlpAttribute{{Key: "service.name", Value: otlpValue{StringValue: &service}}}); len(diff) != 0 {
+		t.Errorf("expected and actual resource attributes do not match, differences: %+v", diff)
+	}
+	spans := req.ResourceSpans[0].ScopeSpans[0].Spans
+	if len(spans) != 2 {
+		t.Fatalf("expected 2 spans, got %d", len(spans))
+	}
+	p, r := spans[0], spans[1]
+	if p.Name != "bmp.parse" || r.Name != "bmp.message" {
+		t.Fatalf("expected spans bmp.parse and bmp.message, got %s and %s", p.Name, r.Name)
+	}
+	if p.TraceID != r.TraceID || p.ParentSpanID != r.SpanID || r.ParentSpanID != "" {
+		t.Errorf("bmp.parse span %+v is not a child of bmp.message span %+v", p, r)
+	}
+	if p.Kind != spanKindInternal || r.Kind != spanKindServer {
+		t.Errorf("expected internal bmp.parse span and server bmp.message span, got kinds %d and %d", p.Kind, r.Kind)
+	}
+	session, msgType := "192.0.2.1", "0"
+	expect := []otlpAttribute{
+		{Key: "bmp.message_type", Value: otlpValue{IntValue: &msgType}},
+		{Key: "bmp.session", Value: otlpValue{StringValue: &session}},
+	}
+	if diff := deep.Equal(r.Attributes, expect); len(diff) != 0 {
+		t.Errorf("expected and actual attributes of bmp.message span do not match, differences: %+v", diff)
+	}
+}
+
+func TestNotSampled(t *testing.T) {
+	tr := &tracer{rnd: rand.New(rand.NewSource(1)), ratio: 0}
+	root := tr.Start("bmp.message")
+	// Stages of not sampled message are not traced
+	child := root.Child("bmp.parse")
+	child.SetAttribute("bmp.session", "192.0.2.1")
+	child.Finish()
+	root.Finish()
+	if root != nil || child != nil {
+		t.Errorf("expected nil spans of not sampled message, got %+v and %+v", root, child)
+	}
+}
+
+func TestNewTracer(t *testing.T) {
+	tests := []struct {
+		name     string
+		endpoint string
+		ratio    float64
+		fail     bool
+	}{
+		{
+			name:     "valid endpoint",
+			endpoint: "https://otel.example.com:4318/v1/traces",
+			ratio:    0.1,
+		},
+		{
+			name:     "grpc endpoint",
+			endpoint: "grpc://localhost:4317",
+			ratio:    1,
+			fail:     true,
+		},
+		{
+			name:     "zero ratio",
+			endpoint: "http://localhost:4318",
+			fail:     true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tr, err := NewTracer(&ExporterConfig{Endpoint: tt.endpoint}, tt.ratio)
+			if err != nil && !tt.fail {
+				t.Fatalf("supposed to succeed but failed with error: %+v", err)
+			}
+			if err == nil && tt.fail {
+				t.Fatalf("supposed to fail but succeeded")
+			}
+			if tr != nil {
+				tr.Stop()
+			}
+		})
+	}
+}
+
+func TestParseHeaders(t *testing.T) {
+	headers, err := ParseHeaders("Authorization=Bearer token, x-scope=bmp=1")
+	if err != nil {
+		t.Fatalf("failed to parse headers with error: %+v", err)
+	}
+	if diff := deep.Equal(headers, map[string]string{"Authorization": "Bearer token", "x-scope": "bmp=1"}); len(diff) != 0 {
+		t.Errorf("expected and actual headers do not match, differences: %+v", diff)
+	}
+	if _, err := ParseHeaders("Authorization"); err == nil {
+		t.Errorf("header without value is supposed to fail")
+	}
+}
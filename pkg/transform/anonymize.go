@@ -0,0 +1,224 @@
+package transform
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net"
+	"strings"
+)
+
+// Methods of anonymization of a field
+const (
+	// AnonymizeHash replaces an address with an address of the same family derived from keyed hash of the
+	// address, other values are replaced with hex encoded keyed hash. Equal values get equal hashes.
+	AnonymizeHash = "hash"
+	// AnonymizeTruncate clears low order bits of an address beyond the truncation length
+	AnonymizeTruncate = "truncate"
+)
+
+const (
+	defaultTruncateIPv4 = 24
+	defaultTruncateIPv6 = 48
+)
+
+// anonymizer replaces values of fields with hashed or truncated values, "prefix" field is anonymized
+// together with "prefix_len" field, so the result is a valid prefix.
+type anonymizer struct {
+	fields map[string]string
+	key    []byte
+	v4     int
+	v6     int
+}
+
+func newAnonymizer(r *Rule) (*anonymizer, error) {
+	a := &anonymizer{
+		fields: r.Anonymize,
+		key:    []byte(r.AnonymizeKey),
+		v4:     r.TruncateIPv4,
+		v6:     r.TruncateIPv6,
+	}
+	for f, m := range a.fields {
+		if m != AnonymizeHash && m != AnonymizeTruncate {
+			return nil, fmt.Errorf("unknown anonymization method %s of field %s", m, f)
+		}
+	}
+	if a.v4 == 0 {
+		a.v4 = defaultTruncateIPv4
+	}
+	if a.v6 == 0 {
+		a.v6 = defaultTruncateIPv6
+	}
+	if a.v4 < 0 || a.v4 > 32 || a.v6 < 0 || a.v6 > 128 {
+		return nil, fmt.Errorf("invalid truncation lengths %d and %d", a.v4, a.v6)
+	}
+	if len(a.key) == 0 {
+		// Without configured key, hashes are not stable across restarts
+		a.key = make([]byte, 32)
+		if _, err := rand.Read(a.key); err != nil {
+			return nil, err
+		}
+	}
+
+	return a, nil
+}
+
+func (a *anonymizer) hash(b []byte) []byte {
+	h := hmac.New(sha256.New, a.key)
+	h.Write(b)
+
+	return h.Sum(nil)
+}
+
+// truncation returns truncation length of the address family of ip
+func (a *anonymizer) truncation(ip net.IP) (net.IP, int) {
+	if v4 := ip.To4(); v4 != nil {
+		return v4, a.v4
+	}
+
+	return ip.To16(), a.v6
+}
+
+// address returns anonymized address keeping its address family
+func (a *anonymizer) address(method string, ip net.IP) net.IP {
+	ip, l := a.truncation(ip)
+	if method == AnonymizeTruncate {
+		return ip.Mask(net.CIDRMask(l, len(ip)*8))
+	}
+
+	return net.IP(a.hash(ip)[:len(ip)])
+}
+
+func (a *anonymizer) prefix(method string, msg map[string]interface{}) {
+	s, _ := msg["prefix"].(string)
+	ip := net.ParseIP(s)
+	pl, ok := prefixLen(msg)
+	if ip == nil || !ok {
+		return
+	}
+	ip, l := a.truncation(ip)
+	if pl < 0 || pl > len(ip)*8 {
+		return
+	}
+	if method == AnonymizeTruncate {
+		if pl > l {
+			pl = l
+		}
+	} else {
+		// Hashing the network and its length, so prefixes of different lengths do not collide
+		ip = net.IP(a.hash(append(ip.Mask(net.CIDRMask(pl, len(ip)*8)), byte(pl)))[:len(ip)])
+	}
+	msg["prefix"] = ip.Mask(net.CIDRMask(pl, len(ip)*8)).String()
+	msg["prefix_len"] = pl
+}
+
+// derivedHashes are fields carrying unkeyed hashes of addresses, which can be reversed by hashing all
+// addresses, they are replaced with keyed hashes by every rule anonymizing fields.
+var derivedHashes = map[string]bool{
+	"hash":           true,
+	"_key":           true,
+	"router_hash":    true,
+	"peer_hash":      true,
+	"base_attr_hash": true,
+}
+
+// rawAddresses are fields carrying raw attribute octets with the address of the field they map to, they are
+// removed when the address field is anonymized.
+var rawAddresses = map[string]string{
+	"aggregator":     "aggregator_address",
+	"as4_aggregator": "aggregator_address",
+}
+
+// hashString returns hex encoded keyed hash of s
+func (a *anonymizer) hashString(s string) string {
+	return hex.EncodeToString(a.hash([]byte(s))[:16])
+}
+
+// value returns anonymized value of a field, lists of addresses are anonymized address by address
+func (a *anonymizer) value(method string, v interface{}) interface{} {
+	switch v := v.(type) {
+	case string:
+		if v == "" {
+			return v
+		}
+		if ip := net.ParseIP(v); ip != nil {
+			return a.address(method, ip).String()
+		}
+		// cluster_list carries comma separated addresses
+		if l := strings.Split(v, ", "); len(l) > 1 {
+			ok := true
+			for i, s := range l {
+				ip := net.ParseIP(s)
+				if ip == nil {
+					ok = false
+					break
+				}
+				l[i] = a.address(method, ip).String()
+			}
+			if ok {
+				return strings.Join(l, ", ")
+			}
+		}
+		if method == AnonymizeHash {
+			return a.hashString(v)
+		}
+	case []interface{}:
+		for i := range v {
+			v[i] = a.value(method, v[i])
+		}
+	case map[string]interface{}:
+		a.anonymize(v)
+	}
+
+	return v
+}
+
+// nested anonymizes fields of objects nested in v
+func (a *anonymizer) nested(v interface{}) {
+	switch v := v.(type) {
+	case []interface{}:
+		for _, e := range v {
+			a.nested(e)
+		}
+	case map[string]interface{}:
+		a.anonymize(v)
+	}
+}
+
+// anonymize anonymizes fields of msg and of objects nested in it
+func (a *anonymizer) anonymize(msg map[string]interface{}) {
+	for f, v := range msg {
+		if derivedHashes[f] {
+			if s, ok := v.(string); ok && s != "" {
+				msg[f] = a.hashString(s)
+			}
+			continue
+		}
+		if addr, ok := rawAddresses[f]; ok && a.fields[addr] != "" {
+			delete(msg, f)
+			continue
+		}
+		if f == "prefix" {
+			continue
+		}
+		if m, ok := a.fields[f]; ok {
+			msg[f] = a.value(m, v)
+			continue
+		}
+		a.nested(v)
+	}
+	if m, ok := a.fields["prefix"]; ok {
+		a.prefix(m, msg)
+	}
+}
+
+// hashKey returns keyed hash of the key of the published message, the key is derived from the router address
+func (a *anonymizer) hashKey(key []byte) []byte {
+	if len(key) == 0 {
+		return key
+	}
+
+	return []byte(a.hashString(string(key)))
+}
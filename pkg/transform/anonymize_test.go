@@ -0,0 +1,172 @@
+package transform
+
+import (
+	"encoding/json"
+	"net"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/go-test/deep"
+	"github.com/sbezverk/gobmp/pkg/bmp"
+	"github.com/sbezverk/gobmp/pkg/pub"
+)
+
+func TestAnonymizeTruncate(t *testing.T) {
+	tests := []struct {
+		name   string
+		rule   *Rule
+		input  map[string]interface{}
+		expect map[string]interface{}
+		fail   bool
+	}{
+		{
+			name: "default lengths",
+			rule: &Rule{Anonymize: map[string]string{"prefix": AnonymizeTruncate, "peer_ip": AnonymizeTruncate, "router_ip": AnonymizeTruncate}},
+			input: map[string]interface{}{
+				"prefix": "10.1.2.128", "prefix_len": json.Number("25"), "peer_ip": "2001:db8:1:2::1", "router_ip": "192.0.2.1",
+			},
+			expect: map[string]interface{}{
+				"prefix": "10.1.2.0", "prefix_len": 24, "peer_ip": "2001:db8:1::", "router_ip": "192.0.2.0",
+			},
+		},
+		{
+			name: "configured lengths keep shorter prefix",
+			rule: &Rule{Anonymize: map[string]string{"prefix": AnonymizeTruncate, "router_id": AnonymizeTruncate}, TruncateIPv4: 16},
+			input: map[string]interface{}{
+				"prefix": "10.0.0.0", "prefix_len": json.Number("8"), "router_id": "10.1.2.3", "router_name": "r1",
+			},
+			expect: map[string]interface{}{
+				"prefix": "10.0.0.0", "prefix_len": 8, "router_id": "10.1.0.0", "router_name": "r1",
+			},
+		},
+		{
+			name: "unknown method",
+			rule: &Rule{Anonymize: map[string]string{"prefix": "encrypt"}},
+			fail: true,
+		},
+		{
+			name: "invalid length",
+			rule: &Rule{Anonymize: map[string]string{"prefix": AnonymizeTruncate}, TruncateIPv6: 129},
+			fail: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tr, err := newRuleTransformer(tt.rule)
+			if err != nil && !tt.fail {
+				t.Fatalf("expected to succeed but failed with error: %+v", err)
+			}
+			if err == nil && tt.fail {
+				t.Fatalf("expected to fail but succeeded")
+			}
+			if err != nil {
+				return
+			}
+			got, err := tr.Transform(0, tt.input)
+			if err != nil {
+				t.Fatalf("failed to transform message with error: %+v", err)
+			}
+			if !reflect.DeepEqual(tt.expect, got) {
+				t.Logf("Differences: %+v", deep.Equal(tt.expect, got))
+				t.Fatalf("expected message %+v does not match actual message %+v", tt.expect, got)
+			}
+		})
+	}
+}
+
+func TestAnonymizeHash(t *testing.T) {
+	rule := &Rule{
+		Anonymize:    map[string]string{"prefix": AnonymizeHash, "peer_ip": AnonymizeHash, "peer_hash": AnonymizeHash},
+		AnonymizeKey: "secret",
+	}
+	anonymize := func(r *Rule) map[string]interface{} {
+		tr, err := newRuleTransformer(r)
+		if err != nil {
+			t.Fatalf("failed to create transformer with error: %+v", err)
+		}
+		msg, err := tr.Transform(0, map[string]interface{}{
+			"prefix": "2001:db8:1::", "prefix_len": json.Number("48"), "peer_ip": "192.0.2.2", "peer_hash": "4a9d8f2c",
+		})
+		if err != nil {
+			t.Fatalf("failed to transform message with error: %+v", err)
+		}
+		return msg
+	}
+	m1 := anonymize(rule)
+	if !reflect.DeepEqual(m1, anonymize(rule)) {
+		t.Fatalf("hashes with the same key are not equal")
+	}
+	if reflect.DeepEqual(m1, anonymize(&Rule{Anonymize: rule.Anonymize, AnonymizeKey: "other"})) {
+		t.Fatalf("hashes with different keys are equal")
+	}
+	_, n, err := net.ParseCIDR(m1["prefix"].(string) + "/48")
+	if err != nil || n.IP.To4() != nil || n.IP.String() != m1["prefix"] || m1["prefix_len"] != 48 {
+		t.Fatalf("hashed prefix %v/%v is not a valid IPv6 prefix", m1["prefix"], m1["prefix_len"])
+	}
+	if m1["prefix"] == "2001:db8:1::" {
+		t.Fatalf("prefix is not hashed")
+	}
+	if ip := net.ParseIP(m1["peer_ip"].(string)); ip == nil || ip.To4() == nil || ip.String() == "192.0.2.2" {
+		t.Fatalf("hashed peer ip %v is not a different IPv4 address", m1["peer_ip"])
+	}
+	if h := m1["peer_hash"].(string); len(h) != 32 || h == "4a9d8f2c" {
+		t.Fatalf("peer hash %s is not hashed", h)
+	}
+}
+
+func TestAnonymizeEnvelope(t *testing.T) {
+	ts, err := (&Config{Rules: []*Rule{
+		{
+			Anonymize: map[string]string{
+				"prefix": AnonymizeHash, "router_ip": AnonymizeHash, "peer_ip": AnonymizeHash, "nexthop": AnonymizeHash,
+				"originator_id": AnonymizeHash, "cluster_list": AnonymizeHash, "aggregator_address": AnonymizeHash,
+			},
+			AnonymizeKey: "secret",
+		},
+	}}).Transformers()
+	if err != nil {
+		t.Fatalf("failed to initialize transforms with error: %+v", err)
+	}
+	r := &recorder{msgs: make([]string, 0)}
+	input := `{"_key":"7f0e2b4c","hash":"7f0e2b4c","router_hash":"c0a8b1d2","peer_hash":"4a9d8f2c",` +
+		`"router_ip":"192.0.2.1","peer_ip":"192.0.2.2","nexthop":"198.51.100.1","prefix":"203.0.113.0","prefix_len":24,` +
+		`"base_attrs":{"base_attr_hash":"9e1c","nexthop":"198.51.100.1","originator_id":"198.51.100.2",` +
+		`"cluster_list":"198.51.100.3, 198.51.100.4","aggregator_as":65000,"aggregator_address":"198.51.100.5",` +
+		`"aggregator":"AAD96MYzZAU="}}`
+	p := NewPublisher(ts, pub.NewEnvelope("c1", r))
+	if err := p.PublishMessage(bmp.UnicastPrefixV4Msg, []byte("c0a8b1d2"), []byte(input)); err != nil {
+		t.Fatalf("failed to publish message with error: %+v", err)
+	}
+	if len(r.msgs) != 1 {
+		t.Fatalf("expected a single message but got %v", r.msgs)
+	}
+	for _, s := range []string{
+		"7f0e2b4c", "c0a8b1d2", "4a9d8f2c", "9e1c", "192.0.2.1", "192.0.2.2", "198.51.100.", "203.0.113.", "AAD96MYzZAU=",
+	} {
+		if strings.Contains(r.msgs[0], s) {
+			t.Fatalf("anonymized message %s carries %s in clear", r.msgs[0], s)
+		}
+	}
+	if string(r.keys[0]) == "c0a8b1d2" {
+		t.Fatalf("key of anonymized message is not hashed")
+	}
+	e := &pub.Envelope{}
+	if err := json.Unmarshal([]byte(r.msgs[0]), e); err != nil {
+		t.Fatalf("failed to unmarshal envelope with error: %+v", err)
+	}
+	m := make(map[string]interface{})
+	if err := json.Unmarshal(e.Message, &m); err != nil {
+		t.Fatalf("failed to unmarshal message with error: %+v", err)
+	}
+	if m["hash"] != m["_key"] || m["router_hash"] != string(r.keys[0]) {
+		t.Fatalf("equal hashes of message %+v and key %s are anonymized to different values", m, string(r.keys[0]))
+	}
+	ba := m["base_attrs"].(map[string]interface{})
+	if ba["nexthop"] != m["nexthop"] {
+		t.Fatalf("equal next hops %v and %v are anonymized to different values", ba["nexthop"], m["nexthop"])
+	}
+	if l := strings.Split(ba["cluster_list"].(string), ", "); len(l) != 2 || net.ParseIP(l[0]) == nil || net.ParseIP(l[1]) == nil {
+		t.Fatalf("anonymized cluster list %v is not a list of 2 addresses", ba["cluster_list"])
+	}
+}
@@ -0,0 +1,34 @@
+package transform
+
+import (
+	"fmt"
+	"plugin"
+)
+
+// PluginSymbol is the name of the symbol Go plugins export, either a variable of Transformer type or
+// a value implementing Transformer interface.
+const PluginSymbol = "Transformer"
+
+// loadPlugin opens Go plugin built with "go build -buildmode=plugin", plugins are supported only by gobmp
+// built with cgo enabled and with the same version of Go and of gobmp packages as the plugin.
+func loadPlugin(path string) (Transformer, error) {
+	p, err := plugin.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open plugin %s with error: %+v", path, err)
+	}
+	s, err := p.Lookup(PluginSymbol)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find symbol %s of plugin %s with error: %+v", PluginSymbol, path, err)
+	}
+	switch t := s.(type) {
+	case *Transformer:
+		if *t == nil {
+			return nil, fmt.Errorf("symbol %s of plugin %s is nil", PluginSymbol, path)
+		}
+		return *t, nil
+	case Transformer:
+		return t, nil
+	}
+
+	return nil, fmt.Errorf("symbol %s of plugin %s of type %T does not implement Transformer", PluginSymbol, path, s)
+}
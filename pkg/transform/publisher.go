@@ -0,0 +1,89 @@
+package transform
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	"github.com/sbezverk/gobmp/pkg/pub"
+)
+
+type transforming struct {
+	publisher    pub.Publisher
+	transformers []Transformer
+}
+
+// transform returns the transformed message and its key, or nil when the message is dropped
+func (p *transforming) transform(msgType int, msgHash []byte, msg []byte) ([]byte, []byte, error) {
+	m := make(map[string]interface{})
+	d := json.NewDecoder(bytes.NewReader(msg))
+	// Keeping numbers as they are, 64 bits counters do not fit into float64
+	d.UseNumber()
+	if err := d.Decode(&m); err != nil {
+		return nil, nil, fmt.Errorf("failed to decode message of type %d to transform with error: %+v", msgType, err)
+	}
+	var err error
+	for _, t := range p.transformers {
+		if kt, ok := t.(keyTransformer); ok {
+			msgHash = kt.transformKey(msgType, m, msgHash)
+		}
+		if m, err = t.Transform(msgType, m); err != nil {
+			return nil, nil, fmt.Errorf("failed to transform message of type %d with error: %+v", msgType, err)
+		}
+		if m == nil {
+			// Message is dropped
+			return nil, nil, nil
+		}
+	}
+	b, err := json.Marshal(m)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to marshal transformed message of type %d with error: %+v", msgType, err)
+	}
+
+	return b, msgHash, nil
+}
+
+func (p *transforming) PublishMessage(msgType int, msgHash []byte, msg []byte) error {
+	b, key, err := p.transform(msgType, msgHash, msg)
+	if err != nil || b == nil {
+		return err
+	}
+
+	return p.publisher.PublishMessage(msgType, key, b)
+}
+
+// PublishUpdate transforms messages of a single BGP Update one by one, messages which are not dropped
+// are published together.
+func (p *transforming) PublishUpdate(msgType int, msgHash []byte, msgs [][]byte) error {
+	publish := make([][]byte, 0, len(msgs))
+	var key []byte
+	for _, msg := range msgs {
+		b, k, err := p.transform(msgType, msgHash, msg)
+		if err != nil {
+			return err
+		}
+		if b != nil {
+			publish = append(publish, b)
+			key = k
+		}
+	}
+	if len(publish) == 0 {
+		return nil
+	}
+
+	return pub.PublishUpdate(p.publisher, msgType, key, publish)
+}
+
+func (p *transforming) Stop() {
+	p.publisher.Stop()
+}
+
+// NewPublisher returns a Publisher passing each message through transformers before it is published to
+// publisher, a message dropped by a transformer is not published. Transformers receive messages as produced,
+// so the publisher must wrap publishers which combine messages or wrap them in the envelope.
+func NewPublisher(transformers []Transformer, publisher pub.Publisher) pub.Publisher {
+	return &transforming{
+		publisher:    publisher,
+		transformers: transformers,
+	}
+}
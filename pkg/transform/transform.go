@@ -0,0 +1,262 @@
+package transform
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net"
+
+	"github.com/sbezverk/gobmp/pkg/bmp"
+)
+
+// Transformer defines an interface of transform hook invoked before a message is published, msg is the
+// decoded JSON message, the returned message is published instead of it and nil drops the message.
+type Transformer interface {
+	Transform(msgType int, msg map[string]interface{}) (map[string]interface{}, error)
+}
+
+// keyTransformer is implemented by transformers replacing the key the message is published with, msg is
+// the message before it is transformed.
+type keyTransformer interface {
+	transformKey(msgType int, msg map[string]interface{}, key []byte) []byte
+}
+
+// Rule defines a transform of messages of listed types, or of all messages when no types are listed.
+// When Groups are listed, the rule applies only to messages of routers of the listed router groups.
+// A rule either loads the Go plugin from Plugin, or applies its operations in the order: DropPrefixes,
+// RedactPrefixes, Anonymize, Rename, Remove and Set.
+type Rule struct {
+	Types  []string `json:"types,omitempty"`
+	Groups []string `json:"groups,omitempty"`
+	// DropPrefixes drops messages with "prefix" field within any of the prefixes
+	DropPrefixes []string `json:"drop_prefixes,omitempty"`
+	// RedactPrefixes replaces "prefix" and "prefix_len" fields within any of the prefixes with the covering prefix
+	RedactPrefixes []string `json:"redact_prefixes,omitempty"`
+	// Anonymize replaces values of fields before they are published, keys are field names and values are
+	// methods "hash" or "truncate"
+	Anonymize map[string]string `json:"anonymize,omitempty"`
+	// AnonymizeKey is the key of "hash" method, when not set, a random key is used and hashes change on restart
+	AnonymizeKey string `json:"anonymize_key,omitempty"`
+	// TruncateIPv4 and TruncateIPv6 are the lengths "truncate" method keeps of addresses, 24 and 48 by default
+	TruncateIPv4 int `json:"truncate_ipv4,omitempty"`
+	TruncateIPv6 int `json:"truncate_ipv6,omitempty"`
+	// Rename renames fields, keys are current names and values are new names
+	Rename map[string]string `json:"rename,omitempty"`
+	// Remove lists fields removed from messages
+	Remove []string `json:"remove,omitempty"`
+	// Set adds fields to messages or replaces their values, for example tags of the collector's site
+	Set map[string]interface{} `json:"set,omitempty"`
+	// Plugin is the path of Go plugin exporting Transformer symbol implementing Transformer interface
+	Plugin string `json:"plugin,omitempty"`
+}
+
+// Config defines transform rules, rules are applied in the listed order
+type Config struct {
+	Rules []*Rule `json:"rules"`
+}
+
+// LoadConfig reads transform configuration from JSON file
+func LoadConfig(file string) (*Config, error) {
+	b, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	c := &Config{}
+	if err := json.Unmarshal(b, c); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal transform configuration %s with error: %+v", file, err)
+	}
+
+	return c, nil
+}
+
+// hook defines a transformer invoked for messages of types, for all messages when types is empty,
+// and of router groups, for messages of all routers when groups is empty
+type hook struct {
+	types       map[int]bool
+	groups      map[string]bool
+	transformer Transformer
+}
+
+// Transformers returns transformers of the configured rules, plugins are loaded by this call
+func (c *Config) Transformers() ([]Transformer, error) {
+	ts := make([]Transformer, 0, len(c.Rules))
+	for i, r := range c.Rules {
+		h := &hook{types: make(map[int]bool, len(r.Types)), groups: make(map[string]bool, len(r.Groups))}
+		for _, name := range r.Types {
+			t, ok := bmp.MsgTypeByName(name)
+			if !ok {
+				return nil, fmt.Errorf("rule %d has unknown message type %s", i, name)
+			}
+			h.types[t] = true
+		}
+		for _, g := range r.Groups {
+			h.groups[g] = true
+		}
+		var err error
+		if r.Plugin != "" {
+			h.transformer, err = loadPlugin(r.Plugin)
+		} else {
+			h.transformer, err = newRuleTransformer(r)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("rule %d is invalid with error: %+v", i, err)
+		}
+		ts = append(ts, h)
+	}
+
+	return ts, nil
+}
+
+func (h *hook) Transform(msgType int, msg map[string]interface{}) (map[string]interface{}, error) {
+	if len(h.types) != 0 && !h.types[msgType] {
+		return msg, nil
+	}
+	if len(h.groups) != 0 && !h.groups[group(msg)] {
+		return msg, nil
+	}
+
+	return h.transformer.Transform(msgType, msg)
+}
+
+func (h *hook) transformKey(msgType int, msg map[string]interface{}, key []byte) []byte {
+	if len(h.types) != 0 && !h.types[msgType] {
+		return key
+	}
+	if len(h.groups) != 0 && !h.groups[group(msg)] {
+		return key
+	}
+	if kt, ok := h.transformer.(keyTransformer); ok {
+		return kt.transformKey(msgType, msg, key)
+	}
+
+	return key
+}
+
+// group returns the router group of the message carried in "group" field of the enrichment
+func group(msg map[string]interface{}) string {
+	e, _ := msg["enrichment"].(map[string]interface{})
+	g, _ := e["group"].(string)
+
+	return g
+}
+
+// ruleTransformer applies operations of a rule
+type ruleTransformer struct {
+	rule       *Rule
+	drop       []*net.IPNet
+	redact     []*net.IPNet
+	anonymizer *anonymizer
+}
+
+func parsePrefixes(prefixes []string) ([]*net.IPNet, error) {
+	nets := make([]*net.IPNet, 0, len(prefixes))
+	for _, p := range prefixes {
+		_, n, err := net.ParseCIDR(p)
+		if err != nil {
+			return nil, err
+		}
+		nets = append(nets, n)
+	}
+
+	return nets, nil
+}
+
+func newRuleTransformer(r *Rule) (Transformer, error) {
+	t := &ruleTransformer{rule: r}
+	var err error
+	if t.drop, err = parsePrefixes(r.DropPrefixes); err != nil {
+		return nil, err
+	}
+	if t.redact, err = parsePrefixes(r.RedactPrefixes); err != nil {
+		return nil, err
+	}
+	if len(r.Anonymize) != 0 {
+		if t.anonymizer, err = newAnonymizer(r); err != nil {
+			return nil, err
+		}
+	}
+
+	return t, nil
+}
+
+// prefixLen returns the value of "prefix_len" field, as decoded or as set by a preceding transform
+func prefixLen(msg map[string]interface{}) (int, bool) {
+	switch v := msg["prefix_len"].(type) {
+	case json.Number:
+		l, err := v.Int64()
+		return int(l), err == nil
+	case int:
+		return v, true
+	case int64:
+		return int(v), true
+	case float64:
+		return int(v), true
+	}
+
+	return 0, false
+}
+
+// covering returns the prefix of nets covering the prefix of the message, or nil
+func covering(nets []*net.IPNet, msg map[string]interface{}) *net.IPNet {
+	if len(nets) == 0 {
+		return nil
+	}
+	s, ok := msg["prefix"].(string)
+	if !ok {
+		return nil
+	}
+	ip := net.ParseIP(s)
+	if ip == nil {
+		return nil
+	}
+	l, ok := prefixLen(msg)
+	if !ok {
+		l = -1
+	}
+	for _, n := range nets {
+		ones, _ := n.Mask.Size()
+		// A prefix shorter than the covering prefix is not within it
+		if n.Contains(ip) && l >= ones {
+			return n
+		}
+	}
+
+	return nil
+}
+
+// transformKey anonymizes the key of messages of rules anonymizing fields, the key is the hash of the router address
+func (t *ruleTransformer) transformKey(msgType int, msg map[string]interface{}, key []byte) []byte {
+	if t.anonymizer == nil {
+		return key
+	}
+
+	return t.anonymizer.hashKey(key)
+}
+
+func (t *ruleTransformer) Transform(msgType int, msg map[string]interface{}) (map[string]interface{}, error) {
+	if covering(t.drop, msg) != nil {
+		return nil, nil
+	}
+	if n := covering(t.redact, msg); n != nil {
+		ones, _ := n.Mask.Size()
+		msg["prefix"] = n.IP.String()
+		msg["prefix_len"] = ones
+	}
+	if t.anonymizer != nil {
+		t.anonymizer.anonymize(msg)
+	}
+	for from, to := range t.rule.Rename {
+		if v, ok := msg[from]; ok {
+			delete(msg, from)
+			msg[to] = v
+		}
+	}
+	for _, f := range t.rule.Remove {
+		delete(msg, f)
+	}
+	for f, v := range t.rule.Set {
+		msg[f] = v
+	}
+
+	return msg, nil
+}
@@ -0,0 +1,170 @@
+package transform
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/sbezverk/gobmp/pkg/bmp"
+	"github.com/sbezverk/gobmp/pkg/pub"
+)
+
+type recorder struct {
+	msgs []string
+	keys [][]byte
+}
+
+func (r *recorder) PublishMessage(msgType int, msgHash []byte, msg []byte) error {
+	r.msgs = append(r.msgs, string(msg))
+	r.keys = append(r.keys, msgHash)
+	return nil
+}
+
+func (r *recorder) Stop() {}
+
+func TestPublisher(t *testing.T) {
+	tests := []struct {
+		name    string
+		rules   []*Rule
+		msgType int
+		input   string
+		expect  []string
+		fail    bool
+	}{
+		{
+			name: "rename, remove and set",
+			rules: []*Rule{
+				{
+					Rename: map[string]string{"peer_ip": "neighbor_ip"},
+					Remove: []string{"base_attrs"},
+					Set:    map[string]interface{}{"site": "ams1"},
+				},
+			},
+			msgType: bmp.UnicastPrefixV4Msg,
+			input:   `{"peer_ip":"192.0.2.2","base_attrs":{"med":10},"prefix":"10.0.0.0","prefix_len":8,"sequence":18446744073709551615}`,
+			expect:  []string{`{"neighbor_ip":"192.0.2.2","prefix":"10.0.0.0","prefix_len":8,"sequence":18446744073709551615,"site":"ams1"}`},
+		},
+		{
+			name:    "rule of other type",
+			rules:   []*Rule{{Types: []string{"peer"}, Set: map[string]interface{}{"site": "ams1"}}},
+			msgType: bmp.UnicastPrefixV4Msg,
+			input:   `{"prefix":"10.0.0.0","prefix_len":8}`,
+			expect:  []string{`{"prefix":"10.0.0.0","prefix_len":8}`},
+		},
+		{
+			name:    "rule of router group",
+			rules:   []*Rule{{Groups: []string{"tenant-a"}, Set: map[string]interface{}{"site": "ams1"}}},
+			msgType: bmp.UnicastPrefixV4Msg,
+			input:   `{"prefix":"10.0.0.0","prefix_len":8,"enrichment":{"group":"tenant-a"}}`,
+			expect:  []string{`{"enrichment":{"group":"tenant-a"},"prefix":"10.0.0.0","prefix_len":8,"site":"ams1"}`},
+		},
+		{
+			name:    "rule of other router group",
+			rules:   []*Rule{{Groups: []string{"tenant-a"}, Set: map[string]interface{}{"site": "ams1"}}},
+			msgType: bmp.UnicastPrefixV4Msg,
+			input:   `{"prefix":"10.0.0.0","prefix_len":8,"enrichment":{"group":"tenant-b"}}`,
+			expect:  []string{`{"enrichment":{"group":"tenant-b"},"prefix":"10.0.0.0","prefix_len":8}`},
+		},
+		{
+			name:    "dropped prefix",
+			rules:   []*Rule{{DropPrefixes: []string{"10.0.0.0/8"}}},
+			msgType: bmp.UnicastPrefixV4Msg,
+			input:   `{"prefix":"10.1.0.0","prefix_len":16}`,
+			expect:  []string{},
+		},
+		{
+			name:    "shorter prefix is not dropped",
+			rules:   []*Rule{{DropPrefixes: []string{"10.0.0.0/8"}}},
+			msgType: bmp.UnicastPrefixV4Msg,
+			input:   `{"prefix":"10.0.0.0","prefix_len":7}`,
+			expect:  []string{`{"prefix":"10.0.0.0","prefix_len":7}`},
+		},
+		{
+			name:    "redacted prefix",
+			rules:   []*Rule{{RedactPrefixes: []string{"2001:db8::/32"}}},
+			msgType: bmp.UnicastPrefixV6Msg,
+			input:   `{"prefix":"2001:db8:1::","prefix_len":48}`,
+			expect:  []string{`{"prefix":"2001:db8::","prefix_len":32}`},
+		},
+		{
+			name:  "unknown message type",
+			rules: []*Rule{{Types: []string{"unknown"}}},
+			fail:  true,
+		},
+		{
+			name:  "invalid prefix",
+			rules: []*Rule{{DropPrefixes: []string{"10.0.0.0"}}},
+			fail:  true,
+		},
+		{
+			name:  "missing plugin",
+			rules: []*Rule{{Plugin: "/nonexistent/plugin.so"}},
+			fail:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ts, err := (&Config{Rules: tt.rules}).Transformers()
+			if err != nil && !tt.fail {
+				t.Fatalf("expected to succeed but failed with error: %+v", err)
+			}
+			if err == nil && tt.fail {
+				t.Fatalf("expected to fail but succeeded")
+			}
+			if err != nil {
+				return
+			}
+			r := &recorder{msgs: make([]string, 0)}
+			if err := NewPublisher(ts, r).PublishMessage(tt.msgType, nil, []byte(tt.input)); err != nil {
+				t.Fatalf("failed to publish message with error: %+v", err)
+			}
+			if len(r.msgs) != len(tt.expect) {
+				t.Fatalf("expected messages %v do not match actual messages %v", tt.expect, r.msgs)
+			}
+			for i := range r.msgs {
+				if r.msgs[i] != tt.expect[i] {
+					t.Fatalf("expected message %s does not match actual message %s", tt.expect[i], r.msgs[i])
+				}
+			}
+		})
+	}
+}
+
+func TestPublisherEnvelope(t *testing.T) {
+	ts, err := (&Config{Rules: []*Rule{
+		{DropPrefixes: []string{"10.0.0.0/8"}, RedactPrefixes: []string{"192.168.0.0/16"}, Remove: []string{"peer_ip"}},
+	}}).Transformers()
+	if err != nil {
+		t.Fatalf("failed to initialize transforms with error: %+v", err)
+	}
+	r := &recorder{msgs: make([]string, 0)}
+	// Publishers are wrapped as by gobmp with default flags, the envelope is enabled
+	p := NewPublisher(ts, pub.NewCombiner(map[int]bool{bmp.UnicastPrefixV6Msg: true}, pub.NewEnvelope("c1", r)))
+	if err := p.PublishMessage(bmp.UnicastPrefixV4Msg, nil, []byte(`{"prefix":"10.1.0.0","prefix_len":16}`)); err != nil {
+		t.Fatalf("failed to publish message with error: %+v", err)
+	}
+	if err := p.PublishMessage(bmp.UnicastPrefixV4Msg, nil, []byte(`{"peer_ip":"192.0.2.2","prefix":"192.168.1.0","prefix_len":24}`)); err != nil {
+		t.Fatalf("failed to publish message with error: %+v", err)
+	}
+	if err := pub.PublishUpdate(p, bmp.UnicastPrefixV6Msg, nil, [][]byte{
+		[]byte(`{"prefix":"2001:db8::","prefix_len":32}`),
+		[]byte(`{"prefix":"10.0.0.0","prefix_len":8}`),
+	}); err != nil {
+		t.Fatalf("failed to publish update with error: %+v", err)
+	}
+	expect := []string{
+		`{"prefix":"192.168.0.0","prefix_len":16}`,
+		`[{"prefix":"2001:db8::","prefix_len":32}]`,
+	}
+	if len(r.msgs) != len(expect) {
+		t.Fatalf("expected messages %v do not match actual messages %v", expect, r.msgs)
+	}
+	for i, m := range r.msgs {
+		e := &pub.Envelope{}
+		if err := json.Unmarshal([]byte(m), e); err != nil {
+			t.Fatalf("failed to unmarshal envelope with error: %+v", err)
+		}
+		if string(e.Message) != expect[i] {
+			t.Fatalf("expected message %s does not match actual message %s", expect[i], string(e.Message))
+		}
+	}
+}
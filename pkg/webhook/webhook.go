@@ -0,0 +1,308 @@
+package webhook
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/sbezverk/gobmp/pkg/bmp"
+	"github.com/sbezverk/gobmp/pkg/filer"
+	"github.com/sbezverk/gobmp/pkg/pub"
+)
+
+const (
+	// queueLength defines the number of messages buffered for posting, when the webhook does not keep up,
+	// messages are written to the dead letter file.
+	queueLength = 8192
+	// retryBackoff defines the wait before the first retry of a failed post, it doubles with every retry
+	retryBackoff = 500 * time.Millisecond
+	// maxBackoff defines the maximum wait between retries of a failed post
+	maxBackoff = 30 * time.Second
+	// SignatureHeader carries hex encoded HMAC-SHA256 of the timestamp, a dot and the body of the request
+	// prefixed with "sha256=", it is set when the secret is configured.
+	SignatureHeader = "X-Gobmp-Signature"
+	// TimestampHeader carries the time of the request in seconds since epoch
+	TimestampHeader = "X-Gobmp-Timestamp"
+)
+
+// Config defines the webhook publisher, messages are posted in batches of up to BatchSize messages,
+// a partial batch is posted after FlushInterval. A failed post is retried Retries times with exponential
+// backoff, messages of the batch which could not be posted are written to DeadLetterFile in the format
+// of the message file, when it is not set, they are dropped. Compression is filer.CompressionNone,
+// filer.CompressionGzip or filer.CompressionZstd compressing bodies of requests, empty does not compress them.
+type Config struct {
+	URL            string
+	Secret         string
+	BatchSize      int
+	FlushInterval  time.Duration
+	Retries        int
+	DeadLetterFile string
+	Compression    string
+}
+
+// Message defines a message in the body of the request
+type Message struct {
+	Type    string          `json:"type"`
+	Key     string          `json:"key,omitempty"`
+	Message json.RawMessage `json:"message"`
+}
+
+// Batch defines the body of the request
+type Batch struct {
+	Messages []*Message `json:"messages"`
+}
+
+type message struct {
+	msgType int
+	key     []byte
+	msg     []byte
+}
+
+type webhook struct {
+	url        string
+	secret     []byte
+	batchSize  int
+	interval   time.Duration
+	retries    int
+	backoff    time.Duration
+	encoding   string
+	client     *http.Client
+	deadLetter pub.Publisher
+	queue      chan *message
+	stopCh     chan struct{}
+	done       chan struct{}
+}
+
+func (w *webhook) PublishMessage(msgType int, msgHash []byte, msg []byte) error {
+	if !json.Valid(msg) {
+		return fmt.Errorf("webhook requires json messages, message of type %d is not json", msgType)
+	}
+	m := &message{
+		msgType: msgType,
+		key:     msgHash,
+		msg:     msg,
+	}
+	select {
+	case w.queue <- m:
+		return nil
+	default:
+	}
+	glog.V(5).Infof("webhook queue is full, message of type %d is not posted", msgType)
+
+	return w.reject([]*message{m})
+}
+
+func (w *webhook) Stop() {
+	close(w.stopCh)
+	<-w.done
+	if w.deadLetter != nil {
+		w.deadLetter.Stop()
+	}
+}
+
+func (w *webhook) worker() {
+	defer close(w.done)
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+	batch := make([]*message, 0, w.batchSize)
+	for {
+		select {
+		case m := <-w.queue:
+			if batch = append(batch, m); len(batch) == w.batchSize {
+				w.post(batch)
+				batch = batch[:0]
+			}
+		case <-ticker.C:
+			w.post(batch)
+			batch = batch[:0]
+		case <-w.stopCh:
+			// Posting messages published before the stop
+			for len(w.queue) != 0 {
+				if batch = append(batch, <-w.queue); len(batch) == w.batchSize {
+					w.post(batch)
+					batch = batch[:0]
+				}
+			}
+			w.post(batch)
+			return
+		}
+	}
+}
+
+// post posts the batch retrying failed posts, the batch is rejected when retries are exhausted,
+// the webhook rejects it or the publisher is stopped.
+func (w *webhook) post(batch []*message) {
+	if len(batch) == 0 {
+		return
+	}
+	b, err := json.Marshal(body(batch))
+	if err != nil {
+		glog.Errorf("failed to marshal %d messages with error: %+v", len(batch), err)
+		return
+	}
+	if w.encoding != "" {
+		if b, err = filer.Compress(w.encoding, b); err != nil {
+			glog.Errorf("failed to compress %d messages with error: %+v", len(batch), err)
+			w.reject(batch)
+			return
+		}
+	}
+	backoff := w.backoff
+	for attempt := 0; ; attempt++ {
+		retry, err := w.send(b)
+		if err == nil {
+			return
+		}
+		if !retry || attempt == w.retries {
+			glog.Errorf("failed to post %d messages to %s with error: %+v", len(batch), w.url, err)
+			break
+		}
+		glog.V(5).Infof("failed to post %d messages to %s with error: %+v, retrying in %s", len(batch), w.url, err, backoff)
+		select {
+		case <-time.After(backoff):
+		case <-w.stopCh:
+			glog.Errorf("gobmp is stopping, failed post of %d messages to %s is not retried", len(batch), w.url)
+			w.reject(batch)
+			return
+		}
+		if backoff *= 2; backoff > maxBackoff {
+			backoff = maxBackoff
+		}
+	}
+	w.reject(batch)
+}
+
+// send sends a single request, retry is true when the request failed and may succeed later
+func (w *webhook) send(b []byte) (bool, error) {
+	req, err := http.NewRequest(http.MethodPost, w.url, bytes.NewReader(b))
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if w.encoding != "" {
+		req.Header.Set("Content-Encoding", w.encoding)
+	}
+	if w.secret != nil {
+		ts := strconv.FormatInt(time.Now().Unix(), 10)
+		req.Header.Set(TimestampHeader, ts)
+		req.Header.Set(SignatureHeader, Sign(w.secret, ts, b))
+	}
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return true, err
+	}
+	io.Copy(ioutil.Discard, resp.Body)
+	resp.Body.Close()
+	if resp.StatusCode/100 == 2 {
+		return false, nil
+	}
+	err = fmt.Errorf("webhook returned status: %s", resp.Status)
+
+	return resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode/100 == 5, err
+}
+
+// reject writes messages which could not be posted to the dead letter file
+func (w *webhook) reject(batch []*message) error {
+	if w.deadLetter == nil {
+		return fmt.Errorf("%d messages are not posted to %s and dropped", len(batch), w.url)
+	}
+	var err error
+	for _, m := range batch {
+		if e := w.deadLetter.PublishMessage(m.msgType, m.key, m.msg); e != nil && err == nil {
+			err = e
+		}
+	}
+	if err != nil {
+		glog.Errorf("failed to write %d messages to the dead letter file with error: %+v", len(batch), err)
+	}
+
+	return err
+}
+
+func body(batch []*message) *Batch {
+	b := &Batch{
+		Messages: make([]*Message, 0, len(batch)),
+	}
+	for _, m := range batch {
+		b.Messages = append(b.Messages, &Message{
+			Type:    bmp.MsgTypeName(m.msgType),
+			Key:     string(m.key),
+			Message: m.msg,
+		})
+	}
+
+	return b
+}
+
+// Sign returns the signature of the request carried by SignatureHeader, receivers of requests compute it
+// from TimestampHeader and the body as received, compressed when the request carries Content-Encoding,
+// and compare it with hmac.Equal.
+func Sign(secret []byte, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte{'.'})
+	mac.Write(body)
+
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// NewPublisher returns a Publisher posting messages to the webhook
+func NewPublisher(config *Config) (pub.Publisher, error) {
+	u, err := url.Parse(config.URL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid webhook url %s with error: %+v", config.URL, err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, fmt.Errorf("invalid webhook url %s, only http and https urls are supported", config.URL)
+	}
+	if config.BatchSize <= 0 {
+		return nil, fmt.Errorf("invalid webhook batch size %d", config.BatchSize)
+	}
+	if config.FlushInterval <= 0 {
+		return nil, fmt.Errorf("invalid webhook flush interval %s", config.FlushInterval)
+	}
+	if config.Retries < 0 {
+		return nil, fmt.Errorf("invalid number of webhook retries %d", config.Retries)
+	}
+	encoding := ""
+	switch config.Compression {
+	case "", filer.CompressionNone:
+	case filer.CompressionGzip, filer.CompressionZstd:
+		encoding = config.Compression
+	default:
+		return nil, fmt.Errorf("unsupported webhook compression %s", config.Compression)
+	}
+	w := &webhook{
+		url:       u.String(),
+		batchSize: config.BatchSize,
+		interval:  config.FlushInterval,
+		retries:   config.Retries,
+		backoff:   retryBackoff,
+		encoding:  encoding,
+		client:    &http.Client{Timeout: 10 * time.Second},
+		queue:     make(chan *message, queueLength),
+		stopCh:    make(chan struct{}),
+		done:      make(chan struct{}),
+	}
+	if config.Secret != "" {
+		w.secret = []byte(config.Secret)
+	}
+	if config.DeadLetterFile != "" {
+		if w.deadLetter, err = filer.NewFiler(config.DeadLetterFile); err != nil {
+			return nil, fmt.Errorf("failed to create dead letter file with error: %+v", err)
+		}
+	}
+	go w.worker()
+
+	return w, nil
+}
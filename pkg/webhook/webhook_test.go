@@ -0,0 +1,212 @@
+package webhook
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"crypto/hmac"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/sbezverk/gobmp/pkg/bmp"
+	"github.com/sbezverk/gobmp/pkg/filer"
+)
+
+// receiver is a webhook returning statuses in order and storing received batches
+type receiver struct {
+	sync.Mutex
+	statuses []int
+	batches  []*Batch
+	requests int
+	signed   bool
+}
+
+func (r *receiver) received() int {
+	r.Lock()
+	defer r.Unlock()
+	return r.requests
+}
+
+func (r *receiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	r.Lock()
+	defer r.Unlock()
+	r.requests++
+	b, _ := ioutil.ReadAll(req.Body)
+	r.signed = hmac.Equal([]byte(req.Header.Get(SignatureHeader)), []byte(Sign([]byte("secret"), req.Header.Get(TimestampHeader), b)))
+	// The signature is computed from the body as received
+	switch req.Header.Get("Content-Encoding") {
+	case "gzip":
+		if zr, err := gzip.NewReader(bytes.NewReader(b)); err == nil {
+			b, _ = ioutil.ReadAll(zr)
+		}
+	case "zstd":
+		if zr, err := zstd.NewReader(nil); err == nil {
+			b, _ = zr.DecodeAll(b, nil)
+			zr.Close()
+		}
+	}
+	status := http.StatusOK
+	if len(r.statuses) != 0 {
+		status, r.statuses = r.statuses[0], r.statuses[1:]
+	}
+	if status == http.StatusOK {
+		batch := &Batch{}
+		json.Unmarshal(b, batch)
+		r.batches = append(r.batches, batch)
+	}
+	w.WriteHeader(status)
+}
+
+func TestWebhook(t *testing.T) {
+	dir, err := ioutil.TempDir("", "webhook")
+	if err != nil {
+		t.Fatalf("failed to create directory with error: %+v", err)
+	}
+	defer os.RemoveAll(dir)
+	tests := []struct {
+		name        string
+		compression string
+		statuses    []int
+		requests    int
+		posted      []int
+		deadLetter  int
+	}{
+		{
+			name:     "posted batches",
+			requests: 1,
+			posted:   []int{2, 1},
+		},
+		{
+			name:        "compressed batches",
+			compression: filer.CompressionGzip,
+			requests:    1,
+			posted:      []int{2, 1},
+		},
+		{
+			name:        "zstd compressed batches",
+			compression: filer.CompressionZstd,
+			requests:    1,
+			posted:      []int{2, 1},
+		},
+		{
+			name:     "retried batch",
+			statuses: []int{http.StatusServiceUnavailable, http.StatusTooManyRequests},
+			requests: 3,
+			posted:   []int{2, 1},
+		},
+		{
+			name:       "exhausted retries",
+			statuses:   []int{http.StatusInternalServerError, http.StatusInternalServerError, http.StatusInternalServerError},
+			requests:   3,
+			posted:     []int{1},
+			deadLetter: 2,
+		},
+		{
+			name:       "rejected batch",
+			statuses:   []int{http.StatusBadRequest},
+			requests:   1,
+			posted:     []int{1},
+			deadLetter: 2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &receiver{statuses: tt.statuses}
+			srv := httptest.NewServer(r)
+			defer srv.Close()
+			deadLetter := filepath.Join(dir, tt.name)
+			p, err := NewPublisher(&Config{
+				URL:            srv.URL,
+				Secret:         "secret",
+				BatchSize:      2,
+				FlushInterval:  time.Hour,
+				Retries:        2,
+				DeadLetterFile: deadLetter,
+				Compression:    tt.compression,
+			})
+			if err != nil {
+				t.Fatalf("failed to create webhook publisher with error: %+v", err)
+			}
+			p.(*webhook).backoff = time.Millisecond
+			for _, msg := range []string{`{"prefix":"10.0.0.0"}`, `{"prefix":"10.1.0.0"}`, `{"prefix":"10.2.0.0"}`} {
+				if err := p.PublishMessage(bmp.UnicastPrefixV4Msg, []byte("key"), []byte(msg)); err != nil {
+					t.Fatalf("failed to publish message with error: %+v", err)
+				}
+			}
+			// Waiting for posts of the full batch, retries are not waited for after the stop
+			for i := 0; i < 100 && r.received() < tt.requests; i++ {
+				time.Sleep(10 * time.Millisecond)
+			}
+			// Stop posts the partial batch
+			p.Stop()
+			var posted []int
+			for _, b := range r.batches {
+				posted = append(posted, len(b.Messages))
+				if b.Messages[0].Type != "unicast_prefix_v4" || b.Messages[0].Key != "key" {
+					t.Errorf("unexpected message %+v", b.Messages[0])
+				}
+			}
+			if !reflect.DeepEqual(posted, tt.posted) {
+				t.Errorf("expected posted batches of %v messages, got %v", tt.posted, posted)
+			}
+			if !r.signed {
+				t.Errorf("request signature does not match")
+			}
+			f, err := os.Open(deadLetter)
+			if err != nil {
+				t.Fatalf("failed to open dead letter file with error: %+v", err)
+			}
+			defer f.Close()
+			n := 0
+			for s := bufio.NewScanner(f); s.Scan(); n++ {
+				m := &filer.MsgOut{}
+				if err := json.Unmarshal(s.Bytes(), m); err != nil || m.Type != bmp.UnicastPrefixV4Msg {
+					t.Errorf("invalid dead letter message %s", s.Text())
+				}
+			}
+			if n != tt.deadLetter {
+				t.Errorf("expected %d messages in the dead letter file, got %d", tt.deadLetter, n)
+			}
+		})
+	}
+}
+
+func TestNewPublisher(t *testing.T) {
+	tests := []struct {
+		name   string
+		config *Config
+	}{
+		{
+			name:   "invalid url",
+			config: &Config{URL: "ftp://localhost", BatchSize: 1, FlushInterval: time.Second},
+		},
+		{
+			name:   "invalid batch size",
+			config: &Config{URL: "http://localhost", FlushInterval: time.Second},
+		},
+		{
+			name:   "invalid flush interval",
+			config: &Config{URL: "http://localhost", BatchSize: 1},
+		},
+		{
+			name:   "unsupported compression",
+			config: &Config{URL: "http://localhost", BatchSize: 1, FlushInterval: time.Second, Compression: "brotli"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewPublisher(tt.config); err == nil {
+				t.Errorf("supposed to fail but succeeded")
+			}
+		})
+	}
+}
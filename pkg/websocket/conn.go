@@ -0,0 +1,195 @@
+package websocket
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// websocketGUID is used to calculate Sec-WebSocket-Accept, RFC 6455 Section 1.3
+const websocketGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+
+// Frame opcodes, RFC 6455 Section 5.2
+const (
+	opText  = 0x1
+	opClose = 0x8
+	opPing  = 0x9
+	opPong  = 0xa
+)
+
+// maxControlPayload is the maximum payload length of control frames, RFC 6455 Section 5.5
+const maxControlPayload = 125
+
+// conn defines server side of websocket connection, the server only sends text frames
+// and reads client's frames to handle control frames.
+type conn struct {
+	sync.Mutex
+	c  net.Conn
+	rw *bufio.ReadWriter
+}
+
+func acceptKey(key string) string {
+	h := sha1.New()
+	h.Write([]byte(key + websocketGUID))
+
+	return base64.StdEncoding.EncodeToString(h.Sum(nil))
+}
+
+func headerContains(h http.Header, name, value string) bool {
+	for _, v := range h.Values(name) {
+		for _, t := range strings.Split(v, ",") {
+			if strings.EqualFold(strings.TrimSpace(t), value) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// upgrade validates websocket handshake request and switches the connection to websocket protocol
+func upgrade(w http.ResponseWriter, r *http.Request) (*conn, error) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return nil, fmt.Errorf("invalid method %s", r.Method)
+	}
+	key := r.Header.Get("Sec-WebSocket-Key")
+	if !headerContains(r.Header, "Connection", "upgrade") || !headerContains(r.Header, "Upgrade", "websocket") || key == "" {
+		http.Error(w, "websocket handshake is expected", http.StatusBadRequest)
+		return nil, fmt.Errorf("invalid websocket handshake")
+	}
+	if r.Header.Get("Sec-WebSocket-Version") != "13" {
+		w.Header().Set("Sec-WebSocket-Version", "13")
+		http.Error(w, "unsupported websocket version", http.StatusUpgradeRequired)
+		return nil, fmt.Errorf("unsupported websocket version %s", r.Header.Get("Sec-WebSocket-Version"))
+	}
+	hj, ok := w.(http.Hijacker)
+	if !ok {
+		http.Error(w, "websocket is not supported", http.StatusInternalServerError)
+		return nil, fmt.Errorf("connection cannot be hijacked")
+	}
+	c, rw, err := hj.Hijack()
+	if err != nil {
+		return nil, err
+	}
+	resp := "HTTP/1.1 101 Switching Protocols\r\n" +
+		"Upgrade: websocket\r\n" +
+		"Connection: Upgrade\r\n" +
+		"Sec-WebSocket-Accept: " + acceptKey(key) + "\r\n\r\n"
+	if _, err := rw.WriteString(resp); err != nil {
+		c.Close()
+		return nil, err
+	}
+	if err := rw.Flush(); err != nil {
+		c.Close()
+		return nil, err
+	}
+
+	return &conn{c: c, rw: rw}, nil
+}
+
+// writeFrame writes a single unmasked frame with FIN bit set
+func (c *conn) writeFrame(op byte, payload []byte) error {
+	c.Lock()
+	defer c.Unlock()
+	h := make([]byte, 2, 10)
+	h[0] = 0x80 | op
+	switch l := len(payload); {
+	case l <= 125:
+		h[1] = byte(l)
+	case l <= 0xffff:
+		h[1] = 126
+		h = h[:4]
+		binary.BigEndian.PutUint16(h[2:], uint16(l))
+	default:
+		h[1] = 127
+		h = h[:10]
+		binary.BigEndian.PutUint64(h[2:], uint64(l))
+	}
+	if _, err := c.rw.Write(h); err != nil {
+		return err
+	}
+	if _, err := c.rw.Write(payload); err != nil {
+		return err
+	}
+
+	return c.rw.Flush()
+}
+
+// readFrame reads a single client frame, client frames must be masked, RFC 6455 Section 5.1
+func (c *conn) readFrame() (byte, []byte, error) {
+	h := make([]byte, 2)
+	if _, err := io.ReadFull(c.rw, h); err != nil {
+		return 0, nil, err
+	}
+	op := h[0] & 0x0f
+	if h[1]&0x80 == 0 {
+		return 0, nil, fmt.Errorf("client frame is not masked")
+	}
+	l := uint64(h[1] & 0x7f)
+	switch l {
+	case 126:
+		b := make([]byte, 2)
+		if _, err := io.ReadFull(c.rw, b); err != nil {
+			return 0, nil, err
+		}
+		l = uint64(binary.BigEndian.Uint16(b))
+	case 127:
+		b := make([]byte, 8)
+		if _, err := io.ReadFull(c.rw, b); err != nil {
+			return 0, nil, err
+		}
+		l = binary.BigEndian.Uint64(b)
+	}
+	if op >= opClose && l > maxControlPayload {
+		return 0, nil, fmt.Errorf("invalid control frame length %d", l)
+	}
+	mask := make([]byte, 4)
+	if _, err := io.ReadFull(c.rw, mask); err != nil {
+		return 0, nil, err
+	}
+	// Payload of data frames is not used by the server and is discarded
+	if op < opClose {
+		_, err := io.CopyN(io.Discard, c.rw, int64(l))
+		return op, nil, err
+	}
+	payload := make([]byte, l)
+	if _, err := io.ReadFull(c.rw, payload); err != nil {
+		return 0, nil, err
+	}
+	for i := range payload {
+		payload[i] ^= mask[i%4]
+	}
+
+	return op, payload, nil
+}
+
+// readLoop processes client's frames until the connection is closed, it answers ping and close frames
+func (c *conn) readLoop() {
+	for {
+		op, payload, err := c.readFrame()
+		if err != nil {
+			return
+		}
+		switch op {
+		case opPing:
+			if err := c.writeFrame(opPong, payload); err != nil {
+				return
+			}
+		case opClose:
+			c.writeFrame(opClose, payload)
+			return
+		}
+	}
+}
+
+func (c *conn) close() error {
+	return c.c.Close()
+}
@@ -0,0 +1,202 @@
+package websocket
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/golang/glog"
+	"github.com/sbezverk/gobmp/pkg/bmp"
+	"github.com/sbezverk/gobmp/pkg/pub"
+)
+
+const (
+	// clientQueueLength defines the number of messages buffered per client, when client
+	// does not keep up, messages are dropped.
+	clientQueueLength = 1024
+)
+
+// Streamer defines a Publisher streaming published messages to websocket clients,
+// clients connect to Streamer's http handler.
+type Streamer interface {
+	pub.Publisher
+	http.Handler
+}
+
+// filter defines which messages are streamed to a client, empty filter matches all messages
+type filter struct {
+	types   map[int]bool
+	routers map[string]bool
+	peers   map[string]bool
+}
+
+type client struct {
+	filter *filter
+	queue  chan []byte
+}
+
+type streamer struct {
+	sync.RWMutex
+	clients map[*client]struct{}
+}
+
+var _ Streamer = &streamer{}
+
+// key defines fields of published messages used by filters
+type key struct {
+	RouterIP string `json:"router_ip"`
+	PeerIP   string `json:"peer_ip"`
+	RemoteIP string `json:"remote_ip"`
+}
+
+func (f *filter) match(msgType int, k *key) bool {
+	if len(f.types) != 0 && !f.types[msgType] {
+		return false
+	}
+	if len(f.routers) != 0 && !f.routers[k.RouterIP] {
+		return false
+	}
+	if len(f.peers) != 0 && !f.peers[k.PeerIP] && !f.peers[k.RemoteIP] {
+		return false
+	}
+
+	return true
+}
+
+func (s *streamer) PublishMessage(msgType int, msgHash []byte, msg []byte) error {
+	s.RLock()
+	defer s.RUnlock()
+	if len(s.clients) == 0 {
+		return nil
+	}
+	k := &key{}
+	if err := json.Unmarshal(msg, k); err != nil {
+		return err
+	}
+	for c := range s.clients {
+		if !c.filter.match(msgType, k) {
+			continue
+		}
+		select {
+		case c.queue <- msg:
+		default:
+			glog.Warningf("websocket client is not keeping up, dropping message of type %d", msgType)
+		}
+	}
+
+	return nil
+}
+
+func (s *streamer) Stop() {
+	s.Lock()
+	defer s.Unlock()
+	for c := range s.clients {
+		delete(s.clients, c)
+		close(c.queue)
+	}
+}
+
+func splitParam(v string) []string {
+	items := make([]string, 0)
+	for _, i := range strings.Split(v, ",") {
+		if i = strings.TrimSpace(i); i != "" {
+			items = append(items, i)
+		}
+	}
+
+	return items
+}
+
+func newFilter(r *http.Request) (*filter, error) {
+	q := r.URL.Query()
+	f := &filter{
+		types:   make(map[int]bool),
+		routers: make(map[string]bool),
+		peers:   make(map[string]bool),
+	}
+	for _, v := range q["type"] {
+		for _, n := range splitParam(v) {
+			t, ok := bmp.MsgTypeByName(n)
+			if !ok {
+				return nil, fmt.Errorf("invalid message type %s", n)
+			}
+			f.types[t] = true
+		}
+	}
+	for _, v := range q["router"] {
+		for _, r := range splitParam(v) {
+			f.routers[r] = true
+		}
+	}
+	for _, v := range q["peer"] {
+		for _, p := range splitParam(v) {
+			f.peers[p] = true
+		}
+	}
+
+	return f, nil
+}
+
+// ServeHTTP upgrades the connection to websocket and streams published messages matching client's filter,
+// the filter is specified by comma separated values of query parameters, for example:
+//
+//	ws://gobmp:8080/stream?type=unicast_prefix_v4,peer&router=192.0.2.1&peer=192.0.2.2
+func (s *streamer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f, err := newFilter(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	conn, err := upgrade(w, r)
+	if err != nil {
+		glog.Errorf("failed to upgrade connection from %s to websocket with error: %+v", r.RemoteAddr, err)
+		return
+	}
+	defer conn.close()
+	c := &client{
+		filter: f,
+		queue:  make(chan []byte, clientQueueLength),
+	}
+	s.Lock()
+	s.clients[c] = struct{}{}
+	s.Unlock()
+	defer func() {
+		s.Lock()
+		if _, ok := s.clients[c]; ok {
+			delete(s.clients, c)
+			close(c.queue)
+		}
+		s.Unlock()
+	}()
+	glog.V(5).Infof("websocket client %s connected", r.RemoteAddr)
+	done := make(chan struct{})
+	go func() {
+		conn.readLoop()
+		close(done)
+	}()
+	for {
+		select {
+		case msg, ok := <-c.queue:
+			if !ok {
+				conn.writeFrame(opClose, nil)
+				return
+			}
+			if err := conn.writeFrame(opText, msg); err != nil {
+				glog.V(5).Infof("websocket client %s is gone with error: %+v", r.RemoteAddr, err)
+				return
+			}
+		case <-done:
+			glog.V(5).Infof("websocket client %s disconnected", r.RemoteAddr)
+			return
+		}
+	}
+}
+
+// NewStreamer instantiates a new instance of websocket Streamer
+func NewStreamer() Streamer {
+	return &streamer{
+		clients: make(map[*client]struct{}),
+	}
+}
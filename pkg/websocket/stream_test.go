@@ -0,0 +1,116 @@
+package websocket
+
+import (
+	"bufio"
+	"encoding/binary"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sbezverk/gobmp/pkg/bmp"
+)
+
+func TestAcceptKey(t *testing.T) {
+	// Example from RFC 6455 Section 1.3
+	if got := acceptKey("dGhlIHNhbXBsZSBub25jZQ=="); got != "s3pPLMBiTxaQ9kYGzzhZRbK+xOo=" {
+		t.Fatalf("expected accept key s3pPLMBiTxaQ9kYGzzhZRbK+xOo=, got %s", got)
+	}
+}
+
+func dial(t *testing.T, srv *httptest.Server, query string) (net.Conn, *bufio.Reader) {
+	t.Helper()
+	c, err := net.Dial("tcp", strings.TrimPrefix(srv.URL, "http://"))
+	if err != nil {
+		t.Fatalf("failed to connect with error: %+v", err)
+	}
+	req := "GET /stream?" + query + " HTTP/1.1\r\nHost: test\r\nUpgrade: websocket\r\nConnection: Upgrade\r\n" +
+		"Sec-WebSocket-Key: dGhlIHNhbXBsZSBub25jZQ==\r\nSec-WebSocket-Version: 13\r\n\r\n"
+	if _, err := c.Write([]byte(req)); err != nil {
+		t.Fatalf("failed to send handshake with error: %+v", err)
+	}
+	r := bufio.NewReader(c)
+	resp, err := http.ReadResponse(r, nil)
+	if err != nil {
+		t.Fatalf("failed to read handshake response with error: %+v", err)
+	}
+	if resp.StatusCode != http.StatusSwitchingProtocols || resp.Header.Get("Sec-WebSocket-Accept") != "s3pPLMBiTxaQ9kYGzzhZRbK+xOo=" {
+		t.Fatalf("unexpected handshake response %+v", resp)
+	}
+
+	return c, r
+}
+
+func readTextFrame(t *testing.T, c net.Conn, r *bufio.Reader) string {
+	t.Helper()
+	c.SetReadDeadline(time.Now().Add(time.Second))
+	h := make([]byte, 2)
+	if _, err := io.ReadFull(r, h); err != nil {
+		t.Fatalf("failed to read frame with error: %+v", err)
+	}
+	if h[0] != 0x81 {
+		t.Fatalf("expected text frame, got %x", h[0])
+	}
+	l := int(h[1])
+	if l == 126 {
+		b := make([]byte, 2)
+		io.ReadFull(r, b)
+		l = int(binary.BigEndian.Uint16(b))
+	}
+	p := make([]byte, l)
+	if _, err := io.ReadFull(r, p); err != nil {
+		t.Fatalf("failed to read frame payload with error: %+v", err)
+	}
+
+	return string(p)
+}
+
+func TestStreamer(t *testing.T) {
+	s := NewStreamer()
+	srv := httptest.NewServer(s)
+	defer srv.Close()
+	defer s.Stop()
+	c, r := dial(t, srv, "type=unicast_prefix_v4&router=192.0.2.1")
+	defer c.Close()
+	// Waiting for the client to be registered
+	for i := 0; i < 100; i++ {
+		s.(*streamer).RLock()
+		n := len(s.(*streamer).clients)
+		s.(*streamer).RUnlock()
+		if n == 1 {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	msgs := []struct {
+		t   int
+		msg string
+	}{
+		{t: bmp.PeerStateChangeMsg, msg: `{"router_ip":"192.0.2.1","remote_ip":"192.0.2.2"}`},
+		{t: bmp.UnicastPrefixV4Msg, msg: `{"router_ip":"192.0.2.2","peer_ip":"192.0.2.2","prefix":"10.0.0.0"}`},
+		{t: bmp.UnicastPrefixV4Msg, msg: `{"router_ip":"192.0.2.1","peer_ip":"192.0.2.2","prefix":"10.0.1.0"}`},
+	}
+	for _, m := range msgs {
+		if err := s.PublishMessage(m.t, nil, []byte(m.msg)); err != nil {
+			t.Fatalf("failed to publish message with error: %+v", err)
+		}
+	}
+	if got := readTextFrame(t, c, r); got != msgs[2].msg {
+		t.Fatalf("expected message %s, got %s", msgs[2].msg, got)
+	}
+}
+
+func TestStreamerInvalidFilter(t *testing.T) {
+	srv := httptest.NewServer(NewStreamer())
+	defer srv.Close()
+	resp, err := http.Get(srv.URL + "/stream?type=unknown")
+	if err != nil {
+		t.Fatalf("failed to send request with error: %+v", err)
+	}
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Fatalf("expected status %d, got %d", http.StatusBadRequest, resp.StatusCode)
+	}
+}
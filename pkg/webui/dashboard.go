@@ -0,0 +1,252 @@
+package webui
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/sbezverk/gobmp/pkg/bmp"
+	"github.com/sbezverk/gobmp/pkg/pub"
+	"github.com/sbezverk/gobmp/pkg/telemetry"
+)
+
+const (
+	// rateInterval defines how often message rates are recalculated
+	rateInterval = 5 * time.Second
+	// maxSearchResults defines the maximum number of objects returned by search
+	maxSearchResults = 100
+)
+
+// Dashboard defines a Publisher collecting routers, peers and message rates which are presented
+// by the built-in web UI.
+type Dashboard interface {
+	pub.Publisher
+	http.Handler
+}
+
+// Peer defines the state of a peer presented by the web UI
+type Peer struct {
+	PeerIP     string `json:"peer_ip"`
+	PeerASN    uint32 `json:"peer_asn,omitempty"`
+	State      string `json:"state"`
+	LastChange string `json:"last_change,omitempty"`
+	Messages   uint64 `json:"messages"`
+}
+
+// Router defines the state of a monitored router presented by the web UI
+type Router struct {
+	RouterIP string  `json:"router_ip"`
+	Messages uint64  `json:"messages"`
+	Peers    []*Peer `json:"peers"`
+}
+
+// Summary defines the content of the web UI summary
+type Summary struct {
+	Routers []*Router          `json:"routers"`
+	Totals  map[string]uint64  `json:"totals"`
+	Rates   map[string]float64 `json:"rates"`
+	Search  bool               `json:"search"`
+}
+
+type router struct {
+	messages uint64
+	peers    map[string]*Peer
+}
+
+type dashboard struct {
+	sync.RWMutex
+	mux     *http.ServeMux
+	cache   telemetry.Server
+	routers map[string]*router
+	totals  map[int]uint64
+	last    map[int]uint64
+	rates   map[int]float64
+	stop    chan struct{}
+}
+
+var _ Dashboard = &dashboard{}
+
+// key defines fields of published messages used by the dashboard
+type key struct {
+	Action    string `json:"action"`
+	RouterIP  string `json:"router_ip"`
+	PeerIP    string `json:"peer_ip"`
+	PeerASN   uint32 `json:"peer_asn"`
+	RemoteIP  string `json:"remote_ip"`
+	RemoteASN uint32 `json:"remote_asn"`
+	Timestamp string `json:"timestamp"`
+}
+
+func (d *dashboard) PublishMessage(msgType int, msgHash []byte, msg []byte) error {
+	k := &key{}
+	if err := json.Unmarshal(msg, k); err != nil {
+		return err
+	}
+	peerIP, peerASN := k.PeerIP, k.PeerASN
+	if msgType == bmp.PeerStateChangeMsg || msgType == bmp.StatsReportMsg {
+		peerIP, peerASN = k.RemoteIP, k.RemoteASN
+	}
+	d.Lock()
+	defer d.Unlock()
+	d.totals[msgType]++
+	if k.RouterIP == "" {
+		return nil
+	}
+	r, ok := d.routers[k.RouterIP]
+	if !ok {
+		r = &router{peers: make(map[string]*Peer)}
+		d.routers[k.RouterIP] = r
+	}
+	r.messages++
+	if peerIP == "" {
+		return nil
+	}
+	p, ok := r.peers[peerIP]
+	if !ok {
+		p = &Peer{PeerIP: peerIP, State: "unknown"}
+		r.peers[peerIP] = p
+	}
+	p.Messages++
+	if peerASN != 0 {
+		p.PeerASN = peerASN
+	}
+	if msgType == bmp.PeerStateChangeMsg {
+		p.State = k.Action
+		p.LastChange = k.Timestamp
+	}
+
+	return nil
+}
+
+func (d *dashboard) Stop() {
+	close(d.stop)
+}
+
+// calculateRates recalculates messages per second rates of all message types every rateInterval
+func (d *dashboard) calculateRates() {
+	ticker := time.NewTicker(rateInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			d.Lock()
+			for t, n := range d.totals {
+				d.rates[t] = float64(n-d.last[t]) / rateInterval.Seconds()
+				d.last[t] = n
+			}
+			d.Unlock()
+		case <-d.stop:
+			return
+		}
+	}
+}
+
+func msgTypeName(t int) string {
+	if n := bmp.MsgTypeName(t); n != "" {
+		return n
+	}
+
+	return strconv.Itoa(t)
+}
+
+func (d *dashboard) summary() *Summary {
+	d.RLock()
+	defer d.RUnlock()
+	s := &Summary{
+		Routers: make([]*Router, 0, len(d.routers)),
+		Totals:  make(map[string]uint64, len(d.totals)),
+		Rates:   make(map[string]float64, len(d.rates)),
+		Search:  d.cache != nil,
+	}
+	for ip, r := range d.routers {
+		rt := &Router{RouterIP: ip, Messages: r.messages, Peers: make([]*Peer, 0, len(r.peers))}
+		for _, p := range r.peers {
+			cp := *p
+			rt.Peers = append(rt.Peers, &cp)
+		}
+		sort.Slice(rt.Peers, func(i, j int) bool { return rt.Peers[i].PeerIP < rt.Peers[j].PeerIP })
+		s.Routers = append(s.Routers, rt)
+	}
+	sort.Slice(s.Routers, func(i, j int) bool { return s.Routers[i].RouterIP < s.Routers[j].RouterIP })
+	for t, n := range d.totals {
+		s.Totals[msgTypeName(t)] = n
+	}
+	for t, r := range d.rates {
+		s.Rates[msgTypeName(t)] = r
+	}
+
+	return s
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		glog.Errorf("failed to send web ui response with error: %+v", err)
+	}
+}
+
+func (d *dashboard) handleSummary(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, d.summary())
+}
+
+// handleSearch returns cached objects which path or content contains the query string
+func (d *dashboard) handleSearch(w http.ResponseWriter, r *http.Request) {
+	if d.cache == nil {
+		http.Error(w, "search requires telemetry cache, enable it with --state-stream-port", http.StatusNotFound)
+		return
+	}
+	q := r.URL.Query().Get("q")
+	if q == "" {
+		http.Error(w, "query is missing", http.StatusBadRequest)
+		return
+	}
+	found := make([]*telemetry.Notification, 0)
+	for _, n := range d.cache.Get("/bmp") {
+		if strings.Contains(n.Path, q) || strings.Contains(string(n.Update), q) {
+			found = append(found, n)
+			if len(found) == maxSearchResults {
+				break
+			}
+		}
+	}
+	sort.Slice(found, func(i, j int) bool { return found[i].Path < found[j].Path })
+	writeJSON(w, found)
+}
+
+func (d *dashboard) handleIndex(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/ui/" {
+		http.NotFound(w, r)
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Write([]byte(indexHTML))
+}
+
+func (d *dashboard) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	d.mux.ServeHTTP(w, r)
+}
+
+// NewDashboard instantiates a new instance of web UI Dashboard, cache is optional, when provided,
+// the web UI allows to search cached prefixes and topology objects.
+func NewDashboard(cache telemetry.Server) Dashboard {
+	d := &dashboard{
+		mux:     http.NewServeMux(),
+		cache:   cache,
+		routers: make(map[string]*router),
+		totals:  make(map[int]uint64),
+		last:    make(map[int]uint64),
+		rates:   make(map[int]float64),
+		stop:    make(chan struct{}),
+	}
+	d.mux.HandleFunc("/ui/", d.handleIndex)
+	d.mux.HandleFunc("/ui/api/summary", d.handleSummary)
+	d.mux.HandleFunc("/ui/api/search", d.handleSearch)
+	go d.calculateRates()
+
+	return d
+}
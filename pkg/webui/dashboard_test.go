@@ -0,0 +1,110 @@
+package webui
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/go-test/deep"
+	"github.com/sbezverk/gobmp/pkg/bmp"
+	"github.com/sbezverk/gobmp/pkg/telemetry"
+)
+
+func TestDashboardSummary(t *testing.T) {
+	tests := []struct {
+		name   string
+		msgs   []map[string]interface{}
+		types  []int
+		expect []*Router
+		totals map[string]uint64
+	}{
+		{
+			name: "peer up and prefixes",
+			msgs: []map[string]interface{}{
+				{"action": "up", "router_ip": "192.0.2.1", "remote_ip": "192.0.2.2", "remote_asn": 65001, "timestamp": "t1"},
+				{"action": "add", "router_ip": "192.0.2.1", "peer_ip": "192.0.2.2", "peer_asn": 65001, "prefix": "10.0.0.0"},
+				{"action": "add", "router_ip": "192.0.2.1", "peer_ip": "192.0.2.3", "peer_asn": 65002, "prefix": "10.0.1.0"},
+			},
+			types: []int{bmp.PeerStateChangeMsg, bmp.UnicastPrefixV4Msg, bmp.UnicastPrefixV4Msg},
+			expect: []*Router{
+				{
+					RouterIP: "192.0.2.1",
+					Messages: 3,
+					Peers: []*Peer{
+						{PeerIP: "192.0.2.2", PeerASN: 65001, State: "up", LastChange: "t1", Messages: 2},
+						{PeerIP: "192.0.2.3", PeerASN: 65002, State: "unknown", Messages: 1},
+					},
+				},
+			},
+			totals: map[string]uint64{"peer": 1, "unicast_prefix_v4": 2},
+		},
+		{
+			name: "peer down",
+			msgs: []map[string]interface{}{
+				{"action": "up", "router_ip": "192.0.2.1", "remote_ip": "192.0.2.2", "remote_asn": 65001, "timestamp": "t1"},
+				{"action": "down", "router_ip": "192.0.2.1", "remote_ip": "192.0.2.2", "remote_asn": 65001, "timestamp": "t2"},
+			},
+			types: []int{bmp.PeerStateChangeMsg, bmp.PeerStateChangeMsg},
+			expect: []*Router{
+				{
+					RouterIP: "192.0.2.1",
+					Messages: 2,
+					Peers: []*Peer{
+						{PeerIP: "192.0.2.2", PeerASN: 65001, State: "down", LastChange: "t2", Messages: 2},
+					},
+				},
+			},
+			totals: map[string]uint64{"peer": 2},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := NewDashboard(nil).(*dashboard)
+			defer d.Stop()
+			for i, m := range tt.msgs {
+				b, _ := json.Marshal(m)
+				if err := d.PublishMessage(tt.types[i], nil, b); err != nil {
+					t.Fatalf("failed to publish message with error: %+v", err)
+				}
+			}
+			s := d.summary()
+			if !reflect.DeepEqual(s.Routers, tt.expect) {
+				t.Errorf("routers do not match expected")
+				t.Logf("Differences: %+v", deep.Equal(s.Routers, tt.expect))
+			}
+			if !reflect.DeepEqual(s.Totals, tt.totals) {
+				t.Errorf("totals do not match expected")
+				t.Logf("Differences: %+v", deep.Equal(s.Totals, tt.totals))
+			}
+		})
+	}
+}
+
+func TestDashboardSearch(t *testing.T) {
+	cache := telemetry.NewServer()
+	defer cache.Stop()
+	d := NewDashboard(cache)
+	defer d.Stop()
+	for _, p := range []string{"10.0.0.0", "10.0.1.0"} {
+		b, _ := json.Marshal(map[string]interface{}{"action": "add", "router_ip": "192.0.2.1", "peer_ip": "192.0.2.2", "prefix": p, "hash": p})
+		if err := cache.PublishMessage(bmp.UnicastPrefixV4Msg, nil, b); err != nil {
+			t.Fatalf("failed to publish message with error: %+v", err)
+		}
+	}
+	w := httptest.NewRecorder()
+	d.ServeHTTP(w, httptest.NewRequest("GET", "/ui/api/search?q=10.0.1.0", nil))
+	found := make([]*telemetry.Notification, 0)
+	if err := json.Unmarshal(w.Body.Bytes(), &found); err != nil {
+		t.Fatalf("failed to unmarshal search result with error: %+v", err)
+	}
+	if len(found) != 1 {
+		t.Fatalf("expected 1 object found but got %d", len(found))
+	}
+
+	w = httptest.NewRecorder()
+	NewDashboard(nil).ServeHTTP(w, httptest.NewRequest("GET", "/ui/api/search?q=10.0.1.0", nil))
+	if w.Code != 404 {
+		t.Errorf("expected search to fail without cache, got code %d", w.Code)
+	}
+}
@@ -0,0 +1,72 @@
+package webui
+
+// indexHTML is the single page of the web UI, it polls summary api and queries search api
+const indexHTML = `<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>goBMP</title>
+<style>
+body { font-family: sans-serif; margin: 1em 2em; }
+table { border-collapse: collapse; margin-bottom: 1em; }
+th, td { border: 1px solid #ccc; padding: 2px 8px; text-align: left; }
+th { background: #eee; }
+.up { color: green; } .down { color: red; }
+pre { background: #f6f6f6; padding: 4px; white-space: pre-wrap; }
+</style>
+</head>
+<body>
+<h1>goBMP</h1>
+<h2>Message rates</h2>
+<table id="rates"></table>
+<h2>Routers</h2>
+<div id="routers"></div>
+<div id="search" style="display:none">
+<h2>Search</h2>
+<input id="q" size="60" placeholder="prefix, router, peer or any value">
+<button onclick="search()">Search</button>
+<div id="results"></div>
+</div>
+<script>
+function esc(s) {
+  return String(s).replace(/[&<>"]/g, function(c) {
+    return {"&": "&amp;", "<": "&lt;", ">": "&gt;", "\"": "&quot;"}[c];
+  });
+}
+function refresh() {
+  fetch("api/summary").then(function(r) { return r.json(); }).then(function(s) {
+    var rows = "<tr><th>type</th><th>total</th><th>msg/s</th></tr>";
+    Object.keys(s.totals).sort().forEach(function(t) {
+      rows += "<tr><td>" + esc(t) + "</td><td>" + s.totals[t] + "</td><td>" + (s.rates[t] || 0).toFixed(1) + "</td></tr>";
+    });
+    document.getElementById("rates").innerHTML = rows;
+    var html = "";
+    s.routers.forEach(function(r) {
+      html += "<h3>" + esc(r.router_ip) + " (" + r.messages + " messages)</h3>";
+      html += "<table><tr><th>peer</th><th>asn</th><th>state</th><th>last change</th><th>messages</th></tr>";
+      r.peers.forEach(function(p) {
+        html += "<tr><td>" + esc(p.peer_ip) + "</td><td>" + (p.peer_asn || "") + "</td><td class=\"" + esc(p.state) + "\">" +
+          esc(p.state) + "</td><td>" + esc(p.last_change || "") + "</td><td>" + p.messages + "</td></tr>";
+      });
+      html += "</table>";
+    });
+    document.getElementById("routers").innerHTML = html;
+    document.getElementById("search").style.display = s.search ? "block" : "none";
+  });
+}
+function search() {
+  var q = document.getElementById("q").value;
+  fetch("api/search?q=" + encodeURIComponent(q)).then(function(r) { return r.json(); }).then(function(found) {
+    var html = "<p>" + found.length + " objects found</p>";
+    found.forEach(function(n) {
+      html += "<h4>" + esc(n.path) + "</h4><pre>" + esc(JSON.stringify(n.update, null, 2)) + "</pre>";
+    });
+    document.getElementById("results").innerHTML = html;
+  });
+}
+refresh();
+setInterval(refresh, 5000);
+</script>
+</body>
+</html>
+`